@@ -30,6 +30,7 @@ typedef struct {
   time_t time;
   bool is_timer;
   char *name;
+  VibePatternSetting vibe_pattern;
   TextLayer *title_layer;
   TextLayer *time_layer;
   StatusBarLayer *status_bar;
@@ -56,13 +57,14 @@ static void prv_click_config_provider(void *context);
 static void prv_handle_snooze(ClickRecognizerRef recognizer, void *context);
 static void prv_handle_dismiss(ClickRecognizerRef recognizer, void *context);
 static uint32_t prv_resource_id_for_setting(VibePatternSetting setting);
-static SadVibeScore *prv_load_vibe_score(bool is_timer);
+static SadVibeScore *prv_load_vibe_score(bool is_timer, VibePatternSetting vibe_pattern);
 
-void alarm_window_push(time_t alarm_time, bool is_timer, char *name) {
+void alarm_window_push(time_t alarm_time, bool is_timer, char *name, VibePatternSetting vibe_pattern) {
   Window* window = window_create();
   AlarmWindowData *data = malloc(sizeof(AlarmWindowData));
   data->time = alarm_time;
   data->is_timer = is_timer;
+  data->vibe_pattern = vibe_pattern;
   data->timer = NULL;
   data->name = NULL;
   if (name) {
@@ -123,7 +125,7 @@ static void prv_window_load(Window *window) {
   action_bar_layer_add_to_window(data->action_bar, window);
   data->animation_layer = vector_sequence_layer_create(GRect((rect.size.w - ACTION_BAR_WIDTH) / 2 - 25, rect.size.h - 55, 50, 50));
   data->draw_commands = gdraw_command_sequence_create_with_resource(RESOURCE_ID_TIRED_PONY);
-  data->vibes = prv_load_vibe_score(data->is_timer);
+  data->vibes = prv_load_vibe_score(data->is_timer, data->vibe_pattern);
   vector_sequence_layer_set_sequence(data->animation_layer, data->draw_commands);
   layer_add_child(root_layer, (Layer *)data->animation_layer);
 }
@@ -218,9 +220,9 @@ static void prv_handle_snooze(ClickRecognizerRef recognizer, void *context) {
   AlarmWindowData* data = window_get_user_data(window);
   int result;
   if (data->is_timer) {
-    result = alarm_manager_add_alarm(time(NULL) + 60, true, data->name, false);
+    result = alarm_manager_add_alarm(time(NULL) + 60, true, data->name, data->vibe_pattern, false);
   } else {
-    result = alarm_manager_add_alarm(time(NULL) + 600, false, data->name, false);
+    result = alarm_manager_add_alarm(time(NULL) + 600, false, data->name, data->vibe_pattern, false);
   }
   if (result == S_SUCCESS) {
     const char* text = data->is_timer ? "Snoozed for 1 minute" : "Snoozed for 10 minutes";
@@ -252,9 +254,11 @@ static uint32_t prv_resource_id_for_setting(VibePatternSetting setting) {
   return RESOURCE_ID_VIBE_STANDARD;
 }
 
-static SadVibeScore *prv_load_vibe_score(bool is_timer) {
+static SadVibeScore *prv_load_vibe_score(bool is_timer, VibePatternSetting vibe_pattern) {
   VibePatternSetting vibe_setting;
-  if (is_timer) {
+  if (vibe_pattern != VibePatternSettingDefault) {
+    vibe_setting = vibe_pattern;
+  } else if (is_timer) {
     vibe_setting = settings_get_timer_vibe_pattern();
   } else {
     vibe_setting = settings_get_alarm_vibe_pattern();
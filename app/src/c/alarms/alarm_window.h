@@ -18,7 +18,8 @@
 #define ALARMS_ALARM_WINDOW_H
 
 #include <pebble.h>
+#include "../settings/settings.h"
 
-void alarm_window_push(time_t alarm_time, bool is_timer, char *name);
+void alarm_window_push(time_t alarm_time, bool is_timer, char *name, VibePatternSetting vibe_pattern);
 
 #endif
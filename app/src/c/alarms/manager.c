@@ -28,6 +28,7 @@ struct Alarm {
   WakeupId wakeup_id;
   char *name;
   bool is_timer; // what's the difference between an alarm and a timer? the user's intention.
+  VibePatternSetting vibe_pattern; // VibePatternSettingDefault to use whatever is in the settings.
 };
 
 struct AlarmManager {
@@ -56,7 +57,7 @@ void alarm_manager_init() {
   prv_load_alarms();
 }
 
-int alarm_manager_add_alarm(time_t when, bool is_timer, char* name, bool conversational) {
+int alarm_manager_add_alarm(time_t when, bool is_timer, char* name, VibePatternSetting vibe_pattern, bool conversational) {
   if (s_manager.pending_alarm_count >= MAX_ALARMS) {
     APP_LOG(APP_LOG_LEVEL_WARNING, "Not scheduling alarm because MAX_ALARMS (%d) was already reached.", MAX_ALARMS);
     return E_OUT_OF_RESOURCES;
@@ -83,6 +84,7 @@ int alarm_manager_add_alarm(time_t when, bool is_timer, char* name, bool convers
   alarm->scheduled_time = when;
   alarm->is_timer = is_timer;
   alarm->wakeup_id = id;
+  alarm->vibe_pattern = vibe_pattern;
   alarm->name = NULL;
   size_t name_len = 0;
   if (name) {
@@ -195,11 +197,15 @@ static void prv_load_alarms() {
   WakeupId wakeup_ids[MAX_ALARMS];
   bool is_timers[MAX_ALARMS];
   char names[MAX_ALARMS][ALARM_NAME_SIZE];
+  // Alarms saved before we had per-alarm vibe patterns won't have any, so they get the default.
+  uint8_t vibe_patterns[MAX_ALARMS];
+  memset(vibe_patterns, VibePatternSettingDefault, sizeof(vibe_patterns));
   
   persist_read_data(PERSIST_KEY_ALARM_TIMES, &times, sizeof(times));
   persist_read_data(PERSIST_KEY_ALARM_WAKEUP_IDS, &wakeup_ids, sizeof(wakeup_ids));
   persist_read_data(PERSIST_KEY_ALARM_IS_TIMERS, &is_timers, sizeof(is_timers));
   persist_read_data(PERSIST_KEY_ALARM_NAMES, &names, sizeof(names));
+  persist_read_data(PERSIST_KEY_ALARM_VIBE_PATTERNS, &vibe_patterns, sizeof(vibe_patterns));
   
   s_manager.pending_alarms = malloc(sizeof(Alarm) * alarm_count);
   s_manager.pending_alarm_count = alarm_count;
@@ -221,6 +227,7 @@ static void prv_load_alarms() {
     alarm->scheduled_time = times[i];
     alarm->wakeup_id = wakeup_ids[i];
     alarm->is_timer = is_timers[i];
+    alarm->vibe_pattern = vibe_patterns[i];
     size_t name_len = strlen(names[i]);
     if (name_len >= ALARM_NAME_SIZE) {
       name_len = ALARM_NAME_SIZE - 1;
@@ -248,6 +255,7 @@ static void prv_save_alarms() {
     persist_delete(PERSIST_KEY_ALARM_WAKEUP_IDS);
     persist_delete(PERSIST_KEY_ALARM_IS_TIMERS);
     persist_delete(PERSIST_KEY_ALARM_NAMES);
+    persist_delete(PERSIST_KEY_ALARM_VIBE_PATTERNS);
     persist_delete(PERSIST_KEY_ALARM_COUNT_TWO);
     wakeup_cancel_all();
   }
@@ -255,12 +263,15 @@ static void prv_save_alarms() {
   WakeupId wakeup_ids[MAX_ALARMS];
   bool is_timers[MAX_ALARMS];
   char names[MAX_ALARMS][ALARM_NAME_SIZE];
+  uint8_t vibe_patterns[MAX_ALARMS];
   memset(names, 0, sizeof(names));
+  memset(vibe_patterns, VibePatternSettingDefault, sizeof(vibe_patterns));
   for (int i = 0; i < s_manager.pending_alarm_count; ++i) {
     Alarm* alarm = &s_manager.pending_alarms[i];
     times[i] = alarm->scheduled_time;
     wakeup_ids[i] = alarm->wakeup_id;
     is_timers[i] = alarm->is_timer;
+    vibe_patterns[i] = alarm->vibe_pattern;
     if (alarm->name) {
       strncpy(names[i], alarm->name, ALARM_NAME_SIZE);
       names[i][ALARM_NAME_SIZE - 1] = '\0';
@@ -274,6 +285,7 @@ static void prv_save_alarms() {
   persist_write_data(PERSIST_KEY_ALARM_WAKEUP_IDS, &wakeup_ids, sizeof(wakeup_ids));
   persist_write_data(PERSIST_KEY_ALARM_IS_TIMERS, &is_timers, sizeof(is_timers));
   persist_write_data(PERSIST_KEY_ALARM_NAMES, &names, sizeof(names));
+  persist_write_data(PERSIST_KEY_ALARM_VIBE_PATTERNS, &vibe_patterns, sizeof(vibe_patterns));
   persist_write_int(PERSIST_KEY_ALARM_COUNT_TWO, s_manager.pending_alarm_count);
   APP_LOG(APP_LOG_LEVEL_INFO, "Wrote %d alarms.", s_manager.pending_alarm_count);
 }
@@ -345,7 +357,7 @@ bool alarm_manager_maybe_alarm() {
     APP_LOG(APP_LOG_LEVEL_INFO, "comparing %d == %d", alarm->wakeup_id, id);
     if (alarm->wakeup_id == id) {
       APP_LOG(APP_LOG_LEVEL_INFO, "alarm found! alarming...");
-      alarm_window_push(alarm->scheduled_time, alarm->is_timer, alarm->name);
+      alarm_window_push(alarm->scheduled_time, alarm->is_timer, alarm->name, alarm->vibe_pattern);
       prv_remove_alarm(i);
       return true;
     }
@@ -365,6 +377,10 @@ char* alarm_get_name(Alarm* alarm) {
   return alarm->name;
 }
 
+VibePatternSetting alarm_get_vibe_pattern(Alarm* alarm) {
+  return alarm->vibe_pattern;
+}
+
 static void prv_handle_set_alarm_request(DictionaryIterator *iterator, void *context) {
   Tuple* tuple = dict_find(iterator, MESSAGE_KEY_SET_ALARM_TIME);
   if (tuple == NULL) {
@@ -385,7 +401,12 @@ static void prv_handle_set_alarm_request(DictionaryIterator *iterator, void *con
   if (tuple != NULL && strlen(tuple->value->cstring) > 0) {
     name = tuple->value->cstring;
   }
-  StatusCode result = alarm_manager_add_alarm(alarm_time, is_timer, name, true);
+  tuple = dict_find(iterator, MESSAGE_KEY_SET_ALARM_VIBE_PATTERN);
+  VibePatternSetting vibe_pattern = VibePatternSettingDefault;
+  if (tuple != NULL && tuple->value->int32 >= VibePatternSettingReveille && tuple->value->int32 <= VibePatternSettingStandard) {
+    vibe_pattern = tuple->value->int32;
+  }
+  StatusCode result = alarm_manager_add_alarm(alarm_time, is_timer, name, vibe_pattern, true);
   prv_send_alarm_response(result);
   if (result == S_SUCCESS) {
     APP_LOG(APP_LOG_LEVEL_INFO, "Set alarm for %d (is timer: %d)", alarm_time, is_timer);
@@ -487,7 +508,7 @@ static void prv_wakeup_handler(WakeupId wakeup_id, int32_t cookie) {
     APP_LOG(APP_LOG_LEVEL_INFO, "comparing %d == %d", alarm->wakeup_id, wakeup_id);
     if (alarm->wakeup_id == wakeup_id) {
       APP_LOG(APP_LOG_LEVEL_INFO, "alarm found! alarming...");
-      alarm_window_push(alarm->scheduled_time, alarm->is_timer, alarm->name);
+      alarm_window_push(alarm->scheduled_time, alarm->is_timer, alarm->name, alarm->vibe_pattern);
       prv_remove_alarm(i);
       break;
     }
@@ -18,12 +18,13 @@
 #define ALARMS_MANAGER_H
 
 #include <pebble.h>
+#include "../settings/settings.h"
 
 typedef struct AlarmManager AlarmManager;
 typedef struct Alarm Alarm;
 
 void alarm_manager_init();
-int alarm_manager_add_alarm(time_t when, bool is_timer, char* name, bool conversational);
+int alarm_manager_add_alarm(time_t when, bool is_timer, char* name, VibePatternSetting vibe_pattern, bool conversational);
 int alarm_manager_cancel_alarm(time_t when, bool is_timer);
 int alarm_manager_get_alarm_count();
 Alarm* alarm_manager_get_alarm(int index);
@@ -32,5 +33,6 @@ bool alarm_manager_maybe_alarm();
 time_t alarm_get_time(Alarm* alarm);
 bool alarm_is_timer(Alarm* alarm);
 char* alarm_get_name(Alarm* alarm);
+VibePatternSetting alarm_get_vibe_pattern(Alarm* alarm);
 
 #endif
@@ -19,6 +19,8 @@
 #include "converse/session_window.h"
 #include "converse/conversation_manager.h"
 #include "alarms/manager.h"
+#include "intervals/manager.h"
+#include "stopwatch/stopwatch.h"
 #include "version/version.h"
 #include "settings/settings.h"
 
@@ -36,6 +38,8 @@ static void prv_init(void) {
   conversation_manager_init();
   events_app_message_open();
   alarm_manager_init();
+  interval_manager_init();
+  stopwatch_init();
 }
 
 static void prv_deinit(void) {
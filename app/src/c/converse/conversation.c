@@ -34,6 +34,10 @@ struct Conversation {
   int entry_count;
   int entry_allocated;
   char thread_id[37];
+  char* suggestions[CONVERSATION_MAX_SUGGESTIONS];
+  int suggestion_count;
+  // The suggestions are only for the answer they came after, so they go stale once anything else is added.
+  int suggestions_entry_count;
 };
 
 static ConversationEntry* prv_create_entry(Conversation* conversation);
@@ -44,9 +48,18 @@ Conversation* conversation_create() {
   conversation->entry_allocated = 3;
   conversation->entries = malloc(sizeof(ConversationEntry) * conversation->entry_allocated);
   conversation->thread_id[0] = 0;
+  conversation->suggestion_count = 0;
+  conversation->suggestions_entry_count = 0;
   return conversation;
 }
 
+static void prv_free_suggestions(Conversation* conversation) {
+  for (int i = 0; i < conversation->suggestion_count; ++i) {
+    free(conversation->suggestions[i]);
+  }
+  conversation->suggestion_count = 0;
+}
+
 void conversation_destroy(Conversation* conversation) {
   for (int i = 0; i < conversation->entry_count; ++i) {
     ConversationEntry* entry = &conversation->entries[i];
@@ -114,11 +127,39 @@ void conversation_destroy(Conversation* conversation) {
               free(entry->content.widget->widget.number.unit);
             }
             break;
+          case ConversationWidgetTypeSports:
+            free(entry->content.widget->widget.sports.league);
+            free(entry->content.widget->widget.sports.home_team);
+            free(entry->content.widget->widget.sports.away_team);
+            if (entry->content.widget->widget.sports.progress) {
+              free(entry->content.widget->widget.sports.progress);
+            }
+            if (entry->content.widget->widget.sports.refresh_token) {
+              free(entry->content.widget->widget.sports.refresh_token);
+            }
+            break;
+          case ConversationWidgetTypePronunciation:
+            free(entry->content.widget->widget.pronunciation.word);
+            free(entry->content.widget->widget.pronunciation.respelling);
+            break;
+          case ConversationWidgetTypePage:
+            free(entry->content.widget->widget.page.document);
+            free(entry->content.widget->widget.page.title);
+            free(entry->content.widget->widget.page.page_title);
+            free(entry->content.widget->widget.page.text);
+            break;
+          case ConversationWidgetTypeIntervalTimer:
+            if (entry->content.widget->widget.interval_timer.name) {
+              free(entry->content.widget->widget.interval_timer.name);
+            }
+            free(entry->content.widget->widget.interval_timer.phases);
+            break;
         }
         free(entry->content.widget);
         break;
     }
   }
+  prv_free_suggestions(conversation);
   free(conversation->entries);
   free(conversation);
 }
@@ -354,6 +395,42 @@ const char* conversation_get_thread_id(Conversation* conversation) {
   return conversation->thread_id;
 }
 
+void conversation_set_suggestions(Conversation* conversation, const char* suggestions) {
+  prv_free_suggestions(conversation);
+  // The suggestions come one to a line.
+  const char* start = suggestions;
+  while (*start != 0 && conversation->suggestion_count < CONVERSATION_MAX_SUGGESTIONS) {
+    const char* end = strchr(start, '\n');
+    size_t len = end ? (size_t)(end - start) : strlen(start);
+    if (len > 0) {
+      char* suggestion = malloc(len + 1);
+      memcpy(suggestion, start, len);
+      suggestion[len] = 0;
+      conversation->suggestions[conversation->suggestion_count++] = suggestion;
+    }
+    if (!end) {
+      break;
+    }
+    start = end + 1;
+  }
+  conversation->suggestions_entry_count = conversation->entry_count;
+  APP_LOG(APP_LOG_LEVEL_INFO, "Received %d suggestions.", conversation->suggestion_count);
+}
+
+int conversation_get_suggestion_count(Conversation* conversation) {
+  if (conversation->suggestions_entry_count != conversation->entry_count) {
+    return 0;
+  }
+  return conversation->suggestion_count;
+}
+
+const char* conversation_get_suggestion(Conversation* conversation, int index) {
+  if (index < 0 || index >= conversation_get_suggestion_count(conversation)) {
+    return NULL;
+  }
+  return conversation->suggestions[index];
+}
+
 bool conversation_is_idle(Conversation* conversation) {
   ConversationEntry* entry = conversation_peek(conversation);
   if (entry == NULL) {
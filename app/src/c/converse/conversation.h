@@ -18,6 +18,7 @@
 #define CONVERSATION_H
 
 #include <pebble.h>
+#include "../intervals/manager.h"
 
 typedef struct Conversation Conversation;
 typedef struct ConversationEntry ConversationEntry;
@@ -37,6 +38,10 @@ typedef enum {
   ConversationWidgetTypeWeatherMultiDay,
   ConversationWidgetTypeTimer,
   ConversationWidgetTypeNumber,
+  ConversationWidgetTypeSports,
+  ConversationWidgetTypePronunciation,
+  ConversationWidgetTypePage,
+  ConversationWidgetTypeIntervalTimer,
 } ConversationWidgetType;
 
 typedef struct {
@@ -92,6 +97,8 @@ typedef struct {
 typedef struct {
   int high;
   int low;
+  // The number of decimal places high and low have been scaled up by.
+  int temp_decimals;
   int condition;
   char *location;
   char *summary;
@@ -102,8 +109,14 @@ typedef struct {
 typedef struct {
   int temperature;
   int feels_like;
+  // The number of decimal places temperature and feels_like have been scaled up by.
+  int temp_decimals;
   int condition;
   int wind_speed;
+  // Optional details, each -1 if it wasn't sent.
+  int humidity;
+  int precip_chance;
+  int uv_index;
   char *location;
   char *summary;
   char *wind_speed_unit;
@@ -126,11 +139,59 @@ typedef struct {
   char *name;
 } ConversationWidgetTimer;
 
+typedef struct {
+  time_t start_time;
+  char *name;
+  IntervalPhase *phases;
+  int phase_count;
+} ConversationWidgetIntervalTimer;
+
 typedef struct {
   char *number;
   char *unit;
 } ConversationWidgetNumber;
 
+typedef enum {
+  ConversationSportsStateScheduled,
+  ConversationSportsStateLive,
+  ConversationSportsStateFinished,
+  ConversationSportsStatePostponed,
+} ConversationSportsState;
+
+typedef struct {
+  char *league;
+  char *home_team;
+  char *away_team;
+  // Each -1 until the match starts.
+  int home_score;
+  int away_score;
+  ConversationSportsState state;
+  // How far into a live match it is, e.g. "67'", or NULL.
+  char *progress;
+  time_t start_time;
+  // The token to ask the phone for the latest score with, and until when; NULL if the score won't change.
+  char *refresh_token;
+  time_t refresh_until;
+} ConversationWidgetSports;
+
+typedef struct {
+  char *word;
+  // Syllables separated by hyphens, with the stressed one in capitals, e.g. "pruh-nuhn-see-AY-shuhn".
+  char *respelling;
+} ConversationWidgetPronunciation;
+
+typedef struct {
+  // The server's ID for the document, used to ask for its other pages.
+  char *document;
+  char *title;
+  // May be empty.
+  char *page_title;
+  char *text;
+  // Numbered from 1.
+  int page;
+  int pages;
+} ConversationWidgetPage;
+
 typedef struct {
   ConversationWidgetType type;
   bool locally_created;
@@ -140,6 +201,10 @@ typedef struct {
     ConversationWidgetWeatherMultiDay weather_multi_day;
     ConversationWidgetTimer timer;
     ConversationWidgetNumber number;
+    ConversationWidgetSports sports;
+    ConversationWidgetPronunciation pronunciation;
+    ConversationWidgetPage page;
+    ConversationWidgetIntervalTimer interval_timer;
   } widget;
 } ConversationWidget;
 
@@ -152,6 +217,9 @@ typedef enum {
   EntryTypeError,
 } EntryType;
 
+// The most follow-ups the server suggests after an answer.
+#define CONVERSATION_MAX_SUGGESTIONS 3
+
 Conversation* conversation_create();
 void conversation_destroy(Conversation* conversation);
 void conversation_add_prompt(Conversation* conversation, const char* prompt);
@@ -165,6 +233,9 @@ void conversation_add_widget(Conversation* conversation, ConversationWidget* wid
 void conversation_add_error(Conversation* conversation, const char* error_text);
 void conversation_set_thread_id(Conversation* conversation, const char* thread_id);
 const char* conversation_get_thread_id(Conversation* conversation);
+void conversation_set_suggestions(Conversation* conversation, const char* suggestions);
+int conversation_get_suggestion_count(Conversation* conversation);
+const char* conversation_get_suggestion(Conversation* conversation, int index);
 int conversation_length(Conversation* conversation);
 bool conversation_is_idle(Conversation* conversation);
 bool conversation_assistant_just_started(Conversation* conversation);
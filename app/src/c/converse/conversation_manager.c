@@ -32,10 +32,44 @@ static void prv_conversation_updated(ConversationManager* manager, bool new_entr
 static void prv_handle_app_message_outbox_sent(DictionaryIterator *iterator, void *context);
 static void prv_handle_app_message_outbox_failed(DictionaryIterator *iterator, AppMessageResult reason, void *context);
 static void prv_handle_app_message_inbox_received(DictionaryIterator *iterator, void *context);
+static void prv_process_page_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager) {
+  if (widget_type != 1) {
+    return;
+  }
+  Tuple *page = dict_find(iter, MESSAGE_KEY_PAGE_WIDGET_PAGE);
+  Tuple *pages = dict_find(iter, MESSAGE_KEY_PAGE_WIDGET_PAGES);
+  ConversationWidget widget = {
+    .type = ConversationWidgetTypePage,
+    .widget = {
+      .page = {
+        .document = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_DOCUMENT),
+        .title = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_TITLE),
+        .page_title = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_PAGE_TITLE),
+        .text = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_TEXT),
+        .page = page ? page->value->int32 : 1,
+        .pages = pages ? pages->value->int32 : 1,
+      }
+    }
+  };
+  if (!widget.widget.page.document || !widget.widget.page.title || !widget.widget.page.text) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Page widget is missing its document, title or text.");
+    free(widget.widget.page.document);
+    free(widget.widget.page.title);
+    free(widget.widget.page.page_title);
+    free(widget.widget.page.text);
+    return;
+  }
+  conversation_add_widget(manager->conversation, &widget);
+  prv_conversation_updated(manager, true);
+}
+
 static void prv_handle_app_message_inbox_dropped(AppMessageResult result, void *context);
 static void prv_process_weather_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager);
 static void prv_process_timer_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager);
 static void prv_process_highlight_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager);
+static void prv_process_sports_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager);
+static void prv_process_pronunciation_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager);
+static void prv_process_page_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager);
 
 static ConversationManager* s_conversation_manager;
 
@@ -71,6 +105,46 @@ ConversationManager* conversation_manager_get_current() {
   return s_conversation_manager;
 }
 
+// Returns the page widget at the end of the conversation, if it has more pages after it.
+static ConversationWidgetPage* prv_get_paged_widget(ConversationManager* manager) {
+  ConversationEntry *entry = conversation_peek(manager->conversation);
+  if (!entry || conversation_entry_get_type(entry) != EntryTypeWidget) {
+    return NULL;
+  }
+  ConversationWidget *widget = conversation_entry_get_widget(entry);
+  if (widget->type != ConversationWidgetTypePage || widget->widget.page.page >= widget->widget.page.pages) {
+    return NULL;
+  }
+  return &widget->widget.page;
+}
+
+bool conversation_manager_has_next_page(ConversationManager* manager) {
+  return prv_get_paged_widget(manager) != NULL;
+}
+
+void conversation_manager_request_next_page(ConversationManager* manager) {
+  ConversationWidgetPage *page = prv_get_paged_widget(manager);
+  if (!page) {
+    return;
+  }
+  DictionaryIterator *iter;
+  AppMessageResult result = app_message_outbox_begin(&iter);
+  if (result != APP_MSG_OK) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Preparing outbox failed: %d.", result);
+    conversation_add_error(manager->conversation, "Sending to service failed.");
+    prv_conversation_updated(manager, true);
+    return;
+  }
+  dict_write_cstring(iter, MESSAGE_KEY_PAGE_REQUEST, page->document);
+  dict_write_int32(iter, MESSAGE_KEY_PAGE_REQUEST_NUMBER, page->page + 1);
+  result = app_message_outbox_send();
+  if (result != APP_MSG_OK) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Sending message failed: %d.", result);
+    conversation_add_error(manager->conversation, "Sending to service failed.");
+    prv_conversation_updated(manager, true);
+  }
+}
+
 Conversation* conversation_manager_get_conversation(ConversationManager* manager) {
   return manager->conversation;
 }
@@ -146,6 +220,8 @@ static void prv_handle_app_message_inbox_received(DictionaryIterator *iter, void
       prv_conversation_updated(manager, false);
     } else if (tuple->key == MESSAGE_KEY_THREAD_ID) {
       conversation_set_thread_id(manager->conversation, tuple->value->cstring);
+    } else if (tuple->key == MESSAGE_KEY_SUGGESTIONS) {
+      conversation_set_suggestions(manager->conversation, tuple->value->cstring);
     } else if (tuple->key == MESSAGE_KEY_CLOSE_WAS_CLEAN) {
       if (!tuple->value->int16) {
         conversation_complete_response(manager->conversation);
@@ -199,15 +275,38 @@ static void prv_handle_app_message_inbox_received(DictionaryIterator *iter, void
       conversation_complete_response(manager->conversation);
       prv_conversation_updated(manager, false);
       prv_process_highlight_widget(tuple->value->int32, iter, manager);
+    } else if (tuple->key == MESSAGE_KEY_SPORTS_WIDGET) {
+      conversation_complete_response(manager->conversation);
+      prv_conversation_updated(manager, false);
+      prv_process_sports_widget(tuple->value->int32, iter, manager);
+    } else if (tuple->key == MESSAGE_KEY_PRONUNCIATION_WIDGET) {
+      conversation_complete_response(manager->conversation);
+      prv_conversation_updated(manager, false);
+      prv_process_pronunciation_widget(tuple->value->int32, iter, manager);
+    } else if (tuple->key == MESSAGE_KEY_PAGE_WIDGET) {
+      conversation_complete_response(manager->conversation);
+      prv_conversation_updated(manager, false);
+      prv_process_page_widget(tuple->value->int32, iter, manager);
     }
   }
 }
 
+static int prv_get_temp_decimals(DictionaryIterator *iter) {
+  Tuple *tuple = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_TEMP_DECIMALS);
+  return tuple ? tuple->value->int32 : 0;
+}
+
+static int prv_get_optional_int(DictionaryIterator *iter, uint32_t key) {
+  Tuple *tuple = dict_find(iter, key);
+  return tuple ? tuple->value->int32 : -1;
+}
+
 static void prv_process_weather_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager) {
   switch (widget_type) {
     case 1: {
       int high = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_DAY_HIGH)->value->int32;
       int low = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_DAY_LOW)->value->int32;
+      int temp_decimals = prv_get_temp_decimals(iter);
       int icon = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_DAY_ICON)->value->int32;
       const char* summary = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_DAY_SUMMARY)->value->cstring;
       const char* location = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_LOCATION)->value->cstring;
@@ -227,6 +326,7 @@ static void prv_process_weather_widget(int widget_type, DictionaryIterator *iter
           .weather_single_day = {
             .high = high,
             .low = low,
+            .temp_decimals = temp_decimals,
             .condition = icon,
             .location = location_stored,
             .summary = summary_stored,
@@ -242,8 +342,12 @@ static void prv_process_weather_widget(int widget_type, DictionaryIterator *iter
     case 2: {
       int temp = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_CURRENT_TEMP)->value->int32;
       int feels_like = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_FEELS_LIKE)->value->int32;
+      int temp_decimals = prv_get_temp_decimals(iter);
       int icon = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_DAY_ICON)->value->int32;
       int wind_speed = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_WIND_SPEED)->value->int32;
+      int humidity = prv_get_optional_int(iter, MESSAGE_KEY_WEATHER_WIDGET_HUMIDITY);
+      int precip_chance = prv_get_optional_int(iter, MESSAGE_KEY_WEATHER_WIDGET_PRECIP_CHANCE);
+      int uv_index = prv_get_optional_int(iter, MESSAGE_KEY_WEATHER_WIDGET_UV_INDEX);
       const char* location = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_LOCATION)->value->cstring;
       const char* summary = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_DAY_SUMMARY)->value->cstring;
       const char* wind_speed_unit = dict_find(iter, MESSAGE_KEY_WEATHER_WIDGET_WIND_SPEED_UNIT)->value->cstring;
@@ -259,8 +363,12 @@ static void prv_process_weather_widget(int widget_type, DictionaryIterator *iter
           .weather_current = {
             .temperature = temp,
             .feels_like = feels_like,
+            .temp_decimals = temp_decimals,
             .condition = icon,
             .wind_speed = wind_speed,
+            .humidity = humidity,
+            .precip_chance = precip_chance,
+            .uv_index = uv_index,
             .location = location_stored,
             .summary = summary_stored,
             .wind_speed_unit = wind_speed_unit_stored,
@@ -348,6 +456,105 @@ static void prv_process_highlight_widget(int widget_type, DictionaryIterator *it
   prv_conversation_updated(manager, true);
 }
 
+static char* prv_copy_optional_string(DictionaryIterator *iter, uint32_t key) {
+  Tuple *tuple = dict_find(iter, key);
+  if (!tuple) {
+    return NULL;
+  }
+  char *stored = malloc(strlen(tuple->value->cstring) + 1);
+  strcpy(stored, tuple->value->cstring);
+  return stored;
+}
+
+static void prv_process_sports_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager) {
+  if (widget_type != 1) {
+    return;
+  }
+  Tuple *refresh_until = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_REFRESH_UNTIL);
+  ConversationWidget widget = {
+    .type = ConversationWidgetTypeSports,
+    .widget = {
+      .sports = {
+        .league = prv_copy_optional_string(iter, MESSAGE_KEY_SPORTS_WIDGET_LEAGUE),
+        .home_team = prv_copy_optional_string(iter, MESSAGE_KEY_SPORTS_WIDGET_HOME_TEAM),
+        .away_team = prv_copy_optional_string(iter, MESSAGE_KEY_SPORTS_WIDGET_AWAY_TEAM),
+        .home_score = prv_get_optional_int(iter, MESSAGE_KEY_SPORTS_WIDGET_HOME_SCORE),
+        .away_score = prv_get_optional_int(iter, MESSAGE_KEY_SPORTS_WIDGET_AWAY_SCORE),
+        .state = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_STATE)->value->int32,
+        .progress = prv_copy_optional_string(iter, MESSAGE_KEY_SPORTS_WIDGET_PROGRESS),
+        .start_time = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_START_TIME)->value->int32,
+        .refresh_token = prv_copy_optional_string(iter, MESSAGE_KEY_SPORTS_WIDGET_REFRESH),
+        .refresh_until = refresh_until ? refresh_until->value->int32 : 0,
+      }
+    }
+  };
+  if (!widget.widget.sports.league || !widget.widget.sports.home_team || !widget.widget.sports.away_team) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Sports widget is missing its teams.");
+    free(widget.widget.sports.league);
+    free(widget.widget.sports.home_team);
+    free(widget.widget.sports.away_team);
+    free(widget.widget.sports.progress);
+    free(widget.widget.sports.refresh_token);
+    return;
+  }
+  conversation_add_widget(manager->conversation, &widget);
+  prv_conversation_updated(manager, true);
+}
+
+static void prv_process_pronunciation_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager) {
+  if (widget_type != 1) {
+    return;
+  }
+  ConversationWidget widget = {
+    .type = ConversationWidgetTypePronunciation,
+    .widget = {
+      .pronunciation = {
+        .word = prv_copy_optional_string(iter, MESSAGE_KEY_PRONUNCIATION_WIDGET_WORD),
+        .respelling = prv_copy_optional_string(iter, MESSAGE_KEY_PRONUNCIATION_WIDGET_RESPELLING),
+      }
+    }
+  };
+  if (!widget.widget.pronunciation.word || !widget.widget.pronunciation.respelling) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Pronunciation widget is missing its word or respelling.");
+    free(widget.widget.pronunciation.word);
+    free(widget.widget.pronunciation.respelling);
+    return;
+  }
+  conversation_add_widget(manager->conversation, &widget);
+  prv_conversation_updated(manager, true);
+}
+
+static void prv_process_page_widget(int widget_type, DictionaryIterator *iter, ConversationManager *manager) {
+  if (widget_type != 1) {
+    return;
+  }
+  Tuple *page = dict_find(iter, MESSAGE_KEY_PAGE_WIDGET_PAGE);
+  Tuple *pages = dict_find(iter, MESSAGE_KEY_PAGE_WIDGET_PAGES);
+  ConversationWidget widget = {
+    .type = ConversationWidgetTypePage,
+    .widget = {
+      .page = {
+        .document = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_DOCUMENT),
+        .title = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_TITLE),
+        .page_title = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_PAGE_TITLE),
+        .text = prv_copy_optional_string(iter, MESSAGE_KEY_PAGE_WIDGET_TEXT),
+        .page = page ? page->value->int32 : 1,
+        .pages = pages ? pages->value->int32 : 1,
+      }
+    }
+  };
+  if (!widget.widget.page.document || !widget.widget.page.title || !widget.widget.page.text) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Page widget is missing its document, title or text.");
+    free(widget.widget.page.document);
+    free(widget.widget.page.title);
+    free(widget.widget.page.page_title);
+    free(widget.widget.page.text);
+    return;
+  }
+  conversation_add_widget(manager->conversation, &widget);
+  prv_conversation_updated(manager, true);
+}
+
 static void prv_handle_app_message_inbox_dropped(AppMessageResult reason, void *context) {
   APP_LOG(APP_LOG_LEVEL_ERROR, "Received message dropped: %d", reason);
   ConversationManager* manager = context;
@@ -31,6 +31,8 @@ void conversation_manager_set_handler(ConversationManager* manager, Conversation
 void conversation_manager_add_input(ConversationManager* manager, const char* input);
 void conversation_manager_add_action(ConversationManager* manager, ConversationAction* action);
 void conversation_manager_add_widget(ConversationManager* manager, ConversationWidget* widget);
+bool conversation_manager_has_next_page(ConversationManager* manager);
+void conversation_manager_request_next_page(ConversationManager* manager);
 Conversation* conversation_manager_get_conversation(ConversationManager* manager);
 
 #endif
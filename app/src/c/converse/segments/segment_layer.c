@@ -23,6 +23,10 @@
 #include "widgets/weather_multi_day.h"
 #include "widgets/number.h"
 #include "widgets/timer.h"
+#include "widgets/sports.h"
+#include "widgets/pronunciation.h"
+#include "widgets/page.h"
+#include "widgets/interval_timer.h"
 
 #include <pebble.h>
 
@@ -38,6 +42,10 @@ typedef enum {
   SegmentTypeWeatherMultiDayWidget,
   SegmentTypeTimerWidget,
   SegmentTypeNumberWidget,
+  SegmentTypeSportsWidget,
+  SegmentTypePronunciationWidget,
+  SegmentTypePageWidget,
+  SegmentTypeIntervalTimerWidget,
 } SegmentType;
 
 typedef struct {
@@ -56,6 +64,10 @@ typedef struct {
     WeatherMultiDayWidget* weather_multi_day_widget;
     TimerWidget* timer_widget;
     NumberWidget* number_widget;
+    SportsWidget* sports_widget;
+    PronunciationWidget* pronunciation_widget;
+    PageWidget* page_widget;
+    IntervalTimerWidget* interval_timer_widget;
   };
 } SegmentLayerData;
 
@@ -97,6 +109,18 @@ SegmentLayer* segment_layer_create(GRect rect, ConversationEntry* entry, bool as
     case SegmentTypeNumberWidget:
       data->number_widget = number_widget_create(child_frame, entry);
       break;
+    case SegmentTypeSportsWidget:
+      data->sports_widget = sports_widget_create(child_frame, entry);
+      break;
+    case SegmentTypePronunciationWidget:
+      data->pronunciation_widget = pronunciation_widget_create(child_frame, entry);
+      break;
+    case SegmentTypePageWidget:
+      data->page_widget = page_widget_create(child_frame, entry);
+      break;
+    case SegmentTypeIntervalTimerWidget:
+      data->interval_timer_widget = interval_timer_widget_create(child_frame, entry);
+      break;
   }
   layer_add_child(layer, data->layer);
   GSize child_size = layer_get_frame(data->layer).size;
@@ -133,6 +157,18 @@ void segment_layer_destroy(SegmentLayer* layer) {
     case SegmentTypeNumberWidget:
       number_widget_destroy(data->number_widget);
       break;
+    case SegmentTypeSportsWidget:
+      sports_widget_destroy(data->sports_widget);
+      break;
+    case SegmentTypePronunciationWidget:
+      pronunciation_widget_destroy(data->pronunciation_widget);
+      break;
+    case SegmentTypePageWidget:
+      page_widget_destroy(data->page_widget);
+      break;
+    case SegmentTypeIntervalTimerWidget:
+      interval_timer_widget_destroy(data->interval_timer_widget);
+      break;
   }
   if (data->assistant_label_layer) {
     text_layer_destroy(data->assistant_label_layer);
@@ -169,6 +205,18 @@ void segment_layer_update(SegmentLayer* layer) {
     case SegmentTypeNumberWidget:
       number_widget_update(data->number_widget);
       break;
+    case SegmentTypeSportsWidget:
+      sports_widget_update(data->sports_widget);
+      break;
+    case SegmentTypePronunciationWidget:
+      pronunciation_widget_update(data->pronunciation_widget);
+      break;
+    case SegmentTypePageWidget:
+      page_widget_update(data->page_widget);
+      break;
+    case SegmentTypeIntervalTimerWidget:
+      interval_timer_widget_update(data->interval_timer_widget);
+      break;
   }
   GSize child_size = layer_get_frame(data->layer).size;
   GPoint origin = layer_get_frame(layer).origin;
@@ -200,6 +248,14 @@ static SegmentType prv_get_segment_type(ConversationEntry* entry) {
           return SegmentTypeTimerWidget;
         case ConversationWidgetTypeNumber:
           return SegmentTypeNumberWidget;
+        case ConversationWidgetTypeSports:
+          return SegmentTypeSportsWidget;
+        case ConversationWidgetTypePronunciation:
+          return SegmentTypePronunciationWidget;
+        case ConversationWidgetTypePage:
+          return SegmentTypePageWidget;
+        case ConversationWidgetTypeIntervalTimer:
+          return SegmentTypeIntervalTimerWidget;
       }
       break;
   }
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+#include "interval_timer.h"
+#include "../../conversation.h"
+#include "../../../util/style.h"
+#include <pebble.h>
+#include <pebble-events/pebble-events.h>
+
+typedef struct {
+  ConversationEntry* entry;
+  GDrawCommandImage *icon;
+  EventHandle event_handle;
+  char phase_text[24];
+  char time_text[12];
+} IntervalTimerWidgetData;
+
+static void prv_layer_update(Layer *layer, GContext *ctx);
+static void prv_handle_tick(struct tm *tick_time, TimeUnits units_changed, void *context);
+static void prv_update_text_buffers(IntervalTimerWidgetData* data);
+
+IntervalTimerWidget* interval_timer_widget_create(GRect rect, ConversationEntry* entry) {
+  Layer *layer = layer_create_with_data(GRect(rect.origin.x, rect.origin.y, rect.size.w, 73), sizeof(IntervalTimerWidgetData));
+  IntervalTimerWidgetData* data = layer_get_data(layer);
+
+  data->entry = entry;
+  data->icon = gdraw_command_image_create_with_resource(RESOURCE_ID_TIMER_ICON);
+  prv_update_text_buffers(data);
+  layer_set_update_proc(layer, prv_layer_update);
+
+  data->event_handle = events_tick_timer_service_subscribe_context(SECOND_UNIT, prv_handle_tick, layer);
+  return layer;
+}
+
+ConversationEntry* interval_timer_widget_get_entry(IntervalTimerWidget* layer) {
+  IntervalTimerWidgetData* data = layer_get_data(layer);
+  return data->entry;
+}
+
+void interval_timer_widget_destroy(IntervalTimerWidget* layer) {
+  IntervalTimerWidgetData* data = layer_get_data(layer);
+  gdraw_command_image_destroy(data->icon);
+  events_tick_timer_service_unsubscribe(data->event_handle);
+  layer_destroy(layer);
+}
+
+void interval_timer_widget_update(IntervalTimerWidget* layer) {
+  // nothing to do here.
+}
+
+static void prv_layer_update(Layer *layer, GContext *ctx) {
+  IntervalTimerWidgetData* data = layer_get_data(layer);
+  ConversationWidgetIntervalTimer *widget = &conversation_entry_get_widget(data->entry)->widget.interval_timer;
+  GRect bounds = layer_get_bounds(layer);
+#if defined(PBL_COLOR)
+  graphics_context_set_fill_color(ctx, BRANDED_BACKGROUND_COLOUR);
+  graphics_context_set_text_color(ctx, gcolor_legible_over(BRANDED_BACKGROUND_COLOUR));
+  graphics_fill_rect(ctx, bounds, 0, GCornerNone);
+#else
+  graphics_context_set_text_color(ctx, GColorBlack);
+#endif
+  graphics_context_set_stroke_color(ctx, GColorBlack);
+  graphics_draw_line(ctx, GPoint(0, 0), GPoint(bounds.size.w, 0));
+  graphics_draw_line(ctx, GPoint(0, bounds.size.h - 1), GPoint(bounds.size.w, bounds.size.h - 1));
+
+  gdraw_command_image_draw(ctx, data->icon, GPoint(5, 3));
+
+  const int16_t icon_space = 26;
+  const GRect title_rect = GRect(icon_space, bounds.origin.y, bounds.size.w - icon_space, 20);
+  const GRect phase_rect = GRect(5, bounds.origin.y + 19, bounds.size.w - 5, 20);
+  const GRect time_rect = GRect(5, bounds.origin.y + 36, bounds.size.w - 5, bounds.size.h - 36);
+
+  GFont title_font = fonts_get_system_font(FONT_KEY_GOTHIC_18_BOLD);
+  GFont phase_font = fonts_get_system_font(FONT_KEY_GOTHIC_18);
+  GFont time_font = fonts_get_system_font(FONT_KEY_LECO_32_BOLD_NUMBERS);
+  graphics_draw_text(ctx, widget->name ? widget->name : "Intervals", title_font, title_rect, GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+  graphics_draw_text(ctx, data->phase_text, phase_font, phase_rect, GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+  graphics_draw_text(ctx, data->time_text, time_font, time_rect, GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+}
+
+static void prv_handle_tick(struct tm *tick_time, TimeUnits units_changed, void *context) {
+  IntervalTimerWidget* layer = context;
+  IntervalTimerWidgetData* data = layer_get_data(layer);
+  prv_update_text_buffers(data);
+  layer_mark_dirty(context);
+}
+
+static void prv_update_text_buffers(IntervalTimerWidgetData* data) {
+  ConversationWidgetIntervalTimer *widget = &conversation_entry_get_widget(data->entry)->widget.interval_timer;
+  int index, remaining;
+  if (!interval_phase_at(widget->phases, widget->phase_count, widget->start_time, time(NULL), &index, &remaining)) {
+    strncpy(data->phase_text, "Done", sizeof(data->phase_text));
+    strncpy(data->time_text, "0:00", sizeof(data->time_text));
+    return;
+  }
+  int round, rounds;
+  interval_round_at(widget->phases, widget->phase_count, index, &round, &rounds);
+  snprintf(data->phase_text, sizeof(data->phase_text), "%s · %d/%d", interval_phase_kind_name(widget->phases[index].kind), round, rounds);
+  int minutes = remaining / 60;
+  int seconds = remaining % 60;
+  snprintf(data->time_text, sizeof(data->time_text), "%d:%02d", minutes, seconds);
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+#include "page.h"
+#include "../../conversation.h"
+#include <pebble.h>
+
+#define HEADER_FONT FONT_KEY_GOTHIC_14
+#define PAGE_TITLE_FONT FONT_KEY_GOTHIC_18_BOLD
+#define TEXT_FONT FONT_KEY_GOTHIC_18
+
+typedef struct {
+  ConversationEntry *entry;
+  // e.g. "The Sleepy Dragon - 2/5"
+  char header[64];
+  int16_t header_height;
+  int16_t page_title_height;
+  int16_t text_height;
+  int16_t hint_height;
+} PageWidgetData;
+
+static const char *HINT = "Hold select for the next page.";
+
+static void prv_layer_update(Layer *layer, GContext *ctx);
+static void prv_size_layer(Layer *layer);
+
+PageWidget* page_widget_create(GRect rect, ConversationEntry* entry) {
+  Layer *layer = layer_create_with_data(GRect(rect.origin.x, rect.origin.y, rect.size.w, 60), sizeof(PageWidgetData));
+  PageWidgetData *data = layer_get_data(layer);
+  ConversationWidgetPage *widget = &conversation_entry_get_widget(entry)->widget.page;
+  data->entry = entry;
+  snprintf(data->header, sizeof(data->header), "%s - %d/%d", widget->title, widget->page, widget->pages);
+  layer_set_update_proc(layer, prv_layer_update);
+  prv_size_layer(layer);
+  return layer;
+}
+
+ConversationEntry* page_widget_get_entry(PageWidget* layer) {
+  PageWidgetData* data = layer_get_data(layer);
+  return data->entry;
+}
+
+void page_widget_destroy(PageWidget* layer) {
+  layer_destroy(layer);
+}
+
+void page_widget_update(PageWidget* layer) {
+  // Nothing to do here: later pages are new widgets, rather than updates to this one.
+}
+
+static int16_t prv_text_height(const char *text, const char *font, int16_t width) {
+  if (!text || text[0] == '\0') {
+    return 0;
+  }
+  return graphics_text_layout_get_content_size(text, fonts_get_system_font(font), GRect(0, 0, width, 2000), GTextOverflowModeWordWrap, GTextAlignmentLeft).h + 4;
+}
+
+static void prv_size_layer(Layer *layer) {
+  PageWidgetData *data = layer_get_data(layer);
+  ConversationWidgetPage *widget = &conversation_entry_get_widget(data->entry)->widget.page;
+  GRect inset_bounds = grect_inset(layer_get_bounds(layer), GEdgeInsets(0, 5));
+  int16_t width = inset_bounds.size.w;
+  data->header_height = prv_text_height(data->header, HEADER_FONT, width);
+  data->page_title_height = prv_text_height(widget->page_title, PAGE_TITLE_FONT, width);
+  data->text_height = prv_text_height(widget->text, TEXT_FONT, width);
+  data->hint_height = widget->page < widget->pages ? prv_text_height(HINT, HEADER_FONT, width) : 0;
+  GRect frame = layer_get_frame(layer);
+  frame.size.h = data->header_height + data->page_title_height + data->text_height + data->hint_height + 6;
+  layer_set_frame(layer, frame);
+}
+
+static void prv_layer_update(Layer *layer, GContext *ctx) {
+  PageWidgetData *data = layer_get_data(layer);
+  ConversationWidgetPage *widget = &conversation_entry_get_widget(data->entry)->widget.page;
+  GRect bounds = layer_get_bounds(layer);
+  GRect inset_bounds = grect_inset(bounds, GEdgeInsets(0, 5));
+  graphics_context_set_stroke_color(ctx, GColorBlack);
+  graphics_draw_line(ctx, GPoint(0, 0), GPoint(bounds.size.w, 0));
+  graphics_draw_line(ctx, GPoint(0, bounds.size.h - 1), GPoint(bounds.size.w, bounds.size.h - 1));
+
+  graphics_context_set_text_color(ctx, GColorBlack);
+  int16_t y = inset_bounds.origin.y;
+  graphics_draw_text(ctx, data->header, fonts_get_system_font(HEADER_FONT), GRect(inset_bounds.origin.x, y, inset_bounds.size.w, data->header_height), GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
+  y += data->header_height;
+  if (data->page_title_height) {
+    graphics_draw_text(ctx, widget->page_title, fonts_get_system_font(PAGE_TITLE_FONT), GRect(inset_bounds.origin.x, y, inset_bounds.size.w, data->page_title_height), GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
+    y += data->page_title_height;
+  }
+  graphics_draw_text(ctx, widget->text, fonts_get_system_font(TEXT_FONT), GRect(inset_bounds.origin.x, y, inset_bounds.size.w, data->text_height), GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
+  y += data->text_height;
+  if (data->hint_height) {
+    graphics_draw_text(ctx, HINT, fonts_get_system_font(HEADER_FONT), GRect(inset_bounds.origin.x, y, inset_bounds.size.w, data->hint_height), GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
+  }
+}
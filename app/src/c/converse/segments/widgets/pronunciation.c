@@ -0,0 +1,79 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+#include "pronunciation.h"
+
+#define WORD_FONT FONT_KEY_GOTHIC_18
+#define RESPELLING_FONT FONT_KEY_GOTHIC_24_BOLD
+
+typedef struct {
+  ConversationEntry *entry;
+  int16_t word_height;
+  int16_t respelling_height;
+} PronunciationWidgetData;
+
+static void prv_layer_update(Layer *layer, GContext *ctx);
+static void prv_size_layer(Layer *layer);
+
+PronunciationWidget* pronunciation_widget_create(GRect rect, ConversationEntry* entry) {
+  Layer *layer = layer_create_with_data(GRect(rect.origin.x, rect.origin.y, rect.size.w, 60), sizeof(PronunciationWidgetData));
+  PronunciationWidgetData *data = layer_get_data(layer);
+  data->entry = entry;
+  layer_set_update_proc(layer, prv_layer_update);
+  prv_size_layer(layer);
+  return layer;
+}
+
+ConversationEntry* pronunciation_widget_get_entry(PronunciationWidget* layer) {
+  PronunciationWidgetData* data = layer_get_data(layer);
+  return data->entry;
+}
+
+void pronunciation_widget_destroy(PronunciationWidget* layer) {
+  layer_destroy(layer);
+}
+
+void pronunciation_widget_update(PronunciationWidget* layer) {
+  // Nothing to do here.
+}
+
+static void prv_size_layer(Layer *layer) {
+  PronunciationWidgetData *data = layer_get_data(layer);
+  ConversationWidgetPronunciation *widget = &conversation_entry_get_widget(data->entry)->widget.pronunciation;
+  GRect bounds = layer_get_bounds(layer);
+  GRect inset_bounds = grect_inset(bounds, GEdgeInsets(0, 5));
+  // Long respellings wrap at their hyphens, so the whole thing is always visible.
+  data->word_height = graphics_text_layout_get_content_size(widget->word, fonts_get_system_font(WORD_FONT), GRect(0, 0, inset_bounds.size.w, 1000), GTextOverflowModeWordWrap, GTextAlignmentLeft).h;
+  data->respelling_height = graphics_text_layout_get_content_size(widget->respelling, fonts_get_system_font(RESPELLING_FONT), GRect(0, 0, inset_bounds.size.w, 1000), GTextOverflowModeWordWrap, GTextAlignmentLeft).h;
+  GRect frame = layer_get_frame(layer);
+  frame.size.h = data->word_height + data->respelling_height + 10;
+  layer_set_frame(layer, frame);
+}
+
+static void prv_layer_update(Layer *layer, GContext *ctx) {
+  PronunciationWidgetData *data = layer_get_data(layer);
+  ConversationWidgetPronunciation *widget = &conversation_entry_get_widget(data->entry)->widget.pronunciation;
+  GRect bounds = layer_get_bounds(layer);
+  GRect inset_bounds = grect_inset(bounds, GEdgeInsets(0, 5));
+  graphics_context_set_stroke_color(ctx, GColorBlack);
+  graphics_draw_line(ctx, GPoint(0, 0), GPoint(bounds.size.w, 0));
+  graphics_draw_line(ctx, GPoint(0, bounds.size.h - 1), GPoint(bounds.size.w, bounds.size.h - 1));
+
+  graphics_context_set_text_color(ctx, GColorBlack);
+  GRect word_rect = GRect(inset_bounds.origin.x, inset_bounds.origin.y, inset_bounds.size.w, data->word_height);
+  graphics_draw_text(ctx, widget->word, fonts_get_system_font(WORD_FONT), word_rect, GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
+  GRect respelling_rect = GRect(inset_bounds.origin.x, inset_bounds.origin.y + data->word_height, inset_bounds.size.w, data->respelling_height);
+  graphics_draw_text(ctx, widget->respelling, fonts_get_system_font(RESPELLING_FONT), respelling_rect, GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+#include "sports.h"
+#include "../../conversation.h"
+#include "../../../util/style.h"
+#include "../../../util/time.h"
+#include <pebble.h>
+#include <pebble-events/pebble-events.h>
+
+// How often to ask the phone for the latest score while the match might be changing.
+#define REFRESH_INTERVAL_MS (60 * 1000)
+#define SCORE_WIDTH 30
+
+typedef struct {
+  ConversationEntry* entry;
+  EventHandle app_message_handle;
+  AppTimer *refresh_timer;
+  char status[24];
+  char home_score[6];
+  char away_score[6];
+} SportsWidgetData;
+
+static void prv_layer_update(Layer *layer, GContext *ctx);
+static void prv_update_text_buffers(SportsWidgetData* data);
+static void prv_schedule_refresh(SportsWidget* layer);
+static void prv_refresh(void *context);
+static void prv_app_message_received(DictionaryIterator *iter, void *context);
+
+SportsWidget* sports_widget_create(GRect rect, ConversationEntry* entry) {
+  Layer *layer = layer_create_with_data(GRect(rect.origin.x, rect.origin.y, rect.size.w, 88), sizeof(SportsWidgetData));
+  SportsWidgetData* data = layer_get_data(layer);
+  ConversationWidgetSports *widget = &conversation_entry_get_widget(entry)->widget.sports;
+
+  data->entry = entry;
+  data->app_message_handle = NULL;
+  data->refresh_timer = NULL;
+  prv_update_text_buffers(data);
+  layer_set_update_proc(layer, prv_layer_update);
+
+  if (widget->refresh_token && widget->refresh_until > time(NULL)) {
+    data->app_message_handle = events_app_message_register_inbox_received(prv_app_message_received, layer);
+    prv_schedule_refresh(layer);
+  }
+  return layer;
+}
+
+ConversationEntry* sports_widget_get_entry(SportsWidget* layer) {
+  SportsWidgetData* data = layer_get_data(layer);
+  return data->entry;
+}
+
+void sports_widget_destroy(SportsWidget* layer) {
+  SportsWidgetData* data = layer_get_data(layer);
+  if (data->refresh_timer) {
+    app_timer_cancel(data->refresh_timer);
+  }
+  if (data->app_message_handle) {
+    events_app_message_unsubscribe(data->app_message_handle);
+  }
+  layer_destroy(layer);
+}
+
+void sports_widget_update(SportsWidget* layer) {
+  // nothing to do here.
+}
+
+static void prv_schedule_refresh(SportsWidget* layer) {
+  SportsWidgetData* data = layer_get_data(layer);
+  ConversationWidgetSports *widget = &conversation_entry_get_widget(data->entry)->widget.sports;
+  data->refresh_timer = NULL;
+  if (!widget->refresh_token || widget->refresh_until <= time(NULL) || widget->state == ConversationSportsStateFinished || widget->state == ConversationSportsStatePostponed) {
+    return;
+  }
+  data->refresh_timer = app_timer_register(REFRESH_INTERVAL_MS, prv_refresh, layer);
+}
+
+static void prv_refresh(void *context) {
+  SportsWidget* layer = context;
+  SportsWidgetData* data = layer_get_data(layer);
+  ConversationWidgetSports *widget = &conversation_entry_get_widget(data->entry)->widget.sports;
+  DictionaryIterator *iter;
+  // If the phone's busy, we'll just try again next time.
+  if (app_message_outbox_begin(&iter) == APP_MSG_OK) {
+    dict_write_cstring(iter, MESSAGE_KEY_SPORTS_WIDGET_REFRESH, widget->refresh_token);
+    app_message_outbox_send();
+  }
+  prv_schedule_refresh(layer);
+}
+
+static void prv_app_message_received(DictionaryIterator *iter, void *context) {
+  SportsWidget* layer = context;
+  SportsWidgetData* data = layer_get_data(layer);
+  ConversationWidgetSports *widget = &conversation_entry_get_widget(data->entry)->widget.sports;
+  Tuple *tuple = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_REFRESH);
+  // Other sports widgets get their own updates.
+  if (!tuple || !widget->refresh_token || strcmp(tuple->value->cstring, widget->refresh_token) != 0) {
+    return;
+  }
+  tuple = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_REFRESH_UNTIL);
+  if (tuple) {
+    widget->refresh_until = tuple->value->int32;
+  }
+  tuple = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_STATE);
+  if (tuple) {
+    widget->state = tuple->value->int32;
+  }
+  Tuple *home = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_HOME_SCORE);
+  Tuple *away = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_AWAY_SCORE);
+  if (home && away) {
+    widget->home_score = home->value->int32;
+    widget->away_score = away->value->int32;
+  }
+  tuple = dict_find(iter, MESSAGE_KEY_SPORTS_WIDGET_PROGRESS);
+  if (tuple) {
+    if (widget->progress) {
+      free(widget->progress);
+    }
+    widget->progress = malloc(strlen(tuple->value->cstring) + 1);
+    strcpy(widget->progress, tuple->value->cstring);
+  }
+  prv_update_text_buffers(data);
+  layer_mark_dirty(layer);
+}
+
+static void prv_update_text_buffers(SportsWidgetData* data) {
+  ConversationWidgetSports *widget = &conversation_entry_get_widget(data->entry)->widget.sports;
+  if (widget->home_score >= 0 && widget->away_score >= 0) {
+    snprintf(data->home_score, sizeof(data->home_score), "%d", widget->home_score);
+    snprintf(data->away_score, sizeof(data->away_score), "%d", widget->away_score);
+  } else {
+    data->home_score[0] = '\0';
+    data->away_score[0] = '\0';
+  }
+  switch (widget->state) {
+    case ConversationSportsStateScheduled:
+      format_datetime(data->status, sizeof(data->status), widget->start_time);
+      break;
+    case ConversationSportsStateLive:
+      if (widget->progress && widget->progress[0] != '\0') {
+        snprintf(data->status, sizeof(data->status), "Live - %s", widget->progress);
+      } else {
+        strncpy(data->status, "Live", sizeof(data->status));
+      }
+      break;
+    case ConversationSportsStateFinished:
+      strncpy(data->status, "Full time", sizeof(data->status));
+      break;
+    case ConversationSportsStatePostponed:
+      strncpy(data->status, "Postponed", sizeof(data->status));
+      break;
+  }
+  data->status[sizeof(data->status) - 1] = '\0';
+}
+
+static void prv_layer_update(Layer *layer, GContext *ctx) {
+  SportsWidgetData* data = layer_get_data(layer);
+  ConversationWidgetSports *widget = &conversation_entry_get_widget(data->entry)->widget.sports;
+  GRect bounds = layer_get_bounds(layer);
+#if defined(PBL_COLOR)
+  graphics_context_set_fill_color(ctx, BRANDED_BACKGROUND_COLOUR);
+  graphics_context_set_text_color(ctx, gcolor_legible_over(BRANDED_BACKGROUND_COLOUR));
+  graphics_fill_rect(ctx, bounds, 0, GCornerNone);
+#else
+  graphics_context_set_text_color(ctx, GColorBlack);
+#endif
+  graphics_context_set_stroke_color(ctx, GColorBlack);
+  graphics_draw_line(ctx, GPoint(0, 0), GPoint(bounds.size.w, 0));
+  graphics_draw_line(ctx, GPoint(0, bounds.size.h - 1), GPoint(bounds.size.w, bounds.size.h - 1));
+
+  GFont small_font = fonts_get_system_font(FONT_KEY_GOTHIC_14);
+  GFont team_font = fonts_get_system_font(FONT_KEY_GOTHIC_24_BOLD);
+  GFont status_font = fonts_get_system_font(FONT_KEY_GOTHIC_18_BOLD);
+  const int16_t team_width = bounds.size.w - SCORE_WIDTH - 10;
+
+  graphics_draw_text(ctx, widget->league, small_font, GRect(5, 0, bounds.size.w - 10, 16), GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+  graphics_draw_text(ctx, widget->home_team, team_font, GRect(5, 12, team_width, 26), GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+  graphics_draw_text(ctx, data->home_score, team_font, GRect(bounds.size.w - SCORE_WIDTH - 5, 12, SCORE_WIDTH, 26), GTextOverflowModeFill, GTextAlignmentRight, NULL);
+  graphics_draw_text(ctx, widget->away_team, team_font, GRect(5, 36, team_width, 26), GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+  graphics_draw_text(ctx, data->away_score, team_font, GRect(bounds.size.w - SCORE_WIDTH - 5, 36, SCORE_WIDTH, 26), GTextOverflowModeFill, GTextAlignmentRight, NULL);
+  graphics_draw_text(ctx, data->status, status_font, GRect(5, 64, bounds.size.w - 10, 20), GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+}
@@ -24,22 +24,47 @@ typedef struct {
   char temp_string[10];
   char feels_like_string[20];
   char wind_speed[10];
+  // The optional details, e.g. "Hum 60%  Rain 20%  UV 3", or empty if there are none.
+  char details[40];
 } WeatherCurrentWidgetData;
 
+#define BASE_HEIGHT 85
+#define DETAILS_HEIGHT 20
+
 static void prv_layer_update(Layer *layer, GContext *ctx);
 
+static void prv_format_details(char *buffer, size_t size, ConversationWidgetWeatherCurrent *w) {
+  size_t len = 0;
+  buffer[0] = '\0';
+  if (w->humidity >= 0 && len < size) {
+    len += snprintf(buffer + len, size - len, "Hum %d%%  ", w->humidity);
+  }
+  if (w->precip_chance >= 0 && len < size) {
+    len += snprintf(buffer + len, size - len, "Rain %d%%  ", w->precip_chance);
+  }
+  if (w->uv_index >= 0 && len < size) {
+    len += snprintf(buffer + len, size - len, "UV %d", w->uv_index);
+  }
+}
+
 WeatherCurrentWidget* weather_current_widget_create(GRect rect, ConversationEntry* entry) {
-  Layer *layer = layer_create_with_data(GRect(rect.origin.x, rect.origin.y, rect.size.w, 85), sizeof(WeatherCurrentWidgetData));
-  WeatherCurrentWidgetData *data = layer_get_data(layer);
   ConversationWidgetWeatherCurrent *w = &conversation_entry_get_widget(entry)->widget.weather_current;
+  bool has_details = w->humidity >= 0 || w->precip_chance >= 0 || w->uv_index >= 0;
+  int height = has_details ? BASE_HEIGHT + DETAILS_HEIGHT : BASE_HEIGHT;
+  Layer *layer = layer_create_with_data(GRect(rect.origin.x, rect.origin.y, rect.size.w, height), sizeof(WeatherCurrentWidgetData));
+  WeatherCurrentWidgetData *data = layer_get_data(layer);
 
   data->entry = entry;
   data->icon = gdraw_command_image_create_with_resource(weather_widget_get_medium_resource_for_condition(w->condition));
   layer_set_update_proc(layer, prv_layer_update);
 
-  snprintf(data->temp_string, sizeof(data->temp_string), "%d°", w->temperature);
+  char temperature[8];
+  weather_widget_format_temperature(temperature, sizeof(temperature), w->temperature, w->temp_decimals);
+  snprintf(data->temp_string, sizeof(data->temp_string), "%s°", temperature);
   snprintf(data->wind_speed, sizeof(data->wind_speed), "%d %s", w->wind_speed, w->wind_speed_unit);
-  snprintf(data->feels_like_string, sizeof(data->feels_like_string), "Seems %d°", w->feels_like);
+  weather_widget_format_temperature(temperature, sizeof(temperature), w->feels_like, w->temp_decimals);
+  snprintf(data->feels_like_string, sizeof(data->feels_like_string), "Seems %s°", temperature);
+  prv_format_details(data->details, sizeof(data->details), w);
   return layer;
 }
 
@@ -60,6 +85,9 @@ static void prv_layer_update(Layer *layer, GContext *ctx) {
   graphics_draw_text(ctx, data->temp_string, fonts_get_system_font(FONT_KEY_LECO_32_BOLD_NUMBERS), GRect(5, 15, bounds.size.w, 50), GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
   graphics_draw_text(ctx, data->feels_like_string, fonts_get_system_font(FONT_KEY_GOTHIC_18), GRect(5, 45, bounds.size.w - 70, 40), GTextOverflowModeWordWrap, GTextAlignmentLeft, NULL);
   graphics_draw_text(ctx, widget->summary, fonts_get_system_font(FONT_KEY_GOTHIC_18), GRect(5, 62, bounds.size.w - 10, 20), GTextOverflowModeFill, GTextAlignmentLeft, NULL);
+  if (data->details[0] != '\0') {
+    graphics_draw_text(ctx, data->details, fonts_get_system_font(FONT_KEY_GOTHIC_18_BOLD), GRect(5, BASE_HEIGHT - 3, bounds.size.w - 10, DETAILS_HEIGHT), GTextOverflowModeTrailingEllipsis, GTextAlignmentLeft, NULL);
+  }
 
   if (data->icon) {
     gdraw_command_image_draw(ctx, data->icon, GPoint(bounds.size.w - 60, 20));
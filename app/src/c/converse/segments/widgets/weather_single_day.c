@@ -21,7 +21,7 @@
 typedef struct {
   ConversationEntry *entry;
   GDrawCommandImage *icon;
-  char temp_summary[20];
+  char temp_summary[24];
 } WeatherSingleDayWidgetData;
 
 static void prv_layer_update(Layer *layer, GContext *ctx);
@@ -36,7 +36,10 @@ WeatherSingleDayWidget* weather_single_day_widget_create(GRect rect, Conversatio
   data->icon = gdraw_command_image_create_with_resource(weather_widget_get_medium_resource_for_condition(w->condition));
   layer_set_update_proc(layer, prv_layer_update);
 
-  snprintf(data->temp_summary, sizeof(data->temp_summary)-1, "H: %d°\nL: %d°", w->high, w->low);
+  char high[8], low[8];
+  weather_widget_format_temperature(high, sizeof(high), w->high, w->temp_decimals);
+  weather_widget_format_temperature(low, sizeof(low), w->low, w->temp_decimals);
+  snprintf(data->temp_summary, sizeof(data->temp_summary)-1, "H: %s°\nL: %s°", high, low);
   return layer;
 }
 
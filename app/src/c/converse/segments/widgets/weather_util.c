@@ -82,4 +82,18 @@ GColor weather_widget_get_colour_for_condition(int condition) {
     default:
       return GColorWhite;
   }
-}
\ No newline at end of file
+}
+
+void weather_widget_format_temperature(char *buffer, size_t size, int temperature, int decimals) {
+  if (decimals != 1) {
+    snprintf(buffer, size, "%d", temperature);
+    return;
+  }
+  // Pebble's snprintf doesn't do floats, so we have to do the decimal point ourselves.
+  int whole = temperature / 10;
+  int tenths = temperature % 10;
+  if (tenths < 0) {
+    tenths = -tenths;
+  }
+  snprintf(buffer, size, "%s%d.%d", (temperature < 0 && whole == 0) ? "-" : "", whole, tenths);
+}
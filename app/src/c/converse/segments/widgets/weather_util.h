@@ -22,5 +22,7 @@
 int weather_widget_get_medium_resource_for_condition(int condition);
 int weather_widget_get_small_resource_for_condition(int condition);
 GColor weather_widget_get_colour_for_condition(int condition);
+// Formats a temperature that has been scaled up by 10^decimals, e.g. 217 with one decimal as "21.7".
+void weather_widget_format_temperature(char *buffer, size_t size, int temperature, int decimals);
 
 #endif //WEATHER_UTIL_H
@@ -23,6 +23,7 @@
 #include "../util/style.h"
 #include "../util/action_menu_crimes.h"
 #include "../vibes/haptic_feedback.h"
+#include "../intervals/manager.h"
 
 #include <pebble.h>
 
@@ -51,6 +52,7 @@ struct SessionWindow {
   int timeout;
   char* starting_prompt;
   char* last_prompt_label;
+  char* suggestion_labels[CONVERSATION_MAX_SUGGESTIONS];
 };
 
 static void prv_window_load(Window *window);
@@ -69,6 +71,7 @@ static void prv_scrolled_handler(ScrollLayer* scroll_layer, void* context);
 static void prv_refresh_timeout(SessionWindow* sw);
 static void prv_timed_out(void *ctx);
 static void prv_cancel_timeout(SessionWindow* sw);
+static void prv_action_menu_next_page(ActionMenu *action_menu, const ActionMenuItem *action, void *context);
 static void prv_action_menu_query(ActionMenu *action_menu, const ActionMenuItem *action, void *context);
 static void prv_action_menu_input(ActionMenu *action_menu, const ActionMenuItem *action, void *context);
 static void prv_action_menu_report_thread(ActionMenu *action_menu, const ActionMenuItem *action, void *context);
@@ -385,6 +388,12 @@ static void prv_destroy_action_menu(ActionMenu *action_menu, const ActionMenuIte
     free(sw->last_prompt_label);
     sw->last_prompt_label = NULL;
   }
+  for (int i = 0; i < CONVERSATION_MAX_SUGGESTIONS; ++i) {
+    if (sw->suggestion_labels[i]) {
+      free(sw->suggestion_labels[i]);
+      sw->suggestion_labels[i] = NULL;
+    }
+  }
 }
 
 static void prv_select_long_pressed(ClickRecognizerRef recognizer, void *context) {
@@ -392,13 +401,27 @@ static void prv_select_long_pressed(ClickRecognizerRef recognizer, void *context
   if (!conversation_is_idle(conversation_manager_get_conversation(sw->manager))) {
     return;
   }
-  ActionMenuLevel *action_menu = action_menu_level_create(5);
+  ActionMenuLevel *action_menu = action_menu_level_create(6 + CONVERSATION_MAX_SUGGESTIONS);
+  int separator_index = 3;
+  // Reading on is the most likely thing to want after a page, so it goes first.
+  if (conversation_manager_has_next_page(sw->manager)) {
+    action_menu_level_add_action(action_menu, "Next page", prv_action_menu_next_page, NULL);
+    separator_index++;
+  }
+  // Then whatever the server thought the user might say next.
+  Conversation *conversation = conversation_manager_get_conversation(sw->manager);
+  int suggestion_count = conversation_get_suggestion_count(conversation);
+  for (int i = 0; i < suggestion_count; ++i) {
+    const char* suggestion = conversation_get_suggestion(conversation, i);
+    sw->suggestion_labels[i] = malloc(strlen(suggestion) + 3);
+    snprintf(sw->suggestion_labels[i], strlen(suggestion) + 3, "\"%s\"", suggestion);
+    action_menu_level_add_action(action_menu, sw->suggestion_labels[i], prv_action_menu_input, (void*)suggestion);
+    separator_index++;
+  }
   action_menu_level_add_action(action_menu, "\"Yes.\"", prv_action_menu_input, "Yes.");
   action_menu_level_add_action(action_menu, "\"No.\"", prv_action_menu_input, "No.");
-  Conversation *conversation = conversation_manager_get_conversation(sw->manager);
   ConversationEntry *entry = conversation_peek(conversation);
   EntryType type = conversation_entry_get_type(entry);
-  int separator_index = 3;
   if (type == EntryTypeError) {
     ConversationEntry *last_prompt = conversation_get_last_of_type(conversation, EntryTypePrompt);
     if (last_prompt != NULL) {
@@ -427,6 +450,11 @@ static void prv_select_long_pressed(ClickRecognizerRef recognizer, void *context
   action_menu_open(&config);
 }
 
+static void prv_action_menu_next_page(ActionMenu *action_menu, const ActionMenuItem *action, void *context) {
+  SessionWindow* sw = context;
+  conversation_manager_request_next_page(sw->manager);
+}
+
 static void prv_action_menu_query(ActionMenu *action_menu, const ActionMenuItem *action, void *context) {
   SessionWindow* sw = context;
   dictation_session_start(sw->dictation);
@@ -470,6 +498,14 @@ static void prv_cancel_timeout(SessionWindow* sw) {
 }
 
 static void prv_timed_out(void *ctx) {
+  SessionWindow* sw = ctx;
+  sw->timeout_handle = NULL;
+  // Closing the app would stop a running interval timer, so hang on until it's finished.
+  if (interval_manager_is_running()) {
+    APP_LOG(APP_LOG_LEVEL_DEBUG, "Timed out, but an interval timer is running");
+    prv_refresh_timeout(sw);
+    return;
+  }
   APP_LOG(APP_LOG_LEVEL_DEBUG, "Timed out");
   window_stack_pop(true);
 }
@@ -0,0 +1,237 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+#include "manager.h"
+#include "../converse/conversation_manager.h"
+
+#include <pebble-events/pebble-events.h>
+#include <pebble.h>
+
+// Each phase arrives from the phone as three bytes: its kind, then its length in seconds, little-endian.
+#define PHASE_BYTES 3
+#define MAX_PHASES 200
+
+typedef struct {
+  IntervalPhase *phases;
+  int phase_count;
+  time_t start_time;
+  // Tracked separately from the clock, so that a timer firing a little early still moves us on to the next phase.
+  int current_phase;
+  AppTimer *timer;
+  EventHandle app_message_handle;
+} IntervalManager;
+
+static IntervalManager s_manager;
+
+static void prv_handle_app_message_inbox_received(DictionaryIterator *iterator, void *context);
+static void prv_send_interval_response(StatusCode response);
+static int prv_start(const uint8_t *data, uint16_t length, char *name);
+static void prv_stop();
+static void prv_schedule_next_phase();
+static void prv_phase_changed(void *context);
+
+static const uint32_t s_finished_segments[] = {400, 200, 400, 200, 800};
+
+void interval_manager_init() {
+  s_manager.phases = NULL;
+  s_manager.phase_count = 0;
+  s_manager.timer = NULL;
+  s_manager.app_message_handle = events_app_message_register_inbox_received(prv_handle_app_message_inbox_received, NULL);
+}
+
+bool interval_manager_is_running() {
+  return s_manager.phases != NULL;
+}
+
+bool interval_phase_at(const IntervalPhase *phases, int phase_count, time_t start_time, time_t now, int *index, int *remaining) {
+  time_t phase_end = start_time;
+  for (int i = 0; i < phase_count; ++i) {
+    phase_end += phases[i].seconds;
+    if (now < phase_end) {
+      *index = i;
+      *remaining = phase_end - now;
+      return true;
+    }
+  }
+  return false;
+}
+
+void interval_round_at(const IntervalPhase *phases, int phase_count, int index, int *round, int *rounds) {
+  *round = 0;
+  *rounds = 0;
+  for (int i = 0; i < phase_count; ++i) {
+    if (phases[i].kind != IntervalPhaseKindWork) {
+      continue;
+    }
+    ++*rounds;
+    if (i <= index) {
+      *round = *rounds;
+    }
+  }
+  if (*round == 0) {
+    *round = 1;
+  }
+}
+
+const char *interval_phase_kind_name(IntervalPhaseKind kind) {
+  switch (kind) {
+    case IntervalPhaseKindWork:
+      return "Work";
+    case IntervalPhaseKindRest:
+      return "Rest";
+    case IntervalPhaseKindLongRest:
+      return "Long rest";
+  }
+  return "";
+}
+
+static int prv_start(const uint8_t *data, uint16_t length, char *name) {
+  int phase_count = length / PHASE_BYTES;
+  if (phase_count == 0 || phase_count > MAX_PHASES || length % PHASE_BYTES != 0) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Interval timer has %d bytes of phases, which doesn't make sense.", length);
+    return E_INVALID_ARGUMENT;
+  }
+  IntervalPhase *phases = malloc(sizeof(IntervalPhase) * phase_count);
+  if (!phases) {
+    return E_OUT_OF_MEMORY;
+  }
+  for (int i = 0; i < phase_count; ++i) {
+    const uint8_t *phase = &data[i * PHASE_BYTES];
+    phases[i].kind = phase[0];
+    phases[i].seconds = phase[1] | (phase[2] << 8);
+    if (phases[i].kind > IntervalPhaseKindLongRest || phases[i].seconds == 0) {
+      APP_LOG(APP_LOG_LEVEL_ERROR, "Interval timer phase %d (kind %d, %d seconds) is invalid.", i, phase[0], phases[i].seconds);
+      free(phases);
+      return E_INVALID_ARGUMENT;
+    }
+  }
+  // Only one interval timer runs at once; starting another replaces it.
+  prv_stop();
+  s_manager.phases = phases;
+  s_manager.phase_count = phase_count;
+  s_manager.start_time = time(NULL);
+  s_manager.current_phase = 0;
+  prv_schedule_next_phase();
+  vibes_long_pulse();
+
+  ConversationManager *conversation_manager = conversation_manager_get_current();
+  if (conversation_manager) {
+    // The widget gets its own copy of the phases, so it can keep showing how things went after we're done.
+    ConversationWidget widget = {
+      .type = ConversationWidgetTypeIntervalTimer,
+      .locally_created = true,
+      .widget = {
+        .interval_timer = {
+          .start_time = s_manager.start_time,
+          .name = NULL,
+          .phases = malloc(sizeof(IntervalPhase) * phase_count),
+          .phase_count = phase_count,
+        }
+      }
+    };
+    memcpy(widget.widget.interval_timer.phases, phases, sizeof(IntervalPhase) * phase_count);
+    if (name) {
+      size_t name_len = strlen(name);
+      widget.widget.interval_timer.name = malloc(name_len + 1);
+      strncpy(widget.widget.interval_timer.name, name, name_len + 1);
+    }
+    conversation_manager_add_widget(conversation_manager, &widget);
+  }
+  APP_LOG(APP_LOG_LEVEL_INFO, "Started an interval timer with %d phases.", phase_count);
+  return S_SUCCESS;
+}
+
+static void prv_stop() {
+  if (s_manager.timer) {
+    app_timer_cancel(s_manager.timer);
+    s_manager.timer = NULL;
+  }
+  if (s_manager.phases) {
+    free(s_manager.phases);
+    s_manager.phases = NULL;
+  }
+  s_manager.phase_count = 0;
+}
+
+static void prv_schedule_next_phase() {
+  time_t phase_end = s_manager.start_time;
+  for (int i = 0; i <= s_manager.current_phase; ++i) {
+    phase_end += s_manager.phases[i].seconds;
+  }
+  time_t delay = phase_end - time(NULL);
+  s_manager.timer = app_timer_register(delay > 0 ? delay * 1000 : 0, prv_phase_changed, NULL);
+}
+
+static void prv_phase_changed(void *context) {
+  s_manager.timer = NULL;
+  light_enable_interaction();
+  int index = ++s_manager.current_phase;
+  if (index >= s_manager.phase_count) {
+    APP_LOG(APP_LOG_LEVEL_INFO, "Interval timer finished.");
+    VibePattern pattern = {
+      .durations = s_finished_segments,
+      .num_segments = ARRAY_LENGTH(s_finished_segments),
+    };
+    vibes_enqueue_custom_pattern(pattern);
+    prv_stop();
+    return;
+  }
+  // One long buzz to get going, two short ones to stop.
+  if (s_manager.phases[index].kind == IntervalPhaseKindWork) {
+    vibes_long_pulse();
+  } else {
+    vibes_double_pulse();
+  }
+  prv_schedule_next_phase();
+}
+
+static void prv_handle_app_message_inbox_received(DictionaryIterator *iterator, void *context) {
+  Tuple *tuple = dict_find(iterator, MESSAGE_KEY_INTERVAL_TIMER_CANCEL);
+  if (tuple != NULL) {
+    bool was_running = interval_manager_is_running();
+    prv_stop();
+    prv_send_interval_response(was_running ? S_SUCCESS : E_DOES_NOT_EXIST);
+    return;
+  }
+  tuple = dict_find(iterator, MESSAGE_KEY_INTERVAL_TIMER_PHASES);
+  if (tuple == NULL) {
+    return;
+  }
+  if (tuple->type != TUPLE_BYTE_ARRAY) {
+    prv_send_interval_response(E_INVALID_ARGUMENT);
+    return;
+  }
+  char *name = NULL;
+  Tuple *name_tuple = dict_find(iterator, MESSAGE_KEY_INTERVAL_TIMER_NAME);
+  if (name_tuple != NULL && strlen(name_tuple->value->cstring) > 0) {
+    name = name_tuple->value->cstring;
+  }
+  prv_send_interval_response(prv_start(tuple->value->data, tuple->length, name));
+}
+
+static void prv_send_interval_response(StatusCode response) {
+  DictionaryIterator *iter;
+  AppMessageResult result = app_message_outbox_begin(&iter);
+  if (result != APP_MSG_OK) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Returning status code %d to phone failed in open: %d.", response, result);
+    return;
+  }
+  dict_write_int32(iter, MESSAGE_KEY_INTERVAL_TIMER_RESULT, response);
+  result = app_message_outbox_send();
+  if (result != APP_MSG_OK) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Returning status code %d to phone failed in send: %d.", response, result);
+  }
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+#ifndef INTERVALS_MANAGER_H
+#define INTERVALS_MANAGER_H
+
+#include <pebble.h>
+
+typedef enum {
+  IntervalPhaseKindWork = 0,
+  IntervalPhaseKindRest = 1,
+  IntervalPhaseKindLongRest = 2,
+} IntervalPhaseKind;
+
+typedef struct {
+  IntervalPhaseKind kind;
+  uint16_t seconds;
+} IntervalPhase;
+
+void interval_manager_init();
+// Whether an interval timer is still running, in which case the app shouldn't close itself.
+bool interval_manager_is_running();
+
+// Works out where a sequence of phases that started at start_time is at now. Returns false once it's finished;
+// otherwise sets the index of the current phase, and how many seconds are left in it.
+bool interval_phase_at(const IntervalPhase *phases, int phase_count, time_t start_time, time_t now, int *index, int *remaining);
+// Which round the phase at index is part of, counting from 1, and how many rounds there are in all.
+void interval_round_at(const IntervalPhase *phases, int phase_count, int index, int *round, int *rounds);
+const char *interval_phase_kind_name(IntervalPhaseKind kind);
+
+#endif
@@ -23,6 +23,8 @@ typedef enum {
 } QuickLaunchBehaviour;
 
 typedef enum {
+  // Only used for individual alarms, to mean whichever pattern is set for all alarms (or timers).
+  VibePatternSettingDefault = 0,
   VibePatternSettingReveille = 1,
   VibePatternSettingMario = 2,
   VibePatternSettingNudgeNudge = 3,
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+#include "stopwatch.h"
+#include "../util/persist_keys.h"
+
+#include <pebble-events/pebble-events.h>
+#include <pebble.h>
+
+#define MAX_LAPS 20
+
+// These match the commands sent by the phone.
+typedef enum {
+  StopwatchCommandStart = 1,
+  StopwatchCommandResume = 2,
+  StopwatchCommandStop = 3,
+  StopwatchCommandLap = 4,
+  StopwatchCommandGet = 5,
+} StopwatchCommand;
+
+// Rather than counting, we keep when the stopwatch was started and work everything out from the clock, so that it
+// keeps going while the app is closed. This is persisted as is, so fields can only be added at the end.
+typedef struct {
+  bool running;
+  // When it was last started or resumed, in milliseconds since the epoch.
+  int64_t started_at_ms;
+  // How long it had already run before then, in milliseconds.
+  int32_t elapsed_before_ms;
+  uint8_t lap_count;
+  // The elapsed time at each lap, in milliseconds.
+  int32_t laps_ms[MAX_LAPS];
+} StopwatchState;
+
+static StopwatchState s_state;
+static EventHandle s_app_message_handle;
+
+static void prv_handle_app_message_inbox_received(DictionaryIterator *iterator, void *context);
+static void prv_send_state(StatusCode status);
+static int64_t prv_now_ms();
+static int32_t prv_elapsed_ms();
+static void prv_save();
+
+void stopwatch_init() {
+  memset(&s_state, 0, sizeof(s_state));
+  persist_read_data(PERSIST_KEY_STOPWATCH, &s_state, sizeof(s_state));
+  s_app_message_handle = events_app_message_register_inbox_received(prv_handle_app_message_inbox_received, NULL);
+}
+
+static int64_t prv_now_ms() {
+  time_t seconds;
+  uint16_t ms;
+  time_ms(&seconds, &ms);
+  return (int64_t)seconds * 1000 + ms;
+}
+
+static int32_t prv_elapsed_ms() {
+  if (!s_state.running) {
+    return s_state.elapsed_before_ms;
+  }
+  return s_state.elapsed_before_ms + (int32_t)(prv_now_ms() - s_state.started_at_ms);
+}
+
+static void prv_save() {
+  persist_write_data(PERSIST_KEY_STOPWATCH, &s_state, sizeof(s_state));
+}
+
+static StatusCode prv_run_command(StopwatchCommand command) {
+  switch (command) {
+    case StopwatchCommandStart:
+      memset(&s_state, 0, sizeof(s_state));
+      s_state.running = true;
+      s_state.started_at_ms = prv_now_ms();
+      break;
+    case StopwatchCommandResume:
+      if (s_state.running) {
+        return S_SUCCESS;
+      }
+      s_state.running = true;
+      s_state.started_at_ms = prv_now_ms();
+      break;
+    case StopwatchCommandStop:
+      if (!s_state.running) {
+        return E_INVALID_OPERATION;
+      }
+      s_state.elapsed_before_ms = prv_elapsed_ms();
+      s_state.running = false;
+      break;
+    case StopwatchCommandLap:
+      if (!s_state.running) {
+        return E_INVALID_OPERATION;
+      }
+      if (s_state.lap_count >= MAX_LAPS) {
+        return E_OUT_OF_RESOURCES;
+      }
+      s_state.laps_ms[s_state.lap_count++] = prv_elapsed_ms();
+      break;
+    case StopwatchCommandGet:
+      return S_SUCCESS;
+    default:
+      return E_INVALID_ARGUMENT;
+  }
+  prv_save();
+  return S_SUCCESS;
+}
+
+static void prv_handle_app_message_inbox_received(DictionaryIterator *iterator, void *context) {
+  Tuple *tuple = dict_find(iterator, MESSAGE_KEY_STOPWATCH_COMMAND);
+  if (tuple == NULL) {
+    return;
+  }
+  StatusCode status = prv_run_command(tuple->value->int32);
+  APP_LOG(APP_LOG_LEVEL_INFO, "Stopwatch command %d -> %d", (int)tuple->value->int32, status);
+  prv_send_state(status);
+}
+
+static void prv_send_state(StatusCode status) {
+  DictionaryIterator *iter;
+  AppMessageResult result = app_message_outbox_begin(&iter);
+  if (result != APP_MSG_OK) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Opening dict to send stopwatch state failed: %d.", result);
+    return;
+  }
+  dict_write_int32(iter, MESSAGE_KEY_STOPWATCH_RESULT, status);
+  dict_write_int16(iter, MESSAGE_KEY_STOPWATCH_RUNNING, s_state.running);
+  dict_write_int32(iter, MESSAGE_KEY_STOPWATCH_ELAPSED, prv_elapsed_ms());
+  if (s_state.lap_count > 0) {
+    // Pebble is little-endian, so the phone can read these straight back out as int32s.
+    dict_write_data(iter, MESSAGE_KEY_STOPWATCH_LAPS, (const uint8_t *)s_state.laps_ms, sizeof(int32_t) * s_state.lap_count);
+  }
+  result = app_message_outbox_send();
+  if (result != APP_MSG_OK) {
+    APP_LOG(APP_LOG_LEVEL_ERROR, "Sending stopwatch state failed: %d.", result);
+  }
+}
@@ -16,9 +16,19 @@
 
 var message_keys = require('message_keys');
 
+// These match VibePatternSetting on the watch.
+var VIBE_PATTERNS = {
+    'reveille': 1,
+    'mario': 2,
+    'nudge_nudge': 3,
+    'jackhammer': 4,
+    'standard': 5,
+};
+
 function setAlarm(session, message, callback) {
     var time = message['time'];
     var name = message['name'] || null;
+    var vibePattern = VIBE_PATTERNS[message['vibration']] || 0;
     var isTimer = !!message['isTimer'];
     var cancelling = !!message['cancel'];
     var date = new Date(time);
@@ -99,6 +109,9 @@ function setAlarm(session, message, callback) {
         if (name) {
             request.SET_ALARM_NAME = name;
         }
+        if (vibePattern) {
+            request.SET_ALARM_VIBE_PATTERN = vibePattern;
+        }
         session.enqueue(request);
     } else {
         session.enqueue({
@@ -0,0 +1,28 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Opens the server's scanning page on the phone. The page sends whatever it scans straight to the server, so all we
+// need to do is open it.
+exports.scanBarcode = function(session, message, callback) {
+  var url = message['url'];
+  if (!url) {
+    callback({"error": "No scanning page provided."});
+    return;
+  }
+  console.log("Opening barcode scanner: " + url);
+  Pebble.openURL(url);
+  callback({"status": "ok"});
+};
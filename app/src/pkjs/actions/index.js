@@ -17,6 +17,9 @@
 var reminders = require('./reminders');
 var alarms = require('./alarms');
 var feedback = require('./feedback');
+var barcode = require('./barcode');
+var intervals = require('./intervals');
+var stopwatch = require('./stopwatch');
 var config = require('../config.js');
 
 var actionMap = {
@@ -26,9 +29,12 @@ var actionMap = {
     'set_alarm': alarms.setAlarm,
     'get_alarm': alarms.getAlarm,
     'send_feedback': feedback.sendFeedback,
+    'scan_barcode': barcode.scanBarcode,
+    'set_interval_timer': intervals.setIntervalTimer,
+    'stopwatch': stopwatch.stopwatch,
 };
 
-var extraActions = ['named_alarms'];
+var extraActions = ['named_alarms', 'reminder_triggers', 'alarm_vibes'];
 
 exports.handleAction = function(session, ws, actionParamString) {
     var params = JSON.parse(actionParamString);
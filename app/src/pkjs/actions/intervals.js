@@ -0,0 +1,94 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// These match IntervalPhaseKind on the watch.
+var PHASE_KINDS = {
+    'work': 0,
+    'rest': 1,
+    'long_rest': 2,
+};
+
+function setIntervalTimer(session, message, callback) {
+    var cancelling = !!message['cancel'];
+    var request = {};
+    if (cancelling) {
+        request.INTERVAL_TIMER_CANCEL = 1;
+    } else {
+        // Each phase is packed into three bytes: its kind, then its length in seconds, little-endian.
+        var phases = message['phases'] || [];
+        var packed = [];
+        for (var i = 0; i < phases.length; ++i) {
+            var kind = PHASE_KINDS[phases[i]['kind']];
+            var seconds = phases[i]['seconds'];
+            if (kind === undefined || !(seconds > 0) || seconds > 65535) {
+                callback({"error": "Invalid interval timer phase " + JSON.stringify(phases[i]) + "."});
+                return;
+            }
+            packed.push(kind, seconds & 0xff, (seconds >> 8) & 0xff);
+        }
+        if (packed.length === 0) {
+            callback({"error": "An interval timer needs at least one phase."});
+            return;
+        }
+        request.INTERVAL_TIMER_PHASES = packed;
+        if (message['name']) {
+            request.INTERVAL_TIMER_NAME = message['name'];
+        }
+    }
+
+    var timeout = setTimeout(function() {
+        console.log("Timed out, returning error message.");
+        callback({"error": "Timed out waiting for a response from the watch."});
+        cleanup();
+    }, 2000);
+
+    var cleanup = function() {
+        Pebble.removeEventListener('appmessage', handleMessage);
+        clearTimeout(timeout);
+    };
+
+    var handleMessage = function(event) {
+        var data = event.payload;
+        if (!('INTERVAL_TIMER_RESULT' in data)) {
+            return;
+        }
+        var result = data['INTERVAL_TIMER_RESULT'];
+        console.log("Got an interval timer response: " + result);
+        switch (result) {
+        case 0: // S_SUCCESS
+            callback({"status": "ok"});
+            break;
+        case -9: // E_DOES_NOT_EXIST
+            callback({"error": "There is no interval timer running."});
+            break;
+        case -4: // E_INVALID_ARGUMENT
+            callback({"error": "The watch couldn't make sense of the interval timer."});
+            break;
+        case -5: // E_OUT_OF_MEMORY
+            callback({"error": "The watch doesn't have enough memory for that many intervals."});
+            break;
+        default:
+            callback({"error": "Something unexpected went wrong."});
+            break;
+        }
+        cleanup();
+    };
+    Pebble.addEventListener('appmessage', handleMessage);
+    session.enqueue(request);
+    console.log("Sent interval timer request to Pebble.");
+}
+
+exports.setIntervalTimer = setIntervalTimer;
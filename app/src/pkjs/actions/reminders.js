@@ -19,6 +19,17 @@ var reminders = require('../lib/reminders');
 exports.setReminder = function(session, message, callback) {
   var when = message['time'];
   var what = message['what'];
+  var trigger = message['trigger'];
+
+  if (trigger) {
+    try {
+      reminders.addTriggeredReminder(what, trigger);
+      callback({"status": "ok"});
+    } catch (err) {
+      callback({"error": "Failed to set reminder: " + err.message});
+    }
+    return;
+  }
   
   try {
     reminders.addReminder(what, when);
@@ -44,7 +55,7 @@ exports.getReminders = function(session, message, callback) {
     }
     callback({
       "status": "ok",
-      "reminders": allReminders
+      "reminders": allReminders.concat(reminders.getTriggeredReminders())
     });
   } catch (err) {
     callback({"error": "Failed to get reminders: " + err.message});
@@ -58,7 +69,7 @@ exports.deleteReminder = function(session, message, callback) {
   }
   
   try {
-    var success = reminders.deleteReminder(reminderId);
+    var success = reminders.deleteTriggeredReminder(reminderId) || reminders.deleteReminder(reminderId);
     if (!success) {
       callback({"error": "Reminder not found"});
     }
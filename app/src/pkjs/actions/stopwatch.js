@@ -0,0 +1,84 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// These match StopwatchCommand on the watch.
+var COMMANDS = {
+    'start': 1,
+    'resume': 2,
+    'stop': 3,
+    'lap': 4,
+    'get': 5,
+};
+
+function stopwatch(session, message, callback) {
+    var command = COMMANDS[message['command']];
+    if (!command) {
+        callback({"error": "Unknown stopwatch command '" + message['command'] + "'."});
+        return;
+    }
+
+    var timeout = setTimeout(function() {
+        console.log("Timed out, returning error message.");
+        callback({"error": "Timed out waiting for a response from the watch."});
+        cleanup();
+    }, 2000);
+
+    var cleanup = function() {
+        Pebble.removeEventListener('appmessage', handleMessage);
+        clearTimeout(timeout);
+    };
+
+    var handleMessage = function(event) {
+        var data = event.payload;
+        if (!('STOPWATCH_RESULT' in data)) {
+            return;
+        }
+        var result = data['STOPWATCH_RESULT'];
+        console.log("Got a stopwatch response: " + result);
+        switch (result) {
+        case 0: // S_SUCCESS
+            var laps = [];
+            var lapBytes = data['STOPWATCH_LAPS'] || [];
+            for (var i = 0; i + 3 < lapBytes.length; i += 4) {
+                laps.push(lapBytes[i] | (lapBytes[i + 1] << 8) | (lapBytes[i + 2] << 16) | (lapBytes[i + 3] << 24));
+            }
+            callback({
+                "status": "ok",
+                "running": !!data['STOPWATCH_RUNNING'],
+                "elapsed_ms": data['STOPWATCH_ELAPSED'],
+                "laps_ms": laps,
+            });
+            break;
+        case -7: // E_OUT_OF_RESOURCES
+            callback({"error": "The stopwatch can't record any more laps."});
+            break;
+        case -10: // E_INVALID_OPERATION
+            callback({"error": "The stopwatch isn't running."});
+            break;
+        default:
+            callback({"error": "Something unexpected went wrong."});
+            break;
+        }
+        cleanup();
+    };
+    Pebble.addEventListener('appmessage', handleMessage);
+    session.enqueue({
+        STOPWATCH_COMMAND: command,
+    });
+    console.log("Sent stopwatch request to Pebble.");
+}
+
+exports.stopwatch = stopwatch;
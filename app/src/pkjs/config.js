@@ -27,3 +27,8 @@ exports.setSetting = function(key, value) {
 exports.isLocationEnabled = function() {
     return !!exports.getSettings()['LOCATION_ENABLED'];
 }
+
+exports.isPreciseLocationEnabled = function() {
+    // This setting was added later, so anyone who hasn't seen it yet keeps the precise location they had before.
+    return exports.getSettings()['PRECISE_LOCATION'] !== false;
+}
@@ -22,7 +22,12 @@ var clayConfig = require('./config.json');
 var customConfigFunction = require('./custom_config');
 var config = require('./config');
 var reminders = require('./reminders');
+var preferences = require('./preferences');
+var quickActions = require('./quick_actions');
+var scores = require('./scores');
+var pages = require('./pages');
 var feedback = require('./lib/feedback');
+var triggeredReminders = require('./lib/reminders');
 var package_json = require('package.json');
 
 
@@ -30,8 +35,19 @@ var clay = new Clay(clayConfig, customConfigFunction);
 
 function main() {
     doQuotaWarning();
-    location.update();
+    // Opening Bobby is the only time we get to check whether any triggered reminders should go off.
+    triggeredReminders.fireTriggeredReminders(null);
+    location.update(function(pos) {
+        triggeredReminders.fireTriggeredReminders(pos);
+    });
+    preferences.syncPreferences();
     Pebble.addEventListener('appmessage', handleAppMessage);
+    // Clay has already saved the new settings by the time this runs.
+    Pebble.addEventListener('webviewclosed', function(e) {
+        if (e && e.response) {
+            preferences.syncPreferences();
+        }
+    });
 }
 
 function doQuotaWarning() {
@@ -49,9 +65,9 @@ function handleAppMessage(e) {
     console.log("Inbound app message!");
     console.log(JSON.stringify(e));
     var data = e.payload;
-    if (data.PROMPT) {
+    if (data.PROMPT || data.QUICK_ACTION) {
         console.log("Starting a new Session...");
-        var s = new session.Session(data.PROMPT, data.THREAD_ID);
+        var s = new session.Session(data.PROMPT, data.THREAD_ID, data.QUICK_ACTION, data.FORK_FROM);
         s.run();
         return;
     }
@@ -60,6 +76,18 @@ function handleAppMessage(e) {
         return;
     }
 
+    if (data.QUICK_ACTION_LIST_REQUEST) {
+        console.log("Requesting quick actions...");
+        quickActions.handleQuickActionListRequest();
+    }
+    if (data.SPORTS_WIDGET_REFRESH) {
+        console.log("Refreshing score...");
+        scores.handleScoreRefresh(data.SPORTS_WIDGET_REFRESH);
+    }
+    if (data.PAGE_REQUEST) {
+        console.log("Requesting page...");
+        pages.handlePageRequest(data.PAGE_REQUEST, data.PAGE_REQUEST_NUMBER);
+    }
     if (data.QUOTA_REQUEST) {
         console.log("Requesting quota...");
         quota.handleQuotaRequest();
@@ -0,0 +1,53 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// When a request fails, the session saves what it knows about it here. If the user then reports a problem, the
+// saved failure is uploaded along with the report, so that it can be replayed on the server.
+
+var STORAGE_KEY = 'last-failure';
+// How long a saved failure stays relevant to a report.
+var MAX_AGE_MS = 60 * 60 * 1000;
+
+exports.MAX_LOG_LINES = 100;
+
+exports.save = function(failure) {
+    failure.time = Date.now();
+    try {
+        localStorage.setItem(STORAGE_KEY, JSON.stringify(failure));
+    } catch (e) {
+        console.log("Couldn't save failure: " + e);
+    }
+}
+
+// Returns the most recent failure, if there's one recent enough to be worth reporting, and forgets it.
+exports.take = function() {
+    var saved = localStorage.getItem(STORAGE_KEY);
+    localStorage.removeItem(STORAGE_KEY);
+    if (!saved) {
+        return null;
+    }
+    var failure;
+    try {
+        failure = JSON.parse(saved);
+    } catch (e) {
+        return null;
+    }
+    if (!failure.time || Date.now() - failure.time > MAX_AGE_MS) {
+        return null;
+    }
+    delete failure.time;
+    return failure;
+}
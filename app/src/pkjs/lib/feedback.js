@@ -17,6 +17,7 @@
 var config = require('../config');
 var location = require('../location');
 var reminders = require('./reminders');
+var failures = require('./failures');
 var package_json = require('package.json');
 var urls = require('../urls');
 var session = require('../session');
@@ -105,6 +106,21 @@ function sendRequest(request, url, callback) {
     req.send(JSON.stringify(request));
 }
 
+// If a request failed recently, that's probably what's being reported, so send what we know about it too.
+function sendSavedFailure(request) {
+    var failure = failures.take();
+    if (!failure) {
+        return;
+    }
+    var report = constructFeedbackMetadata(request);
+    for (var key in failure) {
+        report[key] = failure[key];
+    }
+    sendRequest(report, urls.ERROR_REPORT_URL, function(success, status) {
+        console.log("Error report " + (success ? "sent" : "failed with status " + status));
+    });
+}
+
 exports.sendFeedback = function(feedbackText, threadId, callback) {
     var feedback = constructFeedbackMetadata(null);
     if (feedbackText) {
@@ -119,6 +135,7 @@ exports.sendFeedback = function(feedbackText, threadId, callback) {
 exports.handleFeedbackRequest = function(request) {
     var feedback = constructFeedbackMetadata(request);
     feedback['text'] = request['FEEDBACK_TEXT'];
+    sendSavedFailure(request);
     sendRequest(feedback, urls.REPORT_URL, function(success, status) {
         Pebble.sendAppMessage({'FEEDBACK_SEND_RESULT': success ? 0 : 1});
     });
@@ -127,6 +144,7 @@ exports.handleFeedbackRequest = function(request) {
 exports.handleReportRequest = function(request) {
     var report = constructFeedbackMetadata(request);
     report['thread_uuid'] = request['REPORT_THREAD_UUID'];
+    sendSavedFailure(request);
     sendRequest(report, urls.REPORT_URL, function(success, status) {
         Pebble.sendAppMessage({'REPORT_SEND_RESULT': success ? 0 : 1});
     });
@@ -18,6 +18,8 @@ var timeline = require('../actions/timeline');
 
 // Store reminders in localStorage
 var REMINDERS_STORAGE_KEY = 'bobby_reminders';
+// Reminders that go off on something other than a time, which we check for ourselves.
+var TRIGGERED_REMINDERS_STORAGE_KEY = 'bobby_triggered_reminders';
 // Keep expired reminders for 24 hours before cleanup
 var EXPIRED_REMINDER_TTL = 24 * 60 * 60 * 1000; // 24 hours in milliseconds
 
@@ -117,6 +119,79 @@ function deleteReminder(id) {
   return true;
 }
 
+function loadTriggeredReminders() {
+  var stored = localStorage.getItem(TRIGGERED_REMINDERS_STORAGE_KEY);
+  return stored ? JSON.parse(stored) : [];
+}
+
+function saveTriggeredReminders(reminders) {
+  localStorage.setItem(TRIGGERED_REMINDERS_STORAGE_KEY, JSON.stringify(reminders));
+}
+
+// Triggered reminders don't have a time, so they can't go on the timeline. We keep them here until their trigger
+// happens, then show them as a notification.
+function addTriggeredReminder(text, trigger) {
+  console.log("Setting a reminder: \"" + text + "\" on " + trigger.type);
+  var reminderId = "bobby-triggered-reminder-" + Math.random();
+  var reminders = loadTriggeredReminders();
+  reminders.push({
+    id: reminderId,
+    what: text,
+    trigger: trigger
+  });
+  saveTriggeredReminders(reminders);
+  return reminderId;
+}
+
+function deleteTriggeredReminder(id) {
+  var reminders = loadTriggeredReminders();
+  var remaining = reminders.filter(function(r) {
+    return r.id !== id;
+  });
+  if (remaining.length === reminders.length) {
+    return false;
+  }
+  saveTriggeredReminders(remaining);
+  return true;
+}
+
+function getTriggeredReminders() {
+  return loadTriggeredReminders();
+}
+
+// Returns the distance between two points in metres.
+function distanceMetres(lat1, lon1, lat2, lon2) {
+  var toRad = Math.PI / 180;
+  var dLat = (lat2 - lat1) * toRad;
+  var dLon = (lon2 - lon1) * toRad;
+  var a = Math.sin(dLat / 2) * Math.sin(dLat / 2) +
+    Math.cos(lat1 * toRad) * Math.cos(lat2 * toRad) * Math.sin(dLon / 2) * Math.sin(dLon / 2);
+  return 6371000 * 2 * Math.atan2(Math.sqrt(a), Math.sqrt(1 - a));
+}
+
+// Shows every triggered reminder whose trigger has happened, and forgets about them. pos is where the user is now,
+// or null if we don't know, in which case arrival reminders are left for next time.
+function fireTriggeredReminders(pos) {
+  var reminders = loadTriggeredReminders();
+  var remaining = reminders.filter(function(r) {
+    var trigger = r.trigger;
+    var fired = false;
+    if (trigger.type === 'next_open') {
+      fired = true;
+    } else if (trigger.type === 'arrive' && pos) {
+      fired = distanceMetres(pos.lat, pos.lon, trigger.lat, trigger.lon) <= trigger.radius_m;
+    }
+    if (fired) {
+      console.log("Reminder triggered: \"" + r.what + "\"");
+      Pebble.showSimpleNotificationOnPebble("Reminder", r.what);
+    }
+    return !fired;
+  });
+  if (remaining.length < reminders.length) {
+    saveTriggeredReminders(remaining);
+  }
+}
+
 function getAllReminders() {
   // Clean up expired reminders before returning the list
   var reminders = cleanupExpiredReminders();
@@ -136,5 +211,9 @@ function getAllReminders() {
 module.exports = {
   addReminder: addReminder,
   deleteReminder: deleteReminder,
-  getAllReminders: getAllReminders
+  getAllReminders: getAllReminders,
+  addTriggeredReminder: addTriggeredReminder,
+  deleteTriggeredReminder: deleteTriggeredReminder,
+  getTriggeredReminders: getTriggeredReminders,
+  fireTriggeredReminders: fireTriggeredReminders
 }; 
\ No newline at end of file
@@ -17,7 +17,8 @@
 var cachedLon = undefined;
 var cachedLat = undefined;
 
-exports.update = function() {
+// callback, if given, is called with the new position once we have it.
+exports.update = function(callback) {
     // start with whatever we knew before, if anything.
     var oldLon = localStorage.getItem('oldLon');
     var oldLat = localStorage.getItem('oldLat');
@@ -33,6 +34,9 @@ exports.update = function() {
         console.log("position updated: (" + cachedLat + ", " + cachedLon + ")");
         localStorage.setItem('oldLon', cachedLon);
         localStorage.setItem('oldLat', cachedLat);
+        if (callback) {
+            callback({lon: cachedLon, lat: cachedLat});
+        }
     }, function (err) {
         console.log("Failed to update location: " + err);
     }, {
@@ -49,3 +53,9 @@ exports.isReady = function() {
 exports.getPos = function() {
     return {lon: cachedLon, lat: cachedLat};
 }
+
+// Rounds the position to one decimal place, which is within about 10km: enough to tell which town the user is in,
+// but not where in it.
+exports.getCoarsePos = function() {
+    return {lon: Math.round(cachedLon * 10) / 10, lat: Math.round(cachedLat * 10) / 10};
+}
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+var session = require('./session');
+var PAGE_URL = require('./urls').PAGE_URL;
+var pageMessage = require('./widgets/pages').pageMessage;
+
+// Fetches another page of a document for the watch, which shows it as a new page widget. Only one page is sent at a
+// time, to keep each transfer small.
+exports.handlePageRequest = function(document, page) {
+    var req = new XMLHttpRequest();
+    var url = PAGE_URL + '?token=' + session.userToken;
+    url += '&document=' + encodeURIComponent(document);
+    url += '&page=' + page;
+    req.open('GET', url, true);
+    req.onload = function() {
+        if (req.readyState !== 4) {
+            return;
+        }
+        if (req.status === 200) {
+            Pebble.sendAppMessage(pageMessage(JSON.parse(req.responseText)));
+        } else if (req.status === 404) {
+            Pebble.sendAppMessage({WARNING: "That page isn't available any more."});
+        } else {
+            console.log("Page request returned error code " + req.status.toString());
+            Pebble.sendAppMessage({WARNING: "Couldn't get the next page."});
+        }
+    };
+    req.send();
+}
@@ -0,0 +1,64 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+var session = require('./session');
+var config = require('./config');
+
+var PREFERENCES_URL = require('./urls').PREFERENCES_URL;
+
+function request(method, body, callback) {
+    var url = PREFERENCES_URL + '?token=' + session.userToken;
+    var req = new XMLHttpRequest();
+    req.open(method, url, true);
+    req.onload = function(e) {
+        if (req.readyState === 4) {
+            if (req.status === 200) {
+                callback(JSON.parse(req.responseText));
+            } else {
+                console.log("Preferences request returned error code " + req.status.toString());
+            }
+        }
+    }
+    if (body) {
+        req.setRequestHeader('Content-Type', 'application/json');
+        req.send(JSON.stringify(body));
+    } else {
+        req.send();
+    }
+}
+
+// Copies the settings from the settings page to the server, keeping any stored preferences that the settings page
+// doesn't know about.
+exports.syncPreferences = function() {
+    var settings = config.getSettings();
+    request('GET', null, function(prefs) {
+        prefs.units = settings['UNIT_PREFERENCE'] || '';
+        prefs.language = settings['LANGUAGE_CODE'] || '';
+        prefs.persona = settings['PERSONA'] || '';
+        if (settings['QUIET_HOURS_ENABLED']) {
+            prefs.quiet_hours = {
+                start: settings['QUIET_HOURS_START'] || '22:00',
+                end: settings['QUIET_HOURS_END'] || '07:00',
+                urgent_severity: settings['QUIET_HOURS_URGENT'] || 'severe'
+            };
+        } else {
+            delete prefs.quiet_hours;
+        }
+        request('PUT', prefs, function(saved) {
+            console.log("Saved preferences: " + JSON.stringify(saved));
+        });
+    });
+}
@@ -0,0 +1,60 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+var QUICK_ACTIONS_URL = require('./urls').QUICK_ACTIONS_URL;
+
+function getSettings() {
+    return JSON.parse(localStorage.getItem('clay-settings')) || {};
+}
+
+// Fetches the quick actions - one-tap queries that don't need dictation - in the user's language, and sends them to
+// the watch to build a menu from: first QUICK_ACTION_COUNT, then each action's QUICK_ACTION (its ID, to send back in
+// place of a PROMPT) and QUICK_ACTION_LABEL.
+exports.handleQuickActionListRequest = function() {
+    var url = QUICK_ACTIONS_URL + '?lang=' + encodeURIComponent(getSettings()['LANGUAGE_CODE'] || '');
+    console.log("Fetching quick actions from " + url);
+    var req = new XMLHttpRequest();
+    req.open('GET', url, true);
+    req.onload = function() {
+        if (req.readyState !== 4) {
+            return;
+        }
+        var actions = [];
+        if (req.status === 200) {
+            actions = JSON.parse(req.responseText)['actions'];
+        } else {
+            console.log("Request returned error code " + req.status.toString());
+        }
+        Pebble.sendAppMessage({
+            QUICK_ACTION_COUNT: actions.length
+        });
+        function sendNextAction(index) {
+            if (index >= actions.length) return;
+            Pebble.sendAppMessage({
+                QUICK_ACTION: actions[index]['id'],
+                QUICK_ACTION_LABEL: actions[index]['label']
+            }, function() {
+                sendNextAction(index + 1);
+            }, function() {
+                console.log('Failed to send quick action, retrying.');
+                sendNextAction(index);
+            });
+        }
+        sendNextAction(0);
+    };
+    req.send();
+}
@@ -0,0 +1,52 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+var SCORE_URL = require('./urls').SCORE_URL;
+var SPORTS_STATES = require('./widgets/sports').SPORTS_STATES;
+
+// Looks up the latest score for a sports widget, given its refresh token, and sends it back to the watch tagged with
+// the same token so that it can tell which widget it's for. Expired tokens are answered with a
+// SPORTS_WIDGET_REFRESH_UNTIL of zero, which tells the watch to stop asking.
+exports.handleScoreRefresh = function(token) {
+    var req = new XMLHttpRequest();
+    req.open('GET', SCORE_URL + '?token=' + encodeURIComponent(token), true);
+    req.onload = function() {
+        if (req.readyState !== 4) {
+            return;
+        }
+        var message = {
+            SPORTS_WIDGET_REFRESH: token
+        };
+        if (req.status === 200) {
+            var update = JSON.parse(req.responseText);
+            message['SPORTS_WIDGET_STATE'] = SPORTS_STATES[update['state']] || 0;
+            if (update['home_score'] !== undefined && update['away_score'] !== undefined) {
+                message['SPORTS_WIDGET_HOME_SCORE'] = update['home_score'];
+                message['SPORTS_WIDGET_AWAY_SCORE'] = update['away_score'];
+            }
+            message['SPORTS_WIDGET_PROGRESS'] = update['progress'] || '';
+        } else if (req.status === 404) {
+            message['SPORTS_WIDGET_REFRESH_UNTIL'] = 0;
+        } else {
+            // Something went wrong upstream; the watch will ask again later.
+            console.log("Score request returned error code " + req.status.toString());
+            return;
+        }
+        Pebble.sendAppMessage(message);
+    };
+    req.send();
+}
@@ -18,17 +18,33 @@ var location = require('./location');
 var config = require('./config');
 var actions = require('./actions');
 var widgets = require('./widgets');
+var failures = require('./lib/failures');
 
 var API_URL = require('./urls').QUERY_URL;
 var package_json = require('package.json');
 
-function Session(prompt, threadId) {
+// quickAction, if given, is the ID of a quick action (see quick_actions.js) to run in place of the prompt.
+// forkFrom, if given, is the request ID of an earlier answer in the user's history or pins to start a new
+// conversation from.
+function Session(prompt, threadId, quickAction, forkFrom) {
     this.prompt = prompt;
     this.threadId = threadId;
+    this.quickAction = quickAction;
+    this.forkFrom = forkFrom;
     this.ws = undefined;
     this.queue = [];
     this.hasOpenDialog = false;
     this.messagesInFlight = 0;
+    // Recent events, and the last widget received, in case the request fails and the user reports it.
+    this.log = [];
+    this.lastWidget = null;
+}
+
+Session.prototype.note = function(line) {
+    this.log.push(new Date().toISOString() + ' ' + line);
+    if (this.log.length > failures.MAX_LOG_LINES) {
+        this.log.shift();
+    }
 }
 
 function getSettings() {
@@ -37,23 +53,52 @@ function getSettings() {
 
 Session.prototype.run = function() {
     console.log("Opening websocket connection...");
-    var url = API_URL + '?prompt=' + encodeURIComponent(this.prompt) + '&token=' + exports.userToken;
+    var url = API_URL + '?prompt=' + encodeURIComponent(this.prompt || '') + '&token=' + exports.userToken;
+    if (this.quickAction) {
+        url += '&quickAction=' + encodeURIComponent(this.quickAction);
+    }
     if (location.isReady() && config.isLocationEnabled()) {
-        var loc = location.getPos();
+        var precise = config.isPreciseLocationEnabled();
+        var loc = precise ? location.getPos() : location.getCoarsePos();
         url += '&lon=' + loc.lon + '&lat=' + loc.lat;
+        if (!precise) {
+            url += '&locationPrecision=coarse';
+        }
     } else {
         url += '&location=unknown';
     }
     if (this.threadId) {
         url += '&threadId=' + encodeURIComponent(this.threadId);
+    } else if (this.forkFrom) {
+        url += '&forkFrom=' + encodeURIComponent(this.forkFrom);
     }
     // negate this because JavaScript does it backwards for some reason.
     url += '&tzOffset=' + (-(new Date()).getTimezoneOffset());
     url += '&actions=' + actions.getSupportedActions().join(',');
-    url += '&widgets=weather,timer,number';
+    url += '&widgets=weather,timer,number,sports,pronunciation,page';
+    url += '&iconSet=pebble';
     var settings = getSettings();
-    url += '&units=' + settings['UNIT_PREFERENCE'] || '';
-    url += '&lang=' + settings['LANGUAGE_CODE'] || '';
+    // Units, language and personality are also stored on the server by preferences.syncPreferences, but that may not
+    // have finished yet if the settings were only just changed, so they're sent here too. These take precedence.
+    if (settings['UNIT_PREFERENCE']) {
+        url += '&units=' + encodeURIComponent(settings['UNIT_PREFERENCE']);
+    }
+    if (settings['LANGUAGE_CODE']) {
+        url += '&lang=' + encodeURIComponent(settings['LANGUAGE_CODE']);
+    }
+    if (settings['PERSONA']) {
+        url += '&persona=' + encodeURIComponent(settings['PERSONA']);
+    }
+    url += '&contentFilter=' + (settings['CONTENT_FILTER'] || '');
+    if (settings['TEMP_DECIMALS']) {
+        url += '&tempDecimals=1';
+    }
+    if (settings['KID_MODE']) {
+        url += '&profile=kid';
+    }
+    if (settings['ANALYTICS_OPT_IN']) {
+        url += '&analytics=1';
+    }
     url += '&version=' + package_json['version'];
 
     console.log(url);
@@ -65,6 +110,7 @@ Session.prototype.run = function() {
 Session.prototype.handleMessage = function(event) {
     var message = event.data;
     console.log(message);
+    this.note(message.substring(0, 200));
     if (message[0] == 'c') {
         var widgetRegex = /<<!!WIDGET:(.+?)!!>>/;
         var content = message.substring(1);
@@ -103,6 +149,8 @@ Session.prototype.handleMessage = function(event) {
         this.enqueue({
             FUNCTION: message.substring(1)
         });
+    } else if (message[0] == 'p') {
+        this.handleProgress(JSON.parse(message.substring(1)));
     } else if (message[0] == 'd') {
         this.hasOpenDialog = false;
         this.enqueue({
@@ -114,18 +162,62 @@ Session.prototype.handleMessage = function(event) {
         this.enqueue({
             THREAD_ID: message.substring(1)
         });
-    } else if (message[0] == 'w') {
+    } else if (message[0] == 'w' || message[0] == 'n') {
+        // The watch shows announcements the same way as warnings.
         this.enqueue({
             WARNING: message.substring(1)
         });
+    } else if (message[0] == 's') {
+        // The watch has nowhere to show sources; they're available from the transcript endpoint instead.
+        console.log("Response sources: " + message.substring(1));
+    } else if (message[0] == 'q') {
+        // AppMessages can't carry lists, so the suggestions go one to a line.
+        var suggestions = JSON.parse(message.substring(1)).suggestions;
+        if (suggestions && suggestions.length > 0) {
+            this.enqueue({
+                SUGGESTIONS: suggestions.join('\n')
+            });
+        }
+    }
+}
+
+Session.prototype.handleProgress = function(progress) {
+    console.log('Tool ' + progress.function + ' ' + progress.event);
+    // Starting a tool already produces a thought (sent as an 'f' message), and finishing or failing is followed by
+    // the model's response, so the only event worth surfacing is a retry - otherwise the watch looks frozen.
+    if (progress.event == 'retrying' && progress.detail) {
+        this.enqueue({
+            FUNCTION: progress.detail
+        });
     }
 }
 
 Session.prototype.processWidget = function(widgetData) {
+    this.lastWidget = widgetData;
     widgets.handleWidget(this, widgetData);
 }
 
+// The watch's inbox is 1024 bytes; leave room for the dictionary's own overhead.
+var MAX_CHAT_BYTES = 900;
+
+function utf8Length(s) {
+    return unescape(encodeURIComponent(s)).length;
+}
+
+function isChatOnly(message) {
+    return Object.keys(message).length == 1 && message.CHAT !== undefined;
+}
+
 Session.prototype.enqueue = function(message) {
+    // Each message costs a round trip over Bluetooth, so when we're already waiting on the watch, merge response text
+    // into the message waiting behind it rather than queueing another one.
+    var last = this.queue[this.queue.length - 1];
+    if (last && isChatOnly(last) && isChatOnly(message)
+            && utf8Length(last.CHAT) + utf8Length(message.CHAT) <= MAX_CHAT_BYTES) {
+        last.CHAT += message.CHAT;
+        console.log('merged into queued message, queue length: ' + this.queue.length);
+        return;
+    }
     this.queue.push(message);
     if (this.messagesInFlight < 10) {
         console.log('sending immediately, messages in flight: ' + this.messagesInFlight);
@@ -159,6 +251,22 @@ Session.prototype.dequeue = function() {
 
 Session.prototype.handleClose = function(event) {
     console.log("Connection closed. Code: " + event.code + ". Reason: \"" + event.reason + "\". Was clean: " + event.wasClean);
+    if (event.code != 1000) {
+        var lastWidget = null;
+        try {
+            lastWidget = this.lastWidget ? JSON.parse(this.lastWidget) : null;
+        } catch (e) {
+            this.note("Last widget wasn't valid JSON: " + e);
+        }
+        failures.save({
+            'thread_uuid': this.threadId || '',
+            'prompt': this.prompt,
+            'close_code': event.code,
+            'close_reason': event.reason,
+            'client_logs': this.log,
+            'last_widget': lastWidget
+        });
+    }
     this.enqueue({
         CLOSE_CODE: event.code,
         CLOSE_REASON: event.reason,
@@ -25,6 +25,11 @@ exports.QUERY_URL = 'wss://' + BOBBY_API_URI + '/query';
 exports.QUOTA_URL = 'https://' + BOBBY_API_URI + '/quota';
 exports.FEEDBACK_URL = 'https://' + BOBBY_API_URI + '/feedback';
 exports.REPORT_URL = 'https://' + BOBBY_API_URI + '/report';
+exports.ERROR_REPORT_URL = 'https://' + BOBBY_API_URI + '/error-report';
+exports.PREFERENCES_URL = 'https://' + BOBBY_API_URI + '/preferences';
+exports.QUICK_ACTIONS_URL = 'https://' + BOBBY_API_URI + '/quick-actions';
+exports.SCORE_URL = 'https://' + BOBBY_API_URI + '/score';
+exports.PAGE_URL = 'https://' + BOBBY_API_URI + '/page';
 
 var override = require('./urls_override');
 
@@ -40,3 +45,18 @@ if (override.FEEDBACK_URL) {
 if (override.REPORT_URL) {
     exports.REPORT_URL = override.REPORT_URL;
 }
+if (override.ERROR_REPORT_URL) {
+    exports.ERROR_REPORT_URL = override.ERROR_REPORT_URL;
+}
+if (override.PREFERENCES_URL) {
+    exports.PREFERENCES_URL = override.PREFERENCES_URL;
+}
+if (override.QUICK_ACTIONS_URL) {
+    exports.QUICK_ACTIONS_URL = override.QUICK_ACTIONS_URL;
+}
+if (override.SCORE_URL) {
+    exports.SCORE_URL = override.SCORE_URL;
+}
+if (override.PAGE_URL) {
+    exports.PAGE_URL = override.PAGE_URL;
+}
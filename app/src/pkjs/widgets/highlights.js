@@ -28,3 +28,11 @@ exports.number = function(session, params) {
     console.log(JSON.stringify(message));
     session.enqueue(message);
 }
+
+exports.pronunciation = function(session, params) {
+    session.enqueue({
+        PRONUNCIATION_WIDGET: 1,
+        PRONUNCIATION_WIDGET_WORD: params['word'],
+        PRONUNCIATION_WIDGET_RESPELLING: params['respelling'],
+    });
+}
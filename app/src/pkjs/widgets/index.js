@@ -17,13 +17,18 @@
 var weather = require('./weather');
 var timer = require('./timer');
 var highlights = require('./highlights');
+var sports = require('./sports');
+var pages = require('./pages');
 
 var widgetMap = {
     'timer': timer.timer,
     'number': highlights.number,
+    'pronunciation': highlights.pronunciation,
     'weather-single-day': weather.singleDay,
     'weather-current': weather.current,
-    'weather-multi-day': weather.multiDay
+    'weather-multi-day': weather.multiDay,
+    'sports': sports.sports,
+    'page': pages.page
 }
 
 exports.handleWidget = function(session, widgetString) {
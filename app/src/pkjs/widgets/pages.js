@@ -0,0 +1,34 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+// Builds the message for one page of a document. It's used both for the first page, which comes with the response,
+// and for the pages the watch asks for afterwards (see pages.js).
+exports.pageMessage = function(params) {
+    return {
+        PAGE_WIDGET: 1,
+        PAGE_WIDGET_DOCUMENT: params['document'],
+        PAGE_WIDGET_TITLE: params['title'] || '',
+        PAGE_WIDGET_PAGE_TITLE: params['page_title'] || '',
+        PAGE_WIDGET_TEXT: params['text'],
+        PAGE_WIDGET_PAGE: params['page'],
+        PAGE_WIDGET_PAGES: params['pages']
+    };
+}
+
+exports.page = function(session, params) {
+    session.enqueue(exports.pageMessage(params));
+}
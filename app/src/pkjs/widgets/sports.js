@@ -0,0 +1,56 @@
+/**
+ * Copyright 2025 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+// The watch deals in integers, so states are sent as numbers.
+var SPORTS_STATES = {
+    'scheduled': 0,
+    'live': 1,
+    'finished': 2,
+    'postponed': 3
+};
+
+exports.SPORTS_STATES = SPORTS_STATES;
+
+function toUnixTime(timestamp) {
+    return Math.round(new Date(timestamp).getTime() / 1000);
+}
+
+exports.sports = function(session, params) {
+    var message = {
+        SPORTS_WIDGET: 1,
+        SPORTS_WIDGET_LEAGUE: params['league'] || '',
+        SPORTS_WIDGET_HOME_TEAM: params['home_team'],
+        SPORTS_WIDGET_AWAY_TEAM: params['away_team'],
+        SPORTS_WIDGET_STATE: SPORTS_STATES[params['state']] || 0,
+        SPORTS_WIDGET_START_TIME: toUnixTime(params['start_time'])
+    };
+    // Scores are left out until the match starts.
+    if (params['home_score'] !== undefined && params['away_score'] !== undefined) {
+        message['SPORTS_WIDGET_HOME_SCORE'] = params['home_score'];
+        message['SPORTS_WIDGET_AWAY_SCORE'] = params['away_score'];
+    }
+    if (params['progress']) {
+        message['SPORTS_WIDGET_PROGRESS'] = params['progress'];
+    }
+    // With a refresh token, the watch asks for the latest score every so often (see scores.js) until it expires.
+    if (params['refresh_token']) {
+        message['SPORTS_WIDGET_REFRESH'] = params['refresh_token'];
+        message['SPORTS_WIDGET_REFRESH_UNTIL'] = toUnixTime(params['refresh_until']);
+    }
+    console.log(JSON.stringify(message));
+    session.enqueue(message);
+}
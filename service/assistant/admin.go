@@ -0,0 +1,439 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/audit"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/dates"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/feedback"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/flashcards"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/glossary"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/household"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pages"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pins"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/preferences"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/replay"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/roles"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// maxAdminBodySize bounds the size of a user sent to /admin/users.
+const maxAdminBodySize = 4 * 1024
+
+// maxAdminTokenLength is the longest bearer token the admin API will look up.
+const maxAdminTokenLength = 512
+
+const (
+	// adminTokenCacheTime is how long to remember who a token belongs to.
+	adminTokenCacheTime = 5 * time.Minute
+	// unknownAdminTokenCacheTime is how long to remember that a token belongs to nobody, so that someone retrying a
+	// bad token can't make us ask about it over and over. It's short, since the lookup may only have failed because
+	// the user-info service was down.
+	unknownAdminTokenCacheTime = time.Minute
+	// unknownAdminUser is cached for tokens that belong to nobody.
+	unknownAdminUser = -1
+)
+
+// adminRole returns the role of whoever made the request, from its "Authorization: Bearer <token>" header. The token
+// is either ADMIN_TOKEN, whose holder is always an owner, or a user's own token, in which case it's their role.
+func (s *Service) adminRole(r *http.Request) roles.Role {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return roles.User
+	}
+	if want := config.GetConfig().AdminToken; want != "" && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+		return roles.Owner
+	}
+	if !validBearerToken(token) {
+		return roles.User
+	}
+	userID, ok := s.adminUserID(r.Context(), token)
+	if !ok {
+		return roles.User
+	}
+	role, err := roles.Get(r.Context(), s.redis, userID)
+	if err != nil {
+		requestid.Logf(r.Context(), "Error getting role for user %d: %v", userID, err)
+		return roles.User
+	}
+	return role
+}
+
+// validBearerToken reports whether the token could be one at all: RFC 6750's token68, and no longer than
+// maxAdminTokenLength. Anything else is turned away without asking the user-info service about it.
+func validBearerToken(token string) bool {
+	if token == "" || len(token) > maxAdminTokenLength {
+		return false
+	}
+	body := strings.TrimRight(token, "=")
+	if body == "" {
+		return false
+	}
+	for _, c := range body {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', strings.ContainsRune("-._~+/", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func adminTokenKey(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return "admin:token:" + hex.EncodeToString(h[:])
+}
+
+// adminUserID returns the ID of the user the token belongs to, and whether it belongs to anyone. The answer is
+// remembered for a while, so that each call to the admin API doesn't mean another call to the user-info service.
+// Only who the token belongs to is remembered: their role is read afresh every time, so a demotion takes effect at
+// once.
+func (s *Service) adminUserID(ctx context.Context, token string) (int, bool) {
+	key := adminTokenKey(token)
+	if id, err := s.redis.Get(ctx, key).Int(); err == nil {
+		return id, id != unknownAdminUser
+	} else if !errors.Is(err, redis.Nil) {
+		requestid.Logf(ctx, "Error reading cached admin token: %v", err)
+	}
+	userInfo, err := quota.GetUserInfo(ctx, token)
+	if err != nil {
+		if err := s.redis.Set(ctx, key, unknownAdminUser, unknownAdminTokenCacheTime).Err(); err != nil {
+			requestid.Logf(ctx, "Error caching admin token: %v", err)
+		}
+		return 0, false
+	}
+	if err := s.redis.Set(ctx, key, userInfo.UserId, adminTokenCacheTime).Err(); err != nil {
+		requestid.Logf(ctx, "Error caching admin token: %v", err)
+	}
+	return userInfo.UserId, true
+}
+
+// authorize checks that whoever made the request has the permission, returning their role if they do, and writing an
+// error response if they don't.
+func (s *Service) authorize(rw http.ResponseWriter, r *http.Request, p roles.Permission) (roles.Role, bool) {
+	role := s.adminRole(r)
+	if role == roles.User {
+		// Don't admit the API exists to anyone who can't use it.
+		http.NotFound(rw, r)
+		return role, false
+	}
+	if !role.Can(p) {
+		http.Error(rw, "You don't have permission to do that.", http.StatusForbidden)
+		return role, false
+	}
+	return role, true
+}
+
+// handleAdminUsers serves the admin API for managing the users of a self-hosted household (see the household
+// package).
+//
+//	GET    /admin/users           returns every user. Only owners see their tokens.
+//	POST   /admin/users           adds the user in the JSON body, which needs a name and their watch's timeline token.
+//	DELETE /admin/users?id=...    removes a user, and everything stored for them.
+//
+// Listing users needs roles.ViewUsers, and changing them roles.ManageUsers.
+func (s *Service) handleAdminUsers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	switch r.Method {
+	case http.MethodGet:
+		role, ok := s.authorize(rw, r, roles.ViewUsers)
+		if !ok {
+			return
+		}
+		users, err := household.List(ctx, s.redis)
+		if err != nil {
+			requestid.Logf(ctx, "Error listing users: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// A token is all it takes to act as its user, so only those who could manage users anyway get to see them.
+		if !role.Can(roles.ManageUsers) {
+			for i := range users {
+				users[i].Token = ""
+			}
+		}
+		writeJSON(rw, map[string]any{"users": users})
+	case http.MethodPost:
+		if _, ok := s.authorize(rw, r, roles.ManageUsers); !ok {
+			return
+		}
+		var req struct {
+			Name  string `json:"name"`
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxAdminBodySize)).Decode(&req); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		user, err := household.Create(ctx, s.redis, req.Name, req.Token)
+		if err != nil {
+			switch {
+			case errors.Is(err, household.ErrInvalid):
+				http.Error(rw, "A user needs a name and a token.", http.StatusBadRequest)
+			case errors.Is(err, household.ErrTokenInUse):
+				http.Error(rw, err.Error(), http.StatusConflict)
+			default:
+				requestid.Logf(ctx, "Error creating user: %v", err)
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		writeJSON(rw, user)
+	case http.MethodDelete:
+		if _, ok := s.authorize(rw, r, roles.ManageUsers); !ok {
+			return
+		}
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(rw, "Invalid ID.", http.StatusBadRequest)
+			return
+		}
+		removed, err := household.Remove(ctx, s.redis, id)
+		if err != nil {
+			requestid.Logf(ctx, "Error removing user: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !removed {
+			http.Error(rw, "No such user.", http.StatusNotFound)
+			return
+		}
+		if err := s.deleteUserData(ctx, id); err != nil {
+			// The user is gone, so trying again won't find them; say so rather than pretending it worked.
+			requestid.Logf(ctx, "Error deleting data for user %d: %v", id, err)
+			http.Error(rw, "The user was removed, but some of their data couldn't be deleted: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+// deleteUserData deletes everything stored for a user, carrying on past failures so that as much as possible goes.
+func (s *Service) deleteUserData(ctx context.Context, userID int) error {
+	return errors.Join(
+		preferences.Store{Redis: s.redis}.Delete(ctx, userID),
+		persistence.DeleteHistory(ctx, s.redis, userID),
+		pins.DeleteAll(ctx, s.redis, userID),
+		glossary.DeleteAll(ctx, s.redis, userID),
+		flashcards.DeleteAll(ctx, s.redis, userID),
+		dates.DeleteAll(ctx, s.redis, userID),
+		pages.DeletePosition(ctx, s.redis, userID),
+		schedule.CancelAll(ctx, s.redis, userID),
+		audit.DeleteAll(ctx, s.redis, userID),
+		replay.DeleteAll(ctx, s.redis, userID),
+		feedback.DeleteErrorReports(ctx, s.redis, userID),
+		quota.NewTracker(s.redis, userID).Reset(ctx),
+		roles.Set(ctx, s.redis, userID, roles.User),
+	)
+}
+
+// handleAdminQuota lets moderators see and reset users' quota. It needs roles.ManageQuota.
+//
+//	GET    /admin/quota?user=...  returns how many credits the user has used this month, and how many they have left.
+//	DELETE /admin/quota?user=...  forgets the credits the user has used this month.
+func (s *Service) handleAdminQuota(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, ok := s.authorize(rw, r, roles.ManageQuota); !ok {
+		return
+	}
+	userID, err := strconv.Atoi(r.URL.Query().Get("user"))
+	if err != nil {
+		http.Error(rw, "Invalid user ID.", http.StatusBadRequest)
+		return
+	}
+	qt := quota.NewTracker(s.redis, userID)
+	switch r.Method {
+	case http.MethodGet:
+		used, remaining, err := qt.GetQuota(ctx)
+		if err != nil {
+			requestid.Logf(ctx, "Error getting quota: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(rw, map[string]any{"used": used, "remaining": remaining})
+	case http.MethodDelete:
+		if err := qt.Reset(ctx); err != nil {
+			requestid.Logf(ctx, "Error resetting quota: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, DELETE")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+// defaultAuditLimit is how many audit entries /admin/audit returns if it isn't told.
+const defaultAuditLimit = 50
+
+// handleAdminAudit lets moderators look into what Bobby did on a user's behalf. It needs roles.ViewAudit.
+//
+//	GET /admin/audit?user=...&limit=...  returns the user's most recent calls to functions with side effects, newest
+//	                                     first.
+func (s *Service) handleAdminAudit(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, ok := s.authorize(rw, r, roles.ViewAudit); !ok {
+		return
+	}
+	if r.Method != http.MethodGet {
+		rw.Header().Set("Allow", "GET")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, err := strconv.Atoi(r.URL.Query().Get("user"))
+	if err != nil {
+		http.Error(rw, "Invalid user ID.", http.StatusBadRequest)
+		return
+	}
+	limit := defaultAuditLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > audit.MaxEntriesPerUser {
+			http.Error(rw, "Invalid limit.", http.StatusBadRequest)
+			return
+		}
+	}
+	entries, err := audit.List(ctx, s.redis, userID, limit)
+	if err != nil {
+		requestid.Logf(ctx, "Error listing audit entries: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(rw, map[string]any{"entries": entries})
+}
+
+// handleAdminReplay hands out turns captured for replay, to be run again with cmd/replay. It needs
+// roles.ViewCaptures.
+//
+//	GET /admin/replay?user=...  returns the request IDs of the user's most recent captured turns, newest first.
+//	GET /admin/replay?id=...    returns the turn captured for the request.
+func (s *Service) handleAdminReplay(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, ok := s.authorize(rw, r, roles.ViewCaptures); !ok {
+		return
+	}
+	if r.Method != http.MethodGet {
+		rw.Header().Set("Allow", "GET")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	if id := r.URL.Query().Get("id"); id != "" {
+		b, err := replay.Load(ctx, s.redis, id)
+		if errors.Is(err, replay.ErrNotFound) {
+			http.Error(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			requestid.Logf(ctx, "Error loading capture: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(rw, b)
+		return
+	}
+	userID, err := strconv.Atoi(r.URL.Query().Get("user"))
+	if err != nil {
+		http.Error(rw, "Invalid user ID.", http.StatusBadRequest)
+		return
+	}
+	ids, err := replay.List(ctx, s.redis, userID)
+	if err != nil {
+		requestid.Logf(ctx, "Error listing captures: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(rw, map[string]any{"request_ids": ids})
+}
+
+// handleAdminRoles lets owners decide who else can use the admin API. It needs roles.ManageRoles.
+//
+//	GET  /admin/roles  returns every user who has a role other than "user".
+//	POST /admin/roles  gives the user in the JSON body a role, e.g. {"user_id": 1234, "role": "admin"}.
+func (s *Service) handleAdminRoles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, ok := s.authorize(rw, r, roles.ManageRoles); !ok {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		all, err := roles.List(ctx, s.redis)
+		if err != nil {
+			requestid.Logf(ctx, "Error listing roles: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(rw, map[string]any{"roles": all})
+	case http.MethodPost:
+		var req struct {
+			UserID int        `json:"user_id"`
+			Role   roles.Role `json:"role"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxAdminBodySize)).Decode(&req); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := roles.Set(ctx, s.redis, req.UserID, req.Role); err != nil {
+			if errors.Is(err, roles.ErrInvalid) {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requestid.Logf(ctx, "Error setting role: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, POST")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminReload reads the configuration again (see config.Reload). It needs roles.ReloadConfig.
+//
+//	POST /admin/reload
+func (s *Service) handleAdminReload(rw http.ResponseWriter, r *http.Request) {
+	if _, ok := s.authorize(rw, r, roles.ReloadConfig); !ok {
+		return
+	}
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", "POST")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	requestid.Logf(r.Context(), "Configuration reload requested.")
+	config.Reload()
+	rw.WriteHeader(http.StatusNoContent)
+}
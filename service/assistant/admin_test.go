@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/audit"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/glossary"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/replay"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/roles"
+)
+
+func TestDeleteUserData(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	s := &Service{redis: rd}
+	ctx := context.Background()
+	const userID, otherUserID = 42, 43
+
+	for _, id := range []int{userID, otherUserID} {
+		if err := audit.Record(ctx, rd, audit.Entry{Time: time.Now(), UserID: id, Function: "set_alarm", Outcome: audit.OutcomeOK}); err != nil {
+			t.Fatalf("audit.Record: %v", err)
+		}
+		if err := replay.Store(ctx, rd, &replay.Bundle{RequestID: fmt.Sprintf("req%d", id), UserID: id}, time.Hour); err != nil {
+			t.Fatalf("replay.Store: %v", err)
+		}
+		if err := glossary.Put(ctx, rd, id, glossary.Term{Term: "Pebble"}); err != nil {
+			t.Fatalf("glossary.Put: %v", err)
+		}
+	}
+	// Error reports can only be sent through the handler, which needs a real user, so this is what it leaves behind.
+	mr.SAdd("error-reports:user:42", "report")
+	mr.Set("reported-thread:report", "{}")
+
+	if err := s.deleteUserData(ctx, userID); err != nil {
+		t.Fatalf("deleteUserData: %v", err)
+	}
+	want := []string{"audit:43", "glossary:43", "replay:req43", "replay:user:43"}
+	if got := mr.Keys(); !slices.Equal(got, want) {
+		t.Errorf("after deleting user %d's data, the keys left are %q, want %q", userID, got, want)
+	}
+}
+
+func TestAdminRoleLooksUpTokensOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	s := &Service{redis: rd}
+	lookups := 0
+	userInfo := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.FormValue("token") != "admin-token" {
+			http.Error(rw, "no such user", http.StatusNotFound)
+			return
+		}
+		_, _ = rw.Write([]byte(`{"user_id": 7}`))
+	}))
+	defer userInfo.Close()
+	cfg := config.GetConfig()
+	oldURL, oldRedis := cfg.UserIdentificationURL, cfg.RedisURL
+	cfg.UserIdentificationURL, cfg.RedisURL = userInfo.URL, "redis://"+mr.Addr()
+	defer func() { cfg.UserIdentificationURL, cfg.RedisURL = oldURL, oldRedis }()
+	if err := roles.Set(context.Background(), rd, 7, roles.Admin); err != nil {
+		t.Fatal(err)
+	}
+
+	role := func(authorization string) roles.Role {
+		r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		r.Header.Set("Authorization", authorization)
+		return s.adminRole(r)
+	}
+	for _, authorization := range []string{"", "Bearer ", "Bearer a b", "Bearer tok\x00en", "Bearer ===", "Basic admin-token", "Bearer " + strings.Repeat("a", maxAdminTokenLength+1)} {
+		if got := role(authorization); got != roles.User {
+			t.Errorf("role for %q = %v, want %v", authorization, got, roles.User)
+		}
+	}
+	if lookups != 0 {
+		t.Errorf("malformed tokens made %d lookups, want none", lookups)
+	}
+
+	for range 3 {
+		if got := role("Bearer admin-token"); got != roles.Admin {
+			t.Errorf("role = %v, want %v", got, roles.Admin)
+		}
+		if got := role("Bearer stranger-token"); got != roles.User {
+			t.Errorf("role for an unknown token = %v, want %v", got, roles.User)
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("looking up two tokens three times each made %d lookups, want 2", lookups)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alerting notifies operators when something has gone wrong that needs a human to look at it.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+)
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// rank orders severities from least to most severe. Unknown severities are treated as warnings, so that a typo
+// neither wakes anyone up nor goes unseen.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 0
+	case SeverityCritical:
+		return 2
+	default:
+		return 1
+	}
+}
+
+type Alert struct {
+	Severity Severity
+	// What raised the alert, e.g. "canary".
+	Source  string
+	Summary string
+	Details string
+}
+
+// routes returns every configured alert destination. ALERT_WEBHOOK_URL predates routing, and gets everything.
+func routes() []config.AlertRoute {
+	cfg := config.GetConfig()
+	r := cfg.AlertRoutes
+	if cfg.AlertWebhookURL != "" {
+		r = append([]config.AlertRoute{{Kind: "chat", URL: cfg.AlertWebhookURL}}, r...)
+	}
+	return r
+}
+
+// Send notifies operators of the alert, at every destination routed alerts of its severity. If there are none, the
+// alert is only logged. Failing to reach one destination doesn't stop the alert being sent to the others.
+func Send(ctx context.Context, alert Alert) error {
+	ctx, span := beeline.StartSpan(ctx, "alerting.send")
+	defer span.Send()
+	span.AddField("severity", alert.Severity)
+	span.AddField("source", alert.Source)
+	log.Printf("ALERT [%s] %s: %s\n%s", alert.Severity, alert.Source, alert.Summary, alert.Details)
+	var errs []error
+	sent := 0
+	for _, route := range routes() {
+		if route.MinSeverity != "" && alert.Severity.rank() < Severity(route.MinSeverity).rank() {
+			continue
+		}
+		if err := sendTo(ctx, route, alert); err != nil {
+			errs = append(errs, fmt.Errorf("sending alert to %s failed: %w", route.Kind, err))
+			continue
+		}
+		sent++
+	}
+	span.AddField("destinations", sent)
+	err := errors.Join(errs...)
+	if err != nil {
+		span.AddField("error", err)
+	}
+	return err
+}
+
+func sendTo(ctx context.Context, route config.AlertRoute, alert Alert) error {
+	url, payload, err := payloadFor(route, alert)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("alert destination returned %s: %s", resp.Status, content)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// Discord rejects messages longer than this, and Slack truncates them anyway.
+const maxChatMessageLength = 2000
+
+// PagerDuty rejects summaries longer than this.
+const maxPagerDutySummaryLength = 1024
+
+// webhookPayload is what the "webhook" kind of destination receives.
+type webhookPayload struct {
+	Severity Severity `json:"severity"`
+	Source   string   `json:"source"`
+	Summary  string   `json:"summary"`
+	Details  string   `json:"details"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      Severity       `json:"severity"`
+	Component     string         `json:"component"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// payloadFor returns where to post the alert for the route, and what to post.
+func payloadFor(route config.AlertRoute, alert Alert) (string, any, error) {
+	switch route.Kind {
+	case "chat":
+		// This format is understood by both Discord and Slack incoming webhooks.
+		text := truncate(fmt.Sprintf("**[%s] %s**: %s\n%s", alert.Severity, alert.Source, alert.Summary, alert.Details), maxChatMessageLength)
+		return route.URL, map[string]string{"content": text, "text": text}, nil
+	case "discord":
+		text := truncate(fmt.Sprintf("**[%s] %s**: %s\n%s", alert.Severity, alert.Source, alert.Summary, alert.Details), maxChatMessageLength)
+		return route.URL, map[string]string{"content": text}, nil
+	case "slack":
+		text := truncate(fmt.Sprintf("*[%s] %s*: %s\n%s", alert.Severity, alert.Source, alert.Summary, alert.Details), maxChatMessageLength)
+		return route.URL, map[string]string{"text": text}, nil
+	case "webhook":
+		return route.URL, webhookPayload{
+			Severity: alert.Severity,
+			Source:   alert.Source,
+			Summary:  alert.Summary,
+			Details:  alert.Details,
+		}, nil
+	case "pagerduty":
+		if route.RoutingKey == "" {
+			return "", nil, fmt.Errorf("pagerduty route has no routing_key")
+		}
+		url := route.URL
+		if url == "" {
+			url = pagerDutyEventsURL
+		}
+		severity := alert.Severity
+		if severity.rank() == SeverityWarning.rank() {
+			severity = SeverityWarning
+		}
+		event := pagerDutyEvent{
+			RoutingKey:  route.RoutingKey,
+			EventAction: "trigger",
+			// Repeats of the same alert are folded into one incident rather than paging again.
+			DedupKey: alert.Source + ":" + alert.Summary,
+			Payload: pagerDutyPayload{
+				Summary:   truncate(fmt.Sprintf("[%s] %s", alert.Source, alert.Summary), maxPagerDutySummaryLength),
+				Source:    "bobby-assistant",
+				Severity:  severity,
+				Component: alert.Source,
+			},
+		}
+		if alert.Details != "" {
+			event.Payload.CustomDetails = map[string]any{"details": alert.Details}
+		}
+		return url, event, nil
+	}
+	return "", nil, fmt.Errorf("unknown alert destination kind %q", route.Kind)
+}
+
+// truncate shortens s to at most n bytes, without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n-len("…")]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s + "…"
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analytics records anonymous usage events for users who have opted in, so we can learn which features
+// matter. Events never contain message content, user IDs, locations or thread IDs; the only identifier is a random
+// per-session ID that can't be linked back to anything else we store.
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+type EventKind string
+
+const (
+	EventSessionStarted EventKind = "session_started"
+	EventTurn           EventKind = "turn"
+	EventToolUsed       EventKind = "tool_used"
+	EventWidgetShown    EventKind = "widget_shown"
+	// The model called a tool it doesn't have, or called one with another's arguments.
+	EventToolHallucinated EventKind = "tool_hallucinated"
+)
+
+type Event struct {
+	Time time.Time `json:"time"`
+	// A random identifier for the session, only used to group events.
+	Session  string    `json:"session"`
+	Kind     EventKind `json:"kind"`
+	Language string    `json:"language,omitempty"`
+	// The name of the tool, for EventToolUsed.
+	Tool string `json:"tool,omitempty"`
+	// The type of widget, for EventWidgetShown.
+	Widget string `json:"widget,omitempty"`
+	// What was wrong with the call, for EventToolHallucinated: "unknown_tool" or "wrong_tool".
+	Hallucination string `json:"hallucination,omitempty"`
+	LatencyMs     int64  `json:"latency_ms,omitempty"`
+	Success       bool   `json:"success"`
+}
+
+const (
+	// queueSize is how many events we'll hold before dropping them. Analytics should never slow down a session.
+	queueSize = 1000
+	// batchSize is how many events we'll send to the sink at once.
+	batchSize = 100
+	// flushInterval is the longest we'll hold on to events before sending them.
+	flushInterval = 10 * time.Second
+)
+
+type recorder struct {
+	sink   Sink
+	events chan Event
+	done   chan struct{}
+	// mu guards closed, so that nothing is sent on events once Close has closed it.
+	mu     sync.RWMutex
+	closed bool
+}
+
+var sharedRecorder *recorder
+var sharedRecorderOnce sync.Once
+
+func getRecorder() *recorder {
+	sharedRecorderOnce.Do(func() {
+		sink, err := NewSink(config.GetConfig().AnalyticsSink)
+		if err != nil {
+			log.Printf("Analytics disabled: %v", err)
+			return
+		}
+		if sink == nil {
+			return
+		}
+		sharedRecorder = &recorder{
+			sink:   sink,
+			events: make(chan Event, queueSize),
+			done:   make(chan struct{}),
+		}
+		go sharedRecorder.run()
+	})
+	return sharedRecorder
+}
+
+func (r *recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]Event, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := r.sink.Write(context.Background(), batch); err != nil {
+			log.Printf("Writing %d analytics events failed: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case e, ok := <-r.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+type sessionKey struct{}
+
+// WithSession returns a context carrying a new random analytics session ID.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, uuid.NewString())
+}
+
+// Record queues an event for the current session. It does nothing unless the user opted in and a sink is
+// configured, and it never blocks: if the queue is full, the event is dropped.
+func Record(ctx context.Context, e Event) {
+	if !query.AnalyticsOptInFromContext(ctx) {
+		return
+	}
+	r := getRecorder()
+	if r == nil {
+		return
+	}
+	session, _ := ctx.Value(sessionKey{}).(string)
+	if session == "" {
+		return
+	}
+	e.Session = session
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		// Sessions can outlive the server shutting down, but by then it's too late to send anything.
+		return
+	}
+	select {
+	case r.events <- e:
+	default:
+		log.Printf("Analytics queue full; dropping %s event.", e.Kind)
+	}
+}
+
+// Close flushes any queued events and closes the sink. Events recorded afterwards are dropped.
+func Close() {
+	r := getRecorder()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.events)
+	r.mu.Unlock()
+	<-r.done
+	if err := r.sink.Close(); err != nil {
+		log.Printf("Closing analytics sink failed: %v", err)
+	}
+}
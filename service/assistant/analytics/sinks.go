@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Sink is somewhere analytics events can be stored.
+type Sink interface {
+	Write(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// SinkFactory creates a sink from the part of the sink specification after the colon.
+type SinkFactory func(target string) (Sink, error)
+
+var sinkFactories = map[string]SinkFactory{
+	"file": newFileSink,
+	"log":  newLogSink,
+}
+
+// RegisterSinkType makes a new kind of sink available, e.g. for a database or data warehouse. It should be called
+// during init.
+func RegisterSinkType(name string, factory SinkFactory) {
+	sinkFactories[name] = factory
+}
+
+// NewSink creates a sink from a specification of the form "type:target", e.g. "file:/var/log/bobby/analytics.jsonl"
+// or "log". An empty specification disables analytics, returning a nil sink.
+func NewSink(spec string) (Sink, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	kind, target, _ := strings.Cut(spec, ":")
+	factory, ok := sinkFactories[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown analytics sink type %q", kind)
+	}
+	return factory(target)
+}
+
+// fileSink appends events to a file as JSON lines.
+type fileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func newFileSink(path string) (Sink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file analytics sink requires a path")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{f: f}, nil
+}
+
+func (s *fileSink) Write(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := json.NewEncoder(s.f)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	return s.f.Close()
+}
+
+// logSink writes events to the standard logger, which is mostly useful for development.
+type logSink struct{}
+
+func newLogSink(string) (Sink, error) {
+	return logSink{}, nil
+}
+
+func (logSink) Write(ctx context.Context, events []Event) error {
+	for _, e := range events {
+		j, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		log.Printf("analytics: %s", j)
+	}
+	return nil
+}
+
+func (logSink) Close() error {
+	return nil
+}
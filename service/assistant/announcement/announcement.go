@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package announcement reads the notice operators can set in Redis to tell users about planned downtime or provider
+// outages, and whether the service is in maintenance mode.
+//
+// To put the service into maintenance mode, or take it out again:
+//
+//	SET maintenance 1
+//	DEL maintenance
+//
+// To show users a message at the start of each conversation (and instead of an answer during maintenance):
+//
+//	SET announcement "Weather forecasts are unavailable until 18:00 UTC."
+//	DEL announcement
+//
+// Either can be given an expiry with EX, so that it doesn't outlive the problem if nobody remembers to remove it.
+package announcement
+
+import (
+	"context"
+	"errors"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	maintenanceKey  = "maintenance"
+	announcementKey = "announcement"
+)
+
+// Announcement is what operators currently want users to know.
+type Announcement struct {
+	// Whether the service is down for maintenance, in which case no queries should be answered.
+	Maintenance bool
+	// A one-line message to show users, or "" if there isn't one.
+	Message string
+}
+
+// Load returns the current announcement. If there's no announcement, the zero Announcement is returned.
+func Load(ctx context.Context, rd *redis.Client) (Announcement, error) {
+	ctx, span := beeline.StartSpan(ctx, "announcement.load")
+	defer span.Send()
+	values, err := rd.MGet(ctx, maintenanceKey, announcementKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.AddField("error", err)
+		return Announcement{}, err
+	}
+	var a Announcement
+	if len(values) == 2 {
+		flag, _ := values[0].(string)
+		a.Maintenance = flag != "" && flag != "0" && flag != "false"
+		a.Message, _ = values[1].(string)
+	}
+	span.AddField("maintenance", a.Maintenance)
+	span.AddField("has_announcement", a.Message != "")
+	return a, nil
+}
@@ -15,16 +15,25 @@
 package assistant
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/honeycombio/beeline-go/wrappers/hnynethttp"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/barcode"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/feedback"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/preferences"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// shutdownTimeout is how long to wait for requests in progress when the service is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
 type Service struct {
 	mux   *http.ServeMux
 	redis *redis.Client
@@ -38,8 +47,23 @@ func NewService(r *redis.Client) *Service {
 	s.mux.HandleFunc("/query", s.handleQuery)
 	s.mux.HandleFunc("/quota", s.handleQuota)
 	s.mux.HandleFunc("/heartbeat", s.handleHeartbeat)
+	s.mux.HandleFunc("/transcript", s.handleTranscript)
+	s.mux.HandleFunc("/quick-actions", s.handleQuickActions)
+	s.mux.HandleFunc("/score", s.handleScore)
+	s.mux.HandleFunc("/pins", s.handlePins)
+	s.mux.HandleFunc("/page", s.handlePage)
+	s.mux.HandleFunc("/glossary", s.handleGlossary)
+	s.mux.Handle("/scan", barcode.Handler{Redis: r})
+	s.mux.HandleFunc("/admin/users", s.handleAdminUsers)
+	s.mux.HandleFunc("/admin/quota", s.handleAdminQuota)
+	s.mux.HandleFunc("/admin/roles", s.handleAdminRoles)
+	s.mux.HandleFunc("/admin/reload", s.handleAdminReload)
+	s.mux.HandleFunc("/admin/audit", s.handleAdminAudit)
+	s.mux.HandleFunc("/admin/replay", s.handleAdminReplay)
+	s.mux.Handle("/preferences", preferences.Handler{Store: preferences.Store{Redis: r}})
 	s.mux.HandleFunc("/feedback", feedback.HandleFeedback)
 	s.mux.HandleFunc("/report", feedback.HandleReport)
+	s.mux.HandleFunc("/error-report", feedback.HandleErrorReport)
 	s.mux.HandleFunc("/reported-thread/", feedback.HandleShowReport)
 	s.mux.HandleFunc("/robots.txt", s.handleRobots)
 	return s
@@ -53,13 +77,13 @@ func (s *Service) handleQuota(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	token := r.URL.Query().Get("token")
 	if token == "" {
-		log.Printf("No token provided.")
+		requestid.Logf(ctx, "No token provided.")
 		http.Error(rw, "No token provided.", http.StatusNotFound)
 		return
 	}
 	userInfo, err := quota.GetUserInfo(ctx, token)
 	if err != nil {
-		log.Printf("Error getting user info: %v", err)
+		requestid.Logf(ctx, "Error getting user info: %v", err)
 		http.Error(rw, err.Error(), http.StatusNotFound)
 		return
 	}
@@ -70,7 +94,7 @@ func (s *Service) handleQuota(rw http.ResponseWriter, r *http.Request) {
 			"hasSubscription": false,
 		})
 		if err != nil {
-			log.Printf("Error marshalling quota response: %v", err)
+			requestid.Logf(ctx, "Error marshalling quota response: %v", err)
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -80,7 +104,7 @@ func (s *Service) handleQuota(rw http.ResponseWriter, r *http.Request) {
 	qt := quota.NewTracker(s.redis, userInfo.UserId)
 	used, remaining, err := qt.GetQuota(ctx)
 	if err != nil {
-		log.Printf("Error getting quota: %v", err)
+		requestid.Logf(ctx, "Error getting quota: %v", err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -90,7 +114,7 @@ func (s *Service) handleQuota(rw http.ResponseWriter, r *http.Request) {
 		"hasSubscription": true,
 	})
 	if err != nil {
-		log.Printf("Error marshalling quota response: %v", err)
+		requestid.Logf(ctx, "Error marshalling quota response: %v", err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -100,7 +124,7 @@ func (s *Service) handleQuota(rw http.ResponseWriter, r *http.Request) {
 func (s *Service) handleQuery(rw http.ResponseWriter, r *http.Request) {
 	session, err := NewPromptSession(s.redis, rw, r)
 	if err != nil {
-		log.Printf("Creating session failed: %v", err)
+		requestid.Logf(r.Context(), "Creating session failed: %v", err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -111,6 +135,26 @@ func (s *Service) handleRobots(rw http.ResponseWriter, r *http.Request) {
 	_, _ = rw.Write([]byte("User-agent: *\nDisallow: /\n"))
 }
 
-func (s *Service) ListenAndServe(addr string) error {
-	return http.ListenAndServe(addr, hnynethttp.WrapHandler(s.mux))
+// ListenAndServe serves requests on addr until ctx is cancelled, then stops accepting new connections and gives the
+// requests in progress, sessions included, up to shutdownTimeout to finish.
+func (s *Service) ListenAndServe(ctx context.Context, addr string) error {
+	server := &http.Server{Addr: addr, Handler: hnynethttp.WrapHandler(requestid.Middleware(s.mux))}
+	shutdown := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Waiting for requests to finish failed: %v", err)
+		}
+		if err := waitForSessions(shutdownCtx); err != nil {
+			log.Printf("Stopping with %d sessions still running: %v", sessionCount(), err)
+		}
+		close(shutdown)
+	}()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-shutdown
+	return nil
 }
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit keeps an append-only record of every call to a function that changes something outside the
+// conversation, like setting an alarm or a reminder, so that reports like "Bobby set an alarm I didn't ask for" can be
+// looked into. The arguments themselves aren't kept, only a hash of them, since they're often personal.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxEntriesPerUser is roughly how many entries are kept for each user. Older ones are dropped.
+const MaxEntriesPerUser = 1000
+
+// Outcomes of a call.
+const (
+	OutcomeOK = "ok"
+	// The function returned an error.
+	OutcomeError = "error"
+	// The function was simulated, because the session was in sandbox mode.
+	OutcomeSandboxed = "sandboxed"
+	// The function was still running when the model was told to carry on without it.
+	OutcomePending = "pending"
+)
+
+// Entry is the record of a single call.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	UserID   int       `json:"user_id"`
+	Function string    `json:"function"`
+	// See HashArgs.
+	ArgsHash string `json:"args_hash"`
+	Outcome  string `json:"outcome"`
+	// The error the function returned, if any.
+	Error     string `json:"error,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	// Whether the call was made while answering a scheduled query, rather than something the user asked just then.
+	Scheduled bool `json:"scheduled,omitempty"`
+}
+
+// HashArgs returns a hash of a call's arguments, given as a JSON object. Arguments that are the same apart from the
+// order of their fields hash the same, so that a user's report of what they asked for can be checked against it.
+func HashArgs(args string) string {
+	var decoded any
+	if err := json.Unmarshal([]byte(args), &decoded); err == nil {
+		if canonical, err := json.Marshal(decoded); err == nil {
+			args = string(canonical)
+		}
+	}
+	sum := sha256.Sum256([]byte(args))
+	return hex.EncodeToString(sum[:])
+}
+
+func auditKey(userID int) string {
+	return fmt.Sprintf("audit:%d", userID)
+}
+
+// Record appends an entry to the user's audit log.
+func Record(ctx context.Context, rd *redis.Client, e Entry) error {
+	ctx, span := beeline.StartSpan(ctx, "audit.record")
+	defer span.Send()
+	j, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	err = rd.XAdd(ctx, &redis.XAddArgs{
+		Stream: auditKey(e.UserID),
+		MaxLen: MaxEntriesPerUser,
+		Approx: true,
+		Values: map[string]any{"entry": j},
+	}).Err()
+	if err != nil {
+		span.AddField("error", err)
+	}
+	return err
+}
+
+// List returns up to limit of the user's most recent entries, newest first.
+func List(ctx context.Context, rd *redis.Client, userID int, limit int) ([]Entry, error) {
+	ctx, span := beeline.StartSpan(ctx, "audit.list")
+	defer span.Send()
+	messages, err := rd.XRevRangeN(ctx, auditKey(userID), "+", "-", int64(limit)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, m := range messages {
+		j, _ := m.Values["entry"].(string)
+		var e Entry
+		if err := json.Unmarshal([]byte(j), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// DeleteAll removes the user's whole audit log.
+func DeleteAll(ctx context.Context, rd *redis.Client, userID int) error {
+	return rd.Del(ctx, auditKey(userID)).Err()
+}
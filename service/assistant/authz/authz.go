@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authz decides which tools each user may use. The policy combines server-wide rules from the config with
+// per-user rules, which come from the user's stored preferences.
+package authz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+)
+
+// UserPolicy is a single user's tool settings.
+type UserPolicy struct {
+	// Tools the user has explicitly enabled. This is required for tools listed in the server's opt-in list.
+	Allow []string `json:"allow,omitempty"`
+	// Tools the user has disabled.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// PolicySource loads per-user policies.
+type PolicySource interface {
+	UserPolicy(ctx context.Context, userID int) (*UserPolicy, error)
+}
+
+// Decision is the resolved policy for a single session.
+type Decision struct {
+	server config.ToolPolicy
+	user   UserPolicy
+}
+
+// Permits reports whether the named tool may be offered to, and called by, the model.
+func (d *Decision) Permits(tool string) bool {
+	if d == nil {
+		return true
+	}
+	if slices.Contains(d.server.Deny, tool) || slices.Contains(d.user.Deny, tool) {
+		return false
+	}
+	if slices.Contains(d.server.OptIn, tool) && !slices.Contains(d.user.Allow, tool) {
+		return false
+	}
+	return true
+}
+
+// Load resolves the policy for the given user.
+func Load(ctx context.Context, source PolicySource, userID int) (*Decision, error) {
+	ctx, span := beeline.StartSpan(ctx, "authz.load")
+	defer span.Send()
+	d := &Decision{server: config.GetConfig().ToolPolicy}
+	user, err := source.UserPolicy(ctx, userID)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, fmt.Errorf("loading policy for user %d failed: %w", userID, err)
+	}
+	if user != nil {
+		d.user = *user
+	}
+	return d, nil
+}
+
+type decisionKey struct{}
+
+// WithDecision returns a context carrying the given decision.
+func WithDecision(ctx context.Context, d *Decision) context.Context {
+	return context.WithValue(ctx, decisionKey{}, d)
+}
+
+// DecisionFromContext returns the session's decision. If there isn't one, the result permits everything.
+func DecisionFromContext(ctx context.Context) *Decision {
+	d, _ := ctx.Value(decisionKey{}).(*Decision)
+	return d
+}
+
+// StaticSource returns the same policy for every user. It is useful when the policy has already been loaded.
+type StaticSource struct {
+	Policy *UserPolicy
+}
+
+func (s StaticSource) UserPolicy(ctx context.Context, userID int) (*UserPolicy, error) {
+	return s.Policy, nil
+}
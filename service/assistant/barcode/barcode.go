@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package barcode relays barcodes scanned with the phone's camera to the session that asked for them. The watch can't
+// scan anything itself, so the session asks the phone app to open a scanning page, which posts what it finds back
+// here for the waiting session to pick up.
+package barcode
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// ScanTimeout is how long a session waits for the user to scan something.
+const ScanTimeout = 90 * time.Second
+
+var (
+	ErrUnknownScan = errors.New("no such scan, or it has expired")
+	ErrTimedOut    = errors.New("nothing was scanned in time")
+	ErrCancelled   = errors.New("the user cancelled scanning")
+	ErrInvalid     = errors.New("that isn't a valid barcode")
+)
+
+func pendingKey(token string) string {
+	return "barcode_scan:" + token
+}
+
+func resultKey(token string) string {
+	return "barcode_scan_result:" + token
+}
+
+// NewScan starts a scan, returning the token the scanning page needs to post its result.
+func NewScan(ctx context.Context, rd *redis.Client) (string, error) {
+	token := uuid.NewString()
+	if err := rd.Set(ctx, pendingKey(token), "1", ScanTimeout).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Submit delivers the barcode for a scan. An empty barcode means the user gave up.
+func Submit(ctx context.Context, rd *redis.Client, token, code string) error {
+	ctx, span := beeline.StartSpan(ctx, "barcode.submit")
+	defer span.Send()
+	if code != "" && !Valid(code) {
+		return ErrInvalid
+	}
+	// Only the first result for a scan counts.
+	deleted, err := rd.Del(ctx, pendingKey(token)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	if deleted == 0 {
+		return ErrUnknownScan
+	}
+	pipe := rd.TxPipeline()
+	pipe.RPush(ctx, resultKey(token), code)
+	pipe.Expire(ctx, resultKey(token), ScanTimeout)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	return nil
+}
+
+// Wait blocks until the scan's barcode is submitted, or ScanTimeout passes.
+func Wait(ctx context.Context, rd *redis.Client, token string) (string, error) {
+	ctx, span := beeline.StartSpan(ctx, "barcode.wait")
+	defer span.Send()
+	result, err := rd.BLPop(ctx, ScanTimeout, resultKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrTimedOut
+		}
+		span.AddField("error", err)
+		return "", err
+	}
+	// BLPop returns the key followed by the value.
+	if result[1] == "" {
+		return "", ErrCancelled
+	}
+	return result[1], nil
+}
+
+// Valid reports whether code is a well-formed EAN-8, UPC-A, EAN-13 or GTIN-14 barcode, including its check digit.
+func Valid(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	sum := 0
+	for i := range len(code) {
+		c := code[len(code)-1-i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Counting from the check digit, every other digit is tripled.
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barcode
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+//go:embed scan.html
+var scanTemplateString string
+var scanTemplate = template.Must(template.New("scan").Parse(scanTemplateString))
+
+// Handler serves the scanning page the phone app opens:
+//
+//	GET  /scan?token=... shows the page.
+//	POST /scan?token=... submits the barcode in the "barcode" form field. An empty barcode cancels the scan.
+//
+// The token is all the authorization needed: it only lets whoever has it answer one scan.
+type Handler struct {
+	Redis *redis.Client
+}
+
+func (h Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(rw, "No token provided.", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := scanTemplate.Execute(rw, map[string]string{"Token": token}); err != nil {
+			requestid.Logf(ctx, "Error rendering scan page: %v", err)
+		}
+	case http.MethodPost:
+		err := Submit(ctx, h.Redis, token, r.PostFormValue("barcode"))
+		switch {
+		case err == nil:
+			rw.WriteHeader(http.StatusNoContent)
+		case errors.Is(err, ErrInvalid):
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrUnknownScan):
+			http.Error(rw, err.Error(), http.StatusNotFound)
+		default:
+			requestid.Logf(ctx, "Error submitting barcode: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+	default:
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package canary periodically holds scripted conversations with a live deployment, pretending to be a watch, and
+// raises an alert if any of them don't go as expected. This catches regressions in the model provider, the prompt, or
+// upstream APIs before users notice them.
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"nhooyr.io/websocket"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/alerting"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+)
+
+// scriptTimeout is the longest a single conversation may take.
+const scriptTimeout = 60 * time.Second
+
+// The canary pretends to be in London, so location-dependent scripts have somewhere to be.
+const (
+	canaryLat = 51.5072
+	canaryLon = -0.1276
+)
+
+// Result is the outcome of running a single script.
+type Result struct {
+	Script   string
+	Duration time.Duration
+	// Err is nil if the script passed.
+	Err error
+}
+
+// Start runs the canary suite every interval until the context is cancelled. It does nothing if the canary isn't
+// configured.
+func Start(ctx context.Context) {
+	c := config.GetConfig()
+	if c.CanaryURL == "" || c.CanaryToken == "" || c.CanaryInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(c.CanaryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				RunAndReport(ctx)
+			}
+		}
+	}()
+}
+
+// RunAndReport runs every script and sends a single alert summarising any failures.
+func RunAndReport(ctx context.Context) []Result {
+	ctx, span := beeline.StartSpan(ctx, "canary.run")
+	defer span.Send()
+	var results []Result
+	var failures []string
+	for _, script := range Scripts {
+		r := Run(ctx, script)
+		results = append(results, r)
+		if r.Err != nil {
+			log.Printf("Canary %q failed after %s: %v", r.Script, r.Duration, r.Err)
+			failures = append(failures, fmt.Sprintf("- %s: %v", r.Script, r.Err))
+		} else {
+			log.Printf("Canary %q passed in %s.", r.Script, r.Duration)
+		}
+	}
+	span.AddField("failures", len(failures))
+	if len(failures) > 0 {
+		err := alerting.Send(ctx, alerting.Alert{
+			Severity: alerting.SeverityCritical,
+			Source:   "canary",
+			Summary:  fmt.Sprintf("%d of %d canary conversations failed", len(failures), len(Scripts)),
+			Details:  strings.Join(failures, "\n"),
+		})
+		if err != nil {
+			log.Printf("Sending canary alert failed: %v", err)
+		}
+	}
+	return results
+}
+
+// Run holds a single scripted conversation and checks that it met the script's expectations.
+func Run(ctx context.Context, script Script) Result {
+	ctx, span := beeline.StartSpan(ctx, "canary.script")
+	defer span.Send()
+	span.AddField("script", script.Name)
+	start := time.Now()
+	err := run(ctx, script)
+	if err != nil {
+		span.AddField("error", err)
+	}
+	return Result{Script: script.Name, Duration: time.Since(start), Err: err}
+}
+
+// transcript is what we observed during a conversation.
+type transcript struct {
+	response strings.Builder
+	tools    []string
+	widgets  []string
+	warnings []string
+}
+
+var widgetRegex = regexp.MustCompile(`<<!!WIDGET:(.+?)!!>>`)
+
+func run(ctx context.Context, script Script) error {
+	ctx, cancel := context.WithTimeout(ctx, scriptTimeout)
+	defer cancel()
+	c := config.GetConfig()
+	params := url.Values{}
+	params.Set("prompt", script.Prompt)
+	params.Set("token", c.CanaryToken)
+	params.Set("tzOffset", "0")
+	params.Set("lat", fmt.Sprint(canaryLat))
+	params.Set("lon", fmt.Sprint(canaryLon))
+	params.Set("units", "metric")
+	params.Set("lang", "en_GB")
+	params.Set("actions", "set_alarm,get_alarm,delete_alarm")
+	params.Set("widgets", "weather,timer,number")
+	// The canary shouldn't be able to do anything real, even if the model goes off-script.
+	params.Set("sandbox", "1")
+	conn, _, err := websocket.Dial(ctx, strings.TrimSuffix(c.CanaryURL, "/")+"/query?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("connecting failed: %w", err)
+	}
+	defer conn.CloseNow()
+
+	var t transcript
+	for {
+		_, message, err := conn.Read(ctx)
+		if err != nil {
+			var closeErr websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Code == websocket.StatusNormalClosure {
+				break
+			}
+			return fmt.Errorf("conversation ended abnormally: %w", err)
+		}
+		if len(message) == 0 {
+			continue
+		}
+		if err := t.handle(ctx, conn, message); err != nil {
+			return err
+		}
+	}
+	return t.check(script)
+}
+
+func (t *transcript) handle(ctx context.Context, conn *websocket.Conn, message []byte) error {
+	content := string(message[1:])
+	switch message[0] {
+	case 'c':
+		for _, m := range widgetRegex.FindAllStringSubmatch(content, -1) {
+			var w struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal([]byte(m[1]), &w); err == nil {
+				t.widgets = append(t.widgets, w.Type)
+			}
+		}
+		t.response.WriteString(widgetRegex.ReplaceAllString(content, ""))
+	case 'p':
+		var p struct {
+			Event    string `json:"event"`
+			Function string `json:"function"`
+		}
+		if err := json.Unmarshal([]byte(content), &p); err == nil && p.Event == "started" {
+			t.tools = append(t.tools, p.Function)
+		}
+	case 'w':
+		t.warnings = append(t.warnings, content)
+	case 'n':
+		// Announcements are whatever operators have to say to users, and don't mean this run went wrong.
+	case 'a':
+		// We're pretending to be a watch, so we have to answer any action requests that weren't sandboxed (e.g.
+		// listing alarms). Pretend everything worked.
+		if err := conn.Write(ctx, websocket.MessageText, []byte(`{"status":"ok"}`)); err != nil {
+			return fmt.Errorf("responding to action failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *transcript) check(script Script) error {
+	var problems []string
+	for _, tool := range script.ExpectTools {
+		if !slices.Contains(t.tools, tool) {
+			problems = append(problems, fmt.Sprintf("expected a call to %s, but got %v", tool, t.tools))
+		}
+	}
+	sawWidget := script.ExpectWidget != "" && slices.Contains(t.widgets, script.ExpectWidget)
+	if script.ExpectResponse != nil && !sawWidget && !script.ExpectResponse.MatchString(t.response.String()) {
+		problems = append(problems, fmt.Sprintf("response %q didn't match %s", t.response.String(), script.ExpectResponse))
+	}
+	if len(t.warnings) > 0 {
+		problems = append(problems, fmt.Sprintf("got warnings: %v", t.warnings))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canary
+
+import "regexp"
+
+// Script is a single scripted conversation, and what we expect to happen during it.
+type Script struct {
+	Name   string
+	Prompt string
+	// Tools that must be called at some point during the conversation.
+	ExpectTools []string
+	// If set, the response text (excluding widgets) must match.
+	ExpectResponse *regexp.Regexp
+	// If set, a widget of this type must be shown. If the widget is shown, the response text isn't checked, since
+	// the model often says very little alongside a widget.
+	ExpectWidget string
+}
+
+// Scripts is the suite of conversations the canary runs. They should be cheap, uncontroversial, and exercise the
+// tools people use most.
+var Scripts = []Script{
+	{
+		Name:           "weather here",
+		Prompt:         "What's the weather like here right now?",
+		ExpectTools:    []string{"get_weather"},
+		ExpectResponse: regexp.MustCompile(`\d`),
+		ExpectWidget:   "weather-current",
+	},
+	{
+		Name:        "set a timer",
+		Prompt:      "Set a timer for five minutes.",
+		ExpectTools: []string{"set_timer"},
+	},
+	{
+		Name:           "who is Ada Lovelace",
+		Prompt:         "Who was Ada Lovelace?",
+		ExpectTools:    []string{"wikipedia"},
+		ExpectResponse: regexp.MustCompile(`(?i)mathematician|analytical engine|babbage`),
+	},
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/replay"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// startCapture starts capturing the turn for replay. Captured turns use a fixed seed, which is the one given with the
+// debugSeed query parameter if there is one, so that replaying them gives the model the best chance of doing the same
+// thing again.
+func (ps *PromptSession) startCapture(ctx context.Context, userID int) context.Context {
+	seed, err := strconv.ParseInt(ps.query.Get("debugSeed"), 10, 64)
+	if err != nil {
+		seed = rand.Int64N(1 << 31)
+	}
+	beeline.AddField(ctx, "debug_capture", true)
+	b := &replay.Bundle{
+		RequestID: requestid.FromContext(ctx),
+		UserID:    userID,
+		ThreadID:  ps.threadId.String(),
+		Captured:  time.Now().UTC(),
+		Prompt:    ps.prompt,
+		Model:     chatModel,
+		Seed:      seed,
+	}
+	return replay.WithBundle(ctx, b)
+}
+
+// storeCapture saves the turn captured by startCapture, however the turn ended.
+func (ps *PromptSession) storeCapture(ctx context.Context) {
+	b := replay.FromContext(ctx)
+	if b == nil {
+		return
+	}
+	// The session may have been cancelled, but the capture is most wanted when something went wrong.
+	ctx = context.WithoutCancel(ctx)
+	if err := replay.Store(ctx, ps.redis, b, config.GetConfig().DebugCaptureRetention); err != nil {
+		requestid.Logf(ctx, "store capture failed: %v\n", err)
+		return
+	}
+	requestid.Logf(ctx, "captured the turn for replay as %s\n", b.RequestID)
+}
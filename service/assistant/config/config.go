@@ -15,48 +15,301 @@
 package config
 
 import (
+	"encoding/json"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // TODO: something reasonable.
 
+// ToolPolicy is the server-wide part of the tool authorization policy.
+type ToolPolicy struct {
+	// Tools that are never offered to anyone.
+	Deny []string `json:"deny"`
+	// Tools that are only offered to users who have explicitly enabled them, e.g. tools that send messages.
+	OptIn []string `json:"opt_in"`
+}
+
+// AlertRoute sends operational alerts of at least some severity to one destination.
+type AlertRoute struct {
+	// The least severe alerts to send: "info", "warning" or "critical". Empty sends everything.
+	MinSeverity string `json:"min_severity"`
+	// The kind of destination: "webhook" (the alert as JSON), "slack", "discord" or "pagerduty".
+	Kind string `json:"kind"`
+	// Where to post alerts. Optional for PagerDuty, which defaults to its Events API.
+	URL string `json:"url"`
+	// The PagerDuty integration's routing key.
+	RoutingKey string `json:"routing_key"`
+}
+
 type Config struct {
 	BaseURL               string
-	GeminiKey             string
-	MapboxKey             string
+	GeminiKeys            []string
+	MapboxKeys            []string
 	ExchangeRateApiKey    string
 	RedisURL              string
 	UserIdentificationURL string
 	HoneycombKey          string
 	DiscordFeedbackURL    string
+	// Whether users may turn the content filter off entirely. Operators should only enable this where it is lawful
+	// to do so.
+	AllowUnfilteredContent bool
+	// Where to send anonymous analytics for users who opt in, e.g. "file:/var/log/bobby/analytics.jsonl". Empty
+	// disables analytics.
+	AnalyticsSink string
+	// A Discord or Slack compatible webhook to send operational alerts of every severity to.
+	AlertWebhookURL string
+	// Further destinations for operational alerts, each for alerts of a minimum severity.
+	AlertRoutes []AlertRoute
+	// The base websocket URL of the deployment the canary should talk to, e.g. "wss://bobby.example.com".
+	CanaryURL      string
+	CanaryToken    string
+	CanaryInterval time.Duration
+	ToolPolicy     ToolPolicy
+	// What to do about figures in answers that don't appear in any tool output: "off", "flag" to just record them,
+	// or "reprompt" to also have the model answer again, verifying or hedging them. Reprompting means answers given
+	// after a function call aren't shown until they've been checked.
+	GroundingCheck string
+	// How upstream providers can contact whoever runs this deployment, e.g. an email address or URL. It's included in
+	// the User-Agent of outgoing requests.
+	UpstreamContact string
+	// The most Mapbox requests to make in a calendar month. Once it's used up, geocoding relies on Photon alone.
+	MapboxMonthlyBudget int
+	// The size of the grid, in kilometres, that the user's location is snapped to before being sent to services that
+	// don't need it precisely, like weather. Zero sends it as is.
+	LocationPrecisionKm float64
+	// Feature flags enabled on this deployment, which make functions marked with them available.
+	FeatureFlags []string
+	// How long Gemini should keep the cached system prompt and tool declarations for. Zero disables prompt caching.
+	PromptCacheTTL time.Duration
+	// How long to keep error reports sent by the app.
+	ErrorReportRetention time.Duration
+	// A link to the traces for a request, with "{request_id}" standing in for the request ID, for error reports to
+	// link to.
+	TraceURL string
+	// The estimated size, in tokens, of the system prompt and tool declarations above which to log a warning. Zero
+	// disables the warning.
+	PromptTokenWarning int
+	// Whether to offer permessage-deflate compression to websocket clients. Clients that don't ask for it are
+	// unaffected.
+	WebsocketCompression bool
+	// The base URL of the timeline API that scheduled queries' answers are pinned with. Empty uses Rebble's.
+	TimelineURL string
+	// The TheSportsDB API key to look up fixtures and scores with. Empty uses the free public key, which is heavily
+	// rate limited.
+	SportsDBKey string
+	// How long a session can go without making any progress before it's cancelled. Zero never cancels idle sessions.
+	SessionIdleTimeout time.Duration
+	// The most memory, in megabytes, that the conversations of all the sessions in this process may take up before the
+	// largest are cancelled. Zero means no limit.
+	SessionMemoryLimitMB int
+	// The base URL of a llama.cpp server (or anything else with an OpenAI-compatible chat API) to answer with when
+	// Gemini can't be reached, e.g. "http://localhost:8080". Empty disables the fallback.
+	LocalModelURL string
+	// The model to ask the local server for, if it serves more than one.
+	LocalModelName string
+	// A bearer token that makes whoever holds it an owner in the admin API (see the roles package). Empty leaves the
+	// admin API to users who have been given a role.
+	AdminToken string
+	// How long to keep the turns captured for replay when a query asks for it with debug=true (see the replay
+	// package). Zero disables capturing.
+	DebugCaptureRetention time.Duration
+	// Where to find Open-Meteo, Photon and Wikipedia, e.g. cmd/mockapis when developing without the internet. Empty
+	// uses the real services.
+	OpenMeteoURL string
+	PhotonURL    string
+	WikipediaURL string
+	// The most function calls the model can make in one turn before it has to answer with what it has.
+	MaxToolCallsPerTurn int
+	// The most times the model can call any one function in a turn, which stops it retrying the same search forever.
+	MaxCallsPerFunction int
+	// The oldest app version whose sessions have the model write its answers as JSON rather than with widget tags
+	// (see widgets.Answer). Empty keeps everyone on widget tags.
+	WidgetJSONMinAppVersion string
+	// The oldest app version that's sent suggested follow-ups after each answer, for the user to pick from instead of
+	// dictating. Empty sends them to nobody.
+	SuggestionMinAppVersion string
 }
 
-var c Config
+var current atomic.Pointer[Config]
 
+// GetConfig returns the current configuration. It mustn't be modified, except by tests; Reload replaces it instead.
 func GetConfig() *Config {
-	return &c
+	return current.Load()
 }
 
+var (
+	envFileMu sync.Mutex
+	// envFileKeys are the variables that were set from .env rather than the real environment, which a reload may
+	// change.
+	envFileKeys = map[string]bool{}
+)
+
 func init() {
-	// Load .env file if it exists
-	if err := godotenv.Load(); err != nil {
+	loadEnvFile()
+	current.Store(load())
+}
+
+// Reload reads .env and the environment again, so that settings read as they're used, like the feature flags and the
+// tool policy, can be changed without a restart. Settings only read at startup, like the Gemini keys, still need one.
+func Reload() {
+	loadEnvFile()
+	current.Store(load())
+	log.Printf("Configuration reloaded.")
+}
+
+// loadEnvFile sets variables from .env, if it exists. Variables set in the real environment take precedence.
+func loadEnvFile() {
+	values, err := godotenv.Read()
+	if err != nil {
 		// Only log if the file exists but couldn't be loaded
 		if !os.IsNotExist(err) {
 			log.Printf("Error loading .env file: %v", err)
 		}
+		return
 	}
+	envFileMu.Lock()
+	defer envFileMu.Unlock()
+	for k, v := range values {
+		if _, set := os.LookupEnv(k); set && !envFileKeys[k] {
+			continue
+		}
+		_ = os.Setenv(k, v)
+		envFileKeys[k] = true
+	}
+}
 
-	c = Config{
-		BaseURL:               os.Getenv("BASE_URL"),
-		GeminiKey:             os.Getenv("GEMINI_KEY"),
-		MapboxKey:             os.Getenv("MAPBOX_KEY"),
-		ExchangeRateApiKey:    os.Getenv("EXCHANGE_RATE_API_KEY"),
-		RedisURL:              os.Getenv("REDIS_URL"),
-		UserIdentificationURL: os.Getenv("USER_IDENTIFICATION_URL"),
-		HoneycombKey:          os.Getenv("HONEYCOMB_KEY"),
-		DiscordFeedbackURL:    os.Getenv("DISCORD_FEEDBACK_URL"),
+func load() *Config {
+	return &Config{
+		BaseURL:                 os.Getenv("BASE_URL"),
+		GeminiKeys:              parseList(os.Getenv("GEMINI_KEY")),
+		MapboxKeys:              parseList(os.Getenv("MAPBOX_KEY")),
+		ExchangeRateApiKey:      os.Getenv("EXCHANGE_RATE_API_KEY"),
+		RedisURL:                os.Getenv("REDIS_URL"),
+		UserIdentificationURL:   os.Getenv("USER_IDENTIFICATION_URL"),
+		HoneycombKey:            os.Getenv("HONEYCOMB_KEY"),
+		DiscordFeedbackURL:      os.Getenv("DISCORD_FEEDBACK_URL"),
+		AllowUnfilteredContent:  os.Getenv("ALLOW_UNFILTERED_CONTENT") == "true",
+		AnalyticsSink:           os.Getenv("ANALYTICS_SINK"),
+		AlertWebhookURL:         os.Getenv("ALERT_WEBHOOK_URL"),
+		AlertRoutes:             parseAlertRoutes(os.Getenv("ALERT_ROUTES")),
+		ErrorReportRetention:    parseDuration("ERROR_REPORT_RETENTION", 30*24*time.Hour),
+		TraceURL:                os.Getenv("TRACE_URL"),
+		CanaryURL:               os.Getenv("CANARY_URL"),
+		CanaryToken:             os.Getenv("CANARY_TOKEN"),
+		CanaryInterval:          parseDuration("CANARY_INTERVAL", 24*time.Hour),
+		ToolPolicy:              parseToolPolicy(os.Getenv("TOOL_POLICY")),
+		GroundingCheck:          os.Getenv("GROUNDING_CHECK"),
+		UpstreamContact:         os.Getenv("UPSTREAM_CONTACT"),
+		MapboxMonthlyBudget:     parseInt("MAPBOX_MONTHLY_BUDGET", 90000),
+		LocationPrecisionKm:     parseFloat("LOCATION_PRECISION_KM", 1),
+		FeatureFlags:            parseList(os.Getenv("FEATURE_FLAGS")),
+		PromptCacheTTL:          parseDuration("PROMPT_CACHE_TTL", time.Hour),
+		PromptTokenWarning:      parseInt("PROMPT_TOKEN_WARNING", 8000),
+		WebsocketCompression:    os.Getenv("WEBSOCKET_COMPRESSION") == "true",
+		TimelineURL:             os.Getenv("TIMELINE_URL"),
+		SportsDBKey:             os.Getenv("SPORTS_DB_KEY"),
+		SessionIdleTimeout:      parseDuration("SESSION_IDLE_TIMEOUT", 2*time.Minute),
+		SessionMemoryLimitMB:    parseInt("SESSION_MEMORY_LIMIT_MB", 256),
+		LocalModelURL:           os.Getenv("LOCAL_MODEL_URL"),
+		LocalModelName:          os.Getenv("LOCAL_MODEL_NAME"),
+		AdminToken:              os.Getenv("ADMIN_TOKEN"),
+		DebugCaptureRetention:   parseDuration("DEBUG_CAPTURE_RETENTION", 0),
+		OpenMeteoURL:            os.Getenv("OPEN_METEO_URL"),
+		PhotonURL:               os.Getenv("PHOTON_URL"),
+		WikipediaURL:            os.Getenv("WIKIPEDIA_URL"),
+		MaxToolCallsPerTurn:     parseInt("MAX_TOOL_CALLS_PER_TURN", 10),
+		MaxCallsPerFunction:     parseInt("MAX_CALLS_PER_FUNCTION", 4),
+		WidgetJSONMinAppVersion: os.Getenv("WIDGET_JSON_MIN_APP_VERSION"),
+		SuggestionMinAppVersion: os.Getenv("SUGGESTION_MIN_APP_VERSION"),
+	}
+}
+
+// parseList splits a comma-separated list, ignoring empty entries and surrounding whitespace.
+func parseList(v string) []string {
+	var result []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// parseDuration reads a duration like "24h" from the named environment variable, using the fallback if it's unset
+// or invalid.
+func parseDuration(name string, fallback time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Invalid duration %q for %s, using %s: %v", v, name, fallback, err)
+		return fallback
+	}
+	return d
+}
+
+// parseInt reads an integer from the named environment variable, using the fallback if it's unset or invalid.
+func parseInt(name string, fallback int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Invalid integer %q for %s, using %d: %v", v, name, fallback, err)
+		return fallback
+	}
+	return i
+}
+
+// parseFloat reads a number from the named environment variable, using the fallback if it's unset or invalid.
+func parseFloat(name string, fallback float64) float64 {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("Invalid number %q for %s, using %g: %v", v, name, fallback, err)
+		return fallback
+	}
+	return f
+}
+
+// parseToolPolicy parses a JSON tool policy, e.g. {"deny": ["lua"], "opt_in": ["send_feedback"]}.
+func parseToolPolicy(v string) ToolPolicy {
+	var p ToolPolicy
+	if v == "" {
+		return p
+	}
+	if err := json.Unmarshal([]byte(v), &p); err != nil {
+		log.Printf("Invalid TOOL_POLICY %q, ignoring it: %v", v, err)
+		return ToolPolicy{}
+	}
+	return p
+}
+
+// parseAlertRoutes parses a JSON list of alert routes, e.g.
+// [{"kind": "pagerduty", "min_severity": "critical", "routing_key": "..."}].
+func parseAlertRoutes(v string) []AlertRoute {
+	if v == "" {
+		return nil
+	}
+	var routes []AlertRoute
+	if err := json.Unmarshal([]byte(v), &routes); err != nil {
+		// The routes contain secrets, so they aren't logged.
+		log.Printf("Invalid ALERT_ROUTES, ignoring it: %v", err)
+		return nil
 	}
+	return routes
 }
@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dates stores the dates each user has asked Bobby to keep track of, like when they quit smoking or their
+// wedding anniversary, so that they can ask how long it's been.
+package dates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxPerUser is the most dates a user can track.
+const MaxPerUser = 100
+
+// maxNameLength bounds the length of names and notes, in bytes.
+const maxNameLength = 100
+
+// Layout is how dates are written, both in storage and to the model.
+const Layout = "2006-01-02"
+
+var (
+	ErrTooMany = errors.New("too many tracked dates")
+	ErrInvalid = errors.New("invalid tracked date")
+)
+
+// Date is a named day a user is keeping track of.
+type Date struct {
+	Name string `json:"name"`
+	// The day itself, as YYYY-MM-DD.
+	Date string `json:"date"`
+	// Anything else worth remembering about it, e.g. "their tenth anniversary is tin".
+	Note    string    `json:"note,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Validate checks that the date is worth storing, and tidies it up.
+func (d *Date) Validate() error {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Note = strings.TrimSpace(d.Note)
+	if d.Name == "" || len(d.Name) > maxNameLength || len(d.Note) > maxNameLength {
+		return ErrInvalid
+	}
+	if _, err := d.Day(); err != nil {
+		return fmt.Errorf("%w: the date must be written as YYYY-MM-DD", ErrInvalid)
+	}
+	return nil
+}
+
+// Day returns the date as midnight UTC on that day.
+func (d *Date) Day() (time.Time, error) {
+	return time.Parse(Layout, d.Date)
+}
+
+func datesKey(userID int) string {
+	return fmt.Sprintf("dates:%d", userID)
+}
+
+// nameKey is what a date is stored under, so that changing its capitalisation replaces it.
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// List returns the user's tracked dates, oldest first.
+func List(ctx context.Context, rd *redis.Client, userID int) ([]Date, error) {
+	ctx, span := beeline.StartSpan(ctx, "dates.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, datesKey(userID)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	dates := []Date{}
+	for _, e := range entries {
+		var d Date
+		if err := json.Unmarshal([]byte(e), &d); err != nil {
+			continue
+		}
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		if dates[i].Date != dates[j].Date {
+			return dates[i].Date < dates[j].Date
+		}
+		return nameKey(dates[i].Name) < nameKey(dates[j].Name)
+	})
+	span.AddField("dates", len(dates))
+	return dates, nil
+}
+
+// Get returns the user's tracked date with the given name, ignoring case, or nil if there isn't one.
+func Get(ctx context.Context, rd *redis.Client, userID int, name string) (*Date, error) {
+	ctx, span := beeline.StartSpan(ctx, "dates.get")
+	defer span.Send()
+	data, err := rd.HGet(ctx, datesKey(userID), nameKey(name)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		span.AddField("error", err)
+		return nil, err
+	}
+	var d Date
+	if err := json.Unmarshal(data, &d); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Put starts tracking a date, replacing any the user already had with the same name.
+func Put(ctx context.Context, rd *redis.Client, userID int, d Date) error {
+	ctx, span := beeline.StartSpan(ctx, "dates.put")
+	defer span.Send()
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	key := datesKey(userID)
+	exists, err := rd.HExists(ctx, key, nameKey(d.Name)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	if !exists {
+		count, err := rd.HLen(ctx, key).Result()
+		if err != nil {
+			span.AddField("error", err)
+			return err
+		}
+		if count >= MaxPerUser {
+			return ErrTooMany
+		}
+	}
+	if d.Created.IsZero() {
+		d.Created = time.Now().UTC()
+	}
+	j, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return rd.HSet(ctx, key, nameKey(d.Name), j).Err()
+}
+
+// Remove stops tracking a date, returning false if it wasn't being tracked.
+func Remove(ctx context.Context, rd *redis.Client, userID int, name string) (bool, error) {
+	removed, err := rd.HDel(ctx, datesKey(userID), nameKey(name)).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
+
+// DeleteAll removes all the user's tracked dates.
+func DeleteAll(ctx context.Context, rd *redis.Client, userID int) error {
+	return rd.Del(ctx, datesKey(userID)).Err()
+}
+
+// Between returns how long it is from one day to a later one, in whole years, months and days. Anything shorter
+// than a day is ignored. A month runs from a day to the same day of the next month; where that day doesn't exist,
+// the month ends on the last day instead.
+func Between(from, to time.Time) (years, months, days int) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return 0, 0, 0
+	}
+	total := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if addMonths(from, total).After(to) {
+		total--
+	}
+	days = int(to.Sub(addMonths(from, total)).Hours() / 24)
+	return total / 12, total % 12, days
+}
+
+// NextAnniversary returns the first anniversary of the day that falls on or after today, and which anniversary it
+// is. Anniversaries of 29 February fall on 28 February in other years.
+func NextAnniversary(day, today time.Time) (time.Time, int) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	n := today.Year() - day.Year()
+	if n < 1 {
+		n = 1
+	}
+	next := addMonths(day, n*12)
+	if next.Before(today) {
+		n++
+		next = addMonths(day, n*12)
+	}
+	return next, n
+}
+
+// addMonths is t.AddDate(0, n, 0), except that it stops at the end of the month rather than spilling into the next.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
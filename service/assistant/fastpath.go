@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+	"nhooyr.io/websocket"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/analytics"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/functions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/intent"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/keys"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// fastPathFlag is the feature flag (see config.FeatureFlags) that enables answering simple prompts without the model.
+const fastPathFlag = "fast_path"
+
+// fastPathResult is what the fast path did in place of the model.
+type fastPathResult struct {
+	// The messages to add to the conversation, as if the model had made any function call and given the reply itself,
+	// so that follow-ups in the same thread make sense to it.
+	messages []*genai.Content
+	reply    string
+}
+
+// tryFastPath handles the prompt without the model if it's a simple, unambiguous command. It returns false if the
+// prompt should go to the model as usual, including when the command was recognized but couldn't be carried out:
+// the model is better at explaining what went wrong.
+func (ps *PromptSession) tryFastPath(ctx context.Context, qt *quota.Tracker, quotaUsed int) (fastPathResult, bool) {
+	if ps.quickAction != "" {
+		return ps.tryQuickAction(ctx)
+	}
+	in := intent.Classify(i18n.LanguageFromContext(ctx), ps.prompt)
+	if in.Kind == intent.None {
+		return fastPathResult{}, false
+	}
+	// Questions about whether Bobby is working are always answered here, since the model can only guess.
+	if in.Kind != intent.ServiceStatus && !slices.Contains(config.GetConfig().FeatureFlags, fastPathFlag) {
+		return fastPathResult{}, false
+	}
+	ctx, span := beeline.StartSpan(ctx, "fast_path")
+	defer span.Send()
+	span.AddField("intent", string(in.Kind))
+
+	switch in.Kind {
+	case intent.ServiceStatus:
+		reply := serviceStatusReply(ctx, quotaUsed)
+		return fastPathResult{
+			messages: []*genai.Content{{Parts: []*genai.Part{{Text: reply}}, Role: "model"}},
+			reply:    reply,
+		}, true
+	case intent.CurrentTime:
+		now := time.Now().In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60))
+		reply := i18n.T(ctx, "fast_path.time", now.Format(i18n.T(ctx, "format.time")))
+		return fastPathResult{
+			messages: []*genai.Content{{Parts: []*genai.Part{{Text: reply}}, Role: "model"}},
+			reply:    reply,
+		}, true
+	case intent.SetTimer:
+		args := map[string]any{"duration_seconds": in.Seconds}
+		result, ok := ps.fastPathAction(ctx, qt, "set_timer", args)
+		if !ok {
+			return fastPathResult{}, false
+		}
+		reply := i18n.T(ctx, "fast_path.timer", functions.FormatDuration(ctx, in.Seconds))
+		return fastPathResult{
+			messages: []*genai.Content{
+				{Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{Name: "set_timer", Args: args}}}, Role: "model"},
+				{Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{Name: "set_timer", Response: result}}}, Role: "function"},
+				{Parts: []*genai.Part{{Text: reply}}, Role: "model"},
+			},
+			reply: reply,
+		}, true
+	}
+	return fastPathResult{}, false
+}
+
+// fastPathAction calls an action the same way the model would, with the same progress messages to the client,
+// returning its result and whether it succeeded.
+func (ps *PromptSession) fastPathAction(ctx context.Context, qt *quota.Tracker, fn string, args map[string]any) (map[string]any, bool) {
+	argBytes, _ := json.Marshal(args)
+	fnArgs := string(argBytes)
+	if err := ps.conn.Write(ctx, websocket.MessageText, []byte("f"+functions.SummariseFunction(ctx, fn, fnArgs))); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+		return nil, false
+	}
+	fnCtx := functions.WithProgressReporter(ctx, func(event functions.ProgressEvent, detail string) {
+		ps.sendProgress(ctx, fn, event, detail)
+	})
+	functions.ReportProgress(fnCtx, functions.ProgressStarted, "")
+	callStart := time.Now()
+	result, err := functions.CallAction(fnCtx, qt, fn, fnArgs, ps.conn)
+	var mapResult map[string]any
+	if err == nil {
+		err = json.Unmarshal([]byte(result), &mapResult)
+	}
+	_, failed := mapResult["error"]
+	failed = failed || err != nil
+	if failed {
+		functions.ReportProgress(fnCtx, functions.ProgressFailed, "")
+	} else {
+		functions.ReportProgress(fnCtx, functions.ProgressFinished, "")
+	}
+	analytics.Record(ctx, analytics.Event{
+		Kind:      analytics.EventToolUsed,
+		Tool:      fn,
+		LatencyMs: time.Since(callStart).Milliseconds(),
+		Success:   !failed,
+	})
+	if failed {
+		beeline.AddField(ctx, "fast_path_failed", true)
+		requestid.Logf(ctx, "fast path %s failed, falling back to the model: %v %s\n", fn, err, result)
+		return nil, false
+	}
+	return mapResult, true
+}
+
+// isStatusQuestion reports whether the prompt is asking whether Bobby is working, which is worth answering even for
+// users who have run out of quota.
+func (ps *PromptSession) isStatusQuestion(ctx context.Context) bool {
+	return intent.Classify(i18n.LanguageFromContext(ctx), ps.prompt).Kind == intent.ServiceStatus
+}
+
+// serviceStatusReply describes how Bobby is doing from the service's own point of view: whether it can reach Gemini,
+// and how much of the user's quota is left.
+func serviceStatusReply(ctx context.Context, quotaUsed int) string {
+	healthy := keys.Gemini.Healthy()
+	beeline.AddField(ctx, "gemini_healthy", healthy)
+	reply := i18n.T(ctx, "fast_path.status.ok")
+	if !healthy {
+		reply = i18n.T(ctx, "fast_path.status.degraded")
+	}
+	if quotaUsed >= quota.MonthlyQuotaCredits {
+		return reply + " " + i18n.T(ctx, "fast_path.status.quota_exceeded")
+	}
+	return reply + " " + i18n.T(ctx, "fast_path.status.quota", quotaUsed*100/quota.MonthlyQuotaCredits)
+}
@@ -21,11 +21,11 @@ import (
 	"fmt"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"runtime/debug"
@@ -84,13 +84,13 @@ func sendToDiscord(ctx context.Context, title, message string, metadata feedback
 		AvatarUrl: "https://assets2.rebble.io/144x144/67c3afe9d2acb30009a3c7cd",
 	}
 	if err != nil {
-		log.Printf("Error getting user info: %v", err)
+		requestid.Logf(ctx, "Error getting user info: %v", err)
 		return fmt.Errorf("Error getting user info: %w", err)
 	}
 
 	marshalled, err := json.Marshal(embed)
 	if err != nil {
-		log.Printf("Error marshalling feedback: %v", err)
+		requestid.Logf(ctx, "Error marshalling feedback: %v", err)
 		return fmt.Errorf("Error marshalling feedback: %w", err)
 	}
 	reader := bytes.NewReader(marshalled)
@@ -99,13 +99,13 @@ func sendToDiscord(ctx context.Context, title, message string, metadata feedback
 	url := config.GetConfig().DiscordFeedbackURL
 	result, err := http.Post(url, "application/json", reader)
 	if err != nil {
-		log.Printf("Error sending feedback: %v", err)
+		requestid.Logf(ctx, "Error sending feedback: %v", err)
 		return fmt.Errorf("Error sending feedback: %w", err)
 	}
 	defer result.Body.Close()
 	if result.StatusCode < 200 || result.StatusCode >= 300 {
 		content, _ := io.ReadAll(result.Body)
-		log.Printf("Error sending feedback: %s\n%s", result.Status, string(content))
+		requestid.Logf(ctx, "Error sending feedback: %s\n%s", result.Status, string(content))
 		return fmt.Errorf("error sending feedback: %s (%s)", string(content), result.Status)
 	}
 	return nil
@@ -0,0 +1,223 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feedback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// Limits on what a failure bundle may contain, so that reports stay small enough to keep around.
+const (
+	maxErrorReportBytes  = 256 * 1024
+	maxPromptSize        = 2000
+	maxClientLogLines    = 200
+	maxClientLogLineSize = 500
+	maxWidgetBytes       = 16 * 1024
+	// The most error reports a user can send in a day.
+	maxDailyErrorReports = 20
+)
+
+// FailureBundle is what the phone app knows about a request that failed, attached to a reported thread.
+type FailureBundle struct {
+	// The prompt that failed, so that the request can be replayed.
+	Prompt string `json:"prompt,omitempty"`
+	// The ID of the failed request, which the server includes in the error it sends back (see requestid.Reference).
+	RequestID   string   `json:"request_id,omitempty"`
+	CloseCode   int      `json:"close_code,omitempty"`
+	CloseReason string   `json:"close_reason,omitempty"`
+	ClientLogs  []string `json:"client_logs,omitempty"`
+	// The last widget the app received, indented for reading.
+	LastWidget string `json:"last_widget,omitempty"`
+	// Where to find the request's traces, if TRACE_URL is set.
+	TraceURL string `json:"trace_url,omitempty"`
+}
+
+type errorReportRequest struct {
+	feedbackMetadata
+	ThreadUUID  string          `json:"thread_uuid"`
+	Prompt      string          `json:"prompt"`
+	CloseCode   int             `json:"close_code"`
+	CloseReason string          `json:"close_reason"`
+	ClientLogs  []string        `json:"client_logs"`
+	LastWidget  json.RawMessage `json:"last_widget"`
+}
+
+var requestReferenceRegex = regexp.MustCompile(`\[ref ([A-Za-z0-9._-]+)\]`)
+
+// HandleErrorReport accepts a failure bundle from the phone app, and stores it alongside the thread it failed in for
+// ERROR_REPORT_RETENTION, so that it can be replayed when triaging.
+func HandleErrorReport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req errorReportRequest
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxErrorReportBytes)).Decode(&req); err != nil {
+		requestid.Logf(ctx, "Error decoding error report: %v", err)
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userInfo, err := quota.GetUserInfo(ctx, req.AuthToken)
+	if err != nil {
+		requestid.Logf(ctx, "Error getting user info: %v", err)
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	rd := storage.GetRedis()
+	if allowed, err := allowErrorReport(ctx, rd, userInfo.UserId); err != nil {
+		requestid.Logf(ctx, "Error checking error report limit: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !allowed {
+		http.Error(rw, "Too many error reports today.", http.StatusTooManyRequests)
+		return
+	}
+
+	var messages []persistence.SerializedMessage
+	if req.ThreadUUID != "" {
+		// Threads don't last long, so it's normal for this to fail; the rest of the bundle is still useful.
+		messages, err = persistence.LoadThread(ctx, rd, req.ThreadUUID)
+		if err != nil {
+			requestid.Logf(ctx, "Couldn't load thread for error report: %v", err)
+		}
+	}
+
+	report := ReportedThread{
+		OriginalThreadID: req.ThreadUUID,
+		ReportTime:       time.Now(),
+		ThreadContent:    messages,
+		Failure:          newFailureBundle(req),
+	}
+	reportId, err := storeErrorReport(ctx, rd, userInfo.UserId, report, config.GetConfig().ErrorReportRetention)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summary := fmt.Sprintf("The app reported a failed request: %s/reported-thread/%s", config.GetConfig().BaseURL, reportId)
+	if report.Failure.CloseReason != "" {
+		summary = fmt.Sprintf("%s\n\n%s", report.Failure.CloseReason, summary)
+	}
+	if err := sendToDiscord(ctx, "Error Report Received", summary, req.feedbackMetadata); err != nil {
+		// The report is stored, which is what matters.
+		requestid.Logf(ctx, "Error sending error report to Discord: %v", err)
+	}
+}
+
+// newFailureBundle trims what the app sent down to the limits.
+func newFailureBundle(req errorReportRequest) *FailureBundle {
+	bundle := &FailureBundle{
+		Prompt:      req.Prompt,
+		CloseCode:   req.CloseCode,
+		CloseReason: req.CloseReason,
+	}
+	bundle.Prompt = truncate(bundle.Prompt, maxPromptSize)
+	if m := requestReferenceRegex.FindStringSubmatch(req.CloseReason); m != nil {
+		bundle.RequestID = m[1]
+		if traceURL := config.GetConfig().TraceURL; traceURL != "" {
+			bundle.TraceURL = strings.ReplaceAll(traceURL, "{request_id}", m[1])
+		}
+	}
+	logs := req.ClientLogs
+	if len(logs) > maxClientLogLines {
+		logs = logs[len(logs)-maxClientLogLines:]
+	}
+	for _, line := range logs {
+		bundle.ClientLogs = append(bundle.ClientLogs, truncate(line, maxClientLogLineSize))
+	}
+	if len(req.LastWidget) > 0 && len(req.LastWidget) <= maxWidgetBytes {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, req.LastWidget, "", "  "); err == nil {
+			bundle.LastWidget = indented.String()
+		}
+	}
+	return bundle
+}
+
+// truncate cuts s down to n bytes with an ellipsis after them, backing off so as not to split a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+func errorReportsKey(userId int) string {
+	return fmt.Sprintf("error-reports:user:%d", userId)
+}
+
+// storeErrorReport stores the report like storeReport, and remembers that it's the user's so that DeleteErrorReports
+// can find it.
+func storeErrorReport(ctx context.Context, rd *redis.Client, userId int, report ReportedThread, retention time.Duration) (string, error) {
+	reportId, err := storeReport(ctx, rd, report, retention)
+	if err != nil {
+		return "", err
+	}
+	key := errorReportsKey(userId)
+	pipe := rd.TxPipeline()
+	pipe.SAdd(ctx, key, reportId)
+	if retention > 0 {
+		// The set lasts as long as the newest report in it.
+		pipe.Expire(ctx, key, retention)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		requestid.Logf(ctx, "Error indexing error report: %v", err)
+	}
+	return reportId, nil
+}
+
+// DeleteErrorReports removes every error report the user has sent.
+func DeleteErrorReports(ctx context.Context, rd *redis.Client, userId int) error {
+	ids, err := rd.SMembers(ctx, errorReportsKey(userId)).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{errorReportsKey(userId)}
+	for _, id := range ids {
+		keys = append(keys, "reported-thread:"+id)
+	}
+	return rd.Del(ctx, keys...).Err()
+}
+
+// allowErrorReport counts an error report against the user's daily limit, and reports whether they're still within it.
+func allowErrorReport(ctx context.Context, rd *redis.Client, userId int) (bool, error) {
+	key := fmt.Sprintf("error-reports:%s:%d", time.Now().UTC().Format("060102"), userId)
+	count, err := rd.Incr(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if count == 1 {
+		rd.Expire(ctx, key, 24*time.Hour)
+	}
+	return count <= maxDailyErrorReports, nil
+}
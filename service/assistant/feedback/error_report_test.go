@@ -0,0 +1,40 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feedback
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNewFailureBundleTruncatesRunes(t *testing.T) {
+	// Every character here is three bytes, so neither limit falls on a character boundary.
+	prompt := strings.Repeat("天", maxPromptSize)
+	line := strings.Repeat("気", maxClientLogLineSize)
+	bundle := newFailureBundle(errorReportRequest{Prompt: prompt, ClientLogs: []string{line, "short"}})
+	if !utf8.ValidString(bundle.Prompt) || !strings.HasSuffix(bundle.Prompt, "…") || len(bundle.Prompt) > maxPromptSize+len("…") {
+		t.Errorf("prompt was truncated to %q", bundle.Prompt)
+	}
+	if len(bundle.ClientLogs) != 2 {
+		t.Fatalf("got %d client log lines, want 2", len(bundle.ClientLogs))
+	}
+	if got := bundle.ClientLogs[0]; !utf8.ValidString(got) || !strings.HasSuffix(got, "…") || len(got) > maxClientLogLineSize+len("…") {
+		t.Errorf("log line was truncated to %q", got)
+	}
+	if got := bundle.ClientLogs[1]; got != "short" {
+		t.Errorf("short log line became %q", got)
+	}
+}
@@ -21,9 +21,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
 	"github.com/redis/go-redis/v9"
-	"log"
 	"net/http"
 	"time"
 )
@@ -44,6 +44,8 @@ type ReportedThread struct {
 	ReportTime       time.Time                       `json:"report_time"`
 	ReportText       string                          `json:"report_text"`
 	ThreadContent    []persistence.SerializedMessage `json:"thread_content"`
+	// What the app sent about the failure, if this was an error report rather than a user's report.
+	Failure *FailureBundle `json:"failure,omitempty"`
 }
 
 func HandleFeedback(rw http.ResponseWriter, r *http.Request) {
@@ -51,13 +53,13 @@ func HandleFeedback(rw http.ResponseWriter, r *http.Request) {
 
 	var req feedbackRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Error decoding feedback request: %v", err)
+		requestid.Logf(ctx, "Error decoding feedback request: %v", err)
 		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	if err := sendToDiscord(ctx, "Feedback Received", req.Text, req.feedbackMetadata); err != nil {
-		log.Printf("Error sending feedback to Discord: %v", err)
+		requestid.Logf(ctx, "Error sending feedback to Discord: %v", err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -68,7 +70,7 @@ func HandleReport(rw http.ResponseWriter, r *http.Request) {
 
 	var req reportRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Error decoding report request: %v", err)
+		requestid.Logf(ctx, "Error decoding report request: %v", err)
 		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -79,7 +81,7 @@ func HandleReport(rw http.ResponseWriter, r *http.Request) {
 		var err error
 		messages, err = persistence.LoadThread(ctx, rd, req.ThreadUUID)
 		if err != nil {
-			log.Printf("Error loading thread: %v", err)
+			requestid.Logf(ctx, "Error loading thread: %v", err)
 			http.Error(rw, err.Error(), http.StatusGone)
 			return
 		}
@@ -92,7 +94,7 @@ func HandleReport(rw http.ResponseWriter, r *http.Request) {
 		ReportText:       req.Text,
 	}
 
-	reportId, err := storeReport(ctx, rd, report)
+	reportId, err := storeReport(ctx, rd, report, 0)
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
@@ -105,21 +107,22 @@ func HandleReport(rw http.ResponseWriter, r *http.Request) {
 		dm = fmt.Sprintf("%s\n\n%s/reported-thread/%s", req.Text, config.GetConfig().BaseURL, reportId)
 	}
 	if err := sendToDiscord(ctx, "Report Received", dm, req.feedbackMetadata); err != nil {
-		log.Printf("Error sending report to Discord: %v", err)
+		requestid.Logf(ctx, "Error sending report to Discord: %v", err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
-func storeReport(ctx context.Context, rd *redis.Client, report ReportedThread) (string, error) {
+// storeReport stores the report for the given time, or forever if it's zero, returning its ID.
+func storeReport(ctx context.Context, rd *redis.Client, report ReportedThread, retention time.Duration) (string, error) {
 	reportId := uuid.New()
 
 	j, err := json.Marshal(report)
 	if err != nil {
-		log.Printf("Error marshalling report: %v", err)
+		requestid.Logf(ctx, "Error marshalling report: %v", err)
 		return "", fmt.Errorf("Error marshalling report: %w", err)
 	}
-	rd.Set(ctx, "reported-thread:"+reportId.String(), j, 0)
+	rd.Set(ctx, "reported-thread:"+reportId.String(), j, retention)
 	return reportId.String(), nil
 }
 
@@ -16,9 +16,9 @@ package feedback
 
 import (
 	_ "embed"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
 	"html/template"
-	"log"
 	"net/http"
 	"path"
 )
@@ -35,7 +35,7 @@ func HandleShowReport(rw http.ResponseWriter, r *http.Request) {
 
 	reportedThread, err := loadReport(ctx, rd, reportId)
 	if err != nil {
-		log.Printf("Error getting reported thread: %v", err)
+		requestid.Logf(ctx, "Error getting reported thread: %v", err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flashcards stores users' flashcard decks, and schedules when to quiz them on each card with the SuperMemo 2
+// spaced repetition algorithm.
+package flashcards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxPerUser is the most flashcards a user can have, across all their decks.
+const MaxPerUser = 500
+
+// DefaultDeck is the deck cards go in when the user doesn't name one.
+const DefaultDeck = "general"
+
+// ErrTooMany is returned by Add when the user already has MaxPerUser cards.
+var ErrTooMany = errors.New("too many flashcards")
+
+// Card is a single flashcard, and where it stands in its review schedule.
+type Card struct {
+	ID    string `json:"id"`
+	Deck  string `json:"deck"`
+	Front string `json:"front"`
+	Back  string `json:"back"`
+	// How quickly the card's interval grows when it's remembered.
+	Ease float64 `json:"ease"`
+	// How many days to wait before the next review.
+	Interval int `json:"interval"`
+	// How many times in a row the card has been remembered.
+	Repetitions  int       `json:"repetitions"`
+	Due          time.Time `json:"due"`
+	LastReviewed time.Time `json:"last_reviewed,omitempty"`
+	Created      time.Time `json:"created"`
+}
+
+// New returns a flashcard that is due to be reviewed straight away.
+func New(deck, front, back string, now time.Time) Card {
+	deck = NormalizeDeck(deck)
+	return Card{
+		ID:      uuid.NewString()[:8],
+		Deck:    deck,
+		Front:   strings.TrimSpace(front),
+		Back:    strings.TrimSpace(back),
+		Ease:    initialEase,
+		Due:     now,
+		Created: now,
+	}
+}
+
+// NormalizeDeck returns the name a deck is stored under, so that "Spanish" and "spanish " are the same deck.
+func NormalizeDeck(deck string) string {
+	deck = strings.ToLower(strings.TrimSpace(deck))
+	if deck == "" {
+		return DefaultDeck
+	}
+	return deck
+}
+
+func cardsKey(userID int) string {
+	return fmt.Sprintf("flashcards:%d", userID)
+}
+
+// Add stores a new flashcard.
+func Add(ctx context.Context, rd *redis.Client, userID int, c Card) error {
+	ctx, span := beeline.StartSpan(ctx, "flashcards.add")
+	defer span.Send()
+	key := cardsKey(userID)
+	count, err := rd.HLen(ctx, key).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	if count >= MaxPerUser {
+		return ErrTooMany
+	}
+	return Put(ctx, rd, userID, c)
+}
+
+// Put stores a flashcard, replacing any the user already has with the same ID.
+func Put(ctx context.Context, rd *redis.Client, userID int, c Card) error {
+	j, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return rd.HSet(ctx, cardsKey(userID), c.ID, j).Err()
+}
+
+// Get returns one of the user's flashcards, and whether they have a card with that ID.
+func Get(ctx context.Context, rd *redis.Client, userID int, id string) (Card, bool, error) {
+	j, err := rd.HGet(ctx, cardsKey(userID), id).Result()
+	if errors.Is(err, redis.Nil) {
+		return Card{}, false, nil
+	}
+	if err != nil {
+		return Card{}, false, err
+	}
+	var c Card
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		return Card{}, false, err
+	}
+	return c, true, nil
+}
+
+// List returns the user's flashcards in the given deck, or in every deck if deck is empty, soonest due first.
+func List(ctx context.Context, rd *redis.Client, userID int, deck string) ([]Card, error) {
+	ctx, span := beeline.StartSpan(ctx, "flashcards.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, cardsKey(userID)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	if deck != "" {
+		deck = NormalizeDeck(deck)
+	}
+	cards := []Card{}
+	for _, e := range entries {
+		var c Card
+		if err := json.Unmarshal([]byte(e), &c); err != nil {
+			continue
+		}
+		if deck != "" && c.Deck != deck {
+			continue
+		}
+		cards = append(cards, c)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if !cards[i].Due.Equal(cards[j].Due) {
+			return cards[i].Due.Before(cards[j].Due)
+		}
+		return cards[i].Created.Before(cards[j].Created)
+	})
+	return cards, nil
+}
+
+// DeleteAll removes all the user's cards, in every deck.
+func DeleteAll(ctx context.Context, rd *redis.Client, userID int) error {
+	return rd.Del(ctx, cardsKey(userID)).Err()
+}
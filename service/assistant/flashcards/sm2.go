@@ -0,0 +1,56 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flashcards
+
+import (
+	"math"
+	"time"
+)
+
+// These are the SuperMemo 2 scheduling parameters. See https://super-memory.com/english/ol/sm2.htm.
+const (
+	initialEase = 2.5
+	minimumEase = 1.3
+	// Grades below passingGrade mean the card was forgotten, and start its repetitions over.
+	passingGrade = 3
+	// MaxGrade is the best grade a review can have: a perfect answer with no hesitation.
+	MaxGrade = 5
+)
+
+// Review updates the card's schedule after the user was quizzed on it at now. The grade runs from 0, for complete
+// blackout, to MaxGrade, for a perfect answer.
+func (c *Card) Review(grade int, now time.Time) {
+	grade = max(0, min(grade, MaxGrade))
+	if grade < passingGrade {
+		// A forgotten card starts over, and is asked again in the same session until it's remembered. Its ease stays
+		// as it was: SM-2 only adjusts it for cards that were recalled.
+		c.Repetitions = 0
+		c.Interval = 0
+	} else {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Repetitions++
+		miss := float64(MaxGrade - grade)
+		c.Ease = max(minimumEase, c.Ease+0.1-miss*(0.08+miss*0.02))
+	}
+	c.LastReviewed = now
+	c.Due = now.AddDate(0, 0, c.Interval)
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flashcards
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestReviewFailedKeepsEase(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := &Card{Ease: 2.2, Interval: 15, Repetitions: 3}
+	c.Review(1, now)
+	if c.Ease != 2.2 {
+		t.Errorf("after a failed review, Ease = %v, want it unchanged at 2.2", c.Ease)
+	}
+	if c.Repetitions != 0 || c.Interval != 0 || !c.Due.Equal(now) {
+		t.Errorf("after a failed review, Repetitions = %d, Interval = %d, Due = %v, want the card due again now", c.Repetitions, c.Interval, c.Due)
+	}
+}
+
+func TestReviewPassed(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := &Card{Ease: initialEase}
+	for i, want := range []struct {
+		grade    int
+		interval int
+		ease     float64
+	}{
+		{grade: 5, interval: 1, ease: 2.6},
+		{grade: 4, interval: 6, ease: 2.6},
+		{grade: 3, interval: 16, ease: 2.46},
+	} {
+		c.Review(want.grade, now)
+		if c.Interval != want.interval || math.Abs(c.Ease-want.ease) > 1e-9 || c.Repetitions != i+1 {
+			t.Errorf("review %d: Interval = %d, Ease = %v, Repetitions = %d, want %d, %v, %d", i+1, c.Interval, c.Ease, c.Repetitions, want.interval, want.ease, i+1)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pins"
+)
+
+// errNoSuchTurn is returned by forkTurn when the turn to fork from isn't in the user's history or pins.
+var errNoSuchTurn = errors.New("no such turn")
+
+// forkTurn returns the conversation leading up to and including an earlier turn, identified by its request ID, to
+// start a new conversation from, e.g. to ask a follow-up about a recipe from last week. The turn is looked up in the
+// user's own history and pins, rather than by thread ID, so that nobody can fork someone else's conversation.
+//
+// Threads are only kept for a few minutes, so if the turn's thread is still stored the whole of it is used, function
+// calls and all. After that, only the turn's question and answer are left to go on.
+func (ps *PromptSession) forkTurn(ctx context.Context, userID int, requestID string) ([]*genai.Content, error) {
+	ctx, span := beeline.StartSpan(ctx, "fork_turn")
+	defer span.Send()
+	turn, found, err := persistence.FindTurn(ctx, ps.redis, userID, requestID)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	if !found {
+		pin, pinned, err := pins.Get(ctx, ps.redis, userID, requestID)
+		if err != nil {
+			span.AddField("error", err)
+			return nil, err
+		}
+		if !pinned {
+			return nil, errNoSuchTurn
+		}
+		turn = turnFromPin(pin)
+		span.AddField("from_pin", true)
+	}
+	if turn.ThreadID != "" {
+		messages, err := ps.restoreThread(ctx, turn.ThreadID)
+		if err == nil {
+			span.AddField("from_thread", true)
+			return messages, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			span.AddField("error", err)
+			return nil, err
+		}
+	}
+	return []*genai.Content{
+		{Role: "user", Parts: []*genai.Part{{Text: turn.Prompt}}},
+		{Role: "model", Parts: []*genai.Part{{Text: describeResponse(turn.Response)}}},
+	}, nil
+}
+
+// turnFromPin turns a pinned answer back into the turn it was pinned from.
+func turnFromPin(pin pins.Pin) persistence.Turn {
+	turn := persistence.Turn{
+		ThreadID:  pin.ThreadID,
+		Time:      pin.Time,
+		Prompt:    pin.Prompt,
+		RequestID: pin.ID,
+	}
+	for _, p := range pin.Response {
+		turn.Response = append(turn.Response, persistence.TurnPart{Text: p.Text, Widget: p.Widget})
+	}
+	return turn
+}
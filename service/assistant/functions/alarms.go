@@ -17,11 +17,12 @@ package functions
 import (
 	"context"
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
-	"log"
 	"maps"
 
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"google.golang.org/genai"
 )
 
@@ -30,6 +31,8 @@ type AlarmInput struct {
 	Time string `json:"time"`
 	// An optional name for the alarm.
 	Name string `json:"name"`
+	// How the watch should vibrate when the alarm goes off. Empty means the user's usual setting.
+	Vibration string `json:"vibration"`
 }
 
 type TimerInput struct {
@@ -40,6 +43,8 @@ type TimerInput struct {
 	DurationHours   int `json:"duration_hours"`
 	// An optional name for the alarm or timer.
 	Name string `json:"name"`
+	// How the watch should vibrate when the timer goes off. Empty means the user's usual setting.
+	Vibration string `json:"vibration"`
 }
 
 type DeleteAlarmInput struct {
@@ -54,6 +59,20 @@ type DeleteTimerInput struct {
 
 type Empty struct{}
 
+// alarmVibrations are the vibration patterns the watch app can play, as offered in its settings.
+var alarmVibrations = []string{"standard", "reveille", "mario", "nudge_nudge", "jackhammer"}
+
+// vibrationSchema describes the vibration parameter of set_alarm and set_timer, for watch apps that report the
+// alarm_vibes capability.
+func vibrationSchema(what string) *genai.Schema {
+	return &genai.Schema{
+		Type:        genai.TypeString,
+		Description: "Only if the user asks for a particular vibration, or for the " + what + " to be gentle or strong, the pattern the watch should vibrate with: nudge_nudge is the gentlest and jackhammer the strongest. The watch has no speaker, so every " + what + " is silent and vibrates; a request for a silent or vibrating " + what + " needs nothing extra. Otherwise leave it empty to use the user's usual setting.",
+		Nullable:    true,
+		Enum:        alarmVibrations,
+	}
+}
+
 func init() {
 	params := genai.Schema{
 		Type:     genai.TypeObject,
@@ -61,7 +80,7 @@ func init() {
 		Properties: map[string]*genai.Schema{
 			"time": {
 				Type:        genai.TypeString,
-				Description: "The time to schedule the alarm for in ISO 8601 format, e.g. '2023-07-12T00:00:00-07:00'. Must always be in the future.",
+				Description: "The time to schedule the alarm for in ISO 8601 format, e.g. '2023-07-12T00:00:00-07:00'. Must always be in the future. If the user gives a time somewhere else, call get_time_elsewhere with that location first to find its offset.",
 				Nullable:    true,
 			},
 		},
@@ -75,9 +94,11 @@ func init() {
 			Parameters:  &params,
 		},
 		Cb:             alarmImpl,
+		SideEffects:    true,
 		Thought:        alarmThought,
 		InputType:      AlarmInput{},
 		AntiCapability: "named_alarms",
+		Offline:        true,
 	})
 
 	paramsWithNames := params
@@ -95,10 +116,31 @@ func init() {
 			Description: "Set an alarm for a given time.",
 			Parameters:  &paramsWithNames,
 		},
-		Cb:         alarmImpl,
-		Thought:    alarmThought,
-		InputType:  AlarmInput{},
-		Capability: "named_alarms",
+		Cb:             alarmImpl,
+		SideEffects:    true,
+		Thought:        alarmThought,
+		InputType:      AlarmInput{},
+		Capability:     "named_alarms",
+		AntiCapability: "alarm_vibes",
+		Offline:        true,
+	})
+
+	paramsWithVibes := paramsWithNames
+	paramsWithVibes.Properties = maps.Clone(paramsWithNames.Properties)
+	paramsWithVibes.Properties["vibration"] = vibrationSchema("alarm")
+	// This registration is for watch apps that can vibrate differently for each alarm.
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "set_alarm",
+			Description: "Set an alarm for a given time.",
+			Parameters:  &paramsWithVibes,
+		},
+		Cb:          alarmImpl,
+		SideEffects: true,
+		Thought:     alarmThought,
+		InputType:   AlarmInput{},
+		Capability:  "alarm_vibes",
+		Offline:     true,
 	})
 
 	registerFunction(Registration{
@@ -110,6 +152,7 @@ func init() {
 		Cb:        getAlarmImpl,
 		Thought:   getAlarmThought,
 		InputType: Empty{},
+		Offline:   true,
 	})
 
 	registerFunction(Registration{
@@ -128,9 +171,11 @@ func init() {
 				},
 			},
 		},
-		Cb:        deleteAlarmImpl,
-		Thought:   deleteAlarmThought,
-		InputType: DeleteAlarmInput{},
+		Cb:          deleteAlarmImpl,
+		SideEffects: true,
+		Thought:     deleteAlarmThought,
+		InputType:   DeleteAlarmInput{},
+		Offline:     true,
 	})
 	timerParams := genai.Schema{
 		Type:     genai.TypeObject,
@@ -153,9 +198,11 @@ func init() {
 			Parameters:  &timerParams,
 		},
 		Cb:             timerImpl,
+		SideEffects:    true,
 		Thought:        timerThought,
 		InputType:      TimerInput{},
 		AntiCapability: "named_alarms",
+		Offline:        true,
 	})
 	timerParamsWithNames := timerParams
 	timerParamsWithNames.Properties = maps.Clone(timerParams.Properties)
@@ -170,10 +217,29 @@ func init() {
 			Description: "Set a timer for a given time.",
 			Parameters:  &timerParamsWithNames,
 		},
-		Cb:         timerImpl,
-		Thought:    timerThought,
-		InputType:  TimerInput{},
-		Capability: "named_alarms",
+		Cb:             timerImpl,
+		SideEffects:    true,
+		Thought:        timerThought,
+		InputType:      TimerInput{},
+		Capability:     "named_alarms",
+		AntiCapability: "alarm_vibes",
+		Offline:        true,
+	})
+	timerParamsWithVibes := timerParamsWithNames
+	timerParamsWithVibes.Properties = maps.Clone(timerParamsWithNames.Properties)
+	timerParamsWithVibes.Properties["vibration"] = vibrationSchema("timer")
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "set_timer",
+			Description: "Set a timer for a given time.",
+			Parameters:  &timerParamsWithVibes,
+		},
+		Cb:          timerImpl,
+		SideEffects: true,
+		Thought:     timerThought,
+		InputType:   TimerInput{},
+		Capability:  "alarm_vibes",
+		Offline:     true,
 	})
 
 	registerFunction(Registration{
@@ -185,6 +251,7 @@ func init() {
 		Cb:        getTimerImpl,
 		Thought:   getTimerThought,
 		InputType: Empty{},
+		Offline:   true,
 	})
 
 	registerFunction(Registration{
@@ -203,9 +270,11 @@ func init() {
 				},
 			},
 		},
-		Cb:        deleteTimerImpl,
-		Thought:   deleteTimerThought,
-		InputType: DeleteTimerInput{},
+		Cb:          deleteTimerImpl,
+		SideEffects: true,
+		Thought:     deleteTimerThought,
+		InputType:   DeleteTimerInput{},
+		Offline:     true,
 	})
 }
 
@@ -216,15 +285,16 @@ func alarmImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, reque
 		return Error{Error: "You need to update the app on your watch to set alarms."}
 	}
 	input := args.(*AlarmInput)
-	log.Println("Asking watch to set an alarm...")
+	requestid.Logln(ctx, "Asking watch to set an alarm...")
 	requests <- map[string]any{
-		"time":    input.Time,
-		"isTimer": false,
-		"name":    input.Name,
-		"action":  "set_alarm",
-		"cancel":  false,
+		"time":      input.Time,
+		"isTimer":   false,
+		"name":      input.Name,
+		"vibration": input.Vibration,
+		"action":    "set_alarm",
+		"cancel":    false,
 	}
-	log.Println("Waiting for confirmation...")
+	requestid.Logln(ctx, "Waiting for confirmation...")
 	resp := <-responses
 	return resp
 }
@@ -236,39 +306,45 @@ func timerImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, reque
 		return Error{Error: "You need to update the app on your watch to set timers."}
 	}
 	input := args.(*TimerInput)
-	log.Println("Asking watch to set an alarm...")
+	requestid.Logln(ctx, "Asking watch to set an alarm...")
 	duration := input.Duration + input.DurationMinutes*60 + input.DurationHours*3600
 	if duration == 0 {
 		return Error{Error: "You need to pass the timer duration in seconds to duration_seconds (e.g. duration_seconds=300 for a 5 minute timer)."}
 	}
 	requests <- map[string]any{
-		"duration": duration,
-		"isTimer":  true,
-		"name":     input.Name,
-		"action":   "set_alarm",
-		"cancel":   false,
+		"duration":  duration,
+		"isTimer":   true,
+		"name":      input.Name,
+		"vibration": input.Vibration,
+		"action":    "set_alarm",
+		"cancel":    false,
 	}
-	log.Println("Waiting for confirmation...")
+	requestid.Logln(ctx, "Waiting for confirmation...")
 	resp := <-responses
 	return resp
 }
 
-func alarmThought(i any) string {
+func alarmThought(ctx context.Context, i any) string {
 	args := i.(*AlarmInput)
 	if args.Time == "" {
-		return "Contemplating time"
-	} else {
-		return "Setting an alarm"
+		return i18n.T(ctx, "thought.contemplating_time")
 	}
+	if t := thoughtTime(ctx, args.Time); t != "" {
+		return i18n.T(ctx, "thought.alarm.set.time", t)
+	}
+	return i18n.T(ctx, "thought.alarm.set")
 }
 
-func timerThought(i any) string {
+func timerThought(ctx context.Context, i any) string {
 	args := i.(*TimerInput)
-	if args.Duration == 0 {
-		return "Contemplating time"
-	} else {
-		return "Setting a timer"
+	duration := args.Duration + args.DurationMinutes*60 + args.DurationHours*3600
+	if duration <= 0 {
+		return i18n.T(ctx, "thought.contemplating_time")
+	}
+	if args.Name != "" {
+		return i18n.T(ctx, "thought.timer.set.named", thoughtDuration(ctx, duration), thoughtArgument(args.Name))
 	}
+	return i18n.T(ctx, "thought.timer.set.duration", thoughtDuration(ctx, duration))
 }
 
 func deleteAlarmImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
@@ -278,14 +354,14 @@ func deleteAlarmImpl(ctx context.Context, quotaTracker *quota.Tracker, args any,
 		return Error{Error: "You need to update the app on your watch to delete alarms."}
 	}
 	input := args.(*DeleteAlarmInput)
-	log.Printf("Asking watch to delete an alarm set for %s...\n", input.Time)
+	requestid.Logf(ctx, "Asking watch to delete an alarm set for %s...\n", input.Time)
 	requests <- map[string]any{
 		"time":    input.Time,
 		"isTimer": false,
 		"action":  "set_alarm",
 		"cancel":  true,
 	}
-	log.Println("Waiting for confirmation...")
+	requestid.Logln(ctx, "Waiting for confirmation...")
 	resp := <-responses
 	return resp
 }
@@ -297,24 +373,28 @@ func deleteTimerImpl(ctx context.Context, quotaTracker *quota.Tracker, args any,
 		return Error{Error: "You need to update the app on your watch to delete timers."}
 	}
 	input := args.(*DeleteTimerInput)
-	log.Printf("Asking watch to delete a timer set for %s...\n", input.Time)
+	requestid.Logf(ctx, "Asking watch to delete a timer set for %s...\n", input.Time)
 	requests <- map[string]any{
 		"time":    input.Time,
 		"isTimer": true,
 		"action":  "set_alarm",
 		"cancel":  true,
 	}
-	log.Println("Waiting for confirmation...")
+	requestid.Logln(ctx, "Waiting for confirmation...")
 	resp := <-responses
 	return resp
 }
 
-func deleteAlarmThought(i any) string {
-	return "Deleting an alarm"
+func deleteAlarmThought(ctx context.Context, i any) string {
+	args := i.(*DeleteAlarmInput)
+	if t := thoughtTime(ctx, args.Time); t != "" {
+		return i18n.T(ctx, "thought.alarm.delete.time", t)
+	}
+	return i18n.T(ctx, "thought.alarm.delete")
 }
 
-func deleteTimerThought(i any) string {
-	return "Deleting a timer"
+func deleteTimerThought(ctx context.Context, i any) string {
+	return i18n.T(ctx, "thought.timer.delete")
 }
 
 func getAlarmImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
@@ -323,14 +403,14 @@ func getAlarmImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, re
 	if !query.SupportsAction(ctx, "get_alarm") {
 		return Error{Error: "You need to update the app on your watch to get alarms."}
 	}
-	log.Println("Asking watch to get alarms...")
+	requestid.Logln(ctx, "Asking watch to get alarms...")
 	requests <- map[string]any{
 		"isTimer": false,
 		"action":  "get_alarm",
 	}
-	log.Println("Waiting for response...")
+	requestid.Logln(ctx, "Waiting for response...")
 	resp := <-responses
-	log.Println("Got response:", resp)
+	requestid.Logln(ctx, "Got response:", resp)
 	return resp
 }
 
@@ -340,21 +420,21 @@ func getTimerImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, re
 	if !query.SupportsAction(ctx, "get_alarm") {
 		return Error{Error: "You need to update the app on your watch to get timers."}
 	}
-	log.Println("Asking watch to get alarms...")
+	requestid.Logln(ctx, "Asking watch to get alarms...")
 	requests <- map[string]any{
 		"isTimer": true,
 		"action":  "get_alarm",
 	}
-	log.Println("Waiting for response...")
+	requestid.Logln(ctx, "Waiting for response...")
 	resp := <-responses
-	log.Println("Got response:", resp)
+	requestid.Logln(ctx, "Got response:", resp)
 	return resp
 }
 
-func getAlarmThought(i any) string {
-	return "Checking your alarms"
+func getAlarmThought(ctx context.Context, i any) string {
+	return i18n.T(ctx, "thought.alarm.get")
 }
 
-func getTimerThought(i any) string {
-	return "Checking your timers"
+func getTimerThought(ctx context.Context, i any) string {
+	return i18n.T(ctx, "thought.timer.get")
 }
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// FuzzFixupBrokenJson checks that fixing up the model's arguments never mangles arguments that were fine already.
+func FuzzFixupBrokenJson(f *testing.F) {
+	f.Add(`{"duration": 60}`)
+	f.Add("{\n  \"duration\": 5 * 60,\n  \"name\": \"tea\"\n}")
+	f.Add("{\n  \"amount\": (3 + 4) / 2\n}")
+	f.Add("{\n  \"x\": ,\n}")
+	f.Add(`{"time": "2025-01-01T07:00:00Z"`)
+	f.Fuzz(func(t *testing.T, args string) {
+		fixed := FixupBrokenJson(args)
+		if json.Valid([]byte(args)) && fixed != args {
+			t.Errorf("FixupBrokenJson(%q) = %q, but it was already valid", args, fixed)
+		}
+	})
+}
+
+// FuzzDecodeArgs checks that whatever the model sends as a function's arguments, decoding them either works or says
+// what's wrong, without panicking.
+func FuzzDecodeArgs(f *testing.F) {
+	names := slices.Sorted(maps.Keys(functionMap))
+	f.Add(uint8(0), `{}`)
+	f.Add(uint8(1), `{"query": "weather", "units": "metric"}`)
+	f.Add(uint8(2), "{\n  \"seconds\": 60 * 5\n}")
+	f.Add(uint8(3), `{"time": "2025-06-14T07:00:00Z", "name": null}`)
+	f.Add(uint8(4), `{"days": ["monday", 3], "extra": {"nested": [1, 2]}}`)
+	f.Add(uint8(5), `[1, 2, 3]`)
+	f.Add(uint8(6), `not json`)
+	f.Fuzz(func(t *testing.T, which uint8, args string) {
+		fn := names[int(which)%len(names)]
+		in, fixedArgs, problem := decodeArgs(functionMap[fn], args)
+		if problem != nil {
+			if in != nil {
+				t.Errorf("decodeArgs(%q, %q) gave both %v and a problem: %v", fn, args, in, problem)
+			}
+			return
+		}
+		if in == nil {
+			t.Errorf("decodeArgs(%q, %q) gave neither arguments nor a problem", fn, args)
+		}
+		if !json.Valid([]byte(fixedArgs)) {
+			t.Errorf("decodeArgs(%q, %q) accepted invalid JSON %q", fn, args, fixedArgs)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// stillWorkingNote tells the model what to do with a call that's carrying on in the background.
+const stillWorkingNote = "This is taking a while, so it's carrying on in the background. Briefly tell the user you're still working on it, without answering the question yet or calling this function again. You'll be given the result as soon as it's ready."
+
+// StillWorking is the result given in place of a call that ran over its latency budget.
+type StillWorking struct {
+	Pending bool   `json:"pending"`
+	Note    string `json:"note"`
+}
+
+// PendingCall is a function call that ran over its latency budget and is carrying on in the background.
+type PendingCall struct {
+	Name string
+	Args string
+	// Set once the call has finished, as the JSON result.
+	Result string
+	done   chan any
+}
+
+// PendingCalls are the calls in a session that are carrying on in the background.
+type PendingCalls struct {
+	mu    sync.Mutex
+	calls []*PendingCall
+}
+
+type pendingCallsKey struct{}
+
+// WithPendingCalls returns a context in which functions with a LatencyBudget carry on in the background when they run
+// over it, rather than holding up the response. Without one, they're always waited for.
+func WithPendingCalls(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pendingCallsKey{}, &PendingCalls{})
+}
+
+// PendingCallsFromContext returns the session's pending calls, or nil if it doesn't allow them.
+func PendingCallsFromContext(ctx context.Context) *PendingCalls {
+	p, _ := ctx.Value(pendingCallsKey{}).(*PendingCalls)
+	return p
+}
+
+// Len returns how many calls are still to be collected by Wait.
+func (p *PendingCalls) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.calls)
+}
+
+// Wait waits up to timeout for the pending calls to finish, and returns the ones that did, with their results. The
+// rest are abandoned, so either way there are none pending afterwards.
+func (p *PendingCalls) Wait(ctx context.Context, timeout time.Duration) (finished []*PendingCall, abandoned int) {
+	p.mu.Lock()
+	calls := p.calls
+	p.calls = nil
+	p.mu.Unlock()
+
+	deadline := time.After(timeout)
+	for i, c := range calls {
+		select {
+		case result := <-c.done:
+			c.Result = finishCall(ctx, c.Name, c.Args, result)
+			finished = append(finished, c)
+		case <-deadline:
+			return finished, len(calls) - i
+		case <-ctx.Done():
+			return finished, len(calls) - i
+		}
+	}
+	return finished, 0
+}
+
+// callWithinBudget calls a function, giving up waiting for it after its latency budget if the session allows that. A
+// call that's given up on carries on in the background as a PendingCall, and StillWorking is returned in its place.
+func callWithinBudget(ctx context.Context, fn, args string, call func() any) (result any, finished bool) {
+	budget := functionMap[fn].LatencyBudget
+	pending := PendingCallsFromContext(ctx)
+	if budget <= 0 || pending == nil {
+		return call(), true
+	}
+	done := make(chan any, 1)
+	go func() {
+		done <- call()
+	}()
+	select {
+	case result := <-done:
+		return result, true
+	case <-time.After(budget):
+	}
+	requestid.Logf(ctx, "function %q ran over its %s budget, carrying on in the background\n", fn, budget)
+	pending.mu.Lock()
+	pending.calls = append(pending.calls, &PendingCall{Name: fn, Args: args, done: done})
+	pending.mu.Unlock()
+	return StillWorking{Pending: true, Note: stillWorkingNote}, false
+}
+
+// finishCall encodes a pending call's result, and keeps it for reuse just as CallFunction would have.
+func finishCall(ctx context.Context, fn, args string, result any) string {
+	r, err := json.Marshal(result)
+	if err != nil {
+		r, _ = json.Marshal(Error{"unable to marshal response: " + err.Error()})
+		return string(r)
+	}
+	if reusable(fn) {
+		memoize(ctx, fn, args, string(r))
+		recordResult(ctx, fn, args, string(r))
+	}
+	return string(r)
+}
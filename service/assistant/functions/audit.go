@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/audit"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// maxAuditErrorLength is the longest error message kept in an audit entry.
+const maxAuditErrorLength = 200
+
+// recordAudit adds a call to a function with side effects to the user's audit log. result is the call's result as
+// JSON. Failing to record it is logged, but doesn't affect the call.
+func recordAudit(ctx context.Context, fn, args, result string, pending bool) {
+	if !functionMap[fn].SideEffects {
+		return
+	}
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return
+	}
+	e := audit.Entry{
+		Time:      time.Now().UTC(),
+		UserID:    requester.UserID,
+		Function:  fn,
+		ArgsHash:  audit.HashArgs(args),
+		Outcome:   audit.OutcomeOK,
+		RequestID: requestid.FromContext(ctx),
+		ThreadID:  query.ThreadIdFromContext(ctx),
+		Scheduled: requester.IsScheduled(),
+	}
+	var decoded map[string]any
+	_ = json.Unmarshal([]byte(result), &decoded)
+	switch {
+	case IsSandboxed(ctx):
+		e.Outcome = audit.OutcomeSandboxed
+	case pending:
+		e.Outcome = audit.OutcomePending
+	case decoded["error"] != nil:
+		e.Outcome = audit.OutcomeError
+		e.Error, _ = decoded["error"].(string)
+		if len(e.Error) > maxAuditErrorLength {
+			e.Error = e.Error[:maxAuditErrorLength]
+		}
+	}
+	if err := audit.Record(ctx, storage.GetRedis(), e); err != nil {
+		requestid.Logf(ctx, "Recording %q in the audit log failed: %v", fn, err)
+	}
+}
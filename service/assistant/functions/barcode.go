@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/barcode"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/openfoodfacts"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// maxIngredientsLength is the most of a product's ingredients list we pass on. Some run to pages.
+const maxIngredientsLength = 600
+
+type LookupBarcodeInput struct {
+	// The barcode's digits. If empty, the user is asked to scan one with their phone.
+	Barcode string `json:"barcode"`
+}
+
+type ProductResponse struct {
+	Barcode     string             `json:"barcode"`
+	Name        string             `json:"name,omitempty"`
+	Brands      string             `json:"brands,omitempty"`
+	Quantity    string             `json:"quantity,omitempty"`
+	Ingredients string             `json:"ingredients,omitempty"`
+	Allergens   []string           `json:"allergens,omitempty"`
+	Traces      []string           `json:"may_contain_traces_of,omitempty"`
+	Labels      []string           `json:"labels,omitempty"`
+	NutriScore  string             `json:"nutri_score,omitempty"`
+	NovaGroup   int                `json:"nova_group,omitempty"`
+	Nutrition   map[string]float64 `json:"nutrition_per_100g,omitempty"`
+}
+
+func init() {
+	barcodeParam := &genai.Schema{
+		Type:        genai.TypeString,
+		Description: "The barcode's digits, e.g. \"5000159407236\".",
+		Nullable:    false,
+	}
+	description := "Look up a packaged food or drink by its barcode, to find its ingredients, allergens and nutrition. Use this when the user asks about a product they have in front of them, like \"what's in this?\" or \"does this have nuts in it?\". The data is crowdsourced, so may be incomplete."
+	// This registration is for apps that can't scan barcodes, so the user has to read out the digits.
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "lookup_barcode",
+			Description: description + " Ask the user to read out the numbers under the barcode first.",
+			Parameters: &genai.Schema{
+				Type:       genai.TypeObject,
+				Nullable:   false,
+				Properties: map[string]*genai.Schema{"barcode": barcodeParam},
+				Required:   []string{"barcode"},
+			},
+		},
+		Fn:             lookupBarcode,
+		Thought:        lookupBarcodeThought,
+		InputType:      LookupBarcodeInput{},
+		AntiCapability: "scan_barcode",
+	})
+
+	scanParam := *barcodeParam
+	scanParam.Description = "Only if the user has read out the barcode's digits, the digits. Omit to have the user scan the barcode with their phone's camera."
+	scanParam.Nullable = true
+	// This registration is for apps that can ask the phone to scan a barcode.
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "lookup_barcode",
+			Description: description + " Unless the user has given the digits, calling this asks them to scan the barcode with their phone, so tell them to get their phone out in your response.",
+			Parameters: &genai.Schema{
+				Type:       genai.TypeObject,
+				Nullable:   false,
+				Properties: map[string]*genai.Schema{"barcode": &scanParam},
+			},
+		},
+		Cb:         scanBarcodeImpl,
+		Thought:    lookupBarcodeThought,
+		InputType:  LookupBarcodeInput{},
+		Capability: "scan_barcode",
+	})
+}
+
+func lookupBarcodeThought(ctx context.Context, args any) string {
+	arg := args.(*LookupBarcodeInput)
+	if arg.Barcode == "" {
+		return i18n.T(ctx, "thought.barcode.scan")
+	}
+	return i18n.T(ctx, "thought.barcode")
+}
+
+func lookupBarcode(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "lookup_barcode")
+	defer span.Send()
+	arg := args.(*LookupBarcodeInput)
+	code := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, arg.Barcode)
+	if !barcode.Valid(code) {
+		return Error{Error: fmt.Sprintf("%q isn't a valid barcode. Barcodes have 8, 12, 13 or 14 digits; check with the user that they read it out correctly.", arg.Barcode)}
+	}
+	span.AddField("barcode", code)
+
+	product, err := openfoodfacts.Lookup(ctx, code)
+	if err != nil {
+		span.AddField("error", err)
+		if errors.Is(err, openfoodfacts.ErrUnknownProduct) {
+			return Error{Error: fmt.Sprintf("There's no product with the barcode %s in Open Food Facts.", code)}
+		}
+		return upstreamError("Couldn't look up the product: ", err)
+	}
+	ingredients := product.Ingredients
+	if len(ingredients) > maxIngredientsLength {
+		ingredients = strings.ToValidUTF8(ingredients[:maxIngredientsLength], "") + "..."
+	}
+	return ProductResponse{
+		Barcode:     code,
+		Name:        product.Name,
+		Brands:      product.Brands,
+		Quantity:    product.Quantity,
+		Ingredients: ingredients,
+		Allergens:   product.Allergens,
+		Traces:      product.Traces,
+		Labels:      product.Labels,
+		NutriScore:  product.NutriScore,
+		NovaGroup:   product.NovaGroup,
+		Nutrition:   product.Nutrition,
+	}
+}
+
+// scanBarcodeImpl asks the phone to scan a barcode, if the model doesn't already have one, and then looks it up.
+func scanBarcodeImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
+	ctx, span := beeline.StartSpan(ctx, "scan_barcode")
+	defer span.Send()
+	arg := args.(*LookupBarcodeInput)
+	if arg.Barcode != "" {
+		return lookupBarcode(ctx, quotaTracker, arg)
+	}
+	if !query.SupportsAction(ctx, "scan_barcode") {
+		return Error{Error: "Scanning barcodes isn't possible from here. Ask the user to read out the numbers under the barcode."}
+	}
+	baseURL := config.GetConfig().BaseURL
+	if baseURL == "" {
+		return Error{Error: "Scanning barcodes isn't set up. Ask the user to read out the numbers under the barcode."}
+	}
+	rd := storage.GetRedis()
+	token, err := barcode.NewScan(ctx, rd)
+	if err != nil {
+		span.AddField("error", err)
+		return Error{Error: "Starting the scan failed."}
+	}
+	requestid.Logln(ctx, "Asking phone to scan a barcode...")
+	requests <- map[string]any{
+		"action": "scan_barcode",
+		"url":    baseURL + "/scan?token=" + url.QueryEscape(token),
+	}
+	if resp := <-responses; resp["status"] != "ok" {
+		return resp
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, barcode.ScanTimeout+5*time.Second)
+	defer cancel()
+	code, err := barcode.Wait(ctx, rd, token)
+	if err != nil {
+		span.AddField("error", err)
+		switch {
+		case errors.Is(err, barcode.ErrCancelled):
+			return Error{Error: "The user cancelled scanning."}
+		case errors.Is(err, barcode.ErrTimedOut):
+			return Error{Error: "The user didn't scan anything. They can try again, or read out the numbers under the barcode."}
+		}
+		return Error{Error: "Waiting for the scan failed."}
+	}
+	return lookupBarcode(ctx, quotaTracker, &LookupBarcodeInput{Barcode: code})
+}
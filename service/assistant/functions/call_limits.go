@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/honeycombio/beeline-go"
+)
+
+// CallLimits counts the function calls made in a turn, so that a model stuck in a loop (searching Wikipedia over and
+// over for something that isn't there, say) is made to answer instead of using up the user's quota.
+type CallLimits struct {
+	maxTotal       int
+	maxPerFunction int
+
+	mu          sync.Mutex
+	total       int
+	perFunction map[string]int
+}
+
+type callLimitsKey struct{}
+
+// WithCallLimits returns a context in which the model can make at most maxTotal function calls, and at most
+// maxPerFunction to any one function. Zero means no limit. It should be used once per turn.
+func WithCallLimits(ctx context.Context, maxTotal, maxPerFunction int) context.Context {
+	return context.WithValue(ctx, callLimitsKey{}, &CallLimits{
+		maxTotal:       maxTotal,
+		maxPerFunction: maxPerFunction,
+		perFunction:    map[string]int{},
+	})
+}
+
+// CallLimitsFromContext returns the turn's CallLimits, or nil if there aren't any.
+func CallLimitsFromContext(ctx context.Context) *CallLimits {
+	l, _ := ctx.Value(callLimitsKey{}).(*CallLimits)
+	return l
+}
+
+// Exhausted reports whether the model has made all the calls it's allowed this turn. Once it has, it shouldn't be
+// offered any more functions.
+func (l *CallLimits) Exhausted() bool {
+	if l == nil || l.maxTotal == 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total >= l.maxTotal
+}
+
+// count records a call to fn, returning what to tell the model instead of calling it if it's over a limit.
+func (l *CallLimits) count(ctx context.Context, fn string) any {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		beeline.AddField(ctx, "call_limit_reached", "total")
+		return Error{Error: "You have made as many function calls as you can for this message. Answer with the information you already have."}
+	}
+	if l.maxPerFunction > 0 && l.perFunction[fn] >= l.maxPerFunction {
+		beeline.AddField(ctx, "call_limit_reached", fn)
+		return Error{Error: fmt.Sprintf("You have already called %s %d times for this message, which is as many as you can. Answer with the information you already have, or use a different function.", fn, l.maxPerFunction)}
+	}
+	l.total++
+	l.perFunction[fn]++
+	return nil
+}
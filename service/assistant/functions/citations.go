@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"sync"
+)
+
+// Citation identifies a source a tool consulted, so the phone app can show where an answer came from.
+type Citation struct {
+	// The name of the source, e.g. "Wikipedia".
+	Source string `json:"source"`
+	Title  string `json:"title"`
+	URL    string `json:"url,omitempty"`
+}
+
+type citationCollector struct {
+	mu        sync.Mutex
+	citations []Citation
+}
+
+type citationCollectorKey struct{}
+
+// WithCitations returns a context in which tools can record the sources they used with Cite.
+func WithCitations(ctx context.Context) context.Context {
+	return context.WithValue(ctx, citationCollectorKey{}, &citationCollector{})
+}
+
+// Cite records that a tool's result came from the given source. Citing the same URL twice has no effect, as does
+// citing outside a context created by WithCitations.
+func Cite(ctx context.Context, c Citation) {
+	collector, ok := ctx.Value(citationCollectorKey{}).(*citationCollector)
+	if !ok {
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for _, existing := range collector.citations {
+		if existing.URL == c.URL && existing.Title == c.Title {
+			return
+		}
+	}
+	collector.citations = append(collector.citations, c)
+}
+
+// CitationsFromContext returns the sources cited so far, in the order they were first cited.
+func CitationsFromContext(ctx context.Context) []Citation {
+	collector, ok := ctx.Value(citationCollectorKey{}).(*citationCollector)
+	if !ok {
+		return nil
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return append([]Citation(nil), collector.citations...)
+}
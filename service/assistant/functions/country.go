@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/countries"
+)
+
+type CountryInfoInput struct {
+	// The name or ISO code of the country.
+	Country string `json:"country"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_country_info",
+			Description: "Get basic travel facts about a country: its capital, currency, international calling code, which side of the road people drive on, and which electrical plug types are used. Always call this rather than answering these questions from memory.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"country": {
+						Type:        genai.TypeString,
+						Description: "The English name or ISO 3166-1 alpha-2 code of the country, e.g. \"Japan\" or \"JP\".",
+						Nullable:    false,
+					},
+				},
+				Required: []string{"country"},
+			},
+		},
+		Fn:        getCountryInfo,
+		FreshFor:  24 * time.Hour,
+		Thought:   getCountryInfoThought,
+		InputType: CountryInfoInput{},
+		Offline:   true,
+	})
+}
+
+func getCountryInfoThought(ctx context.Context, args any) string {
+	arg := args.(*CountryInfoInput)
+	if arg.Country == "" {
+		return i18n.T(ctx, "thought.country")
+	}
+	return i18n.T(ctx, "thought.country.named", thoughtArgument(arg.Country))
+}
+
+func getCountryInfo(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_country_info")
+	defer span.Send()
+	arg := args.(*CountryInfoInput)
+	span.AddField("country", arg.Country)
+	country := countries.Lookup(arg.Country)
+	if country == nil {
+		span.AddField("error", "unknown country")
+		return Error{Error: fmt.Sprintf("No information is available for %q. Make sure it's a country name in English, or an ISO country code.", arg.Country)}
+	}
+	return country
+}
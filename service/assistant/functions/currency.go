@@ -17,13 +17,16 @@ package functions
 import (
 	"context"
 	"fmt"
-	"log"
+	"strconv"
+	"time"
 
 	"github.com/honeycombio/beeline-go"
 	"google.golang.org/genai"
 
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/currencies"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 )
 
 type CurrencyConversionRequest struct {
@@ -66,6 +69,7 @@ func init() {
 			},
 		},
 		Fn:        convertCurrency,
+		FreshFor:  time.Hour,
 		Thought:   convertCurrencyThought,
 		InputType: CurrencyConversionRequest{},
 	})
@@ -87,8 +91,8 @@ func convertCurrency(ctx context.Context, qt *quota.Tracker, input any) any {
 
 	data, err := cdm.GetExchangeData(ctx, ccr.From)
 	if err != nil {
-		log.Printf("error getting currency data for %s/%s: %v", ccr.From, ccr.To, err)
-		return Error{Error: err.Error()}
+		requestid.Logf(ctx, "error getting currency data for %s/%s: %v", ccr.From, ccr.To, err)
+		return upstreamError("", err)
 	}
 	if data == nil {
 		return Error{Error: "returned currency data is nil!?"}
@@ -106,7 +110,10 @@ func convertCurrency(ctx context.Context, qt *quota.Tracker, input any) any {
 	}
 }
 
-func convertCurrencyThought(i any) string {
+func convertCurrencyThought(ctx context.Context, i any) string {
 	args := i.(*CurrencyConversionRequest)
-	return fmt.Sprintf("Checking the %s/%s rate...", args.From, args.To)
+	if args.Amount > 0 {
+		return i18n.T(ctx, "thought.currency.amount", strconv.FormatFloat(args.Amount, 'f', -1, 64), args.From, args.To)
+	}
+	return i18n.T(ctx, "thought.currency", args.From, args.To)
 }
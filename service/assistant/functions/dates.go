@@ -0,0 +1,292 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/dates"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+type TrackDateInput struct {
+	// What the date is, e.g. "quit smoking".
+	Name string `json:"name"`
+	// The day, as YYYY-MM-DD. Omit for today.
+	Date string `json:"date"`
+	// Anything else worth remembering about it.
+	Note string `json:"note"`
+}
+
+type TimeSinceInput struct {
+	// The name of the tracked date. Omit for all of them.
+	Name string `json:"name"`
+}
+
+type UntrackDateInput struct {
+	// The name of the tracked date to forget.
+	Name string `json:"name"`
+}
+
+type dateSpan struct {
+	Years  int `json:"years"`
+	Months int `json:"months"`
+	Days   int `json:"days"`
+	// The whole span in days and in weeks, for when that's the more natural way to put it.
+	TotalDays  int `json:"total_days"`
+	TotalWeeks int `json:"total_weeks"`
+}
+
+type trackedDateResult struct {
+	Name string `json:"name"`
+	Date string `json:"date"`
+	Note string `json:"note,omitempty"`
+	// How long ago the date was. Only set for dates that have passed.
+	Since *dateSpan `json:"since,omitempty"`
+	// How long until the date. Only set for dates still to come.
+	Until *dateSpan `json:"until,omitempty"`
+	// The next anniversary. Only set for dates that have passed.
+	NextAnniversary *anniversary `json:"next_anniversary,omitempty"`
+}
+
+type anniversary struct {
+	Date string `json:"date"`
+	// Which anniversary it is, e.g. 10 for the tenth.
+	Number int `json:"number"`
+	// 0 if it's today.
+	DaysUntil int `json:"days_until"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "track_date",
+			Description: "Remember a date that matters to the user, like when they quit smoking, started a job, or got married, so you can tell them later how long it's been or when the next anniversary is. Tracking a name that's already tracked replaces it.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"name": {
+						Type:        genai.TypeString,
+						Description: "A short name for the date, as the user would refer to it, e.g. \"quit smoking\" or \"wedding anniversary\".",
+						Nullable:    false,
+					},
+					"date": {
+						Type:        genai.TypeString,
+						Description: "The day, as YYYY-MM-DD. Work out relative dates like \"three years ago today\" from the current date. Omit for today.",
+						Nullable:    true,
+						Pattern:     `^\d{4}-\d\d-\d\d$`,
+					},
+					"note": {
+						Type:        genai.TypeString,
+						Description: "Anything else the user said that's worth remembering about it.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Fn:          trackDate,
+		SideEffects: true,
+		Thought:     trackDateThought,
+		InputType:   TrackDateInput{},
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "time_since",
+			Description: "Find out how long it's been since a date the user asked you to track with track_date, and when its next anniversary is. Call this whenever the user asks how long it's been since something, or how long until something, before saying you don't know.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"name": {
+						Type:        genai.TypeString,
+						Description: "The name of the tracked date, as given to track_date. Omit to get all of them.",
+						Nullable:    true,
+					},
+				},
+			},
+		},
+		Fn:        timeSince,
+		Thought:   timeSinceThought,
+		InputType: TimeSinceInput{},
+		Offline:   true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "untrack_date",
+			Description: "Forget a date you were asked to track with track_date.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"name": {
+						Type:        genai.TypeString,
+						Description: "The name of the tracked date to forget, as given to track_date.",
+						Nullable:    false,
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Fn:          untrackDate,
+		SideEffects: true,
+		Thought:     trackDateThought,
+		InputType:   UntrackDateInput{},
+		Offline:     true,
+	})
+}
+
+func trackDate(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "track_date")
+	defer span.Send()
+	arg := args.(*TrackDateInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok || requester.IsScheduled() {
+		return Error{Error: "Dates can't be tracked from here."}
+	}
+	d := dates.Date{Name: arg.Name, Date: arg.Date, Note: arg.Note}
+	if d.Date == "" {
+		d.Date = userToday(ctx).Format(dates.Layout)
+	}
+	if err := dates.Put(ctx, storage.GetRedis(), requester.UserID, d); err != nil {
+		switch {
+		case errors.Is(err, dates.ErrTooMany):
+			return Error{Error: fmt.Sprintf("The user is already tracking %d dates, which is the most they can have. They need to forget one first.", dates.MaxPerUser)}
+		case errors.Is(err, dates.ErrInvalid):
+			return Error{Error: err.Error()}
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Tracking date failed: %v", err)
+		return Error{Error: "Remembering the date failed."}
+	}
+	return describeTrackedDate(ctx, d)
+}
+
+func timeSince(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "time_since")
+	defer span.Send()
+	arg := args.(*TimeSinceInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Tracked dates aren't available here."}
+	}
+	rd := storage.GetRedis()
+	if strings.TrimSpace(arg.Name) != "" {
+		d, err := dates.Get(ctx, rd, requester.UserID, arg.Name)
+		if err != nil {
+			span.AddField("error", err)
+			requestid.Logf(ctx, "Looking up tracked date failed: %v", err)
+			return Error{Error: "Looking up the date failed."}
+		}
+		if d != nil {
+			return describeTrackedDate(ctx, *d)
+		}
+	}
+	all, err := dates.List(ctx, rd, requester.UserID)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Listing tracked dates failed: %v", err)
+		return Error{Error: "Looking up tracked dates failed."}
+	}
+	results := []trackedDateResult{}
+	for _, d := range all {
+		results = append(results, describeTrackedDate(ctx, d))
+	}
+	if strings.TrimSpace(arg.Name) != "" {
+		// The model may not have used quite the same name, so give it everything to pick from.
+		return map[string]any{
+			"warning":       fmt.Sprintf("No date called %q is being tracked. These are the ones that are; if one of them is what the user meant, use it, otherwise tell the user.", arg.Name),
+			"tracked_dates": results,
+		}
+	}
+	return map[string]any{"tracked_dates": results}
+}
+
+func untrackDate(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "untrack_date")
+	defer span.Send()
+	arg := args.(*UntrackDateInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok || requester.IsScheduled() {
+		return Error{Error: "Dates can't be tracked from here."}
+	}
+	removed, err := dates.Remove(ctx, storage.GetRedis(), requester.UserID, arg.Name)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Untracking date failed: %v", err)
+		return Error{Error: "Forgetting the date failed."}
+	}
+	if !removed {
+		return Error{Error: fmt.Sprintf("No date called %q is being tracked. Call time_since to find the right name.", arg.Name)}
+	}
+	return map[string]any{"status": "ok"}
+}
+
+// userToday returns the current date in the user's time zone, as midnight UTC on that day.
+func userToday(ctx context.Context) time.Time {
+	now := time.Now().In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60))
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func describeTrackedDate(ctx context.Context, d dates.Date) trackedDateResult {
+	result := trackedDateResult{Name: d.Name, Date: d.Date, Note: d.Note}
+	day, err := d.Day()
+	if err != nil {
+		return result
+	}
+	today := userToday(ctx)
+	if day.After(today) {
+		result.Until = newDateSpan(today, day)
+		return result
+	}
+	result.Since = newDateSpan(day, today)
+	next, n := dates.NextAnniversary(day, today)
+	result.NextAnniversary = &anniversary{
+		Date:      next.Format(dates.Layout),
+		Number:    n,
+		DaysUntil: int(next.Sub(today).Hours() / 24),
+	}
+	return result
+}
+
+func newDateSpan(from, to time.Time) *dateSpan {
+	years, months, days := dates.Between(from, to)
+	total := int(to.Sub(from).Hours() / 24)
+	return &dateSpan{Years: years, Months: months, Days: days, TotalDays: total, TotalWeeks: total / 7}
+}
+
+func trackDateThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.dates.track")
+}
+
+func timeSinceThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.dates.since")
+}
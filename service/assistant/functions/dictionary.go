@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/dictionary"
+)
+
+// maxDefinitionsPerMeaning keeps long entries from filling the context with senses nobody asked about.
+const maxDefinitionsPerMeaning = 3
+
+type DefineWordInput struct {
+	// The word to look up, e.g. "quixotic".
+	Word string `json:"word"`
+}
+
+type DefineWordResponse struct {
+	Word string `json:"word"`
+	// How to say the word, as a respelling like "kwik-SOT-ik", if the dictionary knows.
+	Pronunciation string               `json:"pronunciation,omitempty"`
+	Meanings      []dictionary.Meaning `json:"meanings"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "define_word",
+			Description: "Look up an English word in the dictionary, for its meanings and how to pronounce it. Use this when the user asks what a word means, how to say it, or how many syllables it has. The watch can't display IPA, so never write it: give the pronunciation as it's respelled here.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"word": {
+						Type:        genai.TypeString,
+						Description: "The word to look up, e.g. \"quixotic\".",
+						Nullable:    false,
+					},
+				},
+				Required: []string{"word"},
+			},
+		},
+		Fn:        defineWord,
+		FreshFor:  24 * time.Hour,
+		Thought:   defineWordThought,
+		InputType: DefineWordInput{},
+	})
+}
+
+func defineWordThought(ctx context.Context, args any) string {
+	arg := args.(*DefineWordInput)
+	return i18n.T(ctx, "thought.dictionary", thoughtArgument(arg.Word))
+}
+
+func defineWord(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "define_word")
+	defer span.Send()
+	arg := args.(*DefineWordInput)
+	span.AddField("word", arg.Word)
+
+	entry, err := dictionary.Lookup(ctx, arg.Word)
+	if err != nil {
+		span.AddField("error", err)
+		if errors.Is(err, dictionary.ErrUnknownWord) {
+			return Error{Error: fmt.Sprintf("%q isn't in the dictionary.", arg.Word)}
+		}
+		return upstreamError("Couldn't look up the word: ", err)
+	}
+	response := DefineWordResponse{
+		Word:          entry.Word,
+		Pronunciation: dictionary.Respell(entry.IPA),
+		Meanings:      []dictionary.Meaning{},
+	}
+	for _, m := range entry.Meanings {
+		if len(m.Definitions) > maxDefinitionsPerMeaning {
+			m.Definitions = m.Definitions[:maxDefinitionsPerMeaning]
+		}
+		response.Meanings = append(response.Meanings, m)
+	}
+	return response
+}
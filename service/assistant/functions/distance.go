@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+	"github.com/umahmood/haversine"
+	"google.golang.org/genai"
+)
+
+type GetDistanceInput struct {
+	// The place to measure from. If empty, the user's current location.
+	From string `json:"from"`
+	// The place to measure to.
+	To string `json:"to"`
+}
+
+type DistanceResponse struct {
+	From               string  `json:"from"`
+	To                 string  `json:"to"`
+	DistanceKilometers float64 `json:"distance_kilometers"`
+	DistanceMiles      float64 `json:"distance_miles"`
+	// The initial compass bearing from From to To, in degrees clockwise from north.
+	BearingDegrees float64 `json:"bearing_degrees"`
+	Direction      string  `json:"direction"`
+	// A human-readable summary in the user's preferred units.
+	Formatted string `json:"formatted"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_distance",
+			Description: "Get the straight-line (great-circle) distance and compass direction between two places, or between the user and a place. Use this to answer questions like \"how far is Berlin from here?\" rather than estimating. This is not a driving or walking distance.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"from": {
+						Type:        genai.TypeString,
+						Description: `The place to measure from, e.g. "Paris, France". Omit to measure from the user's current location.`,
+						Nullable:    true,
+					},
+					"to": {
+						Type:        genai.TypeString,
+						Description: `The place to measure to, e.g. "Berlin, Germany".`,
+						Nullable:    false,
+					},
+				},
+				Required: []string{"to"},
+			},
+		},
+		Fn:        getDistanceImpl,
+		FreshFor:  10 * time.Minute,
+		Thought:   getDistanceThought,
+		InputType: GetDistanceInput{},
+	})
+}
+
+func getDistanceThought(ctx context.Context, args any) string {
+	arg := args.(*GetDistanceInput)
+	if arg.To == "" {
+		return i18n.T(ctx, "thought.distance")
+	}
+	if arg.From != "" {
+		return i18n.T(ctx, "thought.distance.between", thoughtArgument(arg.From), thoughtArgument(arg.To))
+	}
+	return i18n.T(ctx, "thought.distance.to", thoughtArgument(arg.To))
+}
+
+func getDistanceImpl(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_distance")
+	defer span.Send()
+	arg := args.(*GetDistanceInput)
+	if arg.To == "" {
+		return Error{Error: "A destination must be provided."}
+	}
+
+	var from photon.Location
+	if arg.From == "" || arg.From == "here" {
+		userLocation := query.LocationFromContext(ctx)
+		if userLocation == nil {
+			span.AddField("error", "no location provided")
+			return Error{Error: "The user's location is not available. Either the user must enable location in settings, or an explicit starting place must be provided."}
+		}
+		from = photon.Location{Lat: userLocation.Lat, Lon: userLocation.Lon, Name: "your location"}
+	} else {
+		var err error
+		from, err = photon.GeocodeWithContext(ctx, arg.From, photon.AnyPlace)
+		if err != nil {
+			span.AddField("error", err)
+			return geocodingError(err)
+		}
+	}
+	to, err := photon.GeocodeWithContext(ctx, arg.To, photon.AnyPlace)
+	if err != nil {
+		span.AddField("error", err)
+		return geocodingError(err)
+	}
+
+	miles, km := haversine.Distance(
+		haversine.Coord{Lat: from.Lat, Lon: from.Lon},
+		haversine.Coord{Lat: to.Lat, Lon: to.Lon})
+	if (arg.From == "" || arg.From == "here") && query.LocationIsCoarseFromContext(ctx) && km < minCoarseDistanceKm {
+		span.AddField("error", "location too coarse")
+		return preciseLocationError("measuring distances to nearby places")
+	}
+	bearing := initialBearing(from.Lat, from.Lon, to.Lat, to.Lon)
+	response := DistanceResponse{
+		From:               from.Name,
+		To:                 to.Name,
+		DistanceKilometers: math.Round(km*10) / 10,
+		DistanceMiles:      math.Round(miles*10) / 10,
+		BearingDegrees:     math.Round(bearing),
+		Direction:          compassDirection(bearing),
+	}
+	response.Formatted = formatDistance(query.PreferredUnitsFromContext(ctx), km, miles) + " " + response.Direction
+	return response
+}
+
+// initialBearing returns the bearing, in degrees clockwise from north, that you'd set off on to follow the great
+// circle from the first point to the second.
+func initialBearing(lat1, lon1, lat2, lon2 float64) float64 {
+	lat1Rad, lat2Rad := lat1*math.Pi/180, lat2*math.Pi/180
+	deltaLon := (lon2 - lon1) * math.Pi / 180
+	y := math.Sin(deltaLon) * math.Cos(lat2Rad)
+	x := math.Cos(lat1Rad)*math.Sin(lat2Rad) - math.Sin(lat1Rad)*math.Cos(lat2Rad)*math.Cos(deltaLon)
+	bearing := math.Atan2(y, x) * 180 / math.Pi
+	return math.Mod(bearing+360, 360)
+}
+
+var compassPoints = []string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"}
+
+// compassDirection turns a bearing into one of the eight principal compass directions.
+func compassDirection(bearing float64) string {
+	return compassPoints[int(math.Round(bearing/45))%len(compassPoints)]
+}
+
+// formatDistance formats a distance according to the user's unit preference. With no preference, both are given.
+func formatDistance(units string, km, miles float64) string {
+	formatOne := func(value float64, unit string) string {
+		if value < 10 {
+			return fmt.Sprintf("%.1f %s", value, unit)
+		}
+		return fmt.Sprintf("%.0f %s", value, unit)
+	}
+	switch units {
+	case "imperial", "uk":
+		return formatOne(miles, "mi")
+	case "metric":
+		return formatOne(km, "km")
+	default:
+		return formatOne(km, "km") + " (" + formatOne(miles, "mi") + ")"
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import "testing"
+
+func TestFormatDistance(t *testing.T) {
+	tests := []struct {
+		units     string
+		km, miles float64
+		want      string
+	}{
+		{"metric", 16.09, 10, "16 km"},
+		{"imperial", 16.09, 10, "10 mi"},
+		{"uk", 16.09, 10, "10 mi"},
+		{"uk", 3.22, 2, "2.0 mi"},
+		{"", 16.09, 10, "16 km (10 mi)"},
+	}
+	for _, tt := range tests {
+		if got := formatDistance(tt.units, tt.km, tt.miles); got != tt.want {
+			t.Errorf("formatDistance(%q, %v, %v) = %q, want %q", tt.units, tt.km, tt.miles, got, tt.want)
+		}
+	}
+}
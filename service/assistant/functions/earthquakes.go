@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/umahmood/haversine"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/earthquakes"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+)
+
+const (
+	defaultEarthquakeHours     = 24
+	maxEarthquakeHours         = 30 * 24
+	defaultEarthquakeRadiusKm  = 300
+	maxEarthquakeRadiusKm      = 2000
+	defaultEarthquakeMagnitude = 2.5
+)
+
+type GetEarthquakesInput struct {
+	// The place to look near. If empty, the user's current location.
+	Location string `json:"location"`
+	// How far back to look, in hours.
+	Hours int `json:"hours"`
+	// How far from the place to look, in kilometres.
+	RadiusKm float64 `json:"radius_km"`
+	// The smallest magnitude to include.
+	MinMagnitude float64 `json:"min_magnitude"`
+}
+
+type earthquakeResult struct {
+	Magnitude float64 `json:"magnitude"`
+	Place     string  `json:"place"`
+	// When it happened, in the user's time zone.
+	Time       string `json:"time"`
+	MinutesAgo int    `json:"minutes_ago"`
+	// How far it was from the place asked about, in the user's preferred units.
+	Distance  string  `json:"distance"`
+	Direction string  `json:"direction"`
+	DepthKm   float64 `json:"depth_km"`
+	// How many people reported feeling it.
+	FeltReports int    `json:"felt_reports,omitempty"`
+	Tsunami     bool   `json:"tsunami_possible,omitempty"`
+	Alert       string `json:"alert_level,omitempty"`
+}
+
+type EarthquakesResponse struct {
+	Location    string             `json:"location"`
+	Hours       int                `json:"hours"`
+	RadiusKm    float64            `json:"radius_km"`
+	Earthquakes []earthquakeResult `json:"earthquakes"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_earthquakes",
+			Description: "Get recent earthquakes near a place, or near the user, from the USGS catalog, most recent first, with their magnitude, distance and time. Use this for questions like \"was that an earthquake just now?\" or \"have there been any earthquakes near Tokyo this week?\". Reports can take a few minutes to appear, so if the user felt something in the last few minutes and nothing is listed, say so rather than saying there was no earthquake.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"location": {
+						Type:        genai.TypeString,
+						Description: `The place to look near, e.g. "Los Angeles, California". Omit to look near the user.`,
+						Nullable:    true,
+					},
+					"hours": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("How far back to look, in hours. Defaults to %d; at most %d.", defaultEarthquakeHours, maxEarthquakeHours),
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"radius_km": {
+						Type:        genai.TypeNumber,
+						Description: fmt.Sprintf("How far from the place to look, in kilometres. Defaults to %d; at most %d.", defaultEarthquakeRadiusKm, maxEarthquakeRadiusKm),
+						Nullable:    true,
+						Format:      "double",
+					},
+					"min_magnitude": {
+						Type:        genai.TypeNumber,
+						Description: fmt.Sprintf("The smallest magnitude to include. Defaults to %.1f. Use a lower value, like 1.5, if the user is asking whether they just felt one.", defaultEarthquakeMagnitude),
+						Nullable:    true,
+						Format:      "double",
+					},
+				},
+			},
+		},
+		Fn:        getEarthquakes,
+		FreshFor:  time.Minute,
+		Thought:   getEarthquakesThought,
+		InputType: GetEarthquakesInput{},
+	})
+}
+
+func getEarthquakesThought(ctx context.Context, args any) string {
+	arg := args.(*GetEarthquakesInput)
+	if arg.Location != "" && arg.Location != "here" {
+		placeName, _, _ := strings.Cut(arg.Location, ",")
+		return i18n.T(ctx, "thought.earthquakes.place", thoughtArgument(placeName))
+	}
+	return i18n.T(ctx, "thought.earthquakes")
+}
+
+func getEarthquakes(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_earthquakes")
+	defer span.Send()
+	arg := args.(*GetEarthquakesInput)
+
+	var place photon.Location
+	if arg.Location == "" || arg.Location == "here" {
+		location := query.CoarseLocationFromContext(ctx)
+		if location == nil {
+			span.AddField("error", "no location provided")
+			return Error{Error: "The user's location is not available. Either the user must enable location in settings, or a place must be provided."}
+		}
+		place = photon.Location{Lat: location.Lat, Lon: location.Lon, Name: "your location"}
+	} else {
+		var err error
+		place, err = photon.GeocodeWithContext(ctx, arg.Location, photon.AnyPlace)
+		if err != nil {
+			span.AddField("error", err)
+			return geocodingError(err)
+		}
+	}
+
+	hours := arg.Hours
+	if hours <= 0 {
+		hours = defaultEarthquakeHours
+	}
+	hours = min(hours, maxEarthquakeHours)
+	radius := arg.RadiusKm
+	if radius <= 0 {
+		radius = defaultEarthquakeRadiusKm
+	}
+	radius = min(radius, maxEarthquakeRadiusKm)
+	magnitude := arg.MinMagnitude
+	if magnitude <= 0 {
+		magnitude = defaultEarthquakeMagnitude
+	}
+	span.AddField("hours", hours)
+	span.AddField("radius_km", radius)
+	span.AddField("min_magnitude", magnitude)
+
+	now := time.Now()
+	quakes, err := earthquakes.Near(ctx, place.Lat, place.Lon, radius, now.Add(-time.Duration(hours)*time.Hour), magnitude)
+	if err != nil {
+		span.AddField("error", err)
+		return upstreamError("Couldn't look up earthquakes: ", err)
+	}
+
+	loc := time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	return EarthquakesResponse{
+		Location:    place.Name,
+		Hours:       hours,
+		RadiusKm:    radius,
+		Earthquakes: earthquakeResults(place, quakes, query.PreferredUnitsFromContext(ctx), loc, now),
+	}
+}
+
+// earthquakeResults describes each earthquake relative to the place asked about, in the user's units and time zone.
+func earthquakeResults(place photon.Location, quakes []earthquakes.Quake, units string, loc *time.Location, now time.Time) []earthquakeResult {
+	results := []earthquakeResult{}
+	for _, q := range quakes {
+		miles, km := haversine.Distance(
+			haversine.Coord{Lat: place.Lat, Lon: place.Lon},
+			haversine.Coord{Lat: q.Lat, Lon: q.Lon})
+		results = append(results, earthquakeResult{
+			Magnitude:   math.Round(q.Magnitude*10) / 10,
+			Place:       q.Place,
+			Time:        q.Time.In(loc).Format(time.RFC3339),
+			MinutesAgo:  int(now.Sub(q.Time).Minutes()),
+			Distance:    formatDistance(units, km, miles),
+			Direction:   compassDirection(initialBearing(place.Lat, place.Lon, q.Lat, q.Lon)),
+			DepthKm:     math.Round(q.DepthKm),
+			FeltReports: q.Felt,
+			Tsunami:     q.Tsunami,
+			Alert:       q.Alert,
+		})
+	}
+	return results
+}
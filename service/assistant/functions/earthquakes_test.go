@@ -0,0 +1,58 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/earthquakes"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+)
+
+func TestEarthquakeResults(t *testing.T) {
+	london := photon.Location{Lat: 51.5074, Lon: -0.1278, Name: "London"}
+	now := time.Date(2025, 6, 14, 12, 0, 0, 0, time.UTC)
+	quakes := []earthquakes.Quake{{
+		Magnitude: 3.14,
+		Place:     "Oxford",
+		Time:      now.Add(-90 * time.Minute),
+		Lat:       51.7520,
+		Lon:       -1.2577,
+		DepthKm:   9.6,
+	}}
+	tests := []struct {
+		units string
+		want  string
+	}{
+		{"metric", "83 km"},
+		{"imperial", "51 mi"},
+		{"uk", "51 mi"},
+		{"", "83 km (51 mi)"},
+	}
+	for _, tt := range tests {
+		results := earthquakeResults(london, quakes, tt.units, time.UTC, now)
+		if len(results) != 1 {
+			t.Fatalf("earthquakeResults(units=%q) gave %d results, want 1", tt.units, len(results))
+		}
+		got := results[0]
+		if got.Distance != tt.want {
+			t.Errorf("earthquakeResults(units=%q) distance = %q, want %q", tt.units, got.Distance, tt.want)
+		}
+		if got.Direction != "west" || got.MinutesAgo != 90 || got.Magnitude != 3.1 {
+			t.Errorf("earthquakeResults(units=%q) = %+v", tt.units, got)
+		}
+	}
+}
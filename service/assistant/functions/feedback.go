@@ -16,10 +16,11 @@ package functions
 
 import (
 	"context"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"google.golang.org/genai"
-	"log"
 )
 
 type FeedbackInput struct {
@@ -50,10 +51,12 @@ func init() {
 				Required: []string{"include_thread"},
 			},
 		},
-		Cb:         sendFeedbackImpl,
-		Thought:    sendFeedbackThought,
-		InputType:  FeedbackInput{},
-		Capability: "send_feedback",
+		Cb:              sendFeedbackImpl,
+		SideEffects:     true,
+		Thought:         sendFeedbackThought,
+		InputType:       FeedbackInput{},
+		Capability:      "send_feedback",
+		HiddenInKidMode: true,
 	})
 }
 
@@ -62,7 +65,7 @@ func sendFeedbackImpl(ctx context.Context, quotaTracker *quota.Tracker, i any, r
 	if !args.IncludeThread && args.Feedback == "" {
 		return Error{Error: "You need either set include_thread = true or include some feedback from the user."}
 	}
-	log.Printf("Asking phone to send feedback...")
+	requestid.Logf(ctx, "Asking phone to send feedback...")
 	request := map[string]any{
 		"action":   "send_feedback",
 		"feedback": args.Feedback,
@@ -71,21 +74,21 @@ func sendFeedbackImpl(ctx context.Context, quotaTracker *quota.Tracker, i any, r
 		request["thread_id"] = query.ThreadIdFromContext(ctx)
 	}
 	requestChan <- request
-	log.Printf("Waiting for response...")
+	requestid.Logf(ctx, "Waiting for response...")
 	response := <-responseChan
-	log.Printf("Got response: %v", response)
+	requestid.Logf(ctx, "Got response: %v", response)
 	return response
 }
 
-func sendFeedbackThought(i any) string {
+func sendFeedbackThought(ctx context.Context, i any) string {
 	args := i.(*FeedbackInput)
 	if args.IncludeThread && args.Feedback != "" {
-		return "Sending conversation with feedback..."
+		return i18n.T(ctx, "thought.feedback.both")
 	} else if args.IncludeThread {
-		return "Sending conversation..."
+		return i18n.T(ctx, "thought.feedback.thread")
 	} else if args.Feedback != "" {
-		return "Sending feedback..."
+		return i18n.T(ctx, "thought.feedback.text")
 	} else {
-		return "Doing nothing productive..."
+		return i18n.T(ctx, "thought.feedback.nothing")
 	}
 }
@@ -0,0 +1,234 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/flashcards"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+type CreateFlashcardInput struct {
+	// The prompt side of the card, e.g. "dog".
+	Front string `json:"front"`
+	// The answer side of the card, e.g. "el perro".
+	Back string `json:"back"`
+	// The deck to put the card in, e.g. "spanish".
+	Deck string `json:"deck"`
+}
+
+type QuizMeInput struct {
+	// The deck to quiz from. Omit to quiz from every deck.
+	Deck string `json:"deck"`
+	// The ID of the card the user just answered, if any.
+	AnsweredID string `json:"answered_id"`
+	// How well the user answered it, from 0 to 5.
+	Grade *int `json:"grade"`
+}
+
+type flashcardResult struct {
+	ID    string `json:"id"`
+	Deck  string `json:"deck"`
+	Front string `json:"front"`
+	Back  string `json:"back"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "create_flashcard",
+			Description: "Add a flashcard to one of the user's decks, so they can be quizzed on it later with spaced repetition. Use this when the user asks to remember, learn or practise something, e.g. a word in a language they're learning.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"front": {
+						Type:        genai.TypeString,
+						Description: "The side of the card the user is shown, e.g. \"dog\".",
+						Nullable:    false,
+					},
+					"back": {
+						Type:        genai.TypeString,
+						Description: "The answer the user has to give, e.g. \"el perro\".",
+						Nullable:    false,
+					},
+					"deck": {
+						Type:        genai.TypeString,
+						Description: "A short name for the deck to put the card in, e.g. \"spanish\". Use the same name for related cards. Omit if nothing fits.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"front", "back"},
+			},
+		},
+		Fn:          createFlashcard,
+		SideEffects: true,
+		Thought:     createFlashcardThought,
+		InputType:   CreateFlashcardInput{},
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name: "quiz_me",
+			Description: "Get the next flashcard the user is due to be quizzed on. Ask the user the front of the card, and don't reveal the back until they've answered. " +
+				"When they answer, call this again with the card's ID as answered_id and a grade for their answer, which schedules the card's next review and returns the next card. " +
+				"Grades are 5 for a perfect answer, 4 for a correct answer after hesitation, 3 for a correct answer recalled with difficulty, 2 for a wrong answer that seemed easy once they were told, 1 for a wrong answer they recognised, and 0 if they didn't remember at all.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: true,
+				Properties: map[string]*genai.Schema{
+					"deck": {
+						Type:        genai.TypeString,
+						Description: "The deck to quiz from. Omit to quiz from every deck.",
+						Nullable:    true,
+					},
+					"answered_id": {
+						Type:        genai.TypeString,
+						Description: "The ID of the card the user just answered, if any.",
+						Nullable:    true,
+					},
+					"grade": {
+						Type:        genai.TypeInteger,
+						Description: "How well the user answered the card given by answered_id, from 0 to 5.",
+						Nullable:    true,
+						Minimum:     genai.Ptr(0.0),
+						Maximum:     genai.Ptr(float64(flashcards.MaxGrade)),
+					},
+				},
+			},
+		},
+		Fn:          quizMe,
+		SideEffects: true,
+		Thought:     quizMeThought,
+		InputType:   QuizMeInput{},
+		Offline:     true,
+	})
+}
+
+func createFlashcard(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "create_flashcard")
+	defer span.Send()
+	arg := args.(*CreateFlashcardInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Flashcards aren't available here."}
+	}
+	card := flashcards.New(arg.Deck, arg.Front, arg.Back, time.Now())
+	if card.Front == "" || card.Back == "" {
+		return Error{Error: "A flashcard needs both a front and a back."}
+	}
+	if err := flashcards.Add(ctx, storage.GetRedis(), requester.UserID, card); err != nil {
+		if errors.Is(err, flashcards.ErrTooMany) {
+			return Error{Error: fmt.Sprintf("The user already has %d flashcards, which is the most they can have.", flashcards.MaxPerUser)}
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Creating flashcard failed: %v", err)
+		return Error{Error: "Creating the flashcard failed."}
+	}
+	span.AddField("deck", card.Deck)
+	return describeFlashcard(card)
+}
+
+func quizMe(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "quiz_me")
+	defer span.Send()
+	arg := args.(*QuizMeInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Flashcards aren't available here."}
+	}
+	rd := storage.GetRedis()
+	now := time.Now()
+	if arg.AnsweredID != "" {
+		if arg.Grade == nil {
+			return Error{Error: "A grade is needed for the answered card."}
+		}
+		card, ok, err := flashcards.Get(ctx, rd, requester.UserID, arg.AnsweredID)
+		if err != nil {
+			span.AddField("error", err)
+			requestid.Logf(ctx, "Loading flashcard failed: %v", err)
+			return Error{Error: "Recording the answer failed."}
+		}
+		if !ok {
+			return Error{Error: fmt.Sprintf("There's no flashcard with ID %q.", arg.AnsweredID)}
+		}
+		card.Review(*arg.Grade, now)
+		if err := flashcards.Put(ctx, rd, requester.UserID, card); err != nil {
+			span.AddField("error", err)
+			requestid.Logf(ctx, "Storing flashcard failed: %v", err)
+			return Error{Error: "Recording the answer failed."}
+		}
+		span.AddField("grade", *arg.Grade)
+	}
+	cards, err := flashcards.List(ctx, rd, requester.UserID, arg.Deck)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Listing flashcards failed: %v", err)
+		return Error{Error: "Looking up flashcards failed."}
+	}
+	if len(cards) == 0 {
+		if arg.Deck != "" {
+			return Error{Error: fmt.Sprintf("The user has no flashcards in the %q deck.", flashcards.NormalizeDeck(arg.Deck))}
+		}
+		return Error{Error: "The user has no flashcards. They can ask you to make some."}
+	}
+	due := 0
+	for _, c := range cards {
+		if !c.Due.After(now) {
+			due++
+		}
+	}
+	span.AddField("due", due)
+	if due == 0 {
+		return map[string]any{
+			"status":   "No cards are due yet.",
+			"next_due": cards[0].Due.In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)).Format(time.RFC3339),
+		}
+	}
+	return map[string]any{
+		"card":      describeFlashcard(cards[0]),
+		"due_count": due,
+	}
+}
+
+func describeFlashcard(c flashcards.Card) flashcardResult {
+	return flashcardResult{
+		ID:    c.ID,
+		Deck:  c.Deck,
+		Front: c.Front,
+		Back:  c.Back,
+	}
+}
+
+func createFlashcardThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.flashcard.create")
+}
+
+func quizMeThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.flashcard.quiz")
+}
@@ -18,20 +18,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/authz"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
-	"log"
 	"reflect"
+	"runtime/debug"
 	"strings"
 	"time"
 
-	"golang.org/x/exp/slices"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/wire"
 	"google.golang.org/genai"
 	"nhooyr.io/websocket"
 )
 
 type ToolFunction func(context.Context, *quota.Tracker, any) any
 type CallbackFunction func(context.Context, *quota.Tracker, any, chan<- map[string]any, <-chan map[string]any) any
-type ThoughtFunction func(any) string
+type ThoughtFunction func(context.Context, any) string
 
 const MaxResponseSize = 20000
 
@@ -55,13 +59,46 @@ type Registration struct {
 	Capability string
 	// A capability the device must *not* report for this function to be provided.
 	AntiCapability string
+	// Whether to withhold the function from sessions running in kid mode, e.g. because it searches the web or sends
+	// messages on the user's behalf.
+	HiddenInKidMode bool
+	// Whether the function changes something outside the conversation, e.g. setting an alarm or sending a message.
+	// Such functions are simulated rather than called in sandbox mode, and every call is recorded in the audit log.
+	SideEffects bool
+	// A feature flag that must be enabled (see FEATURE_FLAGS) for this function to be provided, so that new functions
+	// can be tried out on one deployment before being offered everywhere.
+	FeatureFlag string
+	// How long the function's results stay good enough to reuse in later turns of the conversation. Zero means
+	// they're only reused within a turn. Functions with side effects never have their results reused.
+	FreshFor time.Duration
+	// How long to wait for the function before telling the user it's still working on it. If it runs over, it carries
+	// on in the background and the model is given its result once it's ready. Zero means always wait.
+	LatencyBudget time.Duration
+	// Whether the function works without the internet, e.g. because it only talks to the watch or to Redis. Only
+	// these are offered while the session is answering with the local model.
+	Offline bool
+	// Whether the function should give a different answer every time, e.g. because it rolls dice. Like functions with
+	// side effects, its results are never reused, even within a turn.
+	Random bool
+}
+
+// reusable returns whether a call to fn can be answered with the result of an earlier call with the same arguments.
+func reusable(fn string) bool {
+	return !functionMap[fn].SideEffects && !functionMap[fn].Random
 }
 
 type Error struct {
 	Error string `json:"error"`
 }
 
+// functionMap holds the first registration of each function, for the properties every registration of a function
+// shares. Use lookupFunction for anything that can differ between them, like the parameters or implementation.
 var functionMap = make(map[string]Registration)
+
+// functionVariants holds every registration of each function, in the order they were registered. A function is
+// registered more than once when it needs a different schema or implementation depending on the device's
+// capabilities, e.g. so that older watch apps aren't offered parameters they can't handle.
+var functionVariants = make(map[string][]Registration)
 var functionAliases = make(map[string]string)
 
 // registerFunction registers a function for later use by GetFunctionDefinitions and CallFunction.
@@ -71,38 +108,99 @@ func registerFunction(reg Registration) {
 	if !strings.Contains("ABCDEFGHIJKLMNOPQRSTUVWXYZ", reflect.TypeOf(reg.InputType).Name()[0:1]) {
 		panic(fmt.Sprintf("input type %T must be exported.", reg.InputType))
 	}
-	functionMap[reg.Definition.Name] = reg
+	if _, ok := functionMap[reg.Definition.Name]; !ok {
+		functionMap[reg.Definition.Name] = reg
+	}
+	functionVariants[reg.Definition.Name] = append(functionVariants[reg.Definition.Name], reg)
 	for _, alias := range reg.Aliases {
 		functionAliases[alias] = reg.Definition.Name
 	}
 }
 
-func IsAction(fn string) bool {
+// lookupFunction returns the registration of fn that applies to the session in ctx: the one in its Registry, if it
+// has one, or otherwise the first the device has the capabilities for.
+func lookupFunction(ctx context.Context, fn string) (Registration, bool) {
+	if r := registryFromContext(ctx); r != nil {
+		reg, ok := r.functions[fn]
+		return reg, ok
+	}
+	capabilities := query.SupportedActionsFromContext(ctx)
+	for _, reg := range functionVariants[fn] {
+		if hasCapabilities(reg, capabilities) {
+			return reg, true
+		}
+	}
+	return Registration{}, false
+}
+
+func IsAction(ctx context.Context, fn string) bool {
 	if realFunction, ok := functionAliases[fn]; ok {
 		fn = realFunction
 	}
-	if _, ok := functionMap[fn]; !ok {
-		return false
+	reg, ok := lookupFunction(ctx, fn)
+	return ok && reg.Cb != nil
+}
+
+// decodeArgs decodes the model's arguments for reg into a pointer to a new value of its InputType, after fixing up
+// any expressions in them (see FixupBrokenJson). If they don't fit the function's schema, problem is what to tell the
+// model instead.
+func decodeArgs(reg Registration, args string) (in any, fixedArgs string, problem any) {
+	in = reflect.New(reflect.TypeOf(reg.InputType)).Interface()
+	fixedArgs = FixupBrokenJson(args)
+	if verr := validateArgs(reg.Definition.Name, reg.Definition.Parameters, fixedArgs); verr != nil {
+		return nil, fixedArgs, verr
+	}
+	if err := json.Unmarshal([]byte(fixedArgs), in); err != nil {
+		return nil, fixedArgs, Error{"Invalid JSON: " + err.Error()}
 	}
-	return functionMap[fn].Cb != nil
+	return in, fixedArgs, nil
 }
 
 // CallFunction calls a function by name with the given arguments. The arguments are expected to be
 // a string containing a JSON object (presumably from GPT). The result is returned as a JSON string.
 func CallFunction(ctx context.Context, qt *quota.Tracker, fn, args string) (string, error) {
 	if realFunction, ok := functionAliases[fn]; ok {
-		log.Printf("Model asked for function %q, which is an alias for %q.\n", fn, realFunction)
+		requestid.Logf(ctx, "Model asked for function %q, which is an alias for %q.\n", fn, realFunction)
 		fn = realFunction
 	}
-	if _, ok := functionMap[fn]; !ok || functionMap[fn].Fn == nil {
-		return "", fmt.Errorf("function %q not found", fn)
+	reg, ok := lookupFunction(ctx, fn)
+	if !ok || reg.Fn == nil || !availableInSession(ctx, fn) {
+		return unknownTool(ctx, fn), nil
+	}
+	if over := CallLimitsFromContext(ctx).count(ctx, fn); over != nil {
+		r, _ := json.Marshal(over)
+		return string(r), nil
+	}
+	memoizable := reusable(fn)
+	if memoizable {
+		if result, ok := memoized(ctx, fn, args); ok {
+			requestid.Logf(ctx, "Model repeated a call to %q, using the earlier result.\n", fn)
+			return result, nil
+		}
+		if stored, ok := freshResult(ctx, fn, args); ok {
+			requestid.Logf(ctx, "Model repeated a call to %q from an earlier turn, using the result from %s.\n", fn, describeAge(time.Since(stored.FetchedAt)))
+			return stored.Result, nil
+		}
 	}
 	var result any
-	in := reflect.New(reflect.TypeOf(functionMap[fn].InputType)).Interface()
-	if err := json.Unmarshal([]byte(FixupBrokenJson(args)), in); err != nil {
-		result = Error{"Invalid JSON: " + err.Error()}
+	pending := false
+	in, fixedArgs, problem := decodeArgs(reg, args)
+	if problem != nil {
+		checkWrongTool(ctx, fn, fixedArgs, problem)
+		result = problem
+	} else if IsSandboxed(ctx) && reg.SideEffects {
+		result = simulateFunction(ctx, fn, in)
 	} else {
-		result = functionMap[fn].Fn(ctx, qt, in)
+		call := func() any { return callSafely(ctx, fn, func() any { return reg.Fn(ctx, qt, in) }) }
+		run := call
+		if !reg.SideEffects {
+			run = func() any { return callWithRetry(ctx, fn, call) }
+		}
+		var finished bool
+		result, finished = callWithinBudget(ctx, fn, args, run)
+		// The real result is kept once it arrives; see PendingCalls.Wait.
+		memoizable = memoizable && finished
+		pending = !finished
 	}
 	r, err := json.Marshal(result)
 	if err != nil {
@@ -111,53 +209,69 @@ func CallFunction(ctx context.Context, qt *quota.Tracker, fn, args string) (stri
 	//if len(r) > MaxResponseSize {
 	//	r = r[:MaxResponseSize]
 	//}
+	if memoizable {
+		memoize(ctx, fn, args, string(r))
+		recordResult(ctx, fn, args, string(r))
+	}
+	recordAudit(ctx, fn, fixedArgs, string(r), pending)
 	return string(r), nil
 }
 
-func CallAction(ctx context.Context, qt *quota.Tracker, fn, args string, ws *websocket.Conn) (string, error) {
+func CallAction(ctx context.Context, qt *quota.Tracker, fn, args string, ws *wire.Conn) (string, error) {
 	if realFunction, ok := functionAliases[fn]; ok {
-		log.Printf("Model asked for action %q, which is an alias for %q.\n", fn, realFunction)
+		requestid.Logf(ctx, "Model asked for action %q, which is an alias for %q.\n", fn, realFunction)
 		fn = realFunction
 	}
-	if _, ok := functionMap[fn]; !ok || functionMap[fn].Cb == nil {
-		return "", fmt.Errorf("function %q not found", fn)
+	reg, ok := lookupFunction(ctx, fn)
+	if !ok || reg.Cb == nil || !availableInSession(ctx, fn) {
+		return unknownTool(ctx, fn), nil
+	}
+	if over := CallLimitsFromContext(ctx).count(ctx, fn); over != nil {
+		r, _ := json.Marshal(over)
+		return string(r), nil
 	}
-	a := reflect.New(reflect.TypeOf(functionMap[fn].InputType)).Interface()
 	var result any
-	if err := json.Unmarshal([]byte(FixupBrokenJson(args)), &a); err != nil {
-		result = Error{"Invalid JSON: " + err.Error()}
+	a, fixedArgs, problem := decodeArgs(reg, args)
+	if problem != nil {
+		checkWrongTool(ctx, fn, fixedArgs, problem)
+		result = problem
 	} else {
 		reqChan := make(chan map[string]any)
 		respChan := make(chan map[string]any)
 		defer close(reqChan)
 		defer close(respChan)
 		ctxTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+		sandboxed := IsSandboxed(ctx) && reg.SideEffects
 		go func() {
 			defer cancel()
 			for req := range reqChan {
+				if sandboxed {
+					respChan <- simulateWatchRequest(ctx, fn, req)
+					continue
+				}
 				s, err := json.Marshal(req)
 				if err != nil {
-					log.Printf("unable to marshal request: %v", err)
+					requestid.Logf(ctx, "unable to marshal request: %v", err)
 					respChan <- map[string]any{
 						"status": "error",
 						"error":  "unable to marshal request: " + err.Error(),
 					}
 					continue
 				}
-				log.Println("Sending request to watch...")
+				requestid.Logln(ctx, "Sending request to watch...")
 				if err := ws.Write(ctxTimeout, websocket.MessageText, append([]byte("a"), s...)); err != nil {
-					log.Printf("unable to write request: %v", err)
+					requestid.Logf(ctx, "unable to write request: %v", err)
 					respChan <- map[string]any{
 						"status": "error",
 						"error":  "unable to write request: " + err.Error(),
 					}
 					continue
 				}
-				log.Println("Reading response from watch...")
+				requestid.Logln(ctx, "Reading response from watch...")
 				messageType, respBytes, err := ws.Read(ctxTimeout)
-				log.Printf("response read: %v", string(respBytes))
+				requestid.Logf(ctx, "response read: %v", string(respBytes))
 				if err != nil {
-					log.Printf("unable to read response: %v", err)
+					requestid.Logf(ctx, "unable to read response: %v", err)
 					respChan <- map[string]any{
 						"status": "error",
 						"error":  "unable to read response: " + err.Error(),
@@ -165,7 +279,7 @@ func CallAction(ctx context.Context, qt *quota.Tracker, fn, args string, ws *web
 					continue
 				}
 				if messageType != websocket.MessageText {
-					log.Printf("unexpected message type: %v", messageType)
+					requestid.Logf(ctx, "unexpected message type: %v", messageType)
 					respChan <- map[string]any{
 						"status": "error",
 						"error":  "unable to read response: " + err.Error(),
@@ -174,7 +288,7 @@ func CallAction(ctx context.Context, qt *quota.Tracker, fn, args string, ws *web
 				}
 				var resp map[string]any
 				if err := json.Unmarshal(respBytes, &resp); err != nil {
-					log.Printf("unable to unmarshal response: %v", err)
+					requestid.Logf(ctx, "unable to unmarshal response: %v", err)
 					respChan <- map[string]any{
 						"status": "error",
 						"error":  "unable to unmarshal response: " + err.Error(),
@@ -184,7 +298,7 @@ func CallAction(ctx context.Context, qt *quota.Tracker, fn, args string, ws *web
 				respChan <- resp
 			}
 		}()
-		result = functionMap[fn].Cb(ctx, qt, a, reqChan, respChan)
+		result = callSafely(ctx, fn, func() any { return reg.Cb(ctx, qt, a, reqChan, respChan) })
 	}
 	r, err := json.Marshal(result)
 	if err != nil {
@@ -193,46 +307,94 @@ func CallAction(ctx context.Context, qt *quota.Tracker, fn, args string, ws *web
 	//if len(r) > MaxResponseSize {
 	//	r = r[:MaxResponseSize]
 	//}
+	recordAudit(ctx, fn, fixedArgs, string(r), false)
 	return string(r), nil
 }
 
-func SummariseFunction(fn, args string) string {
+// callSafely calls a function implementation, turning any panic into an error the model can see, so that one broken
+// function doesn't take down the whole session.
+func callSafely(ctx context.Context, fn string, call func() any) (result any) {
+	defer func() {
+		if r := recover(); r != nil {
+			requestid.Logf(ctx, "function %q panicked: %v\n%s", fn, r, debug.Stack())
+			result = Error{fmt.Sprintf("Internal error in %s.", fn)}
+		}
+	}()
+	return call()
+}
+
+func SummariseFunction(ctx context.Context, fn, args string) string {
 	if realFunction, ok := functionAliases[fn]; ok {
 		fn = realFunction
 	}
 	if _, ok := functionMap[fn]; !ok {
-		return "Bobby is slightly lost"
+		return i18n.T(ctx, "thought.lost")
 	}
 	a := reflect.New(reflect.TypeOf(functionMap[fn].InputType)).Interface()
 	if err := json.Unmarshal([]byte(FixupBrokenJson(args)), &a); err != nil {
-		return "Bobby is doing the wrong thing"
+		return i18n.T(ctx, "thought.confused")
 	} else {
-		return functionMap[fn].Thought(a)
+		return functionMap[fn].Thought(ctx, a)
 	}
 }
 
 func GetFunctionDefinitionsByCapability() map[string][]genai.FunctionDeclaration {
 	definitions := map[string][]genai.FunctionDeclaration{}
-	for _, reg := range functionMap {
-		if _, ok := definitions[reg.Capability]; !ok {
-			definitions[reg.Capability] = []genai.FunctionDeclaration{}
+	for _, variants := range functionVariants {
+		for _, reg := range variants {
+			if _, ok := definitions[reg.Capability]; !ok {
+				definitions[reg.Capability] = []genai.FunctionDeclaration{}
+			}
+			definitions[reg.Capability] = append(definitions[reg.Capability], reg.Definition)
 		}
-		definitions[reg.Capability] = append(definitions[reg.Capability], reg.Definition)
 	}
 	return definitions
 }
 
 func GetFunctionDefinitionsForCapabilities(capabilities []string) []*genai.FunctionDeclaration {
 	var definitions []*genai.FunctionDeclaration
-	for _, reg := range functionMap {
-		if (reg.Capability == "" || slices.Contains(capabilities, reg.Capability)) &&
-			(reg.AntiCapability == "" || !slices.Contains(capabilities, reg.AntiCapability)) {
-			d := reg.Definition
-			definitions = append(definitions, &d)
+	for _, variants := range functionVariants {
+		for _, reg := range variants {
+			if hasCapabilities(reg, capabilities) {
+				d := reg.Definition
+				definitions = append(definitions, &d)
+				break
+			}
+		}
+	}
+	return definitions
+}
+
+// GetFunctionDefinitionsForContext returns the function definitions appropriate for the session in ctx: those in its
+// Registry, if it has one, or otherwise those the device has the capabilities for, less any that the session is not
+// allowed to use.
+func GetFunctionDefinitionsForContext(ctx context.Context) []*genai.FunctionDeclaration {
+	if r := registryFromContext(ctx); r != nil {
+		return r.Definitions()
+	}
+	capabilities := query.SupportedActionsFromContext(ctx)
+	var definitions []*genai.FunctionDeclaration
+	for _, d := range GetFunctionDefinitionsForCapabilities(capabilities) {
+		if featureEnabled(functionMap[d.Name]) && isAllowed(ctx, functionMap[d.Name]) {
+			definitions = append(definitions, d)
 		}
 	}
 	return definitions
 }
+
+// isAllowed reports whether the session in ctx may call the given function. This is checked both when building the
+// list of functions to offer the model, and again when the model tries to call one, in case it calls a function it
+// wasn't offered.
+func isAllowed(ctx context.Context, reg Registration) bool {
+	if reg.HiddenInKidMode && query.IsKidMode(ctx) {
+		return false
+	}
+	if IsOffline(ctx) && !reg.Offline {
+		return false
+	}
+	return authz.DecisionFromContext(ctx).Permits(reg.Definition.Name)
+}
+
 func GetFunctionRegistration(fn string) *Registration {
 	if realFunction, ok := functionAliases[fn]; ok {
 		fn = realFunction
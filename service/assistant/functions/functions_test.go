@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// offeredFunctions returns the names of the functions offered to the model in ctx, sorted.
+func offeredFunctions(ctx context.Context) []string {
+	var names []string
+	for _, d := range GetFunctionDefinitionsForContext(ctx) {
+		names = append(names, d.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// allCapabilities returns every capability a function can need, as the watch would send them.
+func allCapabilities() string {
+	var capabilities []string
+	for c := range GetFunctionDefinitionsByCapability() {
+		if c != "" {
+			capabilities = append(capabilities, c)
+		}
+	}
+	return strings.Join(capabilities, ",")
+}
+
+// TestKidModeFunctions lists everything a child can have Bobby do, so that adding a function means deciding whether
+// it belongs here.
+func TestKidModeFunctions(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"profile": {query.ProfileKid}, "actions": {allCapabilities()}})
+	want := []string{
+		"add_to_glossary", "convert_currency", "create_flashcard", "define_word", "delete_alarm", "delete_reminder",
+		"delete_timer", "end_game", "get_alarms", "get_country_info", "get_distance", "get_earthquakes", "get_holidays",
+		"get_location", "get_pinned", "get_prayer_times", "get_religious_calendar", "get_reminders",
+		"get_sports_fixtures", "get_stopwatch", "get_time_elsewhere", "get_timers", "get_weather", "lap_stopwatch",
+		"lookup_barcode", "lua", "quiz_me", "random", "record_game_round", "remove_from_glossary", "resume_reading",
+		"set_alarm", "set_interval_timer", "set_reminder", "set_timer", "set_verbosity", "spell", "split_bill",
+		"start_game", "start_stopwatch", "stop_interval_timer", "stop_stopwatch", "suggest_wake_time", "time_since",
+		"track_date", "untrack_date", "write_pages",
+	}
+	if got := offeredFunctions(ctx); !slices.Equal(got, want) {
+		t.Errorf("functions offered in kid mode = %q, want %q", got, want)
+	}
+}
+
+// TestOfflineFunctions lists everything the local model can do without the internet, so that adding a function means
+// deciding whether it works offline.
+func TestOfflineFunctions(t *testing.T) {
+	ctx := WithOffline(query.ContextWith(context.Background(), url.Values{"actions": {allCapabilities()}}))
+	want := []string{
+		"add_to_glossary", "create_flashcard", "delete_alarm", "delete_reminder", "delete_timer", "end_game",
+		"get_alarms", "get_country_info", "get_pinned", "get_religious_calendar", "get_reminders", "get_stopwatch",
+		"get_timers", "lap_stopwatch", "lua", "quiz_me", "random", "record_game_round", "remove_from_glossary",
+		"set_alarm", "set_interval_timer", "set_reminder", "set_timer", "set_verbosity", "spell", "split_bill",
+		"start_game", "start_stopwatch", "stop_interval_timer", "stop_stopwatch", "time_since", "track_date",
+		"untrack_date",
+	}
+	if got := offeredFunctions(ctx); !slices.Equal(got, want) {
+		t.Errorf("functions offered offline = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/game"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+)
+
+type StartGameInput struct {
+	// Which game to play.
+	Kind string `json:"kind"`
+	// The answer for the first round, which the user mustn't be told.
+	Secret string `json:"secret"`
+}
+
+type RecordGameRoundInput struct {
+	// How many points the user scored in the round.
+	Points int `json:"points"`
+	// The answer for the next round, if it has one.
+	NextSecret string `json:"next_secret"`
+}
+
+type EndGameInput struct {
+	// No parameters needed
+}
+
+type gameResult struct {
+	Game      string `json:"game"`
+	Score     int    `json:"score"`
+	Round     int    `json:"rounds_played"`
+	MaxRounds int    `json:"max_rounds"`
+	Over      bool   `json:"over,omitempty"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "start_game",
+			Description: "Start playing a game with the user: twenty questions (you think of something and they guess it), trivia, or a word game. The score and rounds are kept for you until the game ends.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"kind": {
+						Type:        genai.TypeString,
+						Description: "Which game to play.",
+						Nullable:    false,
+						Enum:        game.Kinds,
+					},
+					"secret": {
+						Type:        genai.TypeString,
+						Description: "The answer for the first round, which you must keep from the user: the thing to guess in twenty questions, or the answer to the first trivia question or word puzzle.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"kind"},
+			},
+		},
+		Fn:          startGame,
+		SideEffects: true,
+		Thought:     gameThought,
+		InputType:   StartGameInput{},
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "record_game_round",
+			Description: "Record the end of a round of the game being played, and how many points the user scored in it. Returns the score so far, and whether that was the last round.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"points": {
+						Type:        genai.TypeInteger,
+						Description: "How many points the user scored in the round: usually 1 for a right answer and 0 for a wrong one. In twenty questions, a round is one question, and guessing the answer scores 1.",
+						Nullable:    false,
+						Minimum:     genai.Ptr(0.0),
+						Maximum:     genai.Ptr(10.0),
+					},
+					"next_secret": {
+						Type:        genai.TypeString,
+						Description: "The answer for the next round, if the game has one for each round, which you must keep from the user.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"points"},
+			},
+		},
+		Fn:          recordGameRound,
+		SideEffects: true,
+		Thought:     gameThought,
+		InputType:   RecordGameRoundInput{},
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "end_game",
+			Description: "End the game being played, returning the final score.",
+		},
+		Fn:          endGame,
+		SideEffects: true,
+		Thought:     gameThought,
+		InputType:   EndGameInput{},
+		Offline:     true,
+	})
+}
+
+func startGame(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "start_game")
+	defer span.Send()
+	arg := args.(*StartGameInput)
+	session := game.FromContext(ctx)
+	if session == nil {
+		return Error{Error: "Games can't be played here."}
+	}
+	st, err := session.Start(game.Kind(arg.Kind), arg.Secret)
+	if errors.Is(err, game.ErrUnknownKind) {
+		return Error{Error: "There's no game called " + arg.Kind + "."}
+	}
+	span.AddField("game", st.Kind)
+	return describeGame(st)
+}
+
+func recordGameRound(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "record_game_round")
+	defer span.Send()
+	arg := args.(*RecordGameRoundInput)
+	session := game.FromContext(ctx)
+	if session == nil {
+		return Error{Error: "Games can't be played here."}
+	}
+	st, err := session.Record(arg.Points, arg.NextSecret)
+	if errors.Is(err, game.ErrNoGame) {
+		return Error{Error: "No game is being played. Call start_game first."}
+	}
+	span.AddField("round", st.Round)
+	return describeGame(st)
+}
+
+func endGame(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "end_game")
+	defer span.Send()
+	session := game.FromContext(ctx)
+	if session == nil {
+		return Error{Error: "Games can't be played here."}
+	}
+	st, ok := session.End()
+	if !ok {
+		return Error{Error: "No game is being played."}
+	}
+	span.AddField("score", st.Score)
+	result := describeGame(st)
+	result.Over = true
+	return result
+}
+
+func describeGame(st game.State) gameResult {
+	return gameResult{
+		Game:      string(st.Kind),
+		Score:     st.Score,
+		Round:     st.Round,
+		MaxRounds: st.MaxRounds,
+		Over:      st.Over(),
+	}
+}
+
+func gameThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.game")
+}
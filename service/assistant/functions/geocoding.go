@@ -0,0 +1,40 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"errors"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+)
+
+// LocationNotFound is returned instead of Error when geocoding fails but we have some idea of what the user might
+// have meant, so the model can ask "did you mean...?" rather than just apologising.
+type LocationNotFound struct {
+	Error       string   `json:"error"`
+	Suggestions []string `json:"did_you_mean,omitempty"`
+}
+
+// geocodingError turns an error from photon.GeocodeWithContext into something suitable to return to the model.
+func geocodingError(err error) any {
+	var notFound *photon.NotFoundError
+	if errors.As(err, &notFound) && len(notFound.Suggestions) > 0 {
+		return LocationNotFound{
+			Error:       "Couldn't find a place called " + notFound.Search + ". If one of the suggestions seems likely, ask the user to confirm it.",
+			Suggestions: notFound.Suggestions,
+		}
+	}
+	return upstreamError("Error finding location: ", err)
+}
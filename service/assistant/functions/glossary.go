@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/glossary"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+type AddToGlossaryInput struct {
+	// The word or name, spelt correctly.
+	Term string `json:"term"`
+	// What it means.
+	Meaning string `json:"meaning"`
+	// What it tends to be misheard as.
+	MisheardAs []string `json:"misheard_as"`
+}
+
+type RemoveFromGlossaryInput struct {
+	// The word or name to forget.
+	Term string `json:"term"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "add_to_glossary",
+			Description: "Remember a word the user uses that you wouldn't otherwise know, like the name of someone in their family or a pet, local slang, or jargon from work, so you spell and understand it correctly in future. Use this when the user tells you about such a word, or corrects how you heard one.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"term": {
+						Type:        genai.TypeString,
+						Description: "The word or name, spelt correctly, e.g. \"Siobhan\".",
+						Nullable:    false,
+					},
+					"meaning": {
+						Type:        genai.TypeString,
+						Description: "What it means or who it is, e.g. \"the user's daughter\".",
+						Nullable:    true,
+					},
+					"misheard_as": {
+						Type:        genai.TypeArray,
+						Description: "What the user's speech tends to be transcribed as instead, e.g. [\"shivawn\", \"she von\"]. These are corrected automatically in future.",
+						Nullable:    true,
+						Items: &genai.Schema{
+							Type: genai.TypeString,
+						},
+					},
+				},
+				Required: []string{"term"},
+			},
+		},
+		Fn:          addToGlossary,
+		SideEffects: true,
+		Thought:     glossaryThought,
+		InputType:   AddToGlossaryInput{},
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "remove_from_glossary",
+			Description: "Forget a word you were asked to remember with add_to_glossary.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"term": {
+						Type:        genai.TypeString,
+						Description: "The word to forget, as it was remembered.",
+						Nullable:    false,
+					},
+				},
+				Required: []string{"term"},
+			},
+		},
+		Fn:          removeFromGlossary,
+		SideEffects: true,
+		Thought:     glossaryThought,
+		InputType:   RemoveFromGlossaryInput{},
+		Offline:     true,
+	})
+}
+
+func addToGlossary(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "add_to_glossary")
+	defer span.Send()
+	arg := args.(*AddToGlossaryInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok || requester.IsScheduled() {
+		return Error{Error: "The glossary can't be changed from here."}
+	}
+	term := glossary.Term{Term: arg.Term, Meaning: arg.Meaning, MisheardAs: arg.MisheardAs}
+	if err := glossary.Put(ctx, storage.GetRedis(), requester.UserID, term); err != nil {
+		switch {
+		case errors.Is(err, glossary.ErrTooMany):
+			return Error{Error: fmt.Sprintf("The user's glossary already has %d words, which is the most it can hold. They need to remove one first.", glossary.MaxTerms)}
+		case errors.Is(err, glossary.ErrInvalid):
+			return Error{Error: err.Error()}
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Adding glossary term failed: %v", err)
+		return Error{Error: "Remembering the word failed."}
+	}
+	return map[string]any{"status": "ok"}
+}
+
+func removeFromGlossary(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "remove_from_glossary")
+	defer span.Send()
+	arg := args.(*RemoveFromGlossaryInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok || requester.IsScheduled() {
+		return Error{Error: "The glossary can't be changed from here."}
+	}
+	removed, err := glossary.Remove(ctx, storage.GetRedis(), requester.UserID, arg.Term)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Removing glossary term failed: %v", err)
+		return Error{Error: "Forgetting the word failed."}
+	}
+	if !removed {
+		return Error{Error: fmt.Sprintf("%q isn't in the user's glossary.", arg.Term)}
+	}
+	return map[string]any{"status": "ok"}
+}
+
+func glossaryThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.glossary")
+}
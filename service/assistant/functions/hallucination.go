@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/analytics"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// Kinds of hallucinated call, as recorded on the span and in analytics.
+const (
+	// The model called a function that doesn't exist, or isn't available in this session.
+	hallucinatedUnknownTool = "unknown_tool"
+	// The model called a real function with arguments that fit a different one.
+	hallucinatedWrongTool = "wrong_tool"
+)
+
+// UnknownTool is returned to the model when it calls a function it doesn't have, so that it can choose one that it
+// does have rather than the turn failing.
+type UnknownTool struct {
+	Error string `json:"error"`
+	// The functions the model can call in this session.
+	ValidTools []string `json:"valid_tools"`
+}
+
+// unknownTool returns the response to a call to fn, which isn't available in this session.
+func unknownTool(ctx context.Context, fn string) string {
+	requestid.Logf(ctx, "Model called function %q, which isn't available.\n", fn)
+	recordHallucination(ctx, hallucinatedUnknownTool, fn)
+	r, _ := json.Marshal(UnknownTool{
+		Error:      fmt.Sprintf("There is no function called %q. Use one of valid_tools instead, or answer without one.", fn),
+		ValidTools: sessionFunctionNames(ctx),
+	})
+	return string(r)
+}
+
+// sessionFunctionNames returns the names of the functions available in the session in ctx, in order.
+func sessionFunctionNames(ctx context.Context) []string {
+	var names []string
+	for _, d := range GetFunctionDefinitionsForContext(ctx) {
+		names = append(names, d.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// checkWrongTool adds a hint to a ValidationError when the arguments the model gave fn would have fit another
+// function in the session, which usually means it mixed the two up.
+func checkWrongTool(ctx context.Context, fn, args string, problem any) {
+	verr, ok := problem.(*ValidationError)
+	if !ok {
+		return
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(args), &obj); err != nil || len(obj) == 0 {
+		return
+	}
+	for _, name := range sessionFunctionNames(ctx) {
+		reg, _ := lookupFunction(ctx, name)
+		params := reg.Definition.Parameters
+		// A function without any declared parameters would accept anything, which tells us nothing.
+		if name == fn || params == nil || len(params.Properties) == 0 {
+			continue
+		}
+		if validateArgs(name, params, args) == nil {
+			recordHallucination(ctx, hallucinatedWrongTool, fn)
+			verr.Problems = append(verr.Problems, fmt.Sprintf("these arguments are for %s, not %s; call %s instead if that's what you meant", name, fn, name))
+			return
+		}
+	}
+}
+
+// recordHallucination notes that the model made up a call, so that we can see how often it happens.
+func recordHallucination(ctx context.Context, kind, fn string) {
+	// The name is whatever the model made up, so keep it to a sensible length.
+	if len(fn) > 64 {
+		fn = fn[:64]
+	}
+	beeline.AddField(ctx, "hallucinated_call", kind)
+	beeline.AddField(ctx, "hallucinated_function", fn)
+	analytics.Record(ctx, analytics.Event{Kind: analytics.EventToolHallucinated, Tool: fn, Hallucination: kind})
+}
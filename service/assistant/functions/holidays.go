@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/countries"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/holidays"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+)
+
+// maxHolidayRange is the longest span of time we'll list holidays for, to keep the response a sensible size.
+const maxHolidayRange = 366 * 24 * time.Hour
+
+type GetHolidaysInput struct {
+	// The country to look up holidays for. If empty, the user's current country.
+	Country string `json:"country"`
+	// The first date to consider, in YYYY-MM-DD format. Defaults to today.
+	From string `json:"from"`
+	// The last date to consider, in YYYY-MM-DD format. Defaults to 60 days after From.
+	To string `json:"to"`
+}
+
+type HolidaysResponse struct {
+	CountryCode string             `json:"country_code"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Holidays    []holidays.Holiday `json:"holidays"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_holidays",
+			Description: "Get the public holidays in a country within a date range. Use this to answer questions like \"is Monday a bank holiday?\" or \"when is the next public holiday?\". Holidays that apply only to some regions list those regions in 'counties'.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"country": {
+						Type:        genai.TypeString,
+						Description: "The English name or ISO 3166-1 alpha-2 code of the country. Omit to use the country the user is currently in.",
+						Nullable:    true,
+					},
+					"from": {
+						Type:        genai.TypeString,
+						Description: "The first date to include, in YYYY-MM-DD format. Defaults to today.",
+						Nullable:    true,
+					},
+					"to": {
+						Type:        genai.TypeString,
+						Description: "The last date to include, in YYYY-MM-DD format. Defaults to 60 days after 'from'. To check a single day, set 'from' and 'to' to the same date.",
+						Nullable:    true,
+					},
+				},
+			},
+		},
+		Fn:        getHolidays,
+		FreshFor:  24 * time.Hour,
+		Thought:   getHolidaysThought,
+		InputType: GetHolidaysInput{},
+	})
+}
+
+func getHolidaysThought(ctx context.Context, args any) string {
+	arg := args.(*GetHolidaysInput)
+	if arg.Country != "" {
+		return i18n.T(ctx, "thought.holidays.country", thoughtArgument(arg.Country))
+	}
+	return i18n.T(ctx, "thought.holidays")
+}
+
+func getHolidays(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_holidays")
+	defer span.Send()
+	arg := args.(*GetHolidaysInput)
+
+	var countryCode string
+	if arg.Country == "" {
+		var err error
+		countryCode, err = userCountryCode(ctx)
+		if err != nil {
+			span.AddField("error", err)
+			return Error{Error: "Couldn't work out which country the user is in, so a country must be specified: " + err.Error()}
+		}
+	} else if country := countries.Lookup(arg.Country); country != nil {
+		countryCode = country.Code
+	} else if len(arg.Country) == 2 {
+		countryCode = strings.ToUpper(arg.Country)
+	} else {
+		return Error{Error: fmt.Sprintf("Unknown country %q. Try its ISO 3166-1 alpha-2 code instead.", arg.Country)}
+	}
+	span.AddField("country", countryCode)
+
+	loc := time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	from := time.Now().In(loc)
+	if arg.From != "" {
+		var err error
+		from, err = time.ParseInLocation(time.DateOnly, arg.From, loc)
+		if err != nil {
+			return Error{Error: fmt.Sprintf("Invalid 'from' date %q: must be in YYYY-MM-DD format.", arg.From)}
+		}
+	}
+	to := from.AddDate(0, 0, 60)
+	if arg.To != "" {
+		var err error
+		to, err = time.ParseInLocation(time.DateOnly, arg.To, loc)
+		if err != nil {
+			return Error{Error: fmt.Sprintf("Invalid 'to' date %q: must be in YYYY-MM-DD format.", arg.To)}
+		}
+	}
+	if to.Before(from) {
+		return Error{Error: "'to' must not be before 'from'."}
+	}
+	if to.Sub(from) > maxHolidayRange {
+		return Error{Error: "The date range can be at most a year long."}
+	}
+
+	result, err := holidays.GetHolidayDataManager().GetHolidaysBetween(ctx, countryCode, from, to)
+	if err != nil {
+		span.AddField("error", err)
+		if errors.Is(err, holidays.ErrUnknownCountry) {
+			return Error{Error: fmt.Sprintf("No holiday data is available for %s.", countryCode)}
+		}
+		return upstreamError("Couldn't look up holidays: ", err)
+	}
+	return HolidaysResponse{
+		CountryCode: countryCode,
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		Holidays:    result,
+	}
+}
+
+// userCountryCode returns the ISO code of the country the user is currently in, based on their location.
+func userCountryCode(ctx context.Context) (string, error) {
+	location := query.CoarseLocationFromContext(ctx)
+	if location == nil {
+		return "", errors.New("the user's location is not available")
+	}
+	feature, err := photon.ReverseGeocode(ctx, location.Lon, location.Lat)
+	if err != nil {
+		return "", err
+	}
+	if feature.Properties.CountryCode == "" {
+		return "", errors.New("the user's location is not in a known country")
+	}
+	return strings.ToUpper(feature.Properties.CountryCode), nil
+}
+
+// holidayOn returns the public holiday falling on the given date in the user's country, if there is one. Errors are
+// swallowed, since this is only ever used to add helpful warnings.
+func holidayOn(ctx context.Context, t time.Time) *holidays.Holiday {
+	countryCode, err := userCountryCode(ctx)
+	if err != nil {
+		return nil
+	}
+	result, err := holidays.GetHolidayDataManager().GetHolidaysBetween(ctx, countryCode, t, t)
+	if err != nil || len(result) == 0 {
+		return nil
+	}
+	return &result[0]
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+const (
+	maxIntervalRounds       = 99
+	minIntervalPhaseSeconds = 5
+	// The watch stores each phase's length in 16 bits, but nobody needs a phase this long anyway.
+	maxIntervalPhaseSeconds = 4 * 60 * 60
+)
+
+type IntervalTimerInput struct {
+	// How long each round of work lasts, in seconds.
+	WorkSeconds int `json:"work_seconds"`
+	// How long to rest between rounds, in seconds. Zero for no rest.
+	RestSeconds int `json:"rest_seconds"`
+	// How many rounds of work there are.
+	Rounds int `json:"rounds"`
+	// A longer rest taken instead of the usual one after every LongRestEvery rounds, as in pomodoro.
+	LongRestSeconds int `json:"long_rest_seconds"`
+	LongRestEvery   int `json:"long_rest_every"`
+	// An optional name for the interval timer.
+	Name string `json:"name"`
+}
+
+type StopIntervalTimerInput struct{}
+
+// intervalPhase is one step of an interval timer, as the watch runs it.
+type intervalPhase struct {
+	// "work", "rest" or "long_rest".
+	Kind    string `json:"kind"`
+	Seconds int    `json:"seconds"`
+}
+
+type IntervalTimerResponse struct {
+	Status string `json:"status"`
+	Rounds int    `json:"rounds"`
+	// How long the whole thing takes, including rests.
+	TotalSeconds int `json:"total_seconds"`
+	// When it finishes, in the user's time zone.
+	EndsAt string `json:"ends_at"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "set_interval_timer",
+			Description: "Start an interval timer on the watch, which alternates between work and rest for a number of rounds and buzzes at each change, e.g. for HIIT (\"40 seconds on, 20 off, 8 rounds\") or pomodoro (25 minutes of work, 5 minutes' rest, and a 15 minute rest every 4 rounds). The watch shows which phase it's in while it runs. Only one interval timer runs at once; starting another replaces it. For a single countdown, use set_timer instead.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"work_seconds": {
+						Type:        genai.TypeInteger,
+						Description: "How long each round of work lasts, in seconds.",
+						Nullable:    false,
+						Format:      "int32",
+					},
+					"rest_seconds": {
+						Type:        genai.TypeInteger,
+						Description: "How long to rest between rounds, in seconds. Use 0 for no rest.",
+						Nullable:    false,
+						Format:      "int32",
+					},
+					"rounds": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("How many rounds of work there are, at most %d. There's no rest after the last one.", maxIntervalRounds),
+						Nullable:    false,
+						Format:      "int32",
+					},
+					"long_rest_seconds": {
+						Type:        genai.TypeInteger,
+						Description: "Only if the user wants one, e.g. for pomodoro: how long a longer rest, taken every long_rest_every rounds instead of the usual rest, lasts in seconds.",
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"long_rest_every": {
+						Type:        genai.TypeInteger,
+						Description: "How many rounds to do between long rests, e.g. 4 for pomodoro. Required if long_rest_seconds is given.",
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"name": {
+						Type:        genai.TypeString,
+						Description: "Only if explicitly specified by the user, the name of the interval timer, e.g. \"Tabata\". Use title case.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"work_seconds", "rest_seconds", "rounds"},
+			},
+		},
+		Cb:          intervalTimerImpl,
+		SideEffects: true,
+		Thought:     intervalTimerThought,
+		InputType:   IntervalTimerInput{},
+		Capability:  "set_interval_timer",
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "stop_interval_timer",
+			Description: "Stop the interval timer that's running on the watch.",
+		},
+		Cb:          stopIntervalTimerImpl,
+		SideEffects: true,
+		Thought:     stopIntervalTimerThought,
+		InputType:   StopIntervalTimerInput{},
+		Capability:  "set_interval_timer",
+		Offline:     true,
+	})
+}
+
+// intervalPhases compiles an interval timer into the sequence of phases the watch runs.
+func intervalPhases(input *IntervalTimerInput) ([]intervalPhase, error) {
+	if input.Rounds < 1 || input.Rounds > maxIntervalRounds {
+		return nil, fmt.Errorf("rounds must be between 1 and %d", maxIntervalRounds)
+	}
+	checkLength := func(name string, seconds int, optional bool) error {
+		if optional && seconds == 0 {
+			return nil
+		}
+		if seconds < minIntervalPhaseSeconds || seconds > maxIntervalPhaseSeconds {
+			return fmt.Errorf("%s must be between %d and %d seconds", name, minIntervalPhaseSeconds, maxIntervalPhaseSeconds)
+		}
+		return nil
+	}
+	if err := checkLength("work_seconds", input.WorkSeconds, false); err != nil {
+		return nil, err
+	}
+	if err := checkLength("rest_seconds", input.RestSeconds, true); err != nil {
+		return nil, err
+	}
+	if err := checkLength("long_rest_seconds", input.LongRestSeconds, true); err != nil {
+		return nil, err
+	}
+	if input.LongRestSeconds > 0 && input.LongRestEvery < 1 {
+		return nil, fmt.Errorf("long_rest_every must be given with long_rest_seconds")
+	}
+	var phases []intervalPhase
+	for round := 1; round <= input.Rounds; round++ {
+		phases = append(phases, intervalPhase{Kind: "work", Seconds: input.WorkSeconds})
+		if round == input.Rounds {
+			break
+		}
+		if input.LongRestSeconds > 0 && round%input.LongRestEvery == 0 {
+			phases = append(phases, intervalPhase{Kind: "long_rest", Seconds: input.LongRestSeconds})
+		} else if input.RestSeconds > 0 {
+			phases = append(phases, intervalPhase{Kind: "rest", Seconds: input.RestSeconds})
+		}
+	}
+	return phases, nil
+}
+
+func intervalTimerImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
+	ctx, span := beeline.StartSpan(ctx, "set_interval_timer")
+	defer span.Send()
+	if !query.SupportsAction(ctx, "set_interval_timer") {
+		return Error{Error: "You need to update the app on your watch to set interval timers."}
+	}
+	input := args.(*IntervalTimerInput)
+	phases, err := intervalPhases(input)
+	if err != nil {
+		return Error{Error: err.Error()}
+	}
+	total := 0
+	for _, p := range phases {
+		total += p.Seconds
+	}
+	span.AddField("rounds", input.Rounds)
+	span.AddField("total_seconds", total)
+	requestid.Logf(ctx, "Asking watch to run an interval timer of %d phases...\n", len(phases))
+	requests <- map[string]any{
+		"action": "set_interval_timer",
+		"name":   input.Name,
+		"phases": phases,
+		"cancel": false,
+	}
+	requestid.Logln(ctx, "Waiting for confirmation...")
+	resp := <-responses
+	if _, failed := resp["error"]; failed {
+		return resp
+	}
+	return IntervalTimerResponse{
+		Status:       "ok",
+		Rounds:       input.Rounds,
+		TotalSeconds: total,
+		EndsAt:       time.Now().Add(time.Duration(total) * time.Second).In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)).Format(time.RFC3339),
+	}
+}
+
+func stopIntervalTimerImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
+	ctx, span := beeline.StartSpan(ctx, "stop_interval_timer")
+	defer span.Send()
+	if !query.SupportsAction(ctx, "set_interval_timer") {
+		return Error{Error: "You need to update the app on your watch to use interval timers."}
+	}
+	requestid.Logln(ctx, "Asking watch to stop the interval timer...")
+	requests <- map[string]any{
+		"action": "set_interval_timer",
+		"cancel": true,
+	}
+	requestid.Logln(ctx, "Waiting for confirmation...")
+	return <-responses
+}
+
+func intervalTimerThought(ctx context.Context, i any) string {
+	args := i.(*IntervalTimerInput)
+	if args.Name != "" {
+		return i18n.T(ctx, "thought.interval_timer.set.named", thoughtArgument(args.Name))
+	}
+	return i18n.T(ctx, "thought.interval_timer.set")
+}
+
+func stopIntervalTimerThought(ctx context.Context, i any) string {
+	return i18n.T(ctx, "thought.interval_timer.stop")
+}
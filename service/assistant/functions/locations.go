@@ -2,8 +2,9 @@ package functions
 
 import (
 	"context"
-	"fmt"
+	"time"
 
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
 	"github.com/umahmood/haversine"
@@ -14,6 +15,7 @@ import (
 )
 
 type LocationResponse struct {
+	Name               string  `json:"name,omitempty"`
 	Latitude           float64 `json:"latitude"`
 	Longitude          float64 `json:"longitude"`
 	DistanceKilometers float64 `json:"distance_meters,omitempty"`
@@ -44,26 +46,29 @@ func init() {
 			},
 		},
 		Fn:        getLocationImpl,
+		FreshFor:  10 * time.Minute,
 		Thought:   getLocationThought,
 		InputType: GetLocationInput{},
 	})
 }
 
-func getLocationThought(args any) string {
+func getLocationThought(ctx context.Context, args any) string {
 	arg := args.(*GetLocationInput)
-	return fmt.Sprintf("Locating %q", arg.PlaceName)
+	return i18n.T(ctx, "thought.location", thoughtArgument(arg.PlaceName))
 }
 
 func getLocationImpl(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 	ctx, span := beeline.StartSpan(ctx, "get_location")
 	defer span.Send()
 	arg := args.(*GetLocationInput)
-	location, err := photon.GeocodeWithContext(ctx, arg.PlaceName)
+	location, err := photon.GeocodeWithContext(ctx, arg.PlaceName, photon.AnyPlace)
 	if err != nil {
-		return fmt.Errorf("failed to geocode %q: %w", arg.PlaceName, err)
+		span.AddField("error", err)
+		return geocodingError(err)
 	}
 	userLocation := query.LocationFromContext(ctx)
 	lr := LocationResponse{
+		Name:      location.Name,
 		Latitude:  location.Lat,
 		Longitude: location.Lon,
 	}
@@ -71,6 +76,9 @@ func getLocationImpl(ctx context.Context, quotaTracker *quota.Tracker, args any)
 		uh := haversine.Coord{Lat: userLocation.Lat, Lon: userLocation.Lon}
 		lh := haversine.Coord{Lat: location.Lat, Lon: location.Lon}
 		lr.DistanceMiles, lr.DistanceKilometers = haversine.Distance(uh, lh)
+		if query.LocationIsCoarseFromContext(ctx) && lr.DistanceKilometers < minCoarseDistanceKm {
+			lr.DistanceMiles, lr.DistanceKilometers = 0, 0
+		}
 	}
 	return lr
 }
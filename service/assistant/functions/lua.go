@@ -18,13 +18,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
-	"log"
 	"math"
 	"strings"
 	"time"
 
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"github.com/yuin/gopher-lua"
 	"google.golang.org/genai"
 )
@@ -62,11 +63,12 @@ func init() {
 		Fn:        luaImplementation,
 		Thought:   luaThought,
 		InputType: LuaInput{},
+		Offline:   true,
 	})
 }
 
-func luaThought(args any) string {
-	return "Getting a calculator"
+func luaThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.lua")
 }
 
 func luaImplementation(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
@@ -76,7 +78,7 @@ func luaImplementation(ctx context.Context, quotaTracker *quota.Tracker, args an
 	result, err := runLua(ctx, arg.Timezone, arg.Script)
 	if err != nil || result == nil {
 		if e, ok := err.(*lua.ApiError); (result == nil && err == nil) || (ok && e.Type == lua.ApiErrorSyntax) {
-			log.Println(err)
+			requestid.Logln(ctx, err)
 			lines := strings.Split(arg.Script, "\n")
 			lines[len(lines)-1] = "return " + lines[len(lines)-1]
 			arg.Script = strings.Join(lines, "\n")
@@ -113,7 +115,7 @@ func convertValueToJsonCompatible(v any) any {
 }
 
 func runLua(ctx context.Context, timezone, script string) (any, error) {
-	log.Println("Running script:", script)
+	requestid.Logln(ctx, "Running script:", script)
 	ctx, cancelFunc := context.WithTimeout(ctx, 100*time.Millisecond)
 	defer cancelFunc()
 	l := lua.NewState(lua.Options{
@@ -172,7 +174,7 @@ func evalExpression(expression string) string {
 	defer l.Close()
 	l.SetContext(ctx)
 	if err := l.DoString("return " + expression); err != nil {
-		log.Printf("Couldn't evaluate %q using lua. Error: %v", expression, err)
+		requestid.Logf(ctx, "Couldn't evaluate %q using lua. Error: %v", expression, err)
 		return expression
 	}
 	return fmt.Sprint(toGoValue(l.Get(-1)))
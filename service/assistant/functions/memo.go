@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/honeycombio/beeline-go"
+)
+
+// callMemo remembers function results for the rest of a turn, because models sometimes call the same function with
+// the same arguments more than once.
+type callMemo struct {
+	mu      sync.Mutex
+	results map[string]string
+}
+
+type callMemoKey struct{}
+
+// WithCallMemo returns a context in which repeated calls to the same function with identical arguments return the
+// first call's result, instead of calling the function again. It should be used once per turn.
+func WithCallMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, callMemoKey{}, &callMemo{results: map[string]string{}})
+}
+
+func memoKey(fn, args string) string {
+	return fn + "\x00" + args
+}
+
+// memoized returns the remembered result of calling fn with args, if there is one.
+func memoized(ctx context.Context, fn, args string) (string, bool) {
+	memo, ok := ctx.Value(callMemoKey{}).(*callMemo)
+	if !ok {
+		return "", false
+	}
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	result, ok := memo.results[memoKey(fn, args)]
+	if ok {
+		beeline.AddField(ctx, "memoized", true)
+	}
+	return result, ok
+}
+
+// memoize remembers the result of calling fn with args. Only functions without side effects should be memoized, and
+// errors are not remembered, so that the model can retry.
+func memoize(ctx context.Context, fn, args, result string) {
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(result), &decoded); err == nil {
+		if _, failed := decoded["error"]; failed {
+			return
+		}
+	}
+	memo, ok := ctx.Value(callMemoKey{}).(*callMemo)
+	if !ok {
+		return
+	}
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	memo.results[memoKey(fn, args)] = result
+}
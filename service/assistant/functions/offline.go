@@ -0,0 +1,32 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import "context"
+
+type offlineKey struct{}
+
+// WithOffline returns a context in which only functions that work without the internet are offered or may be called,
+// for sessions answering with the local model because Gemini can't be reached. A Registry built before this won't
+// reflect it, so call WithRegistry again afterwards.
+func WithOffline(ctx context.Context) context.Context {
+	return context.WithValue(ctx, offlineKey{}, true)
+}
+
+// IsOffline reports whether the session in ctx is answering without the internet.
+func IsOffline(ctx context.Context) bool {
+	offline, _ := ctx.Value(offlineKey{}).(bool)
+	return offline
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pages"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+type WritePagesInput struct {
+	// The title of the whole piece, e.g. "The Sleepy Dragon".
+	Title string       `json:"title"`
+	Pages []pages.Page `json:"pages"`
+}
+
+type ResumeReadingInput struct {
+	// No parameters needed
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "write_pages",
+			Description: "Store a long piece of writing you've composed, like a bedtime story, a recipe with many steps, or a long explanation, split into pages that the user reads one at a time on their watch. Returns the document ID to show it with a PAGE widget.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"title": {
+						Type:        genai.TypeString,
+						Description: "The title of the whole piece, e.g. \"The Sleepy Dragon\".",
+						Nullable:    false,
+					},
+					"pages": {
+						Type:        genai.TypeArray,
+						Description: fmt.Sprintf("The pages, in order. Each should be a paragraph or two, no more than about %d characters; longer pages are split.", pages.MaxPageBytes),
+						Nullable:    false,
+						Items: &genai.Schema{
+							Type: genai.TypeObject,
+							Properties: map[string]*genai.Schema{
+								"title": {
+									Type:        genai.TypeString,
+									Description: "An optional heading for the page, e.g. a chapter name.",
+									Nullable:    true,
+								},
+								"text": {
+									Type:        genai.TypeString,
+									Description: "The text of the page.",
+									Nullable:    false,
+								},
+							},
+							Required: []string{"text"},
+						},
+					},
+				},
+				Required: []string{"title", "pages"},
+			},
+		},
+		Fn:          writePages,
+		SideEffects: true,
+		Thought:     writePagesThought,
+		InputType:   WritePagesInput{},
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "resume_reading",
+			Description: "Find where the user got to in the last long piece they were reading, such as a story, so that they can carry on with it. Returns the document ID and the next page to show with a PAGE widget.",
+		},
+		Fn:        resumeReading,
+		Thought:   resumeReadingThought,
+		InputType: ResumeReadingInput{},
+	})
+}
+
+func writePages(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "write_pages")
+	defer span.Send()
+	arg := args.(*WritePagesInput)
+	if !query.SupportsWidget(ctx, "page") {
+		return Error{Error: "This watch can't show pages. Give a shorter answer directly instead."}
+	}
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Pages aren't available here."}
+	}
+	doc, err := pages.Create(ctx, storage.GetRedis(), requester.UserID, arg.Title, arg.Pages)
+	if err != nil {
+		switch {
+		case errors.Is(err, pages.ErrEmpty):
+			return Error{Error: "The pages have no text."}
+		case errors.Is(err, pages.ErrTooLong):
+			return Error{Error: fmt.Sprintf("That's too long: it can be at most %d pages of about %d characters each.", pages.MaxPages, pages.MaxPageBytes)}
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Storing pages failed: %v", err)
+		return Error{Error: "Storing the pages failed."}
+	}
+	span.AddField("pages", len(doc.Pages))
+	return map[string]any{
+		"document_id": doc.ID,
+		"pages":       len(doc.Pages),
+	}
+}
+
+func resumeReading(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "resume_reading")
+	defer span.Send()
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Pages aren't available here."}
+	}
+	rd := storage.GetRedis()
+	pos, ok, err := pages.LastPosition(ctx, rd, requester.UserID)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Loading reading position failed: %v", err)
+		return Error{Error: "Looking up where the user got to failed."}
+	}
+	if !ok {
+		return Error{Error: "The user hasn't been reading anything."}
+	}
+	doc, err := pages.Get(ctx, rd, requester.UserID, pos.Document)
+	if errors.Is(err, pages.ErrNotFound) {
+		return Error{Error: "What the user was reading has expired."}
+	}
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Loading document failed: %v", err)
+		return Error{Error: "Looking up what the user was reading failed."}
+	}
+	result := map[string]any{
+		"document_id": doc.ID,
+		"title":       doc.Title,
+		"pages":       len(doc.Pages),
+		"last_read":   pos.Page,
+	}
+	if pos.Page < len(doc.Pages) {
+		result["next_page"] = pos.Page + 1
+	} else {
+		result["finished"] = true
+	}
+	return result
+}
+
+func writePagesThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.pages.write")
+}
+
+func resumeReadingThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.pages.resume")
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pins"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+type GetPinnedInput struct {
+	// Words to look for in the pinned answers' titles, questions and answers. Omit to get all of them.
+	Search string `json:"search"`
+}
+
+type pinnedResult struct {
+	Title    string `json:"title"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+	// When the answer was pinned, in the user's time zone.
+	PinnedAt string `json:"pinned_at"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_pinned",
+			Description: "Get the answers the user has pinned from earlier conversations, such as a recipe or a confirmation number. Use this when the user refers to something they saved or pinned, or asks about something from a past conversation that isn't in this one.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: true,
+				Properties: map[string]*genai.Schema{
+					"search": {
+						Type:        genai.TypeString,
+						Description: "Words to look for in the pinned answers, e.g. \"pancakes\". Omit to get all of them.",
+						Nullable:    true,
+					},
+				},
+			},
+		},
+		Fn:        getPinned,
+		Thought:   getPinnedThought,
+		InputType: GetPinnedInput{},
+		Offline:   true,
+	})
+}
+
+func getPinned(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_pinned")
+	defer span.Send()
+	arg := args.(*GetPinnedInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Pinned answers aren't available here."}
+	}
+	list, err := pins.List(ctx, storage.GetRedis(), requester.UserID)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Listing pins failed: %v", err)
+		return Error{Error: "Looking up pinned answers failed."}
+	}
+	words := strings.Fields(strings.ToLower(arg.Search))
+	span.AddField("pins", len(list))
+	span.AddField("search_words", len(words))
+	tz := time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	results := []pinnedResult{}
+	for _, p := range list {
+		answer := p.Text()
+		if !matchesAll(strings.ToLower(p.Title+"\n"+p.Prompt+"\n"+answer), words) {
+			continue
+		}
+		results = append(results, pinnedResult{
+			Title:    p.Title,
+			Question: p.Prompt,
+			Answer:   answer,
+			PinnedAt: p.PinnedAt.In(tz).Format(time.RFC3339),
+		})
+	}
+	if len(list) == 0 {
+		return map[string]any{"pinned": results, "note": "The user hasn't pinned anything. They can pin answers from their history in the phone app."}
+	}
+	return map[string]any{"pinned": results}
+}
+
+func matchesAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func getPinnedThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.pinned")
+}
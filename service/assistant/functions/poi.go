@@ -17,13 +17,17 @@ package functions
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
+	"time"
 
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/mapbox"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/timezone"
 	"github.com/umahmood/haversine"
 	"google.golang.org/api/places/v1"
 	"google.golang.org/genai"
@@ -42,6 +46,7 @@ type POI struct {
 	Categories         []string
 	OpeningHours       []string
 	CurrentlyOpen      bool
+	Hours              *POIHours `json:",omitempty"`
 	PhoneNumber        string
 	PriceLevel         string
 	StarRating         float64
@@ -50,6 +55,16 @@ type POI struct {
 	DistanceMiles      float64 `json:"DistanceMiles,omitempty"`
 }
 
+// POIHours says when a place next opens or closes, worked out from its opening hours so that the model doesn't have
+// to. Times are local to the place, e.g. "21:00", or "Tuesday 09:00" if they're not today.
+type POIHours struct {
+	OpenNow         bool
+	ClosesAt        string `json:",omitempty"`
+	ClosesInMinutes int    `json:",omitempty"`
+	OpensAt         string `json:",omitempty"`
+	OpensInMinutes  int    `json:",omitempty"`
+}
+
 type POIResponse struct {
 	Results []POI
 	Warning string `json:"CriticalRequirement,omitempty"`
@@ -59,7 +74,7 @@ func init() {
 	registerFunction(Registration{
 		Definition: genai.FunctionDeclaration{
 			Name:        "poi",
-			Description: "Look up points of interest near the user's location (or another named location).",
+			Description: "Look up points of interest near the user's location (or another named location). Results say whether each place is open now and when it next opens or closes: use those rather than working it out from the opening hours.",
 			Parameters: &genai.Schema{
 				Type:     genai.TypeObject,
 				Nullable: false,
@@ -82,19 +97,22 @@ func init() {
 				Required: []string{"query", "languageCode"},
 			},
 		},
-		Fn:        searchPoi,
-		Thought:   searchPoiThought,
-		InputType: POIQuery{},
+		Fn:              searchPoi,
+		LatencyBudget:   8 * time.Second,
+		FreshFor:        15 * time.Minute,
+		Thought:         searchPoiThought,
+		InputType:       POIQuery{},
+		HiddenInKidMode: true,
 	})
 }
 
-func searchPoiThought(args any) string {
+func searchPoiThought(ctx context.Context, args any) string {
 	poiQuery := args.(*POIQuery)
 	if poiQuery.Location != "" {
 		location, _, _ := strings.Cut(poiQuery.Location, ",")
-		return fmt.Sprintf("Looking for %s near %s...", poiQuery.Query, location)
+		return i18n.T(ctx, "thought.poi.place", thoughtArgument(poiQuery.Query), thoughtArgument(location))
 	}
-	return fmt.Sprintf("Looking for %s nearby...", poiQuery.Query)
+	return i18n.T(ctx, "thought.poi.nearby", thoughtArgument(poiQuery.Query))
 }
 
 func searchPoi(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
@@ -104,10 +122,10 @@ func searchPoi(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 	span.AddField("query", poiQuery.Query)
 	location := query.LocationFromContext(ctx)
 	if poiQuery.Location != "" {
-		coords, err := photon.GeocodeWithContext(ctx, poiQuery.Location)
+		coords, err := photon.GeocodeWithContext(ctx, poiQuery.Location, photon.AnyPlace)
 		if err != nil {
 			span.AddField("error", err)
-			return Error{Error: "Error finding location: " + err.Error()}
+			return geocodingError(err)
 		}
 		location = &query.Location{
 			Lon: coords.Lon,
@@ -118,13 +136,17 @@ func searchPoi(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 		span.AddField("error", "no location provided")
 		return Error{Error: "Either the user must enable location in settings, or an explicit location parameter must be provided"}
 	}
+	if poiQuery.Location == "" && query.LocationIsCoarseFromContext(ctx) {
+		span.AddField("error", "location too coarse")
+		return preciseLocationError("searching nearby")
+	}
 
 	placeService, err := places.NewService(ctx)
 	if err != nil {
 		span.AddField("error", err)
 		return Error{Error: "Error creating places service: " + err.Error()}
 	}
-	log.Printf("Searching for POIs matching %q", poiQuery.Query)
+	requestid.Logf(ctx, "Searching for POIs matching %q", poiQuery.Query)
 	err = quotaTracker.ChargeUserOrGlobalQuota(ctx, "gplaces_text_search", 1000, quota.PoiSearchCredits)
 	if err != nil {
 		span.AddField("error", err)
@@ -149,26 +171,27 @@ func searchPoi(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 		"places.displayName", "places.location", "places.shortFormattedAddress", "places.subDestinations",
 		"places.types", "places.currentOpeningHours", "places.currentSecondaryOpeningHours",
 		"places.nationalPhoneNumber", "places.priceLevel", "places.priceRange", "places.rating",
-		"places.userRatingCount", "places.attributions",
+		"places.userRatingCount", "places.attributions", "places.utcOffsetMinutes",
 	).Do()
 
 	if err != nil {
 		span.AddField("error", err)
-		log.Printf("Failed to search for POIs: %v", err)
-		return Error{Error: "Error searching for POIs: " + err.Error()}
+		requestid.Logf(ctx, "Failed to search for POIs: %v", err)
+		return upstreamError("Error searching for POIs: ", err)
 	}
 
-	log.Printf("Found %d POIs", len(results.Places))
+	requestid.Logf(ctx, "Found %d POIs", len(results.Places))
 
 	var pois []POI
 	var attributions map[string]any
 	for _, place := range results.Places {
 		var distMiles, distKm float64
 		userLocation := query.LocationFromContext(ctx)
-		if userLocation != nil && place.Location != nil {
+		// Distances from a coarse location would be misleading for places this close.
+		if userLocation != nil && place.Location != nil && !query.LocationIsCoarseFromContext(ctx) {
 			distMiles, distKm = haversine.Distance(
-				haversine.Coord{userLocation.Lat, userLocation.Lon},
-				haversine.Coord{place.Location.Latitude, place.Location.Longitude})
+				haversine.Coord{Lat: userLocation.Lat, Lon: userLocation.Lon},
+				haversine.Coord{Lat: place.Location.Latitude, Lon: place.Location.Longitude})
 		}
 		poi := POI{
 			Name:               place.DisplayName.Text,
@@ -184,6 +207,7 @@ func searchPoi(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 		if place.CurrentOpeningHours != nil {
 			poi.OpeningHours = place.CurrentOpeningHours.WeekdayDescriptions
 			poi.CurrentlyOpen = place.CurrentOpeningHours.OpenNow
+			poi.Hours = evaluateHours(ctx, place)
 		}
 		pois = append(pois, poi)
 		if len(place.Attributions) > 0 {
@@ -205,8 +229,60 @@ func searchPoi(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 		attributionText = fmt.Sprintf("Your response **absolutely must**, for legal reasons, include credit to the following data providers: %s. Failure to include this will result in being sued.", strings.Join(attributionList, ", "))
 	}
 
+	nearUser := poiQuery.Location == "" && query.LocationFromContext(ctx) != nil
+	pois = rankPOIs(pois, poiQuery.Query, nearUser)
+
 	return &POIResponse{
 		Results: pois,
 		Warning: attributionText,
 	}
 }
+
+// evaluateHours works out when the place next opens or closes, or returns nil if it doesn't list any hours.
+func evaluateHours(ctx context.Context, place *places.GoogleMapsPlacesV1Place) *POIHours {
+	// Google describes opening hours the same way Mapbox does, down to Sunday being day 0.
+	var hours mapbox.OpenHours
+	for _, period := range place.CurrentOpeningHours.Periods {
+		if period.Open == nil {
+			continue
+		}
+		p := mapbox.Period{Open: googleTimePoint(period.Open)}
+		if period.Close != nil {
+			p.Close = googleTimePoint(period.Close)
+		}
+		hours.Periods = append(hours.Periods, p)
+	}
+	if len(hours.Periods) == 0 {
+		return nil
+	}
+
+	// Google omits the offset when it's zero, in which case we work it out from where the place is.
+	now := time.Now().In(time.FixedZone("local", int(place.UtcOffsetMinutes)*60))
+	if place.UtcOffsetMinutes == 0 && place.Location != nil {
+		now = timezone.Now(place.Location.Latitude, place.Location.Longitude)
+	}
+	status := hours.Evaluate(now)
+
+	result := &POIHours{OpenNow: status.OpenNow}
+	if !status.ClosesAt.IsZero() {
+		result.ClosesAt = formatPOITime(now, status.ClosesAt)
+		result.ClosesInMinutes = int(status.ClosesAt.Sub(now).Round(time.Minute).Minutes())
+	}
+	if !status.OpensAt.IsZero() {
+		result.OpensAt = formatPOITime(now, status.OpensAt)
+		result.OpensInMinutes = int(status.OpensAt.Sub(now).Round(time.Minute).Minutes())
+	}
+	return result
+}
+
+func googleTimePoint(p *places.GoogleMapsPlacesV1PlaceOpeningHoursPeriodPoint) mapbox.TimePoint {
+	return mapbox.TimePoint{Day: int(p.Day), Time: fmt.Sprintf("%02d%02d", p.Hour, p.Minute)}
+}
+
+// formatPOITime formats t as a time of day, with the weekday if it isn't on the same day as now.
+func formatPOITime(now, t time.Time) string {
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("Monday 15:04")
+}
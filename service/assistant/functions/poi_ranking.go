@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The weights given to each signal when ranking points of interest. The provider's own order counts for a little, so
+// that it still breaks ties and keeps well-known places near the top.
+const (
+	poiDistanceWeight = 0.45
+	poiOpenWeight     = 0.3
+	poiCategoryWeight = 0.15
+	poiProviderWeight = 0.1
+)
+
+// walkingDistanceKm is the distance at which a place's distance score has halved. Beyond this, most people would
+// rather take something further away that's open than walk there to find it closed.
+const walkingDistanceKm = 1.0
+
+// closingSoonMinutes is how near to closing a place has to be before it's ranked as if it were only half open.
+const closingSoonMinutes = 20
+
+// rankPOIs reorders search results so that the first few are the ones the user most likely wants: nearby, open, and
+// of the kind they asked for. Distance only counts when searching near the user, since that's the only time we know
+// how far away the results are from where the user cares about.
+func rankPOIs(pois []POI, searchQuery string, nearUser bool) []POI {
+	if len(pois) < 2 {
+		return pois
+	}
+	queryWords := poiWords(searchQuery)
+	scores := make([]float64, len(pois))
+	for i, poi := range pois {
+		score := poiProviderWeight * (1 - float64(i)/float64(len(pois)))
+		if nearUser {
+			score += poiDistanceWeight * walkingDistanceKm / (walkingDistanceKm + poi.DistanceKilometers)
+		}
+		score += poiOpenWeight * openScore(poi)
+		score += poiCategoryWeight * categoryScore(queryWords, poi)
+		scores[i] = score
+	}
+
+	order := make([]int, len(pois))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	ranked := make([]POI, len(pois))
+	for i, j := range order {
+		ranked[i] = pois[j]
+	}
+	return ranked
+}
+
+// openScore is 1 for places that are open, less for those about to close, and 0 for those that are closed. Places
+// without opening hours get the benefit of the doubt, since many that are always open don't list any.
+func openScore(poi POI) float64 {
+	if poi.Hours == nil {
+		if len(poi.OpeningHours) == 0 {
+			return 0.5
+		}
+		if poi.CurrentlyOpen {
+			return 1
+		}
+		return 0
+	}
+	if !poi.Hours.OpenNow {
+		return 0
+	}
+	if poi.Hours.ClosesAt != "" && poi.Hours.ClosesInMinutes < closingSoonMinutes {
+		return 0.5
+	}
+	return 1
+}
+
+// categoryScore is the fraction of the words in the query that appear in the place's name or categories.
+func categoryScore(queryWords []string, poi POI) float64 {
+	if len(queryWords) == 0 {
+		return 0
+	}
+	placeWords := map[string]bool{}
+	for _, w := range poiWords(poi.Name) {
+		placeWords[w] = true
+	}
+	for _, category := range poi.Categories {
+		for _, w := range poiWords(category) {
+			placeWords[w] = true
+		}
+	}
+	matched := 0
+	for _, w := range queryWords {
+		if placeWords[w] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(queryWords))
+}
+
+// poiWords splits a name or category like "coffee_shop" into lowercase words, with plurals roughly made singular so
+// that "coffee shops" matches "coffee_shop".
+func poiWords(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for i, w := range words {
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			words[i] = strings.TrimSuffix(w, "s")
+		}
+	}
+	return words
+}
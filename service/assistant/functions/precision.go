@@ -0,0 +1,41 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+// NeedsPreciseLocation is the code of a PreciseLocationError.
+const NeedsPreciseLocation = "NEEDS_PRECISE_LOCATION"
+
+// minCoarseDistanceKm is the shortest distance from the user we'll report when we only know roughly where they are.
+// Coarse locations can be several kilometres out, which is fine for "how far is Berlin?" but useless for anything
+// nearer than this.
+const minCoarseDistanceKm = 100
+
+// PreciseLocationError is returned instead of Error when the user has only shared their approximate location and the
+// request needs to know exactly where they are. The code lets the model explain what's wrong and how to fix it,
+// rather than apologising vaguely.
+type PreciseLocationError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// preciseLocationError returns a PreciseLocationError explaining that what the user asked for needs their precise
+// location.
+func preciseLocationError(what string) PreciseLocationError {
+	return PreciseLocationError{
+		Error: "The user has only shared their approximate location, which isn't precise enough for " + what + ". " +
+			"Tell them they can share their precise location on the settings page, or ask them where they'd like to search from.",
+		Code: NeedsPreciseLocation,
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import "context"
+
+// ProgressEvent describes a point in the lifecycle of a single tool call.
+type ProgressEvent string
+
+const (
+	ProgressStarted  ProgressEvent = "started"
+	ProgressRetrying ProgressEvent = "retrying"
+	ProgressFinished ProgressEvent = "finished"
+	ProgressFailed   ProgressEvent = "failed"
+	// The call ran over its latency budget and is carrying on in the background.
+	ProgressContinuing ProgressEvent = "continuing"
+)
+
+// ProgressReporter is called whenever a tool call changes state. detail is an optional human-readable description of
+// what's going on, already localised for the user.
+type ProgressReporter func(event ProgressEvent, detail string)
+
+type progressReporterKey struct{}
+
+// WithProgressReporter returns a context that reports tool progress to the given reporter.
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+// ReportProgress tells whoever is listening that the current tool call has changed state. It does nothing if the
+// context has no reporter, so functions can call it unconditionally.
+func ReportProgress(ctx context.Context, event ProgressEvent, detail string) {
+	reporter, ok := ctx.Value(progressReporterKey{}).(ProgressReporter)
+	if !ok || reporter == nil {
+		return
+	}
+	reporter(event, detail)
+}
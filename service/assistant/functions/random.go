@@ -0,0 +1,245 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+)
+
+const (
+	maxRandomCount     = 100
+	defaultDiceSides   = 6
+	maxDiceSides       = 1000
+	defaultRandomMin   = 1
+	defaultRandomMax   = 100
+	maxRandomMagnitude = 1_000_000_000_000
+)
+
+var randomModes = []string{"dice", "coin", "pick", "number"}
+
+type RandomInput struct {
+	// What to do: "dice", "coin", "pick" or "number".
+	Mode string `json:"mode"`
+	// How many dice to roll, coins to flip, items to pick or numbers to choose.
+	Count int `json:"count"`
+	// How many sides each die has.
+	Sides int `json:"sides"`
+	// The things to pick from.
+	Items []string `json:"items"`
+	// The smallest and largest numbers that can be chosen, inclusive.
+	Min *int64 `json:"min"`
+	Max *int64 `json:"max"`
+}
+
+type RandomResponse struct {
+	Mode  string `json:"mode"`
+	Rolls []int  `json:"rolls,omitempty"`
+	// The sum of the rolls.
+	Total   int      `json:"total,omitempty"`
+	Flips   []string `json:"flips,omitempty"`
+	Heads   *int     `json:"heads,omitempty"`
+	Tails   *int     `json:"tails,omitempty"`
+	Picked  []string `json:"picked,omitempty"`
+	Numbers []int64  `json:"numbers,omitempty"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "random",
+			Description: "Roll dice, flip coins, pick from a list, or choose random numbers. You can't be random on your own, so you *must* use this whenever the user asks for anything to be left to chance, and never make up the result. Call it again for a new result.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"mode": {
+						Type:        genai.TypeString,
+						Description: "What to do: roll dice, flip coins, pick from the items given, or choose numbers between min and max.",
+						Nullable:    false,
+						Enum:        randomModes,
+					},
+					"count": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("How many dice to roll, coins to flip, items to pick or numbers to choose. Defaults to 1, and can be at most %d. Picked items are all different.", maxRandomCount),
+						Nullable:    true,
+					},
+					"sides": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("For dice, how many sides each die has, e.g. 20 for a d20. Defaults to %d.", defaultDiceSides),
+						Nullable:    true,
+					},
+					"items": {
+						Type:        genai.TypeArray,
+						Description: "For pick, the things to pick from, as the user said them.",
+						Nullable:    true,
+						Items: &genai.Schema{
+							Type: genai.TypeString,
+						},
+					},
+					"min": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("For number, the smallest number that can be chosen. Defaults to %d.", defaultRandomMin),
+						Nullable:    true,
+					},
+					"max": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("For number, the largest number that can be chosen. Defaults to %d.", defaultRandomMax),
+						Nullable:    true,
+					},
+				},
+				Required: []string{"mode"},
+			},
+		},
+		Fn:        random,
+		Thought:   randomThought,
+		InputType: RandomInput{},
+		Offline:   true,
+		Random:    true,
+	})
+}
+
+func randomThought(ctx context.Context, args any) string {
+	arg := args.(*RandomInput)
+	switch arg.Mode {
+	case "dice":
+		return i18n.T(ctx, "thought.random.dice")
+	case "coin":
+		return i18n.T(ctx, "thought.random.coin")
+	case "pick":
+		return i18n.T(ctx, "thought.random.pick")
+	default:
+		return i18n.T(ctx, "thought.random.number")
+	}
+}
+
+func random(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "random")
+	defer span.Send()
+	arg := args.(*RandomInput)
+	span.AddField("mode", arg.Mode)
+	count := arg.Count
+	if count == 0 {
+		count = 1
+	}
+	if count < 0 || count > maxRandomCount {
+		return Error{Error: fmt.Sprintf("The count must be between 1 and %d.", maxRandomCount)}
+	}
+	result, err := randomResult(arg, count)
+	if err != nil {
+		span.AddField("error", err)
+		return Error{Error: err.Error()}
+	}
+	return result
+}
+
+func randomResult(arg *RandomInput, count int) (*RandomResponse, error) {
+	response := &RandomResponse{Mode: arg.Mode}
+	switch arg.Mode {
+	case "dice":
+		sides := arg.Sides
+		if sides == 0 {
+			sides = defaultDiceSides
+		}
+		if sides < 2 || sides > maxDiceSides {
+			return nil, fmt.Errorf("dice must have between 2 and %d sides", maxDiceSides)
+		}
+		for range count {
+			n, err := randomBelow(big.NewInt(int64(sides)))
+			if err != nil {
+				return nil, err
+			}
+			response.Rolls = append(response.Rolls, int(n.Int64())+1)
+			response.Total += int(n.Int64()) + 1
+		}
+	case "coin":
+		heads, tails := 0, 0
+		for range count {
+			n, err := randomBelow(big.NewInt(2))
+			if err != nil {
+				return nil, err
+			}
+			if n.Sign() == 0 {
+				response.Flips = append(response.Flips, "heads")
+				heads++
+			} else {
+				response.Flips = append(response.Flips, "tails")
+				tails++
+			}
+		}
+		response.Heads, response.Tails = &heads, &tails
+	case "pick":
+		if len(arg.Items) == 0 {
+			return nil, fmt.Errorf("items are needed to pick from")
+		}
+		if count > len(arg.Items) {
+			return nil, fmt.Errorf("can't pick %d different items from a list of %d", count, len(arg.Items))
+		}
+		// A partial Fisher-Yates shuffle, so that nothing is picked twice.
+		items := append([]string(nil), arg.Items...)
+		for i := range count {
+			n, err := randomBelow(big.NewInt(int64(len(items) - i)))
+			if err != nil {
+				return nil, err
+			}
+			j := i + int(n.Int64())
+			items[i], items[j] = items[j], items[i]
+		}
+		response.Picked = items[:count]
+	case "number":
+		low, high := int64(defaultRandomMin), int64(defaultRandomMax)
+		if arg.Min != nil {
+			low = *arg.Min
+		}
+		if arg.Max != nil {
+			high = *arg.Max
+		}
+		if low > high {
+			return nil, fmt.Errorf("min (%d) is more than max (%d)", low, high)
+		}
+		if low < -maxRandomMagnitude || high > maxRandomMagnitude {
+			return nil, fmt.Errorf("numbers must be between -%d and %d", maxRandomMagnitude, maxRandomMagnitude)
+		}
+		span := big.NewInt(high - low + 1)
+		for range count {
+			n, err := randomBelow(span)
+			if err != nil {
+				return nil, err
+			}
+			response.Numbers = append(response.Numbers, low+n.Int64())
+		}
+	default:
+		return nil, fmt.Errorf("unknown mode %q; it must be one of %v", arg.Mode, randomModes)
+	}
+	return response, nil
+}
+
+// randomBelow returns a uniformly random number in [0, n), from the operating system's secure random source rather
+// than a seeded generator.
+func randomBelow(n *big.Int) (*big.Int, error) {
+	r, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		return nil, fmt.Errorf("no randomness was available: %w", err)
+	}
+	return r, nil
+}
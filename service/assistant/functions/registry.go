@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/honeycombio/beeline-go"
+	"golang.org/x/exp/slices"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// Registry is the set of functions one session may call. It's worked out once, when the session starts, so that the
+// functions offered to the model are exactly those it's allowed to call, and don't change from one turn to the next.
+type Registry struct {
+	functions   map[string]Registration
+	definitions []*genai.FunctionDeclaration
+}
+
+type registryKey struct{}
+
+// WithRegistry returns a context carrying a Registry for the session in ctx, which must already have its query and
+// authorisation decision. Functions not in the registry can't be called in the returned context.
+func WithRegistry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, registryKey{}, newRegistry(ctx))
+}
+
+func newRegistry(ctx context.Context) *Registry {
+	capabilities := query.SupportedActionsFromContext(ctx)
+	r := &Registry{functions: map[string]Registration{}}
+	for name, variants := range functionVariants {
+		for _, reg := range variants {
+			if !hasCapabilities(reg, capabilities) || !featureEnabled(reg) || !isAllowed(ctx, reg) {
+				continue
+			}
+			r.functions[name] = reg
+			d := reg.Definition
+			r.definitions = append(r.definitions, &d)
+			break
+		}
+	}
+	// Keep the order stable, so the declarations don't shuffle between turns (or sessions) for no reason.
+	sort.Slice(r.definitions, func(i, j int) bool {
+		return r.definitions[i].Name < r.definitions[j].Name
+	})
+	beeline.AddField(ctx, "function_count", len(r.definitions))
+	return r
+}
+
+func registryFromContext(ctx context.Context) *Registry {
+	r, _ := ctx.Value(registryKey{}).(*Registry)
+	return r
+}
+
+// Definitions returns the declarations of every function in the registry, sorted by name.
+func (r *Registry) Definitions() []*genai.FunctionDeclaration {
+	return r.definitions
+}
+
+// Contains reports whether the named function (not an alias) is in the registry.
+func (r *Registry) Contains(fn string) bool {
+	_, ok := r.functions[fn]
+	return ok
+}
+
+func hasCapabilities(reg Registration, capabilities []string) bool {
+	return (reg.Capability == "" || slices.Contains(capabilities, reg.Capability)) &&
+		(reg.AntiCapability == "" || !slices.Contains(capabilities, reg.AntiCapability))
+}
+
+func featureEnabled(reg Registration) bool {
+	return reg.FeatureFlag == "" || slices.Contains(config.GetConfig().FeatureFlags, reg.FeatureFlag)
+}
+
+// availableInSession reports whether the named function (not an alias) may be called in the session in ctx. Without
+// a Registry, it falls back to checking the function's capabilities, feature flag and permissions directly.
+func availableInSession(ctx context.Context, fn string) bool {
+	if r := registryFromContext(ctx); r != nil {
+		return r.Contains(fn)
+	}
+	reg, ok := lookupFunction(ctx, fn)
+	return ok && featureEnabled(reg) && isAllowed(ctx, reg)
+}
@@ -0,0 +1,349 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/calendars"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/prayertimes"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/timezone"
+)
+
+// defaultReligiousCalendarDays is how far ahead to list holidays if we aren't told.
+const defaultReligiousCalendarDays = 60
+
+type GetPrayerTimesInput struct {
+	// The place to get prayer times for. If empty, the user's current location.
+	Location string `json:"location"`
+	// The date, in YYYY-MM-DD format. Defaults to today.
+	Date string `json:"date"`
+	// The calculation method. Defaults to the one usual where the place is.
+	Method string `json:"method"`
+	// "standard" or "hanafi".
+	Asr string `json:"asr"`
+}
+
+type PrayerTimesResponse struct {
+	Location string `json:"location"`
+	Date     string `json:"date"`
+	// The date in the Islamic calendar, which may be a day out depending on local moon sighting.
+	HijriDate string `json:"hijri_date"`
+	Method    string `json:"method"`
+	Asr       string `json:"asr_convention"`
+	Fajr      string `json:"fajr"`
+	Sunrise   string `json:"sunrise"`
+	Dhuhr     string `json:"dhuhr"`
+	AsrTime   string `json:"asr"`
+	Maghrib   string `json:"maghrib"`
+	Isha      string `json:"isha"`
+	// The timezone the times are in.
+	Timezone string `json:"timezone"`
+}
+
+type GetReligiousCalendarInput struct {
+	// "hebrew" or "islamic".
+	Calendar string `json:"calendar"`
+	// A Gregorian date to convert, in YYYY-MM-DD format. Defaults to today.
+	Date string `json:"date"`
+	// To convert the other way, a date in the calendar.
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+	// How many days of upcoming holidays to list.
+	Days int `json:"days"`
+}
+
+type religiousHolidayResult struct {
+	Name         string `json:"name"`
+	Date         string `json:"date"`
+	CalendarDate string `json:"calendar_date"`
+}
+
+type ReligiousCalendarResponse struct {
+	Calendar      string                   `json:"calendar"`
+	GregorianDate string                   `json:"gregorian_date"`
+	CalendarDate  string                   `json:"calendar_date"`
+	Holidays      []religiousHolidayResult `json:"upcoming_holidays"`
+	Note          string                   `json:"note"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_prayer_times",
+			Description: "Get the times of the five daily Islamic prayers (Fajr, Dhuhr, Asr, Maghrib and Isha), plus sunrise, for a place and date, and the date in the Islamic calendar. The times are calculated, so local mosques may differ by a few minutes.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"location": {
+						Type:        genai.TypeString,
+						Description: `The place to get prayer times for, e.g. "Birmingham, UK". Omit for the user's current location.`,
+						Nullable:    true,
+					},
+					"date": {
+						Type:        genai.TypeString,
+						Description: "The date, in YYYY-MM-DD format. Defaults to today.",
+						Nullable:    true,
+					},
+					"method": {
+						Type:        genai.TypeString,
+						Description: "The calculation method, which affects Fajr and Isha. Only set this if the user asks for a particular method; by default, the one usual where the place is is used.",
+						Nullable:    true,
+						Enum:        prayertimes.MethodIDs(),
+					},
+					"asr": {
+						Type:        genai.TypeString,
+						Description: "The convention for Asr: 'standard' (Shafi'i, Maliki and Hanbali) or 'hanafi'. Defaults to standard, unless the user says they follow the Hanafi school.",
+						Nullable:    true,
+						Enum:        []string{prayertimes.AsrStandard, prayertimes.AsrHanafi},
+					},
+				},
+			},
+		},
+		Fn:        getPrayerTimes,
+		FreshFor:  time.Hour,
+		Thought:   getPrayerTimesThought,
+		InputType: GetPrayerTimesInput{},
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_religious_calendar",
+			Description: "Convert dates between the Gregorian calendar and the Hebrew or Islamic (Hijri) calendar, and list upcoming Jewish or Islamic holidays. Use this for questions like \"what's the Hebrew date today?\", \"when does Ramadan start?\" or \"when is Passover?\".",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"calendar": {
+						Type:        genai.TypeString,
+						Description: "Which calendar to use.",
+						Nullable:    false,
+						Enum:        []string{string(calendars.Hebrew), string(calendars.Islamic)},
+					},
+					"date": {
+						Type:        genai.TypeString,
+						Description: "A Gregorian date to convert, in YYYY-MM-DD format, and to list holidays from. Defaults to today.",
+						Nullable:    true,
+					},
+					"year": {
+						Type:        genai.TypeInteger,
+						Description: "To convert a date in the Hebrew or Islamic calendar to the Gregorian calendar, its year, e.g. 5786 or 1447. Omit to convert from 'date' instead.",
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"month": {
+						Type:        genai.TypeInteger,
+						Description: "With 'year', the month. Hebrew months are numbered from Nisan (1) to Adar (12), with Adar II as 13; Islamic months from Muharram (1) to Dhu al-Hijjah (12).",
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"day": {
+						Type:        genai.TypeInteger,
+						Description: "With 'year', the day of the month.",
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"days": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("How many days ahead to list holidays for. Defaults to %d; at most 400.", defaultReligiousCalendarDays),
+						Nullable:    true,
+						Format:      "int32",
+					},
+				},
+				Required: []string{"calendar"},
+			},
+		},
+		Fn:        getReligiousCalendar,
+		FreshFor:  24 * time.Hour,
+		Thought:   getReligiousCalendarThought,
+		InputType: GetReligiousCalendarInput{},
+		Offline:   true,
+	})
+}
+
+func getPrayerTimesThought(ctx context.Context, args any) string {
+	arg := args.(*GetPrayerTimesInput)
+	if arg.Location != "" && arg.Location != "here" {
+		placeName, _, _ := strings.Cut(arg.Location, ",")
+		return i18n.T(ctx, "thought.prayer_times.place", thoughtArgument(placeName))
+	}
+	return i18n.T(ctx, "thought.prayer_times")
+}
+
+func getReligiousCalendarThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.religious_calendar")
+}
+
+func getPrayerTimes(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_prayer_times")
+	defer span.Send()
+	arg := args.(*GetPrayerTimesInput)
+
+	var place photon.Location
+	var loc *time.Location
+	if arg.Location == "" || arg.Location == "here" {
+		location := query.CoarseLocationFromContext(ctx)
+		if location == nil {
+			span.AddField("error", "no location provided")
+			return Error{Error: "The user's location is not available. Either the user must enable location in settings, or a place must be provided."}
+		}
+		place = photon.Location{Lat: location.Lat, Lon: location.Lon, Name: "your location"}
+		loc = time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	} else {
+		var err error
+		place, err = photon.GeocodeWithContext(ctx, arg.Location, photon.Settlement)
+		if err != nil {
+			span.AddField("error", err)
+			return geocodingError(err)
+		}
+		loc = timezone.Location(place.Lat, place.Lon)
+	}
+
+	date := time.Now().In(loc)
+	if arg.Date != "" {
+		var err error
+		date, err = time.ParseInLocation(time.DateOnly, arg.Date, loc)
+		if err != nil {
+			return Error{Error: fmt.Sprintf("Invalid date %q: must be in YYYY-MM-DD format.", arg.Date)}
+		}
+	}
+	methodID := arg.Method
+	if methodID == "" {
+		methodID = defaultPrayerMethod(timezone.Name(place.Lat, place.Lon))
+	}
+	method, ok := prayertimes.Methods[methodID]
+	if !ok {
+		return Error{Error: fmt.Sprintf("Unknown method %q. It must be one of %s.", arg.Method, strings.Join(prayertimes.MethodIDs(), ", "))}
+	}
+	asr := arg.Asr
+	if asr != prayertimes.AsrHanafi {
+		asr = prayertimes.AsrStandard
+	}
+	span.AddField("method", methodID)
+	span.AddField("asr", asr)
+
+	times := prayertimes.Calculate(date, place.Lat, place.Lon, loc, method, asr)
+	hijri, _ := calendars.FromGregorian(calendars.Islamic, date)
+	format := func(t time.Time) string {
+		return t.Format(i18n.T(ctx, "format.time"))
+	}
+	return PrayerTimesResponse{
+		Location:  place.Name,
+		Date:      date.Format(time.DateOnly),
+		HijriDate: hijri.String(),
+		Method:    method.Name,
+		Asr:       asr,
+		Fajr:      format(times.Fajr),
+		Sunrise:   format(times.Sunrise),
+		Dhuhr:     format(times.Dhuhr),
+		AsrTime:   format(times.Asr),
+		Maghrib:   format(times.Maghrib),
+		Isha:      format(times.Isha),
+		Timezone:  loc.String(),
+	}
+}
+
+// defaultPrayerMethod picks the calculation method most widely used in the region the timezone is in.
+func defaultPrayerMethod(tz string) string {
+	switch {
+	case strings.HasPrefix(tz, "America/"):
+		return "isna"
+	case slices.Contains([]string{"Asia/Riyadh", "Asia/Qatar", "Asia/Bahrain", "Asia/Kuwait", "Asia/Dubai", "Asia/Muscat", "Asia/Aden"}, tz):
+		return "makkah"
+	case slices.Contains([]string{"Asia/Karachi", "Asia/Kolkata", "Asia/Dhaka", "Asia/Kabul"}, tz):
+		return "karachi"
+	case tz == "Asia/Tehran":
+		return "tehran"
+	case tz == "Europe/Istanbul":
+		return "diyanet"
+	case strings.HasPrefix(tz, "Africa/"), slices.Contains([]string{"Asia/Damascus", "Asia/Beirut", "Asia/Amman", "Asia/Baghdad", "Asia/Gaza", "Asia/Hebron", "Asia/Kuala_Lumpur"}, tz):
+		return "egypt"
+	}
+	return "mwl"
+}
+
+func getReligiousCalendar(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_religious_calendar")
+	defer span.Send()
+	arg := args.(*GetReligiousCalendarInput)
+	calendar := calendars.Calendar(strings.ToLower(arg.Calendar))
+	if calendar != calendars.Hebrew && calendar != calendars.Islamic {
+		return Error{Error: fmt.Sprintf("Unknown calendar %q. It must be 'hebrew' or 'islamic'.", arg.Calendar)}
+	}
+	span.AddField("calendar", calendar)
+
+	loc := time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	date := time.Now().In(loc)
+	if arg.Year != 0 {
+		var err error
+		date, err = calendars.ToGregorian(calendars.Date{Calendar: calendar, Year: arg.Year, Month: arg.Month, Day: arg.Day})
+		if err != nil {
+			return Error{Error: err.Error()}
+		}
+	} else if arg.Date != "" {
+		var err error
+		date, err = time.ParseInLocation(time.DateOnly, arg.Date, loc)
+		if err != nil {
+			return Error{Error: fmt.Sprintf("Invalid date %q: must be in YYYY-MM-DD format.", arg.Date)}
+		}
+	}
+	days := arg.Days
+	if days <= 0 {
+		days = defaultReligiousCalendarDays
+	}
+	days = min(days, 400)
+
+	converted, err := calendars.FromGregorian(calendar, date)
+	if err != nil {
+		return Error{Error: err.Error()}
+	}
+	holidays, err := calendars.HolidaysBetween(calendar, date, date.AddDate(0, 0, days))
+	if err != nil {
+		return Error{Error: err.Error()}
+	}
+	response := ReligiousCalendarResponse{
+		Calendar:      string(calendar),
+		GregorianDate: date.Format(time.DateOnly),
+		CalendarDate:  converted.String(),
+		Holidays:      []religiousHolidayResult{},
+	}
+	for _, h := range holidays {
+		response.Holidays = append(response.Holidays, religiousHolidayResult{
+			Name:         h.Name,
+			Date:         h.Date.Format(time.DateOnly),
+			CalendarDate: h.CalendarDate.String(),
+		})
+	}
+	switch calendar {
+	case calendars.Hebrew:
+		response.Note = "Jewish days, including holidays, begin at sunset the evening before the date given. Diaspora dates are used where they differ from Israel's."
+	case calendars.Islamic:
+		response.Note = "Islamic days begin at sunset the evening before the date given. These dates are calculated; in practice months begin with the sighting of the new moon, which can make them a day or so later, and differ between countries."
+	}
+	return response
+}
@@ -16,14 +16,31 @@ package functions
 
 import (
 	"context"
-	"log"
+	"strings"
 	"time"
 
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
 	"google.golang.org/genai"
 
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// reminderTriggersAction is the capability the phone app advertises when it can set reminders that go off on
+// something other than a time.
+const reminderTriggersAction = "reminder_triggers"
+
+// arrivalRadiusMetres is how close the user has to be to a place to count as having arrived.
+const arrivalRadiusMetres = 200
+
+const (
+	// The reminder goes off when the user gets to a place.
+	triggerArrive = "arrive"
+	// The reminder goes off the next time the user opens Bobby.
+	triggerNextOpen = "next_open"
 )
 
 type SetReminderInput struct {
@@ -33,6 +50,15 @@ type SetReminderInput struct {
 	Delay int `json:"delay_mins"`
 	// What to remind the user to do.
 	What string `json:"what" jsonschema:"required"`
+	// Something other than a time to set the reminder off.
+	Trigger *ReminderTrigger `json:"trigger"`
+}
+
+type ReminderTrigger struct {
+	// Either "arrive" or "next_open".
+	Type string `json:"type"`
+	// For "arrive", where the user has to get to: "home", or the name or address of a place.
+	Place string `json:"place"`
 }
 
 type GetRemindersInput struct {
@@ -47,8 +73,9 @@ type DeleteReminderInput struct {
 func init() {
 	registerFunction(Registration{
 		Definition: genai.FunctionDeclaration{
-			Name:        "set_reminder",
-			Description: "Set a reminder for the user to perform a task at a time.  Either time or delay must be provided, but not both. If the user specifies a time but not a day, assume they meant the next time that time will happen.",
+			Name: "set_reminder",
+			Description: "Set a reminder for the user to perform a task at a time.  Exactly one of time, delay or trigger must be provided. If the user specifies a time but not a day, assume they meant the next time that time will happen. " +
+				"Use a trigger when the reminder depends on something other than the time, like \"remind me when I get home\" or \"remind me next time I check my watch\".",
 			Parameters: &genai.Schema{
 				Type:     genai.TypeObject,
 				Nullable: false,
@@ -69,23 +96,44 @@ func init() {
 						Description: "What to remind the user to do.",
 						Nullable:    false,
 					},
+					"trigger": {
+						Type:        genai.TypeObject,
+						Description: "Something other than a time that sets the reminder off. The user's phone checks for it, so it only works while Bobby is installed and the phone can see the watch.",
+						Nullable:    true,
+						Properties: map[string]*genai.Schema{
+							"type": {
+								Type:        genai.TypeString,
+								Description: "\"arrive\" to remind the user when they get to a place, or \"next_open\" to remind them the next time they open Bobby. Use next_open for requests like \"next time I pick up my phone\" or \"when I next talk to Alex\", since there's no way to tell when those happen.",
+								Enum:        []string{triggerArrive, triggerNextOpen},
+							},
+							"place": {
+								Type:        genai.TypeString,
+								Description: "For arrive, where the user has to get to: \"home\", or the name or address of a place.",
+								Nullable:    true,
+							},
+						},
+						Required: []string{"type"},
+					},
 				},
 				Required: []string{"what"},
 			},
 		},
-		Cb:        setReminder,
-		Thought:   reminderThought,
-		InputType: SetReminderInput{},
+		Cb:          setReminder,
+		SideEffects: true,
+		Thought:     reminderThought,
+		InputType:   SetReminderInput{},
+		Offline:     true,
 	})
 
 	registerFunction(Registration{
 		Definition: genai.FunctionDeclaration{
 			Name:        "get_reminders",
-			Description: "Get a list of all active reminders.",
+			Description: "Get a list of all active reminders. Reminders that go off on something other than a time have a trigger instead of a time.",
 		},
 		Cb:        getReminders,
 		Thought:   getRemindersThought,
 		InputType: GetRemindersInput{},
+		Offline:   true,
 	})
 
 	registerFunction(Registration{
@@ -105,9 +153,11 @@ func init() {
 				Required: []string{"id"},
 			},
 		},
-		Cb:        deleteReminder,
-		Thought:   deleteReminderThought,
-		InputType: DeleteReminderInput{},
+		Cb:          deleteReminder,
+		SideEffects: true,
+		Thought:     deleteReminderThought,
+		InputType:   DeleteReminderInput{},
+		Offline:     true,
 	})
 }
 
@@ -118,6 +168,12 @@ func setReminder(ctx context.Context, quotaTracker *quota.Tracker, args any, req
 		return Error{Error: "You need to update the app on your watch to set reminders."}
 	}
 	arg := args.(*SetReminderInput)
+	if arg.Trigger != nil {
+		if arg.Time != "" || arg.Delay != 0 {
+			return Error{Error: "A reminder with a trigger can't also have a time or delay."}
+		}
+		return setTriggeredReminder(ctx, arg, requestChan, responseChan)
+	}
 	if arg.Time == "" && arg.Delay == 0 {
 		return Error{Error: "Either time or delay must be provided."}
 	}
@@ -133,10 +189,68 @@ func setReminder(ctx context.Context, quotaTracker *quota.Tracker, args any, req
 		"what":   arg.What,
 		"action": "set_reminder",
 	}
-	log.Println("Asking watch to set reminder...")
+	requestid.Logln(ctx, "Asking watch to set reminder...")
+	requestChan <- req
+	requestid.Logln(ctx, "Waiting for confirmation...")
+	resp := <-responseChan
+	if t, err := time.Parse(time.RFC3339, arg.Time); err == nil && resp != nil {
+		if holiday := holidayOn(ctx, t.In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60))); holiday != nil {
+			resp["holiday_warning"] = "This reminder falls on " + holiday.Name + ", a public holiday. Mention this to the user in case it matters."
+		}
+	}
+	return resp
+}
+
+// setTriggeredReminder asks the phone to remind the user when something other than a time happens. The phone keeps
+// the reminder to itself and checks for the trigger locally, since only it knows where the user is.
+func setTriggeredReminder(ctx context.Context, arg *SetReminderInput, requestChan chan<- map[string]any, responseChan <-chan map[string]any) any {
+	if !query.SupportsAction(ctx, reminderTriggersAction) {
+		return Error{Error: "The user needs to update the Bobby app on their phone to set reminders that aren't for a time."}
+	}
+	trigger := map[string]any{"type": arg.Trigger.Type}
+	note := ""
+	switch arg.Trigger.Type {
+	case triggerArrive:
+		place := strings.TrimSpace(arg.Trigger.Place)
+		if place == "" {
+			return Error{Error: "An arrive trigger needs a place."}
+		}
+		var location photon.Location
+		if strings.EqualFold(place, "home") {
+			home := query.HomeFromContext(ctx)
+			if home == nil {
+				return Error{Error: "The user hasn't set their home. They can do it in the app's settings on their phone."}
+			}
+			location = photon.Location{Lat: home.Lat, Lon: home.Lon, Name: home.Name}
+		} else {
+			var err error
+			location, err = photon.GeocodeWithContext(ctx, place, photon.AnyPlace)
+			if err != nil {
+				return geocodingError(err)
+			}
+		}
+		trigger["place"] = location.Name
+		trigger["lat"] = location.Lat
+		trigger["lon"] = location.Lon
+		trigger["radius_m"] = arrivalRadiusMetres
+		note = "The phone can only check where the user is while Bobby is open, so tell the user the reminder will go off the first time they open Bobby at " + location.Name + "."
+	case triggerNextOpen:
+		note = "Tell the user the reminder will go off the next time they open Bobby."
+	default:
+		return Error{Error: "Unknown trigger type " + arg.Trigger.Type + "."}
+	}
+	req := map[string]any{
+		"what":    arg.What,
+		"trigger": trigger,
+		"action":  "set_reminder",
+	}
+	requestid.Logln(ctx, "Asking phone to set triggered reminder...")
 	requestChan <- req
-	log.Println("Waiting for confirmation...")
+	requestid.Logln(ctx, "Waiting for confirmation...")
 	resp := <-responseChan
+	if resp != nil && resp["error"] == nil {
+		resp["note"] = note
+	}
 	return resp
 }
 
@@ -150,9 +264,9 @@ func getReminders(ctx context.Context, quotaTracker *quota.Tracker, args any, re
 	req := map[string]any{
 		"action": "get_reminders",
 	}
-	log.Println("Asking watch to get reminders...")
+	requestid.Logln(ctx, "Asking watch to get reminders...")
 	requestChan <- req
-	log.Println("Waiting for response...")
+	requestid.Logln(ctx, "Waiting for response...")
 	resp := <-responseChan
 	return resp
 }
@@ -169,21 +283,25 @@ func deleteReminder(ctx context.Context, quotaTracker *quota.Tracker, args any,
 		"action": "delete_reminder",
 		"id":     arg.ID,
 	}
-	log.Println("Asking watch to delete reminder...")
+	requestid.Logln(ctx, "Asking watch to delete reminder...")
 	requestChan <- req
-	log.Println("Waiting for confirmation...")
+	requestid.Logln(ctx, "Waiting for confirmation...")
 	resp := <-responseChan
 	return resp
 }
 
-func reminderThought(args any) string {
-	return "Setting a reminder"
+func reminderThought(ctx context.Context, args any) string {
+	arg := args.(*SetReminderInput)
+	if arg.What != "" {
+		return i18n.T(ctx, "thought.reminder.set.what", thoughtArgument(arg.What))
+	}
+	return i18n.T(ctx, "thought.reminder.set")
 }
 
-func getRemindersThought(args any) string {
-	return "Getting your reminders"
+func getRemindersThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.reminder.get")
 }
 
-func deleteReminderThought(args any) string {
-	return "Deleting a reminder"
+func deleteReminderThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.reminder.delete")
 }
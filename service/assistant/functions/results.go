@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+)
+
+// StoredResult is a function result from earlier in a conversation, kept so that it can be reused in later turns
+// while it's still fresh (see Registration.FreshFor).
+type StoredResult struct {
+	Function  string    `json:"function"`
+	Args      string    `json:"args"`
+	Result    string    `json:"result"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+func (r StoredResult) fresh(now time.Time) bool {
+	reg, ok := functionMap[r.Function]
+	return ok && reg.FreshFor > 0 && now.Sub(r.FetchedAt) < reg.FreshFor
+}
+
+type resultStore struct {
+	mu      sync.Mutex
+	results []StoredResult
+}
+
+type resultStoreKey struct{}
+
+// WithResultStore returns a context that remembers the results of functions with a FreshFor, starting with those
+// from earlier turns that are still fresh. Calls repeated while their result is fresh return it again, and the
+// model is told what it already has (see DescribeFreshResults), so that follow-up questions don't fetch everything
+// again.
+func WithResultStore(ctx context.Context, previous []StoredResult) context.Context {
+	store := &resultStore{}
+	now := time.Now()
+	for _, r := range previous {
+		if r.fresh(now) {
+			store.results = append(store.results, r)
+		}
+	}
+	return context.WithValue(ctx, resultStoreKey{}, store)
+}
+
+// FreshResultsFromContext returns the stored results that are still fresh, oldest first.
+func FreshResultsFromContext(ctx context.Context) []StoredResult {
+	store, ok := ctx.Value(resultStoreKey{}).(*resultStore)
+	if !ok {
+		return nil
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	now := time.Now()
+	var results []StoredResult
+	for _, r := range store.results {
+		if r.fresh(now) {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// freshResult returns the stored result of calling fn with args, if there is one that's still fresh.
+func freshResult(ctx context.Context, fn, args string) (StoredResult, bool) {
+	for _, r := range FreshResultsFromContext(ctx) {
+		if r.Function == fn && r.Args == args {
+			beeline.AddField(ctx, "reused_result_age_s", int(time.Since(r.FetchedAt).Seconds()))
+			return r, true
+		}
+	}
+	return StoredResult{}, false
+}
+
+// recordResult stores the result of calling fn with args, if fn's results stay fresh for any time. As with
+// memoization, errors aren't stored.
+func recordResult(ctx context.Context, fn, args, result string) {
+	if functionMap[fn].FreshFor <= 0 {
+		return
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(result), &decoded); err == nil {
+		if _, failed := decoded["error"]; failed {
+			return
+		}
+		// Old data standing in for a service that's down shouldn't stop the next question trying the service again.
+		if _, degraded := decoded["degraded"]; degraded {
+			return
+		}
+	}
+	store, ok := ctx.Value(resultStoreKey{}).(*resultStore)
+	if !ok {
+		return
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	// A newer result replaces any older one for the same call.
+	kept := store.results[:0]
+	for _, r := range store.results {
+		if r.Function != fn || r.Args != args {
+			kept = append(kept, r)
+		}
+	}
+	store.results = append(kept, StoredResult{Function: fn, Args: args, Result: result, FetchedAt: time.Now()})
+}
+
+// DescribeFreshResults tells the model which function results it already has and how old they are, or returns "" if
+// there aren't any.
+func DescribeFreshResults(ctx context.Context) string {
+	results := FreshResultsFromContext(ctx)
+	if len(results) == 0 {
+		return ""
+	}
+	now := time.Now()
+	var descriptions []string
+	for _, r := range results {
+		descriptions = append(descriptions, fmt.Sprintf("%s(%s) from %s", r.Function, r.Args, describeAge(now.Sub(r.FetchedAt))))
+	}
+	return "Earlier in this conversation you called these functions, and their results are still fresh: " +
+		strings.Join(descriptions, "; ") + ". " +
+		"If a follow-up question can be answered from one of those results, answer from it rather than calling the function again. " +
+		"Calling a function again with exactly the same arguments will return the same result.\n"
+}
+
+func describeAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < 2*time.Minute:
+		return "a minute ago"
+	case d < time.Hour:
+		return fmt.Sprintf("%d minutes ago", int(d.Minutes()))
+	case d < 2*time.Hour:
+		return "an hour ago"
+	default:
+		return fmt.Sprintf("%d hours ago", int(d.Hours()))
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// TransientError is returned by functions when an upstream service failed in a way that might work if tried again,
+// such as a timeout or a server error.
+type TransientError struct {
+	Error string `json:"error"`
+	Note  string `json:"note,omitempty"`
+}
+
+// retryDelay is how long to wait before retrying a function that failed transiently.
+const retryDelay = 500 * time.Millisecond
+
+// retriedNote is added to the result of a function that was retried, so the model knows that any failure was a blip
+// rather than the service being broken.
+const retriedNote = "transient failure, retried"
+
+// upstreamError returns the result for a function that failed because an upstream call returned err. The message is
+// prefixed to the error, e.g. "Could not get forecast: ".
+func upstreamError(message string, err error) any {
+	if upstream.IsCircuitOpen(err) {
+		// There's no point retrying: we won't try the service again for a while.
+		return Error{Error: message + err.Error() + ". The service is down at the moment. Tell the user you can't get this right now, and answer the rest of their question as well as you can."}
+	}
+	if upstream.IsTransient(err) {
+		return TransientError{Error: message + err.Error()}
+	}
+	return Error{Error: message + err.Error()}
+}
+
+// callWithRetry calls a function, and if it fails transiently, calls it once more.
+func callWithRetry(ctx context.Context, fn string, call func() any) any {
+	result := call()
+	if _, ok := result.(TransientError); !ok {
+		return result
+	}
+	requestid.Logf(ctx, "function %q failed transiently, retrying: %v\n", fn, result)
+	beeline.AddField(ctx, "retried", true)
+	ReportProgress(ctx, ProgressRetrying, i18n.T(ctx, "thought.retrying"))
+	select {
+	case <-ctx.Done():
+		return result
+	case <-time.After(retryDelay):
+	}
+	return annotateRetried(call())
+}
+
+// annotateRetried adds a note to a function's result saying that it was retried.
+func annotateRetried(result any) any {
+	if t, ok := result.(TransientError); ok {
+		t.Note = retriedNote
+		return t
+	}
+	m, ok := resultAsMap(result)
+	if !ok {
+		return result
+	}
+	m["note"] = retriedNote
+	return m
+}
+
+// resultAsMap converts a function's result to a map, so that fields can be added to it.
+func resultAsMap(result any) (map[string]any, bool) {
+	j, err := json.Marshal(result)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]any
+	if err := json.Unmarshal(j, &m); err != nil {
+		return nil, false
+	}
+	return m, true
+}
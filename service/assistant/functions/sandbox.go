@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+type sandboxKey struct{}
+
+// WithSandbox returns a context in which functions with side effects are simulated instead of called. Everything
+// else - argument parsing, validation, and functions without side effects - behaves as normal, so end-to-end flows
+// can be exercised safely in tests and by the canary.
+func WithSandbox(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sandboxKey{}, true)
+}
+
+// IsSandboxed reports whether the context is running in sandbox mode.
+func IsSandboxed(ctx context.Context) bool {
+	sandboxed, _ := ctx.Value(sandboxKey{}).(bool)
+	return sandboxed
+}
+
+// simulatedResponse is what sandboxed side effects report back, standing in for the real response. It's made afresh
+// each time, since functions add their own notes to the response they get.
+func simulatedResponse() map[string]any {
+	return map[string]any{"status": "ok", "sandboxed": true}
+}
+
+// simulateFunction logs what a function with side effects would have been called with, instead of calling it.
+func simulateFunction(ctx context.Context, fn string, args any) any {
+	j, _ := json.Marshal(args)
+	requestid.Logf(ctx, "[sandbox] Not calling %s with %s.", fn, j)
+	return simulatedResponse()
+}
+
+// simulateWatchRequest stands in for the watch when an action with side effects runs in sandbox mode, logging the
+// request and reporting success without sending anything.
+func simulateWatchRequest(ctx context.Context, fn string, req map[string]any) map[string]any {
+	j, _ := json.Marshal(req)
+	requestid.Logf(ctx, "[sandbox] Not sending %s request to watch: %s", fn, j)
+	return simulatedResponse()
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// TestSandboxedResponsesAreSeparate checks that what one sandboxed call adds to its response doesn't turn up in the
+// next one's.
+func TestSandboxedResponsesAreSeparate(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"actions": {"set_reminder,delete_reminder," + reminderTriggersAction}})
+	ctx = WithSandbox(ctx)
+	call := func(fn, args string) map[string]any {
+		t.Helper()
+		result, err := CallAction(ctx, nil, fn, args, nil)
+		if err != nil {
+			t.Fatalf("CallAction(%s, %s) failed: %v", fn, args, err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(result), &decoded); err != nil {
+			t.Fatalf("CallAction(%s, %s) = %s, which isn't an object: %v", fn, args, result, err)
+		}
+		return decoded
+	}
+	first := call("set_reminder", `{"what": "water the plants", "trigger": {"type": "next_open"}}`)
+	if first["note"] == nil || first["sandboxed"] != true {
+		t.Fatalf("first sandboxed reminder = %v, want a simulated response with a note", first)
+	}
+	second := call("delete_reminder", `{"id": "1"}`)
+	if len(second) != 2 || second["status"] != "ok" || second["sandboxed"] != true {
+		t.Errorf("second sandboxed call = %v, want only the simulated response", second)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// scheduledQueriesFlag is the feature flag that makes scheduled queries available.
+const scheduledQueriesFlag = "scheduled_queries"
+
+type ScheduleQueryInput struct {
+	// What to ask, written as the user would ask it, e.g. "What's the weather going to be like today?"
+	Prompt string `json:"prompt"`
+	// The local time of day to ask at, as HH:MM in 24-hour time.
+	Time string `json:"time"`
+	// The days of the week to ask on. Omit to ask every day.
+	Days []string `json:"days"`
+}
+
+type ListScheduledQueriesInput struct {
+	// No parameters needed
+}
+
+type CancelScheduledQueryInput struct {
+	// The ID of the scheduled query to cancel.
+	ID string `json:"id"`
+}
+
+type scheduledQueryResult struct {
+	ID     string   `json:"id"`
+	Prompt string   `json:"prompt"`
+	Time   string   `json:"time"`
+	Days   []string `json:"days,omitempty"`
+	// When it next runs, in the user's time zone.
+	NextRun string `json:"next_run"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "schedule_query",
+			Description: "Schedule a question to be asked on the user's behalf at a time of day, every day or on certain days of the week, with the answer sent to their watch's timeline. Use this when the user asks to be told something regularly, e.g. \"tell me the weather every morning at 7\".",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"prompt": {
+						Type:        genai.TypeString,
+						Description: "What to ask, written as the user would ask it at the time, e.g. \"What's the weather going to be like today?\".",
+						Nullable:    false,
+					},
+					"time": {
+						Type:        genai.TypeString,
+						Description: "The local time of day to ask at, as HH:MM in 24-hour time.",
+						Nullable:    false,
+						Pattern:     `^\d\d:\d\d$`,
+					},
+					"days": {
+						Type:        genai.TypeArray,
+						Description: "The days of the week to ask on. Omit to ask every day.",
+						Nullable:    true,
+						Items: &genai.Schema{
+							Type: genai.TypeString,
+							Enum: schedule.Weekdays,
+						},
+					},
+				},
+				Required: []string{"prompt", "time"},
+			},
+		},
+		Fn:          scheduleQuery,
+		SideEffects: true,
+		Thought:     scheduleQueryThought,
+		InputType:   ScheduleQueryInput{},
+		FeatureFlag: scheduledQueriesFlag,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "list_scheduled_queries",
+			Description: "List the questions the user has scheduled to be asked regularly.",
+		},
+		Fn:          listScheduledQueries,
+		Thought:     listScheduledQueriesThought,
+		InputType:   ListScheduledQueriesInput{},
+		FeatureFlag: scheduledQueriesFlag,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "cancel_scheduled_query",
+			Description: "Stop asking a scheduled question.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"id": {
+						Type:        genai.TypeString,
+						Description: "The ID of the scheduled query to cancel. You *must* call list_scheduled_queries first to discover the ID of the correct query.",
+						Nullable:    false,
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Fn:          cancelScheduledQuery,
+		SideEffects: true,
+		Thought:     cancelScheduledQueryThought,
+		InputType:   CancelScheduledQueryInput{},
+		FeatureFlag: scheduledQueriesFlag,
+	})
+}
+
+func scheduleQuery(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "schedule_query")
+	defer span.Send()
+	arg := args.(*ScheduleQueryInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok || requester.IsScheduled() {
+		return Error{Error: "Scheduled questions can't be set up from here."}
+	}
+	q, err := schedule.New(requester, arg.Prompt, arg.Time, arg.Days)
+	if err != nil {
+		return Error{Error: err.Error()}
+	}
+	if err := schedule.Add(ctx, storage.GetRedis(), q); err != nil {
+		if errors.Is(err, schedule.ErrTooMany) {
+			return Error{Error: fmt.Sprintf("The user already has %d scheduled questions, which is the most they can have. They need to cancel one first.", schedule.MaxPerUser)}
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Scheduling query failed: %v", err)
+		return Error{Error: "Scheduling the question failed."}
+	}
+	return describeScheduledQuery(ctx, q)
+}
+
+func listScheduledQueries(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "list_scheduled_queries")
+	defer span.Send()
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Scheduled questions aren't available here."}
+	}
+	queries, err := schedule.List(ctx, storage.GetRedis(), requester.UserID)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Listing scheduled queries failed: %v", err)
+		return Error{Error: "Looking up scheduled questions failed."}
+	}
+	results := []scheduledQueryResult{}
+	for _, q := range queries {
+		results = append(results, describeScheduledQuery(ctx, q))
+	}
+	return map[string]any{"scheduled_queries": results}
+}
+
+func cancelScheduledQuery(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "cancel_scheduled_query")
+	defer span.Send()
+	arg := args.(*CancelScheduledQueryInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return Error{Error: "Scheduled questions aren't available here."}
+	}
+	cancelled, err := schedule.Cancel(ctx, storage.GetRedis(), requester.UserID, arg.ID)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Cancelling scheduled query failed: %v", err)
+		return Error{Error: "Cancelling the scheduled question failed."}
+	}
+	if !cancelled {
+		return Error{Error: fmt.Sprintf("There's no scheduled question with ID %q. Call list_scheduled_queries to find the right ID.", arg.ID)}
+	}
+	return map[string]any{"status": "ok"}
+}
+
+func describeScheduledQuery(ctx context.Context, q schedule.Query) scheduledQueryResult {
+	return scheduledQueryResult{
+		ID:      q.ID,
+		Prompt:  q.Prompt,
+		Time:    q.Time,
+		Days:    q.Days,
+		NextRun: q.NextRun.In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)).Format(time.RFC3339),
+	}
+}
+
+func scheduleQueryThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.schedule.set")
+}
+
+func listScheduledQueriesThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.schedule.list")
+}
+
+func cancelScheduledQueryThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.schedule.cancel")
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+)
+
+// maxSpellLength is the most characters spell will spell out, which is already more than fits on the screen.
+const maxSpellLength = 100
+
+// natoAlphabet is the ICAO spelling alphabet. The digits are given as plain words, because "Niner" and "Fife" only
+// help over a crackly radio.
+var natoAlphabet = map[rune]string{
+	'A': "Alfa", 'B': "Bravo", 'C': "Charlie", 'D': "Delta", 'E': "Echo", 'F': "Foxtrot", 'G': "Golf",
+	'H': "Hotel", 'I': "India", 'J': "Juliett", 'K': "Kilo", 'L': "Lima", 'M': "Mike", 'N': "November",
+	'O': "Oscar", 'P': "Papa", 'Q': "Quebec", 'R': "Romeo", 'S': "Sierra", 'T': "Tango", 'U': "Uniform",
+	'V': "Victor", 'W': "Whiskey", 'X': "X-ray", 'Y': "Yankee", 'Z': "Zulu",
+	'0': "Zero", '1': "One", '2': "Two", '3': "Three", '4': "Four", '5': "Five", '6': "Six", '7': "Seven",
+	'8': "Eight", '9': "Nine",
+}
+
+// spokenSymbols are the message keys for the names of the symbols that turn up in codes, email addresses and names,
+// since a bare "." is easy to miss when reading aloud.
+var spokenSymbols = map[rune]string{
+	'.': "spell.symbol.dot", ',': "spell.symbol.comma", '-': "spell.symbol.dash", '_': "spell.symbol.underscore",
+	'@': "spell.symbol.at", '/': "spell.symbol.slash", '\\': "spell.symbol.backslash", '\'': "spell.symbol.apostrophe",
+	'+': "spell.symbol.plus", '&': "spell.symbol.and", '#': "spell.symbol.hash", '*': "spell.symbol.star",
+	':': "spell.symbol.colon", '!': "spell.symbol.exclamation_mark", '?': "spell.symbol.question_mark",
+	'(': "spell.symbol.open_bracket", ')': "spell.symbol.close_bracket",
+}
+
+type SpellInput struct {
+	// The word, name or code to spell.
+	Text string `json:"text"`
+	// Whether to give each letter's NATO phonetic alphabet word too.
+	Nato bool `json:"nato"`
+	// Whether to say which letters are capitals, for codes where case matters.
+	MarkCase bool `json:"mark_case"`
+}
+
+type SpellResponse struct {
+	Text string `json:"text"`
+	// The text spelt out, one character to a line, with a blank line between words.
+	Spelled string `json:"spelled"`
+	// How many letters, digits and symbols there are, not counting spaces.
+	Characters int `json:"characters"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "spell",
+			Description: "Spell out a word, name or code letter by letter, optionally with the NATO phonetic alphabet, laid out to be read aloud from the watch, e.g. when the user has to read a confirmation code or their name to someone over the phone. Reply with the spelled text exactly as it's returned, line breaks and all, and nothing else.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"text": {
+						Type:        genai.TypeString,
+						Description: "The word, name or code to spell, exactly as it's written.",
+						Nullable:    false,
+					},
+					"nato": {
+						Type:        genai.TypeBoolean,
+						Description: "Whether to give the NATO phonetic alphabet word for each letter and digit, e.g. \"B - Bravo\". Use this if the user asks for it, or if they'll be reading out a code.",
+						Nullable:    true,
+					},
+					"mark_case": {
+						Type:        genai.TypeBoolean,
+						Description: "Whether to say which letters are capitals and which are small. Only use this if the user says case matters.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"text"},
+			},
+		},
+		Fn:        spell,
+		Thought:   spellThought,
+		InputType: SpellInput{},
+		Offline:   true,
+	})
+}
+
+func spellThought(ctx context.Context, args any) string {
+	arg := args.(*SpellInput)
+	return i18n.T(ctx, "thought.spell", thoughtArgument(arg.Text))
+}
+
+func spell(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "spell")
+	defer span.Send()
+	arg := args.(*SpellInput)
+	span.AddField("nato", arg.Nato)
+	text := strings.Join(strings.Fields(arg.Text), " ")
+	if text == "" {
+		return Error{Error: "There's nothing to spell."}
+	}
+	if utf8.RuneCountInString(text) > maxSpellLength {
+		return Error{Error: fmt.Sprintf("That's too long to spell out; it can be at most %d characters.", maxSpellLength)}
+	}
+	var lines []string
+	characters := 0
+	for _, r := range text {
+		if r == ' ' {
+			lines = append(lines, "")
+			continue
+		}
+		characters++
+		lines = append(lines, spellRune(ctx, r, arg.Nato, arg.MarkCase))
+	}
+	return SpellResponse{
+		Text:       text,
+		Spelled:    strings.Join(lines, "\n"),
+		Characters: characters,
+	}
+}
+
+// spellRune returns the line for a single character, in the user's language. The NATO words stay as they are, since
+// the point of them is that they're the same everywhere.
+func spellRune(ctx context.Context, r rune, nato, markCase bool) string {
+	if key, ok := spokenSymbols[r]; ok {
+		return fmt.Sprintf("%c (%s)", r, i18n.T(ctx, key))
+	}
+	line := string(unicode.ToUpper(r))
+	if markCase && unicode.IsUpper(r) {
+		line = i18n.T(ctx, "spell.capital", line)
+	} else if markCase && unicode.IsLower(r) {
+		line = i18n.T(ctx, "spell.small", line)
+	}
+	if word, ok := natoAlphabet[unicode.ToUpper(r)]; ok && nato {
+		line += " - " + word
+	}
+	return line
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/honeycombio/beeline-go"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+)
+
+const (
+	maxBillPeople = 100
+	maxBillTotal  = 1_000_000_000
+	maxTipPercent = 100
+)
+
+var billRoundings = []string{"none", "each", "total"}
+
+type SplitBillInput struct {
+	// The bill before the tip.
+	Total float64 `json:"total"`
+	// The tip, as a percentage of the bill.
+	TipPercent float64 `json:"tip_percent"`
+	// How many people are splitting the bill.
+	People int `json:"people"`
+	// How to round: "none", "each" to round each share up to a whole unit, or "total" to round the total up.
+	Rounding string `json:"rounding"`
+	// The ISO 4217 code of the currency the bill is in.
+	Currency string `json:"currency"`
+}
+
+type billShare struct {
+	People int    `json:"people"`
+	Amount string `json:"amount"`
+}
+
+type SplitBillResponse struct {
+	Currency string `json:"currency,omitempty"`
+	Bill     string `json:"bill"`
+	Tip      string `json:"tip"`
+	// The tip as a percentage of the bill, after any rounding.
+	TipPercent float64 `json:"tip_percent"`
+	Total      string  `json:"total"`
+	People     int     `json:"people"`
+	// What each person pays. If the total doesn't divide evenly, this is the larger share, and Shares says who pays
+	// what.
+	PerPerson string      `json:"per_person"`
+	Shares    []billShare `json:"shares,omitempty"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "split_bill",
+			Description: "Work out the tip on a bill and how much each person pays when splitting it. *Always* use this for tips and splitting bills rather than doing the arithmetic yourself, and give the amounts exactly as they're returned.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"total": {
+						Type:        genai.TypeNumber,
+						Format:      "double",
+						Description: "The bill before the tip.",
+						Nullable:    false,
+					},
+					"tip_percent": {
+						Type:        genai.TypeNumber,
+						Format:      "double",
+						Description: "The tip, as a percentage of the bill, e.g. 18. Omit or use 0 for no tip.",
+						Nullable:    true,
+					},
+					"people": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("How many people are splitting the bill. Defaults to 1, and can be at most %d.", maxBillPeople),
+						Nullable:    true,
+					},
+					"rounding": {
+						Type:        genai.TypeString,
+						Description: "How to round: \"none\" to split to the smallest coin, \"each\" to round each person's share up to a whole unit of currency, or \"total\" to round the total up to a whole unit. When rounding, the difference goes to the tip. Defaults to none.",
+						Nullable:    true,
+						Enum:        billRoundings,
+					},
+					"currency": {
+						Type:        genai.TypeString,
+						Description: "The ISO 4217 code of the currency the bill is in, e.g. \"USD\". Omit if the user didn't say, to use the one where they are.",
+						Nullable:    true,
+					},
+				},
+				Required: []string{"total"},
+			},
+		},
+		Fn:        splitBill,
+		Thought:   splitBillThought,
+		InputType: SplitBillInput{},
+		Offline:   true,
+	})
+}
+
+func splitBillThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.split_bill")
+}
+
+func splitBill(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "split_bill")
+	defer span.Send()
+	arg := args.(*SplitBillInput)
+	if arg.Total <= 0 || arg.Total > maxBillTotal || math.IsNaN(arg.Total) {
+		return Error{Error: "The bill must be a positive amount."}
+	}
+	if arg.TipPercent < 0 || arg.TipPercent > maxTipPercent || math.IsNaN(arg.TipPercent) {
+		return Error{Error: fmt.Sprintf("The tip must be between 0%% and %d%%.", maxTipPercent)}
+	}
+	people := arg.People
+	if people == 0 {
+		people = 1
+	}
+	if people < 1 || people > maxBillPeople {
+		return Error{Error: fmt.Sprintf("The bill can be split between 1 and %d people.", maxBillPeople)}
+	}
+	tag := language.Make(query.PreferredLanguageFromContext(ctx))
+	cur, ok := billCurrency(tag, arg.Currency)
+	if !ok {
+		return Error{Error: "Unknown currency code " + arg.Currency}
+	}
+	span.AddField("people", people)
+	span.AddField("rounding", arg.Rounding)
+
+	// Everything is worked out in the currency's smallest unit, so that the shares always add up to the total.
+	scale := 2
+	if cur != (currency.Unit{}) {
+		scale, _ = currency.Standard.Rounding(cur)
+	}
+	unit := int64(math.Pow10(scale))
+	bill := int64(math.Round(arg.Total * float64(unit)))
+	tip := int64(math.Round(float64(bill) * arg.TipPercent / 100))
+	total := bill + tip
+	switch arg.Rounding {
+	case "each":
+		share := roundUpTo((total+int64(people)-1)/int64(people), unit)
+		total = share * int64(people)
+	case "total":
+		total = roundUpTo(total, unit)
+	}
+	tip = total - bill
+
+	p := message.NewPrinter(tag)
+	format := func(amount int64) string {
+		value := float64(amount) / float64(unit)
+		if cur == (currency.Unit{}) {
+			return p.Sprint(number.Decimal(value, number.Scale(scale)))
+		}
+		return p.Sprint(currency.Symbol(cur.Amount(value)))
+	}
+	response := SplitBillResponse{
+		Bill:       format(bill),
+		Tip:        format(tip),
+		TipPercent: math.Round(float64(tip)*1000/float64(bill)) / 10,
+		Total:      format(total),
+		People:     people,
+	}
+	if cur != (currency.Unit{}) {
+		response.Currency = cur.String()
+	}
+	base, extra := total/int64(people), int(total%int64(people))
+	if extra == 0 {
+		response.PerPerson = format(base)
+	} else {
+		response.PerPerson = format(base + 1)
+		response.Shares = []billShare{
+			{People: extra, Amount: format(base + 1)},
+			{People: people - extra, Amount: format(base)},
+		}
+	}
+	return response
+}
+
+// billCurrency returns the currency with the given code or, if there isn't one, the currency of the user's region
+// if their locale says which it is. It returns the zero Unit if the currency isn't known, and false if the code is
+// invalid.
+func billCurrency(tag language.Tag, code string) (currency.Unit, bool) {
+	if code = strings.TrimSpace(code); code != "" {
+		cur, err := currency.ParseISO(code)
+		return cur, err == nil
+	}
+	region, confidence := tag.Region()
+	if confidence < language.High {
+		return currency.Unit{}, true
+	}
+	cur, _ := currency.FromRegion(region)
+	return cur, true
+}
+
+// roundUpTo rounds n up to a multiple of m.
+func roundUpTo(n, m int64) int64 {
+	return (n + m - 1) / m * m
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/sports"
+)
+
+type GetSportsFixturesInput struct {
+	// The name of the team, e.g. "Arsenal" or "Boston Celtics".
+	Team string `json:"team"`
+}
+
+type sportsEventResult struct {
+	ID        string `json:"id"`
+	Sport     string `json:"sport"`
+	League    string `json:"league"`
+	HomeTeam  string `json:"home_team"`
+	AwayTeam  string `json:"away_team"`
+	HomeScore *int   `json:"home_score,omitempty"`
+	AwayScore *int   `json:"away_score,omitempty"`
+	// One of "scheduled", "live", "finished" or "postponed".
+	State    string `json:"state"`
+	Progress string `json:"progress,omitempty"`
+	// When it starts, in the user's time zone.
+	Start string `json:"start"`
+}
+
+type SportsFixturesResponse struct {
+	Team   string              `json:"team"`
+	Events []sportsEventResult `json:"events"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_sports_fixtures",
+			Description: "Get a sports team's most recent results, any match in progress with its live score, and its upcoming fixtures. Use this for questions like \"did Arsenal win?\", \"what's the score in the Celtics game?\" or \"when do Liverpool play next?\".",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"team": {
+						Type:        genai.TypeString,
+						Description: "The name of the team, e.g. \"Arsenal\" or \"Boston Celtics\". Use the team's full English name.",
+						Nullable:    false,
+					},
+				},
+				Required: []string{"team"},
+			},
+		},
+		Fn:            getSportsFixtures,
+		LatencyBudget: 8 * time.Second,
+		FreshFor:      time.Minute,
+		Thought:       getSportsFixturesThought,
+		InputType:     GetSportsFixturesInput{},
+	})
+}
+
+func getSportsFixturesThought(ctx context.Context, args any) string {
+	arg := args.(*GetSportsFixturesInput)
+	return i18n.T(ctx, "thought.sports", thoughtArgument(arg.Team))
+}
+
+func getSportsFixtures(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "get_sports_fixtures")
+	defer span.Send()
+	arg := args.(*GetSportsFixturesInput)
+	span.AddField("team", arg.Team)
+
+	team, events, err := sports.TeamEvents(ctx, arg.Team)
+	if err != nil {
+		span.AddField("error", err)
+		if errors.Is(err, sports.ErrUnknownTeam) {
+			return Error{Error: fmt.Sprintf("No team called %q was found. Try the team's full name.", arg.Team)}
+		}
+		return upstreamError("Couldn't look up fixtures: ", err)
+	}
+	loc := time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	response := SportsFixturesResponse{Team: team, Events: []sportsEventResult{}}
+	for _, e := range events {
+		response.Events = append(response.Events, sportsEventResult{
+			ID:        e.ID,
+			Sport:     e.Sport,
+			League:    e.League,
+			HomeTeam:  e.HomeTeam,
+			AwayTeam:  e.AwayTeam,
+			HomeScore: e.HomeScore,
+			AwayScore: e.AwayScore,
+			State:     string(e.State),
+			Progress:  e.Progress,
+			Start:     e.Start.In(loc).Format(time.RFC3339),
+		})
+	}
+	return response
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+type StartStopwatchInput struct {
+	// Whether to carry on from where the stopwatch was stopped, rather than starting again from zero.
+	Resume bool `json:"resume"`
+}
+
+type StopwatchInput struct{}
+
+type stopwatchLap struct {
+	Lap int `json:"lap"`
+	// How long the lap took (or has taken so far, for the current one).
+	LapTime string `json:"lap_time"`
+	// The stopwatch's time at the end of the lap.
+	SplitTime string `json:"split_time,omitempty"`
+}
+
+type StopwatchResponse struct {
+	Running        bool           `json:"running"`
+	Elapsed        string         `json:"elapsed"`
+	ElapsedSeconds float64        `json:"elapsed_seconds"`
+	Laps           []stopwatchLap `json:"laps,omitempty"`
+	// Only given once there's been at least one lap.
+	CurrentLap *stopwatchLap `json:"current_lap,omitempty"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "start_stopwatch",
+			Description: "Start the stopwatch on the watch. This starts again from zero, clearing any laps, unless resume is set.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"resume": {
+						Type:        genai.TypeBoolean,
+						Description: "Only if the user asks to resume or continue the stopwatch, true to carry on from where it was stopped.",
+						Nullable:    true,
+					},
+				},
+			},
+		},
+		Cb:          startStopwatchImpl,
+		SideEffects: true,
+		Thought:     stopwatchThought("thought.stopwatch.start"),
+		InputType:   StartStopwatchInput{},
+		Capability:  "stopwatch",
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "stop_stopwatch",
+			Description: "Stop the stopwatch, keeping its time so it can be resumed later.",
+		},
+		Cb:          stopwatchImpl("stop"),
+		SideEffects: true,
+		Thought:     stopwatchThought("thought.stopwatch.stop"),
+		InputType:   StopwatchInput{},
+		Capability:  "stopwatch",
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "lap_stopwatch",
+			Description: "Record a lap on the running stopwatch.",
+		},
+		Cb:          stopwatchImpl("lap"),
+		SideEffects: true,
+		Thought:     stopwatchThought("thought.stopwatch.lap"),
+		InputType:   StopwatchInput{},
+		Capability:  "stopwatch",
+		Offline:     true,
+	})
+
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "get_stopwatch",
+			Description: "Get the stopwatch's time, whether it's running, and its laps, including how long the current lap has taken so far.",
+		},
+		Cb:         stopwatchImpl("get"),
+		Thought:    stopwatchThought("thought.stopwatch.get"),
+		InputType:  StopwatchInput{},
+		Capability: "stopwatch",
+		Offline:    true,
+	})
+}
+
+func stopwatchThought(key string) func(ctx context.Context, args any) string {
+	return func(ctx context.Context, args any) string {
+		return i18n.T(ctx, key)
+	}
+}
+
+func startStopwatchImpl(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
+	command := "start"
+	if args.(*StartStopwatchInput).Resume {
+		command = "resume"
+	}
+	return stopwatchImpl(command)(ctx, quotaTracker, args, requests, responses)
+}
+
+// stopwatchImpl returns an action that sends command to the watch's stopwatch, and describes its state afterwards.
+func stopwatchImpl(command string) func(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
+	return func(ctx context.Context, quotaTracker *quota.Tracker, args any, requests chan<- map[string]any, responses <-chan map[string]any) any {
+		ctx, span := beeline.StartSpan(ctx, "stopwatch")
+		defer span.Send()
+		span.AddField("command", command)
+		if !query.SupportsAction(ctx, "stopwatch") {
+			return Error{Error: "You need to update the app on your watch to use the stopwatch."}
+		}
+		requestid.Logf(ctx, "Asking watch to %s the stopwatch...\n", command)
+		requests <- map[string]any{
+			"action":  "stopwatch",
+			"command": command,
+		}
+		requestid.Logln(ctx, "Waiting for response...")
+		resp := <-responses
+		if _, failed := resp["error"]; failed {
+			return resp
+		}
+		elapsed, ok := resp["elapsed_ms"].(float64)
+		if !ok {
+			// The sandbox doesn't tell us anything about the stopwatch.
+			return resp
+		}
+		var laps []float64
+		if l, ok := resp["laps_ms"].([]any); ok {
+			for _, lap := range l {
+				if ms, ok := lap.(float64); ok {
+					laps = append(laps, ms)
+				}
+			}
+		}
+		running, _ := resp["running"].(bool)
+		return describeStopwatch(running, int64(elapsed), laps)
+	}
+}
+
+func describeStopwatch(running bool, elapsedMs int64, lapsMs []float64) StopwatchResponse {
+	response := StopwatchResponse{
+		Running:        running,
+		Elapsed:        formatStopwatchTime(elapsedMs),
+		ElapsedSeconds: float64(elapsedMs) / 1000,
+	}
+	var previous int64
+	for i, split := range lapsMs {
+		response.Laps = append(response.Laps, stopwatchLap{
+			Lap:       i + 1,
+			LapTime:   formatStopwatchTime(int64(split) - previous),
+			SplitTime: formatStopwatchTime(int64(split)),
+		})
+		previous = int64(split)
+	}
+	if len(lapsMs) > 0 {
+		response.CurrentLap = &stopwatchLap{
+			Lap:     len(lapsMs) + 1,
+			LapTime: formatStopwatchTime(elapsedMs - previous),
+		}
+	}
+	return response
+}
+
+// formatStopwatchTime formats a number of milliseconds as a stopwatch would show it, to a tenth of a second, e.g.
+// "1:02.3" or "1:00:02.3".
+func formatStopwatchTime(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	tenths := ms / 100
+	hours := tenths / 36000
+	minutes := tenths / 600 % 60
+	seconds := tenths / 10 % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d.%d", hours, minutes, seconds, tenths%10)
+	}
+	return fmt.Sprintf("%d:%02d.%d", minutes, seconds, tenths%10)
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// maxThoughtArgumentLength is the longest argument we'll interpolate into a thought. Thoughts are shown on a single
+// line or two of a watch screen, so anything longer than this is just noise.
+const maxThoughtArgumentLength = 30
+
+// thoughtArgument tidies up a model-provided argument for display in a thought, truncating it at a rune boundary if it
+// is too long.
+func thoughtArgument(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= maxThoughtArgumentLength {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:maxThoughtArgumentLength-1])) + "…"
+}
+
+// thoughtDuration formats a number of seconds as a short human-readable duration, e.g. "5 minutes" or
+// "1 hour 30 minutes", in the session's language.
+func thoughtDuration(ctx context.Context, seconds int) string {
+	hours := seconds / 3600
+	minutes := (seconds % 3600) / 60
+	seconds = seconds % 60
+	var parts []string
+	if hours > 0 {
+		parts = append(parts, i18n.Plural(ctx, "duration.hours", hours))
+	}
+	if minutes > 0 {
+		parts = append(parts, i18n.Plural(ctx, "duration.minutes", minutes))
+	}
+	if seconds > 0 && hours == 0 {
+		parts = append(parts, i18n.Plural(ctx, "duration.seconds", seconds))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatDuration formats a number of seconds the same way thoughts do, for responses generated without the model.
+func FormatDuration(ctx context.Context, seconds int) string {
+	return thoughtDuration(ctx, seconds)
+}
+
+// thoughtTime formats an ISO 8601 timestamp as a wall clock time in the user's timezone. If the timestamp can't be
+// parsed, the empty string is returned.
+func thoughtTime(ctx context.Context, timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+	t = t.In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60))
+	return t.Format(i18n.T(ctx, "format.time"))
+}
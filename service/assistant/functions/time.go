@@ -8,16 +8,23 @@ import (
 	"time"
 
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/timezone"
 )
 
 type TimeResponse struct {
 	Time string `json:"time"`
+	// The timezone the place is in, if the time was asked for by place.
+	Timezone string `json:"timezone,omitempty"`
 }
 
 type GetTimeInput struct {
 	// The timezone, e.g. 'America/Los_Angeles'.
-	Timezone string `json:"timezone" jsonschema:"required"`
+	Timezone string `json:"timezone"`
+	// The name of a place, as an alternative to the timezone.
+	Location string `json:"location"`
 	// The number of seconds to add to the current time.
 	Offset float64 `json:"offset"`
 }
@@ -26,15 +33,20 @@ func init() {
 	registerFunction(Registration{
 		Definition: genai.FunctionDeclaration{
 			Name:        "get_time_elsewhere",
-			Description: "Get the current time in a given valid tzdb timezone. Not all cities have a tzdb entry - be sure to use one that exists. Call multiple times to find the time in multiple timezones.",
+			Description: "Get the current time in a given place, or a given valid tzdb timezone. Not all cities have a tzdb entry, so prefer passing the place. Call multiple times to find the time in multiple places.",
 			Parameters: &genai.Schema{
 				Type:     genai.TypeObject,
 				Nullable: false,
 				Properties: map[string]*genai.Schema{
 					"timezone": {
 						Type:        genai.TypeString,
-						Description: "The timezone, e.g. 'America/Los_Angeles'.",
-						Nullable:    false,
+						Description: "The timezone, e.g. 'America/Los_Angeles'. Omit if passing location.",
+						Nullable:    true,
+					},
+					"location": {
+						Type:        genai.TypeString,
+						Description: "The name of the place to get the time in, e.g. 'Boise, Idaho'. Omit if passing timezone.",
+						Nullable:    true,
 					},
 					"offset": {
 						Type:        genai.TypeNumber,
@@ -42,7 +54,6 @@ func init() {
 						Format:      "double",
 					},
 				},
-				Required: []string{"timezone"},
 			},
 		},
 		Fn:        getTimeElsewhere,
@@ -51,14 +62,18 @@ func init() {
 	})
 }
 
-func getTimeThought(args any) string {
+func getTimeThought(ctx context.Context, args any) string {
 	arg := args.(*GetTimeInput)
+	if arg.Location != "" {
+		placeName, _, _ := strings.Cut(arg.Location, ",")
+		return i18n.T(ctx, "thought.time.place", thoughtArgument(placeName))
+	}
 	if arg.Timezone != "" {
 		s := strings.Split(arg.Timezone, "/")
 		place := strings.Replace(s[len(s)-1], "_", " ", -1)
-		return "Checking the time in " + place
+		return i18n.T(ctx, "thought.time.place", place)
 	}
-	return "Checking the time"
+	return i18n.T(ctx, "thought.time")
 }
 
 func getTimeElsewhere(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
@@ -66,10 +81,22 @@ func getTimeElsewhere(ctx context.Context, quotaTracker *quota.Tracker, args any
 	defer span.Send()
 	arg := args.(*GetTimeInput)
 	utc := time.Now().UTC().Add(time.Duration(arg.Offset) * time.Second)
+	if arg.Location != "" {
+		place, err := photon.GeocodeWithContext(ctx, arg.Location, photon.AnyPlace)
+		if err != nil {
+			span.AddField("error", err)
+			return geocodingError(err)
+		}
+		name := timezone.Name(place.Lat, place.Lon)
+		span.AddField("timezone", name)
+		return TimeResponse{Time: utc.In(timezone.Location(place.Lat, place.Lon)).Format(time.RFC1123), Timezone: name}
+	}
+	if arg.Timezone == "" {
+		return Error{"Either a timezone or a location is required."}
+	}
 	loc, err := time.LoadLocation(arg.Timezone)
 	if err != nil {
-		return Error{fmt.Sprintf("The timezone %q is not valid", arg.Timezone)}
+		return Error{fmt.Sprintf("The timezone %q is not valid. Try passing the place as the location instead.", arg.Timezone)}
 	}
-	utc.In(loc)
-	return TimeResponse{utc.In(loc).Format(time.RFC1123)}
+	return TimeResponse{Time: utc.In(loc).Format(time.RFC1123)}
 }
@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// ValidationError is returned to the model instead of calling a function when its arguments don't match the
+// function's declared parameters, so that it can correct them and try again.
+type ValidationError struct {
+	Error    string   `json:"error"`
+	Problems []string `json:"problems"`
+}
+
+// validateArgs checks the JSON arguments to a function against its declared parameters. It returns nil if they're
+// fine, or a ValidationError describing everything that's wrong with them.
+func validateArgs(fn string, schema *genai.Schema, args string) *ValidationError {
+	if schema == nil {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(args), &decoded); err != nil {
+		return &ValidationError{
+			Error:    "Invalid JSON: " + err.Error(),
+			Problems: []string{"the arguments must be a JSON object"},
+		}
+	}
+	problems := validateValue("", schema, decoded)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		Error:    fmt.Sprintf("Invalid arguments to %s. Fix the problems listed and call it again.", fn),
+		Problems: problems,
+	}
+}
+
+func validateValue(path string, schema *genai.Schema, value any) []string {
+	name := path
+	if name == "" {
+		name = "the arguments"
+	}
+	if value == nil {
+		if schema.Nullable || path == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s must not be null", name)}
+	}
+	switch schema.Type {
+	case genai.TypeObject:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an object, not %s", name, jsonTypeName(value))}
+		}
+		return validateObject(path, schema, obj)
+	case genai.TypeArray:
+		arr, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array, not %s", name, jsonTypeName(value))}
+		}
+		var problems []string
+		if schema.MinItems != nil && int64(len(arr)) < *schema.MinItems {
+			problems = append(problems, fmt.Sprintf("%s must have at least %d items", name, *schema.MinItems))
+		}
+		if schema.MaxItems != nil && int64(len(arr)) > *schema.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s must have at most %d items", name, *schema.MaxItems))
+		}
+		if schema.Items != nil {
+			for i, item := range arr {
+				problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), schema.Items, item)...)
+			}
+		}
+		return problems
+	case genai.TypeString:
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a string, not %s", name, jsonTypeName(value))}
+		}
+		if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, s) {
+			return []string{fmt.Sprintf("%s is %q, but must be one of %s", name, s, quoteAll(schema.Enum))}
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(s) {
+				return []string{fmt.Sprintf("%s is %q, which doesn't match the pattern %s", name, s, schema.Pattern)}
+			}
+		}
+		return nil
+	case genai.TypeNumber, genai.TypeInteger:
+		n, ok := value.(float64)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a number, not %s", name, jsonTypeName(value))}
+		}
+		if schema.Type == genai.TypeInteger && n != math.Trunc(n) {
+			return []string{fmt.Sprintf("%s must be a whole number, not %v", name, n)}
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			return []string{fmt.Sprintf("%s must be at least %v", name, *schema.Minimum)}
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			return []string{fmt.Sprintf("%s must be at most %v", name, *schema.Maximum)}
+		}
+		return nil
+	case genai.TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s must be true or false, not %s", name, jsonTypeName(value))}
+		}
+		return nil
+	}
+	return nil
+}
+
+func validateObject(path string, schema *genai.Schema, obj map[string]any) []string {
+	var problems []string
+	for _, field := range schema.Required {
+		if _, ok := obj[field]; !ok {
+			problems = append(problems, fmt.Sprintf("missing required field %s", joinPath(path, field)))
+		}
+	}
+	// Sort the keys so that the problems come out in the same order every time.
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fieldSchema, ok := schema.Properties[k]
+		if !ok {
+			if len(schema.Properties) > 0 {
+				problems = append(problems, fmt.Sprintf("unknown field %s (expected one of %s)", joinPath(path, k), quoteAll(sortedKeys(schema.Properties))))
+			}
+			continue
+		}
+		problems = append(problems, validateValue(joinPath(path, k), fieldSchema, obj[k])...)
+	}
+	return problems
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func sortedKeys(m map[string]*genai.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return "null"
+}
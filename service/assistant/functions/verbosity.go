@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/preferences"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+type SetVerbosityInput struct {
+	// One of "brief", "normal" or "detailed".
+	Verbosity string `json:"verbosity"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "set_verbosity",
+			Description: "Change how long your answers are from now on, when the user asks for more or less detail in general, e.g. \"give me more detail\" or \"keep it short\". Don't use this for a single question that just needs a longer answer.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"verbosity": {
+						Type:        genai.TypeString,
+						Description: "\"brief\" for one-line answers, \"normal\" for the usual short answers, or \"detailed\" for fuller explanations.",
+						Nullable:    false,
+						Enum:        preferences.Verbosities,
+					},
+				},
+				Required: []string{"verbosity"},
+			},
+		},
+		Fn:          setVerbosity,
+		SideEffects: true,
+		Thought:     setVerbosityThought,
+		InputType:   SetVerbosityInput{},
+		Offline:     true,
+	})
+}
+
+func setVerbosity(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "set_verbosity")
+	defer span.Send()
+	arg := args.(*SetVerbosityInput)
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok || requester.IsScheduled() {
+		return Error{Error: "The answer length can't be changed from here."}
+	}
+	store := preferences.Store{Redis: storage.GetRedis()}
+	prefs, err := store.Get(ctx, requester.UserID)
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Loading preferences failed: %v", err)
+		return Error{Error: "Changing the answer length failed."}
+	}
+	prefs.Verbosity = arg.Verbosity
+	if err := prefs.Validate(); err != nil {
+		return Error{Error: err.Error()}
+	}
+	if err := store.Put(ctx, requester.UserID, prefs); err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Storing preferences failed: %v", err)
+		return Error{Error: "Changing the answer length failed."}
+	}
+	span.AddField("verbosity", arg.Verbosity)
+	return map[string]any{
+		"status": "ok",
+		"note":   "This applies from the user's next question. Just confirm the change briefly.",
+	}
+}
+
+func setVerbosityThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.verbosity")
+}
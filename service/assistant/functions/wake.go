@@ -0,0 +1,194 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/weather"
+)
+
+// defaultPrepMinutes is how long we assume the user needs between waking up and leaving, if they don't say.
+const defaultPrepMinutes = 45
+
+type SuggestWakeTimeInput struct {
+	// The day to wake up on, in YYYY-MM-DD format. Defaults to tomorrow.
+	Date string `json:"date"`
+	// The time of the user's first commitment of the day, in ISO 8601 format.
+	FirstEventTime string `json:"first_event_time"`
+	// How long the user needs to get ready, in minutes.
+	PrepMinutes int `json:"prep_minutes"`
+	// How long it takes the user to get to their first commitment, in minutes.
+	TravelMinutes int `json:"travel_minutes"`
+}
+
+type WakeTimeOption struct {
+	// One of "sunrise", "before_sunrise", "first_event".
+	Kind string `json:"kind"`
+	// The suggested alarm time, in ISO 8601 format. This can be passed directly to set_alarm.
+	Time        string `json:"time"`
+	Description string `json:"description"`
+	Recommended bool   `json:"recommended,omitempty"`
+}
+
+type SuggestWakeTimeResponse struct {
+	Date    string           `json:"date"`
+	Sunrise string           `json:"sunrise"`
+	Options []WakeTimeOption `json:"options"`
+}
+
+func init() {
+	registerFunction(Registration{
+		Definition: genai.FunctionDeclaration{
+			Name:        "suggest_wake_time",
+			Description: "Suggest times to set a wake-up alarm for, based on sunrise at the user's location and, if known, when their first commitment of the day is. Present the options to the user and let them choose before calling set_alarm. If the user hasn't said when they need to be somewhere, don't make it up.",
+			Parameters: &genai.Schema{
+				Type:     genai.TypeObject,
+				Nullable: false,
+				Properties: map[string]*genai.Schema{
+					"date": {
+						Type:        genai.TypeString,
+						Description: "The day the user wants to wake up, in YYYY-MM-DD format. Defaults to tomorrow.",
+						Nullable:    true,
+					},
+					"first_event_time": {
+						Type:        genai.TypeString,
+						Description: "When the user's first commitment of the day starts, in ISO 8601 format, e.g. '2023-07-12T09:00:00-07:00'. Only provide this if the user told you.",
+						Nullable:    true,
+					},
+					"prep_minutes": {
+						Type:        genai.TypeInteger,
+						Description: fmt.Sprintf("How many minutes the user needs to get ready after waking up. Defaults to %d.", defaultPrepMinutes),
+						Nullable:    true,
+						Format:      "int32",
+					},
+					"travel_minutes": {
+						Type:        genai.TypeInteger,
+						Description: "How many minutes it takes the user to travel to their first commitment. Defaults to 0.",
+						Nullable:    true,
+						Format:      "int32",
+					},
+				},
+			},
+		},
+		Fn:        suggestWakeTime,
+		Thought:   suggestWakeTimeThought,
+		InputType: SuggestWakeTimeInput{},
+	})
+}
+
+func suggestWakeTimeThought(ctx context.Context, args any) string {
+	return i18n.T(ctx, "thought.wake_time")
+}
+
+func suggestWakeTime(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
+	ctx, span := beeline.StartSpan(ctx, "suggest_wake_time")
+	defer span.Send()
+	arg := args.(*SuggestWakeTimeInput)
+	location := query.CoarseLocationFromContext(ctx)
+	if location == nil {
+		span.AddField("error", "no location provided")
+		return Error{Error: "The user's location is needed to find out when sunrise is. Ask them to enable location in settings."}
+	}
+
+	userTz := time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)
+	date := time.Now().In(userTz).AddDate(0, 0, 1).Format(time.DateOnly)
+	if arg.Date != "" {
+		if _, err := time.Parse(time.DateOnly, arg.Date); err != nil {
+			return Error{Error: fmt.Sprintf("Invalid date %q: must be in YYYY-MM-DD format.", arg.Date)}
+		}
+		date = arg.Date
+	}
+	var deadline time.Time
+	if arg.FirstEventTime != "" {
+		event, err := time.Parse(time.RFC3339, arg.FirstEventTime)
+		if err != nil {
+			return Error{Error: fmt.Sprintf("Invalid first_event_time %q: must be in ISO 8601 format.", arg.FirstEventTime)}
+		}
+		prep := arg.PrepMinutes
+		if prep <= 0 {
+			prep = defaultPrepMinutes
+		}
+		deadline = event.Add(-time.Duration(prep+arg.TravelMinutes) * time.Minute).In(userTz)
+		// If the event is on a specific day, that's the day they're waking up.
+		date = deadline.Format(time.DateOnly)
+	}
+
+	sun, err := weather.GetSunTimes(ctx, location.Lat, location.Lon, date)
+	if err != nil {
+		span.AddField("error", err)
+		return weatherError("Couldn't find out when sunrise is: ", err)
+	}
+	sunrise := sun.Sunrise.In(userTz)
+	response := SuggestWakeTimeResponse{
+		Date:    date,
+		Sunrise: sunrise.Format(time.RFC3339),
+	}
+	now := time.Now()
+	sunriseOption := WakeTimeOption{
+		Kind:        "sunrise",
+		Time:        sunrise.Format(time.RFC3339),
+		Description: "Wake up with the sun.",
+	}
+	beforeSunriseOption := WakeTimeOption{
+		Kind:        "before_sunrise",
+		Time:        sunrise.Add(-30 * time.Minute).Format(time.RFC3339),
+		Description: "Wake up half an hour before sunrise, in time to see it.",
+	}
+	if deadline.IsZero() {
+		if sunrise.Before(now) {
+			return Error{Error: fmt.Sprintf("Sunrise on %s has already passed, so there's no wake time to suggest.", date)}
+		}
+		sunriseOption.Recommended = true
+		response.Options = []WakeTimeOption{sunriseOption}
+		if sunrise.Add(-30 * time.Minute).After(now) {
+			response.Options = append(response.Options, beforeSunriseOption)
+		}
+		return response
+	}
+
+	if deadline.Before(now) {
+		return Error{Error: "The user would already need to be up to make their first commitment on time, so there's no wake time to suggest."}
+	}
+	eventOption := WakeTimeOption{
+		Kind:        "first_event",
+		Time:        deadline.Format(time.RFC3339),
+		Description: "The latest the user can wake up and still make their first commitment on time.",
+	}
+	if sunrise.After(deadline) || sunrise.Before(now) {
+		// Waking with the sun would make them late (or it's already up), so only the deadline is really an option.
+		eventOption.Recommended = true
+		if sunrise.After(deadline) {
+			eventOption.Description += " Sunrise is too late to wait for."
+		}
+		response.Options = []WakeTimeOption{eventOption}
+		return response
+	}
+	// Sunrise is early enough, so that's the gentlest option, but they could sleep in until the deadline.
+	sunriseOption.Recommended = true
+	response.Options = []WakeTimeOption{sunriseOption, eventOption}
+	if sunrise.Add(-30 * time.Minute).After(now) {
+		response.Options = append(response.Options, beforeSunriseOption)
+	}
+	return response
+}
@@ -16,10 +16,13 @@ package functions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
@@ -32,8 +35,10 @@ type WeatherInput struct {
 	Location string `json:"location"`
 	// The user's unit preference
 	Unit string `json:"unit" jsonschema:"enum=imperial,enum=metric,enum=uk hybrid"`
-	// The kind of weather to return: current weather, the next 7 days, or the next 24 hours.
+	// The kind of weather to return: current weather, the next 14 days, or the next 24 hours.
 	Kind string `json:"kind" jsonschema:"enum=current,enum=forecast daily,enum=forecast hourly"`
+	// For daily forecasts, the day to return: today, tomorrow, this weekend, a weekday, or a date. Omit for every day.
+	Day string `json:"day"`
 }
 
 func init() {
@@ -58,36 +63,40 @@ func init() {
 					},
 					"kind": {
 						Type:        genai.TypeString,
-						Description: "The kind of weather to return: current weather, the next 7 days, or the next 24 hours.",
+						Description: "The kind of weather to return: current weather, the next 14 days, or the next 24 hours.",
 						Nullable:    false,
 						Enum:        []string{"current", "forecast daily", "forecast hourly"},
 					},
+					"day": {
+						Type:        genai.TypeString,
+						Description: "For daily forecasts, the day to return: 'today', 'tomorrow', 'this weekend', a weekday like 'Tuesday', or a date in YYYY-MM-DD format. Omit to return every day.",
+						Nullable:    true,
+					},
 				},
 				Required: []string{"unit", "kind"},
 			},
 		},
 		Fn:        getWeather,
+		FreshFor:  30 * time.Minute,
 		Thought:   weatherThought,
 		InputType: WeatherInput{},
 	})
 }
 
-func weatherThought(i any) string {
+func weatherThought(ctx context.Context, i any) string {
 	args := i.(*WeatherInput)
-	weatherType := "weather"
+	weatherType := "current"
 	switch args.Kind {
 	case "forecast daily":
-		weatherType = "daily forecast"
+		weatherType = "daily"
 	case "forecast hourly":
-		weatherType = "hourly forecast"
-	case "current":
-		weatherType = "weather"
+		weatherType = "hourly"
 	}
 	if args.Location == "" || args.Location == "here" {
-		return fmt.Sprintf("Checking the %s nearby...", weatherType)
+		return i18n.T(ctx, "thought.weather."+weatherType+".nearby")
 	}
 	placeName, _, _ := strings.Cut(args.Location, ",")
-	return fmt.Sprintf("Checking the %s in %s...", weatherType, placeName)
+	return i18n.T(ctx, "thought.weather."+weatherType+".place", thoughtArgument(placeName))
 }
 
 func getWeather(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
@@ -95,15 +104,15 @@ func getWeather(ctx context.Context, quotaTracker *quota.Tracker, args any) any
 	defer span.Send()
 	arg := args.(*WeatherInput)
 	var lat, lon float64
-	location := query.LocationFromContext(ctx)
+	location := query.CoarseLocationFromContext(ctx)
 	if arg.Location == "here" {
 		arg.Location = ""
 	}
 	if arg.Location != "" {
-		coords, err := photon.GeocodeWithContext(ctx, arg.Location)
+		coords, err := photon.GeocodeWithContext(ctx, arg.Location, photon.Settlement)
 		if err != nil {
 			span.AddField("error", err)
-			return Error{"Error finding location: " + err.Error()}
+			return geocodingError(err)
 		}
 		lat = coords.Lat
 		lon = coords.Lon
@@ -119,21 +128,46 @@ func getWeather(ctx context.Context, quotaTracker *quota.Tracker, args any) any
 	case "current":
 		return processCurrentWeather(ctx, lat, lon, arg.Unit)
 	case "forecast daily":
-		return processDailyForecast(ctx, lat, lon, arg.Unit)
+		return processDailyForecast(ctx, lat, lon, arg.Unit, arg.Day)
 	case "forecast hourly":
 		return processHourlyForecast(ctx, lat, lon, arg.Unit)
 	}
 	return Error{"invalid kind"}
 }
 
-func processDailyForecast(ctx context.Context, lat, lon float64, units string) any {
+func processDailyForecast(ctx context.Context, lat, lon float64, units, day string) any {
 	forecast, err := weather.GetDailyForecast(ctx, lat, lon, units)
 	if err != nil {
 		beeline.AddField(ctx, "error", err)
-		return Error{"Could not get forecast: " + err.Error()}
+		return weatherError("Could not get forecast: ", err)
+	}
+	var dates []string
+	if day != "" {
+		// Days are worked out in the place's own timezone, which its forecast is in, so "tomorrow" in Tokyo is
+		// Tokyo's tomorrow.
+		dates, err = weather.ResolveDays(day, forecast.Now())
+		if err != nil {
+			return Error{err.Error()}
+		}
+	}
+	indices := make([]int, 0, len(forecast.Date))
+	if dates == nil {
+		for i := range forecast.Date {
+			indices = append(indices, i)
+		}
+	} else {
+		for _, date := range dates {
+			i, err := forecast.DayIndex(date)
+			if err != nil {
+				return Error{err.Error()}
+			}
+			indices = append(indices, i)
+		}
 	}
 	response := map[string]any{}
-	for i, day := range forecast.DayOfWeek {
+	for _, i := range indices {
+		// The forecast covers two weeks, so weekday names alone would be ambiguous.
+		day := forecast.DayOfWeek[i] + " " + forecast.Date[i]
 		if i == 0 {
 			day += " (Today)"
 		}
@@ -151,14 +185,14 @@ func processDailyForecast(ctx context.Context, lat, lon float64, units string) a
 		}
 	}
 	//response["source"] = "The Weather Channel"
-	return response
+	return degradedWeather(response, forecast.AsOf)
 }
 
 func processHourlyForecast(ctx context.Context, lat, lon float64, units string) any {
 	hourly, err := weather.GetHourlyForecast(ctx, lat, lon, units)
 	if err != nil {
 		beeline.AddField(ctx, "error", err)
-		return Error{"Could not get forecast: " + err.Error()}
+		return weatherError("Could not get forecast: ", err)
 	}
 	var response []map[string]any
 	for i, t := range hourly.ValidTimeLocal {
@@ -180,14 +214,45 @@ func processHourlyForecast(ctx context.Context, lat, lon float64, units string)
 		response = append(response, entry)
 	}
 	// the thing that is returned must not be an array.
-	return map[string]any{"response": response}
+	return degradedWeather(map[string]any{"response": response}, hourly.AsOf)
 }
 
 func processCurrentWeather(ctx context.Context, lat, lon float64, units string) any {
 	observations, err := weather.GetCurrentConditions(ctx, lat, lon, units)
 	if err != nil {
 		beeline.AddField(ctx, "error", err)
-		return Error{"Could not get current conditions: " + err.Error()}
+		return weatherError("Could not get current conditions: ", err)
+	}
+	return degradedWeather(*observations, observations.AsOf)
+}
+
+// degradedWeather labels a weather result that came from an old report because the weather service couldn't be
+// reached, so that the model tells the user how old it is. Fresh results, with a zero asOf, are returned unchanged.
+func degradedWeather(result any, asOf time.Time) any {
+	if asOf.IsZero() {
+		return result
+	}
+	m, ok := resultAsMap(result)
+	if !ok {
+		return result
+	}
+	age := describeAge(time.Since(asOf))
+	m["degraded"] = true
+	m["as_of"] = age
+	m["note"] = "The weather service can't be reached right now, so this is the last weather we got for this area, from " + age + ". " +
+		"Tell the user that it's out of date and how old it is, and that conditions may have changed since. " +
+		"Days and hours are as they were when it was fetched."
+	return m
+}
+
+// weatherError returns the result for a failed weather lookup, with advice for the model where the failure is one the
+// user should hear about rather than one to work around.
+func weatherError(message string, err error) any {
+	switch {
+	case errors.Is(err, weather.ErrOutOfCoverage):
+		return Error{Error: message + err.Error() + ". Tell the user that there's no weather data for that location; don't guess."}
+	case errors.Is(err, weather.ErrRateLimited):
+		return TransientError{Error: message + err.Error() + ". If it still fails, ask the user to try again in a few minutes."}
 	}
-	return *observations
+	return upstreamError(message, err)
 }
@@ -20,13 +20,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
 	"google.golang.org/genai"
 )
 
@@ -45,6 +49,19 @@ var urlMap = map[string]string{
 	"bulbapedia": "https://bulbapedia.bulbagarden.net/",
 }
 
+// wikiURL returns the base URL of the wiki, with a trailing slash. WIKIPEDIA_URL can point Wikipedia elsewhere.
+func wikiURL(wiki string) string {
+	if u := config.GetConfig().WikipediaURL; wiki == "wikipedia" && u != "" {
+		return strings.TrimSuffix(u, "/") + "/"
+	}
+	return urlMap[wiki]
+}
+
+var wikiNames = map[string]string{
+	"wikipedia":  "Wikipedia",
+	"bulbapedia": "Bulbapedia",
+}
+
 func init() {
 	registerFunction(Registration{
 		Definition: genai.FunctionDeclaration{
@@ -75,18 +92,24 @@ func init() {
 			},
 		},
 		Fn:                        queryWiki,
+		LatencyBudget:             8 * time.Second,
+		FreshFor:                  time.Hour,
 		Thought:                   queryWikiThought,
 		RedactOutputInChatHistory: true,
 		InputType:                 WikiRequest{},
+		HiddenInKidMode:           true,
 	})
 }
 
-func queryWikiThought(i any) string {
+func queryWikiThought(ctx context.Context, i any) string {
 	args := i.(*WikiRequest)
 	if args.Query == "" {
-		return "Looking it up..."
+		return i18n.T(ctx, "thought.wiki")
+	}
+	if args.CompleteArticle {
+		return i18n.T(ctx, "thought.wiki.article.complete", thoughtArgument(args.Query))
 	}
-	return fmt.Sprintf("Looking up %q...", args.Query)
+	return i18n.T(ctx, "thought.wiki.article", thoughtArgument(args.Query))
 }
 
 func queryWiki(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
@@ -96,7 +119,7 @@ func queryWiki(ctx context.Context, quotaTracker *quota.Tracker, args any) any {
 	}
 	results, err := queryWikiInternal(ctx, req.Wiki, req.Query, req.CompleteArticle, true)
 	if err != nil {
-		return Error{Error: err.Error()}
+		return upstreamError("", err)
 	}
 	return &WikiResponse{
 		Results: results,
@@ -107,9 +130,9 @@ func queryWikiInternal(ctx context.Context, wiki, query string, completeArticle,
 	ctx, span := beeline.StartSpan(ctx, "query_wiki")
 	defer span.Send()
 	span.AddField("title", query)
-	log.Printf("Looking up %s article: %q (complete: %t)\n", wiki, query, completeArticle)
+	requestid.Logf(ctx, "Looking up %s article: %q (complete: %t)\n", wiki, query, completeArticle)
 	qs := url.QueryEscape(query)
-	u := urlMap[wiki] + "w/api.php?action=query&prop=revisions&rvprop=content&format=xml&titles=" + qs + "&rvslots=main"
+	u := wikiURL(wiki) + "w/api.php?action=query&prop=revisions&rvprop=content&format=xml&titles=" + qs + "&rvslots=main"
 	if !completeArticle {
 		u += "&rvsection=0"
 	}
@@ -117,8 +140,7 @@ func queryWikiInternal(ctx context.Context, wiki, query string, completeArticle,
 	if err != nil {
 		return "", err
 	}
-	request.Header.Set("User-Agent", "Bobby/0.1 (https://github.com/pebble-dev/bobby-assistant)")
-	response, err := http.DefaultClient.Do(request)
+	response, err := upstream.Client.Do(request)
 	if err != nil {
 		return "", err
 	}
@@ -148,6 +170,11 @@ func queryWikiInternal(ctx context.Context, wiki, query string, completeArticle,
 		}
 		return queryWikiInternal(ctx, wiki, searchResult[0], completeArticle, false)
 	}
+	Cite(ctx, Citation{
+		Source: wikiNames[wiki],
+		Title:  query,
+		URL:    wikiURL(wiki) + "wiki/" + url.PathEscape(strings.ReplaceAll(query, " ", "_")),
+	})
 	addendum := ""
 	if !completeArticle {
 		addendum = "\n\nThis was only the summary. If necessary, more information can be returned by repeating the query_wikipedia call with complete_article = true. You can always do this automatically, without prompting the user."
@@ -159,41 +186,40 @@ func searchWiki(ctx context.Context, wiki, query string) ([]string, error) {
 	ctx, span := beeline.StartSpan(ctx, "search_wikipedia")
 	defer span.Send()
 	span.AddField("query", query)
-	log.Printf("Searching %s for %q\n", wiki, query)
-	request, err := http.NewRequestWithContext(ctx, "GET", urlMap[wiki]+"w/api.php?action=opensearch&limit=5&namespace=0&format=json&redirects=resolve&search="+url.QueryEscape(query), nil)
+	requestid.Logf(ctx, "Searching %s for %q\n", wiki, query)
+	request, err := http.NewRequestWithContext(ctx, "GET", wikiURL(wiki)+"w/api.php?action=opensearch&limit=5&namespace=0&format=json&redirects=resolve&search="+url.QueryEscape(query), nil)
 	if err != nil {
-		log.Printf("Creating request failed: %v\n", err)
+		requestid.Logf(ctx, "Creating request failed: %v\n", err)
 		return nil, err
 	}
-	request.Header.Set("User-Agent", "bobby-service")
-	response, err := http.DefaultClient.Do(request)
+	response, err := upstream.Client.Do(request)
 	if err != nil {
-		log.Printf("Performing request failed: %v\n", err)
+		requestid.Logf(ctx, "Performing request failed: %v\n", err)
 		return nil, err
 	}
 	defer response.Body.Close()
 	if response.StatusCode != http.StatusOK {
 		content, err := io.ReadAll(response.Body)
-		log.Println(string(content))
+		requestid.Logln(ctx, string(content))
 		if err != nil {
-			log.Printf("%s search failed: %v\n", wiki, err)
+			requestid.Logf(ctx, "%s search failed: %v\n", wiki, err)
 			return nil, err
 		}
-		log.Printf("%s search failed: %v\n", wiki, string(content))
+		requestid.Logf(ctx, "%s search failed: %v\n", wiki, string(content))
 		return nil, err
 	}
 	var result []any
 	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
-		log.Printf("JSON decode failed: %v\n", err)
+		requestid.Logf(ctx, "JSON decode failed: %v\n", err)
 		return nil, err
 	}
-	log.Println(result)
+	requestid.Logln(ctx, result)
 	if len(result) < 2 {
-		log.Printf("Search results not in expected format")
+		requestid.Logf(ctx, "Search results not in expected format")
 		return nil, err
 	}
 	if titles, ok := result[1].([]any); ok {
-		log.Println(result[1])
+		requestid.Logln(ctx, result[1])
 		var stringTitles []string
 		for _, title := range titles {
 			if s, ok := title.(string); ok {
@@ -202,6 +228,6 @@ func searchWiki(ctx context.Context, wiki, query string) ([]string, error) {
 		}
 		return stringTitles, nil
 	}
-	log.Printf("Search results not in expected format")
+	requestid.Logf(ctx, "Search results not in expected format")
 	return nil, err
 }
@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package game keeps track of a game the user is playing with Bobby, like twenty questions or trivia, across the
+// turns of a conversation: which game it is, the score, and how many rounds are left.
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// Kind is a kind of game.
+type Kind string
+
+const (
+	TwentyQuestions Kind = "twenty_questions"
+	Trivia          Kind = "trivia"
+	WordGame        Kind = "word_game"
+)
+
+// Kinds lists every kind of game, for the function declarations.
+var Kinds = []string{string(TwentyQuestions), string(Trivia), string(WordGame)}
+
+// maxRounds is how many rounds each kind of game lasts, so that a game on a watch doesn't go on forever.
+var maxRounds = map[Kind]int{
+	TwentyQuestions: 20,
+	Trivia:          10,
+	WordGame:        10,
+}
+
+// MaxReplyTokens caps how long each of the model's replies can be while a game is being played. Games are played a
+// line at a time on a small screen, and a runaway reply spoils them.
+const MaxReplyTokens = 200
+
+// stateTTL matches how long the conversation itself is kept.
+const stateTTL = 10 * time.Minute
+
+var (
+	ErrUnknownKind = errors.New("unknown game")
+	ErrNoGame      = errors.New("no game is being played")
+)
+
+// State is where a game stands.
+type State struct {
+	Kind Kind `json:"kind"`
+	// Whatever Bobby has to remember without telling the user, such as the thing to guess in twenty questions.
+	Secret    string    `json:"secret,omitempty"`
+	Score     int       `json:"score"`
+	Round     int       `json:"round"`
+	MaxRounds int       `json:"max_rounds"`
+	Started   time.Time `json:"started"`
+}
+
+// Over reports whether every round of the game has been played.
+func (s State) Over() bool {
+	return s.Round >= s.MaxRounds
+}
+
+// Session holds the game being played in a conversation, if there is one.
+type Session struct {
+	mu    sync.Mutex
+	state *State
+}
+
+type sessionKey struct{}
+
+// WithSession returns a context carrying the game from earlier in the conversation, or no game if previous is nil.
+func WithSession(ctx context.Context, previous *State) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &Session{state: previous})
+}
+
+// FromContext returns the context's game session, or nil if it doesn't have one.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+	return s
+}
+
+// Start begins a new game, replacing any game already being played.
+func (s *Session) Start(kind Kind, secret string) (State, error) {
+	rounds, ok := maxRounds[kind]
+	if !ok {
+		return State{}, ErrUnknownKind
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = &State{
+		Kind:      kind,
+		Secret:    strings.TrimSpace(secret),
+		MaxRounds: rounds,
+		Started:   time.Now().UTC(),
+	}
+	return *s.state, nil
+}
+
+// Current returns the game being played, and whether there is one.
+func (s *Session) Current() (State, bool) {
+	if s == nil {
+		return State{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return State{}, false
+	}
+	return *s.state, true
+}
+
+// Record finishes a round, adding points to the score. If a new secret is given, it replaces the old one, for games
+// where each round has its own answer.
+func (s *Session) Record(points int, secret string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return State{}, ErrNoGame
+	}
+	if s.state.Over() {
+		return *s.state, nil
+	}
+	s.state.Round++
+	s.state.Score += points
+	if secret = strings.TrimSpace(secret); secret != "" {
+		s.state.Secret = secret
+	}
+	return *s.state, nil
+}
+
+// End stops the game, returning how it ended and whether there was one.
+func (s *Session) End() (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return State{}, false
+	}
+	st := *s.state
+	s.state = nil
+	return st, true
+}
+
+func stateKey(threadID string) string {
+	return "game:" + threadID
+}
+
+// Load returns the game being played in a conversation, or nil if there isn't one.
+func Load(ctx context.Context, rd *redis.Client, threadID string) (*State, error) {
+	ctx, span := beeline.StartSpan(ctx, "game.load")
+	defer span.Send()
+	j, err := rd.Get(ctx, stateKey(threadID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	var st State
+	if err := json.Unmarshal(j, &st); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	span.AddField("game", st.Kind)
+	return &st, nil
+}
+
+// Store saves the context's game under a conversation's thread ID, so that the next turn can carry on with it.
+func Store(ctx context.Context, rd *redis.Client, threadID string) error {
+	st, ok := FromContext(ctx).Current()
+	if !ok {
+		return nil
+	}
+	j, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return rd.Set(ctx, stateKey(threadID), j, stateTTL).Err()
+}
+
+// Prompt tells the model about the game being played, or returns "" if there isn't one.
+func Prompt(ctx context.Context) string {
+	st, ok := FromContext(ctx).Current()
+	if !ok {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You are playing a game of %s with the user. ", strings.ReplaceAll(string(st.Kind), "_", " "))
+	switch st.Kind {
+	case TwentyQuestions:
+		sb.WriteString("You have thought of something, and the user asks yes-or-no questions to guess it. Answer each question truthfully with yes, no, or sometimes, and never reveal the answer unless they guess it or run out of questions. ")
+	case Trivia:
+		sb.WriteString("Ask one trivia question at a time, then say whether the user's answer is right, giving the correct answer if it isn't. ")
+	case WordGame:
+		sb.WriteString("Give the user one word puzzle at a time, such as an anagram, a rhyme, or a word to guess from its definition. ")
+	}
+	if st.Secret != "" {
+		fmt.Fprintf(&sb, "The current answer, which the user must not be told until the round is over, is %q. ", st.Secret)
+	}
+	fmt.Fprintf(&sb, "The user's score is %d, and this is round %d of %d. ", st.Score, min(st.Round+1, st.MaxRounds), st.MaxRounds)
+	sb.WriteString("Call record_game_round at the end of every round, and end_game when the last round is over or the user wants to stop, then tell them their final score. " +
+		"If the user asks something unrelated, answer it normally and then offer to carry on with the game. " +
+		"Keep every reply to one or two short sentences: the game is played on a watch.\n")
+	return sb.String()
+}
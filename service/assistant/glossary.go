@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/glossary"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// maxGlossaryBodySize bounds the size of a glossary term sent to /glossary.
+const maxGlossaryBodySize = 4 * 1024
+
+// handleGlossary serves the glossary API for the phone app:
+//
+//	GET    /glossary?token=...           returns the user's glossary.
+//	POST   /glossary?token=...           adds the term in the JSON body, or replaces it if it's already there.
+//	DELETE /glossary?token=...&term=...  removes a term.
+func (s *Service) handleGlossary(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		requestid.Logf(ctx, "No token provided.")
+		http.Error(rw, "No token provided.", http.StatusNotFound)
+		return
+	}
+	userInfo, err := quota.GetUserInfo(ctx, token)
+	if err != nil {
+		requestid.Logf(ctx, "Error getting user info: %v", err)
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		terms, err := glossary.List(ctx, s.redis, userInfo.UserId)
+		if err != nil {
+			requestid.Logf(ctx, "Error listing glossary: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(rw, map[string]any{"terms": terms})
+	case http.MethodPost:
+		var term glossary.Term
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxGlossaryBodySize)).Decode(&term); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := glossary.Put(ctx, s.redis, userInfo.UserId, term); err != nil {
+			switch {
+			case errors.Is(err, glossary.ErrInvalid):
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, glossary.ErrTooMany):
+				http.Error(rw, fmt.Sprintf("The glossary can hold at most %d terms.", glossary.MaxTerms), http.StatusConflict)
+			default:
+				requestid.Logf(ctx, "Error storing glossary term: %v", err)
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		removed, err := glossary.Remove(ctx, s.redis, userInfo.UserId, r.URL.Query().Get("term"))
+		if err != nil {
+			requestid.Logf(ctx, "Error removing glossary term: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !removed {
+			http.Error(rw, "No such term.", http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
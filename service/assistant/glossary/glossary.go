@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package glossary stores the words each user uses that Bobby wouldn't otherwise know, like the names of their family
+// and pets, local slang or jargon from work, along with how speech recognition tends to mishear them.
+package glossary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxTerms is the most terms a user's glossary can hold.
+const MaxTerms = 100
+
+// MaxMisheard is the most mishearings a term can have.
+const MaxMisheard = 10
+
+// maxTermLength bounds the length of terms, meanings and mishearings, in bytes.
+const maxTermLength = 100
+
+var (
+	ErrTooMany = errors.New("too many glossary terms")
+	ErrInvalid = errors.New("invalid glossary term")
+)
+
+// Term is a word or phrase in a user's glossary.
+type Term struct {
+	Term string `json:"term"`
+	// What it means, e.g. "my daughter" or "the office canteen".
+	Meaning string `json:"meaning,omitempty"`
+	// What speech recognition hears instead, e.g. "shivawn" for "Siobhan".
+	MisheardAs []string `json:"misheard_as,omitempty"`
+}
+
+// Validate checks that the term is worth storing, and tidies it up.
+func (t *Term) Validate() error {
+	t.Term = strings.TrimSpace(t.Term)
+	t.Meaning = strings.TrimSpace(t.Meaning)
+	if t.Term == "" || len(t.Term) > maxTermLength || len(t.Meaning) > maxTermLength {
+		return ErrInvalid
+	}
+	var misheard []string
+	for _, m := range t.MisheardAs {
+		m = strings.TrimSpace(m)
+		if m == "" || strings.EqualFold(m, t.Term) {
+			continue
+		}
+		if len(m) > maxTermLength {
+			return ErrInvalid
+		}
+		misheard = append(misheard, m)
+	}
+	if len(misheard) > MaxMisheard {
+		return fmt.Errorf("%w: at most %d mishearings", ErrInvalid, MaxMisheard)
+	}
+	t.MisheardAs = misheard
+	return nil
+}
+
+func glossaryKey(userID int) string {
+	return fmt.Sprintf("glossary:%d", userID)
+}
+
+// termKey is what a term is stored under, so that changing its capitalisation replaces it.
+func termKey(term string) string {
+	return strings.ToLower(strings.TrimSpace(term))
+}
+
+// List returns the user's glossary, in alphabetical order.
+func List(ctx context.Context, rd *redis.Client, userID int) ([]Term, error) {
+	ctx, span := beeline.StartSpan(ctx, "glossary.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, glossaryKey(userID)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	terms := []Term{}
+	for _, e := range entries {
+		var t Term
+		if err := json.Unmarshal([]byte(e), &t); err != nil {
+			continue
+		}
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		return termKey(terms[i].Term) < termKey(terms[j].Term)
+	})
+	span.AddField("terms", len(terms))
+	return terms, nil
+}
+
+// Put adds a term to the user's glossary, replacing it if it's already there.
+func Put(ctx context.Context, rd *redis.Client, userID int, t Term) error {
+	ctx, span := beeline.StartSpan(ctx, "glossary.put")
+	defer span.Send()
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	key := glossaryKey(userID)
+	exists, err := rd.HExists(ctx, key, termKey(t.Term)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	if !exists {
+		count, err := rd.HLen(ctx, key).Result()
+		if err != nil {
+			span.AddField("error", err)
+			return err
+		}
+		if count >= MaxTerms {
+			return ErrTooMany
+		}
+	}
+	j, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return rd.HSet(ctx, key, termKey(t.Term), j).Err()
+}
+
+// Remove takes a term out of the user's glossary, returning false if it wasn't there.
+func Remove(ctx context.Context, rd *redis.Client, userID int, term string) (bool, error) {
+	removed, err := rd.HDel(ctx, glossaryKey(userID), termKey(term)).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
+
+// DeleteAll removes the user's whole glossary.
+func DeleteAll(ctx context.Context, rd *redis.Client, userID int) error {
+	return rd.Del(ctx, glossaryKey(userID)).Err()
+}
+
+// Repairer replaces the ways a glossary's terms are usually misheard with the terms themselves. Making one compiles its
+// patterns, so it's worth keeping for as long as the glossary stays the same.
+type Repairer struct {
+	rules []repairRule
+}
+
+type repairRule struct {
+	misheard *regexp.Regexp
+	term     string
+}
+
+// NewRepairer returns a Repairer for the given terms.
+func NewRepairer(terms []Term) *Repairer {
+	r := &Repairer{}
+	for _, t := range terms {
+		for _, m := range t.MisheardAs {
+			if m == "" {
+				continue
+			}
+			re, err := regexp.Compile(`(?i)` + regexp.QuoteMeta(m))
+			if err != nil {
+				continue
+			}
+			r.rules = append(r.rules, repairRule{misheard: re, term: t.Term})
+		}
+	}
+	return r
+}
+
+// Repair returns the text with the terms put back. Only whole words are replaced, ignoring case. Regular expressions'
+// \b only knows about ASCII, so words are told apart by hand, using any letters or digits.
+func (r *Repairer) Repair(text string) string {
+	for _, rule := range r.rules {
+		text = rule.apply(text)
+	}
+	return text
+}
+
+func (rule repairRule) apply(text string) string {
+	var sb strings.Builder
+	last, pos := 0, 0
+	for {
+		loc := rule.misheard.FindStringIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			sb.WriteString(text[last:start])
+			sb.WriteString(rule.term)
+			last, pos = end, end
+			continue
+		}
+		// It's part of a longer word, but there might be a whole one starting later on in this match.
+		_, size := utf8.DecodeRuneInString(text[start:])
+		pos = start + size
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)
+}
+
+type termsKey struct{}
+
+// WithTerms returns a context carrying the user's glossary, for the prompt.
+func WithTerms(ctx context.Context, terms []Term) context.Context {
+	return context.WithValue(ctx, termsKey{}, terms)
+}
+
+// Prompt tells the model about the terms in the context's glossary, or returns "" if there aren't any.
+func Prompt(ctx context.Context) string {
+	terms, _ := ctx.Value(termsKey{}).([]Term)
+	if len(terms) == 0 {
+		return ""
+	}
+	var descriptions []string
+	for _, t := range terms {
+		d := fmt.Sprintf("%q", t.Term)
+		if t.Meaning != "" {
+			d += " (" + t.Meaning + ")"
+		}
+		descriptions = append(descriptions, d)
+	}
+	return "The user uses these words, which you might not otherwise know: " + strings.Join(descriptions, "; ") + ". " +
+		"Spell them exactly as given. If something in the user's message sounds like one of them, it probably is.\n"
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glossary
+
+import "testing"
+
+func TestRepair(t *testing.T) {
+	r := NewRepairer([]Term{
+		{Term: "Siobhán", MisheardAs: []string{"shiv on"}},
+		{Term: "Kaffeehaus Zoë", MisheardAs: []string{"cafe zoë"}},
+		{Term: "Саша", MisheardAs: []string{"сача"}},
+		{Term: "Mbappé", MisheardAs: []string{"bap"}},
+	})
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"call shiv on now", "call Siobhán now"},
+		{"SHIV ON, are you there?", "Siobhán, are you there?"},
+		// \b doesn't see a word boundary next to a letter outside ASCII, so these were never found.
+		{"meet at cafe zoë tonight", "meet at Kaffeehaus Zoë tonight"},
+		{"скажи сача привет", "скажи Саша привет"},
+		{"сачами", "сачами"},
+		// For the same reason, these were taken for whole words.
+		{"bapé", "bapé"},
+		{"ébap", "ébap"},
+		{"bap", "Mbappé"},
+		{"bap bap", "Mbappé Mbappé"},
+	}
+	for _, tt := range tests {
+		if got := r.Repair(tt.text); got != tt.want {
+			t.Errorf("Repair(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
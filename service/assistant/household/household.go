@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package household keeps a directory of users for self-hosted deployments, so that one server can serve a whole
+// family's watches without an external user identification service. Each user is identified by their watch's timeline
+// token, and given their own user ID, under which everything else (quota, preferences, history and so on) is kept.
+package household
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// FirstUserID is the ID given to the first user, well clear of the IDs of Rebble accounts in case a deployment uses
+// both.
+const FirstUserID = 1_000_000_000
+
+// MaxNameLength is the longest a user's name can be, in characters.
+const MaxNameLength = 50
+
+const (
+	usersKey  = "household:users"
+	tokensKey = "household:tokens"
+	nextIDKey = "household:next_id"
+)
+
+var (
+	// ErrTokenInUse is returned by Create when another user already has the token.
+	ErrTokenInUse = errors.New("token is already in use")
+	// ErrInvalid is returned by Create when the name or token is missing or too long.
+	ErrInvalid = errors.New("invalid user")
+)
+
+// User is one member of the household.
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// The timeline token of the user's watch, which the app sends with every request.
+	Token   string    `json:"token"`
+	Created time.Time `json:"created"`
+}
+
+// Create adds a user with the given name and timeline token, and returns them with their new ID.
+func Create(ctx context.Context, rd *redis.Client, name, token string) (User, error) {
+	ctx, span := beeline.StartSpan(ctx, "household.create")
+	defer span.Send()
+	name = strings.TrimSpace(name)
+	token = strings.TrimSpace(token)
+	if name == "" || len([]rune(name)) > MaxNameLength || token == "" {
+		return User{}, ErrInvalid
+	}
+	// Claim the token first, so that two requests for the same token can't both succeed.
+	claimed, err := rd.HSetNX(ctx, tokensKey, token, "").Result()
+	if err != nil {
+		span.AddField("error", err)
+		return User{}, err
+	}
+	if !claimed {
+		return User{}, ErrTokenInUse
+	}
+	n, err := rd.Incr(ctx, nextIDKey).Result()
+	if err != nil {
+		span.AddField("error", err)
+		rd.HDel(ctx, tokensKey, token)
+		return User{}, err
+	}
+	u := User{ID: FirstUserID + int(n) - 1, Name: name, Token: token, Created: time.Now().UTC()}
+	j, err := json.Marshal(u)
+	if err != nil {
+		rd.HDel(ctx, tokensKey, token)
+		return User{}, err
+	}
+	if _, err := rd.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, usersKey, strconv.Itoa(u.ID), j)
+		p.HSet(ctx, tokensKey, token, u.ID)
+		return nil
+	}); err != nil {
+		span.AddField("error", err)
+		rd.HDel(ctx, tokensKey, token)
+		return User{}, err
+	}
+	return u, nil
+}
+
+// Lookup returns the user with the given timeline token, and whether there is one.
+func Lookup(ctx context.Context, rd *redis.Client, token string) (User, bool, error) {
+	ctx, span := beeline.StartSpan(ctx, "household.lookup")
+	defer span.Send()
+	id, err := rd.HGet(ctx, tokensKey, token).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && id == "") {
+		return User{}, false, nil
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return User{}, false, err
+	}
+	j, err := rd.HGet(ctx, usersKey, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return User{}, false, nil
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return User{}, false, err
+	}
+	var u User
+	if err := json.Unmarshal([]byte(j), &u); err != nil {
+		return User{}, false, err
+	}
+	return u, true, nil
+}
+
+// List returns every user, in the order they were added.
+func List(ctx context.Context, rd *redis.Client) ([]User, error) {
+	ctx, span := beeline.StartSpan(ctx, "household.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, usersKey).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	users := []User{}
+	for _, e := range entries {
+		var u User
+		if err := json.Unmarshal([]byte(e), &u); err != nil {
+			continue
+		}
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users, nil
+}
+
+// Remove deletes a user from the directory, so that their watch is no longer recognised, returning false if there's
+// no user with that ID. Their data is left alone.
+func Remove(ctx context.Context, rd *redis.Client, id int) (bool, error) {
+	ctx, span := beeline.StartSpan(ctx, "household.remove")
+	defer span.Send()
+	j, err := rd.HGet(ctx, usersKey, strconv.Itoa(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return false, err
+	}
+	var u User
+	if err := json.Unmarshal([]byte(j), &u); err != nil {
+		return false, err
+	}
+	if _, err := rd.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HDel(ctx, usersKey, strconv.Itoa(id))
+		p.HDel(ctx, tokensKey, u.Token)
+		return nil
+	}); err != nil {
+		span.AddField("error", err)
+		return false, err
+	}
+	return true, nil
+}
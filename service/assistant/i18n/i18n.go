@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n localizes the user-visible strings generated by the server (as opposed to those generated by the
+// model, which can already speak the user's language).
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+const fallbackLanguage = "en"
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogs maps a language code (e.g. "de") to a map from message keys to fmt-style format strings.
+var catalogs = map[string]map[string]string{}
+
+func init() {
+	entries, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		panic(fmt.Sprintf("reading message catalogs failed: %v", err))
+	}
+	for _, entry := range entries {
+		content, err := catalogFiles.ReadFile(path.Join("catalogs", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("reading message catalog %s failed: %v", entry.Name(), err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(content, &catalog); err != nil {
+			panic(fmt.Sprintf("parsing message catalog %s failed: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = catalog
+	}
+	if _, ok := catalogs[fallbackLanguage]; !ok {
+		panic("no message catalog for the fallback language")
+	}
+}
+
+// LanguageFromContext returns the catalog language to use for the session in ctx.
+func LanguageFromContext(ctx context.Context) string {
+	return baseLanguage(query.PreferredLanguageFromContext(ctx))
+}
+
+// baseLanguage turns a locale like "pt_BR" or "zu-ZA" into a catalog language like "pt" or "zu".
+func baseLanguage(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "_-"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// T returns the message with the given key, localized for the session in ctx and formatted with args.
+func T(ctx context.Context, key string, args ...any) string {
+	return TLang(LanguageFromContext(ctx), key, args...)
+}
+
+// TLang returns the message with the given key, localized into the given language and formatted with args. If there is
+// no translation available, the English message is used instead. If there is no English message either, the key itself
+// is returned, which at least makes the problem obvious.
+func TLang(language, key string, args ...any) string {
+	format, ok := catalogs[baseLanguage(language)][key]
+	if !ok {
+		format, ok = catalogs[fallbackLanguage][key]
+		if !ok {
+			log.Printf("No message found for key %q", key)
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Plural returns the message with the given key formatted with n, choosing between the key's ".one" and ".other"
+// variants as appropriate. This is a simplification that works for the languages we currently have catalogs for.
+func Plural(ctx context.Context, key string, n int) string {
+	if n == 1 {
+		return T(ctx, key+".one", n)
+	}
+	return T(ctx, key+".other", n)
+}
+
+// Has reports whether there is a message (in any language) with the given key.
+func Has(key string) bool {
+	_, ok := catalogs[fallbackLanguage][key]
+	return ok
+}
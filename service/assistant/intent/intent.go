@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package intent recognizes prompts that are simple enough to handle without asking the model at all, like "set a
+// timer for 10 minutes". It's deliberately conservative: anything it isn't sure about goes to the model as usual.
+package intent
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Kind string
+
+const (
+	// None means the prompt needs the model.
+	None Kind = ""
+	// SetTimer is a request for a timer of Intent.Seconds, with no name.
+	SetTimer Kind = "set_timer"
+	// CurrentTime is a request for the current local time.
+	CurrentTime Kind = "current_time"
+	// ServiceStatus is a question about whether Bobby is working, or how much of their quota the user has left.
+	ServiceStatus Kind = "service_status"
+)
+
+// Intent is what a prompt was recognized as asking for.
+type Intent struct {
+	Kind Kind
+	// For SetTimer, the length of the timer.
+	Seconds int
+}
+
+// maxTimerSeconds is the longest timer we'll set without asking the model. Anything longer is more likely to be a
+// misheard prompt than a real timer.
+const maxTimerSeconds = 24 * 60 * 60
+
+var (
+	// Filler that doesn't change what's being asked for.
+	leadingFiller  = regexp.MustCompile(`^(?:(?:hey |ok |okay )?bobby,? )?(?:please |can you |could you )?`)
+	trailingFiller = regexp.MustCompile(`(?:,? please)?[.!?]*$`)
+
+	timerPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^(?:set|start)(?: me)? an? timer for (.+)$`),
+		regexp.MustCompile(`^(?:set|start)(?: me)? an? (.+?) timer$`),
+		regexp.MustCompile(`^(?:an? )?timer for (.+)$`),
+		regexp.MustCompile(`^(?:an? )?(.+?) timer$`),
+	}
+	timePattern = regexp.MustCompile(`^(?:what(?:'s| is) the (?:current )?time|what time is it)(?: now| right now)?$`)
+
+	statusPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^(?:are you|is bobby|is the (?:service|server)) (?:down|broken|working|up|ok|okay|online)(?: right now| today| at the moment)?$`),
+		regexp.MustCompile(`^why (?:aren't you|are you not|isn't bobby|is bobby not) (?:answering|responding|working)(?: me)?$`),
+		regexp.MustCompile(`^what(?:'s| is) wrong with (?:you|bobby)$`),
+		regexp.MustCompile(`^how much (?:quota|usage) (?:do i have|have i got) (?:left|remaining)$`),
+		regexp.MustCompile(`^how much (?:of my )?quota have i used(?: this month)?$`),
+	}
+)
+
+// Classify returns what the prompt is asking for, if it's one of the few things we can do without the model, or an
+// Intent of Kind None otherwise. Only English prompts are recognized; users who haven't set a language get English.
+func Classify(language, prompt string) Intent {
+	if language != "en" && language != "" {
+		return Intent{}
+	}
+	p := strings.Join(strings.Fields(strings.ToLower(prompt)), " ")
+	p = strings.ReplaceAll(p, "’", "'")
+	p = leadingFiller.ReplaceAllString(p, "")
+	p = trailingFiller.ReplaceAllString(p, "")
+
+	if timePattern.MatchString(p) {
+		return Intent{Kind: CurrentTime}
+	}
+	for _, pattern := range statusPatterns {
+		if pattern.MatchString(p) {
+			return Intent{Kind: ServiceStatus}
+		}
+	}
+	for _, pattern := range timerPatterns {
+		m := pattern.FindStringSubmatch(p)
+		if m == nil {
+			continue
+		}
+		if seconds, ok := parseDuration(m[1]); ok && seconds <= maxTimerSeconds {
+			return Intent{Kind: SetTimer, Seconds: seconds}
+		}
+	}
+	return Intent{}
+}
+
+var unitSeconds = map[string]int{
+	"hour": 3600, "hours": 3600, "hr": 3600, "hrs": 3600,
+	"minute": 60, "minutes": 60, "min": 60, "mins": 60,
+	"second": 1, "seconds": 1, "sec": 1, "secs": 1,
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8,
+	"nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "fifteen": 15, "twenty": 20, "thirty": 30, "forty": 40,
+	"forty-five": 45, "fifty": 50, "sixty": 60, "ninety": 90,
+}
+
+// parseDuration parses durations like "10 minutes", "5 minute", "1 hour and 30 minutes" or "half an hour" into
+// seconds. Anything else at all in the string makes it fail, so that "5 minutes called pasta" goes to the model.
+func parseDuration(s string) (int, bool) {
+	if s == "half an hour" || s == "half hour" {
+		return 30 * 60, true
+	}
+	words := strings.Fields(strings.NewReplacer(",", " ", " and ", " ").Replace(s))
+	if len(words) == 0 || len(words)%2 != 0 {
+		return 0, false
+	}
+	total := 0
+	lastUnit := 0
+	for i := 0; i < len(words); i += 2 {
+		n, ok := numberWords[words[i]]
+		if !ok {
+			var err error
+			if n, err = strconv.Atoi(words[i]); err != nil || n <= 0 {
+				return 0, false
+			}
+		}
+		unit, ok := unitSeconds[words[i+1]]
+		// Units must come largest first, and only once each.
+		if !ok || (lastUnit != 0 && unit >= lastUnit) {
+			return 0, false
+		}
+		lastUnit = unit
+		total += n * unit
+	}
+	return total, true
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/functions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// In JSON mode the model writes its answer as a widgets.Answer, using structured output, rather than writing widget
+// tags into its text. Gemini can't use structured output in a request that offers it functions, so until it's ready
+// to answer the model is made to call a function on every step, and answerFunction is how it says it's ready. The
+// answer is then asked for on its own, with no functions.
+
+// answerFunction is the function the model calls in JSON mode when it has everything it needs to answer.
+const answerFunction = "ready_to_answer"
+
+var answerFunctionDeclaration = &genai.FunctionDeclaration{
+	Name:        answerFunction,
+	Description: "Call this when you have everything you need to answer the user, or if you don't need to call any other function to answer. You'll then be asked for your answer.",
+}
+
+// jsonModeEnabled reports whether the session in ctx should answer in JSON mode. Only apps from
+// WidgetJSONMinAppVersion on do, so that it can be rolled out gradually; the local model doesn't support it.
+func jsonModeEnabled(ctx context.Context) bool {
+	minVersion := config.GetConfig().WidgetJSONMinAppVersion
+	return minVersion != "" && query.SupportsAnyWidgets(ctx) && !functions.IsOffline(ctx) && query.AppVersionAtLeast(ctx, minVersion)
+}
+
+// generateJSONModeSentence explains how to include widgets in an answer written in JSON mode, in terms of the widget
+// tags described by generateWidgetSentence.
+func generateJSONModeSentence(ctx context.Context) string {
+	if !jsonModeEnabled(ctx) {
+		return ""
+	}
+	return "Your answer will be written as a list of parts, each either a short piece of text or a widget, instead of with the widget syntax above. " +
+		"Wherever the widget descriptions say to include a widget, add a widget part at that point instead, with the same attributes: " +
+		"WEATHER-CURRENT is type weather-current, WEATHER-SINGLE-DAY is weather-single-day, WEATHER-MULTI-DAY is weather-multi-day, TIMER is timer (with targetTime as target_time), " +
+		"SPORTS-FIXTURE is sports, PRONUNCIATION is pronunciation, PAGE is page, and NUMERIC-ANSWER is number. Never write widget syntax in the text. " +
+		"Until you're ready to answer, you must call a function on every step; call " + answerFunction + " when you're ready.\n"
+}
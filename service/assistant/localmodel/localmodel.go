@@ -0,0 +1,303 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localmodel answers with a small model running on the same network as the server, such as a llama.cpp server,
+// for deployments that should keep working when they can't reach Gemini. Only the basics are expected of it: short
+// answers, and calling the few functions that work without the internet.
+package localmodel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// RequestTimeout bounds each request to the local model. Small models on home hardware can be slow, but not this slow.
+const RequestTimeout = 60 * time.Second
+
+// remoteDownFor is how long to keep using the local model after Gemini last couldn't be reached, before trying Gemini
+// again.
+const remoteDownFor = time.Minute
+
+var (
+	mu              sync.Mutex
+	remoteDownUntil time.Time
+)
+
+// Enabled reports whether a local model is configured.
+func Enabled() bool {
+	return config.GetConfig().LocalModelURL != ""
+}
+
+// IsUnreachable reports whether err means Gemini couldn't be reached at all, as when the internet is down, rather
+// than that it answered with an error.
+func IsUnreachable(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr)
+}
+
+// MarkRemoteDown records that Gemini couldn't be reached, so that sessions use the local model for a while instead of
+// each waiting to find that out for themselves.
+func MarkRemoteDown() {
+	mu.Lock()
+	defer mu.Unlock()
+	remoteDownUntil = time.Now().Add(remoteDownFor)
+}
+
+// ShouldUse reports whether sessions should answer with the local model: one is configured, and Gemini couldn't be
+// reached recently.
+func ShouldUse() bool {
+	if !Enabled() {
+		return false
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return time.Now().Before(remoteDownUntil)
+}
+
+// GenerateContentStream asks the local model to continue the conversation, through the OpenAI-compatible chat API that
+// llama.cpp's server provides. It takes and returns the same types as genai's GenerateContentStream, so that the
+// session can use either, but the whole answer arrives as a single response. Only the system instruction, tools,
+// temperature and output token limit are taken from the config.
+func GenerateContentStream(ctx context.Context, contents []*genai.Content, cfg *genai.GenerateContentConfig) iter.Seq2[*genai.GenerateContentResponse, error] {
+	return func(yield func(*genai.GenerateContentResponse, error) bool) {
+		resp, err := generate(ctx, contents, cfg)
+		yield(resp, err)
+	}
+}
+
+type chatRequest struct {
+	Model       string        `json:"model,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+	Tools       []chatTool    `json:"tools,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	MaxTokens   *int64        `json:"max_tokens,omitempty"`
+	Seed        *int64        `json:"seed,omitempty"`
+}
+
+type chatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type toolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+		// The arguments, as a JSON object encoded as a string.
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function chatFunction `json:"function"`
+}
+
+type chatFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func generate(ctx context.Context, contents []*genai.Content, cfg *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	ctx, span := beeline.StartSpan(ctx, "localmodel.generate")
+	defer span.Send()
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(buildRequest(contents, cfg))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %w", err)
+	}
+	url := strings.TrimSuffix(config.GetConfig().LocalModelURL, "/") + "/v1/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := requestid.Client.Do(req)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		span.AddField("error", resp.Status)
+		return nil, fmt.Errorf("local model returned %s", resp.Status)
+	}
+	var chat chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		span.AddField("error", err)
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	if len(chat.Choices) == 0 {
+		return nil, errors.New("local model returned no choices")
+	}
+	span.AddField("finish_reason", chat.Choices[0].FinishReason)
+	return toResponse(chat), nil
+}
+
+func buildRequest(contents []*genai.Content, cfg *genai.GenerateContentConfig) chatRequest {
+	req := chatRequest{Model: config.GetConfig().LocalModelName}
+	if cfg != nil {
+		req.Temperature = cfg.Temperature
+		req.MaxTokens = cfg.MaxOutputTokens
+		req.Seed = cfg.Seed
+		if cfg.SystemInstruction != nil {
+			req.Messages = append(req.Messages, chatMessage{Role: "system", Content: partsText(cfg.SystemInstruction.Parts)})
+		}
+		for _, t := range cfg.Tools {
+			for _, d := range t.FunctionDeclarations {
+				req.Tools = append(req.Tools, chatTool{
+					Type: "function",
+					Function: chatFunction{
+						Name:        d.Name,
+						Description: d.Description,
+						Parameters:  schemaToJSON(d.Parameters),
+					},
+				})
+			}
+		}
+	}
+	// Function responses have to say which call they answer. Gemini doesn't always give calls IDs, but a call is
+	// always followed by its response, so we make them up as we go.
+	callIDs := map[string]string{}
+	for i, c := range contents {
+		for _, p := range c.Parts {
+			switch {
+			case p.FunctionCall != nil:
+				call := toolCall{ID: fmt.Sprintf("call_%d", i), Type: "function"}
+				call.Function.Name = p.FunctionCall.Name
+				args, _ := json.Marshal(p.FunctionCall.Args)
+				call.Function.Arguments = string(args)
+				callIDs[p.FunctionCall.Name] = call.ID
+				req.Messages = append(req.Messages, chatMessage{Role: "assistant", ToolCalls: []toolCall{call}})
+			case p.FunctionResponse != nil:
+				result, _ := json.Marshal(p.FunctionResponse.Response)
+				req.Messages = append(req.Messages, chatMessage{Role: "tool", Content: string(result), ToolCallID: callIDs[p.FunctionResponse.Name]})
+			case p.Text != "":
+				role := "user"
+				if c.Role == "model" {
+					role = "assistant"
+				}
+				req.Messages = append(req.Messages, chatMessage{Role: role, Content: p.Text})
+			}
+		}
+	}
+	return req
+}
+
+func partsText(parts []*genai.Part) string {
+	var text strings.Builder
+	for _, p := range parts {
+		text.WriteString(p.Text)
+	}
+	return text.String()
+}
+
+// schemaToJSON converts a Gemini schema to the JSON Schema the chat API expects.
+func schemaToJSON(s *genai.Schema) map[string]any {
+	if s == nil {
+		return map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	j := map[string]any{}
+	if s.Type != "" {
+		j["type"] = strings.ToLower(string(s.Type))
+	}
+	if s.Description != "" {
+		j["description"] = s.Description
+	}
+	if len(s.Enum) > 0 {
+		j["enum"] = s.Enum
+	}
+	if s.Pattern != "" {
+		j["pattern"] = s.Pattern
+	}
+	if s.Minimum != nil {
+		j["minimum"] = *s.Minimum
+	}
+	if s.Maximum != nil {
+		j["maximum"] = *s.Maximum
+	}
+	if s.Items != nil {
+		j["items"] = schemaToJSON(s.Items)
+	}
+	if s.Properties != nil {
+		properties := map[string]any{}
+		for name, p := range s.Properties {
+			properties[name] = schemaToJSON(p)
+		}
+		j["properties"] = properties
+	}
+	if len(s.Required) > 0 {
+		j["required"] = s.Required
+	}
+	return j
+}
+
+func toResponse(chat chatResponse) *genai.GenerateContentResponse {
+	message := chat.Choices[0].Message
+	content := &genai.Content{Role: "model"}
+	if message.Content != "" {
+		content.Parts = append(content.Parts, &genai.Part{Text: message.Content})
+	}
+	for _, call := range message.ToolCalls {
+		var args map[string]any
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			// Small models don't always manage valid JSON; calling with no arguments gets it a useful error.
+			args = map[string]any{}
+		}
+		content.Parts = append(content.Parts, &genai.Part{FunctionCall: &genai.FunctionCall{Name: call.Function.Name, Args: args}})
+	}
+	promptTokens := chat.Usage.PromptTokens
+	completionTokens := chat.Usage.CompletionTokens
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: content}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:     &promptTokens,
+			CandidatesTokenCount: &completionTokens,
+			TotalTokenCount:      promptTokens + completionTokens,
+		},
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pages"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/widgets"
+)
+
+// handlePage returns one page of a document written with write_pages, for the watch to fetch when the user asks for
+// the next one:
+//
+//	GET /page?token=...&document=...&page=N
+//
+// Fetching a page also records it as how far the user has read, so they can carry on from there later.
+func (s *Service) handlePage(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := beeline.StartSpan(r.Context(), "handle_page")
+	defer span.Send()
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		requestid.Logf(ctx, "No token provided.")
+		http.Error(rw, "No token provided.", http.StatusNotFound)
+		return
+	}
+	userInfo, err := quota.GetUserInfo(ctx, token)
+	if err != nil {
+		requestid.Logf(ctx, "Error getting user info: %v", err)
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		http.Error(rw, "Invalid page number.", http.StatusBadRequest)
+		return
+	}
+	widget, err := widgets.NewPageWidget(ctx, userInfo.UserId, r.URL.Query().Get("document"), page)
+	if err != nil {
+		if errors.Is(err, pages.ErrNotFound) {
+			http.Error(rw, "No such page.", http.StatusNotFound)
+			return
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Error loading page: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := json.Marshal(widget)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(response)
+}
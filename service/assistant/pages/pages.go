@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pages stores long pieces of writing, like a bedtime story, split into pages that are small enough to send
+// to the watch one at a time. It also remembers how far each user got, so that they can carry on later.
+package pages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxPageBytes is the most text a page can hold. Longer pages are split, so that each one fits in a single message
+// to the watch along with the rest of the page widget.
+const MaxPageBytes = 500
+
+// MaxPages is the most pages a document can have, after any long ones have been split.
+const MaxPages = 40
+
+// documentTTL is how long a document is kept for the user to come back to.
+const documentTTL = 30 * 24 * time.Hour
+
+var (
+	ErrEmpty    = errors.New("the document has no text")
+	ErrTooLong  = errors.New("the document has too many pages")
+	ErrNotFound = errors.New("no such document")
+)
+
+// Page is one page of a document. Its title is optional, e.g. the name of a chapter.
+type Page struct {
+	Title string `json:"title,omitempty"`
+	Text  string `json:"text"`
+}
+
+// Document is a titled piece of writing, split into pages.
+type Document struct {
+	ID      string    `json:"id"`
+	UserID  int       `json:"user_id"`
+	Title   string    `json:"title"`
+	Pages   []Page    `json:"pages"`
+	Created time.Time `json:"created"`
+}
+
+// Position is how far a user has read into a document. Page numbers start from 1.
+type Position struct {
+	Document string `json:"document"`
+	Page     int    `json:"page"`
+}
+
+func documentKey(id string) string {
+	return "document:" + id
+}
+
+func positionKey(userID int) string {
+	return fmt.Sprintf("reading_position:%d", userID)
+}
+
+// Create stores a new document for the user, splitting any pages longer than MaxPageBytes.
+func Create(ctx context.Context, rd *redis.Client, userID int, title string, pages []Page) (Document, error) {
+	ctx, span := beeline.StartSpan(ctx, "pages.create")
+	defer span.Send()
+	doc := Document{
+		ID:      uuid.NewString(),
+		UserID:  userID,
+		Title:   strings.TrimSpace(title),
+		Created: time.Now().UTC(),
+	}
+	for _, p := range pages {
+		text := strings.TrimSpace(p.Text)
+		for i, chunk := range split(text, MaxPageBytes) {
+			page := Page{Text: chunk}
+			// Only the first part of a split page gets its title, so the watch doesn't show it over and over.
+			if i == 0 {
+				page.Title = strings.TrimSpace(p.Title)
+			}
+			doc.Pages = append(doc.Pages, page)
+		}
+	}
+	span.AddField("pages", len(doc.Pages))
+	if len(doc.Pages) == 0 {
+		return Document{}, ErrEmpty
+	}
+	if len(doc.Pages) > MaxPages {
+		return Document{}, ErrTooLong
+	}
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return Document{}, err
+	}
+	if err := rd.Set(ctx, documentKey(doc.ID), j, documentTTL).Err(); err != nil {
+		span.AddField("error", err)
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+// Get returns one of the user's documents. Documents belonging to anyone else are reported as not existing.
+func Get(ctx context.Context, rd *redis.Client, userID int, id string) (Document, error) {
+	ctx, span := beeline.StartSpan(ctx, "pages.get")
+	defer span.Send()
+	j, err := rd.Get(ctx, documentKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Document{}, ErrNotFound
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return Document{}, err
+	}
+	var doc Document
+	if err := json.Unmarshal(j, &doc); err != nil {
+		span.AddField("error", err)
+		return Document{}, err
+	}
+	if doc.UserID != userID {
+		return Document{}, ErrNotFound
+	}
+	return doc, nil
+}
+
+// Page returns a page of the document, numbered from 1, and whether it has that page.
+func (d Document) Page(n int) (Page, bool) {
+	if n < 1 || n > len(d.Pages) {
+		return Page{}, false
+	}
+	return d.Pages[n-1], true
+}
+
+// SetPosition records that the user has got as far as the given page of a document.
+func SetPosition(ctx context.Context, rd *redis.Client, userID int, pos Position) error {
+	j, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	return rd.Set(ctx, positionKey(userID), j, documentTTL).Err()
+}
+
+// LastPosition returns how far the user got in the last document they read, and whether they've read one.
+func LastPosition(ctx context.Context, rd *redis.Client, userID int) (Position, bool, error) {
+	j, err := rd.Get(ctx, positionKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Position{}, false, nil
+	}
+	if err != nil {
+		return Position{}, false, err
+	}
+	var pos Position
+	if err := json.Unmarshal(j, &pos); err != nil {
+		return Position{}, false, err
+	}
+	return pos, true, nil
+}
+
+// DeletePosition forgets how far the user got in the last document they read. The documents themselves expire on their
+// own.
+func DeletePosition(ctx context.Context, rd *redis.Client, userID int) error {
+	return rd.Del(ctx, positionKey(userID)).Err()
+}
+
+// split breaks text into pieces of at most max bytes, preferring to break between paragraphs, then sentences, then
+// words.
+func split(text string, max int) []string {
+	var pieces []string
+	for len(text) > max {
+		cut := breakPoint(text, max)
+		pieces = append(pieces, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+	if text != "" {
+		pieces = append(pieces, text)
+	}
+	return pieces
+}
+
+// breakPoint returns where to end a page of text that has to fit in max bytes.
+func breakPoint(text string, max int) int {
+	s := text[:max]
+	// Don't break so early that the page is mostly empty.
+	minimum := max / 2
+	if i := strings.LastIndex(s, "\n\n"); i > minimum {
+		return i
+	}
+	for _, end := range []string{". ", "! ", "? ", "\n"} {
+		if i := strings.LastIndex(s, end); i > minimum {
+			return i + len(end)
+		}
+	}
+	if i := strings.LastIndex(s, " "); i > minimum {
+		return i + 1
+	}
+	// There's nowhere good to break, so just avoid splitting a character.
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return cut
+}
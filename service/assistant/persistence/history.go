@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/functions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// MaxHistoryTurns is the number of turns kept in each user's history.
+const MaxHistoryTurns = 50
+
+// HistoryRetention is how long a user's history is kept after their most recent turn.
+const HistoryRetention = 30 * 24 * time.Hour
+
+// Turn is a single question and answer, as shown in the phone app's history view.
+type Turn struct {
+	ThreadID string     `json:"thread_id"`
+	Time     time.Time  `json:"time"`
+	Prompt   string     `json:"prompt"`
+	Response []TurnPart `json:"response"`
+	// The sources the answer was based on, if any.
+	Citations []functions.Citation `json:"citations,omitempty"`
+	// The credits required by the data providers used for the answer.
+	Attributions []upstream.Attribution `json:"attributions,omitempty"`
+	// The ID of the request that produced the turn, for matching a user's report against the server's logs.
+	RequestID string `json:"request_id,omitempty"`
+	// The request ID of the earlier turn the conversation was forked from, if it was.
+	ForkedFrom string `json:"forked_from,omitempty"`
+	// Whether the user has pinned the turn. This isn't stored with it, but filled in when the history is shown.
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// TurnPart is one piece of a response: either some text, or a widget.
+type TurnPart struct {
+	Text   string          `json:"text,omitempty"`
+	Widget json.RawMessage `json:"widget,omitempty"`
+}
+
+func historyKey(userID int) string {
+	return fmt.Sprintf("history:%d", userID)
+}
+
+// AppendTurn adds a turn to the user's history, discarding the oldest turns if there are too many.
+func AppendTurn(ctx context.Context, r *redis.Client, userID int, turn Turn) error {
+	j, err := json.Marshal(turn)
+	if err != nil {
+		return err
+	}
+	key := historyKey(userID)
+	_, err = r.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, j)
+		pipe.LTrim(ctx, key, 0, MaxHistoryTurns-1)
+		pipe.Expire(ctx, key, HistoryRetention)
+		return nil
+	})
+	return err
+}
+
+// LoadHistory returns up to n of the user's most recent turns, oldest first.
+func LoadHistory(ctx context.Context, r *redis.Client, userID int, n int) ([]Turn, error) {
+	entries, err := r.LRange(ctx, historyKey(userID), 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	turns := make([]Turn, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		var t Turn
+		if err := json.Unmarshal([]byte(entries[i]), &t); err != nil {
+			return nil, err
+		}
+		turns = append(turns, t)
+	}
+	return turns, nil
+}
+
+// FindTurn returns the turn in the user's history with the given request ID, and whether there is one.
+func FindTurn(ctx context.Context, r *redis.Client, userID int, requestID string) (Turn, bool, error) {
+	turns, err := LoadHistory(ctx, r, userID, MaxHistoryTurns)
+	if err != nil {
+		return Turn{}, false, err
+	}
+	for _, t := range turns {
+		if t.RequestID == requestID {
+			return t, true, nil
+		}
+	}
+	return Turn{}, false, nil
+}
+
+// DeleteHistory removes the user's entire history.
+func DeleteHistory(ctx context.Context, r *redis.Client, userID int) error {
+	return r.Del(ctx, historyKey(userID)).Err()
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pins"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// maxPinBodySize bounds the size of a pin request.
+const maxPinBodySize = 4 * 1024
+
+// defaultPinTitleLength is how much of the prompt is used as a pin's title if it isn't given one.
+const defaultPinTitleLength = 60
+
+type pinRequest struct {
+	// The request ID of the turn to pin, from the history.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// handlePins serves the pinned answers API for the phone app:
+//
+//	GET    /pins?token=...       returns the user's pins.
+//	POST   /pins?token=...       pins the turn in the user's history with the request ID in the JSON body's "id", or
+//	                             renames it if it's already pinned. The body may also give it a "title".
+//	DELETE /pins?token=...&id=.. unpins it.
+func (s *Service) handlePins(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		requestid.Logf(ctx, "No token provided.")
+		http.Error(rw, "No token provided.", http.StatusNotFound)
+		return
+	}
+	userInfo, err := quota.GetUserInfo(ctx, token)
+	if err != nil {
+		requestid.Logf(ctx, "Error getting user info: %v", err)
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		list, err := pins.List(ctx, s.redis, userInfo.UserId)
+		if err != nil {
+			requestid.Logf(ctx, "Error listing pins: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(rw, map[string]any{"pins": list})
+	case http.MethodPost:
+		var req pinRequest
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxPinBodySize)).Decode(&req); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ID == "" {
+			http.Error(rw, "No ID provided.", http.StatusBadRequest)
+			return
+		}
+		req.Title = strings.TrimSpace(req.Title)
+		if len([]rune(req.Title)) > pins.MaxTitleLength {
+			http.Error(rw, fmt.Sprintf("Titles can be at most %d characters.", pins.MaxTitleLength), http.StatusBadRequest)
+			return
+		}
+		pin, ok, err := pins.Get(ctx, s.redis, userInfo.UserId, req.ID)
+		if err != nil {
+			requestid.Logf(ctx, "Error loading pin: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			turn, found, err := persistence.FindTurn(ctx, s.redis, userInfo.UserId, req.ID)
+			if err != nil {
+				requestid.Logf(ctx, "Error loading history: %v", err)
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !found {
+				http.Error(rw, "No such turn in the history.", http.StatusNotFound)
+				return
+			}
+			pin = pinFromTurn(turn)
+		}
+		if req.Title != "" {
+			pin.Title = req.Title
+		}
+		if err := pins.Put(ctx, s.redis, userInfo.UserId, pin); err != nil {
+			if errors.Is(err, pins.ErrTooMany) {
+				http.Error(rw, fmt.Sprintf("You can have at most %d pinned answers.", pins.MaxPerUser), http.StatusConflict)
+				return
+			}
+			requestid.Logf(ctx, "Error storing pin: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(rw, pin)
+	case http.MethodDelete:
+		removed, err := pins.Remove(ctx, s.redis, userInfo.UserId, r.URL.Query().Get("id"))
+		if err != nil {
+			requestid.Logf(ctx, "Error removing pin: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !removed {
+			http.Error(rw, "No such pin.", http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+// pinFromTurn copies a turn into a pin, titled with the start of its prompt.
+func pinFromTurn(turn persistence.Turn) pins.Pin {
+	title := strings.TrimSpace(turn.Prompt)
+	if runes := []rune(title); len(runes) > defaultPinTitleLength {
+		title = strings.TrimSpace(string(runes[:defaultPinTitleLength-1])) + "…"
+	}
+	pin := pins.Pin{
+		ID:       turn.RequestID,
+		Title:    title,
+		ThreadID: turn.ThreadID,
+		Prompt:   turn.Prompt,
+		Time:     turn.Time,
+		PinnedAt: time.Now().UTC(),
+	}
+	for _, p := range turn.Response {
+		pin.Response = append(pin.Response, pins.Part{Text: p.Text, Widget: p.Widget})
+	}
+	return pin
+}
+
+func writeJSON(rw http.ResponseWriter, v any) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(j)
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pins stores the answers users have pinned from their history in the phone app, like a recipe or a
+// confirmation number. Unlike the history, pins are kept until the user removes them.
+package pins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxPerUser is the most answers a user can have pinned at once.
+const MaxPerUser = 50
+
+// MaxTitleLength is the longest a pin's title can be, in characters.
+const MaxTitleLength = 100
+
+// ErrTooMany is returned by Put when the user already has MaxPerUser pins.
+var ErrTooMany = errors.New("too many pins")
+
+// Part is one piece of a pinned answer: either some text, or a widget.
+type Part struct {
+	Text   string          `json:"text,omitempty"`
+	Widget json.RawMessage `json:"widget,omitempty"`
+}
+
+// Pin is a single question and answer the user wants to keep.
+type Pin struct {
+	// The ID of the request that produced the answer, which identifies it in the user's history.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// The conversation the answer came from.
+	ThreadID string    `json:"thread_id"`
+	Prompt   string    `json:"prompt"`
+	Response []Part    `json:"response"`
+	Time     time.Time `json:"time"`
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+// Text returns the text of the pinned answer, without its widgets.
+func (p Pin) Text() string {
+	text := ""
+	for _, part := range p.Response {
+		text += part.Text
+	}
+	return text
+}
+
+func pinsKey(userID int) string {
+	return fmt.Sprintf("pins:%d", userID)
+}
+
+// Get returns one of the user's pins, and whether they have a pin with that ID.
+func Get(ctx context.Context, rd *redis.Client, userID int, id string) (Pin, bool, error) {
+	j, err := rd.HGet(ctx, pinsKey(userID), id).Result()
+	if errors.Is(err, redis.Nil) {
+		return Pin{}, false, nil
+	}
+	if err != nil {
+		return Pin{}, false, err
+	}
+	var p Pin
+	if err := json.Unmarshal([]byte(j), &p); err != nil {
+		return Pin{}, false, err
+	}
+	return p, true, nil
+}
+
+// Put stores a pin, replacing any the user already has with the same ID.
+func Put(ctx context.Context, rd *redis.Client, userID int, p Pin) error {
+	ctx, span := beeline.StartSpan(ctx, "pins.put")
+	defer span.Send()
+	key := pinsKey(userID)
+	exists, err := rd.HExists(ctx, key, p.ID).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	if !exists {
+		count, err := rd.HLen(ctx, key).Result()
+		if err != nil {
+			span.AddField("error", err)
+			return err
+		}
+		if count >= MaxPerUser {
+			return ErrTooMany
+		}
+	}
+	j, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return rd.HSet(ctx, key, p.ID, j).Err()
+}
+
+// List returns all the user's pins, most recently pinned first.
+func List(ctx context.Context, rd *redis.Client, userID int) ([]Pin, error) {
+	ctx, span := beeline.StartSpan(ctx, "pins.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, pinsKey(userID)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	pins := []Pin{}
+	for _, e := range entries {
+		var p Pin
+		if err := json.Unmarshal([]byte(e), &p); err != nil {
+			continue
+		}
+		pins = append(pins, p)
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].PinnedAt.After(pins[j].PinnedAt)
+	})
+	return pins, nil
+}
+
+// IDs returns the IDs of all the user's pins.
+func IDs(ctx context.Context, rd *redis.Client, userID int) (map[string]bool, error) {
+	keys, err := rd.HKeys(ctx, pinsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, k := range keys {
+		ids[k] = true
+	}
+	return ids, nil
+}
+
+// Remove unpins an answer, returning false if the user didn't have it pinned.
+func Remove(ctx context.Context, rd *redis.Client, userID int, id string) (bool, error) {
+	removed, err := rd.HDel(ctx, pinsKey(userID), id).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
+
+// DeleteAll removes all the user's pins.
+func DeleteAll(ctx context.Context, rd *redis.Client, userID int) error {
+	return rd.Del(ctx, pinsKey(userID)).Err()
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/accessibility"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/emoji"
+)
+
+// postProcessResponse is applied to every chunk of model output before it is streamed to the watch.
+// Widgets have not yet been expanded when this runs, so it sees the model's original widget tags.
+func (ps *PromptSession) postProcessResponse(ctx context.Context, text string) string {
+	text = outsideWidgetTags(text, func(s string) string { return emoji.Normalize(ctx, s) })
+	if query.AccessibilityModeFromContext(ctx) {
+		text = accessibility.Linearize(text)
+		text = accessibility.ExpandAbbreviations(text)
+	}
+	return ps.limiter.apply(text)
+}
+
+// outsideWidgetTags applies f to each run of text between widget tags, leaving the tags themselves untouched so that
+// they can still be parsed.
+func outsideWidgetTags(text string, f func(string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range widgetTagRegex.FindAllStringIndex(text, -1) {
+		sb.WriteString(f(text[last:loc[0]]))
+		sb.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(f(text[last:]))
+	return sb.String()
+}
+
+// boundaryBuffer holds back the end of a streamed response until it reaches the end of a line or sentence, since
+// accessibility mode can't rewrite half a table row, or a "5 km" that's been split across two chunks. It passes
+// everything straight through when accessibility mode is off.
+type boundaryBuffer struct {
+	enabled bool
+	pending string
+}
+
+func newBoundaryBuffer(ctx context.Context) *boundaryBuffer {
+	return &boundaryBuffer{enabled: query.AccessibilityModeFromContext(ctx)}
+}
+
+// add returns the text that's ready to be post-processed, holding back anything after the last boundary. Whitespace
+// on its own is held back too, along with whatever follows it: callers skip blank chunks, which would lose the
+// separator between two sentences or paragraphs.
+func (b *boundaryBuffer) add(text string) string {
+	if !b.enabled {
+		return text
+	}
+	b.pending += text
+	cut := lastBoundary(b.pending)
+	if strings.TrimSpace(b.pending[:cut]) == "" {
+		return ""
+	}
+	ready := b.pending[:cut]
+	b.pending = b.pending[cut:]
+	return ready
+}
+
+// flush returns whatever is still held back, once the response has finished.
+func (b *boundaryBuffer) flush() string {
+	rest := b.pending
+	b.pending = ""
+	return rest
+}
+
+// lastBoundary returns where the last complete line or sentence in text ends, or 0 if there isn't one. Widget tags
+// count as a boundary, and nothing inside one does. Sentences within a table row don't count either, since the row
+// has to be rewritten as a whole.
+func lastBoundary(text string) int {
+	tags := widgetTagRegex.FindAllStringIndex(text, -1)
+	inTag := func(i int) bool {
+		for _, t := range tags {
+			if i > t[0] && i < t[1] {
+				return true
+			}
+		}
+		return false
+	}
+	lineStart := 0
+	for i := len(text); i > 0; i-- {
+		if text[i-1] == '\n' && !inTag(i) {
+			lineStart = i
+			break
+		}
+	}
+	cut := lineStart
+	if len(tags) > 0 && tags[len(tags)-1][1] > cut {
+		cut = tags[len(tags)-1][1]
+	}
+	if strings.HasPrefix(strings.TrimSpace(text[lineStart:]), "|") {
+		return cut
+	}
+	for i := len(text) - 1; i > cut; i-- {
+		if (text[i] == ' ' || text[i] == '\t') && strings.ContainsRune(".!?", rune(text[i-1])) && !inTag(i) {
+			return i + 1
+		}
+	}
+	return cut
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// TestPostProcessStreamed checks that accessibility rewrites come out the same however the response is split into
+// chunks, including part way through a table row or between a number and its unit.
+func TestPostProcessStreamed(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"accessible": {"true"}})
+	response := "Here's the week:\n| Day | Distance |\n|---|---|\n| Monday | 5 km |\n| Tuesday | 12 mi |\nThat's about 3 km each way, e.g. to the shops."
+	ps := &PromptSession{limiter: newLengthLimiter(ctx)}
+	want := ps.postProcessResponse(ctx, response)
+	if strings.Contains(want, "|") || !strings.Contains(want, "5 kilometres") || !strings.Contains(want, "for example") {
+		t.Fatalf("postProcessResponse(%q) = %q", response, want)
+	}
+	splits := [][]string{
+		{response},
+		{"Here's the week:\n| Day | Dis", "tance |\n|---|", "---|\n| Monday | 5 k", "m |\n| Tuesday | 12 mi |\nThat's about 3", " km each way, e.g", ". to the shops."},
+		strings.SplitAfter(response, " "),
+		strings.Split(response, ""),
+	}
+	for _, chunks := range splits {
+		ps := &PromptSession{limiter: newLengthLimiter(ctx)}
+		held := newBoundaryBuffer(ctx)
+		var got strings.Builder
+		for _, chunk := range chunks {
+			got.WriteString(ps.postProcessResponse(ctx, held.add(chunk)))
+		}
+		got.WriteString(ps.postProcessResponse(ctx, held.flush()))
+		if got.String() != want {
+			t.Errorf("streaming %q gave %q, want %q", chunks, got.String(), want)
+		}
+	}
+}
+
+// TestBoundaryBufferKeepsSeparators checks that the chunks the buffer lets through add up to what went in, including
+// the spaces and newlines between sentences, however the response is split.
+func TestBoundaryBufferKeepsSeparators(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"accessible": {"true"}})
+	response := "It ends here. Next one!\n\nA new paragraph?  Two spaces.\n| A | B |\n| 1 | 2 |\n\nDone. "
+	for n := 1; n <= 8; n++ {
+		held := newBoundaryBuffer(ctx)
+		var got strings.Builder
+		for i := 0; i < len(response); i += n {
+			// The session skips blank chunks, so they mustn't be the only place a separator ends up.
+			if ready := held.add(response[i:min(i+n, len(response))]); strings.TrimSpace(ready) != "" {
+				got.WriteString(ready)
+			}
+		}
+		got.WriteString(held.flush())
+		if got.String() != response {
+			t.Errorf("streaming in chunks of %d gave %q, want %q", n, got.String(), response)
+		}
+	}
+}
+
+func TestBoundaryBufferPassesThroughWithoutAccessibility(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{})
+	held := newBoundaryBuffer(ctx)
+	if got := held.add("It's 5"); got != "It's 5" {
+		t.Errorf("add(%q) = %q, want it unchanged", "It's 5", got)
+	}
+	if got := held.flush(); got != "" {
+		t.Errorf("flush() = %q, want nothing", got)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// maxBodySize bounds the size of a preferences update.
+const maxBodySize = 16 * 1024
+
+// Handler serves the preferences API:
+//
+//	GET    /preferences?token=... returns the user's preferences.
+//	PUT    /preferences?token=... replaces them with the JSON in the request body.
+//	DELETE /preferences?token=... clears them.
+type Handler struct {
+	Store Store
+}
+
+func (h Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		requestid.Logf(ctx, "No token provided.")
+		http.Error(rw, "No token provided.", http.StatusNotFound)
+		return
+	}
+	userInfo, err := quota.GetUserInfo(ctx, token)
+	if err != nil {
+		requestid.Logf(ctx, "Error getting user info: %v", err)
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		p, err := h.Store.Get(ctx, userInfo.UserId)
+		if err != nil {
+			requestid.Logf(ctx, "Error loading preferences: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(ctx, rw, p)
+	case http.MethodPut:
+		var p Preferences
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxBodySize)).Decode(&p); err != nil {
+			requestid.Logf(ctx, "Error decoding preferences: %v", err)
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := p.Validate(); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.Store.Put(ctx, userInfo.UserId, &p); err != nil {
+			requestid.Logf(ctx, "Error storing preferences: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(ctx, rw, &p)
+	case http.MethodDelete:
+		if err := h.Store.Delete(ctx, userInfo.UserId); err != nil {
+			requestid.Logf(ctx, "Error deleting preferences: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(ctx context.Context, rw http.ResponseWriter, p *Preferences) {
+	response, err := json.Marshal(p)
+	if err != nil {
+		requestid.Logf(ctx, "Error marshalling preferences: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(response)
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preferences stores each user's settings on the server, so that the phone app doesn't have to send them all
+// with every query.
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/authz"
+)
+
+// Units are the measurement systems a user can choose between.
+var Units = []string{"imperial", "metric", "uk", "both"}
+
+// Personas are the response styles a user can choose between.
+var Personas = []string{"concise", "friendly", "playful", "formal"}
+
+// Verbosities are the answer lengths a user can choose between.
+var Verbosities = []string{"brief", "normal", "detailed"}
+
+// Home is the user's home location.
+type Home struct {
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+}
+
+// Preferences are a single user's settings. Empty fields fall back to whatever the app sent with the query, and then
+// to the server's defaults.
+type Preferences struct {
+	Units    string `json:"units,omitempty"`
+	Language string `json:"language,omitempty"`
+	Persona  string `json:"persona,omitempty"`
+	// How long answers should be. This is set by asking Bobby, rather than on the settings page.
+	Verbosity string `json:"verbosity,omitempty"`
+	// Tools the user has turned on. This is needed for tools the server only offers to users who opt in.
+	EnabledTools []string `json:"enabled_tools,omitempty"`
+	// Tools the user has turned off.
+	DisabledTools []string  `json:"disabled_tools,omitempty"`
+	Home          *Home     `json:"home,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// When not to push anything that isn't urgent, like the answers to scheduled questions.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+}
+
+// Validate checks that every field holds a value we know what to do with.
+func (p *Preferences) Validate() error {
+	if p.Units != "" && !slices.Contains(Units, p.Units) {
+		return fmt.Errorf("unknown units %q", p.Units)
+	}
+	if p.Persona != "" && !slices.Contains(Personas, p.Persona) {
+		return fmt.Errorf("unknown persona %q", p.Persona)
+	}
+	if p.Verbosity != "" && !slices.Contains(Verbosities, p.Verbosity) {
+		return fmt.Errorf("unknown verbosity %q", p.Verbosity)
+	}
+	if len(p.Language) > 16 {
+		return fmt.Errorf("invalid language %q", p.Language)
+	}
+	if p.Home != nil {
+		if p.Home.Lat < -90 || p.Home.Lat > 90 || p.Home.Lon < -180 || p.Home.Lon > 180 {
+			return errors.New("home location is out of range")
+		}
+	}
+	if p.QuietHours != nil {
+		if err := p.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, tool := range p.EnabledTools {
+		if slices.Contains(p.DisabledTools, tool) {
+			return fmt.Errorf("tool %q is both enabled and disabled", tool)
+		}
+	}
+	return nil
+}
+
+// ApplyTo fills in any of the query parameters the app didn't send from the stored preferences. Parameters the app
+// did send take precedence, so that older apps that don't know about stored preferences keep working.
+func (p *Preferences) ApplyTo(q url.Values) {
+	setDefault := func(key, value string) {
+		if value != "" && q.Get(key) == "" {
+			q.Set(key, value)
+		}
+	}
+	setDefault("units", p.Units)
+	setDefault("lang", p.Language)
+	setDefault("persona", p.Persona)
+	setDefault("verbosity", p.Verbosity)
+	if p.Home != nil && q.Get("homeLat") == "" {
+		q.Set("homeName", p.Home.Name)
+		q.Set("homeLat", strconv.FormatFloat(p.Home.Lat, 'f', -1, 64))
+		q.Set("homeLon", strconv.FormatFloat(p.Home.Lon, 'f', -1, 64))
+	}
+}
+
+// Store keeps preferences in Redis, as JSON.
+type Store struct {
+	Redis *redis.Client
+}
+
+func preferencesKey(userID int) string {
+	return fmt.Sprintf("preferences:%d", userID)
+}
+
+// Get returns the stored preferences for the given user. A user who has never saved any gets empty preferences.
+func (s Store) Get(ctx context.Context, userID int) (*Preferences, error) {
+	ctx, span := beeline.StartSpan(ctx, "preferences.get")
+	defer span.Send()
+	data, err := s.Redis.Get(ctx, preferencesKey(userID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return &Preferences{}, nil
+		}
+		span.AddField("error", err)
+		return nil, err
+	}
+	var p Preferences
+	if err := json.Unmarshal(data, &p); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Put replaces the stored preferences for the given user.
+func (s Store) Put(ctx context.Context, userID int, p *Preferences) error {
+	ctx, span := beeline.StartSpan(ctx, "preferences.put")
+	defer span.Send()
+	p.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(p)
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	return s.Redis.Set(ctx, preferencesKey(userID), data, 0).Err()
+}
+
+// Delete removes the stored preferences for the given user.
+func (s Store) Delete(ctx context.Context, userID int) error {
+	return s.Redis.Del(ctx, preferencesKey(userID)).Err()
+}
+
+// UserPolicy implements authz.PolicySource, using the user's enabled and disabled tools.
+func (s Store) UserPolicy(ctx context.Context, userID int) (*authz.UserPolicy, error) {
+	p, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return p.ToolPolicy(), nil
+}
+
+// ToolPolicy returns the user's tool settings as an authz.UserPolicy.
+func (p *Preferences) ToolPolicy() *authz.UserPolicy {
+	return &authz.UserPolicy{Allow: p.EnabledTools, Deny: p.DisabledTools}
+}
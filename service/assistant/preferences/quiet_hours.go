@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preferences
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Severities are the levels of warning a push can carry, from least to most severe, as used by weather warnings. A
+// push with no severity is never urgent.
+var Severities = []string{"minor", "moderate", "severe", "extreme"}
+
+// defaultUrgentSeverity is the least severe warning let through quiet hours if the user hasn't said otherwise.
+const defaultUrgentSeverity = "severe"
+
+// QuietHours is a time of day during which Bobby shouldn't push anything to the user's watch unless it's urgent.
+type QuietHours struct {
+	// When quiet hours start and end, in the user's local time, as "15:04". If End is before Start, quiet hours run
+	// overnight.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// The least severe warning that's urgent enough to push anyway, from Severities, or "none" to hold everything
+	// back. Empty means "severe".
+	UrgentSeverity string `json:"urgent_severity,omitempty"`
+}
+
+// Validate checks that the times and severity are ones we understand.
+func (q *QuietHours) Validate() error {
+	for _, t := range []string{q.Start, q.End} {
+		if _, err := time.Parse("15:04", t); err != nil {
+			return fmt.Errorf("quiet hours must be given as HH:MM, not %q", t)
+		}
+	}
+	if q.UrgentSeverity != "" && q.UrgentSeverity != "none" && !slices.Contains(Severities, q.UrgentSeverity) {
+		return fmt.Errorf("unknown severity %q", q.UrgentSeverity)
+	}
+	return nil
+}
+
+// DeferPush decides when a push of the given severity ("" if it isn't a warning) should reach a user who is tzOffset
+// minutes ahead of UTC. If it should wait for quiet hours to end, it returns when they do and true. Otherwise, it
+// returns false, and the push can go now.
+func (p *Preferences) DeferPush(now time.Time, tzOffset int, severity string) (time.Time, bool) {
+	q := p.QuietHours
+	if q == nil || q.Start == q.End {
+		return time.Time{}, false
+	}
+	if q.isUrgent(severity) {
+		return time.Time{}, false
+	}
+	start, err1 := time.Parse("15:04", q.Start)
+	end, err2 := time.Parse("15:04", q.End)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, false
+	}
+	local := now.In(time.FixedZone("local", tzOffset*60))
+	at := func(t time.Time, days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, t.Hour(), t.Minute(), 0, 0, local.Location())
+	}
+	// Consider the quiet period that started today and the one that started yesterday, since either might still be
+	// going on if they run overnight.
+	for _, days := range []int{0, -1} {
+		from := at(start, days)
+		until := at(end, days)
+		if !until.After(from) {
+			until = until.AddDate(0, 0, 1)
+		}
+		if !local.Before(from) && local.Before(until) {
+			return until.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (q *QuietHours) isUrgent(severity string) bool {
+	threshold := q.UrgentSeverity
+	if threshold == "" {
+		threshold = defaultUrgentSeverity
+	}
+	if severity == "" || threshold == "none" {
+		return false
+	}
+	return slices.Index(Severities, severity) >= slices.Index(Severities, threshold)
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// promptCacheRefreshMargin is how long before a cache expires that we stop using it, so that a request doesn't refer
+// to a cache that expires before Gemini gets to it.
+const promptCacheRefreshMargin = 5 * time.Minute
+
+// promptCacheFailureBackoff is how long to wait before trying to create a cache again after failing to. The usual
+// reason is that the prefix is too short for Gemini to be willing to cache it, which won't change until we redeploy.
+const promptCacheFailureBackoff = time.Hour
+
+type promptCacheEntry struct {
+	mu sync.Mutex
+	// The name of the cached content, or "" if there isn't a usable one.
+	name    string
+	expires time.Time
+	// When to next try creating the cache, if creating it failed.
+	retryAfter time.Time
+}
+
+// promptCache keeps track of Gemini cached contents holding the system prompt prefix and tool declarations, so that
+// they needn't be sent (and paid for in full) on every turn. Sessions with the same prefix, tools, model and API key
+// share a cache; since caches belong to the API key that created them, each key gets its own.
+type promptCache struct {
+	mu      sync.Mutex
+	entries map[string]*promptCacheEntry
+	// When entries that have outlived their caches were last evicted.
+	lastSweep time.Time
+}
+
+var sharedPromptCache = &promptCache{entries: map[string]*promptCacheEntry{}}
+
+func promptCacheKey(model, apiKey, prefix string, tools []*genai.Tool) string {
+	h := sha256.New()
+	toolJSON, _ := json.Marshal(tools)
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00", model, apiKey, prefix)
+	h.Write(toolJSON)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get returns the name of a cached content holding the given prefix and tools, creating one if necessary, or "" if
+// caching is disabled or unavailable. Failing to cache isn't an error: the caller should send everything itself.
+func (c *promptCache) get(ctx context.Context, client *genai.Client, apiKey, model, prefix string, tools []*genai.Tool) string {
+	ttl := config.GetConfig().PromptCacheTTL
+	if ttl <= promptCacheRefreshMargin {
+		return ""
+	}
+	ctx, span := beeline.StartSpan(ctx, "prompt_cache.get")
+	defer span.Send()
+
+	key := promptCacheKey(model, apiKey, prefix, tools)
+	c.mu.Lock()
+	if now := time.Now(); now.Sub(c.lastSweep) > promptCacheRefreshMargin {
+		c.evictExpired(now)
+		c.lastSweep = now
+	}
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &promptCacheEntry{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	// Holding the entry's lock while creating the cache means that concurrent sessions wait for one cache, rather
+	// than all creating their own.
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	now := time.Now()
+	if entry.name != "" && now.Before(entry.expires.Add(-promptCacheRefreshMargin)) {
+		span.AddField("hit", true)
+		return entry.name
+	}
+	if now.Before(entry.retryAfter) {
+		span.AddField("backing_off", true)
+		return ""
+	}
+
+	cached, err := client.Caches.Create(ctx, model, &genai.CreateCachedContentConfig{
+		TTL:               fmt.Sprintf("%ds", int(ttl.Seconds())),
+		DisplayName:       "bobby-prompt-" + key[:12],
+		SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: prefix}}},
+		Tools:             tools,
+	})
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Creating prompt cache failed, sending prompts uncached for %s: %v\n", promptCacheFailureBackoff, err)
+		entry.name = ""
+		entry.retryAfter = now.Add(promptCacheFailureBackoff)
+		return ""
+	}
+	entry.name = cached.Name
+	entry.expires = now.Add(ttl)
+	if cached.ExpireTime != nil {
+		entry.expires = *cached.ExpireTime
+	}
+	span.AddField("created", cached.Name)
+	return entry.name
+}
+
+// evictExpired drops the entries whose caches have expired and which aren't waiting to retry, so that prefixes that
+// are no longer used (e.g. because the prompt changed) don't stay around forever. c.mu must be held.
+func (c *promptCache) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		// An entry someone holds is being used or created, so it isn't stale.
+		if !entry.mu.TryLock() {
+			continue
+		}
+		created := !entry.expires.IsZero() || !entry.retryAfter.IsZero()
+		if created && now.After(entry.expires) && now.After(entry.retryAfter) {
+			delete(c.entries, key)
+		}
+		entry.mu.Unlock()
+	}
+}
+
+// forget stops using the named cache, e.g. because Gemini said it doesn't exist any more.
+func (c *promptCache) forget(name string) {
+	c.mu.Lock()
+	entries := make([]*promptCacheEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	c.mu.Unlock()
+	for _, entry := range entries {
+		entry.mu.Lock()
+		if entry.name == name {
+			entry.name = ""
+			entry.expires = time.Now()
+		}
+		entry.mu.Unlock()
+	}
+}
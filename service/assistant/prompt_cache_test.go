@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromptCacheEvictExpired(t *testing.T) {
+	now := time.Now()
+	c := &promptCache{entries: map[string]*promptCacheEntry{
+		"live":      {name: "cachedContents/live", expires: now.Add(time.Hour)},
+		"expired":   {name: "cachedContents/old", expires: now.Add(-time.Minute)},
+		"forgotten": {expires: now.Add(-time.Second)},
+		"backoff":   {retryAfter: now.Add(time.Minute)},
+		"retryable": {retryAfter: now.Add(-time.Minute)},
+		"new":       {},
+		"busy":      {name: "cachedContents/busy", expires: now.Add(-time.Minute)},
+	}}
+	c.entries["busy"].mu.Lock()
+	c.evictExpired(now)
+	c.entries["busy"].mu.Unlock()
+
+	for key, want := range map[string]bool{
+		"live": true, "expired": false, "forgotten": false, "backoff": true, "retryable": false, "new": true, "busy": true,
+	} {
+		if _, got := c.entries[key]; got != want {
+			t.Errorf("after evicting, entry %q kept = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// charsPerToken is roughly how many characters of English make up a Gemini token. It's only an estimate, but counting
+// tokens properly would mean another request to Gemini on every turn, and an estimate is plenty to spot a section
+// that's growing.
+const charsPerToken = 4
+
+// promptSizeWarningInterval is the least time between warnings about the prompt being too big, since it will be too
+// big on every turn until someone trims it.
+const promptSizeWarningInterval = 10 * time.Minute
+
+var (
+	promptSizeWarningMu   sync.Mutex
+	lastPromptSizeWarning time.Time
+)
+
+func estimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + charsPerToken - 1) / charsPerToken
+}
+
+// reportPromptSize records the estimated size of each section of the system prompt, and of the tool declarations, in
+// the trace, and logs a warning breaking it down if the total exceeds PROMPT_TOKEN_WARNING.
+func reportPromptSize(ctx context.Context, sections []promptSection, tools []*genai.Tool) {
+	sizes := map[string]int{}
+	total := 0
+	for _, section := range sections {
+		tokens := estimateTokens(section.Text)
+		sizes[section.Name] += tokens
+		total += tokens
+	}
+	if len(tools) > 0 {
+		toolJSON, err := json.Marshal(tools)
+		if err == nil {
+			sizes["tools"] = estimateTokens(string(toolJSON))
+			total += sizes["tools"]
+		}
+	}
+	for name, tokens := range sizes {
+		beeline.AddField(ctx, "prompt_tokens_"+name, tokens)
+	}
+	beeline.AddField(ctx, "prompt_tokens_total", total)
+
+	threshold := config.GetConfig().PromptTokenWarning
+	if threshold <= 0 || total <= threshold {
+		return
+	}
+	promptSizeWarningMu.Lock()
+	if time.Since(lastPromptSizeWarning) < promptSizeWarningInterval {
+		promptSizeWarningMu.Unlock()
+		return
+	}
+	lastPromptSizeWarning = time.Now()
+	promptSizeWarningMu.Unlock()
+
+	names := make([]string, 0, len(sizes))
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return sizes[names[i]] > sizes[names[j]]
+	})
+	var breakdown []string
+	for _, name := range names {
+		if sizes[name] > 0 {
+			breakdown = append(breakdown, fmt.Sprintf("%s: %d", name, sizes[name]))
+		}
+	}
+	requestid.Logf(ctx, "System prompt is about %d tokens, over the warning threshold of %d (%s)\n", total, threshold, strings.Join(breakdown, ", "))
+}
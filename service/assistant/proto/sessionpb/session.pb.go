@@ -0,0 +1,3012 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The session protocol spoken over the /query websocket between the server and the phone app.
+//
+// Clients that ask for the "bobby.session.v1" websocket subprotocol get these messages as binary frames. Everyone else
+// gets the original protocol, where each message is a single prefix letter followed by text or JSON; the prefix each
+// message stands in for is noted next to it. Field numbers must never be reused: remove a field by reserving its
+// number.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: bobby/session/v1/session.proto
+
+package sessionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Progress_Event int32
+
+const (
+	Progress_EVENT_UNSPECIFIED Progress_Event = 0
+	Progress_EVENT_STARTED     Progress_Event = 1
+	Progress_EVENT_RETRYING    Progress_Event = 2
+	Progress_EVENT_FINISHED    Progress_Event = 3
+	Progress_EVENT_FAILED      Progress_Event = 4
+	// The call ran over its latency budget and is carrying on in the background.
+	Progress_EVENT_CONTINUING Progress_Event = 5
+)
+
+// Enum value maps for Progress_Event.
+var (
+	Progress_Event_name = map[int32]string{
+		0: "EVENT_UNSPECIFIED",
+		1: "EVENT_STARTED",
+		2: "EVENT_RETRYING",
+		3: "EVENT_FINISHED",
+		4: "EVENT_FAILED",
+		5: "EVENT_CONTINUING",
+	}
+	Progress_Event_value = map[string]int32{
+		"EVENT_UNSPECIFIED": 0,
+		"EVENT_STARTED":     1,
+		"EVENT_RETRYING":    2,
+		"EVENT_FINISHED":    3,
+		"EVENT_FAILED":      4,
+		"EVENT_CONTINUING":  5,
+	}
+)
+
+func (x Progress_Event) Enum() *Progress_Event {
+	p := new(Progress_Event)
+	*p = x
+	return p
+}
+
+func (x Progress_Event) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Progress_Event) Descriptor() protoreflect.EnumDescriptor {
+	return file_bobby_session_v1_session_proto_enumTypes[0].Descriptor()
+}
+
+func (Progress_Event) Type() protoreflect.EnumType {
+	return &file_bobby_session_v1_session_proto_enumTypes[0]
+}
+
+func (x Progress_Event) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Progress_Event.Descriptor instead.
+func (Progress_Event) EnumDescriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{3, 0}
+}
+
+// ServerMessage is everything the server sends during a session.
+type ServerMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*ServerMessage_Content
+	//	*ServerMessage_Thought
+	//	*ServerMessage_Progress
+	//	*ServerMessage_Action
+	//	*ServerMessage_Warning
+	//	*ServerMessage_Sources
+	//	*ServerMessage_ThreadId
+	//	*ServerMessage_Done
+	//	*ServerMessage_Announcement
+	//	*ServerMessage_Suggestions
+	Message       isServerMessage_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerMessage) Reset() {
+	*x = ServerMessage{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerMessage) ProtoMessage() {}
+
+func (x *ServerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
+func (*ServerMessage) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServerMessage) GetMessage() isServerMessage_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetContent() *Content {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Content); ok {
+			return x.Content
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetThought() string {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Thought); ok {
+			return x.Thought
+		}
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetAction() *ActionRequest {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Action); ok {
+			return x.Action
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetWarning() string {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Warning); ok {
+			return x.Warning
+		}
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetSources() *Sources {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Sources); ok {
+			return x.Sources
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetThreadId() string {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_ThreadId); ok {
+			return x.ThreadId
+		}
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetDone() *Done {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Done); ok {
+			return x.Done
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetAnnouncement() string {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Announcement); ok {
+			return x.Announcement
+		}
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetSuggestions() *Suggestions {
+	if x != nil {
+		if x, ok := x.Message.(*ServerMessage_Suggestions); ok {
+			return x.Suggestions
+		}
+	}
+	return nil
+}
+
+type isServerMessage_Message interface {
+	isServerMessage_Message()
+}
+
+type ServerMessage_Content struct {
+	// "c": part of the response, as it's generated.
+	Content *Content `protobuf:"bytes,1,opt,name=content,proto3,oneof"`
+}
+
+type ServerMessage_Thought struct {
+	// "f": what Bobby is doing, e.g. "Checking the weather".
+	Thought string `protobuf:"bytes,2,opt,name=thought,proto3,oneof"`
+}
+
+type ServerMessage_Progress struct {
+	// "p": a tool call changed state.
+	Progress *Progress `protobuf:"bytes,3,opt,name=progress,proto3,oneof"`
+}
+
+type ServerMessage_Action struct {
+	// "a": the watch or phone should do something, and reply with a ClientActionResponse.
+	Action *ActionRequest `protobuf:"bytes,4,opt,name=action,proto3,oneof"`
+}
+
+type ServerMessage_Warning struct {
+	// "w": something the user should be warned about, e.g. that Bobby claimed to set an alarm it didn't.
+	Warning string `protobuf:"bytes,5,opt,name=warning,proto3,oneof"`
+}
+
+type ServerMessage_Sources struct {
+	// "s": where the response's information came from.
+	Sources *Sources `protobuf:"bytes,6,opt,name=sources,proto3,oneof"`
+}
+
+type ServerMessage_ThreadId struct {
+	// "t": the ID to send as threadId to continue the conversation.
+	ThreadId string `protobuf:"bytes,7,opt,name=thread_id,json=threadId,proto3,oneof"`
+}
+
+type ServerMessage_Done struct {
+	// "d": the response is complete.
+	Done *Done `protobuf:"bytes,8,opt,name=done,proto3,oneof"`
+}
+
+type ServerMessage_Announcement struct {
+	// "n": a notice from us, shown at the start of a conversation.
+	Announcement string `protobuf:"bytes,9,opt,name=announcement,proto3,oneof"`
+}
+
+type ServerMessage_Suggestions struct {
+	// "q": follow-up prompts to offer as quick replies.
+	Suggestions *Suggestions `protobuf:"bytes,10,opt,name=suggestions,proto3,oneof"`
+}
+
+func (*ServerMessage_Content) isServerMessage_Message() {}
+
+func (*ServerMessage_Thought) isServerMessage_Message() {}
+
+func (*ServerMessage_Progress) isServerMessage_Message() {}
+
+func (*ServerMessage_Action) isServerMessage_Message() {}
+
+func (*ServerMessage_Warning) isServerMessage_Message() {}
+
+func (*ServerMessage_Sources) isServerMessage_Message() {}
+
+func (*ServerMessage_ThreadId) isServerMessage_Message() {}
+
+func (*ServerMessage_Done) isServerMessage_Message() {}
+
+func (*ServerMessage_Announcement) isServerMessage_Message() {}
+
+func (*ServerMessage_Suggestions) isServerMessage_Message() {}
+
+// Content is a piece of the response, in the order it should be shown.
+type Content struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunks        []*Chunk               `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Content) Reset() {
+	*x = Content{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Content) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Content) ProtoMessage() {}
+
+func (x *Content) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Content.ProtoReflect.Descriptor instead.
+func (*Content) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Content) GetChunks() []*Chunk {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+// Chunk is some text, or a widget to show in its place.
+type Chunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*Chunk_Text
+	//	*Chunk_Widget
+	Content       isChunk_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Chunk) GetContent() isChunk_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Chunk) GetText() string {
+	if x != nil {
+		if x, ok := x.Content.(*Chunk_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *Chunk) GetWidget() *Widget {
+	if x != nil {
+		if x, ok := x.Content.(*Chunk_Widget); ok {
+			return x.Widget
+		}
+	}
+	return nil
+}
+
+type isChunk_Content interface {
+	isChunk_Content()
+}
+
+type Chunk_Text struct {
+	Text string `protobuf:"bytes,1,opt,name=text,proto3,oneof"`
+}
+
+type Chunk_Widget struct {
+	Widget *Widget `protobuf:"bytes,2,opt,name=widget,proto3,oneof"`
+}
+
+func (*Chunk_Text) isChunk_Content() {}
+
+func (*Chunk_Widget) isChunk_Content() {}
+
+type Progress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Event Progress_Event         `protobuf:"varint,1,opt,name=event,proto3,enum=bobby.session.v1.Progress_Event" json:"event,omitempty"`
+	// The name of the tool, e.g. "get_weather".
+	Function string `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
+	// A description of what's going on, already localized, if there is one.
+	Detail        string `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Progress) GetEvent() Progress_Event {
+	if x != nil {
+		return x.Event
+	}
+	return Progress_EVENT_UNSPECIFIED
+}
+
+func (x *Progress) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *Progress) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type Sources struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Citations []*Citation            `protobuf:"bytes,1,rep,name=citations,proto3" json:"citations,omitempty"`
+	// The credits required by the data providers used for the response.
+	Attributions  []*Attribution `protobuf:"bytes,2,rep,name=attributions,proto3" json:"attributions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sources) Reset() {
+	*x = Sources{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sources) ProtoMessage() {}
+
+func (x *Sources) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sources.ProtoReflect.Descriptor instead.
+func (*Sources) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Sources) GetCitations() []*Citation {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+func (x *Sources) GetAttributions() []*Attribution {
+	if x != nil {
+		return x.Attributions
+	}
+	return nil
+}
+
+type Citation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the source, e.g. "Wikipedia".
+	Source        string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Title         string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Url           string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Citation) Reset() {
+	*x = Citation{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Citation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Citation) ProtoMessage() {}
+
+func (x *Citation) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Citation.ProtoReflect.Descriptor instead.
+func (*Citation) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Citation) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Citation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Citation) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Attribution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attribution) Reset() {
+	*x = Attribution{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attribution) ProtoMessage() {}
+
+func (x *Attribution) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attribution.ProtoReflect.Descriptor instead.
+func (*Attribution) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Attribution) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Attribution) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Attribution) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Suggestions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []string               `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suggestions) Reset() {
+	*x = Suggestions{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestions) ProtoMessage() {}
+
+func (x *Suggestions) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestions.ProtoReflect.Descriptor instead.
+func (*Suggestions) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Suggestions) GetSuggestions() []string {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type Done struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Done) Reset() {
+	*x = Done{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Done) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Done) ProtoMessage() {}
+
+func (x *Done) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Done.ProtoReflect.Descriptor instead.
+func (*Done) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{8}
+}
+
+// Widget is something to show in place of text. The server only sends widgets the client said it supports, so there's
+// no need for a fallback. The field names match the widget's type in the original protocol, with "_" for "-".
+type Widget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*Widget_WeatherCurrent
+	//	*Widget_WeatherSingleDay
+	//	*Widget_WeatherMultiDay
+	//	*Widget_Timer
+	//	*Widget_Number
+	//	*Widget_Sports
+	//	*Widget_Pronunciation
+	//	*Widget_Page
+	Content       isWidget_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Widget) Reset() {
+	*x = Widget{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Widget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Widget) ProtoMessage() {}
+
+func (x *Widget) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Widget.ProtoReflect.Descriptor instead.
+func (*Widget) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Widget) GetContent() isWidget_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Widget) GetWeatherCurrent() *WeatherCurrent {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_WeatherCurrent); ok {
+			return x.WeatherCurrent
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetWeatherSingleDay() *WeatherSingleDay {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_WeatherSingleDay); ok {
+			return x.WeatherSingleDay
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetWeatherMultiDay() *WeatherMultiDay {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_WeatherMultiDay); ok {
+			return x.WeatherMultiDay
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetTimer() *Timer {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_Timer); ok {
+			return x.Timer
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetNumber() *Number {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_Number); ok {
+			return x.Number
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetSports() *Sports {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_Sports); ok {
+			return x.Sports
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetPronunciation() *Pronunciation {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_Pronunciation); ok {
+			return x.Pronunciation
+		}
+	}
+	return nil
+}
+
+func (x *Widget) GetPage() *Page {
+	if x != nil {
+		if x, ok := x.Content.(*Widget_Page); ok {
+			return x.Page
+		}
+	}
+	return nil
+}
+
+type isWidget_Content interface {
+	isWidget_Content()
+}
+
+type Widget_WeatherCurrent struct {
+	WeatherCurrent *WeatherCurrent `protobuf:"bytes,1,opt,name=weather_current,json=weatherCurrent,proto3,oneof"`
+}
+
+type Widget_WeatherSingleDay struct {
+	WeatherSingleDay *WeatherSingleDay `protobuf:"bytes,2,opt,name=weather_single_day,json=weatherSingleDay,proto3,oneof"`
+}
+
+type Widget_WeatherMultiDay struct {
+	WeatherMultiDay *WeatherMultiDay `protobuf:"bytes,3,opt,name=weather_multi_day,json=weatherMultiDay,proto3,oneof"`
+}
+
+type Widget_Timer struct {
+	Timer *Timer `protobuf:"bytes,4,opt,name=timer,proto3,oneof"`
+}
+
+type Widget_Number struct {
+	Number *Number `protobuf:"bytes,5,opt,name=number,proto3,oneof"`
+}
+
+type Widget_Sports struct {
+	Sports *Sports `protobuf:"bytes,6,opt,name=sports,proto3,oneof"`
+}
+
+type Widget_Pronunciation struct {
+	Pronunciation *Pronunciation `protobuf:"bytes,7,opt,name=pronunciation,proto3,oneof"`
+}
+
+type Widget_Page struct {
+	Page *Page `protobuf:"bytes,8,opt,name=page,proto3,oneof"`
+}
+
+func (*Widget_WeatherCurrent) isWidget_Content() {}
+
+func (*Widget_WeatherSingleDay) isWidget_Content() {}
+
+func (*Widget_WeatherMultiDay) isWidget_Content() {}
+
+func (*Widget_Timer) isWidget_Content() {}
+
+func (*Widget_Number) isWidget_Content() {}
+
+func (*Widget_Sports) isWidget_Content() {}
+
+func (*Widget_Pronunciation) isWidget_Content() {}
+
+func (*Widget_Page) isWidget_Content() {}
+
+type WeatherCurrent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Location    string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Condition   int32                  `protobuf:"varint,2,opt,name=condition,proto3" json:"condition,omitempty"`
+	Temperature float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	FeelsLike   float64                `protobuf:"fixed64,4,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	// e.g. "°C"
+	Unit          string `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+	Description   string `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	WindSpeed     int32  `protobuf:"varint,7,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindSpeedUnit string `protobuf:"bytes,8,opt,name=wind_speed_unit,json=windSpeedUnit,proto3" json:"wind_speed_unit,omitempty"`
+	// The number of decimal places the temperatures have been rounded to.
+	Decimals int32 `protobuf:"varint,9,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	// Only present if the model asked for them.
+	Humidity      *int32 `protobuf:"varint,10,opt,name=humidity,proto3,oneof" json:"humidity,omitempty"`
+	PrecipChance  *int32 `protobuf:"varint,11,opt,name=precip_chance,json=precipChance,proto3,oneof" json:"precip_chance,omitempty"`
+	UvIndex       *int32 `protobuf:"varint,12,opt,name=uv_index,json=uvIndex,proto3,oneof" json:"uv_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherCurrent) Reset() {
+	*x = WeatherCurrent{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherCurrent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherCurrent) ProtoMessage() {}
+
+func (x *WeatherCurrent) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherCurrent.ProtoReflect.Descriptor instead.
+func (*WeatherCurrent) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WeatherCurrent) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *WeatherCurrent) GetCondition() int32 {
+	if x != nil {
+		return x.Condition
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *WeatherCurrent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *WeatherCurrent) GetWindSpeed() int32 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetWindSpeedUnit() string {
+	if x != nil {
+		return x.WindSpeedUnit
+	}
+	return ""
+}
+
+func (x *WeatherCurrent) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetHumidity() int32 {
+	if x != nil && x.Humidity != nil {
+		return *x.Humidity
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetPrecipChance() int32 {
+	if x != nil && x.PrecipChance != nil {
+		return *x.PrecipChance
+	}
+	return 0
+}
+
+func (x *WeatherCurrent) GetUvIndex() int32 {
+	if x != nil && x.UvIndex != nil {
+		return *x.UvIndex
+	}
+	return 0
+}
+
+type WeatherSingleDay struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Location  string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Day       string                 `protobuf:"bytes,2,opt,name=day,proto3" json:"day,omitempty"`
+	Condition int32                  `protobuf:"varint,3,opt,name=condition,proto3" json:"condition,omitempty"`
+	Unit      string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Summary   string                 `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	High      float64                `protobuf:"fixed64,6,opt,name=high,proto3" json:"high,omitempty"`
+	Low       float64                `protobuf:"fixed64,7,opt,name=low,proto3" json:"low,omitempty"`
+	// The number of decimal places the temperatures have been rounded to.
+	Decimals      int32 `protobuf:"varint,8,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherSingleDay) Reset() {
+	*x = WeatherSingleDay{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherSingleDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherSingleDay) ProtoMessage() {}
+
+func (x *WeatherSingleDay) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherSingleDay.ProtoReflect.Descriptor instead.
+func (*WeatherSingleDay) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WeatherSingleDay) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *WeatherSingleDay) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *WeatherSingleDay) GetCondition() int32 {
+	if x != nil {
+		return x.Condition
+	}
+	return 0
+}
+
+func (x *WeatherSingleDay) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *WeatherSingleDay) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *WeatherSingleDay) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *WeatherSingleDay) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *WeatherSingleDay) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+type WeatherMultiDay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Days          []*WeatherMultiDay_Day `protobuf:"bytes,2,rep,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherMultiDay) Reset() {
+	*x = WeatherMultiDay{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherMultiDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherMultiDay) ProtoMessage() {}
+
+func (x *WeatherMultiDay) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherMultiDay.ProtoReflect.Descriptor instead.
+func (*WeatherMultiDay) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WeatherMultiDay) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *WeatherMultiDay) GetDays() []*WeatherMultiDay_Day {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+type Timer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// RFC 3339.
+	TargetTime    string `protobuf:"bytes,1,opt,name=target_time,json=targetTime,proto3" json:"target_time,omitempty"`
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Timer) Reset() {
+	*x = Timer{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Timer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timer) ProtoMessage() {}
+
+func (x *Timer) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timer.ProtoReflect.Descriptor instead.
+func (*Timer) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Timer) GetTargetTime() string {
+	if x != nil {
+		return x.TargetTime
+	}
+	return ""
+}
+
+func (x *Timer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Number struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Unit          string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Number) Reset() {
+	*x = Number{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Number) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Number) ProtoMessage() {}
+
+func (x *Number) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Number.ProtoReflect.Descriptor instead.
+func (*Number) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Number) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Number) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type Sports struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	League   string                 `protobuf:"bytes,1,opt,name=league,proto3" json:"league,omitempty"`
+	HomeTeam string                 `protobuf:"bytes,2,opt,name=home_team,json=homeTeam,proto3" json:"home_team,omitempty"`
+	AwayTeam string                 `protobuf:"bytes,3,opt,name=away_team,json=awayTeam,proto3" json:"away_team,omitempty"`
+	// Not set before the event starts.
+	HomeScore *int32 `protobuf:"varint,4,opt,name=home_score,json=homeScore,proto3,oneof" json:"home_score,omitempty"`
+	AwayScore *int32 `protobuf:"varint,5,opt,name=away_score,json=awayScore,proto3,oneof" json:"away_score,omitempty"`
+	// One of "scheduled", "live", "finished" or "postponed".
+	State    string `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Progress string `protobuf:"bytes,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	// RFC 3339.
+	StartTime string `protobuf:"bytes,8,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// If the score might change soon, a token to poll for it with until refresh_until.
+	RefreshToken  string `protobuf:"bytes,9,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	RefreshUntil  string `protobuf:"bytes,10,opt,name=refresh_until,json=refreshUntil,proto3" json:"refresh_until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sports) Reset() {
+	*x = Sports{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sports) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sports) ProtoMessage() {}
+
+func (x *Sports) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sports.ProtoReflect.Descriptor instead.
+func (*Sports) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Sports) GetLeague() string {
+	if x != nil {
+		return x.League
+	}
+	return ""
+}
+
+func (x *Sports) GetHomeTeam() string {
+	if x != nil {
+		return x.HomeTeam
+	}
+	return ""
+}
+
+func (x *Sports) GetAwayTeam() string {
+	if x != nil {
+		return x.AwayTeam
+	}
+	return ""
+}
+
+func (x *Sports) GetHomeScore() int32 {
+	if x != nil && x.HomeScore != nil {
+		return *x.HomeScore
+	}
+	return 0
+}
+
+func (x *Sports) GetAwayScore() int32 {
+	if x != nil && x.AwayScore != nil {
+		return *x.AwayScore
+	}
+	return 0
+}
+
+func (x *Sports) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Sports) GetProgress() string {
+	if x != nil {
+		return x.Progress
+	}
+	return ""
+}
+
+func (x *Sports) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *Sports) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *Sports) GetRefreshUntil() string {
+	if x != nil {
+		return x.RefreshUntil
+	}
+	return ""
+}
+
+type Pronunciation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Word  string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	// e.g. "pruh-nuhn-see-AY-shuhn", with the stressed syllable in capitals.
+	Respelling    string `protobuf:"bytes,2,opt,name=respelling,proto3" json:"respelling,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pronunciation) Reset() {
+	*x = Pronunciation{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pronunciation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pronunciation) ProtoMessage() {}
+
+func (x *Pronunciation) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pronunciation.ProtoReflect.Descriptor instead.
+func (*Pronunciation) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Pronunciation) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *Pronunciation) GetRespelling() string {
+	if x != nil {
+		return x.Respelling
+	}
+	return ""
+}
+
+type Page struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Document      string                 `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	PageTitle     string                 `protobuf:"bytes,3,opt,name=page_title,json=pageTitle,proto3" json:"page_title,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	Pages         int32                  `protobuf:"varint,6,opt,name=pages,proto3" json:"pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Page) Reset() {
+	*x = Page{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Page) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Page) ProtoMessage() {}
+
+func (x *Page) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Page.ProtoReflect.Descriptor instead.
+func (*Page) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Page) GetDocument() string {
+	if x != nil {
+		return x.Document
+	}
+	return ""
+}
+
+func (x *Page) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Page) GetPageTitle() string {
+	if x != nil {
+		return x.PageTitle
+	}
+	return ""
+}
+
+func (x *Page) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Page) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Page) GetPages() int32 {
+	if x != nil {
+		return x.Pages
+	}
+	return 0
+}
+
+// ActionRequest asks the client to do something only it can, like setting an alarm on the watch. The field names match
+// the action's name in the original protocol.
+type ActionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Action:
+	//
+	//	*ActionRequest_SetAlarm
+	//	*ActionRequest_GetAlarm
+	//	*ActionRequest_SetReminder
+	//	*ActionRequest_GetReminders
+	//	*ActionRequest_DeleteReminder
+	//	*ActionRequest_SendFeedback
+	//	*ActionRequest_ScanBarcode
+	//	*ActionRequest_SetIntervalTimer
+	//	*ActionRequest_Stopwatch
+	Action        isActionRequest_Action `protobuf_oneof:"action"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionRequest) Reset() {
+	*x = ActionRequest{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionRequest) ProtoMessage() {}
+
+func (x *ActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionRequest.ProtoReflect.Descriptor instead.
+func (*ActionRequest) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ActionRequest) GetAction() isActionRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetSetAlarm() *SetAlarm {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_SetAlarm); ok {
+			return x.SetAlarm
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetGetAlarm() *GetAlarm {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_GetAlarm); ok {
+			return x.GetAlarm
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetSetReminder() *SetReminder {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_SetReminder); ok {
+			return x.SetReminder
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetGetReminders() *GetReminders {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_GetReminders); ok {
+			return x.GetReminders
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetDeleteReminder() *DeleteReminder {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_DeleteReminder); ok {
+			return x.DeleteReminder
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetSendFeedback() *SendFeedback {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_SendFeedback); ok {
+			return x.SendFeedback
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetScanBarcode() *ScanBarcode {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_ScanBarcode); ok {
+			return x.ScanBarcode
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetSetIntervalTimer() *SetIntervalTimer {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_SetIntervalTimer); ok {
+			return x.SetIntervalTimer
+		}
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetStopwatch() *Stopwatch {
+	if x != nil {
+		if x, ok := x.Action.(*ActionRequest_Stopwatch); ok {
+			return x.Stopwatch
+		}
+	}
+	return nil
+}
+
+type isActionRequest_Action interface {
+	isActionRequest_Action()
+}
+
+type ActionRequest_SetAlarm struct {
+	SetAlarm *SetAlarm `protobuf:"bytes,1,opt,name=set_alarm,json=setAlarm,proto3,oneof"`
+}
+
+type ActionRequest_GetAlarm struct {
+	GetAlarm *GetAlarm `protobuf:"bytes,2,opt,name=get_alarm,json=getAlarm,proto3,oneof"`
+}
+
+type ActionRequest_SetReminder struct {
+	SetReminder *SetReminder `protobuf:"bytes,3,opt,name=set_reminder,json=setReminder,proto3,oneof"`
+}
+
+type ActionRequest_GetReminders struct {
+	GetReminders *GetReminders `protobuf:"bytes,4,opt,name=get_reminders,json=getReminders,proto3,oneof"`
+}
+
+type ActionRequest_DeleteReminder struct {
+	DeleteReminder *DeleteReminder `protobuf:"bytes,5,opt,name=delete_reminder,json=deleteReminder,proto3,oneof"`
+}
+
+type ActionRequest_SendFeedback struct {
+	SendFeedback *SendFeedback `protobuf:"bytes,6,opt,name=send_feedback,json=sendFeedback,proto3,oneof"`
+}
+
+type ActionRequest_ScanBarcode struct {
+	ScanBarcode *ScanBarcode `protobuf:"bytes,7,opt,name=scan_barcode,json=scanBarcode,proto3,oneof"`
+}
+
+type ActionRequest_SetIntervalTimer struct {
+	SetIntervalTimer *SetIntervalTimer `protobuf:"bytes,8,opt,name=set_interval_timer,json=setIntervalTimer,proto3,oneof"`
+}
+
+type ActionRequest_Stopwatch struct {
+	Stopwatch *Stopwatch `protobuf:"bytes,9,opt,name=stopwatch,proto3,oneof"`
+}
+
+func (*ActionRequest_SetAlarm) isActionRequest_Action() {}
+
+func (*ActionRequest_GetAlarm) isActionRequest_Action() {}
+
+func (*ActionRequest_SetReminder) isActionRequest_Action() {}
+
+func (*ActionRequest_GetReminders) isActionRequest_Action() {}
+
+func (*ActionRequest_DeleteReminder) isActionRequest_Action() {}
+
+func (*ActionRequest_SendFeedback) isActionRequest_Action() {}
+
+func (*ActionRequest_ScanBarcode) isActionRequest_Action() {}
+
+func (*ActionRequest_SetIntervalTimer) isActionRequest_Action() {}
+
+func (*ActionRequest_Stopwatch) isActionRequest_Action() {}
+
+// SetAlarm sets or cancels an alarm or timer.
+type SetAlarm struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	IsTimer bool                   `protobuf:"varint,1,opt,name=is_timer,json=isTimer,proto3" json:"is_timer,omitempty"`
+	// Whether to cancel the alarm or timer at time, rather than set one.
+	Cancel bool `protobuf:"varint,2,opt,name=cancel,proto3" json:"cancel,omitempty"`
+	// For alarms, and cancelling timers: when, in RFC 3339.
+	Time string `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	// For setting timers: how long, in seconds.
+	Duration int32  `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Name     string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// The vibration pattern to use, if not the default.
+	Vibration     string `protobuf:"bytes,6,opt,name=vibration,proto3" json:"vibration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAlarm) Reset() {
+	*x = SetAlarm{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAlarm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAlarm) ProtoMessage() {}
+
+func (x *SetAlarm) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAlarm.ProtoReflect.Descriptor instead.
+func (*SetAlarm) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetAlarm) GetIsTimer() bool {
+	if x != nil {
+		return x.IsTimer
+	}
+	return false
+}
+
+func (x *SetAlarm) GetCancel() bool {
+	if x != nil {
+		return x.Cancel
+	}
+	return false
+}
+
+func (x *SetAlarm) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *SetAlarm) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *SetAlarm) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetAlarm) GetVibration() string {
+	if x != nil {
+		return x.Vibration
+	}
+	return ""
+}
+
+type GetAlarm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsTimer       bool                   `protobuf:"varint,1,opt,name=is_timer,json=isTimer,proto3" json:"is_timer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlarm) Reset() {
+	*x = GetAlarm{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlarm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlarm) ProtoMessage() {}
+
+func (x *GetAlarm) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlarm.ProtoReflect.Descriptor instead.
+func (*GetAlarm) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetAlarm) GetIsTimer() bool {
+	if x != nil {
+		return x.IsTimer
+	}
+	return false
+}
+
+type SetReminder struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// RFC 3339. Not set if there's a trigger.
+	Time          string               `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	What          string               `protobuf:"bytes,2,opt,name=what,proto3" json:"what,omitempty"`
+	Trigger       *SetReminder_Trigger `protobuf:"bytes,3,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetReminder) Reset() {
+	*x = SetReminder{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReminder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReminder) ProtoMessage() {}
+
+func (x *SetReminder) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReminder.ProtoReflect.Descriptor instead.
+func (*SetReminder) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetReminder) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *SetReminder) GetWhat() string {
+	if x != nil {
+		return x.What
+	}
+	return ""
+}
+
+func (x *SetReminder) GetTrigger() *SetReminder_Trigger {
+	if x != nil {
+		return x.Trigger
+	}
+	return nil
+}
+
+type GetReminders struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReminders) Reset() {
+	*x = GetReminders{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReminders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReminders) ProtoMessage() {}
+
+func (x *GetReminders) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReminders.ProtoReflect.Descriptor instead.
+func (*GetReminders) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{22}
+}
+
+type DeleteReminder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReminder) Reset() {
+	*x = DeleteReminder{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReminder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReminder) ProtoMessage() {}
+
+func (x *DeleteReminder) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReminder.ProtoReflect.Descriptor instead.
+func (*DeleteReminder) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteReminder) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SendFeedback struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Feedback string                 `protobuf:"bytes,1,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	// Set if the user agreed to include the conversation.
+	ThreadId      string `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendFeedback) Reset() {
+	*x = SendFeedback{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendFeedback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendFeedback) ProtoMessage() {}
+
+func (x *SendFeedback) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendFeedback.ProtoReflect.Descriptor instead.
+func (*SendFeedback) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SendFeedback) GetFeedback() string {
+	if x != nil {
+		return x.Feedback
+	}
+	return ""
+}
+
+func (x *SendFeedback) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+type ScanBarcode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The page the phone should open to scan with its camera.
+	Url           string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanBarcode) Reset() {
+	*x = ScanBarcode{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanBarcode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanBarcode) ProtoMessage() {}
+
+func (x *ScanBarcode) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanBarcode.ProtoReflect.Descriptor instead.
+func (*ScanBarcode) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ScanBarcode) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type SetIntervalTimer struct {
+	state  protoimpl.MessageState    `protogen:"open.v1"`
+	Name   string                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Phases []*SetIntervalTimer_Phase `protobuf:"bytes,2,rep,name=phases,proto3" json:"phases,omitempty"`
+	// Whether to stop the interval timer that's running, rather than start one.
+	Cancel        bool `protobuf:"varint,3,opt,name=cancel,proto3" json:"cancel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIntervalTimer) Reset() {
+	*x = SetIntervalTimer{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIntervalTimer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIntervalTimer) ProtoMessage() {}
+
+func (x *SetIntervalTimer) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIntervalTimer.ProtoReflect.Descriptor instead.
+func (*SetIntervalTimer) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetIntervalTimer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetIntervalTimer) GetPhases() []*SetIntervalTimer_Phase {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+func (x *SetIntervalTimer) GetCancel() bool {
+	if x != nil {
+		return x.Cancel
+	}
+	return false
+}
+
+type Stopwatch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "start", "resume", "stop", "lap" or "get".
+	Command       string `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stopwatch) Reset() {
+	*x = Stopwatch{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stopwatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stopwatch) ProtoMessage() {}
+
+func (x *Stopwatch) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stopwatch.ProtoReflect.Descriptor instead.
+func (*Stopwatch) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Stopwatch) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+// ClientActionResponse is the client's reply to an ActionRequest.
+type ClientActionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "ok" on success.
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// Why the action failed, if it did.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// Anything else the action returns, e.g. the list of alarms. It's passed to the model as is, so it stays free-form.
+	Result        *structpb.Struct `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientActionResponse) Reset() {
+	*x = ClientActionResponse{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientActionResponse) ProtoMessage() {}
+
+func (x *ClientActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientActionResponse.ProtoReflect.Descriptor instead.
+func (*ClientActionResponse) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ClientActionResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ClientActionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ClientActionResponse) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type WeatherMultiDay_Day struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	Condition     int32                  `protobuf:"varint,2,opt,name=condition,proto3" json:"condition,omitempty"`
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherMultiDay_Day) Reset() {
+	*x = WeatherMultiDay_Day{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherMultiDay_Day) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherMultiDay_Day) ProtoMessage() {}
+
+func (x *WeatherMultiDay_Day) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherMultiDay_Day.ProtoReflect.Descriptor instead.
+func (*WeatherMultiDay_Day) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{12, 0}
+}
+
+func (x *WeatherMultiDay_Day) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *WeatherMultiDay_Day) GetCondition() int32 {
+	if x != nil {
+		return x.Condition
+	}
+	return 0
+}
+
+func (x *WeatherMultiDay_Day) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *WeatherMultiDay_Day) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+type SetReminder_Trigger struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "arrive" or "next_open".
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// For arrive triggers.
+	Place         string  `protobuf:"bytes,2,opt,name=place,proto3" json:"place,omitempty"`
+	Lat           float64 `protobuf:"fixed64,3,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64 `protobuf:"fixed64,4,opt,name=lon,proto3" json:"lon,omitempty"`
+	RadiusM       int32   `protobuf:"varint,5,opt,name=radius_m,json=radiusM,proto3" json:"radius_m,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetReminder_Trigger) Reset() {
+	*x = SetReminder_Trigger{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReminder_Trigger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReminder_Trigger) ProtoMessage() {}
+
+func (x *SetReminder_Trigger) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReminder_Trigger.ProtoReflect.Descriptor instead.
+func (*SetReminder_Trigger) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{21, 0}
+}
+
+func (x *SetReminder_Trigger) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SetReminder_Trigger) GetPlace() string {
+	if x != nil {
+		return x.Place
+	}
+	return ""
+}
+
+func (x *SetReminder_Trigger) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *SetReminder_Trigger) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *SetReminder_Trigger) GetRadiusM() int32 {
+	if x != nil {
+		return x.RadiusM
+	}
+	return 0
+}
+
+type SetIntervalTimer_Phase struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "work", "rest" or "long_rest".
+	Kind          string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Seconds       int32  `protobuf:"varint,2,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIntervalTimer_Phase) Reset() {
+	*x = SetIntervalTimer_Phase{}
+	mi := &file_bobby_session_v1_session_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIntervalTimer_Phase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIntervalTimer_Phase) ProtoMessage() {}
+
+func (x *SetIntervalTimer_Phase) ProtoReflect() protoreflect.Message {
+	mi := &file_bobby_session_v1_session_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIntervalTimer_Phase.ProtoReflect.Descriptor instead.
+func (*SetIntervalTimer_Phase) Descriptor() ([]byte, []int) {
+	return file_bobby_session_v1_session_proto_rawDescGZIP(), []int{26, 0}
+}
+
+func (x *SetIntervalTimer_Phase) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SetIntervalTimer_Phase) GetSeconds() int32 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+var File_bobby_session_v1_session_proto protoreflect.FileDescriptor
+
+var file_bobby_session_v1_session_proto_rawDesc = string([]byte{
+	0x0a, 0x1e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2f,
+	0x76, 0x31, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x10, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xeb, 0x03, 0x0a, 0x0d, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x07, 0x74, 0x68, 0x6f,
+	0x75, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x74, 0x68,
+	0x6f, 0x75, 0x67, 0x68, 0x74, 0x12, 0x38, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x39, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x48, 0x00, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x07, 0x77, 0x61,
+	0x72, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x77,
+	0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x35, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x48, 0x00, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x1d, 0x0a,
+	0x09, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x04,
+	0x64, 0x6f, 0x6e, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6f, 0x62,
+	0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f,
+	0x6e, 0x65, 0x48, 0x00, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x24, 0x0a, 0x0c, 0x61, 0x6e,
+	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x0c, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x41, 0x0a, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3a,
+	0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6f, 0x62, 0x62,
+	0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0x5c, 0x0a, 0x05, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x77, 0x69, 0x64,
+	0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6f, 0x62, 0x62,
+	0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x64,
+	0x67, 0x65, 0x74, 0x48, 0x00, 0x52, 0x06, 0x77, 0x69, 0x64, 0x67, 0x65, 0x74, 0x42, 0x09, 0x0a,
+	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xfa, 0x01, 0x0a, 0x08, 0x50, 0x72, 0x6f,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x36, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x22, 0x81, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x11, 0x45,
+	0x56, 0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x52,
+	0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x52,
+	0x45, 0x54, 0x52, 0x59, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x03, 0x12, 0x10, 0x0a,
+	0x0c, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12,
+	0x14, 0x0a, 0x10, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x49, 0x4e, 0x55,
+	0x49, 0x4e, 0x47, 0x10, 0x05, 0x22, 0x86, 0x01, 0x0a, 0x07, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x12, 0x38, 0x0a, 0x09, 0x63, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x09, 0x63, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x41, 0x0a, 0x0c, 0x61,
+	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0c, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x4a,
+	0x0a, 0x08, 0x43, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x4f, 0x0a, 0x0b, 0x41, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x2f, 0x0a, 0x0b, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x06, 0x0a, 0x04,
+	0x44, 0x6f, 0x6e, 0x65, 0x22, 0x95, 0x04, 0x0a, 0x06, 0x57, 0x69, 0x64, 0x67, 0x65, 0x74, 0x12,
+	0x4b, 0x0a, 0x0f, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79,
+	0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x61, 0x74,
+	0x68, 0x65, 0x72, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x77, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x52, 0x0a, 0x12,
+	0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x5f, 0x64,
+	0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79,
+	0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x61, 0x74,
+	0x68, 0x65, 0x72, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x44, 0x61, 0x79, 0x48, 0x00, 0x52, 0x10,
+	0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x44, 0x61, 0x79,
+	0x12, 0x4f, 0x0a, 0x11, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x6d, 0x75, 0x6c, 0x74,
+	0x69, 0x5f, 0x64, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x6f,
+	0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x61, 0x79, 0x48, 0x00,
+	0x52, 0x0f, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x61,
+	0x79, 0x12, 0x2f, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x48, 0x00, 0x52, 0x05, 0x74, 0x69, 0x6d,
+	0x65, 0x72, 0x12, 0x32, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x6f, 0x72, 0x74, 0x73,
+	0x48, 0x00, 0x52, 0x06, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x70, 0x72,
+	0x6f, 0x6e, 0x75, 0x6e, 0x63, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6e, 0x75, 0x6e, 0x63, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x6e, 0x75, 0x6e, 0x63, 0x69, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x67,
+	0x65, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xbb, 0x03, 0x0a,
+	0x0e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b,
+	0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66,
+	0x65, 0x65, 0x6c, 0x73, 0x5f, 0x6c, 0x69, 0x6b, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x66, 0x65, 0x65, 0x6c, 0x73, 0x4c, 0x69, 0x6b, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12,
+	0x26, 0x0a, 0x0f, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x5f, 0x75, 0x6e,
+	0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x77, 0x69, 0x6e, 0x64, 0x53, 0x70,
+	0x65, 0x65, 0x64, 0x55, 0x6e, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x69, 0x6d,
+	0x61, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x65, 0x63, 0x69, 0x6d,
+	0x61, 0x6c, 0x73, 0x12, 0x1f, 0x0a, 0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x72, 0x65, 0x63, 0x69, 0x70, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0c, 0x70,
+	0x72, 0x65, 0x63, 0x69, 0x70, 0x43, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1e,
+	0x0a, 0x08, 0x75, 0x76, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x02, 0x52, 0x07, 0x75, 0x76, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
+	0x70, 0x72, 0x65, 0x63, 0x69, 0x70, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x75, 0x76, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0xce, 0x01, 0x0a, 0x10, 0x57,
+	0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x44, 0x61, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x64,
+	0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x61, 0x79, 0x12, 0x1c, 0x0a,
+	0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x67,
+	0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x68, 0x69, 0x67, 0x68, 0x12, 0x10, 0x0a,
+	0x03, 0x6c, 0x6f, 0x77, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x6f, 0x77, 0x12,
+	0x1a, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x22, 0xc5, 0x01, 0x0a, 0x0f,
+	0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x61, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x04, 0x64,
+	0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x6f, 0x62, 0x62,
+	0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x61,
+	0x74, 0x68, 0x65, 0x72, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x61, 0x79, 0x2e, 0x44, 0x61, 0x79,
+	0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x1a, 0x5b, 0x0a, 0x03, 0x44, 0x61, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x64, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x61, 0x79, 0x12,
+	0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x68, 0x69, 0x67,
+	0x68, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6c, 0x6f, 0x77, 0x22, 0x3c, 0x0a, 0x05, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x22, 0x34, 0x0a, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0xdb, 0x02, 0x0a, 0x06, 0x53, 0x70, 0x6f, 0x72,
+	0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f,
+	0x6d, 0x65, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68,
+	0x6f, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x77, 0x61, 0x79, 0x5f,
+	0x74, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x77, 0x61, 0x79,
+	0x54, 0x65, 0x61, 0x6d, 0x12, 0x22, 0x0a, 0x0a, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x09, 0x68, 0x6f, 0x6d, 0x65,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x61, 0x77, 0x61, 0x79,
+	0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x09,
+	0x61, 0x77, 0x61, 0x79, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a,
+	0x0d, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x75, 0x6e,
+	0x74, 0x69, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x72, 0x65,
+	0x73, 0x68, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x68, 0x6f, 0x6d, 0x65,
+	0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x77, 0x61, 0x79, 0x5f,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x43, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x6e, 0x75, 0x6e, 0x63,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65,
+	0x73, 0x70, 0x65, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x72, 0x65, 0x73, 0x70, 0x65, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x22, 0x95, 0x01, 0x0a, 0x04, 0x50,
+	0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54,
+	0x69, 0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x61, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x70, 0x61, 0x67,
+	0x65, 0x73, 0x22, 0x83, 0x05, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x09, 0x73, 0x65, 0x74, 0x5f, 0x61, 0x6c, 0x61, 0x72,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x41, 0x6c,
+	0x61, 0x72, 0x6d, 0x48, 0x00, 0x52, 0x08, 0x73, 0x65, 0x74, 0x41, 0x6c, 0x61, 0x72, 0x6d, 0x12,
+	0x39, 0x0a, 0x09, 0x67, 0x65, 0x74, 0x5f, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x61, 0x72, 0x6d, 0x48, 0x00,
+	0x52, 0x08, 0x67, 0x65, 0x74, 0x41, 0x6c, 0x61, 0x72, 0x6d, 0x12, 0x42, 0x0a, 0x0c, 0x73, 0x65,
+	0x74, 0x5f, 0x72, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x48,
+	0x00, 0x52, 0x0b, 0x73, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x45,
+	0x0a, 0x0d, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x69,
+	0x6e, 0x64, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x0c, 0x67, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x69,
+	0x6e, 0x64, 0x65, 0x72, 0x73, 0x12, 0x4b, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f,
+	0x72, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72,
+	0x48, 0x00, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64,
+	0x65, 0x72, 0x12, 0x45, 0x0a, 0x0d, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x64, 0x62,
+	0x61, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x62, 0x6f, 0x62, 0x62,
+	0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x46, 0x65, 0x65, 0x64, 0x62, 0x61, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x65, 0x6e,
+	0x64, 0x46, 0x65, 0x65, 0x64, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x42, 0x0a, 0x0c, 0x73, 0x63, 0x61,
+	0x6e, 0x5f, 0x62, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x48, 0x00,
+	0x52, 0x0b, 0x73, 0x63, 0x61, 0x6e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x52, 0x0a,
+	0x12, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x6f, 0x62, 0x62,
+	0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x48, 0x00, 0x52,
+	0x10, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65,
+	0x72, 0x12, 0x3b, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x70, 0x77, 0x61, 0x74, 0x63, 0x68, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x77, 0x61, 0x74, 0x63,
+	0x68, 0x48, 0x00, 0x52, 0x09, 0x73, 0x74, 0x6f, 0x70, 0x77, 0x61, 0x74, 0x63, 0x68, 0x42, 0x08,
+	0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9f, 0x01, 0x0a, 0x08, 0x53, 0x65, 0x74,
+	0x41, 0x6c, 0x61, 0x72, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x76, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x76, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x25, 0x0a, 0x08, 0x47, 0x65,
+	0x74, 0x41, 0x6c, 0x61, 0x72, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x54, 0x69, 0x6d, 0x65,
+	0x72, 0x22, 0xea, 0x01, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x68, 0x61, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x77, 0x68, 0x61, 0x74, 0x12, 0x3f, 0x0a, 0x07, 0x74, 0x72, 0x69,
+	0x67, 0x67, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x6f, 0x62,
+	0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x74, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x2e, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65,
+	0x72, 0x52, 0x07, 0x74, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x1a, 0x72, 0x0a, 0x07, 0x54, 0x72,
+	0x69, 0x67, 0x67, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x6c, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x61,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6c, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x5f, 0x6d, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x4d, 0x22, 0x0e,
+	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x22, 0x20,
+	0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x47, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x65, 0x65, 0x64, 0x62, 0x61, 0x63, 0x6b,
+	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x65, 0x65, 0x64, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x66, 0x65, 0x65, 0x64, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x1b, 0x0a, 0x09,
+	0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x22, 0x1f, 0x0a, 0x0b, 0x53, 0x63, 0x61,
+	0x6e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0xb7, 0x01, 0x0a, 0x10, 0x53,
+	0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x40, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x62, 0x6f, 0x62, 0x62, 0x79, 0x2e, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x50, 0x68, 0x61, 0x73, 0x65, 0x52, 0x06, 0x70,
+	0x68, 0x61, 0x73, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x1a, 0x35, 0x0a,
+	0x05, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x22, 0x25, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x77, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x75, 0x0a, 0x14, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x42, 0x53, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x70, 0x65, 0x62, 0x62, 0x6c, 0x65, 0x2d, 0x64, 0x65, 0x76, 0x2f, 0x62, 0x6f, 0x62, 0x62,
+	0x79, 0x2d, 0x61, 0x73, 0x73, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2f, 0x61, 0x73, 0x73, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x3b, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_bobby_session_v1_session_proto_rawDescOnce sync.Once
+	file_bobby_session_v1_session_proto_rawDescData []byte
+)
+
+func file_bobby_session_v1_session_proto_rawDescGZIP() []byte {
+	file_bobby_session_v1_session_proto_rawDescOnce.Do(func() {
+		file_bobby_session_v1_session_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bobby_session_v1_session_proto_rawDesc), len(file_bobby_session_v1_session_proto_rawDesc)))
+	})
+	return file_bobby_session_v1_session_proto_rawDescData
+}
+
+var file_bobby_session_v1_session_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_bobby_session_v1_session_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_bobby_session_v1_session_proto_goTypes = []any{
+	(Progress_Event)(0),            // 0: bobby.session.v1.Progress.Event
+	(*ServerMessage)(nil),          // 1: bobby.session.v1.ServerMessage
+	(*Content)(nil),                // 2: bobby.session.v1.Content
+	(*Chunk)(nil),                  // 3: bobby.session.v1.Chunk
+	(*Progress)(nil),               // 4: bobby.session.v1.Progress
+	(*Sources)(nil),                // 5: bobby.session.v1.Sources
+	(*Citation)(nil),               // 6: bobby.session.v1.Citation
+	(*Attribution)(nil),            // 7: bobby.session.v1.Attribution
+	(*Suggestions)(nil),            // 8: bobby.session.v1.Suggestions
+	(*Done)(nil),                   // 9: bobby.session.v1.Done
+	(*Widget)(nil),                 // 10: bobby.session.v1.Widget
+	(*WeatherCurrent)(nil),         // 11: bobby.session.v1.WeatherCurrent
+	(*WeatherSingleDay)(nil),       // 12: bobby.session.v1.WeatherSingleDay
+	(*WeatherMultiDay)(nil),        // 13: bobby.session.v1.WeatherMultiDay
+	(*Timer)(nil),                  // 14: bobby.session.v1.Timer
+	(*Number)(nil),                 // 15: bobby.session.v1.Number
+	(*Sports)(nil),                 // 16: bobby.session.v1.Sports
+	(*Pronunciation)(nil),          // 17: bobby.session.v1.Pronunciation
+	(*Page)(nil),                   // 18: bobby.session.v1.Page
+	(*ActionRequest)(nil),          // 19: bobby.session.v1.ActionRequest
+	(*SetAlarm)(nil),               // 20: bobby.session.v1.SetAlarm
+	(*GetAlarm)(nil),               // 21: bobby.session.v1.GetAlarm
+	(*SetReminder)(nil),            // 22: bobby.session.v1.SetReminder
+	(*GetReminders)(nil),           // 23: bobby.session.v1.GetReminders
+	(*DeleteReminder)(nil),         // 24: bobby.session.v1.DeleteReminder
+	(*SendFeedback)(nil),           // 25: bobby.session.v1.SendFeedback
+	(*ScanBarcode)(nil),            // 26: bobby.session.v1.ScanBarcode
+	(*SetIntervalTimer)(nil),       // 27: bobby.session.v1.SetIntervalTimer
+	(*Stopwatch)(nil),              // 28: bobby.session.v1.Stopwatch
+	(*ClientActionResponse)(nil),   // 29: bobby.session.v1.ClientActionResponse
+	(*WeatherMultiDay_Day)(nil),    // 30: bobby.session.v1.WeatherMultiDay.Day
+	(*SetReminder_Trigger)(nil),    // 31: bobby.session.v1.SetReminder.Trigger
+	(*SetIntervalTimer_Phase)(nil), // 32: bobby.session.v1.SetIntervalTimer.Phase
+	(*structpb.Struct)(nil),        // 33: google.protobuf.Struct
+}
+var file_bobby_session_v1_session_proto_depIdxs = []int32{
+	2,  // 0: bobby.session.v1.ServerMessage.content:type_name -> bobby.session.v1.Content
+	4,  // 1: bobby.session.v1.ServerMessage.progress:type_name -> bobby.session.v1.Progress
+	19, // 2: bobby.session.v1.ServerMessage.action:type_name -> bobby.session.v1.ActionRequest
+	5,  // 3: bobby.session.v1.ServerMessage.sources:type_name -> bobby.session.v1.Sources
+	9,  // 4: bobby.session.v1.ServerMessage.done:type_name -> bobby.session.v1.Done
+	8,  // 5: bobby.session.v1.ServerMessage.suggestions:type_name -> bobby.session.v1.Suggestions
+	3,  // 6: bobby.session.v1.Content.chunks:type_name -> bobby.session.v1.Chunk
+	10, // 7: bobby.session.v1.Chunk.widget:type_name -> bobby.session.v1.Widget
+	0,  // 8: bobby.session.v1.Progress.event:type_name -> bobby.session.v1.Progress.Event
+	6,  // 9: bobby.session.v1.Sources.citations:type_name -> bobby.session.v1.Citation
+	7,  // 10: bobby.session.v1.Sources.attributions:type_name -> bobby.session.v1.Attribution
+	11, // 11: bobby.session.v1.Widget.weather_current:type_name -> bobby.session.v1.WeatherCurrent
+	12, // 12: bobby.session.v1.Widget.weather_single_day:type_name -> bobby.session.v1.WeatherSingleDay
+	13, // 13: bobby.session.v1.Widget.weather_multi_day:type_name -> bobby.session.v1.WeatherMultiDay
+	14, // 14: bobby.session.v1.Widget.timer:type_name -> bobby.session.v1.Timer
+	15, // 15: bobby.session.v1.Widget.number:type_name -> bobby.session.v1.Number
+	16, // 16: bobby.session.v1.Widget.sports:type_name -> bobby.session.v1.Sports
+	17, // 17: bobby.session.v1.Widget.pronunciation:type_name -> bobby.session.v1.Pronunciation
+	18, // 18: bobby.session.v1.Widget.page:type_name -> bobby.session.v1.Page
+	30, // 19: bobby.session.v1.WeatherMultiDay.days:type_name -> bobby.session.v1.WeatherMultiDay.Day
+	20, // 20: bobby.session.v1.ActionRequest.set_alarm:type_name -> bobby.session.v1.SetAlarm
+	21, // 21: bobby.session.v1.ActionRequest.get_alarm:type_name -> bobby.session.v1.GetAlarm
+	22, // 22: bobby.session.v1.ActionRequest.set_reminder:type_name -> bobby.session.v1.SetReminder
+	23, // 23: bobby.session.v1.ActionRequest.get_reminders:type_name -> bobby.session.v1.GetReminders
+	24, // 24: bobby.session.v1.ActionRequest.delete_reminder:type_name -> bobby.session.v1.DeleteReminder
+	25, // 25: bobby.session.v1.ActionRequest.send_feedback:type_name -> bobby.session.v1.SendFeedback
+	26, // 26: bobby.session.v1.ActionRequest.scan_barcode:type_name -> bobby.session.v1.ScanBarcode
+	27, // 27: bobby.session.v1.ActionRequest.set_interval_timer:type_name -> bobby.session.v1.SetIntervalTimer
+	28, // 28: bobby.session.v1.ActionRequest.stopwatch:type_name -> bobby.session.v1.Stopwatch
+	31, // 29: bobby.session.v1.SetReminder.trigger:type_name -> bobby.session.v1.SetReminder.Trigger
+	32, // 30: bobby.session.v1.SetIntervalTimer.phases:type_name -> bobby.session.v1.SetIntervalTimer.Phase
+	33, // 31: bobby.session.v1.ClientActionResponse.result:type_name -> google.protobuf.Struct
+	32, // [32:32] is the sub-list for method output_type
+	32, // [32:32] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
+}
+
+func init() { file_bobby_session_v1_session_proto_init() }
+func file_bobby_session_v1_session_proto_init() {
+	if File_bobby_session_v1_session_proto != nil {
+		return
+	}
+	file_bobby_session_v1_session_proto_msgTypes[0].OneofWrappers = []any{
+		(*ServerMessage_Content)(nil),
+		(*ServerMessage_Thought)(nil),
+		(*ServerMessage_Progress)(nil),
+		(*ServerMessage_Action)(nil),
+		(*ServerMessage_Warning)(nil),
+		(*ServerMessage_Sources)(nil),
+		(*ServerMessage_ThreadId)(nil),
+		(*ServerMessage_Done)(nil),
+		(*ServerMessage_Announcement)(nil),
+		(*ServerMessage_Suggestions)(nil),
+	}
+	file_bobby_session_v1_session_proto_msgTypes[2].OneofWrappers = []any{
+		(*Chunk_Text)(nil),
+		(*Chunk_Widget)(nil),
+	}
+	file_bobby_session_v1_session_proto_msgTypes[9].OneofWrappers = []any{
+		(*Widget_WeatherCurrent)(nil),
+		(*Widget_WeatherSingleDay)(nil),
+		(*Widget_WeatherMultiDay)(nil),
+		(*Widget_Timer)(nil),
+		(*Widget_Number)(nil),
+		(*Widget_Sports)(nil),
+		(*Widget_Pronunciation)(nil),
+		(*Widget_Page)(nil),
+	}
+	file_bobby_session_v1_session_proto_msgTypes[10].OneofWrappers = []any{}
+	file_bobby_session_v1_session_proto_msgTypes[15].OneofWrappers = []any{}
+	file_bobby_session_v1_session_proto_msgTypes[18].OneofWrappers = []any{
+		(*ActionRequest_SetAlarm)(nil),
+		(*ActionRequest_GetAlarm)(nil),
+		(*ActionRequest_SetReminder)(nil),
+		(*ActionRequest_GetReminders)(nil),
+		(*ActionRequest_DeleteReminder)(nil),
+		(*ActionRequest_SendFeedback)(nil),
+		(*ActionRequest_ScanBarcode)(nil),
+		(*ActionRequest_SetIntervalTimer)(nil),
+		(*ActionRequest_Stopwatch)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bobby_session_v1_session_proto_rawDesc), len(file_bobby_session_v1_session_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   32,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bobby_session_v1_session_proto_goTypes,
+		DependencyIndexes: file_bobby_session_v1_session_proto_depIdxs,
+		EnumInfos:         file_bobby_session_v1_session_proto_enumTypes,
+		MessageInfos:      file_bobby_session_v1_session_proto_msgTypes,
+	}.Build()
+	File_bobby_session_v1_session_proto = out.File
+	file_bobby_session_v1_session_proto_goTypes = nil
+	file_bobby_session_v1_session_proto_depIdxs = nil
+}
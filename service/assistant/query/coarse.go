@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"math"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+)
+
+const kmPerDegreeLatitude = 111.32
+
+// Coarsened returns the location snapped to the centre of a grid square of the configured size, for sending to
+// third parties that don't need to know exactly where the user is. Snapping, rather than adding random jitter, means
+// that repeated requests can't be averaged to recover the real location, and that they still hit the same caches.
+func (l Location) Coarsened() Location {
+	km := config.GetConfig().LocationPrecisionKm
+	if km <= 0 {
+		return l
+	}
+	latStep := km / kmPerDegreeLatitude
+	lat := snap(l.Lat, latStep)
+	// Degrees of longitude get shorter towards the poles, so widen the step to keep the squares roughly square. Use
+	// the snapped latitude so that everyone in the same row of squares gets the same step.
+	lonStep := latStep
+	if c := math.Cos(lat * math.Pi / 180); c > 0.01 {
+		lonStep = latStep / c
+	}
+	return Location{
+		Lat: math.Max(-90, math.Min(90, lat)),
+		Lon: math.Remainder(snap(l.Lon, lonStep), 360),
+	}
+}
+
+func snap(v, step float64) float64 {
+	return (math.Floor(v/step) + 0.5) * step
+}
+
+// CoarseLocationFromContext is like LocationFromContext, but returns the location coarsened for privacy (see
+// Location.Coarsened). Use it for anything that doesn't need to know precisely where the user is, like weather.
+func CoarseLocationFromContext(ctx context.Context) *Location {
+	location := LocationFromContext(ctx)
+	if location == nil {
+		return nil
+	}
+	coarse := location.Coarsened()
+	return &coarse
+}
@@ -28,6 +28,16 @@ type Location struct {
 	Lon float64
 }
 
+// ProfileKid is the profile used for children's watches. It restricts the available tools, simplifies
+// the language used in responses, and forces the strictest content filter.
+const ProfileKid = "kid"
+
+// Home is the user's home location, as saved in their preferences.
+type Home struct {
+	Location
+	Name string
+}
+
 type queryContext struct {
 	location          *Location
 	tzOffset          int
@@ -36,6 +46,19 @@ type queryContext struct {
 	preferredLanguage string
 	preferredUnits    string
 	threadId          string
+	contentFilter     string
+	profile           string
+	accessibility     bool
+	analyticsOptIn    bool
+	sandbox           bool
+	persona           string
+	verbosity         string
+	home              *Home
+	iconSet           string
+	tempDecimals      int
+	coarseLocation    bool
+	appVersion        string
+	debug             bool
 }
 
 type qckt int
@@ -54,12 +77,35 @@ func ContextWith(ctx context.Context, q url.Values) context.Context {
 			}
 		}
 	}
+	var home *Home
+	if q.Get("homeLat") != "" && q.Get("homeLon") != "" {
+		lat, latErr := strconv.ParseFloat(q.Get("homeLat"), 64)
+		lon, lonErr := strconv.ParseFloat(q.Get("homeLon"), 64)
+		if latErr == nil && lonErr == nil {
+			home = &Home{
+				Location: Location{Lat: lat, Lon: lon},
+				Name:     q.Get("homeName"),
+			}
+		}
+	}
 	offset, _ := strconv.Atoi(q.Get("tzOffset"))
 	supportedActions := strings.Split(q.Get("actions"), ",")
 	supportedWidgets := strings.Split(q.Get("widgets"), ",")
 	preferredLanguage := q.Get("lang")
 	preferredUnits := q.Get("units")
 	threadId := q.Get("threadId")
+	contentFilter := q.Get("contentFilter")
+	profile := q.Get("profile")
+	accessibility, _ := strconv.ParseBool(q.Get("accessible"))
+	analyticsOptIn, _ := strconv.ParseBool(q.Get("analytics"))
+	sandbox, _ := strconv.ParseBool(q.Get("sandbox"))
+	persona := q.Get("persona")
+	verbosity := q.Get("verbosity")
+	iconSet := q.Get("iconSet")
+	tempDecimals, _ := strconv.Atoi(q.Get("tempDecimals"))
+	coarseLocation := q.Get("locationPrecision") == "coarse"
+	appVersion := q.Get("version")
+	debug, _ := strconv.ParseBool(q.Get("debug"))
 	qc := queryContext{
 		location:          location,
 		tzOffset:          offset,
@@ -68,6 +114,19 @@ func ContextWith(ctx context.Context, q url.Values) context.Context {
 		preferredLanguage: preferredLanguage,
 		preferredUnits:    preferredUnits,
 		threadId:          threadId,
+		contentFilter:     contentFilter,
+		profile:           profile,
+		accessibility:     accessibility,
+		analyticsOptIn:    analyticsOptIn,
+		sandbox:           sandbox,
+		persona:           persona,
+		verbosity:         verbosity,
+		home:              home,
+		iconSet:           iconSet,
+		tempDecimals:      tempDecimals,
+		coarseLocation:    coarseLocation,
+		appVersion:        appVersion,
+		debug:             debug,
 	}
 	ctx = context.WithValue(ctx, queryContextKey, qc)
 	return ctx
@@ -112,3 +171,93 @@ func SupportsWidget(ctx context.Context, widget string) bool {
 func ThreadIdFromContext(ctx context.Context) string {
 	return ctx.Value(queryContextKey).(queryContext).threadId
 }
+
+func ContentFilterFromContext(ctx context.Context) string {
+	return ctx.Value(queryContextKey).(queryContext).contentFilter
+}
+
+func ProfileFromContext(ctx context.Context) string {
+	return ctx.Value(queryContextKey).(queryContext).profile
+}
+
+func IsKidMode(ctx context.Context) bool {
+	return ProfileFromContext(ctx) == ProfileKid
+}
+
+// AccessibilityModeFromContext reports whether responses should be tailored for screen readers.
+func AccessibilityModeFromContext(ctx context.Context) bool {
+	return ctx.Value(queryContextKey).(queryContext).accessibility
+}
+
+// AnalyticsOptInFromContext reports whether the user has agreed to share anonymous usage analytics.
+func AnalyticsOptInFromContext(ctx context.Context) bool {
+	return ctx.Value(queryContextKey).(queryContext).analyticsOptIn
+}
+
+// SandboxFromContext reports whether the client asked for actions to be simulated rather than carried out.
+func SandboxFromContext(ctx context.Context) bool {
+	return ctx.Value(queryContextKey).(queryContext).sandbox
+}
+
+// PersonaFromContext returns the response style the user has chosen, if any.
+func PersonaFromContext(ctx context.Context) string {
+	return ctx.Value(queryContextKey).(queryContext).persona
+}
+
+// VerbosityFromContext returns how long the user would like answers to be: "brief", "normal" or "detailed". It's
+// empty if they haven't said, which is the same as "normal".
+func VerbosityFromContext(ctx context.Context) string {
+	return ctx.Value(queryContextKey).(queryContext).verbosity
+}
+
+// HomeFromContext returns the user's home location, or nil if they haven't set one.
+func HomeFromContext(ctx context.Context) *Home {
+	return ctx.Value(queryContextKey).(queryContext).home
+}
+
+// IconSetFromContext returns the set of weather icons the watch asked for, if any.
+func IconSetFromContext(ctx context.Context) string {
+	return ctx.Value(queryContextKey).(queryContext).iconSet
+}
+
+// TemperatureDecimalsFromContext returns how many decimal places the watch wants temperatures shown with in widgets.
+func TemperatureDecimalsFromContext(ctx context.Context) int {
+	return ctx.Value(queryContextKey).(queryContext).tempDecimals
+}
+
+// LocationIsCoarseFromContext reports whether the user has chosen to share only roughly where they are, in which case
+// the location is only good to the nearest town or so.
+func LocationIsCoarseFromContext(ctx context.Context) bool {
+	return ctx.Value(queryContextKey).(queryContext).coarseLocation
+}
+
+// AppVersionFromContext returns the version of the app the query came from, e.g. "1.3.0", if it said.
+func AppVersionFromContext(ctx context.Context) string {
+	return ctx.Value(queryContextKey).(queryContext).appVersion
+}
+
+// AppVersionAtLeast reports whether the app the query came from is at least the given version, for features the watch
+// needs to have been updated to support. Apps that don't say which version they are are assumed to be too old.
+func AppVersionAtLeast(ctx context.Context, version string) bool {
+	have := strings.Split(AppVersionFromContext(ctx), ".")
+	want := strings.Split(version, ".")
+	for i, w := range want {
+		wn, _ := strconv.Atoi(w)
+		hn := 0
+		if i < len(have) {
+			var err error
+			if hn, err = strconv.Atoi(have[i]); err != nil {
+				return false
+			}
+		}
+		if hn != wn {
+			return hn > wn
+		}
+	}
+	return true
+}
+
+// DebugFromContext reports whether the client asked for the turn to be captured for replay (see the replay package).
+func DebugFromContext(ctx context.Context) bool {
+	return ctx.Value(queryContextKey).(queryContext).debug
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quickaction defines the predefined queries the watch can offer as one-tap menu items, which skip dictation
+// entirely. Some of them are answered directly with a widget, without asking the model.
+package quickaction
+
+import "github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+
+// Action is a predefined query. Its label and prompt are in the message catalogs, under "quick_action.<ID>.label"
+// and "quick_action.<ID>.prompt".
+type Action struct {
+	ID string
+	// The widget that answers the action for the user's current location, e.g. "WEATHER-CURRENT", if it can be
+	// answered without the model. Empty if the prompt should go to the model.
+	Widget string
+}
+
+// Description is what the watch needs to show an action in a menu.
+type Description struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+var actions = []Action{
+	{ID: "weather_now", Widget: "WEATHER-CURRENT"},
+	{ID: "weather_forecast", Widget: "WEATHER-MULTI-DAY"},
+	{ID: "timers"},
+	{ID: "next_reminder"},
+}
+
+// Find returns the action with the given ID, and whether there is one.
+func Find(id string) (Action, bool) {
+	for _, a := range actions {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Action{}, false
+}
+
+// List describes every action, in the order the watch should show them, localized into the given language.
+func List(language string) []Description {
+	descriptions := make([]Description, 0, len(actions))
+	for _, a := range actions {
+		descriptions = append(descriptions, Description{ID: a.ID, Label: a.Label(language)})
+	}
+	return descriptions
+}
+
+// Label is the action's name in a menu, e.g. "Weather now".
+func (a Action) Label(language string) string {
+	return i18n.TLang(language, "quick_action."+a.ID+".label")
+}
+
+// Prompt is the action written as if the user had asked for it, e.g. "What's the weather like here right now?". It's
+// what the model sees, and what follow-ups in the same thread refer back to.
+func (a Action) Prompt(language string) string {
+	return i18n.TLang(language, "quick_action."+a.ID+".prompt")
+}
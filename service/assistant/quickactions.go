@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/analytics"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quickaction"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/widgets"
+)
+
+// widgetUnits maps the user's unit preference to the units weather widgets take. "both" gets metric, since a widget
+// can only show one. There's no mapping for an unset preference, because only the model knows what's usual where
+// the user is.
+var widgetUnits = map[string]string{
+	"imperial": "imperial",
+	"metric":   "metric",
+	"uk":       "uk hybrid",
+	"both":     "metric",
+}
+
+// handleQuickActions lists the quick actions, for the watch to build a menu from. The labels are localized into the
+// language in the "lang" parameter.
+func (s *Service) handleQuickActions(rw http.ResponseWriter, r *http.Request) {
+	response, err := json.Marshal(map[string]any{
+		"actions": quickaction.List(r.URL.Query().Get("lang")),
+	})
+	if err != nil {
+		requestid.Logf(r.Context(), "Error marshalling quick actions: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(response)
+}
+
+// applyQuickAction replaces the prompt with the session's quick action, written out in the user's language, so that
+// the model and the thread see it as if the user had asked. It returns false if there's no such action.
+func (ps *PromptSession) applyQuickAction(ctx context.Context, prompt *genai.Content) bool {
+	action, ok := quickaction.Find(ps.quickAction)
+	if !ok {
+		return false
+	}
+	beeline.AddField(ctx, "quick_action", action.ID)
+	ps.prompt = action.Prompt(i18n.LanguageFromContext(ctx))
+	prompt.Parts = []*genai.Part{{Text: ps.prompt}}
+	return true
+}
+
+// tryQuickAction answers the session's quick action with a widget for the user's current location, if it's that kind
+// of action, without asking the model. Like the fast path, it returns false if the model should answer instead.
+func (ps *PromptSession) tryQuickAction(ctx context.Context) (fastPathResult, bool) {
+	action, ok := quickaction.Find(ps.quickAction)
+	if !ok || action.Widget == "" {
+		return fastPathResult{}, false
+	}
+	units, ok := widgetUnits[query.PreferredUnitsFromContext(ctx)]
+	if !ok || query.CoarseLocationFromContext(ctx) == nil {
+		return fastPathResult{}, false
+	}
+	ctx, span := beeline.StartSpan(ctx, "quick_action")
+	defer span.Send()
+	span.AddField("quick_action", action.ID)
+
+	tag := fmt.Sprintf("<!%s location=here units=%s!>", action.Widget, units)
+	processed, err := widgets.ProcessWidget(ctx, tag)
+	analytics.Record(ctx, analytics.Event{Kind: analytics.EventWidgetShown, Widget: widgetName(tag), Success: err == nil})
+	if err != nil {
+		span.AddField("error", err)
+		requestid.Logf(ctx, "quick action %s failed, falling back to the model: %v\n", action.ID, err)
+		return fastPathResult{}, false
+	}
+	wd := processed.(widgets.Widget)
+	// The model gets the widget as text, so that it can answer follow-ups about it.
+	text := widgets.FallbackText(ctx, wd)
+	reply := text
+	if query.SupportsWidget(ctx, wd.Capability()) {
+		if jsoned, err := widgets.Marshal(wd); err == nil {
+			reply = "<<!!WIDGET:" + string(jsoned) + "!!>>"
+		}
+	}
+	return fastPathResult{
+		messages: []*genai.Content{{Parts: []*genai.Part{{Text: text}}, Role: "model"}},
+		reply:    reply,
+	}, true
+}
@@ -17,12 +17,13 @@ package quota
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/honeycombio/beeline-go"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 )
 
 // one credit is worth $0.000000025.
@@ -104,10 +105,15 @@ func (q *Tracker) chargeCredits(ctx context.Context, user, credits int) (int, er
 
 func (q *Tracker) ChargeCredits(ctx context.Context, credits int) error {
 	used, err := q.chargeCredits(ctx, q.userId, credits)
-	log.Printf("Charging %d credits to user %d. Total used: %d\n", credits, q.userId, used)
+	requestid.Logf(ctx, "Charging %d credits to user %d. Total used: %d\n", credits, q.userId, used)
 	return err
 }
 
+// Reset forgets the credits the user has used this month.
+func (q *Tracker) Reset(ctx context.Context) error {
+	return q.redis.Del(ctx, keyForUserQuota(q.userId)).Err()
+}
+
 func (q *Tracker) ChargeUserOrGlobalQuota(ctx context.Context, quotaType string, globalMax int, userCredits int) error {
 	ctx, span := beeline.StartSpan(ctx, "charge_user_or_global_quota")
 	defer span.Send()
@@ -117,7 +123,7 @@ func (q *Tracker) ChargeUserOrGlobalQuota(ctx context.Context, quotaType string,
 		return err
 	}
 	if charged {
-		log.Printf("Charged against global quota %s\n", quotaType)
+		requestid.Logf(ctx, "Charged against global quota %s\n", quotaType)
 		return nil
 	}
 	// Charge the user for the function call.
@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"github.com/honeycombio/beeline-go"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/household"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
 )
 
 type UserInfo struct {
@@ -39,30 +41,41 @@ func GetUserInfo(ctx context.Context, token string) (*UserInfo, error) {
 	if token == "" {
 		return nil, fmt.Errorf("no token provided")
 	}
+	// Members of a self-hosted household are known without asking anyone else.
+	if u, ok, err := household.Lookup(ctx, storage.GetRedis(), token); err != nil {
+		requestid.Logf(ctx, "Error looking up household user: %v", err)
+		return nil, err
+	} else if ok {
+		span.AddField("household", true)
+		return &UserInfo{UserId: u.ID, HasSubscription: true}, nil
+	}
+	if config.GetConfig().UserIdentificationURL == "" {
+		return nil, fmt.Errorf("unknown token")
+	}
 	values := &url.Values{}
 	values.Set("token", token)
 	s := values.Encode()
 	r := strings.NewReader(s)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.GetConfig().UserIdentificationURL, r)
 	if err != nil {
-		log.Printf("Error creating user id request: %v", err)
+		requestid.Logf(ctx, "Error creating user id request: %v", err)
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := requestid.Client.Do(req)
 	if err != nil {
-		log.Printf("Error getting user id: %v", err)
+		requestid.Logf(ctx, "Error getting user id: %v", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		log.Printf("Error getting user id: %v", resp.Status)
+		requestid.Logf(ctx, "Error getting user id: %v", resp.Status)
 		result, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("error from user id service: %s", string(result))
 	}
 	var u UserInfo
 	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
-		log.Printf("Error decoding user id response: %v", err)
+		requestid.Logf(ctx, "Error decoding user id response: %v", err)
 		return nil, err
 	}
 	return &u, nil
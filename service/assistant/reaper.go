@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"google.golang.org/genai"
+	"nhooyr.io/websocket"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/sessionlock"
+)
+
+// reapInterval is how often the reaper looks for sessions to cancel.
+const reapInterval = 15 * time.Second
+
+var (
+	// errSessionIdle is the cause of a session being cancelled for doing nothing for too long.
+	errSessionIdle = errors.New("session idle")
+	// errMemoryCeiling is the cause of a session being cancelled to bring the conversations held in memory back under
+	// the limit.
+	errMemoryCeiling = errors.New("session memory ceiling reached")
+)
+
+// liveSession is what the reaper knows about a session that's running in this process.
+type liveSession struct {
+	cancel context.CancelCauseFunc
+	// When the session last made progress, in Unix nanoseconds.
+	lastActive atomic.Int64
+	// Roughly how much memory the session's conversation takes up.
+	bufferedBytes atomic.Int64
+	// Whether the reaper has already cancelled the session, which may take a moment to finish.
+	reaped atomic.Bool
+}
+
+// touch records that the session is making progress.
+func (s *liveSession) touch() {
+	s.lastActive.Store(time.Now().UnixNano())
+}
+
+// setConversation records how big the session's conversation has grown, which also counts as progress.
+func (s *liveSession) setConversation(messages []*genai.Content) {
+	s.bufferedBytes.Store(int64(conversationSize(messages)))
+	s.touch()
+}
+
+var liveSessions = struct {
+	mu       sync.Mutex
+	sessions map[*liveSession]struct{}
+}{sessions: map[*liveSession]struct{}{}}
+
+// trackSession registers a session with the reaper, returning a context the reaper can cancel. The returned function
+// must be called when the session ends.
+func trackSession(ctx context.Context) (context.Context, *liveSession, func()) {
+	s := &liveSession{}
+	ctx, s.cancel = context.WithCancelCause(ctx)
+	s.touch()
+	liveSessions.mu.Lock()
+	liveSessions.sessions[s] = struct{}{}
+	liveSessions.mu.Unlock()
+	return ctx, s, func() {
+		liveSessions.mu.Lock()
+		delete(liveSessions.sessions, s)
+		liveSessions.mu.Unlock()
+		s.cancel(nil)
+	}
+}
+
+// sessionCount returns how many sessions are running in this process.
+func sessionCount() int {
+	liveSessions.mu.Lock()
+	defer liveSessions.mu.Unlock()
+	return len(liveSessions.sessions)
+}
+
+// waitForSessions waits until every session running in this process has ended, or ctx is done. Sessions are
+// websockets, which http.Server.Shutdown doesn't wait for, since it lets go of a connection once it's hijacked.
+func waitForSessions(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for sessionCount() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// StartReaper cancels sessions that have been idle for longer than SESSION_IDLE_TIMEOUT, and the largest sessions
+// whenever the conversations held in memory add up to more than SESSION_MEMORY_LIMIT_MB, until the context is
+// cancelled. Without it, a session stuck waiting on something that never comes would hold on to its memory forever.
+func StartReaper(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(reapInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reap(ctx, time.Now())
+			}
+		}
+	}()
+}
+
+func reap(ctx context.Context, now time.Time) {
+	cfg := config.GetConfig()
+	liveSessions.mu.Lock()
+	var live []*liveSession
+	for s := range liveSessions.sessions {
+		live = append(live, s)
+	}
+	liveSessions.mu.Unlock()
+
+	idle := 0
+	var total int64
+	var remaining []*liveSession
+	for _, s := range live {
+		if s.reaped.Load() {
+			continue
+		}
+		if cfg.SessionIdleTimeout > 0 && now.Sub(time.Unix(0, s.lastActive.Load())) > cfg.SessionIdleTimeout {
+			s.reaped.Store(true)
+			s.cancel(errSessionIdle)
+			idle++
+			continue
+		}
+		total += s.bufferedBytes.Load()
+		remaining = append(remaining, s)
+	}
+
+	overLimit := 0
+	limit := int64(cfg.SessionMemoryLimitMB) << 20
+	if limit > 0 && total > limit {
+		// Cancelling the biggest first frees the most while disturbing the fewest people.
+		sort.Slice(remaining, func(i, j int) bool {
+			return remaining[i].bufferedBytes.Load() > remaining[j].bufferedBytes.Load()
+		})
+		for _, s := range remaining {
+			if total <= limit {
+				break
+			}
+			total -= s.bufferedBytes.Load()
+			s.reaped.Store(true)
+			s.cancel(errMemoryCeiling)
+			overLimit++
+		}
+	}
+
+	if idle > 0 || overLimit > 0 {
+		log.Printf("Reaped %d idle sessions and %d over the memory ceiling; %d sessions remain, holding %d bytes.", idle, overLimit, len(remaining)-overLimit, total)
+		_, span := beeline.StartSpan(ctx, "session_reaper")
+		span.AddField("live_sessions", len(live))
+		span.AddField("idle_reaped", idle)
+		span.AddField("memory_reaped", overLimit)
+		span.AddField("buffered_bytes", total)
+		span.Send()
+	}
+}
+
+// conversationSize estimates how much memory a conversation takes up, from the size of its content.
+func conversationSize(messages []*genai.Content) int {
+	size := 0
+	for _, m := range messages {
+		for _, p := range m.Parts {
+			size += len(p.Text)
+			if p.FunctionCall != nil {
+				j, _ := json.Marshal(p.FunctionCall)
+				size += len(j)
+			}
+			if p.FunctionResponse != nil {
+				j, _ := json.Marshal(p.FunctionResponse)
+				size += len(j)
+			}
+		}
+	}
+	return size
+}
+
+// cancellation returns the close code and message key for a session whose context was cancelled on purpose, rather
+// than because the watch went away, and ok if it was.
+func cancellation(ctx context.Context) (code websocket.StatusCode, key string, ok bool) {
+	switch cause := context.Cause(ctx); {
+	case sessionlock.Superseded(ctx):
+		return websocket.StatusGoingAway, "session.error.superseded", true
+	case errors.Is(cause, errSessionIdle):
+		return websocket.StatusGoingAway, "session.error.idle", true
+	case errors.Is(cause, errMemoryCeiling):
+		return websocket.StatusTryAgainLater, "session.error.overloaded", true
+	}
+	return 0, "", false
+}
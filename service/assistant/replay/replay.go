@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay captures everything that went into each of the model's calls in a turn, so that when someone reports
+// that the model did something weird, the turn can be run again locally (see cmd/replay) with the same prompt, history,
+// tool results and generation settings.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/genai"
+)
+
+// maxPerUser is how many captures are kept for each user. Older ones are deleted as new ones come in.
+const maxPerUser = 20
+
+// ErrNotFound is returned by Load for a capture that doesn't exist, or has expired.
+var ErrNotFound = errors.New("no such capture")
+
+// Call is one call to the model, and what came back.
+type Call struct {
+	// The conversation so far, including the results of any functions called earlier in the turn.
+	Contents []*genai.Content `json:"contents"`
+	// The system prompt, tools, seed and everything else the model was called with.
+	Config *genai.GenerateContentConfig `json:"config"`
+	// What the model said.
+	Response string `json:"response,omitempty"`
+	// The function the model called, if any.
+	FunctionCall *genai.FunctionCall `json:"function_call,omitempty"`
+}
+
+// Bundle is everything captured from a single turn.
+type Bundle struct {
+	RequestID string    `json:"request_id"`
+	UserID    int       `json:"user_id"`
+	ThreadID  string    `json:"thread_id"`
+	Captured  time.Time `json:"captured"`
+	Prompt    string    `json:"prompt"`
+	Model     string    `json:"model"`
+	// The seed every call in the turn was made with.
+	Seed int64 `json:"seed"`
+	// Whether the turn was answered by the local model (see the localmodel package) rather than Gemini.
+	Offline bool   `json:"offline,omitempty"`
+	Calls   []Call `json:"calls"`
+
+	mu sync.Mutex
+}
+
+// Add records a call to the model. It's safe to call on a nil Bundle, which does nothing, so that callers needn't
+// check whether the turn is being captured.
+func (b *Bundle) Add(call Call) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// The conversation grows as the turn goes on, so it's copied as it was at the time.
+	call.Contents = append([]*genai.Content(nil), call.Contents...)
+	b.Calls = append(b.Calls, call)
+}
+
+// SetOffline records that the rest of the turn is being answered by the local model.
+func (b *Bundle) SetOffline() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Offline = true
+}
+
+type contextKey struct{}
+
+// WithBundle returns a context in which calls to the model are captured to b.
+func WithBundle(ctx context.Context, b *Bundle) context.Context {
+	return context.WithValue(ctx, contextKey{}, b)
+}
+
+// FromContext returns the bundle the turn is being captured to, or nil if it isn't.
+func FromContext(ctx context.Context) *Bundle {
+	b, _ := ctx.Value(contextKey{}).(*Bundle)
+	return b
+}
+
+func bundleKey(requestID string) string {
+	return "replay:" + requestID
+}
+
+func userKey(userID int) string {
+	return fmt.Sprintf("replay:user:%d", userID)
+}
+
+// Store saves the bundle for ttl, under its request ID.
+func Store(ctx context.Context, rd *redis.Client, b *Bundle, ttl time.Duration) error {
+	ctx, span := beeline.StartSpan(ctx, "replay.store")
+	defer span.Send()
+	b.mu.Lock()
+	j, err := json.Marshal(b)
+	b.mu.Unlock()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	// The captures about to drop off the end of the list go too, so that every capture can be found through the list
+	// (by DeleteAll, say).
+	stale, err := rd.LRange(ctx, userKey(b.UserID), maxPerUser-1, -1).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	pipe := rd.TxPipeline()
+	pipe.Set(ctx, bundleKey(b.RequestID), j, ttl)
+	for _, id := range stale {
+		pipe.Del(ctx, bundleKey(id))
+	}
+	pipe.LPush(ctx, userKey(b.UserID), b.RequestID)
+	pipe.LTrim(ctx, userKey(b.UserID), 0, maxPerUser-1)
+	pipe.Expire(ctx, userKey(b.UserID), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	return nil
+}
+
+// Load returns the bundle captured for the request.
+func Load(ctx context.Context, rd *redis.Client, requestID string) (*Bundle, error) {
+	ctx, span := beeline.StartSpan(ctx, "replay.load")
+	defer span.Send()
+	j, err := rd.Get(ctx, bundleKey(requestID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	var b Bundle
+	if err := json.Unmarshal(j, &b); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	return &b, nil
+}
+
+// List returns the request IDs of the user's most recent captures, newest first. Some may have expired.
+func List(ctx context.Context, rd *redis.Client, userID int) ([]string, error) {
+	ctx, span := beeline.StartSpan(ctx, "replay.list")
+	defer span.Send()
+	ids, err := rd.LRange(ctx, userKey(userID), 0, -1).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// DeleteAll removes every capture of the user's requests.
+func DeleteAll(ctx context.Context, rd *redis.Client, userID int) error {
+	ctx, span := beeline.StartSpan(ctx, "replay.delete_all")
+	defer span.Send()
+	ids, err := rd.LRange(ctx, userKey(userID), 0, -1).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	keys := []string{userKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, bundleKey(id))
+	}
+	if err := rd.Del(ctx, keys...).Err(); err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	return nil
+}
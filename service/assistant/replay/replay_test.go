@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStoreKeepsMaxPerUser(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+	for i := range maxPerUser + 5 {
+		if err := Store(ctx, rd, &Bundle{RequestID: fmt.Sprintf("req%d", i), UserID: 1}, time.Hour); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+	ids, err := List(ctx, rd, 1)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(ids) != maxPerUser || ids[0] != fmt.Sprintf("req%d", maxPerUser+4) {
+		t.Errorf("List() = %q, want the newest %d captures, newest first", ids, maxPerUser)
+	}
+	if _, err := Load(ctx, rd, "req4"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(req4) = %v, want ErrNotFound for a capture pushed off the list", err)
+	}
+	if _, err := Load(ctx, rd, "req5"); err != nil {
+		t.Errorf("Load(req5) = %v, want the oldest capture still listed", err)
+	}
+
+	if err := DeleteAll(ctx, rd, 1); err != nil {
+		t.Fatalf("DeleteAll: %v", err)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("after DeleteAll, the keys left are %q, want none", keys)
+	}
+}
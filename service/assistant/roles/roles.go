@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package roles decides who may use the admin API. Whoever holds ADMIN_TOKEN is always an owner, and owners can make
+// other users owners or admins, so that a community deployment can share the work of running it without sharing the
+// token.
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// Role is what a user is trusted to do.
+type Role string
+
+const (
+	// Owners can do everything, including managing users and roles, and reloading the configuration.
+	Owner Role = "owner"
+	// Admins can moderate: they can see who the users are and what Bobby has done for them, and manage their quota.
+	Admin Role = "admin"
+	// Users can't use the admin API at all. Everyone is a user unless made something else.
+	User Role = "user"
+)
+
+// Roles are all the roles, most trusted first.
+var Roles = []Role{Owner, Admin, User}
+
+// Permission is something done through the admin API.
+type Permission string
+
+const (
+	ViewUsers    Permission = "view_users"
+	ManageUsers  Permission = "manage_users"
+	ManageQuota  Permission = "manage_quota"
+	ManageRoles  Permission = "manage_roles"
+	ReloadConfig Permission = "reload_config"
+	ViewAudit    Permission = "view_audit"
+	// Captured turns hold whole conversations, so this is kept to owners.
+	ViewCaptures Permission = "view_captures"
+)
+
+var permissions = map[Role][]Permission{
+	Owner: {ViewUsers, ManageUsers, ManageQuota, ManageRoles, ReloadConfig, ViewAudit, ViewCaptures},
+	Admin: {ViewUsers, ManageQuota, ViewAudit},
+}
+
+// ErrInvalid is returned by Set for a role that doesn't exist.
+var ErrInvalid = errors.New("invalid role")
+
+const rolesKey = "roles"
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(p Permission) bool {
+	return slices.Contains(permissions[r], p)
+}
+
+// Get returns the user's role, which is User unless they've been given another.
+func Get(ctx context.Context, rd *redis.Client, userID int) (Role, error) {
+	ctx, span := beeline.StartSpan(ctx, "roles.get")
+	defer span.Send()
+	role, err := rd.HGet(ctx, rolesKey, strconv.Itoa(userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return User, nil
+	}
+	if err != nil {
+		span.AddField("error", err)
+		return "", err
+	}
+	if !slices.Contains(Roles, Role(role)) {
+		return User, nil
+	}
+	return Role(role), nil
+}
+
+// Set gives the user a role. Giving them User removes whatever role they had.
+func Set(ctx context.Context, rd *redis.Client, userID int, role Role) error {
+	ctx, span := beeline.StartSpan(ctx, "roles.set")
+	defer span.Send()
+	if !slices.Contains(Roles, role) {
+		return fmt.Errorf("%w %q", ErrInvalid, role)
+	}
+	var err error
+	if role == User {
+		err = rd.HDel(ctx, rolesKey, strconv.Itoa(userID)).Err()
+	} else {
+		err = rd.HSet(ctx, rolesKey, strconv.Itoa(userID), string(role)).Err()
+	}
+	if err != nil {
+		span.AddField("error", err)
+	}
+	return err
+}
+
+// List returns the role of every user who has been given one, by user ID.
+func List(ctx context.Context, rd *redis.Client) (map[int]Role, error) {
+	ctx, span := beeline.StartSpan(ctx, "roles.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, rolesKey).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	result := map[int]Role{}
+	for id, role := range entries {
+		userID, err := strconv.Atoi(id)
+		if err != nil || !slices.Contains(Roles, Role(role)) {
+			continue
+		}
+		result[userID] = Role(role)
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package safety
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+type FilterLevel string
+
+const (
+	FilterStrict   FilterLevel = "strict"
+	FilterStandard FilterLevel = "standard"
+	FilterOff      FilterLevel = "off"
+)
+
+var harmCategories = []genai.HarmCategory{
+	genai.HarmCategoryHateSpeech,
+	genai.HarmCategoryDangerousContent,
+	genai.HarmCategoryHarassment,
+	genai.HarmCategorySexuallyExplicit,
+}
+
+// FilterLevelFromContext returns the content filter level the user asked for. Unknown values fall back to the
+// standard level, as does asking for no filtering on a deployment that doesn't permit it.
+func FilterLevelFromContext(ctx context.Context) FilterLevel {
+	// Kid mode always gets the strictest filtering, regardless of what else was requested.
+	if query.IsKidMode(ctx) {
+		return FilterStrict
+	}
+	switch FilterLevel(strings.ToLower(query.ContentFilterFromContext(ctx))) {
+	case FilterStrict:
+		return FilterStrict
+	case FilterOff:
+		if config.GetConfig().AllowUnfilteredContent {
+			return FilterOff
+		}
+	}
+	return FilterStandard
+}
+
+// SettingsForLevel returns the Gemini safety settings corresponding to the given filter level.
+func SettingsForLevel(level FilterLevel) []*genai.SafetySetting {
+	var threshold genai.HarmBlockThreshold
+	switch level {
+	case FilterStrict:
+		threshold = genai.HarmBlockThresholdBlockLowAndAbove
+	case FilterOff:
+		threshold = genai.HarmBlockThresholdBlockNone
+	default:
+		threshold = genai.HarmBlockThresholdBlockMediumAndAbove
+	}
+	settings := make([]*genai.SafetySetting, 0, len(harmCategories))
+	for _, category := range harmCategories {
+		settings = append(settings, &genai.SafetySetting{
+			Category:  category,
+			Threshold: threshold,
+		})
+	}
+	return settings
+}
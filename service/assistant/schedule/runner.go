@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+	"nhooyr.io/websocket"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/preferences"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// defaultTimelineURL is Rebble's timeline API, used unless TIMELINE_URL says otherwise.
+const defaultTimelineURL = "https://timeline-api.rebble.io"
+
+// checkInterval is how often to look for queries that are due.
+const checkInterval = time.Minute
+
+// queryTimeout is the longest a scheduled query may take.
+const queryTimeout = 2 * time.Minute
+
+// Timeline pins are limited in size; the watch can't show much more than this anyway.
+const maxPinBodyLength = 500
+
+var widgetRegex = regexp.MustCompile(`<<!!WIDGET:.+?!!>>`)
+
+// Start runs due queries through the /query endpoint at queryURL, e.g. "ws://127.0.0.1:8080/query", until the context
+// is cancelled. Going through the endpoint means scheduled queries get exactly what the user would have.
+func Start(ctx context.Context, rd *redis.Client, queryURL string) {
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runDue(ctx, rd, queryURL)
+			}
+		}
+	}()
+}
+
+func runDue(ctx context.Context, rd *redis.Client, queryURL string) {
+	due, err := claimDue(ctx, rd, time.Now())
+	if err != nil {
+		requestid.Logf(ctx, "Checking for scheduled queries failed: %v", err)
+	}
+	for _, q := range due {
+		// Answers to scheduled questions are never urgent, so they wait for the user's quiet hours to end, and are
+		// asked then so that they're up to date.
+		if until, ok := deferral(ctx, rd, q); ok {
+			if err := deferUntil(ctx, rd, q, until); err != nil {
+				requestid.Logf(ctx, "Deferring scheduled query %s for user %d failed: %v", q.ID, q.UserID, err)
+			}
+			continue
+		}
+		go func(q Query) {
+			ctx, span := beeline.StartSpan(ctx, "schedule.run")
+			defer span.Send()
+			// Each run gets its own request ID, which the session it starts carries on with, so that its log lines
+			// can be found together.
+			ctx = requestid.WithID(ctx, requestid.New())
+			span.AddField("user_id", q.UserID)
+			if err := run(ctx, q, queryURL); err != nil {
+				span.AddField("error", err)
+				requestid.Logf(ctx, "Scheduled query %s for user %d failed: %v", q.ID, q.UserID, err)
+			}
+		}(q)
+	}
+}
+
+// deferral returns when the query should be asked instead of now, if the user is in their quiet hours.
+func deferral(ctx context.Context, rd *redis.Client, q Query) (time.Time, bool) {
+	prefs, err := preferences.Store{Redis: rd}.Get(ctx, q.UserID)
+	if err != nil {
+		// Better to disturb them than to lose the answer.
+		requestid.Logf(ctx, "Loading preferences for user %d failed: %v", q.UserID, err)
+		return time.Time{}, false
+	}
+	return prefs.DeferPush(time.Now(), q.TzOffset, "")
+}
+
+// run asks the query as if from a watch that can't perform any actions or show widgets, and pins the answer to the
+// user's timeline.
+func run(ctx context.Context, q Query, queryURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	params, err := url.ParseQuery(q.Params)
+	if err != nil {
+		return fmt.Errorf("bad params: %w", err)
+	}
+	params.Set("prompt", q.Prompt)
+	params.Set("actions", "")
+	params.Set("widgets", "")
+	params.Set(scheduledParam, runToken)
+	conn, _, err := websocket.Dial(ctx, queryURL+"?"+params.Encode(), &websocket.DialOptions{
+		HTTPHeader: http.Header{requestid.Header: {requestid.FromContext(ctx)}},
+	})
+	if err != nil {
+		return fmt.Errorf("connecting failed: %w", err)
+	}
+	defer conn.CloseNow()
+
+	var response strings.Builder
+	for {
+		_, message, err := conn.Read(ctx)
+		if err != nil {
+			var closeErr websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Code == websocket.StatusNormalClosure {
+				break
+			}
+			return fmt.Errorf("query ended abnormally: %w", err)
+		}
+		if len(message) == 0 {
+			continue
+		}
+		switch message[0] {
+		case 'c':
+			response.WriteString(widgetRegex.ReplaceAllString(string(message[1:]), ""))
+		case 'a':
+			// We said we can't do any actions, but answer anyway rather than leave the session waiting.
+			if err := conn.Write(ctx, websocket.MessageText, []byte(`{"error":"The watch isn't connected."}`)); err != nil {
+				return fmt.Errorf("responding to action failed: %w", err)
+			}
+		}
+	}
+	return pin(ctx, q, params.Get("token"), strings.TrimSpace(response.String()))
+}
+
+type timelinePin struct {
+	ID                 string          `json:"id"`
+	Time               string          `json:"time"`
+	Layout             timelineLayout  `json:"layout"`
+	CreateNotification *timelineLayout `json:"createNotification,omitempty"`
+}
+
+type timelineLayout struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	TinyIcon string `json:"tinyIcon"`
+}
+
+// pin puts the answer on the user's timeline, with a notification.
+func pin(ctx context.Context, q Query, timelineToken, answer string) error {
+	if timelineToken == "" {
+		return errors.New("no timeline token")
+	}
+	if len([]rune(answer)) > maxPinBodyLength {
+		answer = string([]rune(answer)[:maxPinBodyLength-1]) + "…"
+	}
+	layout := timelineLayout{
+		Type:     "genericPin",
+		Title:    q.Prompt,
+		Body:     answer,
+		TinyIcon: "system://images/GENERIC_CONFIRMATION",
+	}
+	notification := layout
+	notification.Type = "genericNotification"
+	now := time.Now().UTC()
+	pinID := fmt.Sprintf("bobby-%s-%d", q.ID, now.Unix())
+	body, err := json.Marshal(timelinePin{
+		ID:                 pinID,
+		Time:               now.Format(time.RFC3339),
+		Layout:             layout,
+		CreateNotification: &notification,
+	})
+	if err != nil {
+		return err
+	}
+	timelineURL := config.GetConfig().TimelineURL
+	if timelineURL == "" {
+		timelineURL = defaultTimelineURL
+	}
+	pinURL := strings.TrimSuffix(timelineURL, "/") + "/v1/user/pins/" + pinID
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, pinURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-Token", timelineToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pushing pin failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushing pin failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schedule stores the queries users have asked Bobby to run for them on a schedule, like "tell me the weather
+// every morning at 7", and runs them when they're due (see Start). Results are pushed to the watch as timeline pins.
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxPerUser is the most scheduled queries a user can have at once.
+const MaxPerUser = 5
+
+// dueKey is a sorted set of every scheduled query, as "<user ID>:<query ID>", scored by when it next runs.
+const dueKey = "scheduled_queries"
+
+// scheduledParam is the query parameter that marks a session as running a scheduled query. Its value must be
+// runToken.
+const scheduledParam = "scheduled"
+
+// runToken is what the runner passes as scheduledParam to show that it, and not a client, started the session. It's
+// made afresh whenever the service starts and never leaves the process, since the runner only connects to the service
+// it's part of.
+var runToken = newRunToken()
+
+// ErrTooMany is returned by Add when the user already has MaxPerUser scheduled queries.
+var ErrTooMany = errors.New("too many scheduled queries")
+
+// Weekdays are the names days are given as, indexed by time.Weekday.
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// Query is a query to run on a schedule.
+type Query struct {
+	ID     string `json:"id"`
+	UserID int    `json:"user_id"`
+	Prompt string `json:"prompt"`
+	// The local time of day to run at, as "15:04".
+	Time string `json:"time"`
+	// The days of the week to run on, from Weekdays. Empty means every day.
+	Days []string `json:"days,omitempty"`
+	// The user's offset from UTC, in minutes, when they scheduled it.
+	TzOffset int       `json:"tz_offset"`
+	NextRun  time.Time `json:"next_run"`
+	// The query parameters to run it with, as the watch sent them when it was scheduled. This includes the user's
+	// token, which is also their timeline token, so that the query is run as them, counts against their quota, and
+	// can be pinned to their timeline.
+	Params string `json:"params"`
+}
+
+// Requester is who's asking, for scheduling queries on their behalf.
+type Requester struct {
+	UserID int
+	// The parameters the session was started with.
+	Params url.Values
+}
+
+// IsScheduled reports whether the requester is itself a scheduled query, which can't schedule more.
+func (r Requester) IsScheduled() bool {
+	return subtle.ConstantTimeCompare([]byte(r.Params.Get(scheduledParam)), []byte(runToken)) == 1
+}
+
+func newRunToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("making scheduled query token failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+type contextKey struct{}
+
+// WithRequester returns a context that can schedule queries for the given requester.
+func WithRequester(ctx context.Context, r Requester) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// RequesterFromContext returns the requester in ctx, and whether there is one.
+func RequesterFromContext(ctx context.Context) (Requester, bool) {
+	r, ok := ctx.Value(contextKey{}).(Requester)
+	return r, ok
+}
+
+// New returns a query to run the prompt for the requester at a local time of day, on the given days or every day.
+func New(r Requester, prompt, timeOfDay string, days []string) (Query, error) {
+	if _, err := time.Parse("15:04", timeOfDay); err != nil {
+		return Query{}, fmt.Errorf("time must be given as HH:MM, not %q", timeOfDay)
+	}
+	for i, d := range days {
+		days[i] = strings.ToLower(d)
+		if !slices.Contains(Weekdays, days[i]) {
+			return Query{}, fmt.Errorf("unknown day %q", d)
+		}
+	}
+	params := url.Values{}
+	for k, v := range r.Params {
+		switch k {
+		// These are about the conversation it was scheduled in, not the one it'll run in.
+		case "prompt", "threadId", "quickAction", scheduledParam:
+			continue
+		}
+		params[k] = v
+	}
+	tzOffset, _ := strconv.Atoi(r.Params.Get("tzOffset"))
+	q := Query{
+		ID:       uuid.NewString()[:8],
+		UserID:   r.UserID,
+		Prompt:   prompt,
+		Time:     timeOfDay,
+		Days:     days,
+		TzOffset: tzOffset,
+		Params:   params.Encode(),
+	}
+	q.NextRun = q.After(time.Now())
+	return q, nil
+}
+
+// After returns the first time after t that the query should run.
+func (q Query) After(t time.Time) time.Time {
+	tod, _ := time.Parse("15:04", q.Time)
+	local := t.In(time.FixedZone("local", q.TzOffset*60))
+	next := time.Date(local.Year(), local.Month(), local.Day(), tod.Hour(), tod.Minute(), 0, 0, local.Location())
+	for i := 0; i < 8; i++ {
+		if next.After(t) && (len(q.Days) == 0 || slices.Contains(q.Days, Weekdays[next.Weekday()])) {
+			break
+		}
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.UTC()
+}
+
+func userKey(userID int) string {
+	return fmt.Sprintf("scheduled_queries:%d", userID)
+}
+
+func dueMember(userID int, id string) string {
+	return fmt.Sprintf("%d:%s", userID, id)
+}
+
+// Add stores a new scheduled query.
+func Add(ctx context.Context, rd *redis.Client, q Query) error {
+	ctx, span := beeline.StartSpan(ctx, "schedule.add")
+	defer span.Send()
+	count, err := rd.HLen(ctx, userKey(q.UserID)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return err
+	}
+	if count >= MaxPerUser {
+		return ErrTooMany
+	}
+	return save(ctx, rd, q)
+}
+
+func save(ctx context.Context, rd *redis.Client, q Query) error {
+	j, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	_, err = rd.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, userKey(q.UserID), q.ID, j)
+		pipe.ZAdd(ctx, dueKey, redis.Z{Score: float64(q.NextRun.Unix()), Member: dueMember(q.UserID, q.ID)})
+		return nil
+	})
+	return err
+}
+
+// List returns the user's scheduled queries, soonest first.
+func List(ctx context.Context, rd *redis.Client, userID int) ([]Query, error) {
+	ctx, span := beeline.StartSpan(ctx, "schedule.list")
+	defer span.Send()
+	entries, err := rd.HGetAll(ctx, userKey(userID)).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	var queries []Query
+	for _, e := range entries {
+		var q Query
+		if err := json.Unmarshal([]byte(e), &q); err != nil {
+			continue
+		}
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].NextRun.Before(queries[j].NextRun)
+	})
+	return queries, nil
+}
+
+// Cancel removes one of the user's scheduled queries, returning false if they don't have one with that ID.
+func Cancel(ctx context.Context, rd *redis.Client, userID int, id string) (bool, error) {
+	ctx, span := beeline.StartSpan(ctx, "schedule.cancel")
+	defer span.Send()
+	var deleted *redis.IntCmd
+	_, err := rd.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.HDel(ctx, userKey(userID), id)
+		pipe.ZRem(ctx, dueKey, dueMember(userID, id))
+		return nil
+	})
+	if err != nil {
+		span.AddField("error", err)
+		return false, err
+	}
+	return deleted.Val() > 0, nil
+}
+
+// deferUntil moves the query's next run to t, after which it carries on as usual. This only makes sense for times
+// before the run after next, which quiet hours always are.
+func deferUntil(ctx context.Context, rd *redis.Client, q Query, t time.Time) error {
+	q.NextRun = t
+	return save(ctx, rd, q)
+}
+
+// claimDue returns the queries that are due to run, and moves each on to its next run. Each query is only returned to
+// one caller, even if several servers are checking at once.
+func claimDue(ctx context.Context, rd *redis.Client, now time.Time) ([]Query, error) {
+	members, err := rd.ZRangeByScore(ctx, dueKey, &redis.ZRangeBy{Min: "-inf", Max: fmt.Sprint(now.Unix())}).Result()
+	if err != nil {
+		return nil, err
+	}
+	var due []Query
+	for _, m := range members {
+		// Whoever removes it from the set gets to run it.
+		if removed, err := rd.ZRem(ctx, dueKey, m).Result(); err != nil || removed == 0 {
+			continue
+		}
+		userID, id, ok := strings.Cut(m, ":")
+		if !ok {
+			continue
+		}
+		j, err := rd.HGet(ctx, "scheduled_queries:"+userID, id).Result()
+		if err != nil {
+			// It was cancelled.
+			continue
+		}
+		var q Query
+		if err := json.Unmarshal([]byte(j), &q); err != nil {
+			continue
+		}
+		due = append(due, q)
+		q.NextRun = q.After(now)
+		if err := save(ctx, rd, q); err != nil {
+			return due, err
+		}
+	}
+	return due, nil
+}
+
+// CancelAll removes all the user's scheduled queries.
+func CancelAll(ctx context.Context, rd *redis.Client, userID int) error {
+	ctx, span := beeline.StartSpan(ctx, "schedule.cancel_all")
+	defer span.Send()
+	queries, err := List(ctx, rd, userID)
+	if err != nil {
+		return err
+	}
+	_, err = rd.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, userKey(userID))
+		for _, q := range queries {
+			pipe.ZRem(ctx, dueKey, dueMember(userID, q.ID))
+		}
+		return nil
+	})
+	if err != nil {
+		span.AddField("error", err)
+	}
+	return err
+}
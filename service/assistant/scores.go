@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/widgets"
+)
+
+// handleScore returns the latest score for a sports widget, given the refresh token it came with. The token is all
+// the authorization needed, so this is cheap enough for the watch to poll. Expired tokens get a 404, which tells the
+// watch to stop asking.
+func (s *Service) handleScore(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := beeline.StartSpan(r.Context(), "handle_score")
+	defer span.Send()
+	update, err := widgets.RefreshScore(ctx, r.URL.Query().Get("token"))
+	if err != nil {
+		if errors.Is(err, widgets.ErrRefreshExpired) {
+			http.Error(rw, "Refresh token expired.", http.StatusNotFound)
+			return
+		}
+		span.AddField("error", err)
+		requestid.Logf(ctx, "Error refreshing score: %v", err)
+		http.Error(rw, "Couldn't get the latest score.", http.StatusBadGateway)
+		return
+	}
+	response, err := json.Marshal(update)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(response)
+}
@@ -19,35 +19,63 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/analytics"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/announcement"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/authz"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/localmodel"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/preferences"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/replay"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/safety"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/sessionlock"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/verifier"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/widgets"
-	"log"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/wire"
+	"iter"
+	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/functions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/game"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/glossary"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/keys"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/weather"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/api/iterator"
 	"google.golang.org/genai"
 	"nhooyr.io/websocket"
 )
 
+// chatModel is the Gemini model that holds the conversation.
+const chatModel = "models/gemini-2.0-flash"
+
 type PromptSession struct {
-	conn             *websocket.Conn
+	conn             *wire.Conn
 	prompt           string
 	userToken        string
 	query            url.Values
 	redis            *redis.Client
 	threadId         uuid.UUID
 	originalThreadId string
+	// The request ID of an earlier turn to start the conversation from, if any.
+	forkFrom string
+	// The quick action the watch asked for in place of a prompt, if any.
+	quickAction string
+	// Cuts off responses that run past the user's chosen verbosity.
+	limiter *lengthLimiter
 }
 
 type QueryContext struct {
@@ -58,10 +86,21 @@ func NewPromptSession(redisClient *redis.Client, rw http.ResponseWriter, r *http
 	prompt := r.URL.Query().Get("prompt")
 	userToken := r.URL.Query().Get("token")
 	originalThreadId := r.URL.Query().Get("threadId")
-	c, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+	// A continuation already has its context, so there's nothing to fork.
+	forkFrom := ""
+	if originalThreadId == "" {
+		forkFrom = r.URL.Query().Get("forkFrom")
+	}
+	opts := &websocket.AcceptOptions{
 		OriginPatterns:     []string{"null"},
 		InsecureSkipVerify: true,
-	})
+	}
+	if config.GetConfig().WebsocketCompression {
+		// Response chunks are small and repetitive, so they compress far better when they share a window than
+		// individually. Sessions are short, so the memory that costs isn't held for long.
+		opts.CompressionMode = websocket.CompressionContextTakeover
+	}
+	c, err := wire.Accept(rw, r, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -74,109 +113,397 @@ func NewPromptSession(redisClient *redis.Client, rw http.ResponseWriter, r *http
 		redis:            redisClient,
 		threadId:         uuid.New(),
 		originalThreadId: originalThreadId,
+		forkFrom:         forkFrom,
+		quickAction:      r.URL.Query().Get("quickAction"),
 	}, nil
 }
 
 func (ps *PromptSession) Run(ctx context.Context) {
+	ctx, live, untrack := trackSession(ctx)
+	defer untrack()
 	ctx = query.ContextWith(ctx, ps.query)
+	ctx = analytics.WithSession(ctx)
+	ctx = functions.WithCitations(ctx)
+	ctx = upstream.WithAttributions(ctx)
+	ctx = functions.WithCallMemo(ctx)
+	ctx = functions.WithCallLimits(ctx, config.GetConfig().MaxToolCallsPerTurn, config.GetConfig().MaxCallsPerFunction)
+	ctx = functions.WithPendingCalls(ctx)
+	ctx = weather.WithReportCache(ctx)
+	if query.SandboxFromContext(ctx) {
+		beeline.AddField(ctx, "sandbox", true)
+		ctx = functions.WithSandbox(ctx)
+	}
+	notice, err := announcement.Load(ctx, ps.redis)
+	if err != nil {
+		// Failing to tell users about an outage isn't worth causing one over.
+		requestid.Logf(ctx, "load announcement failed: %v\n", err)
+	}
+	if notice.Maintenance {
+		beeline.AddField(ctx, "maintenance", true)
+		message := notice.Message
+		if message == "" {
+			message = i18n.T(ctx, "session.maintenance")
+		}
+		if err := ps.conn.Write(ctx, websocket.MessageText, []byte("w"+message)); err != nil {
+			requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+		}
+		ps.closeWithError(ctx, websocket.StatusTryAgainLater, "session.error.maintenance")
+		return
+	}
+	// Announcements are shown at the start of each conversation, rather than after every follow-up. They're sent
+	// prefixed with "n" rather than as warnings, since they aren't about anything going wrong with this conversation.
+	if notice.Message != "" && ps.originalThreadId == "" && ps.forkFrom == "" {
+		if err := ps.conn.Write(ctx, websocket.MessageText, []byte("n"+notice.Message)); err != nil {
+			requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+		}
+	}
+	geminiKey := keys.Gemini.Next()
 	geminiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  config.GetConfig().GeminiKey,
-		Backend: genai.BackendGeminiAPI,
+		APIKey:     geminiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: requestid.Client,
 	})
 	if err != nil {
-		log.Printf("error creating Gemini client: %v\n", err)
-		_ = ps.conn.Close(websocket.StatusInternalError, "Error creating client.")
+		requestid.Logf(ctx, "error creating Gemini client: %v\n", err)
+		ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.client")
 		return
 	}
 
 	var messages []*genai.Content
-	messages = append(messages, &genai.Content{
+	userPrompt := &genai.Content{
 		Parts: []*genai.Part{{Text: ps.prompt}},
 		Role:  "user",
-	})
+	}
+	messages = append(messages, userPrompt)
 
+	var previousResults []functions.StoredResult
+	var previousGame *game.State
 	if ps.originalThreadId != "" {
 		oldMessages, err := ps.restoreThread(ctx, ps.originalThreadId)
 		if err != nil {
-			log.Printf("error restoring thread: %v\n", err)
-			_ = ps.conn.Close(websocket.StatusInternalError, "Error restoring thread.")
+			requestid.Logf(ctx, "error restoring thread: %v\n", err)
+			ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.restore_thread")
 			return
 		} else {
 			messages = append(oldMessages, messages...)
 		}
+		previousResults = ps.restoreThreadResults(ctx, ps.originalThreadId)
+		previousGame, err = game.Load(ctx, ps.redis, ps.originalThreadId)
+		if err != nil {
+			// Losing the score is a shame, but not worth ending the conversation over.
+			requestid.Logf(ctx, "error restoring game: %v\n", err)
+		}
 	}
+	ctx = functions.WithResultStore(ctx, previousResults)
+	ctx = game.WithSession(ctx, previousGame)
 	user, err := quota.GetUserInfo(ctx, ps.userToken)
 	if err != nil {
-		log.Printf("get user info failed: %v\n", err)
-		_ = ps.conn.Close(websocket.StatusInternalError, "get user info failed")
+		requestid.Logf(ctx, "get user info failed: %v\n", err)
+		ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.user_info")
 		return
 	}
 	beeline.AddField(ctx, "user_id", user.UserId)
 	if !user.HasSubscription {
 		beeline.AddField(ctx, "error", "no subscription")
-		log.Printf("user %d has no subscription\n", user.UserId)
-		_ = ps.conn.Close(websocket.StatusPolicyViolation, "You need an active Rebble subscription to use Bobby.")
+		requestid.Logf(ctx, "user %d has no subscription\n", user.UserId)
+		ps.closeWithError(ctx, websocket.StatusPolicyViolation, "session.error.no_subscription")
+		return
+	}
+	// This is taken before the preferences are applied, so that scheduled queries pick up any later changes to them.
+	requester := schedule.Requester{UserID: user.UserId, Params: maps.Clone(ps.query)}
+	ctx = schedule.WithRequester(ctx, requester)
+	// Scheduled queries run alongside whatever the user is doing, so they mustn't cancel it, or be cancelled by it.
+	if !requester.IsScheduled() {
+		lockedCtx, lock, err := sessionlock.Acquire(ctx, ps.redis, user.UserId)
+		if err != nil {
+			// Better to risk a duplicate session than to refuse this one.
+			requestid.Logf(ctx, "acquire session lock failed: %v\n", err)
+		} else {
+			ctx = lockedCtx
+			defer lock.Release(ctx)
+		}
+	}
+	prefs, err := preferences.Store{Redis: ps.redis}.Get(ctx, user.UserId)
+	if err != nil {
+		requestid.Logf(ctx, "load preferences failed: %v\n", err)
+		ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.user_info")
+		return
+	}
+	// The preferences are read once, here, and used for the rest of the session, so that a change made on the phone
+	// mid-conversation takes effect from the next query rather than halfway through this one.
+	prefs.ApplyTo(ps.query)
+	ctx = query.ContextWith(ctx, ps.query)
+	ps.limiter = newLengthLimiter(ctx)
+	// Quick actions are written out in the user's language, which isn't known until their preferences are loaded.
+	if ps.quickAction != "" && !ps.applyQuickAction(ctx, userPrompt) {
+		requestid.Logf(ctx, "unknown quick action %q\n", ps.quickAction)
+		ps.closeWithError(ctx, websocket.StatusPolicyViolation, "session.error.quick_action")
 		return
 	}
+	terms, err := glossary.List(ctx, ps.redis, user.UserId)
+	if err != nil {
+		// Without it some words may be misheard, but that's no reason to stop.
+		requestid.Logf(ctx, "load glossary failed: %v\n", err)
+	}
+	ctx = glossary.WithTerms(ctx, terms)
+	// Quick action prompts are written by us, so only dictated prompts need repairing.
+	if ps.quickAction == "" {
+		if repaired := glossary.NewRepairer(terms).Repair(ps.prompt); repaired != ps.prompt {
+			beeline.AddField(ctx, "glossary_repaired", true)
+			ps.prompt = repaired
+			userPrompt.Parts[0].Text = repaired
+		}
+	}
+	decision, err := authz.Load(ctx, authz.StaticSource{Policy: prefs.ToolPolicy()}, user.UserId)
+	if err != nil {
+		requestid.Logf(ctx, "load tool policy failed: %v\n", err)
+		ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.user_info")
+		return
+	}
+	ctx = authz.WithDecision(ctx, decision)
+	if query.DebugFromContext(ctx) && config.GetConfig().DebugCaptureRetention > 0 {
+		ctx = ps.startCapture(ctx, user.UserId)
+		defer ps.storeCapture(ctx)
+	}
+	if localmodel.ShouldUse() {
+		ctx = ps.goOffline(ctx)
+	}
+	ctx = functions.WithRegistry(ctx)
 	qt := quota.NewTracker(ps.redis, user.UserId)
 	used, remaining, err := qt.GetQuota(ctx)
 	if err != nil {
-		log.Printf("get quota failed: %v\n", err)
-		_ = ps.conn.Close(websocket.StatusInternalError, "Quota lookup failed.")
+		requestid.Logf(ctx, "get quota failed: %v\n", err)
+		ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.quota_lookup")
 		return
 	}
-	if remaining < 1 {
-		log.Printf("quota exceeded for user %d\n", user.UserId)
-		_ = ps.conn.Close(websocket.StatusPolicyViolation, "You have exceeded your quota for this month.")
+	if remaining < 1 && !ps.isStatusQuestion(ctx) {
+		requestid.Logf(ctx, "quota exceeded for user %d\n", user.UserId)
+		ps.closeWithError(ctx, websocket.StatusPolicyViolation, "session.error.quota_exceeded")
 		return
 	}
-	log.Printf("user %d has used %d / %d credits\n", user.UserId, used, remaining)
+	requestid.Logf(ctx, "user %d has used %d / %d credits\n", user.UserId, used, remaining)
+	// The forked context is sent to the model with every request this session makes, so it's charged to this session
+	// like any other input.
+	if ps.forkFrom != "" {
+		seed, err := ps.forkTurn(ctx, user.UserId, ps.forkFrom)
+		if err != nil {
+			requestid.Logf(ctx, "fork from %q failed: %v\n", ps.forkFrom, err)
+			if errors.Is(err, errNoSuchTurn) {
+				ps.closeWithError(ctx, websocket.StatusPolicyViolation, "session.error.fork")
+			} else {
+				ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.restore_thread")
+			}
+			return
+		}
+		beeline.AddField(ctx, "forked_from", ps.forkFrom)
+		beeline.AddField(ctx, "fork_messages", len(seed))
+		messages = append(seed, messages...)
+	}
+	analytics.Record(ctx, analytics.Event{Kind: analytics.EventSessionStarted, Language: i18n.LanguageFromContext(ctx), Success: true})
 	totalInputTokens := 0
+	totalCachedInputTokens := 0
 	totalOutputTokens := 0
-	iterations := 0
-	for {
+	transcript := persistence.Turn{Prompt: ps.prompt, ForkedFrom: ps.forkFrom}
+	groundingChecked := false
+	// In reprompt mode, answers that might be checked are held back until they pass, so that one that doesn't can be
+	// replaced before the user sees it. Only answers given after calling a function are checked.
+	reprompting := config.GetConfig().GroundingCheck == verifier.GroundingReprompt
+	calledFunction := false
+	var withheld [][]byte
+	var transcriptBeforeAnswer []persistence.TurnPart
+	// The figures the model's first answer got wrong, if it's being asked to answer again.
+	var groundingFigures []string
+	// Set when Gemini turns out to be unreachable partway through, so the turn can be tried again with the local model.
+	failover := false
+	// Set in JSON mode once the model has said it's ready to answer; see answerFunction.
+	answering := false
+	fastPath, fastPathed := ps.tryFastPath(ctx, qt, used)
+	if fastPathed {
+		messages = append(messages, fastPath.messages...)
+		appendToTranscript(&transcript, fastPath.reply)
+		if err := ps.conn.Write(ctx, websocket.MessageText, []byte("c"+fastPath.reply)); err != nil {
+			requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+		}
+	}
+	for !fastPathed {
 		cont, err := func() (bool, error) {
 			ctx, span := beeline.StartSpan(ctx, "chat_iteration")
 			defer span.Send()
-			iterations++
+			turnStart := time.Now()
+			// Each reply gets the whole allowance, so that whatever the model said before calling a function doesn't
+			// cut short the answer it gives with the result.
+			ps.limiter.reset()
 			var tools []*genai.Tool
-			if iterations <= 10 {
-				tools = []*genai.Tool{{FunctionDeclarations: functions.GetFunctionDefinitionsForCapabilities(query.SupportedActionsFromContext(ctx))}}
+			// Once the model has made all the calls it can, it isn't offered any more, so it has to answer.
+			if !functions.CallLimitsFromContext(ctx).Exhausted() {
+				tools = []*genai.Tool{{FunctionDeclarations: functions.GetFunctionDefinitionsForContext(ctx)}}
 			}
-			systemPrompt := ps.generateSystemPrompt(ctx)
-			streamCtx, streamSpan := beeline.StartSpan(ctx, "chat_stream")
+			jsonMode := jsonModeEnabled(ctx)
+			structured := jsonMode && (answering || tools == nil)
+			if structured {
+				tools = nil
+			} else if jsonMode {
+				tools[0].FunctionDeclarations = append(tools[0].FunctionDeclarations, answerFunctionDeclaration)
+			}
+			prefixSections := systemPromptPrefixSections(ctx)
+			sessionSections := ps.sessionPromptSections(ctx)
+			if groundingFigures != nil {
+				// This goes in the system prompt rather than the conversation so that it's only seen by the calls
+				// that need it, and isn't stored with the thread.
+				sessionSections = append(sessionSections, promptSection{"grounding", verifier.GroundingPrompt(groundingFigures)})
+			}
+			reportPromptSize(ctx, append(prefixSections, sessionSections...), tools)
+			promptPrefix := joinSections(prefixSections)
+			sessionPrompt := joinSections(sessionSections)
 			temperature := float64(0.5)
 			one := int64(1)
-			s := geminiClient.Models.GenerateContentStream(streamCtx, "models/gemini-2.0-flash", messages, &genai.GenerateContentConfig{
-				SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: systemPrompt}}},
-				Temperature:       &temperature,
-				CandidateCount:    &one,
-				Tools:             tools,
-			})
+			generateConfig := &genai.GenerateContentConfig{
+				Temperature:    &temperature,
+				CandidateCount: &one,
+				SafetySettings: safety.SettingsForLevel(safety.FilterLevelFromContext(ctx)),
+			}
+			if _, playing := game.FromContext(ctx).Current(); playing {
+				maxTokens := int64(game.MaxReplyTokens)
+				generateConfig.MaxOutputTokens = &maxTokens
+			}
+			capture := replay.FromContext(ctx)
+			if capture != nil {
+				generateConfig.Seed = &capture.Seed
+			}
+			contents := messages
+			cacheName := ""
+			// A captured turn has to be replayable without the cache, so it's called just as it would be without.
+			// JSON mode needs a tool config, which can't be combined with cached content either.
+			if tools != nil && !functions.IsOffline(ctx) && capture == nil && !jsonMode {
+				cacheName = sharedPromptCache.get(ctx, geminiClient, geminiKey, chatModel, promptPrefix, tools)
+			}
+			if cacheName != "" {
+				// Cached content can't be combined with a system instruction or tools, so the part of the prompt
+				// specific to this session goes ahead of the conversation instead.
+				generateConfig.CachedContent = cacheName
+				contents = append([]*genai.Content{{
+					Parts: []*genai.Part{{Text: sessionPrompt}},
+					Role:  "user",
+				}}, messages...)
+			} else {
+				generateConfig.SystemInstruction = &genai.Content{Parts: []*genai.Part{{Text: promptPrefix + sessionPrompt}}}
+				generateConfig.Tools = tools
+			}
+			if structured {
+				generateConfig.ResponseMIMEType = "application/json"
+				generateConfig.ResponseSchema = widgets.AnswerSchema(ctx)
+			} else if jsonMode {
+				generateConfig.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeAny}}
+			}
+			streamCtx, streamSpan := beeline.StartSpan(ctx, "chat_stream")
+			streamSpan.AddField("prompt_cache", cacheName != "")
+			var s iter.Seq2[*genai.GenerateContentResponse, error]
+			if functions.IsOffline(ctx) {
+				s = localmodel.GenerateContentStream(streamCtx, contents, generateConfig)
+			} else {
+				s = geminiClient.Models.GenerateContentStream(streamCtx, chatModel, contents, generateConfig)
+			}
+			// Writing to the watch through this means a slow watch pauses the stream, instead of every chunk piling up.
+			stream := newStreamWriter(streamCtx, ps.conn)
 			var functionCall *genai.FunctionCall
 			content := ""
 			var usageData *genai.GenerateContentResponseUsageMetadata
 			bufferedContent := ""
+			held := newBoundaryBuffer(ctx)
+			// streamFailed is whether writing to the watch has failed, after which there's no point trying again.
+			streamFailed := false
 			leftTrimming := false
+			var answer widgets.AnswerDecoder
+			// lastText is whether the last thing sent was text, so that the next text part needs a space before it.
+			lastText := false
+			holding := reprompting && calledFunction && !groundingChecked
+			if holding {
+				transcriptBeforeAnswer = slices.Clone(transcript.Response)
+			}
+			// send streams some of the response to the watch, a word at a time unless it has a widget in it, and
+			// reports whether it could.
+			send := func(streamContent string, splitting bool) bool {
+				// If the last thing we generated was a widget, it's possible the model will try to put some
+				// newlines or spaces in front of the next text. We don't want that, so strip it out.
+				if leftTrimming {
+					streamContent = strings.TrimLeft(streamContent, " \r\n\t")
+				}
+				if strings.TrimSpace(streamContent) == "" {
+					return true
+				}
+				appendToTranscript(&transcript, streamContent)
+				var words []string
+				if splitting {
+					words = strings.Split(streamContent, " ")
+					leftTrimming = false
+				} else {
+					words = []string{streamContent}
+				}
+				for i, w := range words {
+					if i != len(words)-1 {
+						w += " "
+					}
+					if holding {
+						withheld = append(withheld, []byte("c"+w))
+						continue
+					}
+					if err := stream.Write([]byte("c"+w), true); err != nil {
+						streamSpan.AddField("error", err)
+						requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+						streamFailed = true
+						return false
+					}
+				}
+				return true
+			}
 		read_loop:
 			for resp, err := range s {
 				if errors.Is(err, iterator.Done) {
 					break
 				}
+				if _, _, cancelled := cancellation(ctx); err != nil && cancelled {
+					_ = stream.Close(streamCtx)
+					ps.closeCancelled(ctx)
+					streamSpan.Send()
+					return false, err
+				}
 				if err != nil {
 					streamSpan.AddField("error", err)
-					log.Printf("recv from Google failed: %v\n", err)
+					requestid.Logf(ctx, "recv from Google failed: %v\n", err)
+					if !functions.IsOffline(ctx) && content == "" && localmodel.Enabled() && localmodel.IsUnreachable(err) {
+						// The internet's down, which isn't the key's fault. The user hasn't seen anything yet, so
+						// the local model can answer instead.
+						localmodel.MarkRemoteDown()
+						failover = true
+						_ = stream.Close(streamCtx)
+						streamSpan.Send()
+						return true, nil
+					}
+					keys.Gemini.ReportError(geminiKey, err)
+					if cacheName != "" {
+						// The cache may have been deleted or expired early; don't let the next session use it.
+						sharedPromptCache.forget(cacheName)
+					}
 					// This comes up when Google is over capacity, which does happen sometimes.
 					// There's nothing we can really do here, though we could blame them instead of ourselves.
-					_ = ps.conn.Close(websocket.StatusInternalError, "Bobby is unavailable right now. Please try again in a few moments.")
+					_ = stream.Close(streamCtx)
+					ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.unavailable")
 					streamSpan.Send()
 					return false, err
 				}
+				live.touch()
 				usageData = resp.UsageMetadata
 				if len(resp.Candidates) == 0 {
 					continue
 				}
 				choice := resp.Candidates[0]
+				if choice.FinishReason == genai.FinishReasonSafety {
+					streamSpan.AddField("safety_blocked", true)
+					requestid.Logf(ctx, "response blocked by safety filter\n")
+				}
+				if choice.Content == nil {
+					continue
+				}
 				ourContent := ""
 				for _, c := range choice.Content.Parts {
 					if c.Text != "" {
@@ -187,6 +514,30 @@ func (ps *PromptSession) Run(ctx context.Context) {
 						functionCall = &fc
 					}
 				}
+				if structured {
+					content += ourContent
+					for _, part := range answer.Write(ourContent) {
+						sent := true
+						if part.Widget != nil {
+							processed, err := widgets.ProcessStructured(ctx, *part.Widget)
+							replacement, isWidget := ps.widgetReplacement(ctx, part.Widget.Type, processed, err)
+							sent = send(replacement, !isWidget)
+							leftTrimming = leftTrimming || isWidget
+							lastText = false
+						} else if part.Text != "" {
+							text := ps.postProcessResponse(ctx, part.Text)
+							if lastText && !strings.HasSuffix(text, "\n") {
+								text = " " + text
+							}
+							sent = send(text, true)
+							lastText = true
+						}
+						if !sent {
+							break read_loop
+						}
+					}
+					continue
+				}
 				if bufferedContent != "" {
 					bufferedContent += ourContent
 					closers := strings.Count(bufferedContent, "!>") + strings.Count(bufferedContent, "/>")
@@ -203,75 +554,64 @@ func (ps *PromptSession) Run(ctx context.Context) {
 						continue
 					}
 				}
-				if strings.TrimSpace(ourContent) != "" {
-					streamContent := ourContent
-					re := regexp.MustCompile(`(?s)\s*<!.+?[!/]>\s*`)
-					widget := re.FindAllString(ourContent, -1)
+				if ready := held.add(ourContent); strings.TrimSpace(ready) != "" {
+					streamContent := ps.postProcessResponse(ctx, ready)
+					widget := widgetTagSpanRegex.FindAllString(streamContent, -1)
 					splitting := true
-					if len(widget) > 0 {
-						for _, w := range widget {
-							processed, err := widgets.ProcessWidget(ctx, w)
-							replacement := ""
-							if err != nil {
-								log.Printf("process widget failed: %v\n", err)
-								replacement = "(widget processing failed)"
-							} else {
-								jsoned, err := json.Marshal(processed)
-								if err != nil {
-									log.Printf("marshal widget failed: %v\n", err)
-									replacement = "(widget processing failed)"
-								} else {
-									splitting = false
-									replacement = "<<!!WIDGET:" + string(jsoned) + "!!>>"
-								}
-							}
-							streamContent = strings.Replace(streamContent, w, replacement, 1)
-							if strings.HasSuffix(streamContent, "!!>>") {
-								leftTrimming = true
-							}
-						}
-					}
-					// If the last thing we generated was a widget, it's possible the model will try to put some
-					// newlines or spaces in front of the next text. We don't want that, so strip it out.
-					if leftTrimming {
-						streamContent = strings.TrimLeft(streamContent, " \r\n\t")
-					}
-					if strings.TrimSpace(streamContent) != "" {
-						var words []string
-						if splitting {
-							words = strings.Split(streamContent, " ")
-							leftTrimming = false
-						} else {
-							words = []string{streamContent}
+					for _, w := range widget {
+						processed, err := widgets.ProcessWidget(ctx, w)
+						replacement, isWidget := ps.widgetReplacement(ctx, widgetName(w), processed, err)
+						if isWidget {
+							splitting = false
 						}
-						for i, w := range words {
-							if i != len(words)-1 {
-								w += " "
-							}
-							if err := ps.conn.Write(streamCtx, websocket.MessageText, []byte("c"+w)); err != nil {
-								streamSpan.AddField("error", err)
-								log.Printf("write to websocket failed: %v\n", err)
-								break read_loop
-							}
-							time.Sleep(time.Millisecond * 40)
+						streamContent = strings.Replace(streamContent, w, replacement, 1)
+						if strings.HasSuffix(streamContent, "!!>>") {
+							leftTrimming = true
 						}
 					}
+					if !send(streamContent, splitting) {
+						break read_loop
+					}
 				}
 				content += ourContent
 			}
+			// Whatever was held back had no widgets in it, since their ends count as boundaries.
+			if rest := held.flush(); !streamFailed && strings.TrimSpace(rest) != "" {
+				send(ps.postProcessResponse(ctx, rest), true)
+			}
+			if err := stream.Close(streamCtx); err != nil {
+				streamSpan.AddField("error", err)
+				requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+			}
+			if structured {
+				if err := answer.Close(); err != nil {
+					streamSpan.AddField("answer_error", err)
+					requestid.Logf(ctx, "structured answer was incomplete: %v\n", err)
+				}
+			}
 			streamSpan.Send()
+			capture.Add(replay.Call{Contents: contents, Config: generateConfig, Response: content, FunctionCall: functionCall})
+			analytics.Record(ctx, analytics.Event{
+				Kind:      analytics.EventTurn,
+				Language:  i18n.LanguageFromContext(ctx),
+				LatencyMs: time.Since(turnStart).Milliseconds(),
+				Success:   true,
+			})
 			if usageData != nil {
+				if usageData.CachedContentTokenCount != nil {
+					totalCachedInputTokens += int(*usageData.CachedContentTokenCount)
+				}
 				if usageData.PromptTokenCount != nil {
 					_, err = qt.ChargeOutputQuota(ctx, int(*usageData.PromptTokenCount))
 					if err != nil {
-						log.Printf("charge output quota failed: %v\n", err)
+						requestid.Logf(ctx, "charge output quota failed: %v\n", err)
 					}
 					totalInputTokens += int(*usageData.PromptTokenCount)
 				}
 				if usageData.CandidatesTokenCount != nil {
 					_, err = qt.ChargeInputQuota(ctx, int(*usageData.CandidatesTokenCount))
 					if err != nil {
-						log.Printf("charge input quota failed: %v\n", err)
+						requestid.Logf(ctx, "charge input quota failed: %v\n", err)
 					}
 					totalOutputTokens += int(*usageData.CandidatesTokenCount)
 				}
@@ -283,32 +623,63 @@ func (ps *PromptSession) Run(ctx context.Context) {
 				})
 			}
 			if functionCall != nil {
+				// It isn't the answer after all.
+				ps.release(ctx, withheld)
+				withheld = nil
+			}
+			if functionCall != nil && functionCall.Name == answerFunction {
+				// In JSON mode, this is the model saying it's ready to answer, so ask it for its answer.
+				answering = true
+				return true, nil
+			}
+			if functionCall != nil {
+				calledFunction = true
 				messages = append(messages, &genai.Content{
 					Role: "model",
 					Parts: []*genai.Part{
 						{FunctionCall: functionCall},
 					},
 				})
-				log.Printf("calling function %s\n", functionCall.Name)
+				requestid.Logf(ctx, "calling function %s\n", functionCall.Name)
 				fnBytes, _ := json.Marshal(functionCall.Args)
 				fnArgs := string(fnBytes)
-				if err := ps.conn.Write(ctx, websocket.MessageText, []byte("f"+functions.SummariseFunction(functionCall.Name, fnArgs))); err != nil {
-					log.Printf("write to websocket failed: %v\n", err)
+				if err := ps.conn.Write(ctx, websocket.MessageText, []byte("f"+functions.SummariseFunction(ctx, functionCall.Name, fnArgs))); err != nil {
+					requestid.Logf(ctx, "write to websocket failed: %v\n", err)
 					return false, err
 				}
+				fnCtx := functions.WithProgressReporter(ctx, func(event functions.ProgressEvent, detail string) {
+					ps.sendProgress(ctx, functionCall.Name, event, detail)
+				})
+				functions.ReportProgress(fnCtx, functions.ProgressStarted, "")
+				callStart := time.Now()
 				var result string
 				var err error
-				if functions.IsAction(functionCall.Name) {
-					result, err = functions.CallAction(ctx, qt, functionCall.Name, fnArgs, ps.conn)
+				if functions.IsAction(fnCtx, functionCall.Name) {
+					result, err = functions.CallAction(fnCtx, qt, functionCall.Name, fnArgs, ps.conn)
 				} else {
-					result, err = functions.CallFunction(ctx, qt, functionCall.Name, fnArgs)
+					result, err = functions.CallFunction(fnCtx, qt, functionCall.Name, fnArgs)
 				}
 				if err != nil {
-					log.Printf("call function failed: %v\n", err)
+					requestid.Logf(ctx, "call function failed: %v\n", err)
 					result = "failed to call function: " + err.Error()
 				}
 				var mapResult map[string]any
 				_ = json.Unmarshal([]byte(result), &mapResult)
+				_, failed := mapResult["error"]
+				failed = failed || err != nil
+				if failed {
+					functions.ReportProgress(fnCtx, functions.ProgressFailed, "")
+				} else if mapResult["pending"] == true {
+					functions.ReportProgress(fnCtx, functions.ProgressContinuing, "")
+				} else {
+					functions.ReportProgress(fnCtx, functions.ProgressFinished, "")
+				}
+				analytics.Record(ctx, analytics.Event{
+					Kind:      analytics.EventToolUsed,
+					Tool:      functionCall.Name,
+					LatencyMs: time.Since(callStart).Milliseconds(),
+					Success:   !failed,
+				})
 				messages = append(messages, &genai.Content{
 					Role: "function",
 					Parts: []*genai.Part{
@@ -325,61 +696,307 @@ func (ps *PromptSession) Run(ctx context.Context) {
 		if err != nil {
 			return
 		}
+		if failover {
+			failover = false
+			requestid.Logln(ctx, "Gemini is unreachable, answering with the local model")
+			ctx = functions.WithRegistry(ps.goOffline(ctx))
+			continue
+		}
+		live.setConversation(messages)
 		if !cont {
-			log.Println("Stopping")
+			if pending := functions.PendingCallsFromContext(ctx); pending.Len() > 0 {
+				// The model has told the user it's still working on something; now it can answer properly.
+				ps.release(ctx, withheld)
+				withheld = nil
+				if ps.awaitPendingCalls(ctx, pending, &messages) {
+					requestid.Logln(ctx, "Answering with the results of calls that ran over budget")
+					continue
+				}
+			}
+			if !groundingChecked {
+				groundingChecked = true
+				if figures := ps.checkGrounding(ctx, messages); len(figures) > 0 && withheld != nil {
+					// Nobody's seen this answer, so it can be dropped and replaced with one that's been checked.
+					if last := messages[len(messages)-1]; last.Role == "model" {
+						messages = messages[:len(messages)-1]
+					}
+					transcript.Response = transcriptBeforeAnswer
+					withheld = nil
+					groundingFigures = figures
+					requestid.Logln(ctx, "Asking the model to check its figures")
+					continue
+				}
+			}
+			ps.release(ctx, withheld)
+			withheld = nil
+			requestid.Logln(ctx, "Stopping")
 			break
 		}
-		log.Println("Going around again")
+		requestid.Logln(ctx, "Going around again")
 	}
 
-	lies, err := verifier.FindLies(ctx, qt, messages)
-	if err != nil {
-		// Bobby doesn't usually lie, so this isn't worth killing the session over.
-		log.Printf("find lies failed: %v\n", err)
+	if _, _, cancelled := cancellation(ctx); cancelled {
+		ps.closeCancelled(ctx)
+		return
+	}
+
+	// Suggestions don't depend on anything that follows, so they're worked out while the answer is checked.
+	var suggestionsReady <-chan []string
+	if !fastPathed && suggestionsEnabled(ctx) {
+		suggestionsReady = ps.startSuggestions(ctx, qt, transcript)
+	}
+
+	var lies []string
+	// The fast path's replies are templated, so they can't claim anything it didn't do. Offline, there's no way to check.
+	if !fastPathed && !functions.IsOffline(ctx) {
+		lies, err = verifier.FindLies(ctx, qt, messages)
+		if err != nil {
+			// Bobby doesn't usually lie, so this isn't worth killing the session over.
+			requestid.Logf(ctx, "find lies failed: %v\n", err)
+		}
 	}
 	if len(lies) > 0 {
 		beeline.AddField(ctx, "lies", lies)
-		log.Printf("lies detected: %v\n", lies)
+		requestid.Logf(ctx, "lies detected: %v\n", lies)
 		var formattedLies []string
 		for _, l := range lies {
 			switch l {
-			case "alarm":
-				formattedLies = append(formattedLies, "set an alarm")
-			case "timer":
-				formattedLies = append(formattedLies, "set a timer")
-			case "reminder":
-				formattedLies = append(formattedLies, "set a reminder")
+			case "alarm", "timer", "reminder":
+				formattedLies = append(formattedLies, i18n.T(ctx, "session.lie."+l))
 			}
 		}
-		prettyLies := strings.Join(formattedLies, ", ")
+		separator := i18n.T(ctx, "list.separator")
+		prettyLies := strings.Join(formattedLies, separator)
 		if len(formattedLies) > 1 {
-			prettyLies = strings.Join(formattedLies[:len(formattedLies)-1], ", ") + ", or " + formattedLies[len(formattedLies)-1]
+			prettyLies = i18n.T(ctx, "list.or", strings.Join(formattedLies[:len(formattedLies)-1], separator), formattedLies[len(formattedLies)-1])
 		}
-		message := "Bobby did not, in fact, " + prettyLies + "."
+		message := i18n.T(ctx, "session.lie", prettyLies)
 		if err := ps.conn.Write(ctx, websocket.MessageText, []byte("w"+message)); err != nil {
-			log.Printf("write to websocket failed: %v\n", err)
+			requestid.Logf(ctx, "write to websocket failed: %v\n", err)
 		}
 	}
 
+	transcript.Citations = functions.CitationsFromContext(ctx)
+	transcript.Attributions = upstream.AttributionsFromContext(ctx)
+	if len(transcript.Citations) > 0 || len(transcript.Attributions) > 0 {
+		ps.sendCitations(ctx, transcript.Citations, transcript.Attributions)
+	}
+
 	if err := ps.conn.Write(ctx, websocket.MessageText, []byte("d")); err != nil {
-		log.Printf("write to websocket failed: %v\n", err)
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
 	}
 
 	beeline.AddField(ctx, "total_input_tokens", totalInputTokens)
+	beeline.AddField(ctx, "total_cached_input_tokens", totalCachedInputTokens)
 	beeline.AddField(ctx, "total_output_tokens", totalOutputTokens)
 	beeline.AddField(ctx, "total_cost", totalInputTokens*quota.InputTokenCredits+totalOutputTokens*quota.OutputTokenCredits)
 	if err := ps.storeThread(ctx, messages); err != nil {
-		log.Printf("store thread failed: %v\n", err)
-		_ = ps.conn.Close(websocket.StatusInternalError, "store thread failed")
+		requestid.Logf(ctx, "store thread failed: %v\n", err)
+		ps.closeWithError(ctx, websocket.StatusInternalError, "session.error.store_thread")
 		return
 	}
 	if err := ps.conn.Write(ctx, websocket.MessageText, []byte("t"+ps.threadId.String())); err != nil {
-		log.Printf("store thread ID failed: %s\n", err)
+		requestid.Logf(ctx, "store thread ID failed: %s\n", err)
+	}
+	if suggestionsReady != nil {
+		ps.sendSuggestions(ctx, suggestionsReady)
+	}
+	transcript.ThreadID = ps.threadId.String()
+	transcript.RequestID = requestid.FromContext(ctx)
+	transcript.Time = time.Now().UTC()
+	if err := persistence.AppendTurn(ctx, ps.redis, user.UserId, transcript); err != nil {
+		// The history is a convenience, so there's no need to bother the user about it.
+		requestid.Logf(ctx, "store transcript failed: %v\n", err)
 	}
-	log.Println("Request handled successfully.")
+	requestid.Logln(ctx, "Request handled successfully.")
 	_ = ps.conn.Close(websocket.StatusNormalClosure, "")
 }
 
+// goOffline switches the session to answering with the local model, with only the functions that work without the
+// internet, and tells the user why answers may be worse than usual. The session's Registry needs rebuilding after.
+func (ps *PromptSession) goOffline(ctx context.Context) context.Context {
+	beeline.AddField(ctx, "offline", true)
+	replay.FromContext(ctx).SetOffline()
+	if err := ps.conn.Write(ctx, websocket.MessageText, []byte("w"+i18n.T(ctx, "session.offline"))); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+	}
+	return functions.WithOffline(ctx)
+}
+
+// maxCloseReasonLength is the longest close reason a websocket close frame can carry.
+const maxCloseReasonLength = 123
+
+// closeWithError closes the connection with the localized error message, followed by the request ID so that a user
+// reporting the error can tell us which request it was.
+func (ps *PromptSession) closeWithError(ctx context.Context, code websocket.StatusCode, key string) {
+	message := i18n.T(ctx, key)
+	ref := requestid.Reference(ctx)
+	if len(message)+len(ref) > maxCloseReasonLength {
+		// A close reason that's too long makes the close fail altogether, so it's better to lose the reference.
+		ref = ""
+	}
+	_ = ps.conn.Close(code, message+ref)
+}
+
+// closeCancelled ends a session that's been cancelled on purpose, because the user started another or the reaper
+// stopped it. There's nothing worth saving from it.
+func (ps *PromptSession) closeCancelled(ctx context.Context) {
+	code, key, _ := cancellation(ctx)
+	beeline.AddField(ctx, "cancelled", key)
+	requestid.Logf(ctx, "Session cancelled: %v\n", context.Cause(ctx))
+	ps.closeWithError(ctx, code, key)
+}
+
+// release sends the parts of an answer that were held back while it was checked.
+func (ps *PromptSession) release(ctx context.Context, withheld [][]byte) {
+	if len(withheld) == 0 {
+		return
+	}
+	stream := newStreamWriter(ctx, ps.conn)
+	for _, m := range withheld {
+		if err := stream.Write(m, true); err != nil {
+			break
+		}
+	}
+	if err := stream.Close(ctx); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+	}
+}
+
+// checkGrounding records any figures in the answer that don't appear in the turn's tool output, and returns those the
+// model should be asked to verify or hedge, if any.
+func (ps *PromptSession) checkGrounding(ctx context.Context, messages []*genai.Content) []string {
+	mode := config.GetConfig().GroundingCheck
+	if mode == verifier.GroundingOff {
+		return nil
+	}
+	figures := verifier.FindUngroundedFigures(messages)
+	if len(figures) == 0 {
+		return nil
+	}
+	beeline.AddField(ctx, "ungrounded_figures", figures)
+	requestid.Logf(ctx, "ungrounded figures: %v\n", figures)
+	if mode != verifier.GroundingReprompt {
+		return nil
+	}
+	return figures
+}
+
+// maxContinuationWait is how long to wait for calls carrying on in the background, after the model has told the user
+// it's still working on them.
+const maxContinuationWait = 45 * time.Second
+
+// awaitPendingCalls waits for the calls carrying on in the background, and adds their results to the conversation as
+// if they'd come back straight away, for the model to answer with. It returns false if none finished in time.
+func (ps *PromptSession) awaitPendingCalls(ctx context.Context, pending *functions.PendingCalls, messages *[]*genai.Content) bool {
+	ctx, span := beeline.StartSpan(ctx, "await_pending_calls")
+	defer span.Send()
+	if err := ps.conn.Write(ctx, websocket.MessageText, []byte("f"+i18n.T(ctx, "thought.continuing"))); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+	}
+	finished, abandoned := pending.Wait(ctx, maxContinuationWait)
+	span.AddField("finished_calls", len(finished))
+	span.AddField("abandoned_calls", abandoned)
+	for _, c := range finished {
+		var args, result map[string]any
+		_ = json.Unmarshal([]byte(functions.FixupBrokenJson(c.Args)), &args)
+		_ = json.Unmarshal([]byte(c.Result), &result)
+		if _, failed := result["error"]; failed {
+			ps.sendProgress(ctx, c.Name, functions.ProgressFailed, "")
+		} else {
+			ps.sendProgress(ctx, c.Name, functions.ProgressFinished, "")
+		}
+		*messages = append(*messages,
+			&genai.Content{Role: "model", Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{Name: c.Name, Args: args}}}},
+			&genai.Content{Role: "function", Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{Name: c.Name, Response: result}}}},
+		)
+	}
+	if abandoned > 0 {
+		requestid.Logf(ctx, "gave up waiting for %d calls\n", abandoned)
+		if err := ps.conn.Write(ctx, websocket.MessageText, []byte("w"+i18n.T(ctx, "session.continuation_timed_out"))); err != nil {
+			requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+		}
+	}
+	return len(finished) > 0
+}
+
+// widgetTagSpanRegex matches a widget tag in the model's output along with the whitespace around it, which goes when
+// the tag is replaced.
+var widgetTagSpanRegex = regexp.MustCompile(`(?s)\s*<!.+?[!/]>\s*`)
+
+// widgetReplacement returns what to send the watch in place of a widget the model asked for: the widget itself, if the
+// watch can show it, or failing that some text. isWidget is false if it's text.
+func (ps *PromptSession) widgetReplacement(ctx context.Context, name string, processed any, err error) (replacement string, isWidget bool) {
+	analytics.Record(ctx, analytics.Event{Kind: analytics.EventWidgetShown, Widget: name, Success: err == nil})
+	if err != nil {
+		requestid.Logf(ctx, "process widget failed: %v\n", err)
+		return i18n.T(ctx, "session.widget_failed"), false
+	}
+	if wd, ok := processed.(widgets.Widget); ok && !query.SupportsWidget(ctx, wd.Capability()) {
+		// This watch is too old to show this kind of widget, so show it as text instead.
+		return widgets.FallbackText(ctx, wd) + "\n", false
+	}
+	jsoned, err := widgets.Marshal(processed)
+	if wd, ok := processed.(widgets.Widget); ok && errors.Is(err, widgets.ErrWidgetTooLarge) {
+		requestid.Logf(ctx, "widget too large to send, showing it as text: %v\n", err)
+		return widgets.FallbackText(ctx, wd) + "\n", false
+	}
+	if err != nil {
+		requestid.Logf(ctx, "marshal widget failed: %v\n", err)
+		return i18n.T(ctx, "session.widget_failed"), false
+	}
+	return "<<!!WIDGET:" + string(jsoned) + "!!>>", true
+}
+
+var widgetNameRegex = regexp.MustCompile(`<!\s*([A-Za-z-]+)`)
+
+// widgetName extracts just the widget type from a widget tag, e.g. "WEATHER-CURRENT", so we can record which widgets
+// are used without recording their content.
+func widgetName(tag string) string {
+	m := widgetNameRegex.FindStringSubmatch(tag)
+	if m == nil {
+		return "unknown"
+	}
+	return m[1]
+}
+
+// citationsMessage is sent to the client, prefixed with "s", before the response is finished. The watch has nowhere to
+// show it, but the phone app keeps it for the history view.
+type citationsMessage struct {
+	Citations []functions.Citation `json:"citations"`
+	// The credits required by the data providers used for the response.
+	Attributions []upstream.Attribution `json:"attributions,omitempty"`
+}
+
+func (ps *PromptSession) sendCitations(ctx context.Context, citations []functions.Citation, attributions []upstream.Attribution) {
+	j, err := json.Marshal(citationsMessage{Citations: citations, Attributions: attributions})
+	if err != nil {
+		requestid.Logf(ctx, "marshal citations failed: %v\n", err)
+		return
+	}
+	if err := ps.conn.Write(ctx, websocket.MessageText, append([]byte("s"), j...)); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+	}
+}
+
+// progressMessage is sent to the client, prefixed with "p", whenever a tool call changes state.
+type progressMessage struct {
+	Event    functions.ProgressEvent `json:"event"`
+	Function string                  `json:"function"`
+	Detail   string                  `json:"detail,omitempty"`
+}
+
+func (ps *PromptSession) sendProgress(ctx context.Context, fn string, event functions.ProgressEvent, detail string) {
+	j, err := json.Marshal(progressMessage{Event: event, Function: fn, Detail: detail})
+	if err != nil {
+		requestid.Logf(ctx, "marshal progress message failed: %v\n", err)
+		return
+	}
+	if err := ps.conn.Write(ctx, websocket.MessageText, append([]byte("p"), j...)); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+	}
+}
+
 func (ps *PromptSession) storeThread(ctx context.Context, messages []*genai.Content) error {
 	ctx, span := beeline.StartSpan(ctx, "store_thread")
 	defer span.Send()
@@ -414,9 +1031,43 @@ func (ps *PromptSession) storeThread(ctx context.Context, messages []*genai.Cont
 		return err
 	}
 	ps.redis.Set(ctx, "thread:"+ps.threadId.String(), j, 10*time.Minute)
+	if results := functions.FreshResultsFromContext(ctx); len(results) > 0 {
+		j, err := json.Marshal(results)
+		if err != nil {
+			span.AddField("error", err)
+			return err
+		}
+		ps.redis.Set(ctx, "thread_results:"+ps.threadId.String(), j, 10*time.Minute)
+	}
+	if err := game.Store(ctx, ps.redis, ps.threadId.String()); err != nil {
+		// As when restoring it, a lost game isn't worth failing the whole turn for.
+		span.AddField("game_error", err)
+		requestid.Logf(ctx, "store game failed: %v\n", err)
+	}
 	return nil
 }
 
+// restoreThreadResults returns the function results stored with an earlier thread. They're only an optimisation, so
+// if they can't be loaded the conversation carries on without them.
+func (ps *PromptSession) restoreThreadResults(ctx context.Context, oldThreadId string) []functions.StoredResult {
+	ctx, span := beeline.StartSpan(ctx, "restore_thread_results")
+	defer span.Send()
+	j, err := ps.redis.Get(ctx, "thread_results:"+oldThreadId).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			span.AddField("error", err)
+		}
+		return nil
+	}
+	var results []functions.StoredResult
+	if err := json.Unmarshal(j, &results); err != nil {
+		span.AddField("error", err)
+		return nil
+	}
+	span.AddField("result_count", len(results))
+	return results
+}
+
 func (ps *PromptSession) restoreThread(ctx context.Context, oldThreadId string) ([]*genai.Content, error) {
 	ctx, span := beeline.StartSpan(ctx, "restore_thread")
 	defer span.Send()
@@ -0,0 +1,242 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessionlock makes sure each user has only one session generating a response at a time, so that a watch
+// retrying a request it thinks has failed can't have both attempts spend the user's quota. The newest session always
+// wins: starting one cancels any the user already has running, on any server.
+package sessionlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// lockTTL is how long a lock lasts if its session never releases it, which is far longer than any session.
+const lockTTL = 10 * time.Minute
+
+// checkInterval is how often a session checks it still holds its lock, in case it missed being told it didn't.
+const checkInterval = 5 * time.Second
+
+// ErrSuperseded is the cause of a session's context being cancelled because the user started another.
+var ErrSuperseded = errors.New("superseded by a newer session")
+
+// releaseScript deletes the lock only if it's still held by the session releasing it.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock is a session's hold on its user's lock.
+type Lock struct {
+	rd     *redis.Client
+	userID int
+	id     string
+	// Where the subscriber passes on the IDs of sessions that take over the lock.
+	takeovers chan string
+	cancel    context.CancelCauseFunc
+}
+
+func lockKey(userID int) string {
+	return fmt.Sprintf("session_lock:%d", userID)
+}
+
+func takeoverChannel(userID int) string {
+	return fmt.Sprintf("session_takeover:%d", userID)
+}
+
+// Acquire takes the user's lock for a new session, cancelling any session that already holds it. The returned context
+// is cancelled with ErrSuperseded if a newer session takes over in turn. Release must be called once the session ends.
+func Acquire(ctx context.Context, rd *redis.Client, userID int) (context.Context, *Lock, error) {
+	ctx, span := beeline.StartSpan(ctx, "sessionlock.acquire")
+	defer span.Send()
+	l := &Lock{rd: rd, userID: userID, id: uuid.NewString(), takeovers: make(chan string, 1)}
+	// Subscribe before announcing ourselves, so that a session starting right after us can't be missed.
+	sub := subscriberFor(rd)
+	if err := sub.add(ctx, takeoverChannel(userID), l); err != nil {
+		span.AddField("error", err)
+		return nil, nil, err
+	}
+	previous, err := rd.SetArgs(ctx, lockKey(userID), l.id, redis.SetArgs{TTL: lockTTL, Get: true}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.AddField("error", err)
+		sub.remove(takeoverChannel(userID), l)
+		return nil, nil, err
+	}
+	if previous != "" {
+		span.AddField("took_over", true)
+		if err := rd.Publish(ctx, takeoverChannel(userID), l.id).Err(); err != nil {
+			// The old session will notice when it next checks the lock.
+			requestid.Logf(ctx, "announcing session takeover failed: %v\n", err)
+		}
+	}
+	ctx, l.cancel = context.WithCancelCause(ctx)
+	go l.watch(ctx)
+	return ctx, l, nil
+}
+
+// watch cancels the session if another takes over its lock.
+func (l *Lock) watch(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-l.takeovers:
+			if id != l.id {
+				l.cancel(ErrSuperseded)
+				return
+			}
+		case <-ticker.C:
+			holder, err := l.rd.Get(ctx, lockKey(l.userID)).Result()
+			if err == nil && holder != l.id {
+				l.cancel(ErrSuperseded)
+				return
+			}
+		}
+	}
+}
+
+// Release gives up the lock, if it's still held, and stops watching for takeovers. It's safe to call on a nil Lock.
+func (l *Lock) Release(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	l.cancel(nil)
+	subscriberFor(l.rd).remove(takeoverChannel(l.userID), l)
+	// The session's own context may have been cancelled by now, but the lock should still go.
+	ctx = context.WithoutCancel(ctx)
+	if err := releaseScript.Run(ctx, l.rd, []string{lockKey(l.userID)}, l.id).Err(); err != nil {
+		requestid.Logf(ctx, "releasing session lock failed: %v\n", err)
+	}
+}
+
+// subscriber listens for takeovers on behalf of every session on this server, over a single connection, and passes
+// each one on to the sessions of the user it's about. Giving each session a subscription of its own would take a
+// connection per session.
+type subscriber struct {
+	mu  sync.Mutex
+	sub *redis.PubSub
+	// The locks held on this server, by the channel their takeovers are announced on.
+	locks map[string]map[*Lock]bool
+	// Closed when Redis confirms the subscription to a channel.
+	ready map[string]chan struct{}
+}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   = map[*redis.Client]*subscriber{}
+)
+
+// subscriberFor returns the subscriber for rd, starting it if it isn't running yet.
+func subscriberFor(rd *redis.Client) *subscriber {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	s, ok := subscribers[rd]
+	if !ok {
+		s = &subscriber{sub: rd.Subscribe(context.Background()), locks: map[string]map[*Lock]bool{}, ready: map[string]chan struct{}{}}
+		subscribers[rd] = s
+		go s.dispatch(s.sub.ChannelWithSubscriptions())
+	}
+	return s
+}
+
+// dispatch passes each takeover on to the locks on its channel, and lets add know when it's subscribed.
+func (s *subscriber) dispatch(messages <-chan any) {
+	for m := range messages {
+		switch m := m.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			s.mu.Lock()
+			if ready, ok := s.ready[m.Channel]; ok {
+				close(ready)
+				delete(s.ready, m.Channel)
+			}
+			s.mu.Unlock()
+		case *redis.Message:
+			s.mu.Lock()
+			for l := range s.locks[m.Channel] {
+				select {
+				case l.takeovers <- m.Payload:
+				default:
+					// The lock already has a takeover to deal with, which will end its session anyway.
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// add starts passing takeovers on the channel to l, returning once Redis has confirmed the subscription.
+func (s *subscriber) add(ctx context.Context, channel string, l *Lock) error {
+	s.mu.Lock()
+	locks, ok := s.locks[channel]
+	if !ok {
+		if err := s.sub.Subscribe(ctx, channel); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		locks = map[*Lock]bool{}
+		s.locks[channel] = locks
+		s.ready[channel] = make(chan struct{})
+	}
+	locks[l] = true
+	ready, waiting := s.ready[channel]
+	s.mu.Unlock()
+	if !waiting {
+		return nil
+	}
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.remove(channel, l)
+		return ctx.Err()
+	}
+}
+
+// remove stops passing takeovers on the channel to l, unsubscribing from it if nothing else is listening.
+func (s *subscriber) remove(channel string, l *Lock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	locks := s.locks[channel]
+	delete(locks, l)
+	if len(locks) > 0 {
+		return
+	}
+	delete(s.locks, channel)
+	delete(s.ready, channel)
+	if err := s.sub.Unsubscribe(context.Background(), channel); err != nil {
+		log.Printf("unsubscribing from %s failed: %v\n", channel, err)
+	}
+}
+
+// Superseded reports whether ctx was cancelled because the user started a newer session.
+func Superseded(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrSuperseded)
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAcquireSupersedes(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+
+	first, firstLock, err := Acquire(ctx, rd, 1)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	other, otherLock, err := Acquire(ctx, rd, 2)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	second, secondLock, err := Acquire(ctx, rd, 1)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	// Every session shares the one subscription, however many users they're for.
+	if got := mr.PubSubNumSub(takeoverChannel(1), takeoverChannel(2)); got[takeoverChannel(1)] != 1 || got[takeoverChannel(2)] != 1 {
+		t.Errorf("subscribers = %v, want one for each user", got)
+	}
+
+	select {
+	case <-first.Done():
+		if !Superseded(first) {
+			t.Errorf("the first session was cancelled with %v, want ErrSuperseded", context.Cause(first))
+		}
+	case <-time.After(checkInterval / 2):
+		t.Fatal("the first session wasn't cancelled when the second took over")
+	}
+	if second.Err() != nil || other.Err() != nil {
+		t.Errorf("the second session's context is %v and the other user's is %v, want neither cancelled", second.Err(), other.Err())
+	}
+
+	firstLock.Release(ctx)
+	if holder, _ := mr.Get(lockKey(1)); holder != secondLock.id {
+		t.Errorf("after the first session released its lock, it's held by %q, want the second session", holder)
+	}
+	secondLock.Release(ctx)
+	otherLock.Release(ctx)
+	if mr.Exists(lockKey(1)) || mr.Exists(lockKey(2)) {
+		t.Errorf("keys left after releasing every lock: %q", mr.Keys())
+	}
+	// Unsubscribing happens in the background, on the subscriber's connection.
+	deadline := time.Now().Add(time.Second)
+	for len(mr.PubSubChannels("")) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if channels := mr.PubSubChannels(""); len(channels) > 0 {
+		t.Errorf("still subscribed to %q after releasing every lock", channels)
+	}
+}
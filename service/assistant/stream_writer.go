@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/wire"
+	"nhooyr.io/websocket"
+)
+
+// maxStreamBufferBytes is how much of a response can be waiting to go to the watch before we stop reading from the
+// model. Gemini usually produces text far faster than the watch can take it, so without a limit a long answer would
+// pile up in memory; with it, the model's stream just waits for the watch to catch up.
+const maxStreamBufferBytes = 8 * 1024
+
+// chunkDelay is how long to leave after each paced chunk, so that the watch isn't flooded with tiny messages.
+const chunkDelay = 40 * time.Millisecond
+
+type streamChunk struct {
+	data  []byte
+	paced bool
+}
+
+// streamWriter sends a response to the watch from its own goroutine, so that reading from the model isn't held up by
+// each write, until maxStreamBufferBytes are waiting. Chunks are always sent in order, and never dropped.
+type streamWriter struct {
+	conn *wire.Conn
+	done chan struct{}
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// Chunks waiting to be sent. The first is the one being sent, if any.
+	queue  []streamChunk
+	queued int
+	closed bool
+	err    error
+
+	peakBytes int
+	stalls    int
+	stalled   time.Duration
+}
+
+func newStreamWriter(ctx context.Context, conn *wire.Conn) *streamWriter {
+	w := &streamWriter{conn: conn, done: make(chan struct{})}
+	w.cond = sync.NewCond(&w.mu)
+	go w.run(ctx)
+	return w
+}
+
+func (w *streamWriter) run(ctx context.Context) {
+	defer close(w.done)
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 {
+			w.mu.Unlock()
+			return
+		}
+		chunk := w.queue[0]
+		w.mu.Unlock()
+
+		err := w.conn.Write(ctx, websocket.MessageText, chunk.data)
+		if err == nil && chunk.paced {
+			time.Sleep(chunkDelay)
+		}
+
+		w.mu.Lock()
+		w.queue = w.queue[1:]
+		w.queued -= len(chunk.data)
+		if err != nil {
+			// Nothing after a failed write would make sense to the watch, so give up on the rest.
+			w.err = err
+			w.queue = nil
+			w.queued = 0
+		}
+		w.cond.Broadcast()
+		w.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Write queues a message to send to the watch, first waiting for room in the buffer if it's full. If paced, the watch
+// is given a moment after it before the next message. It returns the error from any earlier write that failed.
+func (w *streamWriter) Write(data []byte, paced bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// A chunk bigger than the whole buffer still has to be sent, so it only waits for the buffer to empty.
+	if w.err == nil && w.queued > 0 && w.queued+len(data) > maxStreamBufferBytes {
+		w.stalls++
+		start := time.Now()
+		for w.err == nil && w.queued > 0 && w.queued+len(data) > maxStreamBufferBytes {
+			w.cond.Wait()
+		}
+		w.stalled += time.Since(start)
+	}
+	if w.err != nil {
+		return w.err
+	}
+	w.queue = append(w.queue, streamChunk{data: data, paced: paced})
+	w.queued += len(data)
+	w.peakBytes = max(w.peakBytes, w.queued)
+	w.cond.Broadcast()
+	return nil
+}
+
+// Close waits for everything queued to be sent, and records how full the buffer got on the span in ctx. It returns
+// the error from the first write that failed, if any. Nothing else should be written to the connection until it's done.
+func (w *streamWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	beeline.AddField(ctx, "stream_buffer_peak_bytes", w.peakBytes)
+	beeline.AddField(ctx, "stream_buffer_stalls", w.stalls)
+	beeline.AddField(ctx, "stream_buffer_stalled_ms", w.stalled.Milliseconds())
+	return w.err
+}
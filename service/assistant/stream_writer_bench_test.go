@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"nhooyr.io/websocket"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/wire"
+)
+
+// benchmarkChunks is a response as Gemini streams it: a few words at a time, with a widget part way through.
+var benchmarkChunks = strings.SplitAfter(strings.Repeat("It's going to be a mild day in London, with sunshine this "+
+	"morning and clouds later on. ", 6)+"<!WEATHER-SINGLE-DAY location=[London] units=[metric] day=[Tuesday]!> "+
+	strings.Repeat("Take an umbrella if you're out this evening, as showers are likely after six. ", 4), ". ")
+
+// BenchmarkStreamChunks covers what happens to each chunk of model output before it's written: post-processing, and
+// finding the widgets in it.
+func BenchmarkStreamChunks(b *testing.B) {
+	for _, verbosity := range []string{"normal", "brief"} {
+		b.Run(verbosity, func(b *testing.B) {
+			ctx := query.ContextWith(context.Background(), url.Values{"verbosity": {verbosity}})
+			b.ReportAllocs()
+			for range b.N {
+				ps := &PromptSession{limiter: newLengthLimiter(ctx)}
+				for _, chunk := range benchmarkChunks {
+					content := ps.postProcessResponse(ctx, chunk)
+					_ = widgetTagSpanRegex.FindAllString(content, -1)
+					_ = strings.Split(content, " ")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkStreamWriter sends a response a word at a time over a real websocket, without pacing.
+func BenchmarkStreamWriter(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(rw, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		for {
+			if _, _, err := conn.Read(r.Context()); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	var words [][]byte
+	for _, chunk := range benchmarkChunks {
+		for _, w := range strings.SplitAfter(chunk, " ") {
+			words = append(words, []byte("c"+w))
+		}
+	}
+	b.ReportAllocs()
+	for range b.N {
+		stream := newStreamWriter(ctx, wire.Wrap(conn))
+		for _, w := range words {
+			if err := stream.Write(w, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := stream.Close(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
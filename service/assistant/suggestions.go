@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"nhooyr.io/websocket"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/functions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/suggestions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// suggestionsTimeout is how long the session will hold on for suggestions once the answer is finished. They're a
+// nicety, so it's not worth keeping the user's phone waiting much longer.
+const suggestionsTimeout = 3 * time.Second
+
+// suggestionsEnabled reports whether the session in ctx should be sent suggested follow-ups. Only apps from
+// SuggestionMinAppVersion on are, since older ones have nowhere to show them. They're not offered offline, where
+// there's no model to write them, or to children, since they don't go through the content filter.
+func suggestionsEnabled(ctx context.Context) bool {
+	minVersion := config.GetConfig().SuggestionMinAppVersion
+	return minVersion != "" && !functions.IsOffline(ctx) && !query.IsKidMode(ctx) && query.AppVersionAtLeast(ctx, minVersion)
+}
+
+// startSuggestions starts coming up with follow-ups to the turn in the background. The channel it returns gets them
+// once they're ready, or nothing if there aren't any.
+func (ps *PromptSession) startSuggestions(ctx context.Context, qt *quota.Tracker, turn persistence.Turn) <-chan []string {
+	ready := make(chan []string, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, suggestionsTimeout)
+		defer cancel()
+		followUps, err := suggestions.Generate(ctx, qt, turn.Prompt, describeResponse(turn.Response))
+		if err != nil {
+			requestid.Logf(ctx, "generate suggestions failed: %v\n", err)
+		}
+		ready <- followUps
+	}()
+	return ready
+}
+
+// describeResponse writes out a turn's response as text for the suggestions model, with its widgets as JSON.
+func describeResponse(parts []persistence.TurnPart) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		if p.Widget != nil {
+			sb.WriteString("[widget: " + string(p.Widget) + "]")
+			continue
+		}
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
+
+// suggestionsMessage is sent to the client, prefixed with "q", after the thread ID. The watch offers the suggestions
+// as quick replies.
+type suggestionsMessage struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+func (ps *PromptSession) sendSuggestions(ctx context.Context, ready <-chan []string) {
+	followUps := <-ready
+	if len(followUps) == 0 {
+		return
+	}
+	j, err := json.Marshal(suggestionsMessage{Suggestions: followUps})
+	if err != nil {
+		requestid.Logf(ctx, "marshal suggestions failed: %v\n", err)
+		return
+	}
+	if err := ps.conn.Write(ctx, websocket.MessageText, append([]byte("q"), j...)); err != nil {
+		requestid.Logf(ctx, "write to websocket failed: %v\n", err)
+	}
+}
@@ -18,19 +18,25 @@ import (
 	"context"
 	"github.com/honeycombio/beeline-go"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
-	"log"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/pebble-dev/bobby-assistant/service/assistant/functions"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/game"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/glossary"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/safety"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/widgets"
 )
 
 func (ps *PromptSession) generateTimeSentence(ctx context.Context) string {
 	tzOffset := ps.query.Get("tzOffset")
 	tzOffsetInt, err := strconv.Atoi(tzOffset)
 	if err != nil {
-		log.Printf("Failed to parse tzOffset: %v", err)
+		requestid.Logf(ctx, "Failed to parse tzOffset: %v", err)
 		return ""
 	}
 	// tzOffset is in minutes, but Go wants seconds.
@@ -38,7 +44,7 @@ func (ps *PromptSession) generateTimeSentence(ctx context.Context) string {
 	return "The user's local time is " + now.Format("Mon, 2 Jan 2006 15:04:05-07:00") + ". "
 }
 
-func generateLanguageSentence(ctx context.Context) string {
+func (ps *PromptSession) generateLanguageSentence(ctx context.Context) string {
 	sentence := ""
 	var units = query.PreferredUnitsFromContext(ctx)
 	unitMap := map[string]string{
@@ -55,7 +61,15 @@ func generateLanguageSentence(ctx context.Context) string {
 	}
 	sentence += "Format numbers with commas and/or periods as appropriate for the user's language. "
 	var language = util.GetLanguageName(query.PreferredLanguageFromContext(ctx))
-	if language != "" {
+	// Bilingual users may switch languages mid-conversation, in which case we follow their lead for this turn
+	// rather than sticking rigidly to their preference.
+	detected := util.GetLanguageName(util.DetectLanguage(ps.prompt))
+	if detected != "" {
+		beeline.AddField(ctx, "detected_language", detected)
+	}
+	if detected != "" && language != "" && detected != language {
+		sentence += "The user usually prefers " + language + ", but their latest message is in " + detected + ", so respond in " + detected + ". "
+	} else if language != "" {
 		sentence += "Respond in " + language + ". "
 	} else {
 		sentence += "Respond in the language the user is using, unless they specify otherwise."
@@ -66,8 +80,9 @@ func generateLanguageSentence(ctx context.Context) string {
 func (ps *PromptSession) getPlaceFromLocation(ctx context.Context) (string, error) {
 	// Use the Photon API to turn the user's longitude and latitude into a place name.
 	// We don't want anything more specific than their town name, so we filter at that level.
-	// We will return just a region or country if there isn't a nearby place.
-	location := query.LocationFromContext(ctx)
+	// We will return just a region or country if there isn't a nearby place. Since we only want the town, there's no
+	// need to tell Photon exactly where the user is.
+	location := query.CoarseLocationFromContext(ctx)
 	feature, err := photon.ReverseGeocode(ctx, location.Lon, location.Lat)
 	if err != nil {
 		return "", err
@@ -87,9 +102,13 @@ func generateWidgetSentence(ctx context.Context) string {
 			location_value = "here|place name"
 		}
 		sentence += "<!WEATHER-CURRENT location=[" + location_value + "] units=[metric|imperial|uk hybrid]!>: embeds a weather widget showing the weather right now in the given location\n" +
-			"<!WEATHER-SINGLE-DAY location=[" + location_value + "] units=[metric|imperial|uk hybrid] day=[the name of a weekday, like Tuesday]!>: embeds a weather widget summarising the weather in the given location for a single day within the coming week.\n" +
+			"<!WEATHER-SINGLE-DAY location=[" + location_value + "] units=[metric|imperial|uk hybrid] day=[today|tomorrow|a weekday, like Tuesday|a date, like 2025-06-14]!>: embeds a weather widget summarising the weather in the given location for a single day within the next two weeks.\n" +
 			"<!WEATHER-MULTI-DAY location=[" + location_value + "] units=[metric|imperial|uk hybrid]!>: embeds a weather widget summarising the weather in the given location for the next three days\n" +
 			"Before including a weather widget, you *must* still look up the weather, and include a textual response after the widget. Always call get_weather first, then put the widget before any other text. "
+		if widgets.SupportsWeatherDetails(ctx) {
+			sentence += "WEATHER-CURRENT can also take a details attribute listing extra details to show, from " + strings.Join(widgets.WeatherDetails, ", ") + ", e.g. <!WEATHER-CURRENT location=[London] units=[metric] details=[humidity,uv]!>. " +
+				"Only add details the user asked about, or that matter for what they're asking, like uv when they ask about sunburn. "
+		}
 		if has_location {
 			sentence += "If showing the weather for the user's current location, always use 'here' instead of a place name. "
 		}
@@ -99,6 +118,19 @@ func generateWidgetSentence(ctx context.Context) string {
 		sentence += "<!TIMER targetTime=[time in ISO 8601 format] name=[name of the timer]!>: embeds a timer widget counting down to the given time. If the timer doesn't have a name, the `name` field can be omitted\n" +
 			"If a user asks to see a timer, and the timer exists, you should *always* include that timer as a widget at the beginning of your response. Before including a timer widget, you *must* call get_timers first to verify when the timer is set for. Use the TIMER widget *only* when showing the user how long is left on their timer, not when setting one. \n\n"
 	}
+	if query.SupportsWidget(ctx, "sports") {
+		sentence += "<!SPORTS-FIXTURE event=[event ID]!>: embeds a widget showing a sports match, with its score if it has started. The score keeps itself up to date while the match is on. " +
+			"Before including a sports widget, you *must* call get_sports_fixtures to find the event's ID. Use it when the user asks about a specific match, like the score of a game in progress or when their team next plays, and put it at the start of your response.\n\n"
+	}
+	if query.SupportsWidget(ctx, "pronunciation") {
+		sentence += "<!PRONUNCIATION word=[word]!>: embeds a widget showing how to say an English word, broken into syllables. " +
+			"Use it at the start of your response when the user asks how to pronounce a word or how many syllables it has. It looks the word up itself, so you don't need to call define_word first just for this.\n\n"
+	}
+	if query.SupportsWidget(ctx, "page") {
+		sentence += "<!PAGE document=[document ID] page=[page number]!>: embeds a widget showing one page of a long piece of writing, which the user can page through on their watch. " +
+			"When the user asks for something too long to show at once, like a bedtime story, first call write_pages to store it, then respond with just a short introduction followed by this widget for page 1. Don't repeat the pages' text yourself. " +
+			"If the user asks to carry on with something they were reading, call resume_reading and show the next page it gives you.\n\n"
+	}
 	if query.SupportsWidget(ctx, "number") {
 		sentence += "<!NUMERIC-ANSWER number=[number] unit=[unit]!>: If the primary response to a question is a single number, optionally with a unit (e.g. 'pounds', 'm/s', 'people') or without (e.g. the answer to some arithmetic), you *should* use this widget at the start of your response to highlight the answer. If there is no further clarification after the widget, **do not** provide any text output. **Never** include words (like 'million') in the number - you can put them in the unit (e.g. number '340.1', unit 'million people'). If no unit is necessary, leave it blank. For this widget only, format the number for human readability.\n" +
 			"If using a NUMERIC-ANSWER widget, *always* put it at the *start* of the response. **NEVER**, UNDER ANY CIRCUMSTANCES, put a number widget after any text.\n"
@@ -106,36 +138,153 @@ func generateWidgetSentence(ctx context.Context) string {
 	return sentence
 }
 
-func (ps *PromptSession) generateSystemPrompt(ctx context.Context) string {
-	ctx, span := beeline.StartSpan(ctx, "generate_system_prompt")
+func generateContentFilterSentence(ctx context.Context) string {
+	switch safety.FilterLevelFromContext(ctx) {
+	case safety.FilterStrict:
+		return "The user has asked for strict content filtering, and the watch may belong to a child. Keep every response family-friendly: " +
+			"never use profanity or crude language, avoid graphic descriptions of violence, and don't discuss sexual topics, drugs, or other mature themes. " +
+			"If asked about something inappropriate, politely decline and suggest asking a trusted adult. "
+	case safety.FilterOff:
+		return ""
+	default:
+		return "Avoid profanity unless the user explicitly asks for it. "
+	}
+}
+
+func generateKidModeSentence(ctx context.Context) string {
+	if !query.IsKidMode(ctx) {
+		return ""
+	}
+	return "You are talking to a child. Use simple, everyday words and short sentences that a young child can understand. " +
+		"Explain any difficult word you have to use. Be warm, patient, and encouraging. " +
+		"You cannot search for businesses or send messages for this user - if asked, say that a grown-up can help with that. "
+}
+
+func generateAccessibilitySentence(ctx context.Context) string {
+	if !query.AccessibilityModeFromContext(ctx) {
+		return ""
+	}
+	return "The user is using a screen reader, so your response will be read aloud. Use short, simple sentences. " +
+		"Never use tables, columns, or lists - write everything as ordinary sentences. " +
+		"Spell out abbreviations and units in full (e.g. 'kilometres per hour', not 'km/h'; 'degrees Celsius', not '°C'). "
+}
+
+func generateOfflineSentence(ctx context.Context) string {
+	if !functions.IsOffline(ctx) {
+		return ""
+	}
+	return "Bobby can't reach the internet right now, so you are a small model running on the user's local network. " +
+		"You can't look anything up: there's no weather, news, web search, maps, sports or exchange rates. " +
+		"If the user asks for something that needs them, say briefly that it isn't available until Bobby is back online. " +
+		"Answer other questions from what you know, keeping answers short, and say so if you aren't sure. "
+}
+
+func generateCallLimitSentence(ctx context.Context) string {
+	if !functions.CallLimitsFromContext(ctx).Exhausted() {
+		return ""
+	}
+	return "You have used all the function calls you can for this message, so you can't look anything else up. " +
+		"Answer now with the information you already have, and say briefly if you couldn't find everything. "
+}
+
+func generatePersonaSentence(ctx context.Context) string {
+	switch query.PersonaFromContext(ctx) {
+	case "concise":
+		return "The user prefers extremely short answers: give just the answer, with no pleasantries or follow-up offers. "
+	case "friendly":
+		return "Be warm and chatty, as though talking to a friend, while still keeping responses short. "
+	case "playful":
+		return "Be playful and light-hearted, with the occasional pun or joke, while still answering the question. "
+	case "formal":
+		return "Use a polite, formal register, and avoid slang and jokes. "
+	default:
+		return ""
+	}
+}
+
+func generateHomeSentence(ctx context.Context) string {
+	home := query.HomeFromContext(ctx)
+	if home == nil || home.Name == "" {
+		return ""
+	}
+	return "The user's home is " + home.Name + ". When the user refers to 'home', they mean this place. "
+}
+
+// staticSystemPrompt is the part of the system prompt that's the same for everyone.
+const staticSystemPrompt = "You are a helpful assistant in the style of phone voice assistants. " +
+	"Your name is Bobby, and you are running on a Pebble smartwatch. " +
+	"The text you receive is transcribed from voice input. " +
+	"Your knowledge cutoff is September 2024. However, you can use the wikipedia function to access the current content of specific Wikipedia pages. " +
+	"Do not try to use Wikipedia to answer 'how to' or 'how do I' type questions - Wikipedia does not contain instructions. Instead, try to answer using your general knowledge. " +
+	"When provided, always follow Wikipedia redirects immediately and silently. Never ask the user whether you should check wikipedia, or whether you should check the full article - if you would ask, assume that you should (but don't ever fetch full articles if you already have the answer to the question). Don't mention looking up articles or Wikipedia to the user. " +
+	"You may call multiple functions before responding to the user, if necessary. If executing a lua script fails, try hard to fix the script using the error message, and consider alternate approaches to solve the problem. " +
+	"If the user asks to set an alarm, assume they always want to set it for a time in the future. " +
+	"As a creative, intelligent, helpful, friendly assistant, you should always try to answer the user's question. You can and should provide creative suggestions and factual responses as appropriate. Always try your best to answer the user's question. " +
+	"**Never** claim to have taken an action (e.g. set a timer, alarm, or reminder) unless you have actually used a tool to do so. " +
+	"Alarms and reminders are not interchangable - *never* use alarms when a user asks for reminders, and never user reminders when the user asks for an alarm or timer. If a user asks to set a timer, always set a timer (using 'set_timer'), not a reminder. If the user asks about a specific timer, respond only about that one. " +
+	"If asked to perform language translation (e.g. 'what is X in french?'), *don't* look anything up - just respond immediately. You know how to do translations between any language pair. " +
+	"Your responses will be displayed on a very small screen, so be brief. Do not use markdown in your responses.\n"
+
+// promptSection is a named part of the system prompt. Naming them lets us see which parts are growing (see
+// reportPromptSize).
+type promptSection struct {
+	Name string
+	Text string
+}
+
+func joinSections(sections []promptSection) string {
+	var sb strings.Builder
+	for _, section := range sections {
+		sb.WriteString(section.Text)
+	}
+	return sb.String()
+}
+
+// systemPromptPrefixSections returns the part of the system prompt that's shared by every session on the same kind of
+// device, which makes it worth caching (see promptCache).
+func systemPromptPrefixSections(ctx context.Context) []promptSection {
+	return []promptSection{
+		{"static", staticSystemPrompt},
+		{"widgets", generateWidgetSentence(ctx)},
+		{"json_mode", generateJSONModeSentence(ctx)},
+	}
+}
+
+// sessionPromptSections returns the part of the system prompt that's specific to this session: who and where the
+// user is, and how they'd like to be answered.
+func (ps *PromptSession) sessionPromptSections(ctx context.Context) []promptSection {
+	ctx, span := beeline.StartSpan(ctx, "generate_session_prompt")
 	defer span.Send()
 	locationString := ""
 	location := query.LocationFromContext(ctx)
-	if location != nil {
+	if location != nil && !functions.IsOffline(ctx) {
+		// Finding the place needs the internet, so offline the model goes without.
 		if place, err := ps.getPlaceFromLocation(ctx); err == nil {
 			locationString = "The user is in " + place + ". "
+			if query.LocationIsCoarseFromContext(ctx) {
+				locationString += "They have only shared their approximate location, so you don't know exactly where they are in " + place + ". "
+			}
 		} else {
 			span.AddField("error", err)
-			log.Printf("Failed to get user location: %v", err)
+			requestid.Logf(ctx, "Failed to get user location: %v", err)
 		}
-	} else {
+	} else if location == nil {
 		locationString = "The user has not granted permission to access their location, but they could enable it on the settings page if needed. "
 	}
-	return "You are a helpful assistant in the style of phone voice assistants. " +
-		"Your name is Bobby, and you are running on a Pebble smartwatch. " +
-		"The text you receive is transcribed from voice input. " +
-		"Your knowledge cutoff is September 2024. However, you can use the wikipedia function to access the current content of specific Wikipedia pages. " +
-		"Do not try to use Wikipedia to answer 'how to' or 'how do I' type questions - Wikipedia does not contain instructions. Instead, try to answer using your general knowledge. " +
-		"When provided, always follow Wikipedia redirects immediately and silently. Never ask the user whether you should check wikipedia, or whether you should check the full article - if you would ask, assume that you should (but don't ever fetch full articles if you already have the answer to the question). Don't mention looking up articles or Wikipedia to the user. " +
-		"You may call multiple functions before responding to the user, if necessary. If executing a lua script fails, try hard to fix the script using the error message, and consider alternate approaches to solve the problem. " +
-		"If the user asks to set an alarm, assume they always want to set it for a time in the future. " +
-		"As a creative, intelligent, helpful, friendly assistant, you should always try to answer the user's question. You can and should provide creative suggestions and factual responses as appropriate. Always try your best to answer the user's question. " +
-		"**Never** claim to have taken an action (e.g. set a timer, alarm, or reminder) unless you have actually used a tool to do so. " +
-		"Alarms and reminders are not interchangable - *never* use alarms when a user asks for reminders, and never user reminders when the user asks for an alarm or timer. If a user asks to set a timer, always set a timer (using 'set_timer'), not a reminder. If the user asks about a specific timer, respond only about that one. " +
-		"If asked to perform language translation (e.g. 'what is X in french?'), *don't* look anything up - just respond immediately. You know how to do translations between any language pair. " +
-		"Your responses will be displayed on a very small screen, so be brief. Do not use markdown in your responses.\n" +
-		locationString +
-		ps.generateTimeSentence(ctx) +
-		generateWidgetSentence(ctx) +
-		generateLanguageSentence(ctx)
+	return []promptSection{
+		{"location", locationString},
+		{"home", generateHomeSentence(ctx)},
+		{"glossary", glossary.Prompt(ctx)},
+		{"time", ps.generateTimeSentence(ctx)},
+		{"kid_mode", generateKidModeSentence(ctx)},
+		{"accessibility", generateAccessibilitySentence(ctx)},
+		{"content_filter", generateContentFilterSentence(ctx)},
+		{"persona", generatePersonaSentence(ctx)},
+		{"verbosity", generateVerbositySentence(ctx)},
+		{"language", ps.generateLanguageSentence(ctx)},
+		{"fresh_results", functions.DescribeFreshResults(ctx)},
+		{"game", game.Prompt(ctx)},
+		{"offline", generateOfflineSentence(ctx)},
+		{"call_limit", generateCallLimitSentence(ctx)},
+	}
 }
@@ -0,0 +1,42 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+func BenchmarkSystemPrompt(b *testing.B) {
+	// There's no location, so that the benchmark doesn't depend on looking up where the user is.
+	q := url.Values{
+		"tzOffset": {"60"},
+		"lang":     {"en-GB"},
+		"units":    {"uk"},
+		"actions":  {"set_alarm,set_reminder"},
+		"widgets":  {"weather,timer,number,sports,pronunciation,page"},
+		"persona":  {"friendly"},
+	}
+	ctx := query.ContextWith(context.Background(), q)
+	ps := &PromptSession{query: q, prompt: "What's the weather like tomorrow?"}
+	b.ReportAllocs()
+	for range b.N {
+		_ = joinSections(systemPromptPrefixSections(ctx))
+		_ = joinSections(ps.sessionPromptSections(ctx))
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/persistence"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pins"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/quota"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+const defaultTranscriptTurns = 20
+
+var widgetMarkerRegex = regexp.MustCompile(`(?s)<<!!WIDGET:(.*?)!!>>`)
+
+// appendToTranscript adds content that was sent to the watch to the turn, splitting out any widgets into their own
+// parts.
+func appendToTranscript(turn *persistence.Turn, content string) {
+	addText := func(text string) {
+		if text == "" {
+			return
+		}
+		if n := len(turn.Response); n > 0 && turn.Response[n-1].Widget == nil {
+			turn.Response[n-1].Text += text
+			return
+		}
+		turn.Response = append(turn.Response, persistence.TurnPart{Text: text})
+	}
+	last := 0
+	for _, m := range widgetMarkerRegex.FindAllStringSubmatchIndex(content, -1) {
+		addText(content[last:m[0]])
+		turn.Response = append(turn.Response, persistence.TurnPart{Widget: json.RawMessage(content[m[2]:m[3]])})
+		last = m[1]
+	}
+	addText(content[last:])
+}
+
+// handleTranscript returns the user's most recent turns, for the phone app's history view. The number of turns can
+// be set with the "limit" parameter. A DELETE request clears the history.
+func (s *Service) handleTranscript(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		requestid.Logf(ctx, "No token provided.")
+		http.Error(rw, "No token provided.", http.StatusNotFound)
+		return
+	}
+	userInfo, err := quota.GetUserInfo(ctx, token)
+	if err != nil {
+		requestid.Logf(ctx, "Error getting user info: %v", err)
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodDelete:
+		if err := persistence.DeleteHistory(ctx, s.redis, userInfo.UserId); err != nil {
+			requestid.Logf(ctx, "Error deleting history: %v", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		rw.Header().Set("Allow", "GET, DELETE")
+		http.Error(rw, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := defaultTranscriptTurns
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 {
+			http.Error(rw, "Invalid limit.", http.StatusBadRequest)
+			return
+		}
+	}
+	limit = min(limit, persistence.MaxHistoryTurns)
+	turns, err := persistence.LoadHistory(ctx, s.redis, userInfo.UserId, limit)
+	if err != nil {
+		requestid.Logf(ctx, "Error loading history: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pinned, err := pins.IDs(ctx, s.redis, userInfo.UserId)
+	if err != nil {
+		// The history is still worth showing without its pins marked.
+		requestid.Logf(ctx, "Error loading pins: %v", err)
+	}
+	for i := range turns {
+		turns[i].Pinned = turns[i].RequestID != "" && pinned[turns[i].RequestID]
+	}
+	response, err := json.Marshal(map[string]any{
+		"turns": turns,
+	})
+	if err != nil {
+		requestid.Logf(ctx, "Error marshalling transcript: %v", err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(response)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessibility
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Abbreviations that can appear anywhere in the text.
+var phraseAbbreviations = map[string]string{
+	"e.g.":    "for example",
+	"i.e.":    "that is",
+	"etc.":    "and so on",
+	"approx.": "approximately",
+	"vs.":     "versus",
+	"w/o":     "without",
+	"aka":     "also known as",
+	"ASAP":    "as soon as possible",
+	"FYI":     "for your information",
+}
+
+// Units that only get expanded when they follow a number, since many of them are also ordinary words.
+var unitAbbreviations = map[string]string{
+	"°C":   "degrees Celsius",
+	"°F":   "degrees Fahrenheit",
+	"°":    "degrees",
+	"km/h": "kilometres per hour",
+	"kmh":  "kilometres per hour",
+	"m/s":  "metres per second",
+	"mph":  "miles per hour",
+	"km":   "kilometres",
+	"mi":   "miles",
+	"m":    "metres",
+	"cm":   "centimetres",
+	"mm":   "millimetres",
+	"ft":   "feet",
+	"kg":   "kilograms",
+	"g":    "grams",
+	"lb":   "pounds",
+	"lbs":  "pounds",
+	"oz":   "ounces",
+	"hr":   "hours",
+	"hrs":  "hours",
+	"min":  "minutes",
+	"mins": "minutes",
+	"sec":  "seconds",
+	"secs": "seconds",
+	"%":    "percent",
+}
+
+var phraseRegex = buildRegex(`(?:^|\b)(`, phraseAbbreviations, `)(?:\s|$|[,;:])`)
+var unitRegex = buildRegex(`(\d)\s?(`, unitAbbreviations, `)(?:\b|\s|$|[.,;:!?)])`)
+var tableSeparatorRegex = regexp.MustCompile(`(?m)^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$\n?`)
+var tableCellRegex = regexp.MustCompile(`\s*\|\s*`)
+var bulletRegex = regexp.MustCompile(`(?m)^\s*[-*•]\s+`)
+
+func buildRegex(prefix string, m map[string]string, suffix string) *regexp.Regexp {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, regexp.QuoteMeta(k))
+	}
+	// Longer alternatives must come first, so that e.g. "km/h" wins over "km".
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	return regexp.MustCompile(prefix + strings.Join(keys, "|") + suffix)
+}
+
+// ExpandAbbreviations replaces common abbreviations and unit symbols with the words they stand for, so that screen
+// readers don't spell them out letter by letter.
+func ExpandAbbreviations(text string) string {
+	text = phraseRegex.ReplaceAllStringFunc(text, func(match string) string {
+		sub := phraseRegex.FindStringSubmatch(match)
+		return strings.Replace(match, sub[1], phraseAbbreviations[sub[1]], 1)
+	})
+	text = unitRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return unitReplacement(match, unitRegex.FindStringSubmatch(match))
+	})
+	return text
+}
+
+// unitReplacement rebuilds a number-unit match with the unit spelled out, preserving whatever trailing punctuation
+// the match consumed.
+func unitReplacement(match string, sub []string) string {
+	rest := strings.TrimPrefix(match, sub[1])
+	rest = strings.TrimPrefix(rest, " ")
+	trailing := strings.TrimPrefix(rest, sub[2])
+	return sub[1] + " " + unitAbbreviations[sub[2]] + trailing
+}
+
+// Linearize flattens markdown-style tables and bullet lists into plain sentences, which read much better through a
+// screen reader than columns of values do.
+func Linearize(text string) string {
+	text = tableSeparatorRegex.ReplaceAllString(text, "")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.Count(line, "|") >= 2 {
+			cells := tableCellRegex.Split(strings.Trim(strings.TrimSpace(line), "|"), -1)
+			var kept []string
+			for _, c := range cells {
+				if c = strings.TrimSpace(c); c != "" {
+					kept = append(kept, c)
+				}
+			}
+			lines[i] = strings.Join(kept, ", ") + "."
+		}
+		lines[i] = strings.ReplaceAll(lines[i], "\t", ", ")
+	}
+	text = strings.Join(lines, "\n")
+	return bulletRegex.ReplaceAllString(text, "")
+}
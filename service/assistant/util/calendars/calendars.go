@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package calendars converts dates between the Gregorian calendar and the Hebrew and Islamic calendars, and knows
+// when the major religious holidays in each fall.
+//
+// The algorithms are those from Reingold and Dershowitz's Calendrical Calculations. Dates are handled as "fixed" day
+// numbers, counting from 1 on January 1st, 1 CE.
+package calendars
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar is one of the calendars we can convert to and from.
+type Calendar string
+
+const (
+	Hebrew  Calendar = "hebrew"
+	Islamic Calendar = "islamic"
+)
+
+// Date is a date in one of the non-Gregorian calendars.
+type Date struct {
+	Calendar Calendar
+	Year     int
+	Month    int
+	Day      int
+}
+
+// MonthName returns the English name of the date's month.
+func (d Date) MonthName() string {
+	switch d.Calendar {
+	case Hebrew:
+		return hebrewMonthName(d.Year, d.Month)
+	case Islamic:
+		if d.Month >= 1 && d.Month <= 12 {
+			return islamicMonths[d.Month-1]
+		}
+	}
+	return fmt.Sprintf("month %d", d.Month)
+}
+
+// String formats the date as, e.g., "15 Nisan 5785".
+func (d Date) String() string {
+	return fmt.Sprintf("%d %s %d", d.Day, d.MonthName(), d.Year)
+}
+
+// Holiday is a religious holiday falling on a particular Gregorian date.
+type Holiday struct {
+	Name string
+	Date time.Time
+	// The date in the holiday's own calendar.
+	CalendarDate Date
+}
+
+// unixEpochFixed is the fixed day number of January 1st, 1970.
+const unixEpochFixed = 719163
+
+func fixedFromTime(t time.Time) int {
+	y, m, d := t.Date()
+	days := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return int(days) + unixEpochFixed
+}
+
+func timeFromFixed(fixed int) time.Time {
+	return time.Unix(int64(fixed-unixEpochFixed)*86400, 0).UTC()
+}
+
+// FromGregorian returns the given Gregorian date in the given calendar. Only the date part of t is used.
+func FromGregorian(c Calendar, t time.Time) (Date, error) {
+	fixed := fixedFromTime(t)
+	switch c {
+	case Hebrew:
+		return hebrewFromFixed(fixed), nil
+	case Islamic:
+		return islamicFromFixed(fixed), nil
+	}
+	return Date{}, fmt.Errorf("unknown calendar %q", c)
+}
+
+// ToGregorian returns the Gregorian date, at midnight UTC, of the given date.
+func ToGregorian(d Date) (time.Time, error) {
+	switch d.Calendar {
+	case Hebrew:
+		if d.Month < 1 || d.Month > lastMonthOfHebrewYear(d.Year) || d.Day < 1 || d.Day > lastDayOfHebrewMonth(d.Year, d.Month) {
+			return time.Time{}, fmt.Errorf("%d-%d-%d isn't a valid Hebrew date", d.Year, d.Month, d.Day)
+		}
+		return timeFromFixed(fixedFromHebrew(d.Year, d.Month, d.Day)), nil
+	case Islamic:
+		if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 30 {
+			return time.Time{}, fmt.Errorf("%d-%d-%d isn't a valid Islamic date", d.Year, d.Month, d.Day)
+		}
+		return timeFromFixed(fixedFromIslamic(d.Year, d.Month, d.Day)), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown calendar %q", d.Calendar)
+}
+
+// HolidaysBetween returns the holidays in the given calendar falling between from and to, inclusive. Holidays that
+// last several days are listed once, on their first day, unless from falls part way through them.
+func HolidaysBetween(c Calendar, from, to time.Time) ([]Holiday, error) {
+	var holidaysOn func(fixed int) []string
+	switch c {
+	case Hebrew:
+		holidaysOn = hebrewHolidaysOn
+	case Islamic:
+		holidaysOn = islamicHolidaysOn
+	default:
+		return nil, fmt.Errorf("unknown calendar %q", c)
+	}
+	var result []Holiday
+	for fixed := fixedFromTime(from); fixed <= fixedFromTime(to); fixed++ {
+		for _, name := range holidaysOn(fixed) {
+			date, _ := FromGregorian(c, timeFromFixed(fixed))
+			result = append(result, Holiday{Name: name, Date: timeFromFixed(fixed), CalendarDate: date})
+		}
+	}
+	return result, nil
+}
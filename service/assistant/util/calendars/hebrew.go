@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calendars
+
+// hebrewEpoch is the fixed day number of 1 Tishrei, AM 1.
+const hebrewEpoch = -1373427
+
+// Hebrew months are numbered from Nisan, although the year starts in Tishrei, the seventh month. In leap years, Adar
+// is replaced by Adar I and Adar II.
+const (
+	nisan            = 1
+	iyyar            = 2
+	sivan            = 3
+	tammuz           = 4
+	av               = 5
+	elul             = 6
+	tishrei          = 7
+	heshvan          = 8
+	kislev           = 9
+	tevet            = 10
+	shevat           = 11
+	adar             = 12
+	adarII           = 13
+	hebrewMonthCount = 13
+)
+
+var hebrewMonths = [hebrewMonthCount]string{"Nisan", "Iyyar", "Sivan", "Tammuz", "Av", "Elul", "Tishrei", "Heshvan", "Kislev", "Tevet", "Shevat", "Adar", "Adar II"}
+
+func hebrewMonthName(year, month int) string {
+	if month < 1 || month > hebrewMonthCount {
+		return ""
+	}
+	if month == adar && hebrewLeapYear(year) {
+		return "Adar I"
+	}
+	return hebrewMonths[month-1]
+}
+
+func hebrewLeapYear(year int) bool {
+	return mod(7*year+1, 19) < 7
+}
+
+func lastMonthOfHebrewYear(year int) int {
+	if hebrewLeapYear(year) {
+		return adarII
+	}
+	return adar
+}
+
+// hebrewCalendarElapsedDays returns the number of days from the epoch to the new year's molad, postponed if it falls
+// on a Sunday, Wednesday or Friday.
+func hebrewCalendarElapsedDays(year int) int {
+	monthsElapsed := floorDiv(235*year-234, 19)
+	partsElapsed := 12084 + 13753*monthsElapsed
+	days := 29*monthsElapsed + floorDiv(partsElapsed, 25920)
+	if mod(3*(days+1), 7) < 3 {
+		return days + 1
+	}
+	return days
+}
+
+// hebrewYearLengthCorrection returns the further postponement of the new year needed to keep year lengths valid.
+func hebrewYearLengthCorrection(year int) int {
+	ny0 := hebrewCalendarElapsedDays(year - 1)
+	ny1 := hebrewCalendarElapsedDays(year)
+	ny2 := hebrewCalendarElapsedDays(year + 1)
+	switch {
+	case ny2-ny1 == 356:
+		return 2
+	case ny1-ny0 == 382:
+		return 1
+	}
+	return 0
+}
+
+func hebrewNewYear(year int) int {
+	return hebrewEpoch + hebrewCalendarElapsedDays(year) + hebrewYearLengthCorrection(year)
+}
+
+func daysInHebrewYear(year int) int {
+	return hebrewNewYear(year+1) - hebrewNewYear(year)
+}
+
+func lastDayOfHebrewMonth(year, month int) int {
+	switch {
+	case month == iyyar || month == tammuz || month == elul || month == tevet || month == adarII:
+		return 29
+	case month == adar && !hebrewLeapYear(year):
+		return 29
+	case month == heshvan && mod(daysInHebrewYear(year), 10) != 5:
+		return 29
+	case month == kislev && mod(daysInHebrewYear(year), 10) == 3:
+		return 29
+	}
+	return 30
+}
+
+func fixedFromHebrew(year, month, day int) int {
+	fixed := hebrewNewYear(year) + day - 1
+	if month < tishrei {
+		for m := tishrei; m <= lastMonthOfHebrewYear(year); m++ {
+			fixed += lastDayOfHebrewMonth(year, m)
+		}
+		for m := nisan; m < month; m++ {
+			fixed += lastDayOfHebrewMonth(year, m)
+		}
+	} else {
+		for m := tishrei; m < month; m++ {
+			fixed += lastDayOfHebrewMonth(year, m)
+		}
+	}
+	return fixed
+}
+
+func hebrewFromFixed(fixed int) Date {
+	// The average Hebrew year is 35975351/98496 days long.
+	year := int(float64(fixed-hebrewEpoch)*98496/35975351) - 1
+	for hebrewNewYear(year+1) <= fixed {
+		year++
+	}
+	month := nisan
+	if fixed < fixedFromHebrew(year, nisan, 1) {
+		month = tishrei
+	}
+	for fixed > fixedFromHebrew(year, month, lastDayOfHebrewMonth(year, month)) {
+		month++
+	}
+	return Date{Calendar: Hebrew, Year: year, Month: month, Day: fixed - fixedFromHebrew(year, month, 1) + 1}
+}
+
+// hebrewHolidaysOn returns the names of the holidays starting on the given day. Jewish holidays begin at sunset the
+// evening before. Where the diaspora and Israel differ, the diaspora dates are used.
+func hebrewHolidaysOn(fixed int) []string {
+	d := hebrewFromFixed(fixed)
+	purimMonth := adar
+	if hebrewLeapYear(d.Year) {
+		purimMonth = adarII
+	}
+	var names []string
+	switch {
+	case d.Month == tishrei && d.Day == 1:
+		names = append(names, "Rosh Hashanah")
+	case d.Month == tishrei && d.Day == 10:
+		names = append(names, "Yom Kippur")
+	case d.Month == tishrei && d.Day == 15:
+		names = append(names, "Sukkot")
+	case d.Month == tishrei && d.Day == 22:
+		names = append(names, "Shemini Atzeret")
+	case d.Month == tishrei && d.Day == 23:
+		names = append(names, "Simchat Torah")
+	case d.Month == kislev && d.Day == 25:
+		names = append(names, "Hanukkah")
+	case d.Month == shevat && d.Day == 15:
+		names = append(names, "Tu BiShvat")
+	case d.Month == purimMonth && d.Day == 14:
+		names = append(names, "Purim")
+	case d.Month == nisan && d.Day == 15:
+		names = append(names, "Passover")
+	case d.Month == sivan && d.Day == 6:
+		names = append(names, "Shavuot")
+	case d.Month == av && d.Day == 9 && mod(fixed, 7) != 6:
+		names = append(names, "Tisha B'Av")
+	case d.Month == av && d.Day == 10 && mod(fixed-1, 7) == 6:
+		// Tisha B'Av is postponed a day when it falls on Shabbat.
+		names = append(names, "Tisha B'Av")
+	}
+	return names
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calendars
+
+// islamicEpoch is the fixed day number of 1 Muharram, AH 1, by the tabular calendar.
+const islamicEpoch = 227015
+
+var islamicMonths = [12]string{"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani", "Jumada al-Awwal", "Jumada al-Thani", "Rajab", "Shaban", "Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah"}
+
+// The tabular Islamic calendar is arithmetic, but in practice months begin when the new crescent moon is sighted, which
+// can be a day or two different, and differ from country to country. Dates are only approximate as a result.
+
+func fixedFromIslamic(year, month, day int) int {
+	return day + 29*(month-1) + floorDiv(6*month-1, 11) + (year-1)*354 + floorDiv(3+11*year, 30) + islamicEpoch - 1
+}
+
+func islamicFromFixed(fixed int) Date {
+	year := floorDiv(30*(fixed-islamicEpoch)+10646, 10631)
+	priorDays := fixed - fixedFromIslamic(year, 1, 1)
+	month := floorDiv(11*priorDays+330, 325)
+	return Date{Calendar: Islamic, Year: year, Month: month, Day: fixed - fixedFromIslamic(year, month, 1) + 1}
+}
+
+// islamicHolidaysOn returns the names of the holidays on the given day. Islamic days begin at sunset the evening
+// before.
+func islamicHolidaysOn(fixed int) []string {
+	d := islamicFromFixed(fixed)
+	switch {
+	case d.Month == 1 && d.Day == 1:
+		return []string{"Islamic New Year"}
+	case d.Month == 1 && d.Day == 10:
+		return []string{"Ashura"}
+	case d.Month == 3 && d.Day == 12:
+		return []string{"Mawlid"}
+	case d.Month == 7 && d.Day == 27:
+		return []string{"Isra and Mi'raj"}
+	case d.Month == 9 && d.Day == 1:
+		return []string{"Start of Ramadan"}
+	case d.Month == 9 && d.Day == 27:
+		return []string{"Laylat al-Qadr"}
+	case d.Month == 10 && d.Day == 1:
+		return []string{"Eid al-Fitr"}
+	case d.Month == 12 && d.Day == 9:
+		return []string{"Day of Arafah"}
+	case d.Month == 12 && d.Day == 10:
+		return []string{"Eid al-Adha"}
+	}
+	return nil
+}
+
+// floorDiv divides, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// mod returns a modulo b, with the sign of b.
+func mod(a, b int) int {
+	return a - b*floorDiv(a, b)
+}
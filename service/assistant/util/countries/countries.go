@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package countries provides basic travel facts about countries and territories from an embedded dataset, so we
+// don't need to rely on the model's memory (or a network request) for them.
+package countries
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+type Country struct {
+	// The ISO 3166-1 alpha-2 code, e.g. "FR".
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Capital      string   `json:"capital"`
+	Currency     string   `json:"currency"`
+	CurrencyName string   `json:"currency_name"`
+	CallingCode  string   `json:"calling_code"`
+	// Either "left" or "right".
+	DrivingSide string `json:"driving_side"`
+	// The IEC plug type letters in common use, e.g. ["C", "F"].
+	PlugTypes []string `json:"plug_types"`
+}
+
+//go:embed countries.json
+var countriesJSON []byte
+
+var countriesByKey map[string]*Country
+
+func init() {
+	var countries []*Country
+	if err := json.Unmarshal(countriesJSON, &countries); err != nil {
+		panic("countries.json is invalid: " + err.Error())
+	}
+	countriesByKey = make(map[string]*Country, len(countries)*2)
+	for _, c := range countries {
+		countriesByKey[normalise(c.Code)] = c
+		countriesByKey[normalise(c.Name)] = c
+		for _, alias := range c.Aliases {
+			countriesByKey[normalise(alias)] = c
+		}
+	}
+}
+
+// Lookup finds a country by name, common alias, or ISO code. The match ignores case, accents and punctuation.
+// It returns nil if the country isn't known.
+func Lookup(name string) *Country {
+	return countriesByKey[normalise(name)]
+}
+
+func normalise(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, s); err == nil {
+		s = folded
+	}
+	s = strings.ToLower(s)
+	s = strings.TrimPrefix(s, "the ")
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
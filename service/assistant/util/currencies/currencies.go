@@ -29,6 +29,7 @@ import (
 
 	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
 )
 
 type CurrencyExchangeData struct {
@@ -92,7 +93,7 @@ func (dm *DataManager) fetchExchangeRateData(ctx context.Context, from string) (
 	if err != nil {
 		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(request)
+	resp, err := upstream.Client.Do(request)
 	if err != nil {
 		return nil, err
 	}
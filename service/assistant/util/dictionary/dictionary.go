@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dictionary looks up English words' meanings and pronunciations from the Free Dictionary API.
+package dictionary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// entryCacheTime is how long to keep a word's entry. Dictionaries don't change much.
+const entryCacheTime = 7 * 24 * time.Hour
+
+var ErrUnknownWord = errors.New("unknown word")
+
+// Entry is everything the dictionary has to say about a word.
+type Entry struct {
+	Word string `json:"word"`
+	// The word's pronunciation in IPA, e.g. "/prəˌnʌnsiˈeɪʃən/", if the dictionary has one.
+	IPA      string    `json:"ipa,omitempty"`
+	Meanings []Meaning `json:"meanings"`
+}
+
+// Meaning is what a word means as one part of speech.
+type Meaning struct {
+	PartOfSpeech string       `json:"part_of_speech"`
+	Definitions  []Definition `json:"definitions"`
+}
+
+type Definition struct {
+	Definition string `json:"definition"`
+	Example    string `json:"example,omitempty"`
+}
+
+// apiEntry is an entry as the Free Dictionary API returns it. Words with several etymologies get several entries.
+type apiEntry struct {
+	Word      string `json:"word"`
+	Phonetic  string `json:"phonetic"`
+	Phonetics []struct {
+		Text string `json:"text"`
+	} `json:"phonetics"`
+	Meanings []struct {
+		PartOfSpeech string `json:"partOfSpeech"`
+		Definitions  []struct {
+			Definition string `json:"definition"`
+			Example    string `json:"example"`
+		} `json:"definitions"`
+	} `json:"meanings"`
+}
+
+// Lookup returns the dictionary's entry for an English word.
+func Lookup(ctx context.Context, word string) (*Entry, error) {
+	ctx, span := beeline.StartSpan(ctx, "dictionary.lookup")
+	defer span.Send()
+	word = strings.ToLower(strings.TrimSpace(word))
+	span.AddField("word", word)
+	rd := storage.GetRedis()
+	if cached, err := rd.Get(ctx, cacheKey(word)).Result(); err == nil {
+		var e Entry
+		if err := json.Unmarshal([]byte(cached), &e); err == nil {
+			span.AddField("cached", true)
+			return &e, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	u := "https://api.dictionaryapi.dev/api/v2/entries/en/" + url.PathEscape(word)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := upstream.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUnknownWord
+	}
+	if err := upstream.CheckStatus("dictionaryapi", resp); err != nil {
+		return nil, err
+	}
+	var entries []apiEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrUnknownWord
+	}
+	e := merge(entries)
+	if encoded, err := json.Marshal(e); err == nil {
+		if err := rd.Set(ctx, cacheKey(word), encoded, entryCacheTime).Err(); err != nil {
+			span.AddField("cache_error", err)
+		}
+	}
+	return e, nil
+}
+
+func cacheKey(word string) string {
+	return "dictionary:" + word
+}
+
+// merge combines the API's entries for a word into one, using the first pronunciation given.
+func merge(entries []apiEntry) *Entry {
+	e := &Entry{Word: entries[0].Word, Meanings: []Meaning{}}
+	for _, a := range entries {
+		if e.IPA == "" {
+			e.IPA = a.Phonetic
+		}
+		for _, p := range a.Phonetics {
+			if e.IPA == "" {
+				e.IPA = p.Text
+			}
+		}
+		for _, m := range a.Meanings {
+			meaning := Meaning{PartOfSpeech: m.PartOfSpeech}
+			for _, d := range m.Definitions {
+				meaning.Definitions = append(meaning.Definitions, Definition{Definition: d.Definition, Example: d.Example})
+			}
+			e.Meanings = append(e.Meanings, meaning)
+		}
+	}
+	return e
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dictionary
+
+import "strings"
+
+// phoneme is one sound, as it's spelled in a respelling.
+type phoneme struct {
+	spelling string
+	vowel    bool
+}
+
+// phonemes maps English IPA to a simplified respelling in plain ASCII, along the lines of Wikipedia's pronunciation
+// respelling key. Pebble fonts can't show most IPA glyphs, but anyone can read these.
+var phonemes = map[string]phoneme{
+	// Vowels followed by an r that doesn't start the next syllable.
+	"ɑːr": {"ar", true}, "ɑr": {"ar", true}, "ɔːr": {"or", true}, "ɔr": {"or", true}, "ɪər": {"eer", true},
+	"ɪr": {"eer", true}, "ɛər": {"air", true}, "eər": {"air", true}, "ɛr": {"air", true}, "ʊər": {"oor", true},
+	"ʊr": {"oor", true}, "ɜːr": {"ur", true}, "ɜr": {"ur", true}, "ər": {"er", true},
+	// Diphthongs.
+	"eɪ": {"ay", true}, "aɪ": {"eye", true}, "ɔɪ": {"oy", true}, "aʊ": {"ow", true}, "oʊ": {"oh", true},
+	"əʊ": {"oh", true}, "ɪə": {"eer", true}, "eə": {"air", true}, "ɛə": {"air", true}, "ʊə": {"oor", true},
+	// Monophthongs.
+	"iː": {"ee", true}, "i": {"ee", true}, "ɪ": {"ih", true}, "ᵻ": {"ih", true}, "e": {"eh", true},
+	"ɛ": {"eh", true}, "æ": {"a", true}, "a": {"a", true}, "ɑː": {"ah", true}, "ɑ": {"ah", true},
+	"ɒ": {"o", true}, "ɔː": {"aw", true}, "ɔ": {"aw", true}, "o": {"oh", true}, "ʊ": {"uu", true},
+	"uː": {"oo", true}, "u": {"oo", true}, "ʌ": {"uh", true}, "ə": {"uh", true}, "ɐ": {"uh", true},
+	"ɜː": {"ur", true}, "ɜ": {"ur", true}, "ɝ": {"ur", true}, "ɚ": {"er", true},
+	// Consonants.
+	"tʃ": {"ch", false}, "dʒ": {"j", false}, "p": {"p", false}, "b": {"b", false}, "t": {"t", false},
+	"d": {"d", false}, "k": {"k", false}, "g": {"g", false}, "f": {"f", false}, "v": {"v", false},
+	"θ": {"th", false}, "ð": {"dh", false}, "s": {"s", false}, "z": {"z", false}, "ʃ": {"sh", false},
+	"ʒ": {"zh", false}, "h": {"h", false}, "x": {"kh", false}, "m": {"m", false}, "n": {"n", false},
+	"ŋ": {"ng", false}, "l": {"l", false}, "r": {"r", false}, "w": {"w", false}, "j": {"y", false},
+}
+
+// maxPhonemeRunes is the length of the longest key in phonemes.
+const maxPhonemeRunes = 3
+
+// normalize folds the variant symbols different dictionaries use into the ones in phonemes, and drops the ones that
+// don't change how a word would be respelled.
+var normalize = strings.NewReplacer(
+	"ɹ", "r", "ɡ", "g", "ɫ", "l", "ɾ", "t", "ʔ", "", "ʰ", "", "̩", "", "̯", "", "ˑ", "", "(", "", ")", "",
+	"‿", "", "'", "ˈ", ",", "ˌ", " ", ".", "-", ".",
+)
+
+type syllable struct {
+	sounds   []phoneme
+	stressed bool
+}
+
+// Respell turns an IPA transcription like "/prəˌnʌnsiˈeɪʃən/" into a respelling with its syllables broken up and the
+// stressed one in capitals, like "pruh-nuhn-see-AY-shuhn". It returns "" if the transcription can't be respelled.
+func Respell(ipa string) string {
+	syllables := syllabify(tokenize(transcription(ipa)))
+	var parts []string
+	for _, s := range syllables {
+		var b strings.Builder
+		for i, p := range s.sounds {
+			// "eye" reads better alone, but "y" reads better after a consonant, as in "TYM" for "time".
+			if p.spelling == "eye" && i > 0 {
+				b.WriteString("y")
+				continue
+			}
+			// Likewise "ih" and "eh" are only needed at the end of a syllable: "in" and "bed" say it already.
+			if (p.spelling == "ih" || p.spelling == "eh") && i < len(s.sounds)-1 {
+				b.WriteString(p.spelling[:1])
+				continue
+			}
+			b.WriteString(p.spelling)
+		}
+		part := b.String()
+		if s.stressed {
+			part = strings.ToUpper(part)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "-")
+}
+
+// transcription returns the first transcription in ipa, without its slashes or brackets.
+func transcription(ipa string) string {
+	ipa = strings.TrimSpace(ipa)
+	for _, delims := range []string{"//", "[]"} {
+		if strings.HasPrefix(ipa, delims[:1]) {
+			ipa = ipa[1:]
+			if end := strings.Index(ipa, delims[1:]); end >= 0 {
+				ipa = ipa[:end]
+			}
+			break
+		}
+	}
+	return normalize.Replace(ipa)
+}
+
+// tokenize splits an IPA transcription into phonemes, with stress and syllable marks left as they are. Sounds it
+// doesn't recognize are skipped.
+func tokenize(ipa string) []string {
+	var tokens []string
+	runes := []rune(ipa)
+	for i := 0; i < len(runes); {
+		switch runes[i] {
+		case 'ˈ', 'ˌ', '.':
+			tokens = append(tokens, string(runes[i]))
+			i++
+			continue
+		}
+		matched := false
+		for n := min(maxPhonemeRunes, len(runes)-i); n > 0; n-- {
+			candidate := string(runes[i : i+n])
+			if _, ok := phonemes[candidate]; !ok {
+				continue
+			}
+			// An r that begins the next syllable belongs to it, not the vowel before.
+			if n > 1 && strings.HasSuffix(candidate, "r") && i+n < len(runes) && startsVowel(runes[i+n:]) {
+				continue
+			}
+			tokens = append(tokens, candidate)
+			i += n
+			matched = true
+			break
+		}
+		if !matched {
+			i++
+		}
+	}
+	return tokens
+}
+
+func startsVowel(runes []rune) bool {
+	for n := min(maxPhonemeRunes, len(runes)); n > 0; n-- {
+		if p, ok := phonemes[string(runes[:n])]; ok {
+			return p.vowel
+		}
+	}
+	return false
+}
+
+// syllabify groups phonemes into syllables. Marks in the transcription are followed where there are any; otherwise
+// consonants between two vowels are split so the second syllable gets one of them.
+func syllabify(tokens []string) []syllable {
+	var syllables []syllable
+	var current syllable
+	// The consonants after the current syllable's vowel, which might yet belong to the next one.
+	var coda []phoneme
+	hasVowel := false
+	finish := func(next syllable) {
+		current.sounds = append(current.sounds, coda...)
+		if len(current.sounds) > 0 {
+			syllables = append(syllables, current)
+		}
+		current = next
+		coda = nil
+		hasVowel = false
+	}
+	for _, t := range tokens {
+		switch t {
+		case "ˈ":
+			finish(syllable{stressed: true})
+			continue
+		case "ˌ", ".":
+			finish(syllable{})
+			continue
+		}
+		p := phonemes[t]
+		if !p.vowel {
+			if hasVowel {
+				coda = append(coda, p)
+			} else {
+				current.sounds = append(current.sounds, p)
+			}
+			continue
+		}
+		if hasVowel {
+			keep := 0
+			if len(coda) > 1 {
+				keep = 1
+			}
+			onset := coda[keep:]
+			coda = coda[:keep]
+			finish(syllable{sounds: append([]phoneme(nil), onset...)})
+		}
+		current.sounds = append(current.sounds, p)
+		hasVowel = true
+	}
+	finish(syllable{})
+	return syllables
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package earthquakes looks up recent earthquakes from the USGS earthquake catalog.
+package earthquakes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// maxResults is the most earthquakes we'll ask for at once. Aftershock sequences can produce hundreds, but nobody
+// wants to hear about more than a few.
+const maxResults = 20
+
+// Quake is a single earthquake.
+type Quake struct {
+	ID        string
+	Magnitude float64
+	// A description of where it was, e.g. "10 km NE of Ridgecrest, CA".
+	Place    string
+	Time     time.Time
+	Lat, Lon float64
+	DepthKm  float64
+	// How many people have reported feeling it to USGS's "Did You Feel It?" survey.
+	Felt int
+	// Whether a tsunami warning may have been issued. This is only ever set for large quakes in oceanic regions.
+	Tsunami bool
+	// The PAGER alert level: "green", "yellow", "orange" or "red", if one has been assessed.
+	Alert string
+	URL   string
+}
+
+// apiResponse is the GeoJSON format USGS returns.
+type apiResponse struct {
+	Features []struct {
+		ID         string `json:"id"`
+		Properties struct {
+			Mag     *float64 `json:"mag"`
+			Place   string   `json:"place"`
+			Time    int64    `json:"time"`
+			Felt    *int     `json:"felt"`
+			Tsunami int      `json:"tsunami"`
+			Alert   *string  `json:"alert"`
+			URL     string   `json:"url"`
+		} `json:"properties"`
+		Geometry struct {
+			// Longitude, latitude and depth in kilometres.
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// Near returns the earthquakes of at least minMagnitude within radiusKm of the given point since the given time, most
+// recent first.
+func Near(ctx context.Context, lat, lon, radiusKm float64, since time.Time, minMagnitude float64) ([]Quake, error) {
+	ctx, span := beeline.StartSpan(ctx, "earthquakes.near")
+	defer span.Send()
+	params := url.Values{}
+	params.Set("format", "geojson")
+	params.Set("latitude", strconv.FormatFloat(lat, 'f', 4, 64))
+	params.Set("longitude", strconv.FormatFloat(lon, 'f', 4, 64))
+	params.Set("maxradiuskm", strconv.FormatFloat(radiusKm, 'f', 0, 64))
+	params.Set("starttime", since.UTC().Format("2006-01-02T15:04:05"))
+	params.Set("minmagnitude", strconv.FormatFloat(minMagnitude, 'f', 1, 64))
+	params.Set("orderby", "time")
+	params.Set("limit", strconv.Itoa(maxResults))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://earthquake.usgs.gov/fdsnws/event/1/query?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := upstream.Client.Do(req)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := upstream.CheckStatus("usgs", resp); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		span.AddField("error", err)
+		return nil, fmt.Errorf("decoding USGS response failed: %w", err)
+	}
+	quakes := make([]Quake, 0, len(result.Features))
+	for _, f := range result.Features {
+		// Events that haven't had a magnitude worked out yet aren't much use to anyone.
+		if f.Properties.Mag == nil || len(f.Geometry.Coordinates) < 3 {
+			continue
+		}
+		q := Quake{
+			ID:        f.ID,
+			Magnitude: *f.Properties.Mag,
+			Place:     f.Properties.Place,
+			Time:      time.UnixMilli(f.Properties.Time).UTC(),
+			Lon:       f.Geometry.Coordinates[0],
+			Lat:       f.Geometry.Coordinates[1],
+			DepthKm:   f.Geometry.Coordinates[2],
+			Tsunami:   f.Properties.Tsunami != 0,
+			URL:       f.Properties.URL,
+		}
+		if f.Properties.Felt != nil {
+			q.Felt = *f.Properties.Felt
+		}
+		if f.Properties.Alert != nil {
+			q.Alert = *f.Properties.Alert
+		}
+		quakes = append(quakes, q)
+	}
+	span.AddField("count", len(quakes))
+	return quakes, nil
+}
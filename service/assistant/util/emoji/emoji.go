@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emoji
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// replacements maps emoji the model likes to use onto something the Pebble system fonts can actually render.
+// An empty replacement means the emoji is purely decorative, and can be dropped without losing any meaning.
+var replacements = map[rune]string{
+	// Faces
+	'😀': ":D", '😃': ":D", '😄': ":D", '😁': ":D", '😆': "XD", '😂': "XD", '🤣': "XD",
+	'🙂': ":)", '😊': ":)", '☺': ":)", '😇': ":)", '🥰': ":)", '😍': ":)", '🤗': ":)",
+	'😉': ";)", '😜': ";P", '😛': ":P", '😝': "XP", '😋': ":P",
+	'🙁': ":(", '☹': ":(", '😞': ":(", '😔': ":(", '😟': ":(", '😢': ":'(", '😭': ":'(",
+	'😮': ":O", '😯': ":O", '😲': ":O", '😱': ":O",
+	'😐': ":|", '😑': ":|", '🤔': "hmm",
+	'😎': "B)",
+	// Hearts
+	'❤': "<3", '💖': "<3", '💕': "<3", '💗': "<3", '💙': "<3", '💚': "<3", '💛': "<3", '💜': "<3", '🧡': "<3",
+	// Gestures and marks
+	'👍': "(y)", '👎': "(n)", '👋': "", '🙏': "", '👏': "", '💪': "",
+	'✅': "", '✔': "", '☑': "", '❌': "", '❗': "!", '❕': "!", '❓': "?", '❔': "?",
+	'⚠': "!",
+	// Arrows and symbols that have plain-text equivalents.
+	'➡': "->", '⬅': "<-", '⬆': "^", '⬇': "v", '↔': "<->",
+	'➕': "+", '➖': "-", '➗': "/", '✖': "x",
+	// Decorative emoji that are frequently used alongside text that already says the same thing.
+	'☀': "", '🌞': "", '🌤': "", '⛅': "", '🌥': "", '☁': "", '🌦': "", '🌧': "", '⛈': "", '🌩': "", '🌨': "",
+	'❄': "", '☃': "", '⛄': "", '🌪': "", '🌫': "", '🌈': "", '☔': "", '💧': "", '🌡': "", '💨': "",
+	'🌙': "", '🌛': "", '🌜': "", '⭐': "", '🌟': "", '✨': "", '🎉': "", '🎊': "", '🥳': "", '🔥': "",
+	'⏰': "", '⏱': "", '⏲': "", '⌛': "", '⏳': "", '🔔': "", '📅': "", '📆': "", '📍': "", '🗺': "",
+	'💡': "", '📌': "", '📝': "", '🔍': "", '🔎': "", '🎵': "", '🎶': "",
+}
+
+// isEmoji reports whether r is in one of the blocks that the model uses for emoji. This isn't perfect, but it covers
+// everything we've actually seen come back from the model.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Emoticons, pictographs, transport, flags, supplemental symbols...
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Miscellaneous symbols and arrows
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // Miscellaneous technical (watches, hourglasses, etc.)
+		return true
+	case r == 0x2194 || r == 0x2139 || r == 0x203C || r == 0x2049:
+		return true
+	}
+	return false
+}
+
+// isModifier reports whether r is an invisible emoji modifier, which is meaningless once the emoji it modifies has
+// been removed.
+func isModifier(r rune) bool {
+	switch {
+	case r == 0x200D: // Zero width joiner
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // Variation selectors
+		return true
+	case r == 0x20E3: // Combining enclosing keycap
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // Tags (used for subdivision flags)
+		return true
+	}
+	return false
+}
+
+// Normalize replaces emoji in the given text with equivalents that can be rendered on a Pebble, and strips any that
+// we don't know how to handle. Stripped emoji are logged so we can decide whether to add them to the table.
+func Normalize(ctx context.Context, text string) string {
+	// Fast path: most responses don't contain anything we need to look at.
+	if !strings.ContainsFunc(text, func(r rune) bool { return isEmoji(r) || isModifier(r) }) {
+		return text
+	}
+	out := make([]byte, 0, len(text))
+	var stripped []string
+	// Whether the last thing we saw was an emoji we dropped. If it was, the spaces either side of it would otherwise
+	// leave a double space (or a space at the end of a line) behind. Spaces that were already in the text are left
+	// alone.
+	dropped := false
+	for _, r := range text {
+		if isModifier(r) {
+			continue
+		}
+		replacement, known := replacements[r]
+		if known && replacement != "" {
+			out = append(out, replacement...)
+			dropped = false
+			continue
+		}
+		if known || isEmoji(r) {
+			if !known {
+				stripped = append(stripped, fmt.Sprintf("U+%04X", r))
+			}
+			dropped = true
+			continue
+		}
+		if dropped && len(out) > 0 && out[len(out)-1] == ' ' {
+			if r == ' ' {
+				continue
+			}
+			if r == '\n' {
+				out = out[:len(out)-1]
+			}
+		}
+		dropped = false
+		out = utf8.AppendRune(out, r)
+	}
+	if len(stripped) > 0 {
+		requestid.Logf(ctx, "Stripped unsupported emoji from response: %s", strings.Join(stripped, ", "))
+		beeline.AddField(ctx, "stripped_emoji", strings.Join(stripped, ","))
+	}
+	return string(out)
+}
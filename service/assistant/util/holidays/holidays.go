@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package holidays
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// Holiday is a public holiday, as returned by the Nager.Date API.
+type Holiday struct {
+	// The date of the holiday, in YYYY-MM-DD format.
+	Date      string `json:"date"`
+	LocalName string `json:"localName"`
+	Name      string `json:"name"`
+	// Whether the holiday applies to the entire country, rather than only some regions.
+	Global bool `json:"global"`
+	// The ISO 3166-2 codes of the regions the holiday applies to, if it isn't global.
+	Counties []string `json:"counties,omitempty"`
+	// e.g. "Public", "Bank", "School", "Observance".
+	Types []string `json:"types"`
+}
+
+var ErrUnknownCountry = errors.New("unknown country code")
+
+var sharedHolidayDataManager *DataManager
+var sharedHolidayDataManagerOnce sync.Once
+
+func GetHolidayDataManager() *DataManager {
+	sharedHolidayDataManagerOnce.Do(func() {
+		sharedHolidayDataManager = &DataManager{
+			redisClient: storage.GetRedis(),
+		}
+	})
+	return sharedHolidayDataManager
+}
+
+type DataManager struct {
+	redisClient *redis.Client
+}
+
+// GetHolidays returns all the public holidays in the given country (an ISO 3166-1 alpha-2 code) in the given year.
+func (dm *DataManager) GetHolidays(ctx context.Context, countryCode string, year int) ([]Holiday, error) {
+	ctx, span := beeline.StartSpan(ctx, "get_holidays")
+	defer span.Send()
+	countryCode = strings.ToUpper(countryCode)
+	span.AddField("country", countryCode)
+	span.AddField("year", year)
+	holidays, err := dm.loadCachedData(ctx, countryCode, year)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load cached data: %w", err)
+	}
+	if holidays != nil {
+		return holidays, nil
+	}
+	holidays, err = dm.fetchHolidays(ctx, countryCode, year)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, fmt.Errorf("couldn't fetch holiday data: %w", err)
+	}
+	// Failing to cache isn't worth failing the request over: we'll just fetch them again next time.
+	if err := dm.cacheData(ctx, countryCode, year, holidays); err != nil {
+		span.AddField("cache_error", err)
+		requestid.Logf(ctx, "Caching holiday data failed: %v", err)
+	}
+	return holidays, nil
+}
+
+// GetHolidaysBetween returns the public holidays in the given country between from and to, inclusive.
+func (dm *DataManager) GetHolidaysBetween(ctx context.Context, countryCode string, from, to time.Time) ([]Holiday, error) {
+	var result []Holiday
+	fromDate := from.Format(time.DateOnly)
+	toDate := to.Format(time.DateOnly)
+	for year := from.Year(); year <= to.Year(); year++ {
+		holidays, err := dm.GetHolidays(ctx, countryCode, year)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holidays {
+			// Dates in this format sort lexicographically.
+			if h.Date >= fromDate && h.Date <= toDate {
+				result = append(result, h)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (dm *DataManager) fetchHolidays(ctx context.Context, countryCode string, year int) ([]Holiday, error) {
+	ctx, span := beeline.StartSpan(ctx, "fetch_holidays")
+	defer span.Send()
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://date.nager.at/api/v3/PublicHolidays/%d/%s", year, url.PathEscape(countryCode)), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := upstream.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusNoContent:
+		return nil, ErrUnknownCountry
+	default:
+		return nil, upstream.CheckStatus("nager.date", resp)
+	}
+	var holidays []Holiday
+	if err := json.NewDecoder(resp.Body).Decode(&holidays); err != nil {
+		return nil, err
+	}
+	if holidays == nil {
+		// Cache a year with no holidays as [] rather than null, so that it's recognised as a hit next time.
+		holidays = []Holiday{}
+	}
+	return holidays, nil
+}
+
+func (dm *DataManager) cacheData(ctx context.Context, countryCode string, year int, holidays []Holiday) error {
+	ctx, span := beeline.StartSpan(ctx, "cache_data")
+	defer span.Send()
+	encoded, err := json.Marshal(holidays)
+	if err != nil {
+		return err
+	}
+	// Holidays are occasionally announced or moved at short notice, so don't hold on to them forever.
+	if err := dm.redisClient.Set(ctx, cacheKey(countryCode, year), encoded, 24*time.Hour).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dm *DataManager) loadCachedData(ctx context.Context, countryCode string, year int) ([]Holiday, error) {
+	ctx, span := beeline.StartSpan(ctx, "load_cached_data")
+	defer span.Send()
+	data, err := dm.redisClient.Get(ctx, cacheKey(countryCode, year)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var decoded []Holiday
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func cacheKey(countryCode string, year int) string {
+	return fmt.Sprintf("holidays:%s:%d", countryCode, year)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keys spreads requests to upstream providers across several API keys, and stops using keys that the
+// provider has rejected.
+package keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/alerting"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+const (
+	// How long to stop using a key that the provider says is invalid. This is long because it probably needs a human
+	// to fix it, but not forever, because providers do sometimes reject good keys briefly.
+	AuthFailureCooldown = time.Hour
+	// How long to stop using a key that has run out of quota.
+	QuotaCooldown = 10 * time.Minute
+)
+
+// Ring holds a provider's API keys and hands them out in turn, skipping any that have recently been rejected.
+type Ring struct {
+	Provider string
+
+	mu            sync.Mutex
+	keys          []string
+	disabledUntil map[string]time.Time
+	next          int
+}
+
+// NewRing returns a ring of the given keys for the named provider.
+func NewRing(provider string, keys []string) *Ring {
+	return &Ring{Provider: provider, keys: keys, disabledUntil: map[string]time.Time{}}
+}
+
+var (
+	Gemini = NewRing("gemini", config.GetConfig().GeminiKeys)
+	Mapbox = NewRing("mapbox", config.GetConfig().MapboxKeys)
+)
+
+// Next returns the key to use for the next request, or "" if the ring has no keys at all. If every key has been
+// disabled, it returns the one that will be re-enabled soonest, since it may well work again by now and failing
+// outright certainly won't.
+func (r *Ring) Next() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.keys) == 0 {
+		return ""
+	}
+	now := time.Now()
+	soonest := ""
+	for i := 0; i < len(r.keys); i++ {
+		key := r.keys[(r.next+i)%len(r.keys)]
+		until := r.disabledUntil[key]
+		if until.Before(now) {
+			r.next = (r.next + i + 1) % len(r.keys)
+			return key
+		}
+		if soonest == "" || until.Before(r.disabledUntil[soonest]) {
+			soonest = key
+		}
+	}
+	log.Printf("All %s keys are disabled; using %s anyway", r.Provider, redact(soonest))
+	return soonest
+}
+
+// Healthy reports whether the ring has at least one key that hasn't been disabled.
+func (r *Ring) Healthy() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, key := range r.keys {
+		if r.disabledUntil[key].Before(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Disable stops the ring from handing out key for the given duration.
+func (r *Ring) Disable(key string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabledUntil[key] = time.Now().Add(d)
+}
+
+// ReportStatus tells the ring the HTTP status a request made with key got back. Keys that were rejected as invalid or
+// out of quota are disabled for a while; invalid keys also raise an alert.
+func (r *Ring) ReportStatus(key string, status int) {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		r.Disable(key, AuthFailureCooldown)
+		log.Printf("Disabled %s key %s for %s after status %d", r.Provider, redact(key), AuthFailureCooldown, status)
+		go func() {
+			err := alerting.Send(context.Background(), alerting.Alert{
+				Severity: alerting.SeverityWarning,
+				Source:   "keys",
+				Summary:  fmt.Sprintf("%s rejected API key %s", r.Provider, redact(key)),
+				Details:  fmt.Sprintf("The key got HTTP status %d, and won't be used for %s.", status, AuthFailureCooldown),
+			})
+			if err != nil {
+				log.Printf("Sending key alert failed: %v", err)
+			}
+		}()
+	case http.StatusTooManyRequests:
+		r.Disable(key, QuotaCooldown)
+		log.Printf("Disabled %s key %s for %s after running out of quota", r.Provider, redact(key), QuotaCooldown)
+	}
+}
+
+// ReportError is like ReportStatus, but takes the error a request made with key failed with. Errors that don't carry
+// an HTTP status are ignored.
+func (r *Ring) ReportError(key string, err error) {
+	var clientErr genai.ClientError
+	var serverErr genai.ServerError
+	var statusErr *upstream.StatusError
+	switch {
+	case errors.As(err, &clientErr):
+		r.ReportStatus(key, clientErr.Code)
+	case errors.As(err, &serverErr):
+		r.ReportStatus(key, serverErr.Code)
+	case errors.As(err, &statusErr):
+		r.ReportStatus(key, statusErr.StatusCode)
+	}
+}
+
+// redact returns enough of a key to tell which one it is in logs, without giving it away.
+func redact(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "…" + key[len(key)-4:]
+}
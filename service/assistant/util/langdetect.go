@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Common words that are fairly distinctive for each language. Voice queries are short, so we only need to catch the
+// handful of function words that show up in nearly every sentence.
+var stopwords = map[string][]string{
+	"en": {"the", "what", "is", "how", "my", "you", "and", "for", "it", "me", "set", "tell", "whats", "what's", "weather", "today", "tomorrow", "please", "can"},
+	"de": {"der", "die", "das", "und", "ist", "wie", "was", "ich", "mir", "nicht", "ein", "eine", "wetter", "heute", "morgen", "bitte", "wecker", "mich"},
+	"fr": {"le", "la", "les", "est", "et", "quel", "quelle", "je", "moi", "pour", "une", "des", "quoi", "météo", "aujourd'hui", "demain", "c'est", "qu'est", "ce"},
+	"es": {"el", "los", "las", "es", "qué", "que", "cómo", "para", "una", "por", "tiempo", "hoy", "mañana", "cuál", "dime", "está", "del"},
+	"it": {"il", "lo", "gli", "è", "che", "come", "per", "una", "sono", "cosa", "tempo", "oggi", "domani", "dimmi", "della", "quanto"},
+	"pt": {"o", "os", "é", "que", "como", "para", "uma", "não", "qual", "tempo", "hoje", "amanhã", "você", "está", "do", "da"},
+	"nl": {"de", "het", "een", "is", "wat", "hoe", "ik", "mij", "niet", "weer", "vandaag", "morgen", "alsjeblieft", "van", "voor"},
+	"sv": {"är", "och", "vad", "hur", "jag", "mig", "det", "en", "inte", "vädret", "idag", "imorgon", "för", "på"},
+	"da": {"er", "og", "hvad", "hvordan", "jeg", "mig", "det", "ikke", "vejret", "morgen", "til", "på"},
+	"no": {"er", "og", "hva", "hvordan", "jeg", "meg", "det", "ikke", "været", "morgen", "til", "på"},
+	"fi": {"on", "ja", "mikä", "mitä", "miten", "minä", "ei", "sää", "tänään", "huomenna", "kuinka", "onko"},
+	"pl": {"jest", "i", "co", "jak", "nie", "się", "pogoda", "dzisiaj", "jutro", "proszę", "jaka", "mi"},
+	"cs": {"je", "a", "co", "jak", "ne", "se", "počasí", "dnes", "zítra", "prosím", "jaké", "mi"},
+	"tr": {"ve", "bir", "ne", "nasıl", "bu", "hava", "bugün", "yarın", "lütfen", "mi", "mı", "nedir"},
+	"id": {"dan", "yang", "apa", "bagaimana", "saya", "tidak", "ini", "cuaca", "hari", "besok", "tolong", "ada"},
+	"ro": {"și", "este", "ce", "cum", "eu", "nu", "vremea", "astăzi", "mâine", "vă", "rog", "care"},
+	"hu": {"és", "az", "egy", "mi", "hogy", "nem", "időjárás", "ma", "holnap", "kérlek", "milyen", "van"},
+}
+
+// scripts maps non-Latin scripts onto the language that is overwhelmingly likely to be using them.
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+}
+
+// DetectLanguage makes a best-effort guess at the language of the given text, returning a language code, or the empty
+// string if it isn't reasonably confident. It is only intended to be good enough to notice when a user switches
+// language mid-conversation.
+func DetectLanguage(text string) string {
+	if lang := detectScript(text); lang != "" {
+		return lang
+	}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	scores := map[string]int{}
+	for lang, list := range stopwords {
+		for _, w := range words {
+			for _, s := range list {
+				if w == s {
+					scores[lang]++
+					break
+				}
+			}
+		}
+	}
+	best, bestScore, runnerUp := "", 0, 0
+	for lang, score := range scores {
+		if score > bestScore {
+			best, bestScore, runnerUp = lang, score, bestScore
+		} else if score > runnerUp {
+			runnerUp = score
+		}
+	}
+	// Demand a clear winner: a single ambiguous word like "is" or "de" isn't enough to go on.
+	if bestScore < 2 || bestScore == runnerUp {
+		return ""
+	}
+	return best
+}
+
+func detectScript(text string) string {
+	counts := make([]int, len(scripts))
+	for _, r := range text {
+		for i, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[i]++
+				break
+			}
+		}
+	}
+	bestIndex, bestCount := -1, 0
+	for i, c := range counts {
+		// Japanese text is usually mostly Han characters, so the presence of any kana at all settles it.
+		if c > 0 && scripts[i].language == "ja" {
+			return "ja"
+		}
+		if c > bestCount {
+			bestIndex, bestCount = i, c
+		}
+	}
+	if bestIndex == -1 {
+		return ""
+	}
+	return scripts[bestIndex].language
+}
@@ -48,6 +48,14 @@ var languages = map[string]string{
 	"sw":  "Swahili",
 	"tr":  "Turkish",
 	"zu":  "Zulu",
+	// These aren't offered as preferences, but we may detect them in the user's messages.
+	"ar": "Arabic",
+	"el": "Greek",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"ja": "Japanese",
+	"th": "Thai",
+	"zh": "Chinese",
 }
 
 func GetLanguageName(code string) string {
@@ -0,0 +1,77 @@
+package mapbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/alerting"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+var (
+	// ErrNotConfigured is returned instead of making a request when there's no Mapbox key.
+	ErrNotConfigured = errors.New("mapbox is not configured")
+	// ErrBudgetExhausted is returned instead of making a request once this month's budget has been used up.
+	ErrBudgetExhausted = errors.New("the monthly mapbox budget has been used up")
+)
+
+// Available reports whether Mapbox can be used at all. Even if it can, requests may still fail with
+// ErrBudgetExhausted.
+func Available() bool {
+	return len(config.GetConfig().MapboxKeys) > 0
+}
+
+func budgetKey() string {
+	now := time.Now()
+	return fmt.Sprintf("mapbox_budget:%02d%02d", now.Year()%100, now.Month())
+}
+
+// spend charges one request against this month's budget, returning ErrBudgetExhausted if there's nothing left. If
+// we can't tell how much has been spent, we don't make the request: Photon is free, and Mapbox isn't.
+func spend(ctx context.Context) error {
+	ctx, span := beeline.StartSpan(ctx, "mapbox.spend")
+	defer span.Send()
+	if !Available() {
+		return ErrNotConfigured
+	}
+	budget := config.GetConfig().MapboxMonthlyBudget
+	rd := storage.GetRedis()
+	key := budgetKey()
+	used, err := rd.Incr(ctx, key).Result()
+	if err != nil {
+		span.AddField("error", err)
+		return fmt.Errorf("error checking mapbox budget: %w", err)
+	}
+	if used == 1 {
+		// As with the global quotas, 45 days is long enough to outlive the month.
+		if err := rd.Expire(ctx, key, 45*24*time.Hour).Err(); err != nil {
+			span.AddField("error", err)
+		}
+	}
+	span.AddField("mapbox_budget_used", used)
+	if used <= int64(budget) {
+		return nil
+	}
+	if used == int64(budget)+1 {
+		// Only the first request over budget gets here each month, so this alerts once.
+		requestid.Logf(ctx, "Mapbox budget of %d requests used up for this month\n", budget)
+		go func() {
+			err := alerting.Send(context.Background(), alerting.Alert{
+				Severity: alerting.SeverityWarning,
+				Source:   "mapbox",
+				Summary:  "Mapbox budget used up",
+				Details:  fmt.Sprintf("All %d Mapbox requests budgeted for this month have been made. Geocoding will use Photon alone until next month.", budget),
+			})
+			if err != nil {
+				requestid.Logf(ctx, "Sending mapbox budget alert failed: %v", err)
+			}
+		}()
+	}
+	return ErrBudgetExhausted
+}
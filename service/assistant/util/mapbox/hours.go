@@ -0,0 +1,98 @@
+package mapbox
+
+import (
+	"fmt"
+	"time"
+)
+
+const minutesPerWeek = 7 * 24 * 60
+
+// HoursStatus is what a place's opening hours mean at a particular moment.
+type HoursStatus struct {
+	OpenNow bool
+	// When the place next closes, if it's open. Zero if it's open around the clock.
+	ClosesAt time.Time
+	// When the place next opens, if it's closed. Zero if it never opens.
+	OpensAt time.Time
+}
+
+// Evaluate works out whether the place is open at now, and when that next changes. Periods are interpreted in now's
+// location, so now should be in the place's (or, failing that, the user's) timezone.
+func (h OpenHours) Evaluate(now time.Time) HoursStatus {
+	now = now.Truncate(time.Minute)
+	current := minuteOfWeek(int(now.Weekday()), now.Hour(), now.Minute())
+
+	// Turn the periods into intervals in minutes from the start of the week. An interval may run past the end of the
+	// week, e.g. from Saturday evening to Sunday morning.
+	type interval struct{ start, end int }
+	var intervals []interval
+	for _, period := range h.Periods {
+		start, ok := period.Open.minuteOfWeek()
+		if !ok {
+			continue
+		}
+		end, ok := period.Close.minuteOfWeek()
+		if !ok {
+			// A period with no close is open from then on; the only sensible reading of that is a place that never
+			// closes.
+			return HoursStatus{OpenNow: true}
+		}
+		if end <= start {
+			end += minutesPerWeek
+		}
+		intervals = append(intervals, interval{start, end})
+	}
+
+	// minutesUntilClose returns how long after current the interval containing it ends, if there is one.
+	minutesUntilClose := func(at int) (int, bool) {
+		for _, i := range intervals {
+			// at may fall in this week's instance of the interval, or last week's, if that wrapped around.
+			for _, offset := range []int{0, minutesPerWeek} {
+				if at+offset >= i.start && at+offset < i.end {
+					return i.end - at - offset, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	if closesIn, open := minutesUntilClose(current); open {
+		// Places often list back-to-back periods, e.g. one per day for somewhere open around the clock, so follow
+		// them to find when it actually closes.
+		for total := closesIn; total < minutesPerWeek; total += closesIn {
+			if closesIn, open = minutesUntilClose((current + total) % minutesPerWeek); !open {
+				return HoursStatus{OpenNow: true, ClosesAt: now.Add(time.Duration(total) * time.Minute)}
+			}
+		}
+		return HoursStatus{OpenNow: true}
+	}
+
+	opensIn := -1
+	for _, i := range intervals {
+		until := (i.start - current + minutesPerWeek) % minutesPerWeek
+		if opensIn < 0 || until < opensIn {
+			opensIn = until
+		}
+	}
+	if opensIn < 0 {
+		return HoursStatus{}
+	}
+	return HoursStatus{OpensAt: now.Add(time.Duration(opensIn) * time.Minute)}
+}
+
+func minuteOfWeek(day, hour, minute int) int {
+	return day*24*60 + hour*60 + minute
+}
+
+// minuteOfWeek returns the number of minutes from the start of Sunday to the time point, or false if the time point
+// is missing or malformed.
+func (t TimePoint) minuteOfWeek() (int, bool) {
+	var hour, minute int
+	if len(t.Time) != 4 || t.Day < 0 || t.Day > 6 {
+		return 0, false
+	}
+	if _, err := fmt.Sscanf(t.Time, "%02d%02d", &hour, &minute); err != nil || hour > 24 || minute > 59 {
+		return 0, false
+	}
+	return minuteOfWeek(t.Day, hour, minute), true
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/honeycombio/beeline-go"
-	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/keys"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 type FeatureCollection struct {
@@ -54,18 +56,64 @@ type TimePoint struct {
 func SearchBoxRequest(ctx context.Context, params url.Values) (*FeatureCollection, error) {
 	ctx, span := beeline.StartSpan(ctx, "mapbox.searchbox")
 	defer span.Send()
-	params.Set("access_token", config.GetConfig().MapboxKey)
+	if err := spend(ctx); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	key := keys.Mapbox.Next()
+	params.Set("access_token", key)
 	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.mapbox.com/search/searchbox/v1/forward?"+params.Encode(), nil)
 	if err != nil {
 		span.AddField("error", err)
 		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := upstream.Client.Do(req)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := upstream.CheckStatus("mapbox", resp); err != nil {
+		span.AddField("error", err)
+		keys.Mapbox.ReportError(key, err)
+		return nil, err
+	}
+	var collection FeatureCollection
+	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// GeocodeRequest looks up a place name using the Mapbox geocoding API.
+func GeocodeRequest(ctx context.Context, search string, params url.Values) (*FeatureCollection, error) {
+	ctx, span := beeline.StartSpan(ctx, "mapbox.geocode")
+	defer span.Send()
+	if err := spend(ctx); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	key := keys.Mapbox.Next()
+	params.Set("access_token", key)
+	// Mapbox treats semicolons in the search as a batch separator.
+	search = strings.ReplaceAll(search, ";", ",")
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.mapbox.com/geocoding/v5/mapbox.places/"+url.PathEscape(search)+".json?"+params.Encode(), nil)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	resp, err := upstream.Client.Do(req)
 	if err != nil {
 		span.AddField("error", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if err := upstream.CheckStatus("mapbox", resp); err != nil {
+		span.AddField("error", err)
+		keys.Mapbox.ReportError(key, err)
+		return nil, err
+	}
 	var collection FeatureCollection
 	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
 		span.AddField("error", err)
@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openfoodfacts looks up food products by barcode in the Open Food Facts database.
+package openfoodfacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// fields are the product fields we ask for. Full products can run to hundreds of kilobytes.
+var fields = []string{
+	"product_name", "brands", "quantity", "ingredients_text", "allergens_tags", "traces_tags", "labels_tags",
+	"nutriscore_grade", "nova_group", "nutriments",
+}
+
+var ErrUnknownProduct = errors.New("unknown product")
+
+// Product is a food product, as far as Open Food Facts knows it. Any field may be empty: the database is
+// crowdsourced, and many entries are incomplete.
+type Product struct {
+	Name     string
+	Brands   string
+	Quantity string
+	// The ingredients as printed on the packaging, in whatever language that is.
+	Ingredients string
+	Allergens   []string
+	// Allergens the product may contain traces of.
+	Traces []string
+	// e.g. "vegan", "organic".
+	Labels []string
+	// The Nutri-Score, from "a" (best) to "e".
+	NutriScore string
+	// The NOVA group, from 1 (unprocessed) to 4 (ultra-processed), or 0 if unknown.
+	NovaGroup int
+	// Nutrition per 100g or 100ml, keyed by nutrient, e.g. "energy-kcal", "sugars".
+	Nutrition map[string]float64
+}
+
+// nutrients are the nutrients we pass on, of the many Open Food Facts records.
+var nutrients = []string{"energy-kcal", "fat", "saturated-fat", "carbohydrates", "sugars", "fiber", "proteins", "salt"}
+
+type apiResponse struct {
+	Status  int `json:"status"`
+	Product struct {
+		ProductName     string         `json:"product_name"`
+		Brands          string         `json:"brands"`
+		Quantity        string         `json:"quantity"`
+		IngredientsText string         `json:"ingredients_text"`
+		AllergensTags   []string       `json:"allergens_tags"`
+		TracesTags      []string       `json:"traces_tags"`
+		LabelsTags      []string       `json:"labels_tags"`
+		NutriscoreGrade string         `json:"nutriscore_grade"`
+		NovaGroup       int            `json:"nova_group"`
+		Nutriments      map[string]any `json:"nutriments"`
+	} `json:"product"`
+}
+
+// Lookup returns the product with the given barcode, or ErrUnknownProduct if there isn't one.
+func Lookup(ctx context.Context, barcode string) (*Product, error) {
+	ctx, span := beeline.StartSpan(ctx, "openfoodfacts.lookup")
+	defer span.Send()
+	span.AddField("barcode", barcode)
+	u := fmt.Sprintf("https://world.openfoodfacts.org/api/v2/product/%s.json?fields=%s", url.PathEscape(barcode), strings.Join(fields, ","))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := upstream.Client.Do(req)
+	if err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUnknownProduct
+	}
+	if err := upstream.CheckStatus("openfoodfacts", resp); err != nil {
+		span.AddField("error", err)
+		return nil, err
+	}
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		span.AddField("error", err)
+		return nil, fmt.Errorf("decoding Open Food Facts response failed: %w", err)
+	}
+	if result.Status != 1 {
+		return nil, ErrUnknownProduct
+	}
+	p := result.Product
+	product := &Product{
+		Name:        p.ProductName,
+		Brands:      p.Brands,
+		Quantity:    p.Quantity,
+		Ingredients: p.IngredientsText,
+		Allergens:   stripLanguages(p.AllergensTags),
+		Traces:      stripLanguages(p.TracesTags),
+		Labels:      stripLanguages(p.LabelsTags),
+		NutriScore:  p.NutriscoreGrade,
+		NovaGroup:   p.NovaGroup,
+		Nutrition:   map[string]float64{},
+	}
+	for _, n := range nutrients {
+		// Values are usually numbers, but older entries sometimes have strings.
+		switch v := p.Nutriments[n+"_100g"].(type) {
+		case float64:
+			product.Nutrition[n] = v
+		case string:
+			var f float64
+			if _, err := fmt.Sscan(v, &f); err == nil {
+				product.Nutrition[n] = f
+			}
+		}
+	}
+	return product, nil
+}
+
+// stripLanguages turns tags like "en:milk" into "milk".
+func stripLanguages(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if _, name, ok := strings.Cut(t, ":"); ok {
+			t = name
+		}
+		result = append(result, strings.ReplaceAll(t, "-", " "))
+	}
+	return result
+}
@@ -5,11 +5,25 @@ import (
     "encoding/json"
     "fmt"
     "github.com/honeycombio/beeline-go"
+    "github.com/pebble-dev/bobby-assistant/service/assistant/config"
     "github.com/pebble-dev/bobby-assistant/service/assistant/query"
+    "github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
     "net/http"
     "net/url"
+    "strings"
 )
 
+// defaultBaseURL is Photon's public API, used unless PHOTON_URL says otherwise.
+const defaultBaseURL = "https://photon.komoot.io"
+
+// baseURL returns where to find Photon, without a trailing slash.
+func baseURL() string {
+    if u := config.GetConfig().PhotonURL; u != "" {
+        return strings.TrimSuffix(u, "/")
+    }
+    return defaultBaseURL
+}
+
 type FeatureCollection struct {
     Features []Feature `json:"features"`
 }
@@ -34,6 +48,7 @@ type Properties struct {
     City      string `json:"city,omitempty"`
     State     string `json:"state,omitempty"`
     Country   string `json:"country,omitempty"`
+    CountryCode string `json:"countrycode,omitempty"`
     OSMId     int64  `json:"osm_id"`
     OSMType   string `json:"osm_type"`
     OSMKey    string `json:"osm_key"`
@@ -41,8 +56,12 @@ type Properties struct {
 }
 
 type Location struct {
-    Lat float64
-    Lon float64
+    Lat  float64
+    Lon  float64
+    // Name is the name of the place we actually found, which may differ from what was searched for.
+    Name string
+    // CountryCode is the place's ISO 3166 country code, if known.
+    CountryCode string
 }
 
 // generatePlaceName returns just the city name, or falls back to other location info if city is unavailable
@@ -76,12 +95,16 @@ func sendRequest(ctx context.Context, url string) (*FeatureCollection, error) {
         return nil, err
     }
 
-    resp, err := http.DefaultClient.Do(req)
+    resp, err := upstream.Client.Do(req)
     if err != nil {
         span.AddField("error", err)
         return nil, err
     }
     defer resp.Body.Close()
+    if err := upstream.CheckStatus("photon", resp); err != nil {
+        span.AddField("error", err)
+        return nil, err
+    }
 
     var collection FeatureCollection
     if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
@@ -97,16 +120,46 @@ func sendRequest(ctx context.Context, url string) (*FeatureCollection, error) {
     return &collection, nil
 }
 
-// GeocodeWithContext converts a location name to coordinates
-func GeocodeWithContext(ctx context.Context, search string) (Location, error) {
+// GeocodeWithContext converts a location name to coordinates, considering only places of the given kind. Photon is
+// used where it gives a good answer, and Mapbox where it doesn't (see preferMapbox). If nothing matches, it tries a
+// few variations on the search (see recoverGeocode); if those fail too, the returned error is a *NotFoundError, which
+// may carry suggestions.
+func GeocodeWithContext(ctx context.Context, search string, kind PlaceKind) (Location, error) {
     ctx, span := beeline.StartSpan(ctx, "photon.geocode")
     defer span.Send()
 
-    location := query.LocationFromContext(ctx)
+    feature, err := geocode(ctx, search, kind)
+    if err != nil {
+        span.AddField("error", err)
+        return Location{}, fmt.Errorf("could not find location: %w", err)
+    }
+    if feature == nil {
+        location, err := recoverGeocode(ctx, search, kind)
+        if err != nil {
+            span.AddField("error", err)
+        }
+        return location, err
+    }
+    if reason := mapboxReason(search, feature); reason != "" {
+        if location := preferMapbox(ctx, search, kind, reason); location != nil {
+            span.AddField("provider", "mapbox")
+            return *location, nil
+        }
+    }
+
+    return featureLocation(feature), nil
+}
+
+// geocode returns photon's best hit of the given kind for the search, or nil if there were no plausible hits.
+func geocode(ctx context.Context, search string, kind PlaceKind) (*Feature, error) {
+    location := query.CoarseLocationFromContext(ctx)
 
     params := url.Values{}
     params.Set("q", search)
-    params.Set("limit", "1")
+    params.Set("limit", "5")
+    for _, layer := range kind.layers() {
+        params.Add("layer", layer)
+    }
 
     // If we have user location, use it for biasing results
     if location != nil {
@@ -114,25 +167,35 @@ func GeocodeWithContext(ctx context.Context, search string) (Location, error) {
         params.Set("lat", fmt.Sprintf("%f", location.Lat))
     }
 
-    apiURL := "https://photon.komoot.io/api/?" + params.Encode()
+    apiURL := baseURL() + "/api/?" + params.Encode()
 
     collection, err := sendRequest(ctx, apiURL)
     if err != nil {
-        return Location{}, fmt.Errorf("could not find location: %w", err)
+        return nil, err
     }
 
-    if len(collection.Features) == 0 {
-        return Location{}, fmt.Errorf("could not find location with name %q", search)
+    // Photon orders results sensibly, but location biasing means the first result isn't necessarily the one that
+    // best matches the name. Take the first one that's plausibly what was asked for.
+    for i := range collection.Features {
+        feature := &collection.Features[i]
+        if len(feature.Geometry.Coordinates) < 2 {
+            continue
+        }
+        if looksLikeAbbreviation(search) || relevance(search, feature) >= minRelevance {
+            return feature, nil
+        }
     }
+    return nil, nil
+}
 
+func featureLocation(feature *Feature) Location {
     // Photon API returns coordinates as [lon, lat]
-    lon := collection.Features[0].Geometry.Coordinates[0]
-    lat := collection.Features[0].Geometry.Coordinates[1]
-
     return Location{
-        Lat: lat,
-        Lon: lon,
-    }, nil
+        Lat:  feature.Geometry.Coordinates[1],
+        Lon:  feature.Geometry.Coordinates[0],
+        Name: feature.PlaceName,
+        CountryCode: feature.Properties.CountryCode,
+    }
 }
 
 // ReverseGeocode converts coordinates to a location name
@@ -144,7 +207,7 @@ func ReverseGeocode(ctx context.Context, lon, lat float64) (*Feature, error) {
     params.Set("lon", fmt.Sprintf("%f", lon))
     params.Set("lat", fmt.Sprintf("%f", lat))
 
-    apiURL := "https://photon.komoot.io/reverse/?" + params.Encode()
+    apiURL := baseURL() + "/reverse/?" + params.Encode()
 
     collection, err := sendRequest(ctx, apiURL)
     if err != nil {
@@ -0,0 +1,80 @@
+package photon
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PlaceKind tells the geocoder what sort of place the caller is looking for.
+type PlaceKind int
+
+const (
+	// AnyPlace accepts any result, including businesses, landmarks and street addresses.
+	AnyPlace PlaceKind = iota
+	// Settlement accepts only areas: cities, towns, villages, and the regions and countries containing them. This is
+	// what weather lookups want - otherwise location biasing can turn "Paris" into a nearby restaurant called Paris.
+	Settlement
+)
+
+// layers returns the photon layers to restrict the search to, or nil for no restriction.
+func (k PlaceKind) layers() []string {
+	switch k {
+	case Settlement:
+		return []string{"city", "locality", "district", "county", "state", "country"}
+	}
+	return nil
+}
+
+// mapboxTypes returns the Mapbox place types equivalent to this kind, for fuzzy matching.
+func (k PlaceKind) mapboxTypes() string {
+	switch k {
+	case Settlement:
+		return "country,region,district,place,locality,neighborhood"
+	}
+	return "country,region,district,place,locality,neighborhood,address,poi"
+}
+
+// minRelevance is the fraction of the words in a search that must appear in a result for us to believe it's what
+// was asked for. Photon will always return *something*, and "something" is often nonsense.
+const minRelevance = 0.5
+
+// relevance estimates how well a feature matches a search, from 0 (no words in common) to 1 (every word in the
+// search appears somewhere in the feature's name or address).
+func relevance(search string, feature *Feature) float64 {
+	searchWords := placeWords(search)
+	if len(searchWords) == 0 {
+		return 1
+	}
+	p := feature.Properties
+	featureWords := map[string]bool{}
+	for _, field := range []string{p.Name, p.City, p.State, p.Country, p.Street, p.Postcode} {
+		for _, w := range placeWords(field) {
+			featureWords[w] = true
+		}
+	}
+	matched := 0
+	for _, w := range searchWords {
+		if featureWords[w] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(searchWords))
+}
+
+// placeWords splits a place name into lowercase, diacritic-free words, ignoring qualifiers like "city of".
+func placeWords(s string) []string {
+	s = strings.ToLower(transliterate(qualifierPattern.ReplaceAllString(s, " ")))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// looksLikeAbbreviation reports whether the search is probably something like "NYC" or "LA", which won't share any
+// words with the place's actual name, so the relevance check would wrongly reject it.
+func looksLikeAbbreviation(search string) bool {
+	search = strings.TrimSpace(search)
+	if len(search) <= 3 {
+		return true
+	}
+	return !strings.ContainsFunc(search, unicode.IsLower) && !strings.ContainsFunc(search, unicode.IsSpace)
+}
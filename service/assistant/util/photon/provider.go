@@ -0,0 +1,71 @@
+package photon
+
+import (
+	"context"
+	"errors"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/mapbox"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// Mapbox charges for every request and photon is free, so photon answers everything it can. Mapbox is only asked
+// when photon's answer is a point of interest, which OpenStreetMap often has stale or patchy data for, or when
+// photon's answer only loosely matches the search. Failed searches also go to Mapbox (see recoverGeocode).
+
+// goodRelevance is the relevance above which we trust photon's answer without checking it against Mapbox.
+const goodRelevance = 0.75
+
+// poiOSMKeys are the OpenStreetMap keys that mark a feature as a point of interest rather than a place or address.
+var poiOSMKeys = map[string]bool{
+	"amenity":    true,
+	"shop":       true,
+	"tourism":    true,
+	"leisure":    true,
+	"office":     true,
+	"craft":      true,
+	"healthcare": true,
+}
+
+// mapboxReason returns why photon's answer to the search isn't good enough to use without asking Mapbox, or "" if
+// it is.
+func mapboxReason(search string, feature *Feature) string {
+	if poiOSMKeys[feature.Properties.OSMKey] {
+		return "poi"
+	}
+	if relevance(search, feature) < goodRelevance {
+		return "low_relevance"
+	}
+	return ""
+}
+
+// mapboxTypesFor returns the Mapbox place types to search when checking photon's answer for the given reason.
+func mapboxTypesFor(kind PlaceKind, reason string) string {
+	if reason == "poi" {
+		return "poi"
+	}
+	return kind.mapboxTypes()
+}
+
+// preferMapbox asks Mapbox about a search photon answered poorly, returning Mapbox's answer if it's confident and
+// nil otherwise, in which case photon's answer should be used after all.
+func preferMapbox(ctx context.Context, search string, kind PlaceKind, reason string) *Location {
+	ctx, span := beeline.StartSpan(ctx, "photon.prefer_mapbox")
+	defer span.Send()
+	span.AddField("reason", reason)
+	if !mapbox.Available() {
+		return nil
+	}
+	location, _, err := mapboxGeocode(ctx, search, mapboxTypesFor(kind, reason))
+	if err != nil {
+		span.AddField("error", err)
+		if !errors.Is(err, mapbox.ErrBudgetExhausted) {
+			requestid.Logf(ctx, "checking %q with Mapbox failed: %v\n", search, err)
+		}
+		return nil
+	}
+	if location != nil {
+		span.AddField("resolved", location.Name)
+	}
+	return location
+}
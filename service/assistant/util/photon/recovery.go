@@ -0,0 +1,149 @@
+package photon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/mapbox"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// mapboxConfidentRelevance is the relevance above which we'll take Mapbox's fuzzy match as the answer rather than
+// offering it as a suggestion.
+const mapboxConfidentRelevance = 0.9
+
+// maxSuggestions is the most "did you mean" suggestions we'll return.
+const maxSuggestions = 3
+
+// NotFoundError is returned when a place can't be found, even after trying variations on the name.
+type NotFoundError struct {
+	Search string
+	// Suggestions are place names that might be what the user meant, best first. It may be empty.
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("could not find location with name %q", e.Search)
+	}
+	return fmt.Sprintf("could not find location with name %q, did you mean: %s", e.Search, strings.Join(e.Suggestions, "; "))
+}
+
+// qualifierPattern matches words people (and dictation) often wrap around a place name that confuse the geocoder.
+var qualifierPattern = regexp.MustCompile(`(?i)\b(the|city|town|village|of|downtown|central|greater|metro|area|region|near|around)\b`)
+
+// recoverGeocode is called when a search found nothing. It tries, in order: stripping qualifiers, dropping trailing
+// comma-separated parts, transliterating to plain ASCII, and finally Mapbox's fuzzy geocoder. Dictation often mishears
+// place names ("Gnome Alaska" for "Nome, Alaska"), so a fuzzy match is more useful than giving up.
+func recoverGeocode(ctx context.Context, search string, kind PlaceKind) (Location, error) {
+	ctx, span := beeline.StartSpan(ctx, "photon.recover_geocode")
+	defer span.Send()
+	span.AddField("search", search)
+
+	for _, variant := range searchVariants(search) {
+		feature, err := geocode(ctx, variant, kind)
+		if err != nil {
+			// If photon is broken, there's no point trying more variants.
+			return Location{}, fmt.Errorf("could not find location: %w", err)
+		}
+		if feature != nil {
+			requestid.Logf(ctx, "Geocoding %q found nothing, but %q did.\n", search, variant)
+			span.AddField("resolved_variant", variant)
+			return featureLocation(feature), nil
+		}
+	}
+
+	location, suggestions, err := fuzzyGeocode(ctx, search, kind)
+	if err != nil {
+		// Mapbox failing shouldn't hide the fact that the place wasn't found. Running out of budget, or not having a
+		// key at all, is expected and not worth logging.
+		if !errors.Is(err, mapbox.ErrBudgetExhausted) && !errors.Is(err, mapbox.ErrNotConfigured) {
+			requestid.Logf(ctx, "fuzzy geocoding %q failed: %v\n", search, err)
+		}
+		span.AddField("error", err)
+	}
+	if location != nil {
+		span.AddField("resolved_fuzzy", location.Name)
+		return *location, nil
+	}
+	span.AddField("suggestions", suggestions)
+	return Location{}, &NotFoundError{Search: search, Suggestions: suggestions}
+}
+
+// searchVariants returns alternative spellings of the search worth trying, without duplicates or the original.
+func searchVariants(search string) []string {
+	seen := map[string]bool{strings.ToLower(search): true}
+	var variants []string
+	add := func(s string) {
+		s = strings.Join(strings.Fields(strings.Trim(s, " ,")), " ")
+		if s == "" || seen[strings.ToLower(s)] {
+			return
+		}
+		seen[strings.ToLower(s)] = true
+		variants = append(variants, s)
+	}
+
+	stripped := qualifierPattern.ReplaceAllString(search, " ")
+	add(stripped)
+	if before, _, ok := strings.Cut(stripped, ","); ok {
+		add(before)
+	}
+	add(transliterate(stripped))
+	return variants
+}
+
+// specialLetters covers the letters that don't decompose into an ASCII base letter plus diacritics.
+var specialLetters = strings.NewReplacer("ß", "ss", "æ", "ae", "Æ", "Ae", "ø", "o", "Ø", "O", "ł", "l", "Ł", "L", "đ", "d", "Đ", "D", "þ", "th", "œ", "oe")
+
+// transliterate strips diacritics from the search, e.g. "Zürich" becomes "Zurich".
+func transliterate(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, specialLetters.Replace(s))
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// fuzzyGeocode asks Mapbox, which is more forgiving of misspellings than photon.
+func fuzzyGeocode(ctx context.Context, search string, kind PlaceKind) (*Location, []string, error) {
+	return mapboxGeocode(ctx, search, kind.mapboxTypes())
+}
+
+// mapboxGeocode searches Mapbox for places of the given types. If Mapbox is confident about its top result, that
+// location is returned; otherwise its candidates are returned as suggestions.
+func mapboxGeocode(ctx context.Context, search, types string) (*Location, []string, error) {
+	params := url.Values{}
+	params.Set("fuzzyMatch", "true")
+	params.Set("types", types)
+	params.Set("limit", fmt.Sprint(maxSuggestions))
+	if location := query.CoarseLocationFromContext(ctx); location != nil {
+		params.Set("proximity", fmt.Sprintf("%f,%f", location.Lon, location.Lat))
+	}
+	collection, err := mapbox.GeocodeRequest(ctx, search, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(collection.Features) == 0 {
+		return nil, nil, nil
+	}
+	top := collection.Features[0]
+	if top.Relevance >= mapboxConfidentRelevance && len(top.Center) == 2 {
+		return &Location{Lat: top.Center[1], Lon: top.Center[0], Name: top.PlaceName}, nil, nil
+	}
+	var suggestions []string
+	for _, f := range collection.Features {
+		suggestions = append(suggestions, f.PlaceName)
+	}
+	return nil, suggestions, nil
+}
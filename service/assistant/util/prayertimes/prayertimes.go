@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prayertimes calculates the times of the five daily Islamic prayers, using the same astronomical approach as
+// PrayTimes.org. Times are approximate to within a minute or two; local mosques may add their own adjustments.
+package prayertimes
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Method is a convention for the sun's angle below the horizon at Fajr and Isha. Different regions follow different
+// conventions, which can put Fajr and Isha half an hour or more apart.
+type Method struct {
+	Name string
+	// The sun's angle below the horizon at Fajr.
+	FajrAngle float64
+	// The sun's angle below the horizon at Isha. Ignored if IshaMinutes is set.
+	IshaAngle float64
+	// If set, Isha is this long after Maghrib, rather than being worked out from an angle.
+	IshaMinutes float64
+	// If set, Maghrib is when the sun is this far below the horizon, rather than at sunset.
+	MaghribAngle float64
+}
+
+// Methods are the calculation methods we know about, by the IDs used to pick them.
+var Methods = map[string]Method{
+	"mwl":     {Name: "Muslim World League", FajrAngle: 18, IshaAngle: 17},
+	"isna":    {Name: "Islamic Society of North America", FajrAngle: 15, IshaAngle: 15},
+	"egypt":   {Name: "Egyptian General Authority of Survey", FajrAngle: 19.5, IshaAngle: 17.5},
+	"makkah":  {Name: "Umm al-Qura University, Makkah", FajrAngle: 18.5, IshaMinutes: 90},
+	"karachi": {Name: "University of Islamic Sciences, Karachi", FajrAngle: 18, IshaAngle: 18},
+	"tehran":  {Name: "Institute of Geophysics, University of Tehran", FajrAngle: 17.7, IshaAngle: 14, MaghribAngle: 4.5},
+	"jafari":  {Name: "Shia Ithna-Ashari, Leva Institute, Qum", FajrAngle: 16, IshaAngle: 14, MaghribAngle: 4},
+	"diyanet": {Name: "Diyanet İşleri Başkanlığı, Turkey", FajrAngle: 18, IshaAngle: 17},
+}
+
+// MethodIDs returns the IDs of all the methods, sorted.
+func MethodIDs() []string {
+	var ids []string
+	for id := range Methods {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Asr conventions differ in how long an object's shadow must be, relative to the object, for Asr to begin.
+const (
+	AsrStandard = "standard"
+	AsrHanafi   = "hanafi"
+)
+
+// Times are a single day's prayer times, along with sunrise, which marks the end of the time for Fajr.
+type Times struct {
+	Fajr    time.Time
+	Sunrise time.Time
+	Dhuhr   time.Time
+	Asr     time.Time
+	Maghrib time.Time
+	Isha    time.Time
+}
+
+// riseSetAngle is how far below the horizon the centre of the sun is at sunrise and sunset, allowing for refraction
+// and the size of the sun's disc.
+const riseSetAngle = 0.833
+
+// Calculate returns the prayer times on the given date at the given place, in loc. Only the date is taken from date.
+func Calculate(date time.Time, lat, lon float64, loc *time.Location, method Method, asr string) Times {
+	year, month, day := date.Date()
+	jd := julianDay(year, int(month), day) - lon/(15*24)
+	asrFactor := 1.0
+	if asr == AsrHanafi {
+		asrFactor = 2
+	}
+
+	// The sun's position depends on the time of day, so make a first guess and then refine it.
+	hours := [6]float64{5, 6, 12, 13, 18, 18}
+	for range 2 {
+		t := func(i int) float64 { return jd + hours[i]/24 }
+		fajr := sunAngleTime(t(0), lat, method.FajrAngle, true)
+		sunrise := sunAngleTime(t(1), lat, riseSetAngle, true)
+		dhuhr := midDay(t(2))
+		asrTime := asrTime(t(3), lat, asrFactor)
+		sunset := sunAngleTime(t(4), lat, riseSetAngle, false)
+		maghrib := sunset
+		if method.MaghribAngle != 0 {
+			maghrib = sunAngleTime(t(4), lat, method.MaghribAngle, false)
+		}
+		var isha float64
+		if method.IshaMinutes != 0 {
+			isha = maghrib + method.IshaMinutes/60
+		} else {
+			isha = sunAngleTime(t(5), lat, method.IshaAngle, false)
+		}
+		hours = [6]float64{fajr, sunrise, dhuhr, asrTime, maghrib, isha}
+		adjustHighLatitudes(&hours, sunrise, sunset, method)
+	}
+
+	midnightUTC := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	at := func(h float64) time.Time {
+		// The calculations are in local solar time, so shift to UTC by the longitude.
+		utc := h - lon/15
+		return midnightUTC.Add(time.Duration(utc * float64(time.Hour))).Round(time.Minute).In(loc)
+	}
+	return Times{
+		Fajr:    at(hours[0]),
+		Sunrise: at(hours[1]),
+		Dhuhr:   at(hours[2]),
+		Asr:     at(hours[3]),
+		Maghrib: at(hours[4]),
+		Isha:    at(hours[5]),
+	}
+}
+
+// adjustHighLatitudes stops Fajr and Isha drifting into the middle of the night, or not happening at all, far from
+// the equator in summer. It uses the angle-based method: the night is divided up in proportion to the angle.
+func adjustHighLatitudes(hours *[6]float64, sunrise, sunset float64, method Method) {
+	night := 24 - (sunset - sunrise)
+	if math.IsNaN(night) {
+		// Midnight sun, or polar night. There's nothing sensible to do.
+		return
+	}
+	fajrPortion := method.FajrAngle / 60 * night
+	if math.IsNaN(hours[0]) || sunrise-hours[0] > fajrPortion {
+		hours[0] = sunrise - fajrPortion
+	}
+	if method.IshaMinutes == 0 {
+		ishaPortion := method.IshaAngle / 60 * night
+		if math.IsNaN(hours[5]) || hours[5]-sunset > ishaPortion {
+			hours[5] = sunset + ishaPortion
+		}
+	}
+	if method.MaghribAngle != 0 {
+		maghribPortion := method.MaghribAngle / 60 * night
+		if math.IsNaN(hours[4]) || hours[4]-sunset > maghribPortion {
+			hours[4] = sunset + maghribPortion
+		}
+	}
+}
+
+func julianDay(year, month, day int) float64 {
+	if month <= 2 {
+		year--
+		month += 12
+	}
+	a := math.Floor(float64(year) / 100)
+	b := 2 - a + math.Floor(a/4)
+	return math.Floor(365.25*float64(year+4716)) + math.Floor(30.6001*float64(month+1)) + float64(day) + b - 1524.5
+}
+
+func sin(d float64) float64    { return math.Sin(d * math.Pi / 180) }
+func cos(d float64) float64    { return math.Cos(d * math.Pi / 180) }
+func tan(d float64) float64    { return math.Tan(d * math.Pi / 180) }
+func arcsin(x float64) float64 { return math.Asin(x) * 180 / math.Pi }
+func arccos(x float64) float64 { return math.Acos(x) * 180 / math.Pi }
+func arccot(x float64) float64 { return math.Atan(1/x) * 180 / math.Pi }
+func arctan2(y, x float64) float64 {
+	return math.Atan2(y, x) * 180 / math.Pi
+}
+
+func fix(a, b float64) float64 {
+	a = a - b*math.Floor(a/b)
+	if a < 0 {
+		return a + b
+	}
+	return a
+}
+
+// sunPosition returns the sun's declination and the equation of time at the given Julian day.
+func sunPosition(jd float64) (declination, equationOfTime float64) {
+	d := jd - 2451545.0
+	g := fix(357.529+0.98560028*d, 360)
+	q := fix(280.459+0.98564736*d, 360)
+	l := fix(q+1.915*sin(g)+0.020*sin(2*g), 360)
+	e := 23.439 - 0.00000036*d
+	ra := arctan2(cos(e)*sin(l), cos(l)) / 15
+	equationOfTime = q/15 - fix(ra, 24)
+	declination = arcsin(sin(e) * sin(l))
+	return declination, equationOfTime
+}
+
+// midDay returns the local solar time at which the sun crosses the meridian.
+func midDay(jd float64) float64 {
+	_, eqt := sunPosition(jd)
+	return fix(12-eqt, 24)
+}
+
+// sunAngleTime returns the local solar time at which the sun is the given angle below the horizon, before noon if
+// beforeNoon is set, and after it if not. It's NaN if the sun never gets that low.
+func sunAngleTime(jd, lat, angle float64, beforeNoon bool) float64 {
+	decl, _ := sunPosition(jd)
+	noon := midDay(jd)
+	t := arccos((-sin(angle)-sin(decl)*sin(lat))/(cos(decl)*cos(lat))) / 15
+	if beforeNoon {
+		return noon - t
+	}
+	return noon + t
+}
+
+// asrTime returns the local solar time at which shadows reach the given multiple of their objects' length, plus their
+// length at noon.
+func asrTime(jd, lat, factor float64) float64 {
+	decl, _ := sunPosition(jd)
+	angle := -arccot(factor + tan(math.Abs(lat-decl)))
+	return sunAngleTime(jd, lat, angle, false)
+}
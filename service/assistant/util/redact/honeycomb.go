@@ -16,6 +16,7 @@ var sensitiveQueryParams = []string{
 	"proximity", // the target location for a POI lookup
 	"tzOffset",  // user's timezone offset as sent to us
 	"token",     // user's auth (timeline) token, identifies them uniquely.
+	"scheduled", // the token marking a session as a scheduled query
 }
 var mapboxPathRegex = regexp.MustCompile(`^/geocoding/v5/mapbox\.places/.+?.json$`)
 
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package requestid gives each incoming request an ID that follows it everywhere: into traces, log lines, the
+// requests we make upstream, and the errors we send back. When a user reports that something failed, the ID (or just
+// the time) is enough to find what happened.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/honeycombio/beeline-go"
+)
+
+// Header is the HTTP header request IDs are sent in, and accepted from a proxy in front of us.
+const Header = "X-Request-ID"
+
+// validID matches IDs we're willing to accept from a proxy. Anything else is replaced, so that an ID can't be used to
+// inject anything into logs.
+var validID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type contextKey struct{}
+
+// New returns a new random request ID. It's short, so that users can read it off a watch.
+func New() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Middleware gives every request passing through it an ID, reusing one set by a proxy if there is one. The ID is
+// added to the request's context and trace, and returned in the response's headers.
+func Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !validID.MatchString(id) {
+			id = New()
+		}
+		rw.Header().Set(Header, id)
+		h.ServeHTTP(rw, r.WithContext(WithID(r.Context(), id)))
+	})
+}
+
+// WithID returns a context carrying the request ID, and adds it to every span in the context's trace.
+func WithID(ctx context.Context, id string) context.Context {
+	beeline.AddFieldToTrace(ctx, "request_id", id)
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or "" if there isn't one.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logf is log.Printf, but prefixed with the request ID in ctx, if any.
+func Logf(ctx context.Context, format string, args ...any) {
+	if id := FromContext(ctx); id != "" {
+		format = "[" + id + "] " + format
+	}
+	log.Printf(format, args...)
+}
+
+// Logln is log.Println, but prefixed with the request ID in ctx, if any.
+func Logln(ctx context.Context, args ...any) {
+	if id := FromContext(ctx); id != "" {
+		args = append([]any{"[" + id + "]"}, args...)
+	}
+	log.Println(args...)
+}
+
+// Reference formats the request ID in ctx for the end of an error message shown to a user, e.g. " [ref 1a2b3c4d5e6f]",
+// or returns "" if there isn't one.
+func Reference(ctx context.Context) string {
+	id := FromContext(ctx)
+	if id == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [ref %s]", id)
+}
+
+// Transport adds the request ID from each request's context to its headers before passing it on to Base, or
+// http.DefaultTransport if Base is nil.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id := FromContext(req.Context()); id != "" && req.Header.Get(Header) == "" {
+		// RoundTrippers mustn't modify the request they're given.
+		req = req.Clone(req.Context())
+		req.Header.Set(Header, id)
+	}
+	return base.RoundTrip(req)
+}
+
+// Client is an http.Client that sends the request ID along with every request.
+var Client = &http.Client{Transport: &Transport{}}
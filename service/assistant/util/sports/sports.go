@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sports looks up teams' fixtures and scores from TheSportsDB.
+package sports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// defaultAPIKey is TheSportsDB's free public key, used unless SPORTS_DB_KEY says otherwise.
+const defaultAPIKey = "3"
+
+// eventCacheTime is how long to keep an event before asking again. Scores don't need to be fresher than this, and
+// every watch showing the same match shares the cached copy.
+const eventCacheTime = 30 * time.Second
+
+// maxEventLength is how long after an event starts we assume it's over, if we aren't told.
+const maxEventLength = 3 * time.Hour
+
+var ErrUnknownTeam = errors.New("unknown team")
+var ErrUnknownEvent = errors.New("unknown event")
+
+// State is how far along an event is.
+type State string
+
+const (
+	StateScheduled State = "scheduled"
+	StateLive      State = "live"
+	StateFinished  State = "finished"
+	StatePostponed State = "postponed"
+)
+
+// Event is a single match or game.
+type Event struct {
+	ID       string `json:"id"`
+	Sport    string `json:"sport"`
+	League   string `json:"league"`
+	HomeTeam string `json:"home_team"`
+	AwayTeam string `json:"away_team"`
+	// Scores are nil until the event has started.
+	HomeScore *int  `json:"home_score,omitempty"`
+	AwayScore *int  `json:"away_score,omitempty"`
+	State     State `json:"state"`
+	// How far into a live event it is, if the provider says, e.g. "67'".
+	Progress string    `json:"progress,omitempty"`
+	Start    time.Time `json:"start"`
+}
+
+// apiEvent is an event as TheSportsDB returns it. Numbers come back as strings, and missing values as nulls.
+type apiEvent struct {
+	ID        string  `json:"idEvent"`
+	Sport     string  `json:"strSport"`
+	League    string  `json:"strLeague"`
+	HomeTeam  string  `json:"strHomeTeam"`
+	AwayTeam  string  `json:"strAwayTeam"`
+	HomeScore *string `json:"intHomeScore"`
+	AwayScore *string `json:"intAwayScore"`
+	Status    string  `json:"strStatus"`
+	Progress  string  `json:"strProgress"`
+	Timestamp string  `json:"strTimestamp"`
+	Date      string  `json:"dateEvent"`
+	Time      string  `json:"strTime"`
+	Postponed string  `json:"strPostponed"`
+}
+
+type apiTeam struct {
+	ID     string `json:"idTeam"`
+	Name   string `json:"strTeam"`
+	Sport  string `json:"strSport"`
+	League string `json:"strLeague"`
+}
+
+// TeamEvents returns the full name of the team best matching the given name, along with its most recent results and
+// its next fixtures, in the order they're played.
+func TeamEvents(ctx context.Context, team string) (string, []Event, error) {
+	ctx, span := beeline.StartSpan(ctx, "sports.team_events")
+	defer span.Send()
+	var teams struct {
+		Teams []apiTeam `json:"teams"`
+	}
+	if err := get(ctx, "searchteams.php", url.Values{"t": {team}}, &teams); err != nil {
+		return "", nil, err
+	}
+	if len(teams.Teams) == 0 {
+		return "", nil, ErrUnknownTeam
+	}
+	t := teams.Teams[0]
+	span.AddField("team_id", t.ID)
+
+	var last struct {
+		Results []apiEvent `json:"results"`
+	}
+	if err := get(ctx, "eventslast.php", url.Values{"id": {t.ID}}, &last); err != nil {
+		return "", nil, err
+	}
+	var next struct {
+		Events []apiEvent `json:"events"`
+	}
+	if err := get(ctx, "eventsnext.php", url.Values{"id": {t.ID}}, &next); err != nil {
+		return "", nil, err
+	}
+	var events []Event
+	for _, e := range append(last.Results, next.Events...) {
+		events = append(events, e.event())
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return t.Name, events, nil
+}
+
+// LookupEvent returns the event with the given ID, as of at most eventCacheTime ago.
+func LookupEvent(ctx context.Context, id string) (*Event, error) {
+	ctx, span := beeline.StartSpan(ctx, "sports.lookup_event")
+	defer span.Send()
+	span.AddField("event_id", id)
+	rd := storage.GetRedis()
+	if cached, err := rd.Get(ctx, cacheKey(id)).Result(); err == nil {
+		var e Event
+		if err := json.Unmarshal([]byte(cached), &e); err == nil {
+			span.AddField("cached", true)
+			return &e, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	var result struct {
+		Events []apiEvent `json:"events"`
+	}
+	if err := get(ctx, "lookupevent.php", url.Values{"id": {id}}, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Events) == 0 || result.Events[0].ID != id {
+		return nil, ErrUnknownEvent
+	}
+	e := result.Events[0].event()
+	if encoded, err := json.Marshal(e); err == nil {
+		if err := rd.Set(ctx, cacheKey(id), encoded, eventCacheTime).Err(); err != nil {
+			span.AddField("cache_error", err)
+		}
+	}
+	return &e, nil
+}
+
+func cacheKey(id string) string {
+	return "sports_event:" + id
+}
+
+func get(ctx context.Context, endpoint string, params url.Values, result any) error {
+	key := config.GetConfig().SportsDBKey
+	if key == "" {
+		key = defaultAPIKey
+	}
+	u := fmt.Sprintf("https://www.thesportsdb.com/api/v1/json/%s/%s?%s", url.PathEscape(key), endpoint, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := upstream.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := upstream.CheckStatus("thesportsdb", resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (e apiEvent) event() Event {
+	event := Event{
+		ID:        e.ID,
+		Sport:     e.Sport,
+		League:    e.League,
+		HomeTeam:  e.HomeTeam,
+		AwayTeam:  e.AwayTeam,
+		HomeScore: parseScore(e.HomeScore),
+		AwayScore: parseScore(e.AwayScore),
+		Progress:  e.Progress,
+		Start:     e.start(),
+	}
+	event.State = e.state(event)
+	return event
+}
+
+// start returns when the event starts. Timestamps are in UTC, but don't always say so.
+func (e apiEvent) start() time.Time {
+	if e.Timestamp != "" {
+		ts := strings.TrimSuffix(strings.TrimSuffix(e.Timestamp, "Z"), "+00:00")
+		if t, err := time.Parse("2006-01-02T15:04:05", ts); err == nil {
+			return t
+		}
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05", e.Date+" "+e.Time); err == nil {
+		return t
+	}
+	t, _ := time.Parse(time.DateOnly, e.Date)
+	return t
+}
+
+func (e apiEvent) state(event Event) State {
+	switch strings.ToUpper(e.Status) {
+	case "NS", "NOT STARTED", "TBD":
+		return StateScheduled
+	case "FT", "AET", "PEN", "AP", "MATCH FINISHED", "FINISHED", "AOT", "AW":
+		return StateFinished
+	case "PST", "CANC", "ABD", "SUSP", "POSTPONED", "CANCELLED", "ABANDONED":
+		return StatePostponed
+	case "1H", "HT", "2H", "ET", "BT", "P", "LIVE", "IN PROGRESS", "Q1", "Q2", "Q3", "Q4", "OT":
+		return StateLive
+	}
+	if e.Postponed == "yes" {
+		return StatePostponed
+	}
+	// The status isn't always filled in, so fall back to guessing from the time.
+	switch {
+	case time.Now().Before(event.Start):
+		return StateScheduled
+	case time.Since(event.Start) < maxEventLength:
+		return StateLive
+	default:
+		return StateFinished
+	}
+}
+
+func parseScore(s *string) *int {
+	if s == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(*s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timezone works out which IANA timezone a place is in from its coordinates, for anything that needs to know
+// the local time somewhere other than where the user is.
+//
+// It looks the coordinates up in the zone boundaries from timezone-boundary-builder, which are embedded in the binary,
+// so it's right on both sides of a border. The boundaries are simplified a little to keep them small, which only
+// matters within a few hundred metres of one.
+package timezone
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+	// The zones are looked up by name, so the database has to be there even on machines without one.
+	_ "time/tzdata"
+
+	"github.com/ringsaturn/tzf"
+)
+
+var (
+	finderOnce sync.Once
+	finder     tzf.F
+)
+
+// loadFinder loads the boundaries the first time they're needed. They take a while to load, and tens of megabytes
+// once they have, so there's no point doing it in processes that never ask.
+func loadFinder() tzf.F {
+	finderOnce.Do(func() {
+		var err error
+		finder, err = tzf.NewDefaultFinder()
+		if err != nil {
+			panic(fmt.Sprintf("timezone: loading the zone boundaries failed: %v", err))
+		}
+	})
+	return finder
+}
+
+// Name returns the name of the timezone at the given coordinates, e.g. "Europe/London". Out at sea, it's the nautical
+// timezone for the longitude, e.g. "Etc/GMT+5".
+func Name(lat, lon float64) string {
+	if name := loadFinder().GetTimezoneName(lon, lat); name != "" {
+		return name
+	}
+	return nauticalZone(lon)
+}
+
+// nauticalZone returns the Etc zone for the longitude. Their signs are the opposite of what you'd expect: Etc/GMT+5
+// is five hours behind UTC.
+func nauticalZone(lon float64) string {
+	hours := int(math.Round(lon / 15))
+	switch {
+	case hours == 0:
+		return "Etc/GMT"
+	case hours > 0:
+		return fmt.Sprintf("Etc/GMT-%d", min(hours, 12))
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", min(-hours, 12))
+	}
+}
+
+// Location returns the timezone at the given coordinates.
+func Location(lat, lon float64) *time.Location {
+	loc, err := time.LoadLocation(Name(lat, lon))
+	if err != nil {
+		// The boundaries are newer than the embedded database, and name a zone it doesn't have yet. Going by the
+		// longitude is at least close.
+		loc, _ = time.LoadLocation(nauticalZone(lon))
+	}
+	return loc
+}
+
+// Now returns the current time at the given coordinates.
+func Now(lat, lon float64) time.Time {
+	return time.Now().In(Location(lat, lon))
+}
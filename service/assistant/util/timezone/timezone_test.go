@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timezone
+
+import "testing"
+
+// TestName checks places either side of borders between zones, where the nearest big city is often in the wrong one.
+func TestName(t *testing.T) {
+	tests := []struct {
+		place    string
+		lat, lon float64
+		want     string
+	}{
+		{"London", 51.5072, -0.1276, "Europe/London"},
+		{"San Diego", 32.7157, -117.1611, "America/Los_Angeles"},
+		{"Tijuana", 32.5149, -117.0382, "America/Tijuana"},
+		{"Louisville", 38.2527, -85.7585, "America/Kentucky/Louisville"},
+		{"Jeffersonville, across the river in Indiana", 38.2776, -85.7372, "America/Kentucky/Louisville"},
+		{"Evansville, in Indiana's Central time corner", 37.9716, -87.5711, "America/Chicago"},
+		{"El Paso", 31.7619, -106.4850, "America/Denver"},
+		{"Ciudad Juárez", 31.6904, -106.4245, "America/Ciudad_Juarez"},
+		{"mid-Atlantic", 30, -40, "Etc/GMT+3"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.lat, tt.lon); got != tt.want {
+			t.Errorf("Name(%v, %v) for %s = %q, want %q", tt.lat, tt.lon, tt.place, got, tt.want)
+		}
+	}
+}
+
+func TestLocationIsLoadable(t *testing.T) {
+	if loc := Location(-33.8688, 151.2093); loc.String() != "Australia/Sydney" {
+		t.Errorf("Location for Sydney = %v, want Australia/Sydney", loc)
+	}
+}
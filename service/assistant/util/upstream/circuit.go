@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/alerting"
+)
+
+const (
+	// circuitThreshold is how many failures in a row it takes to decide a provider is down.
+	circuitThreshold = 5
+	// circuitCooldown is how long we stop calling a provider that's down before trying it again.
+	circuitCooldown = 30 * time.Second
+)
+
+// CircuitOpenError is returned instead of making a request to a provider that has been failing, so that a provider
+// that's down doesn't make every turn that needs it wait for a timeout.
+type CircuitOpenError struct {
+	Provider string
+	// When we'll next try the provider.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s is unavailable", e.Provider)
+}
+
+// IsCircuitOpen reports whether err is because we've stopped calling a provider that's down.
+func IsCircuitOpen(err error) bool {
+	var circuitErr *CircuitOpenError
+	return errors.As(err, &circuitErr)
+}
+
+// IsUnavailable reports whether err means the provider couldn't be reached or couldn't answer, rather than that
+// something was wrong with the request, so that a caller can fall back to older data.
+func IsUnavailable(err error) bool {
+	return IsCircuitOpen(err) || IsTransient(err)
+}
+
+// allow returns a CircuitOpenError if the provider has been failing and its cooldown hasn't finished.
+func (p *Provider) allow(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Now().Before(p.openUntil) {
+		beeline.AddField(ctx, "upstream_circuit_open", p.Name)
+		return &CircuitOpenError{Provider: p.Name, Until: p.openUntil}
+	}
+	return nil
+}
+
+// record notes how a request to the provider went. Once it has failed circuitThreshold times in a row, requests are
+// refused until circuitCooldown has passed. After that, a single failure is enough to refuse them again, until a
+// request succeeds. Operators are alerted the first time the circuit opens, but not again until the provider has
+// recovered, so that a long outage doesn't send an alert every cooldown.
+func (p *Provider) record(ctx context.Context, resp *http.Response, err error) {
+	if ctx.Err() != nil {
+		// We gave up on the request ourselves, which says nothing about the provider.
+		return
+	}
+	failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !failed {
+		p.failures = 0
+		return
+	}
+	p.failures++
+	if p.failures >= circuitThreshold {
+		p.openUntil = time.Now().Add(circuitCooldown)
+		beeline.AddField(ctx, "upstream_circuit_opened", p.Name)
+		if p.failures == circuitThreshold {
+			go alertCircuitOpen(p.Name, err, resp)
+		}
+	}
+}
+
+func alertCircuitOpen(provider string, err error, resp *http.Response) {
+	var cause string
+	if err != nil {
+		cause = err.Error()
+	} else {
+		cause = fmt.Sprintf("HTTP status %d", resp.StatusCode)
+	}
+	alertErr := alerting.Send(context.Background(), alerting.Alert{
+		Severity: alerting.SeverityWarning,
+		Source:   "upstream",
+		Summary:  fmt.Sprintf("%s is down", provider),
+		Details:  fmt.Sprintf("%d requests in a row failed, most recently with: %s. Requests to it are being refused for %s at a time until one succeeds.", circuitThreshold, cause, circuitCooldown),
+	})
+	if alertErr != nil {
+		log.Printf("Sending circuit alert failed: %v", alertErr)
+	}
+}
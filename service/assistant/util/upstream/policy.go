@@ -0,0 +1,237 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+)
+
+// Attribution is a credit that a provider's terms require us to show wherever its data is used.
+type Attribution struct {
+	Provider string `json:"provider"`
+	Text     string `json:"text"`
+	URL      string `json:"url,omitempty"`
+}
+
+// Provider describes the terms we use an upstream API under.
+type Provider struct {
+	Name string
+	// The hostnames the provider's API is served from. Subdomains match too.
+	Hosts []string
+	// The shortest time to leave between starting two requests, across the whole server. Zero means no limit.
+	MinInterval time.Duration
+	// The credit to show when the provider's data is used, if it requires one.
+	Attribution *Attribution
+
+	mu   sync.Mutex
+	next time.Time
+	// How many requests in a row have failed, and when we'll next try the provider if it's down. See record.
+	failures  int
+	openUntil time.Time
+}
+
+// providers are the upstream APIs we know the terms of. The intervals keep us comfortably inside the free tiers.
+var providers = []*Provider{
+	{
+		Name:        "open-meteo",
+		Hosts:       []string{"api.open-meteo.com"},
+		MinInterval: 100 * time.Millisecond,
+		Attribution: &Attribution{Provider: "open-meteo", Text: "Weather data by Open-Meteo.com", URL: "https://open-meteo.com/"},
+	},
+	{
+		Name:        "photon",
+		Hosts:       []string{"photon.komoot.io"},
+		MinInterval: 200 * time.Millisecond,
+		Attribution: &Attribution{Provider: "photon", Text: "© OpenStreetMap contributors", URL: "https://www.openstreetmap.org/copyright"},
+	},
+	{
+		Name:        "mapbox",
+		Hosts:       []string{"api.mapbox.com"},
+		MinInterval: 100 * time.Millisecond,
+		Attribution: &Attribution{Provider: "mapbox", Text: "© Mapbox © OpenStreetMap", URL: "https://www.mapbox.com/about/maps/"},
+	},
+	{
+		Name:  "wikipedia",
+		Hosts: []string{"wikipedia.org"},
+	},
+	{
+		Name:        "nager.date",
+		Hosts:       []string{"date.nager.at"},
+		MinInterval: 100 * time.Millisecond,
+	},
+	{
+		Name:        "thesportsdb",
+		Hosts:       []string{"thesportsdb.com"},
+		MinInterval: 2 * time.Second,
+		Attribution: &Attribution{Provider: "thesportsdb", Text: "Sports data by TheSportsDB.com", URL: "https://www.thesportsdb.com/"},
+	},
+	{
+		Name:        "dictionaryapi",
+		Hosts:       []string{"api.dictionaryapi.dev"},
+		MinInterval: 200 * time.Millisecond,
+	},
+	{
+		Name:        "usgs",
+		Hosts:       []string{"earthquake.usgs.gov"},
+		MinInterval: 200 * time.Millisecond,
+		Attribution: &Attribution{Provider: "usgs", Text: "Earthquake data from the U.S. Geological Survey", URL: "https://earthquake.usgs.gov/"},
+	},
+	{
+		Name:  "openfoodfacts",
+		Hosts: []string{"openfoodfacts.org"},
+		// Open Food Facts asks for no more than 100 product lookups a minute.
+		MinInterval: 600 * time.Millisecond,
+		Attribution: &Attribution{Provider: "openfoodfacts", Text: "Product data from Open Food Facts, under the ODbL", URL: "https://world.openfoodfacts.org/"},
+	},
+}
+
+func providerForHost(host string) *Provider {
+	for _, p := range providers {
+		for _, h := range p.Hosts {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// wait blocks until the provider's rate limit allows another request, or the context is done.
+func (p *Provider) wait(ctx context.Context) error {
+	if p.MinInterval == 0 {
+		return nil
+	}
+	p.mu.Lock()
+	now := time.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(p.MinInterval)
+	p.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	beeline.AddField(ctx, "upstream_rate_limit_delay_ms", delay.Milliseconds())
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// UserAgent returns the User-Agent we send upstream. Providers ask for one that lets them get in touch with whoever
+// runs the deployment, which UPSTREAM_CONTACT provides.
+func UserAgent() string {
+	contact := config.GetConfig().UpstreamContact
+	if contact == "" {
+		contact = "https://github.com/pebble-dev/bobby-assistant"
+	}
+	return "BobbyAssistant/1.0 (" + contact + ")"
+}
+
+type policyTransport struct {
+	base http.RoundTripper
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	provider := providerForHost(req.URL.Hostname())
+	if provider != nil {
+		if err := provider.allow(req.Context()); err != nil {
+			return nil, err
+		}
+		if err := provider.wait(req.Context()); err != nil {
+			return nil, err
+		}
+		recordAttribution(req.Context(), provider)
+		// Whether connections are being reused shows whether TuneTransport's pool is big enough.
+		ctx := req.Context()
+		req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				beeline.AddField(ctx, "upstream."+provider.Name+".conn_reused", info.Reused)
+			},
+		}))
+	}
+	if req.Header.Get("User-Agent") == "" {
+		// RoundTrippers mustn't modify the request they're given.
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", UserAgent())
+	}
+	resp, err := t.base.RoundTrip(req)
+	if provider != nil {
+		provider.record(req.Context(), resp, err)
+	}
+	return resp, err
+}
+
+// Client should be used for all requests to third-party APIs. It identifies us with UserAgent, keeps to each known
+// provider's rate limit, stops calling providers that are down (see CircuitOpenError), records the attributions for
+// any providers used (see WithAttributions), and passes on the request ID.
+var Client = &http.Client{Transport: &policyTransport{base: &requestid.Transport{Base: http.DefaultTransport}}}
+
+type attributionCollector struct {
+	mu           sync.Mutex
+	attributions []Attribution
+}
+
+type attributionCollectorKey struct{}
+
+// WithAttributions returns a context in which requests made with Client record the attributions their providers
+// require, for AttributionsFromContext to return.
+func WithAttributions(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attributionCollectorKey{}, &attributionCollector{})
+}
+
+func recordAttribution(ctx context.Context, p *Provider) {
+	if p.Attribution == nil {
+		return
+	}
+	collector, ok := ctx.Value(attributionCollectorKey{}).(*attributionCollector)
+	if !ok {
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for _, a := range collector.attributions {
+		if a.Provider == p.Attribution.Provider {
+			return
+		}
+	}
+	collector.attributions = append(collector.attributions, *p.Attribution)
+}
+
+// AttributionsFromContext returns the attributions for every provider used so far, in the order they were first used.
+func AttributionsFromContext(ctx context.Context) []Attribution {
+	collector, ok := ctx.Value(attributionCollectorKey{}).(*attributionCollector)
+	if !ok {
+		return nil
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return append([]Attribution(nil), collector.attributions...)
+}
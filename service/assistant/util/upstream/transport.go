@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Go's default transport keeps only two idle connections to each host, so when a turn makes several tool calls at
+// once, most of them pay for a new TCP and TLS handshake. These are sized for a whole server's worth of users hitting
+// the same handful of APIs.
+const (
+	maxIdleConns        = 128
+	maxIdleConnsPerHost = 32
+	idleConnTimeout     = 90 * time.Second
+	// How long a DNS lookup for one of cachedHosts is reused. The hosts are all behind CDNs or load balancers that
+	// change their addresses rarely, and an address that can't be connected to is dropped anyway.
+	dnsCacheTTL = time.Minute
+)
+
+// cachedHosts are the hosts called on almost every turn, whose DNS lookups are cached.
+var cachedHosts = map[string]bool{
+	"api.open-meteo.com":                true,
+	"photon.komoot.io":                  true,
+	"generativelanguage.googleapis.com": true,
+}
+
+// TuneTransport sets up t to keep connections to upstream APIs open between requests, and to cache DNS lookups for
+// the busiest of them. It should be called on http.DefaultTransport once, at startup, before any requests are made.
+func TuneTransport(t *http.Transport) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	cache := &dnsCache{resolver: net.DefaultResolver, entries: map[string]dnsEntry{}}
+	t.DialContext = cache.dialContext(dialer)
+	// Go only tries HTTP/2 by itself when DialContext hasn't been replaced.
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = maxIdleConns
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	t.IdleConnTimeout = idleConnTimeout
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsCache remembers the addresses of cachedHosts for dnsCacheTTL.
+type dnsCache struct {
+	resolver *net.Resolver
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[host] = dnsEntry{addrs: addrs, expires: time.Now().Add(dnsCacheTTL)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// drop removes an address that couldn't be connected to from the host's cached addresses, so that the next connection
+// doesn't wait on it too. Once none are left, the host is looked up again.
+func (c *dnsCache) drop(host, ip string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[host]
+	if !ok {
+		return
+	}
+	// The slice may be in use by another dial, so it's copied rather than changed.
+	addrs := make([]string, 0, len(entry.addrs))
+	for _, a := range entry.addrs {
+		if a != ip {
+			addrs = append(addrs, a)
+		}
+	}
+	if len(addrs) == 0 {
+		delete(c.entries, host)
+		return
+	}
+	entry.addrs = addrs
+	c.entries[host] = entry
+}
+
+// dialContext returns a DialContext function that dials cachedHosts by their cached addresses, and everything else as
+// usual. Like net.Dialer, it tries the addresses of the first one's family in turn, and after the dialer's
+// FallbackDelay starts on those of the other family alongside them (RFC 6555), taking whichever connects first.
+func (c *dnsCache) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || !cachedHosts[host] {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		addrs, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+		}
+		if dialer.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+			defer cancel()
+		}
+		primaries, fallbacks := partitionAddrs(addrs)
+		delay := dialer.FallbackDelay
+		if delay == 0 {
+			delay = defaultFallbackDelay
+		}
+		if len(fallbacks) == 0 || delay < 0 {
+			return c.dialSerial(ctx, dialer, network, host, port, append(primaries, fallbacks...))
+		}
+		return c.dialParallel(ctx, dialer, network, host, port, primaries, fallbacks, delay)
+	}
+}
+
+// defaultFallbackDelay is how long to wait before trying the other address family, if the dialer doesn't say. It's
+// the same as net.Dialer's.
+const defaultFallbackDelay = 300 * time.Millisecond
+
+// minDialTime is the least time each address gets when dialing several in turn, unless there's less than that left.
+const minDialTime = 2 * time.Second
+
+// partitionAddrs splits addrs into those of the same family as the first, and the rest.
+func partitionAddrs(addrs []string) (primaries, fallbacks []string) {
+	isIPv4 := func(a string) bool {
+		ip := net.ParseIP(a)
+		return ip != nil && ip.To4() != nil
+	}
+	for _, a := range addrs {
+		if len(primaries) == 0 || isIPv4(a) == isIPv4(primaries[0]) {
+			primaries = append(primaries, a)
+		} else {
+			fallbacks = append(fallbacks, a)
+		}
+	}
+	return primaries, fallbacks
+}
+
+// dialParallel races dialing primaries against dialing fallbacks, which starts after delay or as soon as the
+// primaries have all failed.
+func (c *dnsCache) dialParallel(ctx context.Context, dialer *net.Dialer, network, host, port string, primaries, fallbacks []string, delay time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result)
+	start := func(ips []string) {
+		go func() {
+			conn, err := c.dialSerial(ctx, dialer, network, host, port, ips)
+			select {
+			case results <- result{conn, err}:
+			case <-ctx.Done():
+				// The other family won.
+				if conn != nil {
+					_ = conn.Close()
+				}
+			}
+		}()
+	}
+	start(primaries)
+	fallbackTimer := time.NewTimer(delay)
+	defer fallbackTimer.Stop()
+	running, fallbackStarted := 1, false
+	var firstErr error
+	for {
+		select {
+		case <-fallbackTimer.C:
+			if !fallbackStarted {
+				start(fallbacks)
+				fallbackStarted = true
+				running++
+			}
+		case r := <-results:
+			running--
+			if r.err == nil {
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if !fallbackStarted {
+				start(fallbacks)
+				fallbackStarted = true
+				running++
+			} else if running == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
+
+// dialSerial tries each of ips in turn, giving each its share of the time left, and dropping any that fail from the
+// cache.
+func (c *dnsCache) dialSerial(ctx context.Context, dialer *net.Dialer, network, host, port string, ips []string) (net.Conn, error) {
+	var firstErr error
+	for i, ip := range ips {
+		dialCtx, cancel := ctx, context.CancelFunc(func() {})
+		if deadline, ok := ctx.Deadline(); ok {
+			left := time.Until(deadline)
+			share := max(left/time.Duration(len(ips)-i), min(minDialTime, left))
+			dialCtx, cancel = context.WithTimeout(ctx, share)
+		}
+		conn, err := dialer.DialContext(dialCtx, network, net.JoinHostPort(ip, port))
+		cancel()
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			// Giving up isn't the address's fault.
+			return nil, err
+		}
+		c.drop(host, ip)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+const testHost = "api.open-meteo.com"
+
+// listen returns a listener on the loopback address, or skips the test if there's no such address here.
+func listen(t *testing.T, ip string) (net.Listener, string) {
+	l, err := net.Listen("tcp", net.JoinHostPort(ip, "0"))
+	if err != nil {
+		t.Skipf("can't listen on %s: %v", ip, err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	return l, port
+}
+
+func dialCached(t *testing.T, c *dnsCache, port string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	return c.dialContext(dialer)(ctx, "tcp", net.JoinHostPort(testHost, port))
+}
+
+func TestDialDropsFailedAddresses(t *testing.T) {
+	_, port := listen(t, "127.0.0.1")
+	// Nothing listens on 127.0.0.2, so connecting to it is refused.
+	c := &dnsCache{entries: map[string]dnsEntry{testHost: {addrs: []string{"127.0.0.2", "127.0.0.1"}, expires: time.Now().Add(time.Hour)}}}
+	conn, err := dialCached(t, c, port)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	_ = conn.Close()
+	if got := c.entries[testHost].addrs; !slices.Equal(got, []string{"127.0.0.1"}) {
+		t.Errorf("cached addresses = %q, want only the one that worked", got)
+	}
+}
+
+func TestDialFallsBackToOtherFamily(t *testing.T) {
+	_, port := listen(t, "::1")
+	c := &dnsCache{entries: map[string]dnsEntry{testHost: {addrs: []string{"127.0.0.2", "::1"}, expires: time.Now().Add(time.Hour)}}}
+	conn, err := dialCached(t, c, port)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	if ip := conn.RemoteAddr().(*net.TCPAddr).IP; !ip.Equal(net.IPv6loopback) {
+		t.Errorf("connected to %v, want ::1", ip)
+	}
+	_ = conn.Close()
+	if got := c.entries[testHost].addrs; !slices.Equal(got, []string{"::1"}) {
+		t.Errorf("cached addresses = %q, want only the one that worked", got)
+	}
+}
+
+func TestDialForgetsHostWhenEveryAddressFails(t *testing.T) {
+	l, port := listen(t, "127.0.0.1")
+	_ = l.Close()
+	c := &dnsCache{entries: map[string]dnsEntry{testHost: {addrs: []string{"127.0.0.1", "127.0.0.2"}, expires: time.Now().Add(time.Hour)}}}
+	if conn, err := dialCached(t, c, port); err == nil {
+		_ = conn.Close()
+		t.Fatal("dial succeeded, want it to fail")
+	}
+	if _, ok := c.entries[testHost]; ok {
+		t.Errorf("cached addresses = %q, want the host forgotten", c.entries[testHost].addrs)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// defaultBaseURL is Open-Meteo's API, used unless OPEN_METEO_URL says otherwise.
+const defaultBaseURL = "https://api.open-meteo.com"
+
+// RequestTimeout bounds each request to Open-Meteo, so that a slow response can't hold up the whole conversation.
+const RequestTimeout = 10 * time.Second
+
+var (
+	// ErrOutOfCoverage is returned when Open-Meteo has no data for the requested location.
+	ErrOutOfCoverage = errors.New("no weather data is available for that location")
+	// ErrRateLimited is returned when Open-Meteo is refusing requests because we've made too many.
+	ErrRateLimited = errors.New("the weather service is rate limiting requests")
+)
+
+// APIError is returned when Open-Meteo responds with an error. It matches ErrOutOfCoverage or ErrRateLimited with
+// errors.Is where appropriate, and the underlying upstream.StatusError with errors.As.
+type APIError struct {
+	// The reason Open-Meteo gave, if any.
+	Reason string
+	kind   error
+	status *upstream.StatusError
+}
+
+func (e *APIError) Error() string {
+	if e.Reason == "" {
+		return e.status.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.status.Error(), e.Reason)
+}
+
+func (e *APIError) Unwrap() []error {
+	if e.kind == nil {
+		return []error{e.status}
+	}
+	return []error{e.kind, e.status}
+}
+
+// openMeteoError is the body Open-Meteo sends along with error statuses.
+type openMeteoError struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// baseURL returns where to find Open-Meteo, without a trailing slash.
+func baseURL() string {
+	if u := config.GetConfig().OpenMeteoURL; u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	return defaultBaseURL
+}
+
+// getJSON fetches url from Open-Meteo and decodes the response into out, returning an APIError if the request failed.
+func getJSON(ctx context.Context, url string, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := upstream.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := upstream.CheckStatus("open-meteo", resp); err != nil {
+		return apiError(resp, err.(*upstream.StatusError))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
+
+func apiError(resp *http.Response, status *upstream.StatusError) *APIError {
+	e := &APIError{status: status}
+	var body openMeteoError
+	if b, err := io.ReadAll(io.LimitReader(resp.Body, 4096)); err == nil && json.Unmarshal(b, &body) == nil {
+		e.Reason = body.Reason
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		e.kind = ErrRateLimited
+	case resp.StatusCode == http.StatusBadRequest && mentionsCoordinates(e.Reason):
+		// Open-Meteo rejects coordinates it can't serve, such as those outside a regional model's domain.
+		e.kind = ErrOutOfCoverage
+	}
+	return e
+}
+
+func mentionsCoordinates(reason string) bool {
+	reason = strings.ToLower(reason)
+	return strings.Contains(reason, "latitude") || strings.Contains(reason, "longitude") ||
+		strings.Contains(reason, "coordinate") || strings.Contains(reason, "no data")
+}
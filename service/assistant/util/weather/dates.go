@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ForecastDays is how many days the daily forecast covers, starting today. Open-Meteo allows up to 16.
+const ForecastDays = 14
+
+// DateFormat is the format of the dates in Forecast.Date, and of the dates returned by ResolveDays.
+const DateFormat = "2006-01-02"
+
+// OutOfRangeError is returned when asked for a day the forecast doesn't cover.
+type OutOfRangeError struct {
+	Date  string
+	First string
+	Last  string
+}
+
+func (e *OutOfRangeError) Error() string {
+	return fmt.Sprintf("there is no forecast for %s: forecasts are only available from %s to %s", e.Date, e.First, e.Last)
+}
+
+// ResolveDays works out which dates a description of a day refers to, given the current time in the user's timezone.
+// The description can be "today", "tomorrow", "this weekend", the name of a weekday (optionally preceded by "this" or
+// "next"), or a date in YYYY-MM-DD format. Weekdays refer to the next such day, counting today. The dates are returned
+// in DateFormat; "this weekend" returns two of them, unless it's already Sunday.
+func ResolveDays(description string, now time.Time) ([]string, error) {
+	d := strings.ToLower(strings.TrimSpace(description))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch d {
+	case "", "today", "tonight":
+		return []string{today.Format(DateFormat)}, nil
+	case "tomorrow":
+		return []string{today.AddDate(0, 0, 1).Format(DateFormat)}, nil
+	case "weekend", "this weekend":
+		switch today.Weekday() {
+		case time.Sunday:
+			return []string{today.Format(DateFormat)}, nil
+		default:
+			saturday := today.AddDate(0, 0, int(time.Saturday-today.Weekday()))
+			return []string{saturday.Format(DateFormat), saturday.AddDate(0, 0, 1).Format(DateFormat)}, nil
+		}
+	}
+	if t, err := time.ParseInLocation(DateFormat, d, now.Location()); err == nil {
+		return []string{t.Format(DateFormat)}, nil
+	}
+	next := false
+	if rest, ok := strings.CutPrefix(d, "next "); ok {
+		d, next = rest, true
+	} else if rest, ok := strings.CutPrefix(d, "this "); ok {
+		d = rest
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if d != strings.ToLower(wd.String()) {
+			continue
+		}
+		offset := (int(wd) - int(today.Weekday()) + 7) % 7
+		if offset == 0 && next {
+			offset = 7
+		}
+		return []string{today.AddDate(0, 0, offset).Format(DateFormat)}, nil
+	}
+	return nil, fmt.Errorf("can't tell which day %q means: use today, tomorrow, this weekend, a weekday, or a date in YYYY-MM-DD format", description)
+}
+
+// DayIndex returns the index of the given date in the forecast, or an OutOfRangeError if the forecast doesn't cover it.
+func (f *Forecast) DayIndex(date string) (int, error) {
+	for i, d := range f.Date {
+		if d == date {
+			return i, nil
+		}
+	}
+	if len(f.Date) == 0 {
+		return -1, fmt.Errorf("the forecast is empty")
+	}
+	return -1, &OutOfRangeError{Date: date, First: f.Date[0], Last: f.Date[len(f.Date)-1]}
+}
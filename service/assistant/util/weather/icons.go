@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+// IconSet names a set of weather icon codes that a watch knows how to display.
+type IconSet string
+
+const (
+	// IconSetTWC is the set of Weather Company icon codes, which older watch apps translate into their own icons.
+	IconSetTWC IconSet = "twc"
+	// IconSetPebble is the set of conditions the watch app can draw directly.
+	IconSetPebble IconSet = "pebble"
+)
+
+type dayNightIcon struct {
+	Day   int `json:"day"`
+	Night int `json:"night"`
+}
+
+// condition describes a group of WMO weather interpretation codes, which Open-Meteo uses.
+// https://www.nodc.noaa.gov/archive/arc0021/0002199/1.1/data/0-data/HTML/WMO-CODE/WMO4677.HTM
+type condition struct {
+	Codes       []int                    `json:"codes"`
+	Description string                   `json:"description"`
+	Icons       map[IconSet]dayNightIcon `json:"icons"`
+}
+
+//go:embed icons.json
+var iconsJSON []byte
+
+var conditions = map[int]*condition{}
+
+// unknownCondition is used for codes that aren't in the table.
+var unknownCondition = &condition{
+	Description: "Unknown",
+	Icons: map[IconSet]dayNightIcon{
+		IconSetTWC:    {Day: 32, Night: 31},
+		IconSetPebble: {Day: 6, Night: 6},
+	},
+}
+
+func init() {
+	var table []*condition
+	if err := json.Unmarshal(iconsJSON, &table); err != nil {
+		panic("invalid weather icon table: " + err.Error())
+	}
+	for _, c := range table {
+		for _, code := range c.Codes {
+			conditions[code] = c
+		}
+	}
+}
+
+func lookupCondition(code int) *condition {
+	if c, ok := conditions[code]; ok {
+		return c
+	}
+	return unknownCondition
+}
+
+// Describe returns an English description of a WMO weather code, e.g. "Partly cloudy".
+func Describe(code int) string {
+	return lookupCondition(code).Description
+}
+
+// Icon returns the icon code for the given WMO weather code in the given icon set, taking into account whether it's
+// day or night. Unknown icon sets fall back to the Weather Company codes.
+func Icon(code int, isDay bool, set IconSet) int {
+	icons, ok := lookupCondition(code).Icons[set]
+	if !ok {
+		icons = lookupCondition(code).Icons[IconSetTWC]
+	}
+	if isDay {
+		return icons.Day
+	}
+	return icons.Night
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// MaxStaleness is the oldest a report can be and still be used when Open-Meteo can't be reached. Much older than this
+// and the "current" conditions aren't worth much.
+const MaxStaleness = 6 * time.Hour
+
+// lastGoodReport is how reports are kept in Redis.
+type lastGoodReport struct {
+	Report    *Report   `json:"report"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// lastGoodKey rounds the location to two decimal places, about a kilometre, so that nearby requests share a report.
+// The version changes whenever Report does in a way that old reports wouldn't decode, so that they're ignored.
+func lastGoodKey(lat, lon float64, units string) string {
+	return fmt.Sprintf("weather:last_good:v3:%.2f,%.2f,%s", lat, lon, units)
+}
+
+// storeLastGood remembers a report that was fetched successfully, so that it can stand in if Open-Meteo goes down.
+func storeLastGood(ctx context.Context, lat, lon float64, units string, report *Report) {
+	data, err := json.Marshal(lastGoodReport{Report: report, FetchedAt: time.Now().UTC()})
+	if err != nil {
+		return
+	}
+	if err := storage.GetRedis().Set(ctx, lastGoodKey(lat, lon, units), data, MaxStaleness).Err(); err != nil {
+		requestid.Logf(ctx, "Storing last good weather report failed: %v", err)
+	}
+}
+
+// loadLastGood returns the last report fetched successfully for the location, with its AsOf fields set to when it was
+// fetched. It returns nil if there isn't one.
+func loadLastGood(ctx context.Context, lat, lon float64, units string) *Report {
+	data, err := storage.GetRedis().Get(ctx, lastGoodKey(lat, lon, units)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			requestid.Logf(ctx, "Loading last good weather report failed: %v", err)
+		}
+		return nil
+	}
+	var stored lastGoodReport
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Report == nil {
+		return nil
+	}
+	report := stored.Report
+	if report.Current == nil || report.Hourly == nil || report.Daily == nil {
+		return nil
+	}
+	report.Current.AsOf = stored.FetchedAt
+	report.Hourly.AsOf = stored.FetchedAt
+	report.Daily.AsOf = stored.FetchedAt
+	beeline.AddField(ctx, "weather_report_stale_seconds", int(time.Since(stored.FetchedAt).Seconds()))
+	return report
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+)
+
+// Precipitation chances below this aren't worth mentioning.
+const minNarrativePrecipChance = 10
+
+// Wind speeds at or above these are strong enough to mention (Beaufort 5, a "fresh breeze"), by wind unit.
+var strongWindSpeeds = map[string]float64{
+	"kmh": 30,
+	"mph": 19,
+}
+
+var windUnitNames = map[string]string{
+	"kmh": "km/h",
+	"mph": "mph",
+}
+
+// narrative describes the weather over a day, or part of one. High and low are optional, so the same narrative can
+// describe a whole day or just its daytime or night.
+type narrative struct {
+	weatherCode  int
+	high         *float64
+	low          *float64
+	precipChance int
+	windSpeed    float64
+	// The unit windSpeed is in, as passed to Open-Meteo.
+	windUnit string
+}
+
+// render turns the narrative into a sentence or two in the user's language, leaving out anything not worth saying.
+func (n narrative) render(ctx context.Context) string {
+	condition := i18n.T(ctx, "weather.condition."+Describe(n.weatherCode))
+	var sentences []string
+	switch {
+	case n.high != nil && n.low != nil:
+		sentences = append(sentences, i18n.T(ctx, "weather.narrative.high_low", condition, *n.high, *n.low))
+	case n.high != nil:
+		sentences = append(sentences, i18n.T(ctx, "weather.narrative.high", condition, *n.high))
+	case n.low != nil:
+		sentences = append(sentences, i18n.T(ctx, "weather.narrative.low", condition, *n.low))
+	default:
+		sentences = append(sentences, condition+".")
+	}
+	if n.precipChance >= minNarrativePrecipChance {
+		sentences = append(sentences, i18n.T(ctx, "weather.narrative.precip", n.precipChance))
+	}
+	if threshold, ok := strongWindSpeeds[n.windUnit]; ok && n.windSpeed >= threshold {
+		sentences = append(sentences, i18n.T(ctx, "weather.narrative.wind", n.windSpeed, windUnitNames[n.windUnit]))
+	}
+	return strings.Join(sentences, " ")
+}
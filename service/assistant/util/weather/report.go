@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/honeycombio/beeline-go"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
+)
+
+// Report is everything we know about the weather at a location: its current conditions, and its hourly and daily
+// forecasts.
+type Report struct {
+	Current *CurrentConditions
+	Hourly  *HourlyForecast
+	Daily   *Forecast
+}
+
+// GetReport fetches the current conditions, hourly forecast and daily forecast for a location in a single request.
+// If Open-Meteo can't be reached, it returns the last report fetched for the location instead, as long as it's no
+// older than MaxStaleness, with AsOf set on each part to say how old it is.
+func GetReport(ctx context.Context, lat, lon float64, units string) (*Report, error) {
+	ctx, span := beeline.StartSpan(ctx, "weather.get_report")
+	defer span.Send()
+	params, err := mapUnit(units)
+	if err != nil {
+		return nil, err
+	}
+
+	url := forecastURL(lat, lon, params, fmt.Sprintf("current=%s&hourly=%s&daily=%s&forecast_days=%d", currentFields, hourlyFields, dailyFields, ForecastDays))
+
+	var openMeteoResp openMeteoResponse
+	if err := getJSON(ctx, url, &openMeteoResp); err != nil {
+		span.AddField("error", err)
+		if upstream.IsUnavailable(err) {
+			// Old weather with a warning is more use than none at all.
+			if report := loadLastGood(ctx, lat, lon, units); report != nil {
+				return report, nil
+			}
+		}
+		return nil, err
+	}
+
+	report := &Report{}
+	if report.Current, err = currentConditionsFromResponse(&openMeteoResp, params); err != nil {
+		return nil, err
+	}
+	if report.Hourly, err = hourlyForecastFromResponse(&openMeteoResp); err != nil {
+		return nil, err
+	}
+	if report.Daily, err = dailyForecastFromResponse(ctx, &openMeteoResp, params); err != nil {
+		return nil, err
+	}
+	storeLastGood(ctx, lat, lon, units, report)
+	return report, nil
+}
+
+type reportCacheKey struct{}
+
+type reportCache struct {
+	mu      sync.Mutex
+	reports map[string]*cachedReport
+}
+
+type cachedReport struct {
+	once   sync.Once
+	report *Report
+	err    error
+}
+
+// WithReportCache returns a context in which GetCurrentConditions, GetHourlyForecast and GetDailyForecast all share
+// a single Report per location, so that a turn that needs several kinds of weather for the same place only makes one
+// request. It should be used once per turn, so that the weather doesn't go stale.
+func WithReportCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reportCacheKey{}, &reportCache{reports: map[string]*cachedReport{}})
+}
+
+// cachedReportFromContext returns the report for the location from the context's cache, fetching it if necessary.
+// ok is false if there is no cache in the context.
+func cachedReportFromContext(ctx context.Context, lat, lon float64, units string) (report *Report, ok bool, err error) {
+	cache, ok := ctx.Value(reportCacheKey{}).(*reportCache)
+	if !ok {
+		return nil, false, nil
+	}
+	// Geocoding the same place twice gives the same coordinates, but there's no point being more precise than this.
+	key := fmt.Sprintf("%.4f,%.4f,%s", lat, lon, units)
+	cache.mu.Lock()
+	entry, found := cache.reports[key]
+	if !found {
+		entry = &cachedReport{}
+		cache.reports[key] = entry
+	}
+	cache.mu.Unlock()
+	if found {
+		beeline.AddField(ctx, "weather_report_cached", true)
+	}
+
+	entry.once.Do(func() {
+		entry.report, entry.err = GetReport(ctx, lat, lon, units)
+	})
+	if entry.err != nil {
+		// Don't remember failures, so that a retry makes a fresh request.
+		cache.mu.Lock()
+		if cache.reports[key] == entry {
+			delete(cache.reports, key)
+		}
+		cache.mu.Unlock()
+	}
+	return entry.report, true, entry.err
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/timezone"
+)
+
+type SunTimes struct {
+	Sunrise time.Time
+	Sunset  time.Time
+}
+
+// GetSunTimes returns the sunrise and sunset at the given location on the given date (in YYYY-MM-DD format, local to
+// the location). The returned times are in the location's own timezone.
+func GetSunTimes(ctx context.Context, lat, lon float64, date string) (*SunTimes, error) {
+	loc := timezone.Location(lat, lon)
+	url := fmt.Sprintf(
+		"%s/v1/forecast?latitude=%f&longitude=%f&daily=sunrise,sunset&timezone=%s&timeformat=iso8601&start_date=%s&end_date=%s",
+		baseURL(), lat, lon, url.QueryEscape(loc.String()), date, date)
+
+	var openMeteoResp openMeteoResponse
+	if err := getJSON(ctx, url, &openMeteoResp); err != nil {
+		return nil, err
+	}
+
+	if openMeteoResp.Daily == nil || len(openMeteoResp.Daily.SunriseIso) == 0 || len(openMeteoResp.Daily.SunsetIso) == 0 {
+		return nil, fmt.Errorf("no sunrise data received")
+	}
+
+	// Times are local to the location but don't include an offset, so we have to add it back. The offset Open-Meteo
+	// gives is today's, which is wrong for dates on the other side of a change to or from daylight saving time.
+	sunrise, err := time.ParseInLocation("2006-01-02T15:04", openMeteoResp.Daily.SunriseIso[0], loc)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sunrise time: %w", err)
+	}
+	sunset, err := time.ParseInLocation("2006-01-02T15:04", openMeteoResp.Daily.SunsetIso[0], loc)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sunset time: %w", err)
+	}
+	return &SunTimes{Sunrise: sunrise, Sunset: sunset}, nil
+}
@@ -16,17 +16,18 @@ package weather
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"strings"
+	"math"
+	"net/url"
 	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/timezone"
 )
 
 // Weather data structures for the API response
 type Forecast struct {
-	CalendarDayTemperatureMax []int
-	CalendarDayTemperatureMin []int
+	CalendarDayTemperatureMax []float64
+	CalendarDayTemperatureMin []float64
 	DayOfWeek                 []string
 	MoonPhaseCode             []string
 	MoonPhase                 []string
@@ -38,25 +39,51 @@ type Forecast struct {
 	MoonsetTimeLocal          []string
 	Qpf                       []float32
 	QpfSnow                   []float32
-	DayParts                  []ForecastDayPart
+	// The local date of each day at the forecast location, in DateFormat.
+	Date []string
+	// The forecast location's offset from UTC, which its dates are in.
+	UTCOffsetSeconds int
+	// The forecast location's timezone, e.g. "Europe/London". Reports saved before it was added don't have it.
+	Timezone string
+	// The WMO weather code for each day.
+	WeatherCode []int
+	// Two parts for each day, the daytime and then the night, so day i's are at 2i and 2i+1.
+	DayParts []ForecastDayPart
+	// When the data was fetched, if it's an old report standing in because Open-Meteo couldn't be reached. It's zero
+	// for fresh data.
+	AsOf time.Time `json:"-"`
 }
 
+// Now returns the current time at the forecast location, for working out which of its dates are meant by "today" or
+// "tomorrow".
+func (f *Forecast) Now() time.Time {
+	if loc, err := time.LoadLocation(f.Timezone); f.Timezone != "" && err == nil {
+		return time.Now().In(loc)
+	}
+	return time.Now().In(time.FixedZone("forecast", f.UTCOffsetSeconds))
+}
+
+// ForecastDayPart is the forecast for half of a day: the daytime, or the night that follows it.
 type ForecastDayPart struct {
-	CloudCover            []*int
-	DayOrNight            []*string
-	DaypartName           []*string
-	IconCode              []*int
-	IconCodeExtend        []*int
-	Narrative             []*string
-	PrecipChance          []*int
-	PrecipType            []*string
-	Temperature           []*int
-	WindDirectionCardinal []*string
-	WindSpeed             []*int
-	WxPhraseLong          []*string
+	// False if there's no forecast for this part of the day, in which case the other fields are zero.
+	Valid                 bool
+	CloudCover            int
+	DayOrNight            string
+	DaypartName           string
+	IconCode              int
+	IconCodeExtend        int
+	Narrative             string
+	PrecipChance          int
+	PrecipType            string
+	Temperature           float64
+	WindDirectionCardinal string
+	WindSpeed             int
+	WxPhraseLong          string
 }
 
 type CurrentConditions struct {
+	WeatherCode           int
+	IsDay                 bool
 	CloudCover            int
 	CloudCoverPhrase      string
 	DayOfWeek             string
@@ -64,27 +91,37 @@ type CurrentConditions struct {
 	Description           string
 	IconCode              int
 	Precip1Hour           float32
+	PrecipChance          int
 	RelativeHumidity      int
 	SunriseTimeLocal      string
 	SunsetTimeLocal       string
-	Temperature           int
-	TemperatureFeelsLike  int
-	TemperatureMax24Hour  int
-	TemperatureMin24Hour  int
-	TemperatureWindChill  int
+	Temperature           float64
+	TemperatureFeelsLike  float64
+	TemperatureMax24Hour  float64
+	TemperatureMin24Hour  float64
+	TemperatureWindChill  float64
 	UVIndex               int
 	Visibility            float32
 	WindDirectionCardinal string
 	WindSpeed             int
+	// When the data was fetched, if it's an old report standing in because Open-Meteo couldn't be reached. It's zero
+	// for fresh data.
+	AsOf time.Time `json:"-"`
 }
 
 type HourlyForecast struct {
-	Temperature    []int
+	WeatherCode    []int
+	IconCode       []int
+	IsDay          []bool
+	Temperature    []float64
 	WxPhraseLong   []string
 	PrecipChance   []int
 	PrecipType     []string
 	ValidTimeLocal []string
 	UVIndex        []int
+	// When the data was fetched, if it's an old report standing in because Open-Meteo couldn't be reached. It's zero
+	// for fresh data.
+	AsOf time.Time `json:"-"`
 }
 
 type openMeteoParams struct {
@@ -120,32 +157,35 @@ func mapUnit(unit string) (openMeteoParams, error) {
 
 // OpenMeteo response structures
 type openMeteoResponse struct {
-	Latitude             float64                  `json:"latitude"`
-	Longitude            float64                  `json:"longitude"`
-	Elevation            float64                  `json:"elevation"`
-	GenerationTimeMs     float64                  `json:"generationtime_ms"`
-	UtcOffsetSeconds     int                      `json:"utc_offset_seconds"`
-	Timezone             string                   `json:"timezone"`
-	TimezoneAbbreviation string                   `json:"timezone_abbreviation"`
-	CurrentWeather       *openMeteoCurrentWeather `json:"current_weather,omitempty"`
-	Daily                *openMeteoDaily          `json:"daily,omitempty"`
-	DailyUnits           *openMeteoUnits          `json:"daily_units,omitempty"`
-	Hourly               *openMeteoHourly         `json:"hourly,omitempty"`
-	HourlyUnits          *openMeteoUnits          `json:"hourly_units,omitempty"`
+	Latitude             float64           `json:"latitude"`
+	Longitude            float64           `json:"longitude"`
+	Elevation            float64           `json:"elevation"`
+	GenerationTimeMs     float64           `json:"generationtime_ms"`
+	UtcOffsetSeconds     int               `json:"utc_offset_seconds"`
+	Timezone             string            `json:"timezone"`
+	TimezoneAbbreviation string            `json:"timezone_abbreviation"`
+	Current              *openMeteoCurrent `json:"current,omitempty"`
+	Daily                *openMeteoDaily   `json:"daily,omitempty"`
+	DailyUnits           *openMeteoUnits   `json:"daily_units,omitempty"`
+	Hourly               *openMeteoHourly  `json:"hourly,omitempty"`
+	HourlyUnits          *openMeteoUnits   `json:"hourly_units,omitempty"`
 }
 
-type openMeteoCurrentWeather struct {
-	Temperature         float64 `json:"temperature"`
-	Windspeed           float64 `json:"windspeed"`
-	WindDirection       float64 `json:"winddirection"`
-	WeatherCode         int     `json:"weathercode"`
-	IsDay               int     `json:"is_day"`
+// openMeteoCurrent is the "current" section of the response, with the fields in currentFields.
+type openMeteoCurrent struct {
 	Time                string  `json:"time"`
-	RelativeHumidity    float64 `json:"relativehumidity_2m,omitempty"`
-	ApparentTemperature float64 `json:"apparent_temperature,omitempty"`
-	Precipitation       float64 `json:"precipitation,omitempty"`
-	Visibility          float64 `json:"visibility,omitempty"`
-	CloudCover          float64 `json:"cloudcover,omitempty"`
+	Temperature         float64 `json:"temperature_2m"`
+	RelativeHumidity    float64 `json:"relative_humidity_2m"`
+	ApparentTemperature float64 `json:"apparent_temperature"`
+	IsDay               int     `json:"is_day"`
+	Precipitation       float64 `json:"precipitation"`
+	WeatherCode         int     `json:"weather_code"`
+	CloudCover          float64 `json:"cloud_cover"`
+	Visibility          float64 `json:"visibility"`
+	WindSpeed           float64 `json:"wind_speed_10m"`
+	WindDirection       float64 `json:"wind_direction_10m"`
+	UVIndex             float64 `json:"uv_index"`
+	PrecipChance        float64 `json:"precipitation_probability"`
 }
 
 type openMeteoDaily struct {
@@ -180,40 +220,57 @@ type openMeteoHourly struct {
 
 type openMeteoUnits map[string]string
 
+// The fields we request from Open-Meteo for each kind of weather.
+const (
+	dailyFields        = "weathercode,temperature_2m_max,temperature_2m_min,sunrise,sunset,precipitation_sum,precipitation_hours,precipitation_probability_max,windspeed_10m_max,winddirection_10m_dominant,uv_index_max"
+	currentFields      = "temperature_2m,relative_humidity_2m,apparent_temperature,is_day,precipitation,weather_code,cloud_cover,visibility,wind_speed_10m,wind_direction_10m,uv_index,precipitation_probability"
+	currentDailyFields = "temperature_2m_max,temperature_2m_min,sunrise,sunset"
+	hourlyFields       = "temperature_2m,precipitation_probability,precipitation,weathercode,uv_index,is_day"
+)
+
+// hourlyForecastHours is how many hours the hourly forecast covers.
+const hourlyForecastHours = 48
+
+// forecastURL returns the Open-Meteo URL for the given sections of the forecast, e.g. "daily=sunrise,sunset". Times
+// are always local to the location, in the timezone we'd give it anywhere else, rather than Open-Meteo's own idea of
+// it, which can differ near a border.
+func forecastURL(lat, lon float64, params openMeteoParams, sections string) string {
+	return fmt.Sprintf(
+		"%s/v1/forecast?latitude=%f&longitude=%f&%s&timeformat=%s&temperature_unit=%s&windspeed_unit=%s&precipitation_unit=%s&timezone=%s",
+		baseURL(), lat, lon, sections, params.timeFormat, params.tempUnit, params.windUnit, params.precipUnit, url.QueryEscape(timezone.Name(lat, lon)))
+}
+
 func GetDailyForecast(ctx context.Context, lat, lon float64, units string) (*Forecast, error) {
+	if report, ok, err := cachedReportFromContext(ctx, lat, lon, units); ok {
+		if err != nil {
+			return nil, err
+		}
+		return report.Daily, nil
+	}
 	params, err := mapUnit(units)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&daily=weathercode,temperature_2m_max,temperature_2m_min,sunrise,sunset,precipitation_sum,precipitation_hours,precipitation_probability_max,windspeed_10m_max,winddirection_10m_dominant,uv_index_max&timeformat=%s&temperature_unit=%s&windspeed_unit=%s&precipitation_unit=%s",
-		lat, lon, params.timeFormat, params.tempUnit, params.windUnit, params.precipUnit)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
+	url := forecastURL(lat, lon, params, fmt.Sprintf("daily=%s&forecast_days=%d", dailyFields, ForecastDays))
 
 	var openMeteoResp openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openMeteoResp); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := getJSON(ctx, url, &openMeteoResp); err != nil {
+		return nil, err
 	}
+	return dailyForecastFromResponse(ctx, &openMeteoResp, params)
+}
 
+func dailyForecastFromResponse(ctx context.Context, openMeteoResp *openMeteoResponse, params openMeteoParams) (*Forecast, error) {
 	if openMeteoResp.Daily == nil {
 		return nil, fmt.Errorf("no daily forecast data received")
 	}
 
 	// Convert to our format
 	forecast := &Forecast{
-		CalendarDayTemperatureMax: make([]int, len(openMeteoResp.Daily.Time)),
-		CalendarDayTemperatureMin: make([]int, len(openMeteoResp.Daily.Time)),
+		CalendarDayTemperatureMax: make([]float64, len(openMeteoResp.Daily.Time)),
+		CalendarDayTemperatureMin: make([]float64, len(openMeteoResp.Daily.Time)),
+		Date:                      openMeteoResp.Daily.Time,
 		DayOfWeek:                 make([]string, len(openMeteoResp.Daily.Time)),
 		MoonPhaseCode:             make([]string, len(openMeteoResp.Daily.Time)),
 		MoonPhase:                 make([]string, len(openMeteoResp.Daily.Time)),
@@ -225,25 +282,30 @@ func GetDailyForecast(ctx context.Context, lat, lon float64, units string) (*For
 		MoonsetTimeLocal:          make([]string, len(openMeteoResp.Daily.Time)),
 		Qpf:                       make([]float32, len(openMeteoResp.Daily.Time)),
 		QpfSnow:                   make([]float32, len(openMeteoResp.Daily.Time)),
+		WeatherCode:               openMeteoResp.Daily.WeatherCode,
+		UTCOffsetSeconds:          openMeteoResp.UtcOffsetSeconds,
+		Timezone:                  openMeteoResp.Timezone,
 	}
 
 	// Map data from Open-Meteo to our structure
 	for i, timeStr := range openMeteoResp.Daily.Time {
-		t, _ := time.Parse("2006-01-02", timeStr)
+		t, _ := time.Parse(DateFormat, timeStr)
 		forecast.DayOfWeek[i] = t.Format("Monday")
-		forecast.CalendarDayTemperatureMax[i] = int(openMeteoResp.Daily.TemperatureMax[i])
-		forecast.CalendarDayTemperatureMin[i] = int(openMeteoResp.Daily.TemperatureMin[i])
+		forecast.CalendarDayTemperatureMax[i] = openMeteoResp.Daily.TemperatureMax[i]
+		forecast.CalendarDayTemperatureMin[i] = openMeteoResp.Daily.TemperatureMin[i]
 		forecast.SunriseTimeLocal[i] = openMeteoResp.Daily.SunriseIso[i]
 		forecast.SunsetTimeLocal[i] = openMeteoResp.Daily.SunsetIso[i]
 		forecast.Qpf[i] = float32(openMeteoResp.Daily.PrecipitationSum[i])
 
 		// Generate a narrative based on weather code and temperatures
-		weatherDesc := weatherCodeToDescription(openMeteoResp.Daily.WeatherCode[i])
-		forecast.Narrative[i] = fmt.Sprintf("%s with high of %d and low of %d. %d%% chance of precipitation.",
-			weatherDesc,
-			int(openMeteoResp.Daily.TemperatureMax[i]),
-			int(openMeteoResp.Daily.TemperatureMin[i]),
-			int(openMeteoResp.Daily.PrecipitationProbabilityMax[i]))
+		forecast.Narrative[i] = narrative{
+			weatherCode:  openMeteoResp.Daily.WeatherCode[i],
+			high:         &openMeteoResp.Daily.TemperatureMax[i],
+			low:          &openMeteoResp.Daily.TemperatureMin[i],
+			precipChance: int(openMeteoResp.Daily.PrecipitationProbabilityMax[i]),
+			windSpeed:    openMeteoResp.Daily.WindspeedMax[i],
+			windUnit:     params.windUnit,
+		}.render(ctx)
 
 		// We don't have moon phase data from Open-Meteo, using placeholders
 		forecast.MoonPhaseCode[i] = "N"
@@ -254,174 +316,141 @@ func GetDailyForecast(ctx context.Context, lat, lon float64, units string) (*For
 		forecast.QpfSnow[i] = 0 // Open-Meteo doesn't provide separate snow data in free tier
 	}
 
-	// Create day parts
-	forecast.DayParts = []ForecastDayPart{
-		{
-			CloudCover:            make([]*int, len(openMeteoResp.Daily.Time)*2),
-			DayOrNight:            make([]*string, len(openMeteoResp.Daily.Time)*2),
-			DaypartName:           make([]*string, len(openMeteoResp.Daily.Time)*2),
-			IconCode:              make([]*int, len(openMeteoResp.Daily.Time)*2),
-			IconCodeExtend:        make([]*int, len(openMeteoResp.Daily.Time)*2),
-			Narrative:             make([]*string, len(openMeteoResp.Daily.Time)*2),
-			PrecipChance:          make([]*int, len(openMeteoResp.Daily.Time)*2),
-			PrecipType:            make([]*string, len(openMeteoResp.Daily.Time)*2),
-			Temperature:           make([]*int, len(openMeteoResp.Daily.Time)*2),
-			WindDirectionCardinal: make([]*string, len(openMeteoResp.Daily.Time)*2),
-			WindSpeed:             make([]*int, len(openMeteoResp.Daily.Time)*2),
-			WxPhraseLong:          make([]*string, len(openMeteoResp.Daily.Time)*2),
-		},
-	}
-
 	// Create day/night entries for each day
+	forecast.DayParts = make([]ForecastDayPart, len(openMeteoResp.Daily.Time)*2)
 	for i := range openMeteoResp.Daily.Time {
-		// Day
-		day := "day"
-		night := "night"
-		dayName := fmt.Sprintf("Day %d", i+1)
-		nightName := fmt.Sprintf("Night %d", i+1)
-
-		dayIndex := i * 2
-		nightIndex := i*2 + 1
-
-		iconCode := weatherCodeToIconCode(openMeteoResp.Daily.WeatherCode[i])
-		weatherDesc := weatherCodeToDescription(openMeteoResp.Daily.WeatherCode[i])
-		dayNarrative := fmt.Sprintf("%s with high of %d. %d%% chance of precipitation.",
-			weatherDesc, int(openMeteoResp.Daily.TemperatureMax[i]), int(openMeteoResp.Daily.PrecipitationProbabilityMax[i]))
-		nightNarrative := fmt.Sprintf("%s with low of %d. %d%% chance of precipitation.",
-			weatherDesc, int(openMeteoResp.Daily.TemperatureMin[i]), int(openMeteoResp.Daily.PrecipitationProbabilityMax[i]))
-
+		weatherDesc := Describe(openMeteoResp.Daily.WeatherCode[i])
 		precipChance := int(openMeteoResp.Daily.PrecipitationProbabilityMax[i])
-
 		var precipType string
 		if precipChance > 0 {
 			precipType = "rain" // Simplification since we don't have detailed precip type
-		} else {
-			precipType = ""
 		}
-
 		windDir := cardinalFromDegrees(openMeteoResp.Daily.WinddirectionDominant[i])
 		windSpeed := int(openMeteoResp.Daily.WindspeedMax[i])
 
-		// Day values
-		forecast.DayParts[0].DayOrNight[dayIndex] = &day
-		forecast.DayParts[0].DaypartName[dayIndex] = &dayName
-		forecast.DayParts[0].IconCode[dayIndex] = &iconCode
-		forecast.DayParts[0].IconCodeExtend[dayIndex] = &iconCode
-		forecast.DayParts[0].Narrative[dayIndex] = &dayNarrative
-		forecast.DayParts[0].PrecipChance[dayIndex] = &precipChance
-		forecast.DayParts[0].PrecipType[dayIndex] = &precipType
-		forecast.DayParts[0].Temperature[dayIndex] = intPtr(int(openMeteoResp.Daily.TemperatureMax[i]))
-		forecast.DayParts[0].WindDirectionCardinal[dayIndex] = &windDir
-		forecast.DayParts[0].WindSpeed[dayIndex] = &windSpeed
-		forecast.DayParts[0].WxPhraseLong[dayIndex] = &weatherDesc
-
-		// Night values
-		forecast.DayParts[0].DayOrNight[nightIndex] = &night
-		forecast.DayParts[0].DaypartName[nightIndex] = &nightName
-		forecast.DayParts[0].IconCode[nightIndex] = &iconCode
-		forecast.DayParts[0].IconCodeExtend[nightIndex] = &iconCode
-		forecast.DayParts[0].Narrative[nightIndex] = &nightNarrative
-		forecast.DayParts[0].PrecipChance[nightIndex] = &precipChance
-		forecast.DayParts[0].PrecipType[nightIndex] = &precipType
-		forecast.DayParts[0].Temperature[nightIndex] = intPtr(int(openMeteoResp.Daily.TemperatureMin[i]))
-		forecast.DayParts[0].WindDirectionCardinal[nightIndex] = &windDir
-		forecast.DayParts[0].WindSpeed[nightIndex] = &windSpeed
-		forecast.DayParts[0].WxPhraseLong[nightIndex] = &weatherDesc
+		// Open-Meteo only gives us daily figures, so both parts of the day share the precipitation chance and wind.
+		forecast.DayParts[i*2] = ForecastDayPart{
+			Valid:          true,
+			DayOrNight:     "day",
+			DaypartName:    fmt.Sprintf("Day %d", i+1),
+			IconCode:       Icon(openMeteoResp.Daily.WeatherCode[i], true, IconSetTWC),
+			IconCodeExtend: Icon(openMeteoResp.Daily.WeatherCode[i], true, IconSetTWC),
+			Narrative: narrative{
+				weatherCode:  openMeteoResp.Daily.WeatherCode[i],
+				high:         &openMeteoResp.Daily.TemperatureMax[i],
+				precipChance: precipChance,
+				windSpeed:    openMeteoResp.Daily.WindspeedMax[i],
+				windUnit:     params.windUnit,
+			}.render(ctx),
+			PrecipChance:          precipChance,
+			PrecipType:            precipType,
+			Temperature:           openMeteoResp.Daily.TemperatureMax[i],
+			WindDirectionCardinal: windDir,
+			WindSpeed:             windSpeed,
+			WxPhraseLong:          weatherDesc,
+		}
+		forecast.DayParts[i*2+1] = ForecastDayPart{
+			Valid:          true,
+			DayOrNight:     "night",
+			DaypartName:    fmt.Sprintf("Night %d", i+1),
+			IconCode:       Icon(openMeteoResp.Daily.WeatherCode[i], false, IconSetTWC),
+			IconCodeExtend: Icon(openMeteoResp.Daily.WeatherCode[i], false, IconSetTWC),
+			Narrative: narrative{
+				weatherCode:  openMeteoResp.Daily.WeatherCode[i],
+				low:          &openMeteoResp.Daily.TemperatureMin[i],
+				precipChance: precipChance,
+				windSpeed:    openMeteoResp.Daily.WindspeedMax[i],
+				windUnit:     params.windUnit,
+			}.render(ctx),
+			PrecipChance:          precipChance,
+			PrecipType:            precipType,
+			Temperature:           openMeteoResp.Daily.TemperatureMin[i],
+			WindDirectionCardinal: windDir,
+			WindSpeed:             windSpeed,
+			WxPhraseLong:          weatherDesc,
+		}
 	}
 
 	return forecast, nil
 }
 
 func GetCurrentConditions(ctx context.Context, lat, lon float64, units string) (*CurrentConditions, error) {
+	if report, ok, err := cachedReportFromContext(ctx, lat, lon, units); ok {
+		if err != nil {
+			return nil, err
+		}
+		return report.Current, nil
+	}
 	params, err := mapUnit(units)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current_weather=true&hourly=temperature_2m,relativehumidity_2m,apparent_temperature,precipitation,visibility,cloudcover,weathercode&daily=temperature_2m_max,temperature_2m_min,sunrise,sunset&timeformat=%s&temperature_unit=%s&windspeed_unit=%s&precipitation_unit=%s",
-		lat, lon, params.timeFormat, params.tempUnit, params.windUnit, params.precipUnit)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
+	url := forecastURL(lat, lon, params, "current="+currentFields+"&daily="+currentDailyFields)
 
 	var openMeteoResp openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openMeteoResp); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := getJSON(ctx, url, &openMeteoResp); err != nil {
+		return nil, err
 	}
+	return currentConditionsFromResponse(&openMeteoResp, params)
+}
 
-	if openMeteoResp.CurrentWeather == nil {
+func currentConditionsFromResponse(openMeteoResp *openMeteoResponse, params openMeteoParams) (*CurrentConditions, error) {
+	current := openMeteoResp.Current
+	if current == nil {
 		return nil, fmt.Errorf("no current weather data received")
 	}
 
-	// Find current time in hourly data to get additional fields
-	currentTime := openMeteoResp.CurrentWeather.Time
-	currentTimeIndex := -1
-	for i, t := range openMeteoResp.Hourly.Time {
-		if strings.HasPrefix(t, currentTime) {
-			currentTimeIndex = i
-			break
-		}
-	}
-
 	// Get day of week
-	t, _ := time.Parse(time.RFC3339, openMeteoResp.CurrentWeather.Time)
+	t, _ := time.Parse("2006-01-02T15:04", current.Time)
 	dayOfWeek := t.Format("Monday")
 
 	// Create current conditions object
+	isDay := current.IsDay == 1
 	conditions := &CurrentConditions{
-		Temperature:           int(openMeteoResp.CurrentWeather.Temperature),
-		TemperatureFeelsLike:  int(openMeteoResp.CurrentWeather.Temperature),
-		WindSpeed:             int(openMeteoResp.CurrentWeather.Windspeed),
-		WindDirectionCardinal: cardinalFromDegrees(int(openMeteoResp.CurrentWeather.WindDirection)),
-		IconCode:              weatherCodeToIconCode(openMeteoResp.CurrentWeather.WeatherCode),
-		Description:           weatherCodeToDescription(openMeteoResp.CurrentWeather.WeatherCode),
+		WeatherCode:           current.WeatherCode,
+		IsDay:                 isDay,
+		Temperature:           current.Temperature,
+		TemperatureFeelsLike:  current.ApparentTemperature,
+		WindSpeed:             int(current.WindSpeed),
+		WindDirectionCardinal: cardinalFromDegrees(int(current.WindDirection)),
+		IconCode:              Icon(current.WeatherCode, isDay, IconSetTWC),
+		Description:           Describe(current.WeatherCode),
 		DayOfWeek:             dayOfWeek,
+		RelativeHumidity:      int(current.RelativeHumidity),
+		Precip1Hour:           float32(current.Precipitation),
+		PrecipChance:          int(current.PrecipChance),
+		CloudCover:            int(current.CloudCover),
+		UVIndex:               int(current.UVIndex),
 	}
 
 	// Set day or night
-	if openMeteoResp.CurrentWeather.IsDay == 1 {
+	if isDay {
 		conditions.DayOrNight = "D"
 	} else {
 		conditions.DayOrNight = "N"
 	}
 
-	// Add additional data if we found the current time in hourly data
-	if currentTimeIndex >= 0 && openMeteoResp.Hourly != nil {
-		conditions.RelativeHumidity = int(openMeteoResp.Hourly.RelativeHumidity[currentTimeIndex])
-		conditions.Precip1Hour = float32(openMeteoResp.Hourly.Precipitation[currentTimeIndex])
-
-		// Set visibility - scale to miles or km as needed
-		if params.tempUnit == "fahrenheit" {
-			// Convert from meters to miles
-			conditions.Visibility = float32(openMeteoResp.Hourly.Visibility[currentTimeIndex] / 1609.34)
-		} else {
-			// Convert from meters to km
-			conditions.Visibility = float32(openMeteoResp.Hourly.Visibility[currentTimeIndex] / 1000)
-		}
-
-		conditions.CloudCover = int(openMeteoResp.Hourly.Visibility[currentTimeIndex])
-
-		// Cloud cover phrase
-		if conditions.CloudCover < 10 {
-			conditions.CloudCoverPhrase = "Clear"
-		} else if conditions.CloudCover < 30 {
-			conditions.CloudCoverPhrase = "Mostly Clear"
-		} else if conditions.CloudCover < 60 {
-			conditions.CloudCoverPhrase = "Partly Cloudy"
-		} else if conditions.CloudCover < 90 {
-			conditions.CloudCoverPhrase = "Mostly Cloudy"
-		} else {
-			conditions.CloudCoverPhrase = "Cloudy"
-		}
+	// Set visibility - scale to miles or km as needed
+	if params.tempUnit == "fahrenheit" {
+		// Convert from meters to miles
+		conditions.Visibility = float32(current.Visibility / 1609.34)
+	} else {
+		// Convert from meters to km
+		conditions.Visibility = float32(current.Visibility / 1000)
+	}
+
+	// Cloud cover phrase
+	if conditions.CloudCover < 10 {
+		conditions.CloudCoverPhrase = "Clear"
+	} else if conditions.CloudCover < 30 {
+		conditions.CloudCoverPhrase = "Mostly Clear"
+	} else if conditions.CloudCover < 60 {
+		conditions.CloudCoverPhrase = "Partly Cloudy"
+	} else if conditions.CloudCover < 90 {
+		conditions.CloudCoverPhrase = "Mostly Cloudy"
+	} else {
+		conditions.CloudCoverPhrase = "Cloudy"
 	}
 
 	// Add sunrise/sunset data
@@ -432,8 +461,8 @@ func GetCurrentConditions(ctx context.Context, lat, lon float64, units string) (
 
 	// Set min/max temps
 	if openMeteoResp.Daily != nil && len(openMeteoResp.Daily.TemperatureMax) > 0 {
-		conditions.TemperatureMax24Hour = int(openMeteoResp.Daily.TemperatureMax[0])
-		conditions.TemperatureMin24Hour = int(openMeteoResp.Daily.TemperatureMin[0])
+		conditions.TemperatureMax24Hour = openMeteoResp.Daily.TemperatureMax[0]
+		conditions.TemperatureMin24Hour = openMeteoResp.Daily.TemperatureMin[0]
 	}
 
 	// Wind chill is same as feels like in cold conditions, otherwise same as temperature
@@ -443,59 +472,59 @@ func GetCurrentConditions(ctx context.Context, lat, lon float64, units string) (
 		conditions.TemperatureWindChill = conditions.Temperature
 	}
 
-	// Set UV Index to a default value as Open-Meteo doesn't provide current UV
-	if currentTimeIndex >= 0 && openMeteoResp.Hourly != nil {
-		conditions.UVIndex = int(openMeteoResp.Hourly.UvIndex[currentTimeIndex])
-	} else {
-		conditions.UVIndex = 0
-	}
-
 	return conditions, nil
 }
 
 func GetHourlyForecast(ctx context.Context, lat, lon float64, units string) (*HourlyForecast, error) {
+	if report, ok, err := cachedReportFromContext(ctx, lat, lon, units); ok {
+		if err != nil {
+			return nil, err
+		}
+		return report.Hourly, nil
+	}
 	params, err := mapUnit(units)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&hourly=temperature_2m,precipitation_probability,precipitation,weathercode,uv_index&timeformat=%s&temperature_unit=%s&windspeed_unit=%s&precipitation_unit=%s&forecast_days=2",
-		lat, lon, params.timeFormat, params.tempUnit, params.windUnit, params.precipUnit)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
+	url := forecastURL(lat, lon, params, fmt.Sprintf("hourly=%s&forecast_days=%d", hourlyFields, hourlyForecastHours/24))
 
 	var openMeteoResp openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openMeteoResp); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := getJSON(ctx, url, &openMeteoResp); err != nil {
+		return nil, err
 	}
+	return hourlyForecastFromResponse(&openMeteoResp)
+}
 
+func hourlyForecastFromResponse(openMeteoResp *openMeteoResponse) (*HourlyForecast, error) {
 	if openMeteoResp.Hourly == nil {
 		return nil, fmt.Errorf("no hourly forecast data received")
 	}
 
-	// Map to hourly forecast
-	forecast := &HourlyForecast{
-		Temperature:    make([]int, len(openMeteoResp.Hourly.Time)),
-		WxPhraseLong:   make([]string, len(openMeteoResp.Hourly.Time)),
-		PrecipChance:   make([]int, len(openMeteoResp.Hourly.Time)),
-		PrecipType:     make([]string, len(openMeteoResp.Hourly.Time)),
-		ValidTimeLocal: make([]string, len(openMeteoResp.Hourly.Time)),
-		UVIndex:        make([]int, len(openMeteoResp.Hourly.Time)),
+	// Combined requests fetch hourly data for the whole daily forecast, but we only want the next couple of days.
+	times := openMeteoResp.Hourly.Time
+	if len(times) > hourlyForecastHours {
+		times = times[:hourlyForecastHours]
 	}
 
-	for i, timeStr := range openMeteoResp.Hourly.Time {
-		forecast.Temperature[i] = int(openMeteoResp.Hourly.Temperature[i])
-		forecast.WxPhraseLong[i] = weatherCodeToDescription(openMeteoResp.Hourly.WeatherCode[i])
+	// Map to hourly forecast
+	forecast := &HourlyForecast{
+		WeatherCode:    openMeteoResp.Hourly.WeatherCode[:len(times)],
+		IconCode:       make([]int, len(times)),
+		IsDay:          make([]bool, len(times)),
+		Temperature:    make([]float64, len(times)),
+		WxPhraseLong:   make([]string, len(times)),
+		PrecipChance:   make([]int, len(times)),
+		PrecipType:     make([]string, len(times)),
+		ValidTimeLocal: make([]string, len(times)),
+		UVIndex:        make([]int, len(times)),
+	}
+
+	for i, timeStr := range times {
+		forecast.Temperature[i] = openMeteoResp.Hourly.Temperature[i]
+		forecast.WxPhraseLong[i] = Describe(openMeteoResp.Hourly.WeatherCode[i])
+		forecast.IsDay[i] = i < len(openMeteoResp.Hourly.IsDay) && openMeteoResp.Hourly.IsDay[i] == 1
+		forecast.IconCode[i] = Icon(openMeteoResp.Hourly.WeatherCode[i], forecast.IsDay[i], IconSetTWC)
 		forecast.PrecipChance[i] = int(openMeteoResp.Hourly.PrecipitationProbability[i])
 		forecast.ValidTimeLocal[i] = timeStr
 		forecast.UVIndex[i] = int(openMeteoResp.Hourly.UvIndex[i])
@@ -512,8 +541,11 @@ func GetHourlyForecast(ctx context.Context, lat, lon float64, units string) (*Ho
 }
 
 // Helper functions
-func intPtr(i int) *int {
-	return &i
+// RoundTemperature rounds a temperature for display. Temperatures are carried unrounded until they're shown, so that
+// each consumer can decide how precise to be.
+func RoundTemperature(t float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(t*p) / p
 }
 
 func cardinalFromDegrees(degrees int) string {
@@ -521,81 +553,3 @@ func cardinalFromDegrees(degrees int) string {
 	index := int((float64(degrees)+11.25)/22.5) % 16
 	return directions[index]
 }
-
-func weatherCodeToDescription(code int) string {
-	// WMO Weather interpretation codes (WW)
-	// https://www.nodc.noaa.gov/archive/arc0021/0002199/1.1/data/0-data/HTML/WMO-CODE/WMO4677.HTM
-	switch {
-	case code == 0:
-		return "Clear sky"
-	case code == 1:
-		return "Mainly clear"
-	case code == 2:
-		return "Partly cloudy"
-	case code == 3:
-		return "Overcast"
-	case code >= 45 && code <= 48:
-		return "Fog"
-	case code >= 51 && code <= 55:
-		return "Drizzle"
-	case code >= 56 && code <= 57:
-		return "Freezing Drizzle"
-	case code >= 61 && code <= 65:
-		return "Rain"
-	case code >= 66 && code <= 67:
-		return "Freezing Rain"
-	case code >= 71 && code <= 75:
-		return "Snow"
-	case code == 77:
-		return "Snow grains"
-	case code >= 80 && code <= 82:
-		return "Rain showers"
-	case code >= 85 && code <= 86:
-		return "Snow showers"
-	case code == 95:
-		return "Thunderstorm"
-	case code >= 96 && code <= 99:
-		return "Thunderstorm with hail"
-	default:
-		return "Unknown"
-	}
-}
-
-func weatherCodeToIconCode(code int) int {
-	// Map Open-Meteo weather codes to original icon codes
-	// Using approximate mappings
-	switch {
-	case code == 0:
-		return 32 // Sunny
-	case code == 1:
-		return 34 // Mostly Sunny
-	case code == 2:
-		return 30 // Partly Cloudy
-	case code == 3:
-		return 26 // Cloudy
-	case code >= 45 && code <= 48:
-		return 20 // Fog
-	case code >= 51 && code <= 55:
-		return 11 // Drizzle
-	case code >= 56 && code <= 57:
-		return 8 // Freezing Drizzle
-	case code >= 61 && code <= 65:
-		return 12 // Rain
-	case code >= 66 && code <= 67:
-		return 10 // Freezing Rain
-	case code >= 71 && code <= 75:
-		return 16 // Snow
-	case code == 77:
-		return 16 // Snow grains
-	case code >= 80 && code <= 82:
-		return 39 // Rain showers
-	case code >= 85 && code <= 86:
-		return 41 // Snow showers
-	case code == 95:
-		return 4 // Thunderstorm
-	case code >= 96 && code <= 99:
-		return 17 // Thunderstorm with hail
-	default:
-		return 32 // Default sunny
-	}
-}
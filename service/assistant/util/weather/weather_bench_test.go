@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// benchmarkResponse returns an Open-Meteo response as it comes over the wire, with a week of daily forecasts and two
+// days of hourly ones, which is what we ask for.
+func benchmarkResponse(b *testing.B) []byte {
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	daily := &openMeteoDaily{}
+	for d := range 7 {
+		day := start.AddDate(0, 0, d)
+		daily.Time = append(daily.Time, day.Format("2006-01-02"))
+		daily.WeatherCode = append(daily.WeatherCode, []int{0, 2, 3, 61, 80, 95, 45}[d])
+		daily.TemperatureMax = append(daily.TemperatureMax, 20+float64(d))
+		daily.TemperatureMin = append(daily.TemperatureMin, 11+float64(d)/2)
+		daily.SunriseIso = append(daily.SunriseIso, day.Add(4*time.Hour+45*time.Minute).Format("2006-01-02T15:04"))
+		daily.SunsetIso = append(daily.SunsetIso, day.Add(21*time.Hour+10*time.Minute).Format("2006-01-02T15:04"))
+		daily.PrecipitationSum = append(daily.PrecipitationSum, float64(d%3))
+		daily.PrecipitationHours = append(daily.PrecipitationHours, float64(d%4))
+		daily.PrecipitationProbabilityMax = append(daily.PrecipitationProbabilityMax, float64(d*12))
+		daily.WindspeedMax = append(daily.WindspeedMax, 10+float64(d))
+		daily.WinddirectionDominant = append(daily.WinddirectionDominant, d*50)
+		daily.UvIndexMax = append(daily.UvIndexMax, 5)
+	}
+	hourly := &openMeteoHourly{}
+	for h := range 48 {
+		hourly.Time = append(hourly.Time, start.Add(time.Duration(h)*time.Hour).Format("2006-01-02T15:04"))
+		hourly.Temperature = append(hourly.Temperature, 12+float64(h%24)/2)
+		hourly.PrecipitationProbability = append(hourly.PrecipitationProbability, float64(h%10*10))
+		hourly.Precipitation = append(hourly.Precipitation, 0)
+		hourly.WeatherCode = append(hourly.WeatherCode, []int{0, 2, 3, 61}[h%4])
+		hourly.Visibility = append(hourly.Visibility, 24000)
+		hourly.Windspeed = append(hourly.Windspeed, 12)
+		hourly.WindDirection = append(hourly.WindDirection, 225)
+		hourly.UvIndex = append(hourly.UvIndex, 3)
+		hourly.IsDay = append(hourly.IsDay, map[bool]int{true: 1, false: 0}[h%24 >= 5 && h%24 < 21])
+		hourly.RelativeHumidity = append(hourly.RelativeHumidity, 70)
+		hourly.ApparentTemperature = append(hourly.ApparentTemperature, 11)
+	}
+	resp := openMeteoResponse{
+		Latitude:  51.5,
+		Longitude: -0.12,
+		Timezone:  "Europe/London",
+		Current: &openMeteoCurrent{
+			Time: "2025-06-02T14:00", Temperature: 19.3, RelativeHumidity: 58, ApparentTemperature: 18.1, IsDay: 1,
+			WeatherCode: 2, CloudCover: 40, Visibility: 24000, WindSpeed: 14, WindDirection: 230, UVIndex: 5,
+			PrecipChance: 10,
+		},
+		Daily:  daily,
+		Hourly: hourly,
+	}
+	j, err := json.Marshal(resp)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return j
+}
+
+func BenchmarkResponseConversion(b *testing.B) {
+	body := benchmarkResponse(b)
+	params, err := mapUnit("metric")
+	if err != nil {
+		b.Fatal(err)
+	}
+	// The daily narratives are written in the user's language.
+	ctx := query.ContextWith(context.Background(), url.Values{"lang": {"en-US"}})
+	benchmarks := []struct {
+		name    string
+		convert func(*openMeteoResponse) error
+	}{
+		{"daily", func(r *openMeteoResponse) error {
+			_, err := dailyForecastFromResponse(ctx, r, params)
+			return err
+		}},
+		{"current", func(r *openMeteoResponse) error {
+			_, err := currentConditionsFromResponse(r, params)
+			return err
+		}},
+		{"hourly", func(r *openMeteoResponse) error {
+			_, err := hourlyForecastFromResponse(r)
+			return err
+		}},
+	}
+	for _, bm := range benchmarks {
+		// Decoding is included, since it's most of the work of handling a response.
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for range b.N {
+				var resp openMeteoResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					b.Fatal(err)
+				}
+				if err := bm.convert(&resp); err != nil {
+					b.Fatal(fmt.Errorf("%s: %w", bm.name, err))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// verbosityLimits are the most characters of text a response can have at each verbosity, beyond which the rest is
+// cut off. The prompt asks for much less than this; the limits are there for when the model doesn't listen. Detailed
+// answers are left alone.
+var verbosityLimits = map[string]int{
+	"brief":  240,
+	"normal": 1200,
+	"":       1200,
+}
+
+var widgetTagRegex = regexp.MustCompile(`(?s)<!.+?[!/]>`)
+
+func generateVerbositySentence(ctx context.Context) string {
+	switch query.VerbosityFromContext(ctx) {
+	case "brief":
+		return "The user wants one-line answers: reply in a single short sentence, or just the answer itself, unless they ask for more. "
+	case "detailed":
+		return "The user wants detailed answers: explain your reasoning and include useful context, in up to a few short paragraphs. Still avoid padding. "
+	default:
+		return ""
+	}
+}
+
+// lengthLimiter cuts a streamed reply off once it's longer than the user's verbosity allows. It only counts text, in
+// characters rather than bytes, and always lets widgets through.
+type lengthLimiter struct {
+	limit int
+	used  int
+	done  bool
+}
+
+// newLengthLimiter returns a limiter for the verbosity in the context, or nil if responses shouldn't be limited.
+func newLengthLimiter(ctx context.Context) *lengthLimiter {
+	limit, ok := verbosityLimits[query.VerbosityFromContext(ctx)]
+	if !ok {
+		return nil
+	}
+	return &lengthLimiter{limit: limit}
+}
+
+// reset starts counting again from nothing, for the model's next reply.
+func (l *lengthLimiter) reset() {
+	if l == nil {
+		return
+	}
+	l.used = 0
+	l.done = false
+}
+
+// apply returns as much of the next chunk of the reply as fits, with any widgets left where they were.
+func (l *lengthLimiter) apply(chunk string) string {
+	if l == nil {
+		return chunk
+	}
+	return outsideWidgetTags(chunk, l.fit)
+}
+
+// fit returns as much of a run of text as fits in what's left of the limit.
+func (l *lengthLimiter) fit(text string) string {
+	if l.done {
+		return ""
+	}
+	length := utf8.RuneCountInString(text)
+	if l.used+length <= l.limit {
+		l.used += length
+		return text
+	}
+	// This takes it over the limit, so end at the last sentence that fits, or failing that the last word.
+	l.done = true
+	cut := len(text)
+	left := l.limit - l.used
+	for i := range text {
+		if left == 0 {
+			cut = i
+			break
+		}
+		left--
+	}
+	remaining := text[:cut]
+	if end := strings.LastIndexAny(remaining, ".!?。！？"); end >= 0 {
+		_, size := utf8.DecodeRuneInString(remaining[end:])
+		return remaining[:end+size]
+	}
+	// Languages written without spaces can be cut anywhere.
+	if next, _ := utf8.DecodeRuneInString(text[cut:]); !unicode.IsSpace(next) {
+		if space := strings.LastIndexFunc(remaining, unicode.IsSpace); space >= 0 {
+			remaining = remaining[:space]
+		}
+	}
+	return strings.TrimRightFunc(remaining, unicode.IsSpace) + "…"
+}
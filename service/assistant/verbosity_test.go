@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assistant
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+func TestLengthLimiterResetsEachReply(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"verbosity": {"brief"}})
+	l := newLengthLimiter(ctx)
+	first := strings.Repeat("Let me check. ", 20)
+	if got := l.apply(first); utf8.RuneCountInString(got) > verbosityLimits["brief"] || !strings.HasSuffix(got, ".") {
+		t.Errorf("apply(%q) = %q, want it cut at a sentence within the limit", first, got)
+	}
+	l.reset()
+	second := "It's 12 degrees and sunny."
+	if got := l.apply(second); got != second {
+		t.Errorf("after reset, apply(%q) = %q, want it unchanged", second, got)
+	}
+}
+
+func TestLengthLimiterCountsCharacters(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"verbosity": {"brief"}})
+	l := newLengthLimiter(ctx)
+	// Each of these is two bytes, so counting bytes would cut this off half way.
+	reply := strings.Repeat("é", 200)
+	if got := l.apply(reply); got != reply {
+		t.Errorf("apply(%q) = %q, want it unchanged", reply, got)
+	}
+
+	l.reset()
+	reply = strings.Repeat("Über schöne Brücken ", 20)
+	got := l.apply(reply)
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "Brücken…") {
+		t.Errorf("apply(%q) = %q, want it cut after a whole word", reply, got)
+	}
+	if n := utf8.RuneCountInString(got); n > verbosityLimits["brief"]+1 {
+		t.Errorf("apply(%q) is %d characters long, want no more than %d", reply, n, verbosityLimits["brief"]+1)
+	}
+}
+
+func TestLengthLimiterKeepsWidgetsInPlace(t *testing.T) {
+	ctx := query.ContextWith(context.Background(), url.Values{"verbosity": {"brief"}})
+	l := newLengthLimiter(ctx)
+	reply := "Here you go. <!weather-single-day location=\"London\" day=\"today\"!> " + strings.Repeat("Then more. ", 30)
+	got := l.apply(reply)
+	if !strings.HasPrefix(got, "Here you go. <!weather-single-day") {
+		t.Errorf("apply(%q) = %q, want the widget left after the first sentence", reply, got)
+	}
+	if got := l.apply("More text. <!map!>"); got != "<!map!>" {
+		t.Errorf("apply after the limit = %q, want only the widget", got)
+	}
+}
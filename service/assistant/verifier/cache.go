@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/honeycombio/beeline-go"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// actionCacheTime is how long to remember what a message claimed to do. The same confirmations turn up over and over
+// ("Okay, I've set a timer for 5 minutes."), and what they claim doesn't change, so this only needs to be short enough
+// that a change to the prompt takes effect reasonably quickly.
+const actionCacheTime = 6 * time.Hour
+
+// normalizeMessage reduces a message to what matters for DetermineActions, so that confirmations differing only in
+// case, spacing or numbers share a cache entry: setting a timer for 5 minutes is no different from setting one for 10.
+func normalizeMessage(message string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(message) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case unicode.IsDigit(r):
+			r = '0'
+		default:
+			r = unicode.ToLower(r)
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func actionCacheKey(message string) string {
+	h := sha256.Sum256([]byte(verifierModel + "\x00" + SYSTEM_PROMPT + "\x00" + normalizeMessage(message)))
+	return "verifier:actions:" + hex.EncodeToString(h[:])
+}
+
+// cachedActions returns what the message was found to claim last time it was seen, if it was seen recently. Failing
+// to reach the cache just means asking the model again.
+func cachedActions(ctx context.Context, message string) ([]ActionCheck, bool) {
+	data, err := storage.GetRedis().Get(ctx, actionCacheKey(message)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			beeline.AddField(ctx, "cache_error", err)
+		}
+		return nil, false
+	}
+	var checks []ActionCheck
+	if err := json.Unmarshal(data, &checks); err != nil {
+		return nil, false
+	}
+	return checks, true
+}
+
+func cacheActions(ctx context.Context, message string, checks []ActionCheck) {
+	data, err := json.Marshal(checks)
+	if err != nil {
+		return
+	}
+	if err := storage.GetRedis().Set(ctx, actionCacheKey(message), data, actionCacheTime).Err(); err != nil {
+		beeline.AddField(ctx, "cache_error", err)
+	}
+}
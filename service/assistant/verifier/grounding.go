@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// Grounding check modes, as set by GROUNDING_CHECK.
+const (
+	GroundingOff      = "off"
+	GroundingFlag     = "flag"
+	GroundingReprompt = "reprompt"
+)
+
+// Figures below this are usually counts, ordinals, or list items rather than facts looked up from somewhere, so we
+// don't try to ground them.
+const minGroundedFigure = 10
+
+// figureRegex matches numbers, allowing thousands separators and decimals. Numbers that are part of a time (like
+// 7:30) or a longer token (like a model number) are excluded by the caller.
+var figureRegex = regexp.MustCompile(`-?\d[\d,]*(?:\.\d+)?`)
+
+// FindUngroundedFigures returns the figures in the model's answer for the latest turn that don't appear in any tool
+// output from that turn, allowing for rounding and common unit conversions. It only looks at turns in which the model
+// called a tool: without one there is nothing to ground against, and the answer comes from general knowledge.
+func FindUngroundedFigures(messages []*genai.Content) []string {
+	start := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" && len(messages[i].Parts) > 0 && messages[i].Parts[0].Text != "" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	var known []float64
+	var answer strings.Builder
+	calledTool := false
+	known = append(known, extractFigures(messages[start].Parts[0].Text)...)
+	for _, m := range messages[start+1:] {
+		for _, part := range m.Parts {
+			if part.FunctionResponse != nil {
+				calledTool = true
+				j, err := json.Marshal(part.FunctionResponse.Response)
+				if err == nil {
+					known = append(known, extractFigures(string(j))...)
+				}
+			}
+			if part.FunctionCall != nil {
+				j, err := json.Marshal(part.FunctionCall.Args)
+				if err == nil {
+					known = append(known, extractFigures(string(j))...)
+				}
+			}
+			if m.Role == "model" && part.Text != "" {
+				answer.WriteString(part.Text)
+				answer.WriteString("\n")
+			}
+		}
+	}
+	if !calledTool {
+		return nil
+	}
+
+	var ungrounded []string
+	for _, match := range figureMatches(answer.String()) {
+		value, ok := parseFigure(match)
+		if !ok || math.Abs(value) < minGroundedFigure {
+			continue
+		}
+		if !isGrounded(value, known) {
+			ungrounded = append(ungrounded, match)
+		}
+	}
+	return ungrounded
+}
+
+// figureMatches returns the figures in text, skipping those that are part of times, dates or identifiers.
+func figureMatches(text string) []string {
+	var result []string
+	for _, loc := range figureRegex.FindAllStringIndex(text, -1) {
+		before, after := byte(' '), byte(' ')
+		if loc[0] > 0 {
+			before = text[loc[0]-1]
+		}
+		if loc[1] < len(text) {
+			after = text[loc[1]]
+		}
+		if before == ':' || after == ':' || before == '/' || after == '/' || isAlnum(before) {
+			continue
+		}
+		result = append(result, strings.TrimRight(text[loc[0]:loc[1]], ","))
+	}
+	return result
+}
+
+func isAlnum(b byte) bool {
+	return isLetter(b) || (b >= '0' && b <= '9')
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func extractFigures(text string) []float64 {
+	var result []float64
+	for _, match := range figureRegex.FindAllString(text, -1) {
+		if v, ok := parseFigure(match); ok {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func parseFigure(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimRight(s, ","), ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// conversions are the unit conversions the model is asked to make for the user, so a converted figure still counts
+// as grounded.
+var conversions = []func(float64) float64{
+	func(v float64) float64 { return v },
+	func(v float64) float64 { return v*9/5 + 32 },       // °C to °F
+	func(v float64) float64 { return (v - 32) * 5 / 9 }, // °F to °C
+	func(v float64) float64 { return v * 0.621371 },     // km to miles
+	func(v float64) float64 { return v * 1.609344 },     // miles to km
+	func(v float64) float64 { return v * 3.28084 },      // m to ft
+	func(v float64) float64 { return v * 0.3048 },       // ft to m
+	func(v float64) float64 { return v * 2.20462 },      // kg to lb
+	func(v float64) float64 { return v * 0.453592 },     // lb to kg
+	func(v float64) float64 { return v * 3.6 },          // m/s to km/h
+	func(v float64) float64 { return v * 2.23694 },      // m/s to mph
+	func(v float64) float64 { return v * 100 },          // fraction to percentage
+	func(v float64) float64 { return v / 1000 },         // e.g. metres to kilometres
+	func(v float64) float64 { return v / 1e6 },          // e.g. "3.4 million"
+	func(v float64) float64 { return v / 1e9 },          // e.g. "1.2 billion"
+}
+
+// isGrounded reports whether value matches any of the known figures, after rounding and unit conversion.
+func isGrounded(value float64, known []float64) bool {
+	for _, k := range known {
+		for _, convert := range conversions {
+			c := convert(k)
+			// Allow for rounding to the nearest whole number, or to two significant figures.
+			if math.Abs(c-value) <= 0.5 || math.Abs(c-value) <= math.Abs(c)*0.05 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GroundingPrompt is added to the system prompt when the model is asked to answer again, in place of an answer that
+// contained ungrounded figures. The user never saw that answer, so the new one has to stand on its own.
+func GroundingPrompt(figures []string) string {
+	return "A draft of your answer to this turn included these figures, which don't appear in any tool result: " +
+		strings.Join(figures, ", ") + ". The user hasn't seen that draft. Answer them in full again. If you can, " +
+		"verify those figures with a tool first and correct anything wrong. Otherwise, say briefly that they're " +
+		"estimates from memory and may be out of date. Don't mention the draft or this check. "
+}
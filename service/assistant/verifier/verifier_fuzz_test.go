@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func FuzzParseActionChecks(f *testing.F) {
+	f.Add(`[]`)
+	f.Add(`[{"topic":"alarm","action":"setting"}]`)
+	f.Add("```json\n[{\"topic\":\"timer\",\"action\":\"reporting\"}]\n```")
+	f.Add(`Here you go: [{"topic":"reminder","action":"setting"},{"topic":"cake","action":"eating"}]`)
+	f.Add(`[{"topic":"alarm"}`)
+	f.Add(`null`)
+	f.Fuzz(func(t *testing.T, text string) {
+		checks, err := parseActionChecks(text)
+		if err != nil {
+			if checks != nil {
+				t.Fatalf("parseActionChecks(%q) returned %v along with error %v", text, checks, err)
+			}
+			return
+		}
+		for _, c := range checks {
+			if !slices.Contains(checkTopics, c.Topic) || !slices.Contains(checkActions, c.Action) {
+				t.Errorf("parseActionChecks(%q) returned unexpected check %+v", text, c)
+			}
+		}
+		if json.Valid([]byte(text)) {
+			var direct []ActionCheck
+			if json.Unmarshal([]byte(text), &direct) == nil && len(checks) > len(direct) {
+				t.Errorf("parseActionChecks(%q) found more checks than the JSON holds", text)
+			}
+		}
+	})
+}
+
+func FuzzFigureMatches(f *testing.F) {
+	f.Add("It's 21°C, with 1,234 people at 10:30 on 3/4.")
+	f.Add("-5.5 and A380 and 1,000,000,")
+	f.Fuzz(func(t *testing.T, text string) {
+		for _, m := range figureMatches(text) {
+			if !strings.Contains(text, m) {
+				t.Errorf("figureMatches(%q) returned %q, which isn't in the text", text, m)
+			}
+			// Figures are compared as numbers, so parsing mustn't panic whatever the model wrote.
+			parseFigure(m)
+		}
+	})
+}
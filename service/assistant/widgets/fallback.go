@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/sports"
+)
+
+// Capability returns the name the watch uses to say it supports this kind of widget, as sent in the "widgets" query
+// parameter.
+func (w Widget) Capability() string {
+	if strings.HasPrefix(w.Type, "weather-") {
+		return "weather"
+	}
+	return w.Type
+}
+
+// FallbackText renders a widget as plain text, for watches that can't display it.
+func FallbackText(ctx context.Context, w Widget) string {
+	switch c := w.Content.(type) {
+	case *CurrentConditionsWidgetContent:
+		text := i18n.T(ctx, "widget.fallback.weather.current", c.Location, formatTemperature(ctx, c.Temperature), c.Unit, c.Description, formatTemperature(ctx, c.FeelsLike), c.Unit, c.WindSpeed, c.WindSpeedUnit)
+		if c.Humidity != nil {
+			text += " " + i18n.T(ctx, "widget.fallback.weather.humidity", *c.Humidity)
+		}
+		if c.PrecipChance != nil {
+			text += " " + i18n.T(ctx, "weather.narrative.precip", *c.PrecipChance)
+		}
+		if c.UVIndex != nil {
+			text += " " + i18n.T(ctx, "widget.fallback.weather.uv", *c.UVIndex)
+		}
+		return text
+	case *SingleDayWidgetContent:
+		return i18n.T(ctx, "widget.fallback.weather.day", c.Location, c.Day, c.Summary, formatTemperature(ctx, c.High), c.Unit, formatTemperature(ctx, c.Low), c.Unit)
+	case *MultiDayWidgetContent:
+		var days []string
+		for _, d := range c.Days {
+			days = append(days, i18n.T(ctx, "widget.fallback.weather.day_range", d.Day, formatTemperature(ctx, d.High), formatTemperature(ctx, d.Low)))
+		}
+		return i18n.T(ctx, "widget.fallback.weather.multi_day", c.Location, strings.Join(days, i18n.T(ctx, "list.separator")))
+	case *TimerWidget:
+		t, err := time.Parse(time.RFC3339, c.TargetTime)
+		if err != nil {
+			return ""
+		}
+		end := t.In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60)).Format(i18n.T(ctx, "format.time"))
+		if c.Name != "" {
+			return i18n.T(ctx, "widget.fallback.timer.named", c.Name, end)
+		}
+		return i18n.T(ctx, "widget.fallback.timer", end)
+	case *NumberWidget:
+		return strings.TrimSpace(c.Number + " " + c.Unit)
+	case *SportsWidget:
+		if c.State == string(sports.StatePostponed) {
+			return i18n.T(ctx, "widget.fallback.sports.postponed", c.HomeTeam, c.AwayTeam)
+		}
+		if c.HomeScore == nil || c.AwayScore == nil {
+			t, err := time.Parse(time.RFC3339, c.StartTime)
+			if err != nil {
+				return i18n.T(ctx, "widget.fallback.sports.fixture", c.HomeTeam, c.AwayTeam, c.StartTime)
+			}
+			t = t.In(time.FixedZone("local", query.TzOffsetFromContext(ctx)*60))
+			start := i18n.T(ctx, "weekday."+t.Weekday().String()) + " " + t.Format(i18n.T(ctx, "format.time"))
+			return i18n.T(ctx, "widget.fallback.sports.fixture", c.HomeTeam, c.AwayTeam, start)
+		}
+		return i18n.T(ctx, "widget.fallback.sports.score", c.HomeTeam, *c.HomeScore, *c.AwayScore, c.AwayTeam)
+	case *PronunciationWidget:
+		return i18n.T(ctx, "widget.fallback.pronunciation", c.Word, c.Respelling)
+	case *PageWidget:
+		return i18n.T(ctx, "widget.fallback.page", c.Title, c.Page, c.Pages) + "\n" + c.Text
+	}
+	return fmt.Sprint(w.Content)
+}
+
+// formatTemperature formats an already-rounded temperature with the user's decimal separator.
+func formatTemperature(ctx context.Context, t float64) string {
+	return strings.Replace(strconv.FormatFloat(t, 'f', -1, 64), ".", i18n.T(ctx, "format.decimal_separator"), 1)
+}
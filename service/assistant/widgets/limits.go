@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// The watch receives each widget as a single AppMessage, which has to fit in its 1024 byte inbox along with the
+// message's own overhead. These limits, in bytes, keep each field to what the watch can show anyway.
+const (
+	maxWidgetBytes       = 768
+	maxLocationBytes     = 40
+	maxSummaryBytes      = 120
+	maxDayBytes          = 16
+	maxUnitBytes         = 16
+	maxTimerNameBytes    = 64
+	maxNumberBytes       = 32
+	maxNumberUnitBytes   = 32
+	maxWatchMultiDayDays = 3
+	maxLeagueBytes       = 40
+	maxTeamBytes         = 32
+	maxProgressBytes     = 16
+	maxWordBytes         = 48
+	maxRespellingBytes   = 96
+	maxPageTitleBytes    = 48
+	maxPageTextBytes     = 560
+)
+
+// ErrWidgetTooLarge is returned by Marshal when a widget can't be made small enough for the watch.
+var ErrWidgetTooLarge = errors.New("widget too large")
+
+// Marshal encodes a widget for sending to the watch, first trimming it to fit. Widgets too large to send even then
+// return ErrWidgetTooLarge, and should be shown as text instead.
+func Marshal(widget any) ([]byte, error) {
+	if w, ok := widget.(Widget); ok {
+		widget = w.fit()
+	}
+	j, err := json.Marshal(widget)
+	if err != nil {
+		return nil, err
+	}
+	if len(j) > maxWidgetBytes {
+		return nil, fmt.Errorf("%w: %d bytes", ErrWidgetTooLarge, len(j))
+	}
+	return j, nil
+}
+
+// fit returns a copy of the widget with its content trimmed to the limits above. Content that's already small enough
+// is returned unchanged.
+func (w Widget) fit() Widget {
+	switch c := w.Content.(type) {
+	case *CurrentConditionsWidgetContent:
+		f := *c
+		f.Location = truncate(f.Location, maxLocationBytes)
+		f.Description = truncate(f.Description, maxSummaryBytes)
+		f.Unit = truncate(f.Unit, maxUnitBytes)
+		f.WindSpeedUnit = truncate(f.WindSpeedUnit, maxUnitBytes)
+		w.Content = &f
+	case *SingleDayWidgetContent:
+		f := *c
+		f.Location = truncate(f.Location, maxLocationBytes)
+		f.Day = truncate(f.Day, maxDayBytes)
+		f.Summary = truncate(f.Summary, maxSummaryBytes)
+		f.Unit = truncate(f.Unit, maxUnitBytes)
+		w.Content = &f
+	case *MultiDayWidgetContent:
+		f := MultiDayWidgetContent{Location: truncate(c.Location, maxLocationBytes)}
+		// The rest of the forecast is still used for the text fallback, but the watch only has room for these.
+		for i := 0; i < len(c.Days) && i < maxWatchMultiDayDays; i++ {
+			day := c.Days[i]
+			day.Day = truncate(day.Day, maxDayBytes)
+			f.Days = append(f.Days, day)
+		}
+		w.Content = &f
+	case *TimerWidget:
+		f := *c
+		f.Name = truncate(f.Name, maxTimerNameBytes)
+		w.Content = &f
+	case *NumberWidget:
+		f := *c
+		f.Number = truncate(f.Number, maxNumberBytes)
+		f.Unit = truncate(f.Unit, maxNumberUnitBytes)
+		w.Content = &f
+	case *SportsWidget:
+		f := *c
+		f.League = truncate(f.League, maxLeagueBytes)
+		f.HomeTeam = truncate(f.HomeTeam, maxTeamBytes)
+		f.AwayTeam = truncate(f.AwayTeam, maxTeamBytes)
+		f.Progress = truncate(f.Progress, maxProgressBytes)
+		w.Content = &f
+	case *PronunciationWidget:
+		f := *c
+		f.Word = truncate(f.Word, maxWordBytes)
+		f.Respelling = truncate(f.Respelling, maxRespellingBytes)
+		w.Content = &f
+	case *PageWidget:
+		f := *c
+		f.Title = truncate(f.Title, maxPageTitleBytes)
+		f.PageTitle = truncate(f.PageTitle, maxPageTitleBytes)
+		f.Text = truncate(f.Text, maxPageTextBytes)
+		w.Content = &f
+	}
+	return w
+}
+
+// truncate shortens s to at most n bytes, ending in an ellipsis, without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n-len("…")]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s + "…"
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/pages"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// PageWidget shows one page of a long document, like a story. The watch asks for the next page when the user wants
+// it, using the document ID, rather than being sent the whole thing at once.
+type PageWidget struct {
+	Document  string `json:"document"`
+	Title     string `json:"title"`
+	PageTitle string `json:"page_title,omitempty"`
+	Text      string `json:"text"`
+	// Numbered from 1.
+	Page  int `json:"page"`
+	Pages int `json:"pages"`
+}
+
+// NewPageWidget returns a page of a document, and records that the user has read that far.
+func NewPageWidget(ctx context.Context, userID int, documentID string, page int) (*PageWidget, error) {
+	rd := storage.GetRedis()
+	doc, err := pages.Get(ctx, rd, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := doc.Page(page)
+	if !ok {
+		return nil, fmt.Errorf("%w: the document has %d pages, not %d", pages.ErrNotFound, len(doc.Pages), page)
+	}
+	if err := pages.SetPosition(ctx, rd, userID, pages.Position{Document: doc.ID, Page: page}); err != nil {
+		// The page is still worth showing; the user just won't be able to pick up from here later.
+		requestid.Logf(ctx, "Storing reading position failed: %v", err)
+	}
+	return &PageWidget{
+		Document:  doc.ID,
+		Title:     doc.Title,
+		PageTitle: p.Title,
+		Text:      p.Text,
+		Page:      page,
+		Pages:     len(doc.Pages),
+	}, nil
+}
+
+func pageWidget(ctx context.Context, documentID, page string) (*PageWidget, error) {
+	requester, ok := schedule.RequesterFromContext(ctx)
+	if !ok {
+		return nil, errors.New("no user to show the page to")
+	}
+	n := 1
+	if page != "" {
+		var err error
+		if n, err = strconv.Atoi(page); err != nil {
+			return nil, fmt.Errorf("invalid page number %q: %w", page, err)
+		}
+	}
+	return NewPageWidget(ctx, requester.UserID, documentID, n)
+}
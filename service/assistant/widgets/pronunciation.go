@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/dictionary"
+)
+
+// PronunciationWidget shows how to say a word. The watch's fonts can't show IPA, so it gets a plain respelling with
+// the syllables broken up instead.
+type PronunciationWidget struct {
+	Word string `json:"word"`
+	// e.g. "pruh-nuhn-see-AY-shuhn", with the stressed syllable in capitals.
+	Respelling string `json:"respelling"`
+}
+
+func pronunciationWidget(ctx context.Context, word string) (*PronunciationWidget, error) {
+	entry, err := dictionary.Lookup(ctx, word)
+	if err != nil {
+		return nil, fmt.Errorf("looking up word failed: %w", err)
+	}
+	respelling := dictionary.Respell(entry.IPA)
+	if respelling == "" {
+		return nil, errors.New("the dictionary doesn't say how to pronounce it")
+	}
+	return &PronunciationWidget{
+		Word:       entry.Word,
+		Respelling: respelling,
+	}, nil
+}
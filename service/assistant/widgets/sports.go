@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/sports"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+)
+
+// refreshWindow is how long the watch can keep polling for a sports widget's score after it's shown.
+const refreshWindow = time.Hour
+
+// ErrRefreshExpired is returned by RefreshScore for tokens that have expired, or never existed.
+var ErrRefreshExpired = errors.New("refresh token expired")
+
+type SportsWidget struct {
+	League    string `json:"league"`
+	HomeTeam  string `json:"home_team"`
+	AwayTeam  string `json:"away_team"`
+	HomeScore *int   `json:"home_score,omitempty"`
+	AwayScore *int   `json:"away_score,omitempty"`
+	// One of "scheduled", "live", "finished" or "postponed".
+	State    string `json:"state"`
+	Progress string `json:"progress,omitempty"`
+	// When the event starts, in RFC 3339 format.
+	StartTime string `json:"start_time"`
+	// If the score might change soon, a token for the watch to poll RefreshScore with until RefreshUntil, so that it
+	// can keep the score up to date without starting a new conversation.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	RefreshUntil string `json:"refresh_until,omitempty"`
+}
+
+// ScoreUpdate is the latest score for a sports widget.
+type ScoreUpdate struct {
+	HomeScore *int   `json:"home_score,omitempty"`
+	AwayScore *int   `json:"away_score,omitempty"`
+	State     string `json:"state"`
+	Progress  string `json:"progress,omitempty"`
+}
+
+func refreshKey(token string) string {
+	return "sports_refresh:" + token
+}
+
+func sportsWidget(ctx context.Context, eventID string) (*SportsWidget, error) {
+	event, err := sports.LookupEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("looking up event failed: %w", err)
+	}
+	widget := &SportsWidget{
+		League:    event.League,
+		HomeTeam:  event.HomeTeam,
+		AwayTeam:  event.AwayTeam,
+		HomeScore: event.HomeScore,
+		AwayScore: event.AwayScore,
+		State:     string(event.State),
+		Progress:  event.Progress,
+		StartTime: event.Start.Format(time.RFC3339),
+	}
+	// Only hand out a token if there's going to be something to see in the next hour.
+	if event.State == sports.StateLive || (event.State == sports.StateScheduled && time.Until(event.Start) < refreshWindow) {
+		token := uuid.NewString()
+		if err := storage.GetRedis().Set(ctx, refreshKey(token), eventID, refreshWindow).Err(); err != nil {
+			// The widget's still worth showing, it just won't update.
+			requestid.Logf(ctx, "Storing sports refresh token failed: %v", err)
+		} else {
+			widget.RefreshToken = token
+			widget.RefreshUntil = time.Now().Add(refreshWindow).UTC().Format(time.RFC3339)
+		}
+	}
+	return widget, nil
+}
+
+// RefreshScore returns the latest score for the sports widget that was given the token, if it hasn't expired.
+func RefreshScore(ctx context.Context, token string) (*ScoreUpdate, error) {
+	eventID, err := storage.GetRedis().Get(ctx, refreshKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrRefreshExpired
+		}
+		return nil, err
+	}
+	event, err := sports.LookupEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &ScoreUpdate{
+		HomeScore: event.HomeScore,
+		AwayScore: event.AwayScore,
+		State:     string(event.State),
+		Progress:  event.Progress,
+	}, nil
+}
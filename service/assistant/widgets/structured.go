@@ -0,0 +1,193 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
+)
+
+// In JSON mode, instead of writing widget tags into its answer, the model writes the answer as a list of parts, each
+// either some text or a widget, using Gemini's structured output. There's nothing to parse, so there are no tags that
+// don't quite match the syntax.
+
+// Answer is an answer written in JSON mode.
+type Answer struct {
+	Parts []AnswerPart `json:"parts"`
+}
+
+// AnswerPart is one part of an Answer: either some text, or a widget.
+type AnswerPart struct {
+	Text   string            `json:"text,omitempty"`
+	Widget *StructuredWidget `json:"widget,omitempty"`
+}
+
+// StructuredWidget is a widget in an Answer. Which of the fields matter depends on its type; they're the attributes
+// of the matching widget tag.
+type StructuredWidget struct {
+	Type       string `json:"type"`
+	Location   string `json:"location,omitempty"`
+	Units      string `json:"units,omitempty"`
+	Day        string `json:"day,omitempty"`
+	Details    string `json:"details,omitempty"`
+	TargetTime string `json:"target_time,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Event      string `json:"event,omitempty"`
+	Word       string `json:"word,omitempty"`
+	Document   string `json:"document,omitempty"`
+	Page       string `json:"page,omitempty"`
+	Number     string `json:"number,omitempty"`
+	Unit       string `json:"unit,omitempty"`
+}
+
+// structuredTypes are the widget types an Answer can have, which are the same as the types of the widgets sent to
+// the watch.
+var structuredTypes = []string{"weather-current", "weather-single-day", "weather-multi-day", "timer", "sports", "pronunciation", "page", "number"}
+
+func (w StructuredWidget) tag() tag {
+	return tag{kind: w.Type, attrs: map[string]string{
+		"location":   w.Location,
+		"units":      w.Units,
+		"day":        w.Day,
+		"details":    w.Details,
+		"targetTime": w.TargetTime,
+		"name":       w.Name,
+		"event":      w.Event,
+		"word":       w.Word,
+		"document":   w.Document,
+		"page":       w.Page,
+		"number":     w.Number,
+		"unit":       w.Unit,
+	}}
+}
+
+// ProcessStructured is ProcessWidget for a widget in an answer written in JSON mode.
+func ProcessStructured(ctx context.Context, w StructuredWidget) (any, error) {
+	return processTag(ctx, w.tag())
+}
+
+// AnswerSchema returns the schema for answers written in JSON mode, which only allows the widgets the watch in ctx
+// can show.
+func AnswerSchema(ctx context.Context) *genai.Schema {
+	var types []string
+	for _, t := range structuredTypes {
+		if query.SupportsWidget(ctx, Widget{Type: t}.Capability()) {
+			types = append(types, t)
+		}
+	}
+	str := func(description string) *genai.Schema {
+		return &genai.Schema{Type: genai.TypeString, Description: description}
+	}
+	part := &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"text": str("A sentence or two of the answer. Leave it out if this part is a widget."),
+		},
+	}
+	if len(types) > 0 {
+		part.Properties["widget"] = &genai.Schema{
+			Type:        genai.TypeObject,
+			Description: "A widget to show at this point in the answer. Leave it out if this part is text.",
+			Properties: map[string]*genai.Schema{
+				"type":        {Type: genai.TypeString, Enum: types},
+				"location":    str("For weather widgets: the place, or 'here' for the user's location."),
+				"units":       {Type: genai.TypeString, Enum: []string{"metric", "imperial", "uk hybrid"}, Description: "For weather widgets."},
+				"day":         str("For weather-single-day: today, tomorrow, a weekday or a date."),
+				"details":     str("For weather-current: the extra details to show, separated by commas."),
+				"target_time": str("For timer: when the timer goes off, in ISO 8601 format."),
+				"name":        str("For timer: the timer's name, if it has one."),
+				"event":       str("For sports: the event ID from get_sports_fixtures."),
+				"word":        str("For pronunciation: the word."),
+				"document":    str("For page: the document ID from write_pages."),
+				"page":        str("For page: the page number."),
+				"number":      str("For number: the number."),
+				"unit":        str("For number: the unit, if there is one."),
+			},
+			Required: []string{"type"},
+		}
+	}
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"parts": {Type: genai.TypeArray, Items: part},
+		},
+		Required: []string{"parts"},
+	}
+}
+
+// AnswerDecoder picks the complete parts out of an answer written in JSON mode as it streams in, so that each can be
+// sent to the watch as soon as it's finished.
+type AnswerDecoder struct {
+	buf  []byte
+	sent int
+}
+
+// Write adds the next chunk of the answer, returning any parts it completed.
+func (d *AnswerDecoder) Write(chunk string) []AnswerPart {
+	d.buf = append(d.buf, chunk...)
+	// Answers are short, so decoding everything again each time is cheap enough, and much simpler than keeping track
+	// of where the decoder got to.
+	dec := json.NewDecoder(bytes.NewReader(d.buf))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil
+	}
+	var parts []AnswerPart
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return parts
+		}
+		if key != "parts" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return parts
+			}
+			continue
+		}
+		if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+			return parts
+		}
+		for i := 0; dec.More(); i++ {
+			var p AnswerPart
+			if err := dec.Decode(&p); err != nil {
+				return parts
+			}
+			if i >= d.sent {
+				parts = append(parts, p)
+				d.sent++
+			}
+		}
+		return parts
+	}
+	return parts
+}
+
+// Close checks that the whole answer was valid.
+func (d *AnswerDecoder) Close() error {
+	var a Answer
+	if err := json.Unmarshal(d.buf, &a); err != nil {
+		return err
+	}
+	if len(a.Parts) != d.sent {
+		return errors.New("answer ended with parts that weren't sent")
+	}
+	return nil
+}
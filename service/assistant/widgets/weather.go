@@ -18,32 +18,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/i18n"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/query"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/photon"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/weather"
-	"log"
 	"strings"
 )
 
 type SingleDayWidgetContent struct {
-	Location  string `json:"location"`
-	Day       string `json:"day"`
-	Condition int    `json:"condition"`
-	Unit      string `json:"unit"`
-	Summary   string `json:"summary"`
-	High      int    `json:"high"`
-	Low       int    `json:"low"`
+	Location  string  `json:"location"`
+	Day       string  `json:"day"`
+	Condition int     `json:"condition"`
+	Unit      string  `json:"unit"`
+	Summary   string  `json:"summary"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	// The number of decimal places the temperatures have been rounded to.
+	Decimals int `json:"decimals"`
 }
 
 type CurrentConditionsWidgetContent struct {
-	Location      string `json:"location"`
-	Condition     int    `json:"condition"`
-	Temperature   int    `json:"temperature"`
-	FeelsLike     int    `json:"feels_like"`
-	Unit          string `json:"unit"`
-	Description   string `json:"description"`
-	WindSpeed     int    `json:"wind_speed"`
-	WindSpeedUnit string `json:"wind_speed_unit"`
+	Location      string  `json:"location"`
+	Condition     int     `json:"condition"`
+	Temperature   float64 `json:"temperature"`
+	FeelsLike     float64 `json:"feels_like"`
+	Unit          string  `json:"unit"`
+	Description   string  `json:"description"`
+	WindSpeed     int     `json:"wind_speed"`
+	WindSpeedUnit string  `json:"wind_speed_unit"`
+	// The number of decimal places the temperatures have been rounded to.
+	Decimals int `json:"decimals"`
+	// Optional extra details, only present if asked for; see WeatherDetails.
+	Humidity     *int `json:"humidity,omitempty"`
+	PrecipChance *int `json:"precip_chance,omitempty"`
+	UVIndex      *int `json:"uv_index,omitempty"`
 }
 
 type MultiDayWidgetContent struct {
@@ -52,10 +61,10 @@ type MultiDayWidgetContent struct {
 }
 
 type MultiDayWidgetContentDay struct {
-	Day       string `json:"day"`
-	Condition int    `json:"condition"`
-	High      int    `json:"high"`
-	Low       int    `json:"low"`
+	Day       string  `json:"day"`
+	Condition int     `json:"condition"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
 }
 
 var tempUnitMap = map[string]string{
@@ -70,10 +79,41 @@ var windSpeedUnitMap = map[string]string{
 	"uk hybrid": "mph",
 }
 
+// WeatherDetails are the extra details a current conditions widget can be asked to show, with its details attribute.
+var WeatherDetails = []string{"humidity", "precip", "uv"}
+
+// weatherDetailsMinVersion is the first version of the app that can show weather details.
+const weatherDetailsMinVersion = "1.3.0"
+
+// SupportsWeatherDetails reports whether the watch can show WeatherDetails in its current conditions widget.
+func SupportsWeatherDetails(ctx context.Context) bool {
+	return query.AppVersionAtLeast(ctx, weatherDetailsMinVersion)
+}
+
+// multiDayWidgetDays is how many days fit in a multi-day weather widget.
+const multiDayWidgetDays = 7
+
+// temperatureDecimals returns how many decimal places to show temperatures with. Only metric temperatures get a decimal
+// place, when the watch asks for one: Fahrenheit degrees are small enough already.
+func temperatureDecimals(ctx context.Context, units string) int {
+	if units == "imperial" || query.TemperatureDecimalsFromContext(ctx) < 1 {
+		return 0
+	}
+	return 1
+}
+
+// iconSet returns the weather icon set the watch understands. Watches that don't say get Weather Company codes.
+func iconSet(ctx context.Context) weather.IconSet {
+	if set := query.IconSetFromContext(ctx); set != "" {
+		return weather.IconSet(set)
+	}
+	return weather.IconSetTWC
+}
+
 func resolveLocation(ctx context.Context, location string) (string, query.Location, error) {
 	var lat, lon float64
 	if location == "here" {
-		location := query.LocationFromContext(ctx)
+		location := query.CoarseLocationFromContext(ctx)
 		if location == nil {
 			return "", query.Location{}, errors.New("can't get location without permission")
 		}
@@ -81,7 +121,7 @@ func resolveLocation(ctx context.Context, location string) (string, query.Locati
 		lon = location.Lon
 	} else {
 		// Look up the location
-		coords, err := photon.GeocodeWithContext(ctx, location)
+		coords, err := photon.GeocodeWithContext(ctx, location, photon.Settlement)
 		if err != nil {
 			return "", query.Location{}, fmt.Errorf("geocding location failed: %w", err)
 		}
@@ -110,70 +150,86 @@ func singleDayWeatherWidget(ctx context.Context, placeName, units, date string)
 		return nil, fmt.Errorf("getting daily forecast failed: %w", err)
 	}
 
-	dayIndex := -1
-	switch date {
-	case "today":
-		dayIndex = 0
-	case "tomorrow":
-		dayIndex = 1
-	default:
-		for i, day := range w.DayOfWeek {
-			if strings.EqualFold(day, date) {
-				dayIndex = i
-				break
-			}
-		}
+	// Days are worked out in the place's own timezone, which its forecast is in, so "tomorrow" in Tokyo is Tokyo's
+	// tomorrow.
+	dates, err := weather.ResolveDays(date, w.Now())
+	if err != nil {
+		return nil, err
 	}
-	if dayIndex == -1 {
-		return nil, fmt.Errorf("could not find day %q", date)
+	// A single-day widget can only show one day, so "this weekend" shows the first day of it.
+	dayIndex, err := w.DayIndex(dates[0])
+	if err != nil {
+		return nil, err
 	}
 
+	decimals := temperatureDecimals(ctx, units)
 	widget := &SingleDayWidgetContent{
 		Location: locationDisplayName,
-		Day:      w.DayOfWeek[dayIndex],
-		High:     w.CalendarDayTemperatureMax[dayIndex],
-		Low:      w.CalendarDayTemperatureMin[dayIndex],
+		Day:      i18n.T(ctx, "weekday."+w.DayOfWeek[dayIndex]),
+		High:     weather.RoundTemperature(w.CalendarDayTemperatureMax[dayIndex], decimals),
+		Low:      weather.RoundTemperature(w.CalendarDayTemperatureMin[dayIndex], decimals),
 		Unit:     tempUnitMap[units],
+		Decimals: decimals,
 	}
 
-	if len(w.DayParts) == 0 {
+	dayPartIndex := dayIndex * 2
+	if dayPartIndex+1 >= len(w.DayParts) {
 		return nil, fmt.Errorf("no day parts found")
 	}
-
-	dayPart := w.DayParts[0]
-
-	dayPartIndex := dayIndex * 2
-	if dayPart.DaypartName[dayPartIndex] == nil {
+	if !w.DayParts[dayPartIndex].Valid {
 		dayPartIndex++
 	}
 
-	widget.Condition = *dayPart.IconCode[dayPartIndex]
-	widget.Summary = *dayPart.WxPhraseLong[dayPartIndex]
+	widget.Condition = weather.Icon(w.WeatherCode[dayIndex], dayPartIndex%2 == 0, iconSet(ctx))
+	widget.Summary = i18n.T(ctx, "weather.condition."+w.DayParts[dayPartIndex].WxPhraseLong)
 
 	return widget, nil
 }
 
-func currentConditionsWeatherWidget(ctx context.Context, placeName, units string) (*CurrentConditionsWidgetContent, error) {
+// currentConditionsWeatherWidget returns the current weather at the place. details is a comma-separated list of
+// WeatherDetails to include, which is ignored if the watch can't show them.
+func currentConditionsWeatherWidget(ctx context.Context, placeName, units, details string) (*CurrentConditionsWidgetContent, error) {
 	locationDisplayName, location, err := resolveLocation(ctx, placeName)
 	if err != nil {
-		log.Printf("Error resolving location: %v", err)
+		requestid.Logf(ctx, "Error resolving location: %v", err)
 		return nil, fmt.Errorf("resolving location failed: %w", err)
 	}
 	conditions, err := weather.GetCurrentConditions(ctx, location.Lat, location.Lon, units)
 	if err != nil {
-		log.Printf("Error getting current conditions: %v", err)
+		requestid.Logf(ctx, "Error getting current conditions: %v", err)
 		return nil, fmt.Errorf("getting current conditions failed: %w", err)
 	}
-	return &CurrentConditionsWidgetContent{
+	decimals := temperatureDecimals(ctx, units)
+	widget := &CurrentConditionsWidgetContent{
 		Location:      locationDisplayName,
-		Condition:     conditions.IconCode,
-		Temperature:   conditions.Temperature,
-		FeelsLike:     conditions.TemperatureFeelsLike,
+		Condition:     weather.Icon(conditions.WeatherCode, conditions.IsDay, iconSet(ctx)),
+		Temperature:   weather.RoundTemperature(conditions.Temperature, decimals),
+		FeelsLike:     weather.RoundTemperature(conditions.TemperatureFeelsLike, decimals),
 		Unit:          tempUnitMap[units],
-		Description:   conditions.Description,
+		Description:   i18n.T(ctx, "weather.condition."+conditions.Description),
 		WindSpeed:     conditions.WindSpeed,
 		WindSpeedUnit: windSpeedUnitMap[units],
-	}, nil
+		Decimals:      decimals,
+	}
+	if details == "" || !SupportsWeatherDetails(ctx) {
+		return widget, nil
+	}
+	for _, d := range strings.Split(details, ",") {
+		switch strings.ToLower(strings.TrimSpace(d)) {
+		case "humidity":
+			v := conditions.RelativeHumidity
+			widget.Humidity = &v
+		case "precip":
+			v := conditions.PrecipChance
+			widget.PrecipChance = &v
+		case "uv":
+			v := conditions.UVIndex
+			widget.UVIndex = &v
+		default:
+			requestid.Logf(ctx, "Ignoring unknown weather detail %q", d)
+		}
+	}
+	return widget, nil
 }
 
 func multiDayWeatherWidget(ctx context.Context, placeName, units string) (*MultiDayWidgetContent, error) {
@@ -192,18 +248,14 @@ func multiDayWeatherWidget(ctx context.Context, placeName, units string) (*Multi
 		Location: locationDisplayName,
 	}
 
-	for i := 0; i < len(w.DayOfWeek); i++ {
+	for i := 0; i < len(w.DayOfWeek) && i < multiDayWidgetDays; i++ {
 		day := MultiDayWidgetContentDay{
-			Day:  w.DayOfWeek[i],
-			High: w.CalendarDayTemperatureMax[i],
-			Low:  w.CalendarDayTemperatureMin[i],
-		}
-		dayPartIndex := i * 2
-		if w.DayParts[0].IconCode[dayPartIndex] != nil {
-			day.Condition = *w.DayParts[0].IconCode[dayPartIndex]
-		} else {
-			day.Condition = *w.DayParts[0].IconCode[dayPartIndex+1]
+			Day: i18n.T(ctx, "weekday."+w.DayOfWeek[i]),
+			// There's no room for decimals in the multi-day widget.
+			High: weather.RoundTemperature(w.CalendarDayTemperatureMax[i], 0),
+			Low:  weather.RoundTemperature(w.CalendarDayTemperatureMin[i], 0),
 		}
+		day.Condition = weather.Icon(w.WeatherCode[i], true, iconSet(ctx))
 		widget.Days = append(widget.Days, day)
 	}
 
@@ -3,12 +3,16 @@ package widgets
 import (
 	"context"
 	"fmt"
-	"log"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/requestid"
 	"regexp"
+	"strings"
 )
 
 var timerWidgetRegex = regexp.MustCompile(`<!TIMER targetTime=[\["]?(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d{0,5})?(?:Z|[+-](?:\d{4}|\d\d:\d\d)))[]"!]? ?(?: name=[\["]?(.*?)[]"]?)?[!/]>`)
-var weatherWidgetRegex = regexp.MustCompile(`<!WEATHER-(CURRENT|SINGLE-DAY|MULTI-DAY) location=[\["]?(.+?)[]"!]? units=[\["]?(imperial|metric|uk hybrid)[]"!]?(?: day=[\["]?(.+?)[]"]?)?[!/]>`)
+var weatherWidgetRegex = regexp.MustCompile(`<!WEATHER-(CURRENT|SINGLE-DAY|MULTI-DAY) location=[\["]?(.+?)[]"!]? units=[\["]?(imperial|metric|uk hybrid)[]"!]?(?: day=[\["]?(.+?)[]"]?)?(?: details=[\["]?(.+?)[]"]?)?[!/]>`)
+var sportsWidgetRegex = regexp.MustCompile(`<!SPORTS-FIXTURE event=[\["]?(\d+)[]"!]?[!/]>`)
+var pronunciationWidgetRegex = regexp.MustCompile(`<!PRONUNCIATION word=[\["]?(.+?)[]"!]?[!/]>`)
+var pageWidgetRegex = regexp.MustCompile(`<!PAGE document=[\["]?([0-9a-f-]+)[]"!]?(?: page=[\["]?(\d+)[]"]?)?[!/]>`)
 var numberWidgetRegex = regexp.MustCompile(`<!NUMERIC-ANSWER number=[\["]?(.+?)[]"!]? ?(?: unit=[\["]?(.*?)[]"]?)?[!/]>`)
 
 type Widget struct {
@@ -16,52 +20,86 @@ type Widget struct {
 	Type    string `json:"type"`
 }
 
-func ProcessWidget(ctx context.Context, widget string) (any, error) {
-	weatherWidgets := weatherWidgetRegex.FindAllStringSubmatch(widget, -1)
-	for _, weatherWidget := range weatherWidgets {
-		switch weatherWidget[1] {
-		case "CURRENT":
-			widget, err := currentConditionsWeatherWidget(ctx, weatherWidget[2], weatherWidget[3])
-			if err != nil {
-				log.Printf("Error processing weather widget: %v", err)
-				return nil, fmt.Errorf("error processing weather widget: %w", err)
-			}
-			return Widget{Content: widget, Type: "weather-current"}, nil
-		case "SINGLE-DAY":
-			widget, err := singleDayWeatherWidget(ctx, weatherWidget[2], weatherWidget[3], weatherWidget[4])
-			if err != nil {
-				log.Printf("Error processing weather widget: %v", err)
-				return nil, fmt.Errorf("error processing weather widget: %w", err)
-			}
-			return Widget{Content: widget, Type: "weather-single-day"}, nil
-		case "MULTI-DAY":
-			widget, err := multiDayWeatherWidget(ctx, weatherWidget[2], weatherWidget[3])
-			if err != nil {
-				log.Printf("Error processing weather widget: %v", err)
-				return nil, fmt.Errorf("error processing weather widget: %w", err)
-			}
-			return Widget{Content: widget, Type: "weather-multi-day"}, nil
-		default:
-			log.Printf("Unknown weather widget %q", weatherWidget[1])
+// tag is a widget tag the model wrote, e.g. <!TIMER targetTime=[...] name=[...]!>, split into the kind of widget and
+// its attributes. Attributes the model left out are empty.
+type tag struct {
+	kind  string
+	attrs map[string]string
+}
+
+// tagPatterns are the widget tags we understand, in the order they're tried, with the names of each one's
+// attributes.
+var tagPatterns = []struct {
+	regex *regexp.Regexp
+	// The kind of widget, or "" to take it from the first submatch (for weather widgets).
+	kind  string
+	attrs []string
+}{
+	{weatherWidgetRegex, "", []string{"location", "units", "day", "details"}},
+	{timerWidgetRegex, "timer", []string{"targetTime", "name"}},
+	{sportsWidgetRegex, "sports", []string{"event"}},
+	{pronunciationWidgetRegex, "pronunciation", []string{"word"}},
+	{pageWidgetRegex, "page", []string{"document", "page"}},
+	{numberWidgetRegex, "number", []string{"number", "unit"}},
+}
+
+// parseTag finds the first widget tag we understand in widget.
+func parseTag(widget string) (tag, bool) {
+	for _, p := range tagPatterns {
+		m := p.regex.FindStringSubmatch(widget)
+		if m == nil {
+			continue
 		}
-	}
-	timerWidgets := timerWidgetRegex.FindAllStringSubmatch(widget, -1)
-	for _, w := range timerWidgets {
-		widget, err := timerWidget(ctx, w[1], w[2])
-		if err != nil {
-			log.Printf("Error processing timer widget: %v", err)
-			return nil, fmt.Errorf("error processing timer widget: %w", err)
+		m = m[1:]
+		t := tag{kind: p.kind, attrs: map[string]string{}}
+		if t.kind == "" {
+			t.kind = "weather-" + strings.ToLower(m[0])
+			m = m[1:]
 		}
-		return Widget{Content: widget, Type: "timer"}, nil
-	}
-	numberWidgets := numberWidgetRegex.FindAllStringSubmatch(widget, -1)
-	for _, w := range numberWidgets {
-		widget, err := numberWidget(ctx, w[1], w[2])
-		if err != nil {
-			log.Printf("Error processing number widget: %v", err)
-			return nil, fmt.Errorf("error processing number widget: %w", err)
+		for i, name := range p.attrs {
+			t.attrs[name] = m[i]
 		}
-		return Widget{Content: widget, Type: "number"}, nil
+		return t, true
+	}
+	return tag{}, false
+}
+
+func ProcessWidget(ctx context.Context, widget string) (any, error) {
+	t, ok := parseTag(widget)
+	if !ok {
+		return nil, fmt.Errorf("unknown widget %q", widget)
+	}
+	return processTag(ctx, t)
+}
+
+func processTag(ctx context.Context, t tag) (any, error) {
+	a := t.attrs
+	var content any
+	var err error
+	switch t.kind {
+	case "weather-current":
+		content, err = currentConditionsWeatherWidget(ctx, a["location"], a["units"], a["details"])
+	case "weather-single-day":
+		content, err = singleDayWeatherWidget(ctx, a["location"], a["units"], a["day"])
+	case "weather-multi-day":
+		content, err = multiDayWeatherWidget(ctx, a["location"], a["units"])
+	case "timer":
+		content, err = timerWidget(ctx, a["targetTime"], a["name"])
+	case "sports":
+		content, err = sportsWidget(ctx, a["event"])
+	case "pronunciation":
+		content, err = pronunciationWidget(ctx, a["word"])
+	case "page":
+		content, err = pageWidget(ctx, a["document"], a["page"])
+	case "number":
+		content, err = numberWidget(ctx, a["number"], a["unit"])
+	default:
+		return nil, fmt.Errorf("unknown widget type %q", t.kind)
+	}
+	if err != nil {
+		name, _, _ := strings.Cut(t.kind, "-")
+		requestid.Logf(ctx, "Error processing %s widget: %v", name, err)
+		return nil, fmt.Errorf("error processing %s widget: %w", name, err)
 	}
-	return nil, fmt.Errorf("unknown widget %q", widget)
+	return Widget{Content: content, Type: t.kind}, nil
 }
@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"strings"
+	"testing"
+)
+
+func BenchmarkMarshal(b *testing.B) {
+	humidity, uv := 64, 3
+	days := make([]MultiDayWidgetContentDay, 7)
+	for i := range days {
+		days[i] = MultiDayWidgetContentDay{Day: "Wednesday", Condition: 3, High: 21.5, Low: 12}
+	}
+	benchmarks := []struct {
+		name   string
+		widget any
+	}{
+		{"current", Widget{Type: "weather-current", Content: &CurrentConditionsWidgetContent{
+			Location: "London", Condition: 2, Temperature: 18.4, FeelsLike: 17, Unit: "°C", Description: "Partly cloudy",
+			WindSpeed: 14, WindSpeedUnit: "km/h", Decimals: 1, Humidity: &humidity, UVIndex: &uv,
+		}}},
+		{"single_day", Widget{Type: "weather-single-day", Content: &SingleDayWidgetContent{
+			Location: "Paris", Day: "Tuesday", Condition: 61, Unit: "°C", Summary: "Rain in the afternoon", High: 19, Low: 11,
+		}}},
+		{"multi_day", Widget{Type: "weather-multi-day", Content: &MultiDayWidgetContent{Location: "Tokyo", Days: days}}},
+		// Content this long has to be trimmed to fit before it's sent.
+		{"trimmed", Widget{Type: "weather-single-day", Content: &SingleDayWidgetContent{
+			Location: strings.Repeat("Llanfairpwllgwyngyll ", 20), Day: "Tuesday", Summary: strings.Repeat("Showers. ", 100),
+		}}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := Marshal(bm.widget); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseTag(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		parseTag(`<!WEATHER-SINGLE-DAY location=[Paris, France] units=[metric] day=[Tuesday]!>`)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgets
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseTag(f *testing.F) {
+	f.Add(`<!TIMER targetTime=[2025-03-01T10:00:00Z] name=[Pasta]!>`)
+	f.Add(`<!WEATHER-CURRENT location=[London] units=[metric] details=[wind]!>`)
+	f.Add(`<!WEATHER-SINGLE-DAY location="Paris, France" units=uk hybrid day=[Tuesday]/>`)
+	f.Add(`<!WEATHER-MULTI-DAY location=[Tokyo] units=[imperial]!>`)
+	f.Add(`<!SPORTS-FIXTURE event=[12345]!>`)
+	f.Add(`<!PRONUNCIATION word=[quinoa]!>`)
+	f.Add(`<!PAGE document=[0a1b-2c3d] page=[2]!>`)
+	f.Add(`<!NUMERIC-ANSWER number=[42] unit=[km]!>`)
+	f.Add(`<!UNKNOWN thing=[x]!>`)
+	f.Fuzz(func(t *testing.T, widget string) {
+		tag, ok := parseTag(widget)
+		if !ok {
+			if tag.kind != "" || tag.attrs != nil {
+				t.Fatalf("parseTag(%q) failed but returned %+v", widget, tag)
+			}
+			return
+		}
+		if tag.kind == "" {
+			t.Fatalf("parseTag(%q) returned no kind", widget)
+		}
+		for name, value := range tag.attrs {
+			if !strings.Contains(widget, value) {
+				t.Errorf("parseTag(%q) returned %s=%q, which isn't in the tag", widget, name, value)
+			}
+		}
+	})
+}
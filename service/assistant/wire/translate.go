@@ -0,0 +1,171 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/proto/sessionpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// widgetRegex matches a widget embedded in a response, as the original protocol sends it.
+var widgetRegex = regexp.MustCompile(`(?s)<<!!WIDGET:(.*?)!!>>`)
+
+// The JSON in the original protocol uses the same names as the schema, so it can be read straight into the protobufs.
+// Anything the schema doesn't know about yet is left out, rather than failing the whole message.
+var jsonOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// Translate turns a message in the original protocol into the protobuf that replaces it.
+func Translate(message []byte) (*sessionpb.ServerMessage, error) {
+	if len(message) == 0 {
+		return nil, errors.New("empty message")
+	}
+	body := message[1:]
+	switch message[0] {
+	case 'c':
+		content, err := translateContent(string(body))
+		if err != nil {
+			return nil, err
+		}
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Content{Content: content}}, nil
+	case 'f':
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Thought{Thought: string(body)}}, nil
+	case 'p':
+		progress, err := translateProgress(body)
+		if err != nil {
+			return nil, err
+		}
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Progress{Progress: progress}}, nil
+	case 'a':
+		action, err := translateAction(body)
+		if err != nil {
+			return nil, err
+		}
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Action{Action: action}}, nil
+	case 'w':
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Warning{Warning: string(body)}}, nil
+	case 'n':
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Announcement{Announcement: string(body)}}, nil
+	case 's':
+		var sources sessionpb.Sources
+		if err := jsonOptions.Unmarshal(body, &sources); err != nil {
+			return nil, fmt.Errorf("translate sources failed: %w", err)
+		}
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Sources{Sources: &sources}}, nil
+	case 'q':
+		var suggestions sessionpb.Suggestions
+		if err := jsonOptions.Unmarshal(body, &suggestions); err != nil {
+			return nil, fmt.Errorf("translate suggestions failed: %w", err)
+		}
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Suggestions{Suggestions: &suggestions}}, nil
+	case 't':
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_ThreadId{ThreadId: string(body)}}, nil
+	case 'd':
+		return &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Done{Done: &sessionpb.Done{}}}, nil
+	default:
+		return nil, fmt.Errorf("unknown message type %q", message[:1])
+	}
+}
+
+// translateContent splits a piece of the response into its text and widgets.
+func translateContent(text string) (*sessionpb.Content, error) {
+	content := &sessionpb.Content{}
+	last := 0
+	for _, loc := range widgetRegex.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] > last {
+			content.Chunks = append(content.Chunks, &sessionpb.Chunk{Content: &sessionpb.Chunk_Text{Text: text[last:loc[0]]}})
+		}
+		widget, err := translateWidget([]byte(text[loc[2]:loc[3]]))
+		if err != nil {
+			return nil, err
+		}
+		content.Chunks = append(content.Chunks, &sessionpb.Chunk{Content: &sessionpb.Chunk_Widget{Widget: widget}})
+		last = loc[1]
+	}
+	if last < len(text) {
+		content.Chunks = append(content.Chunks, &sessionpb.Chunk{Content: &sessionpb.Chunk_Text{Text: text[last:]}})
+	}
+	return content, nil
+}
+
+func translateWidget(j []byte) (*sessionpb.Widget, error) {
+	var w struct {
+		Type    string          `json:"type"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(j, &w); err != nil {
+		return nil, fmt.Errorf("translate widget failed: %w", err)
+	}
+	widget := &sessionpb.Widget{}
+	if err := setOneof(widget, strings.ReplaceAll(w.Type, "-", "_"), w.Content); err != nil {
+		return nil, fmt.Errorf("translate widget failed: %w", err)
+	}
+	return widget, nil
+}
+
+func translateProgress(j []byte) (*sessionpb.Progress, error) {
+	var p struct {
+		Event    string `json:"event"`
+		Function string `json:"function"`
+		Detail   string `json:"detail"`
+	}
+	if err := json.Unmarshal(j, &p); err != nil {
+		return nil, fmt.Errorf("translate progress failed: %w", err)
+	}
+	event, ok := sessionpb.Progress_Event_value["EVENT_"+strings.ToUpper(p.Event)]
+	if !ok {
+		return nil, fmt.Errorf("translate progress failed: unknown event %q", p.Event)
+	}
+	return &sessionpb.Progress{Event: sessionpb.Progress_Event(event), Function: p.Function, Detail: p.Detail}, nil
+}
+
+func translateAction(j []byte) (*sessionpb.ActionRequest, error) {
+	var a struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(j, &a); err != nil {
+		return nil, fmt.Errorf("translate action failed: %w", err)
+	}
+	action := &sessionpb.ActionRequest{}
+	// The action's arguments sit alongside its name, which the message it's read into just ignores.
+	if err := setOneof(action, a.Action, j); err != nil {
+		return nil, fmt.Errorf("translate action failed: %w", err)
+	}
+	return action, nil
+}
+
+// setOneof reads j into m's field called name, which must be one of the messages in its oneof.
+func setOneof(m proto.Message, name string, j []byte) error {
+	r := m.ProtoReflect()
+	fd := r.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if fd == nil || fd.ContainingOneof() == nil || fd.Message() == nil {
+		return fmt.Errorf("unknown %s %q", r.Descriptor().Name(), name)
+	}
+	v := r.NewField(fd)
+	if len(j) > 0 {
+		if err := jsonOptions.Unmarshal(j, v.Message().Interface()); err != nil {
+			return err
+		}
+	}
+	r.Set(fd, v)
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wire carries a session's messages over its websocket in whichever protocol the client asked for: the
+// original one, where each message is a prefix letter followed by text or JSON, or the protobuf one described in
+// proto/bobby/session/v1. The rest of the server only speaks the original protocol, and this translates.
+package wire
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/proto/sessionpb"
+	"google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
+)
+
+// Subprotocol is the websocket subprotocol clients ask for to be sent protobufs.
+const Subprotocol = "bobby.session.v1"
+
+// Conn is a session's websocket.
+type Conn struct {
+	ws *websocket.Conn
+	// Whether the client asked for protobufs.
+	binary bool
+}
+
+// Accept accepts a websocket connection from a client, offering it the protobuf protocol.
+func Accept(w http.ResponseWriter, r *http.Request, opts *websocket.AcceptOptions) (*Conn, error) {
+	var o websocket.AcceptOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Subprotocols = append(slices.Clone(o.Subprotocols), Subprotocol)
+	ws, err := websocket.Accept(w, r, &o)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(ws), nil
+}
+
+// Wrap returns a Conn for a websocket that's already open, using whichever protocol was agreed on opening it.
+func Wrap(ws *websocket.Conn) *Conn {
+	return &Conn{ws: ws, binary: ws.Subprotocol() == Subprotocol}
+}
+
+// Write sends a message in the original protocol, first translating it if the client asked for protobufs.
+func (c *Conn) Write(ctx context.Context, typ websocket.MessageType, p []byte) error {
+	if !c.binary || typ != websocket.MessageText {
+		return c.ws.Write(ctx, typ, p)
+	}
+	msg, err := Translate(p)
+	if err != nil {
+		return err
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal %q message failed: %w", p[:1], err)
+	}
+	return c.ws.Write(ctx, websocket.MessageBinary, b)
+}
+
+// Read reads the client's reply to an action. If the client asked for protobufs, the ClientActionResponse it sent is
+// returned as the JSON object the original protocol would have had.
+func (c *Conn) Read(ctx context.Context) (websocket.MessageType, []byte, error) {
+	typ, p, err := c.ws.Read(ctx)
+	if err != nil || !c.binary || typ != websocket.MessageBinary {
+		return typ, p, err
+	}
+	var resp sessionpb.ClientActionResponse
+	if err := proto.Unmarshal(p, &resp); err != nil {
+		return typ, nil, fmt.Errorf("unmarshal action response failed: %w", err)
+	}
+	j, err := json.Marshal(responseFields(&resp))
+	if err != nil {
+		return typ, nil, fmt.Errorf("marshal action response failed: %w", err)
+	}
+	return websocket.MessageText, j, nil
+}
+
+// Close closes the websocket with the given status code and reason.
+func (c *Conn) Close(code websocket.StatusCode, reason string) error {
+	return c.ws.Close(code, reason)
+}
+
+// CloseNow closes the websocket without waiting for the client to agree.
+func (c *Conn) CloseNow() error {
+	return c.ws.CloseNow()
+}
+
+func responseFields(resp *sessionpb.ClientActionResponse) map[string]any {
+	fields := resp.GetResult().AsMap()
+	if resp.GetStatus() != "" {
+		fields["status"] = resp.GetStatus()
+	}
+	if resp.GetError() != "" {
+		fields["error"] = resp.GetError()
+	}
+	return fields
+}
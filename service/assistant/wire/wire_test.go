@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/proto/sessionpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"nhooyr.io/websocket"
+)
+
+func TestTranslate(t *testing.T) {
+	three := int32(3)
+	tests := []struct {
+		message string
+		want    *sessionpb.ServerMessage
+	}{
+		{
+			message: `cIt's sunny. <<!!WIDGET:{"content":{"location":"London","condition":1,"temperature":21.5,"feels_like":20,"unit":"°C","description":"Sunny","wind_speed":3,"wind_speed_unit":"mph","decimals":1,"humidity":3},"type":"weather-current"}!!>>Enjoy!`,
+			want: &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Content{Content: &sessionpb.Content{Chunks: []*sessionpb.Chunk{
+				{Content: &sessionpb.Chunk_Text{Text: "It's sunny. "}},
+				{Content: &sessionpb.Chunk_Widget{Widget: &sessionpb.Widget{Content: &sessionpb.Widget_WeatherCurrent{WeatherCurrent: &sessionpb.WeatherCurrent{
+					Location: "London", Condition: 1, Temperature: 21.5, FeelsLike: 20, Unit: "°C", Description: "Sunny",
+					WindSpeed: 3, WindSpeedUnit: "mph", Decimals: 1, Humidity: &three,
+				}}}}},
+				{Content: &sessionpb.Chunk_Text{Text: "Enjoy!"}},
+			}}}},
+		},
+		{
+			message: `p{"event":"continuing","function":"get_weather"}`,
+			want: &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Progress{Progress: &sessionpb.Progress{
+				Event: sessionpb.Progress_EVENT_CONTINUING, Function: "get_weather",
+			}}},
+		},
+		{
+			message: `a{"action":"set_alarm","duration":300,"isTimer":true,"name":"Tea","vibration":"","cancel":false}`,
+			want: &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Action{Action: &sessionpb.ActionRequest{Action: &sessionpb.ActionRequest_SetAlarm{SetAlarm: &sessionpb.SetAlarm{
+				IsTimer: true, Duration: 300, Name: "Tea",
+			}}}}},
+		},
+		{
+			message: `a{"action":"set_reminder","what":"Buy milk","trigger":{"type":"arrive","place":"Tesco","lat":51.5,"lon":-0.1,"radius_m":200}}`,
+			want: &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Action{Action: &sessionpb.ActionRequest{Action: &sessionpb.ActionRequest_SetReminder{SetReminder: &sessionpb.SetReminder{
+				What: "Buy milk", Trigger: &sessionpb.SetReminder_Trigger{Type: "arrive", Place: "Tesco", Lat: 51.5, Lon: -0.1, RadiusM: 200},
+			}}}}},
+		},
+		{
+			message: `a{"action":"get_reminders"}`,
+			want:    &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Action{Action: &sessionpb.ActionRequest{Action: &sessionpb.ActionRequest_GetReminders{GetReminders: &sessionpb.GetReminders{}}}}},
+		},
+		{
+			message: `s{"citations":[{"source":"Wikipedia","title":"Pebble","url":"https://en.wikipedia.org/wiki/Pebble"}]}`,
+			want: &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Sources{Sources: &sessionpb.Sources{
+				Citations: []*sessionpb.Citation{{Source: "Wikipedia", Title: "Pebble", Url: "https://en.wikipedia.org/wiki/Pebble"}},
+			}}},
+		},
+		{
+			message: `q{"suggestions":["And tomorrow?"]}`,
+			want:    &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Suggestions{Suggestions: &sessionpb.Suggestions{Suggestions: []string{"And tomorrow?"}}}},
+		},
+		{
+			message: "nWe're upgrading tonight.",
+			want:    &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Announcement{Announcement: "We're upgrading tonight."}},
+		},
+		{
+			message: "d",
+			want:    &sessionpb.ServerMessage{Message: &sessionpb.ServerMessage_Done{Done: &sessionpb.Done{}}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Translate([]byte(tt.message))
+		if err != nil {
+			t.Errorf("Translate(%q) failed: %v", tt.message, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("Translate(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateRejectsUnknown(t *testing.T) {
+	for _, message := range []string{
+		`a{"action":"launch_rocket"}`,
+		`c<<!!WIDGET:{"content":{},"type":"hologram"}!!>>`,
+		`p{"event":"exploded","function":"get_weather"}`,
+		"z",
+	} {
+		if _, err := Translate([]byte(message)); err == nil {
+			t.Errorf("Translate(%q) succeeded, want an error", message)
+		}
+	}
+}
+
+// TestConn checks that only clients that ask for protobufs get them, and that their replies come back as JSON.
+func TestConn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		conn, err := Accept(rw, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		if err := conn.Write(r.Context(), websocket.MessageText, []byte(`a{"action":"get_alarm","isTimer":true}`)); err != nil {
+			return
+		}
+		_, reply, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+		_ = conn.Write(r.Context(), websocket.MessageText, append([]byte("f"), reply...))
+	}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ctx := context.Background()
+
+	t.Run("original", func(t *testing.T) {
+		ws, _, err := websocket.Dial(ctx, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ws.CloseNow()
+		typ, p, err := ws.Read(ctx)
+		if err != nil || typ != websocket.MessageText || string(p) != `a{"action":"get_alarm","isTimer":true}` {
+			t.Fatalf("Read() = %v, %q, %v, want the action as text", typ, p, err)
+		}
+		if err := ws.Write(ctx, websocket.MessageText, []byte(`{"status":"ok","alarms":[]}`)); err != nil {
+			t.Fatal(err)
+		}
+		if _, p, err := ws.Read(ctx); err != nil || string(p) != `f{"status":"ok","alarms":[]}` {
+			t.Errorf("Read() = %q, %v, want the reply echoed unchanged", p, err)
+		}
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		ws, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{Subprotocols: []string{Subprotocol}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ws.CloseNow()
+		typ, p, err := ws.Read(ctx)
+		if err != nil || typ != websocket.MessageBinary {
+			t.Fatalf("Read() = %v, %v, want a binary message", typ, err)
+		}
+		var msg sessionpb.ServerMessage
+		if err := proto.Unmarshal(p, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if !msg.GetAction().GetGetAlarm().GetIsTimer() {
+			t.Errorf("got %v, want a get_alarm action for timers", &msg)
+		}
+		result, _ := structpb.NewStruct(map[string]any{"alarms": []any{}})
+		reply, _ := proto.Marshal(&sessionpb.ClientActionResponse{Status: "ok", Result: result})
+		if err := ws.Write(ctx, websocket.MessageBinary, reply); err != nil {
+			t.Fatal(err)
+		}
+		_, p, err = ws.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proto.Unmarshal(p, &msg); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(msg.GetThought()), &got); err != nil || got["status"] != "ok" || got["alarms"] == nil {
+			t.Errorf("the server read the reply as %q, want its JSON with status and alarms", msg.GetThought())
+		}
+	})
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// bobbycli talks to a Bobby server from a terminal, the way the phone app does, so that server changes can be tried
+// out without a watch.
+//
+//	go run ./cmd/bobbycli -token $TOKEN "What's the weather like?"
+//
+// With no prompt, it reads prompts a line at a time, carrying the conversation on from one to the next.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+type Options struct {
+	URL      string
+	Token    string
+	Lat      string
+	Lon      string
+	Lang     string
+	Units    string
+	Actions  string
+	Widgets  string
+	Sandbox  bool
+	Raw      bool
+	ThreadID string
+	Params   []string
+}
+
+func parseFlags() Options {
+	o := Options{}
+	flag.StringVar(&o.URL, "url", "ws://localhost:8080/query", "The server's query endpoint")
+	flag.StringVar(&o.Token, "token", os.Getenv("BOBBY_TOKEN"), "The user's timeline token. Defaults to $BOBBY_TOKEN")
+	flag.StringVar(&o.Lat, "lat", "", "Latitude to report, if any")
+	flag.StringVar(&o.Lon, "lon", "", "Longitude to report, if any")
+	flag.StringVar(&o.Lang, "lang", "", "Preferred language, e.g. en_GB")
+	flag.StringVar(&o.Units, "units", "", "Preferred units: imperial, metric, uk or both")
+	flag.StringVar(&o.Actions, "actions", "set_alarm,get_alarm,delete_alarm,set_reminder,get_reminders,delete_reminder", "Actions to claim the watch supports. They're answered as if they worked")
+	flag.StringVar(&o.Widgets, "widgets", "weather,timer,number,sports,pronunciation,page", "Widgets to claim the watch can show")
+	flag.BoolVar(&o.Sandbox, "sandbox", true, "Whether to ask the server to simulate actions rather than carry them out")
+	flag.BoolVar(&o.Raw, "raw", false, "Print every message from the server as a line of JSON, instead of as a conversation")
+	flag.StringVar(&o.ThreadID, "thread", "", "The ID of a conversation to carry on")
+	flag.Func("param", "Extra query parameter to send, as key=value, e.g. debug=true. May be repeated", func(v string) error {
+		if !strings.Contains(v, "=") {
+			return errors.New("must be key=value")
+		}
+		o.Params = append(o.Params, v)
+		return nil
+	})
+	flag.Parse()
+	if o.Token == "" {
+		fmt.Fprintf(os.Stderr, "A token must be given with -token or $BOBBY_TOKEN\n")
+		os.Exit(1)
+	}
+	return o
+}
+
+func (o Options) queryURL(prompt, threadID string) string {
+	params := url.Values{}
+	params.Set("prompt", prompt)
+	params.Set("token", o.Token)
+	_, offset := time.Now().Zone()
+	params.Set("tzOffset", fmt.Sprint(offset/60))
+	params.Set("actions", o.Actions)
+	params.Set("widgets", o.Widgets)
+	params.Set("version", "cli")
+	if o.Lat != "" && o.Lon != "" {
+		params.Set("lat", o.Lat)
+		params.Set("lon", o.Lon)
+	}
+	if o.Lang != "" {
+		params.Set("lang", o.Lang)
+	}
+	if o.Units != "" {
+		params.Set("units", o.Units)
+	}
+	if o.Sandbox {
+		params.Set("sandbox", "1")
+	}
+	if threadID != "" {
+		params.Set("threadId", threadID)
+	}
+	for _, p := range o.Params {
+		k, v, _ := strings.Cut(p, "=")
+		params.Set(k, v)
+	}
+	return o.URL + "?" + params.Encode()
+}
+
+var widgetRegex = regexp.MustCompile(`(?s)<<!!WIDGET:(.+?)!!>>`)
+
+// messageTypes names the kinds of message the server sends, by their first byte.
+var messageTypes = map[byte]string{
+	'c': "content",
+	'f': "thought",
+	'p': "progress",
+	'a': "action",
+	'w': "warning",
+	'n': "announcement",
+	's': "sources",
+	't': "thread",
+	'd': "done",
+}
+
+// printer shows what the server sends.
+type printer struct {
+	raw bool
+	out io.Writer
+	// Whether the last thing written to out was the middle of a line.
+	midLine bool
+}
+
+func (p *printer) line(format string, args ...any) {
+	if p.midLine {
+		fmt.Fprintln(p.out)
+		p.midLine = false
+	}
+	fmt.Fprintf(p.out, format+"\n", args...)
+}
+
+func (p *printer) print(kind byte, content string) {
+	if p.raw {
+		m := map[string]any{"type": messageTypes[kind], "content": content}
+		if json.Valid([]byte(content)) {
+			m["content"] = json.RawMessage(content)
+		}
+		j, _ := json.Marshal(m)
+		fmt.Fprintln(p.out, string(j))
+		return
+	}
+	switch kind {
+	case 'c':
+		for content != "" {
+			loc := widgetRegex.FindStringSubmatchIndex(content)
+			if loc == nil {
+				break
+			}
+			p.text(content[:loc[0]])
+			p.widget(content[loc[2]:loc[3]])
+			content = content[loc[1]:]
+		}
+		p.text(content)
+	case 'f':
+		p.line("[%s]", content)
+	case 'a':
+		p.line("[action %s]", content)
+	case 'w':
+		p.line("Warning: %s", content)
+	case 'n':
+		p.line("Announcement: %s", content)
+	case 's':
+		p.line("Sources: %s", content)
+	case 'd':
+		if p.midLine {
+			fmt.Fprintln(p.out)
+			p.midLine = false
+		}
+	}
+}
+
+func (p *printer) text(s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprint(p.out, s)
+	p.midLine = !strings.HasSuffix(s, "\n")
+}
+
+func (p *printer) widget(j string) {
+	var indented strings.Builder
+	var v any
+	if err := json.Unmarshal([]byte(j), &v); err == nil {
+		b, _ := json.MarshalIndent(v, "", "  ")
+		indented.Write(b)
+	} else {
+		indented.WriteString(j)
+	}
+	p.line("[widget]\n%s", indented.String())
+}
+
+// ask sends a single prompt, printing what comes back, and returns the ID of the thread to carry on with.
+func ask(ctx context.Context, o Options, p *printer, prompt, threadID string) (string, error) {
+	conn, _, err := websocket.Dial(ctx, o.queryURL(prompt, threadID), nil)
+	if err != nil {
+		return threadID, fmt.Errorf("connecting failed: %w", err)
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(-1)
+	for {
+		_, message, err := conn.Read(ctx)
+		if err != nil {
+			var closeErr websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Code == websocket.StatusNormalClosure {
+				return threadID, nil
+			}
+			if errors.As(err, &closeErr) {
+				return threadID, fmt.Errorf("the server closed the connection (%d): %s", closeErr.Code, closeErr.Reason)
+			}
+			return threadID, err
+		}
+		if len(message) == 0 {
+			continue
+		}
+		p.print(message[0], string(message[1:]))
+		switch message[0] {
+		case 't':
+			threadID = string(message[1:])
+		case 'a':
+			// There's no watch to ask, so pretend it did whatever it was asked.
+			if err := conn.Write(ctx, websocket.MessageText, []byte(`{"status":"ok"}`)); err != nil {
+				return threadID, fmt.Errorf("responding to action failed: %w", err)
+			}
+		}
+	}
+}
+
+func main() {
+	options := parseFlags()
+	ctx := context.Background()
+	p := &printer{raw: options.Raw, out: os.Stdout}
+
+	if flag.NArg() > 0 {
+		if _, err := ask(ctx, options, p, strings.Join(flag.Args(), " "), options.ThreadID); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	threadID := options.ThreadID
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprint(os.Stderr, "> ")
+		if !scanner.Scan() {
+			break
+		}
+		prompt := strings.TrimSpace(scanner.Text())
+		if prompt == "" {
+			continue
+		}
+		var err error
+		threadID, err = ask(ctx, options, p, prompt, threadID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// mockapis serves canned responses in place of the upstream APIs Bobby uses, so that the server can be developed
+// without the internet, and tested against answers that don't change from one run to the next. Point the server at it
+// with OPEN_METEO_URL, PHOTON_URL and WIKIPEDIA_URL:
+//
+//	go run ./cmd/mockapis -addr localhost:8090
+//	OPEN_METEO_URL=http://localhost:8090 PHOTON_URL=http://localhost:8090 WIKIPEDIA_URL=http://localhost:8090 go run .
+//
+// Forecasts are the same every day, dated from whenever they're asked for.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+)
+
+type Options struct {
+	Addr string
+}
+
+func parseFlags() Options {
+	o := Options{}
+	flag.StringVar(&o.Addr, "addr", "localhost:8090", "Address to listen on")
+	flag.Parse()
+	return o
+}
+
+func writeJSON(rw http.ResponseWriter, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		log.Printf("Writing response failed: %v", err)
+	}
+}
+
+// logRequests logs each request, so that it's clear what the server asked for.
+func logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL)
+		h.ServeHTTP(rw, r)
+	})
+}
+
+func main() {
+	options := parseFlags()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/forecast", handleForecast)
+	mux.HandleFunc("/api/", handleGeocode)
+	mux.HandleFunc("/reverse/", handleReverseGeocode)
+	mux.HandleFunc("/w/api.php", handleWiki)
+	log.Printf("Listening on %s.", options.Addr)
+	log.Fatal(http.ListenAndServe(options.Addr, logRequests(mux)))
+}
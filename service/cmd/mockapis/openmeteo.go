@@ -0,0 +1,225 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// dailyWeather is the weather on each day of the week, starting on Monday, so that every forecast for a given day
+// is the same. Temperatures are in Celsius, and rain in millimetres.
+var dailyWeather = []dayWeather{
+	{code: 0, max: 21, min: 11, wind: 10},
+	{code: 2, max: 19, min: 10, rainChance: 10, wind: 14, cloud: 40},
+	{code: 3, max: 17, min: 10, rain: 0.4, rainChance: 30, wind: 18, cloud: 90},
+	{code: 61, max: 14, min: 9, rain: 6.2, rainChance: 80, wind: 25, cloud: 100},
+	{code: 80, max: 15, min: 8, rain: 3.1, rainChance: 60, wind: 22, cloud: 70},
+	{code: 1, max: 20, min: 12, rainChance: 5, wind: 12, cloud: 15},
+	{code: 45, max: 16, min: 7, rainChance: 10, wind: 6, cloud: 100},
+}
+
+type dayWeather struct {
+	// The WMO weather code.
+	code       int
+	max, min   float64
+	rain       float64
+	rainChance float64
+	wind       float64
+	cloud      float64
+}
+
+const (
+	sunrise = "06:30"
+	sunset  = "19:45"
+)
+
+// units converts the canned values into whatever units were asked for.
+type units struct {
+	fahrenheit bool
+	windUnit   string
+	inches     bool
+}
+
+func unitsFromQuery(q map[string][]string) units {
+	get := func(k string) string {
+		if v := q[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	return units{fahrenheit: get("temperature_unit") == "fahrenheit", windUnit: get("windspeed_unit"), inches: get("precipitation_unit") == "inch"}
+}
+
+func (u units) temp(c float64) float64 {
+	if u.fahrenheit {
+		return round(c*9/5 + 32)
+	}
+	return c
+}
+
+func (u units) wind(kmh float64) float64 {
+	switch u.windUnit {
+	case "mph":
+		return round(kmh / 1.609)
+	case "ms":
+		return round(kmh / 3.6)
+	}
+	return kmh
+}
+
+func (u units) rain(mm float64) float64 {
+	if u.inches {
+		return round(mm / 25.4)
+	}
+	return mm
+}
+
+func round(v float64) float64 {
+	return float64(int(v*10+0.5)) / 10
+}
+
+func weatherOn(day time.Time) int {
+	return (int(day.Weekday()) + 6) % 7
+}
+
+// handleForecast stands in for Open-Meteo's forecast API. Times are always UTC, whatever the location.
+func handleForecast(rw http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	u := unitsFromQuery(q)
+	lat, _ := strconv.ParseFloat(q.Get("latitude"), 64)
+	lon, _ := strconv.ParseFloat(q.Get("longitude"), 64)
+	now := time.Now().UTC()
+	start := now.Truncate(24 * time.Hour)
+	days, err := strconv.Atoi(q.Get("forecast_days"))
+	if err != nil || days < 1 {
+		days = 7
+	}
+	if d, err := time.Parse("2006-01-02", q.Get("start_date")); err == nil {
+		start = d
+		days = 1
+		if end, err := time.Parse("2006-01-02", q.Get("end_date")); err == nil && end.After(d) {
+			days = int(end.Sub(d).Hours()/24) + 1
+		}
+	}
+	resp := map[string]any{
+		"latitude":              lat,
+		"longitude":             lon,
+		"elevation":             25,
+		"generationtime_ms":     0.1,
+		"utc_offset_seconds":    0,
+		"timezone":              "GMT",
+		"timezone_abbreviation": "GMT",
+	}
+	if q.Has("daily") {
+		resp["daily"] = daily(u, start, days)
+	}
+	if q.Has("hourly") {
+		resp["hourly"] = hourly(u, start, days)
+	}
+	if q.Has("current") {
+		resp["current"] = current(u, now)
+	}
+	writeJSON(rw, resp)
+}
+
+func daily(u units, start time.Time, days int) map[string][]any {
+	d := map[string][]any{}
+	add := func(k string, v any) { d[k] = append(d[k], v) }
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i)
+		w := dailyWeather[weatherOn(day)]
+		date := day.Format("2006-01-02")
+		add("time", date)
+		add("weathercode", w.code)
+		add("temperature_2m_max", u.temp(w.max))
+		add("temperature_2m_min", u.temp(w.min))
+		add("sunrise", date+"T"+sunrise)
+		add("sunset", date+"T"+sunset)
+		add("precipitation_sum", u.rain(w.rain))
+		add("precipitation_hours", float64(int(w.rain)))
+		add("precipitation_probability_max", w.rainChance)
+		add("windspeed_10m_max", u.wind(w.wind))
+		add("winddirection_10m_dominant", 225)
+		add("uv_index_max", 5.0)
+	}
+	return d
+}
+
+// hourlyTemp returns the temperature at the hour of the day, somewhere between the day's low and high.
+func hourlyTemp(w dayWeather, hour int) float64 {
+	// Coldest at 5am and warmest at 3pm.
+	frac := 1 - float64(absInt(hour-15))/10
+	if frac < 0 {
+		frac = 0
+	}
+	return round(w.min + (w.max-w.min)*frac)
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func isDay(hour int) int {
+	if hour >= 7 && hour < 20 {
+		return 1
+	}
+	return 0
+}
+
+func hourly(u units, start time.Time, days int) map[string][]any {
+	d := map[string][]any{}
+	add := func(k string, v any) { d[k] = append(d[k], v) }
+	for h := 0; h < days*24; h++ {
+		t := start.Add(time.Duration(h) * time.Hour)
+		w := dailyWeather[weatherOn(t)]
+		add("time", t.Format("2006-01-02T15:04"))
+		add("temperature_2m", u.temp(hourlyTemp(w, t.Hour())))
+		add("apparent_temperature", u.temp(hourlyTemp(w, t.Hour())-1))
+		add("precipitation_probability", w.rainChance)
+		add("precipitation", u.rain(w.rain/24))
+		add("weathercode", w.code)
+		add("visibility", 20000.0)
+		add("windspeed_10m", u.wind(w.wind))
+		add("winddirection_10m", 225.0)
+		add("uv_index", float64(isDay(t.Hour())*3))
+		add("is_day", isDay(t.Hour()))
+		add("relativehumidity_2m", 70.0)
+	}
+	return d
+}
+
+func current(u units, now time.Time) map[string]any {
+	w := dailyWeather[weatherOn(now)]
+	t := now.Truncate(15 * time.Minute)
+	return map[string]any{
+		"time":                      t.Format("2006-01-02T15:04"),
+		"temperature_2m":            u.temp(hourlyTemp(w, t.Hour())),
+		"relative_humidity_2m":      70.0,
+		"apparent_temperature":      u.temp(hourlyTemp(w, t.Hour()) - 1),
+		"is_day":                    isDay(t.Hour()),
+		"precipitation":             u.rain(w.rain / 24),
+		"weather_code":              w.code,
+		"cloud_cover":               w.cloud,
+		"visibility":                20000.0,
+		"wind_speed_10m":            u.wind(w.wind),
+		"wind_direction_10m":        225.0,
+		"uv_index":                  float64(isDay(t.Hour()) * 3),
+		"precipitation_probability": w.rainChance,
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type place struct {
+	name        string
+	state       string
+	country     string
+	countryCode string
+	lat, lon    float64
+}
+
+// places are the places Photon knows about. Anywhere else is found too, at a made up location that's always the
+// same for the same name.
+var places = []place{
+	{"London", "England", "United Kingdom", "GB", 51.5074, -0.1278},
+	{"Paris", "Île-de-France", "France", "FR", 48.8566, 2.3522},
+	{"New York", "New York", "United States", "US", 40.7128, -74.0060},
+	{"San Francisco", "California", "United States", "US", 37.7749, -122.4194},
+	{"Tokyo", "Tokyo", "Japan", "JP", 35.6762, 139.6503},
+	{"Sydney", "New South Wales", "Australia", "AU", -33.8688, 151.2093},
+	{"Berlin", "Berlin", "Germany", "DE", 52.5200, 13.4050},
+}
+
+// madeUpPlace places somewhere we don't know about in the mid latitudes, based on its name.
+func madeUpPlace(name string) place {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(name)))
+	sum := h.Sum32()
+	return place{
+		name:        name,
+		country:     "Mockland",
+		countryCode: "MK",
+		lat:         float64(sum%6000)/100 - 30,
+		lon:         float64((sum/6000)%36000)/100 - 180,
+	}
+}
+
+func (p place) feature(id int) map[string]any {
+	return map[string]any{
+		"type": "Feature",
+		"geometry": map[string]any{
+			"type":        "Point",
+			"coordinates": []float64{p.lon, p.lat},
+		},
+		"properties": map[string]any{
+			"name":        p.name,
+			"city":        p.name,
+			"state":       p.state,
+			"country":     p.country,
+			"countrycode": p.countryCode,
+			"osm_id":      id,
+			"osm_type":    "N",
+			"osm_key":     "place",
+			"osm_value":   "city",
+			"type":        "city",
+		},
+	}
+}
+
+func featureCollection(features ...map[string]any) map[string]any {
+	if features == nil {
+		features = []map[string]any{}
+	}
+	return map[string]any{"type": "FeatureCollection", "features": features}
+}
+
+// handleGeocode stands in for Photon's search.
+func handleGeocode(rw http.ResponseWriter, r *http.Request) {
+	search := strings.TrimSpace(r.URL.Query().Get("q"))
+	if search == "" {
+		writeJSON(rw, featureCollection())
+		return
+	}
+	for i, p := range places {
+		if strings.EqualFold(p.name, search) || strings.HasPrefix(strings.ToLower(search), strings.ToLower(p.name)+",") {
+			writeJSON(rw, featureCollection(p.feature(i+1)))
+			return
+		}
+	}
+	writeJSON(rw, featureCollection(madeUpPlace(search).feature(1000)))
+}
+
+// handleReverseGeocode stands in for Photon's reverse geocoding, returning whichever known place is nearest.
+func handleReverseGeocode(rw http.ResponseWriter, r *http.Request) {
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	if latErr != nil || lonErr != nil {
+		http.Error(rw, `{"message":"invalid coordinates"}`, http.StatusBadRequest)
+		return
+	}
+	nearest, best := 0, math.Inf(1)
+	for i, p := range places {
+		if d := math.Hypot(p.lat-lat, p.lon-lon); d < best {
+			nearest, best = i, d
+		}
+	}
+	writeJSON(rw, featureCollection(places[nearest].feature(nearest+1)))
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// articles are the Wikipedia articles that exist, by title. Each is a summary, then the rest of the article.
+var articles = map[string][2]string{
+	"Pebble (watch)": {
+		"The '''Pebble''' is a smartwatch developed by Pebble Technology Corporation. It was released in 2013, after a record-breaking Kickstarter campaign.",
+		"\n\n== Hardware ==\nThe Pebble has a 1.26-inch black and white e-paper display, a vibrating motor, a magnetometer, an ambient light sensor and a three-axis accelerometer.\n\n== Software ==\nPebble OS is based on FreeRTOS.",
+	},
+	"London": {
+		"'''London''' is the capital and largest city of both England and the United Kingdom, with a population of around 8.9 million.",
+		"\n\n== History ==\nLondinium was established as a town by the Romans around AD 47.",
+	},
+	"Eiffel Tower": {
+		"The '''Eiffel Tower''' is a wrought-iron lattice tower on the Champ de Mars in Paris, France. It is 330 metres tall.",
+		"\n\n== History ==\nThe tower was built for the 1889 World's Fair, and was designed by the company of Gustave Eiffel.",
+	},
+}
+
+func findArticle(title string) (string, bool) {
+	for t := range articles {
+		if strings.EqualFold(t, strings.ReplaceAll(title, "_", " ")) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// handleWiki stands in for the MediaWiki API, for just the queries Bobby makes.
+func handleWiki(rw http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch q.Get("action") {
+	case "query":
+		title := q.Get("titles")
+		rw.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		t, ok := findArticle(title)
+		if !ok {
+			fmt.Fprintf(rw, `<?xml version="1.0"?><api batchcomplete=""><query><pages><page _idx="-1" ns="0" title="%s" missing="" /></pages></query></api>`, escapeXML(title))
+			return
+		}
+		content := articles[t][0]
+		if q.Get("rvsection") == "" {
+			content += articles[t][1]
+		}
+		fmt.Fprintf(rw, `<?xml version="1.0"?><api batchcomplete=""><query><pages><page _idx="1" pageid="1" ns="0" title="%s"><revisions><rev><slots><slot contentmodel="wikitext" contentformat="text/x-wiki" xml:space="preserve">%s</slot></slots></rev></revisions></page></pages></query></api>`, escapeXML(t), escapeXML(content))
+	case "opensearch":
+		search := q.Get("search")
+		titles := []string{}
+		urls := []string{}
+		for _, t := range slices.Sorted(maps.Keys(articles)) {
+			if strings.Contains(strings.ToLower(t), strings.ToLower(search)) {
+				titles = append(titles, t)
+				urls = append(urls, "http://"+r.Host+"/wiki/"+strings.ReplaceAll(t, " ", "_"))
+			}
+		}
+		writeJSON(rw, []any{search, titles, make([]string, len(titles)), urls})
+	default:
+		http.Error(rw, "unsupported action", http.StatusBadRequest)
+	}
+}
+
+func escapeXML(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// replay runs a turn captured by the server (see the replay package) against the model again, with the same prompt,
+// history, tool results and settings, and prints what the model says this time next to what it said then.
+//
+//	curl -H "Authorization: Bearer $ADMIN_TOKEN" "https://bobby.example.com/admin/replay?id=<request ID>" > turn.json
+//	GEMINI_KEY=... go run ./cmd/replay -bundle turn.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/genai"
+
+	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/localmodel"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/replay"
+)
+
+type Options struct {
+	Bundle string
+	Call   int
+	Times  int
+	Seed   int64
+}
+
+func parseFlags() Options {
+	o := Options{}
+	flag.StringVar(&o.Bundle, "bundle", "-", "Captured turn to replay, or - to read it from stdin")
+	flag.IntVar(&o.Call, "call", 0, "Which of the turn's calls to the model to replay, counting from 1, or 0 for all of them")
+	flag.IntVar(&o.Times, "times", 1, "Number of times to replay each call")
+	flag.Int64Var(&o.Seed, "seed", 0, "Seed to use in place of the captured one, or 0 to keep it")
+	flag.Parse()
+	if o.Times < 1 {
+		fmt.Fprintf(os.Stderr, "Times must be at least 1\n")
+		os.Exit(1)
+	}
+	return o
+}
+
+func loadBundle(name string) (*replay.Bundle, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var b replay.Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// describe turns what the model said, and the function it called, into a line or two of text.
+func describe(text string, fc *genai.FunctionCall) string {
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSpace(text))
+	if fc != nil {
+		args, _ := json.Marshal(fc.Args)
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "-> %s(%s)", fc.Name, args)
+	}
+	return sb.String()
+}
+
+func run(ctx context.Context, client *genai.Client, b *replay.Bundle, call replay.Call) (string, error) {
+	var s iter.Seq2[*genai.GenerateContentResponse, error]
+	if b.Offline {
+		s = localmodel.GenerateContentStream(ctx, call.Contents, call.Config)
+	} else {
+		s = client.Models.GenerateContentStream(ctx, b.Model, call.Contents, call.Config)
+	}
+	text := ""
+	var fc *genai.FunctionCall
+	for resp, err := range s {
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			continue
+		}
+		for _, p := range resp.Candidates[0].Content.Parts {
+			text += p.Text
+			if p.FunctionCall != nil {
+				fc = p.FunctionCall
+			}
+		}
+	}
+	return describe(text, fc), nil
+}
+
+func main() {
+	options := parseFlags()
+	ctx := context.Background()
+
+	b, err := loadBundle(options.Bundle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load captured turn: %v\n", err)
+		os.Exit(1)
+	}
+	if options.Call < 0 || options.Call > len(b.Calls) {
+		fmt.Fprintf(os.Stderr, "The turn only has %d calls\n", len(b.Calls))
+		os.Exit(1)
+	}
+
+	var client *genai.Client
+	if b.Offline {
+		if !localmodel.Enabled() {
+			fmt.Fprintf(os.Stderr, "The turn was answered by the local model, so LOCAL_MODEL_URL must be set\n")
+			os.Exit(1)
+		}
+	} else {
+		keys := config.GetConfig().GeminiKeys
+		if len(keys) == 0 {
+			fmt.Fprintf(os.Stderr, "GEMINI_KEY must be set\n")
+			os.Exit(1)
+		}
+		client, err = genai.NewClient(ctx, &genai.ClientConfig{APIKey: keys[0], Backend: genai.BackendGeminiAPI})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create Gemini client: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("Request %s, captured %s\nPrompt: %s\n", b.RequestID, b.Captured.Format("2006-01-02 15:04:05Z"), b.Prompt)
+	for i, call := range b.Calls {
+		if options.Call != 0 && options.Call != i+1 {
+			continue
+		}
+		if options.Seed != 0 && call.Config != nil {
+			call.Config.Seed = &options.Seed
+		}
+		fmt.Printf("\n=== Call %d of %d ===\nThen:\n%s\n", i+1, len(b.Calls), describe(call.Response, call.FunctionCall))
+		for n := 0; n < options.Times; n++ {
+			got, err := run(ctx, client, b, call)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to replay call %d: %v\n", i+1, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Now:\n%s\n", got)
+		}
+	}
+}
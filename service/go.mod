@@ -5,16 +5,19 @@ go 1.23.0
 toolchain go1.23.3
 
 require (
+	github.com/alicebob/miniredis/v2 v2.39.0
 	github.com/google/uuid v1.6.0
 	github.com/honeycombio/beeline-go v1.18.0
 	github.com/joho/godotenv v1.5.1
 	github.com/redis/go-redis/v9 v9.7.1
+	github.com/ringsaturn/tzf v0.14.2
 	github.com/umahmood/haversine v0.0.0-20151105152445-808ab04add26
 	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/exp v0.0.0-20250228200357-dead58393ab7
 	golang.org/x/text v0.23.0
 	google.golang.org/api v0.224.0
 	google.golang.org/genai v0.4.0
+	google.golang.org/protobuf v1.36.5
 	nhooyr.io/websocket v1.8.10
 )
 
@@ -38,8 +41,16 @@ require (
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/honeycombio/libhoney-go v1.25.0 // indirect
 	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/paulmach/orb v0.11.0 // indirect
+	github.com/ringsaturn/tzf-rel v0.0.2023-d1 // indirect
+	github.com/tidwall/geoindex v1.7.0 // indirect
+	github.com/tidwall/geojson v1.4.5 // indirect
+	github.com/tidwall/rtree v1.10.0 // indirect
+	github.com/twpayne/go-polyline v1.1.1 // indirect
 	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	go.mongodb.org/mongo-driver v1.11.4 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.59.0 // indirect
 	go.opentelemetry.io/otel v1.34.0 // indirect
@@ -52,7 +63,6 @@ require (
 	google.golang.org/genproto/googleapis/api v0.0.0-20250303144028-a0af3efb3deb // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250303144028-a0af3efb3deb // indirect
 	google.golang.org/grpc v1.71.0 // indirect
-	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/alexcesaro/statsd.v2 v2.0.0 // indirect
 )
 
@@ -15,14 +15,22 @@
 package main
 
 import (
+	"context"
 	"github.com/honeycombio/beeline-go"
 	"github.com/honeycombio/beeline-go/wrappers/hnynethttp"
 	"github.com/pebble-dev/bobby-assistant/service/assistant"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/analytics"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/canary"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/config"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/schedule"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/redact"
 	"github.com/pebble-dev/bobby-assistant/service/assistant/util/storage"
+	"github.com/pebble-dev/bobby-assistant/service/assistant/util/upstream"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -33,9 +41,20 @@ func main() {
 		PresendHook: redact.CleanHoneycomb,
 	})
 	defer beeline.Close()
+	defer analytics.Close()
+	// Stopping on a signal, rather than being killed, lets the deferred calls above flush what they're holding.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	upstream.TuneTransport(http.DefaultTransport.(*http.Transport))
 	http.DefaultTransport = hnynethttp.WrapRoundTripper(http.DefaultTransport)
 	service := assistant.NewService(storage.GetRedis())
+	canary.Start(ctx)
+	assistant.StartReaper(ctx)
 	addr := "0.0.0.0:8080"
+	schedule.Start(ctx, storage.GetRedis(), "ws://127.0.0.1:8080/query")
 	log.Printf("Listening on %s.", addr)
-	log.Fatal(service.ListenAndServe(addr))
+	if err := service.ListenAndServe(ctx, addr); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Shut down.")
 }